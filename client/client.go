@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net/url"
 	"runtime/trace"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -33,6 +34,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/pd/client/errs"
+	"github.com/tikv/pd/client/grpcutil"
 	"github.com/tikv/pd/client/tlsutil"
 	"github.com/tikv/pd/client/tsoutil"
 	"go.uber.org/zap"
@@ -217,6 +219,7 @@ type GetRegionOp struct {
 	needBuckets                  bool
 	allowFollowerHandle          bool
 	outputMustContainAllKeyRange bool
+	maxStaleness                 time.Duration
 }
 
 // GetRegionOption configures GetRegionOp.
@@ -232,6 +235,18 @@ func WithAllowFollowerHandle() GetRegionOption {
 	return func(op *GetRegionOp) { op.allowFollowerHandle = true }
 }
 
+// WithMaxStaleness means that client can send request to follower and let it
+// handle this request if its region cache is not staler than the given bound,
+// otherwise the request is retried on the leader. The leader syncs the region
+// cache to the followers at least every 10 seconds, so a smaller bound may fall
+// back to the leader when there are few region changes.
+func WithMaxStaleness(maxStaleness time.Duration) GetRegionOption {
+	return func(op *GetRegionOp) {
+		op.allowFollowerHandle = true
+		op.maxStaleness = maxStaleness
+	}
+}
+
 // WithOutputMustContainAllKeyRange means the output must contain all key ranges.
 func WithOutputMustContainAllKeyRange() GetRegionOption {
 	return func(op *GetRegionOp) { op.outputMustContainAllKeyRange = true }
@@ -304,6 +319,23 @@ func WithInitMetricsOption(initMetrics bool) ClientOption {
 	}
 }
 
+// WithComponent configures the client with the component of the caller, e.g.
+// tidb, cdc or br, which is reported to the server along with the TSO requests,
+// so the TSO allocations can be told apart by the components.
+func WithComponent(component string) ClientOption {
+	return func(c *client) {
+		c.option.component = component
+	}
+}
+
+// WithComponentVersion configures the client with the version of the caller
+// component, which is reported to the server to identify the clients.
+func WithComponentVersion(version string) ClientOption {
+	return func(c *client) {
+		c.option.componentVersion = version
+	}
+}
+
 var _ Client = (*client)(nil)
 
 // serviceModeKeeper is for service mode switching.
@@ -828,7 +860,21 @@ func (c *client) getClientAndContext(ctx context.Context) (pdpb.PDClient, contex
 	if serviceClient == nil || serviceClient.GetClientConn() == nil {
 		return nil, ctx
 	}
-	return pdpb.NewPDClient(serviceClient.GetClientConn()), serviceClient.BuildGRPCTargetContext(ctx, true)
+	return pdpb.NewPDClient(serviceClient.GetClientConn()), c.buildClientInfoContext(serviceClient.BuildGRPCTargetContext(ctx, true))
+}
+
+func (c *client) buildClientInfoContext(ctx context.Context) context.Context {
+	return buildClientInfoContext(ctx, c.option, c.keyspaceID)
+}
+
+// buildClientInfoContext attaches the metadata of the client to the context, which
+// is used by the server to track the connected clients.
+func buildClientInfoContext(ctx context.Context, option *option, keyspaceID uint32) context.Context {
+	var keyspace string
+	if keyspaceID != nullKeyspaceID {
+		keyspace = strconv.FormatUint(uint64(keyspaceID), 10)
+	}
+	return grpcutil.BuildClientInfoContext(ctx, option.component, option.componentVersion, keyspace)
 }
 
 // getClientAndContext returns the leader pd client and the original context. If leader is unhealthy, it returns
@@ -838,14 +884,14 @@ func (c *client) getRegionAPIClientAndContext(ctx context.Context, allowFollower
 	if allowFollower {
 		serviceClient = c.pdSvcDiscovery.getServiceClientByKind(regionAPIKind)
 		if serviceClient != nil {
-			return serviceClient, serviceClient.BuildGRPCTargetContext(ctx, !allowFollower)
+			return serviceClient, c.buildClientInfoContext(serviceClient.BuildGRPCTargetContext(ctx, !allowFollower))
 		}
 	}
 	serviceClient = c.pdSvcDiscovery.GetServiceClient()
 	if serviceClient == nil || serviceClient.GetClientConn() == nil {
 		return nil, ctx
 	}
-	return serviceClient, serviceClient.BuildGRPCTargetContext(ctx, !allowFollower)
+	return serviceClient, c.buildClientInfoContext(serviceClient.BuildGRPCTargetContext(ctx, !allowFollower))
 }
 
 func (c *client) GetTSAsync(ctx context.Context) TSFuture {
@@ -1033,6 +1079,7 @@ func (c *client) GetRegion(ctx context.Context, key []byte, opts ...GetRegionOpt
 		NeedBuckets: options.needBuckets,
 	}
 	serviceClient, cctx := c.getRegionAPIClientAndContext(ctx, options.allowFollowerHandle && c.option.getEnableFollowerHandle())
+	cctx = grpcutil.BuildMaxStalenessContext(cctx, options.maxStaleness)
 	if serviceClient == nil {
 		return nil, errs.ErrClientGetProtoClient
 	}
@@ -1071,6 +1118,7 @@ func (c *client) GetPrevRegion(ctx context.Context, key []byte, opts ...GetRegio
 		NeedBuckets: options.needBuckets,
 	}
 	serviceClient, cctx := c.getRegionAPIClientAndContext(ctx, options.allowFollowerHandle && c.option.getEnableFollowerHandle())
+	cctx = grpcutil.BuildMaxStalenessContext(cctx, options.maxStaleness)
 	if serviceClient == nil {
 		return nil, errs.ErrClientGetProtoClient
 	}
@@ -1109,6 +1157,7 @@ func (c *client) GetRegionByID(ctx context.Context, regionID uint64, opts ...Get
 		NeedBuckets: options.needBuckets,
 	}
 	serviceClient, cctx := c.getRegionAPIClientAndContext(ctx, options.allowFollowerHandle && c.option.getEnableFollowerHandle())
+	cctx = grpcutil.BuildMaxStalenessContext(cctx, options.maxStaleness)
 	if serviceClient == nil {
 		return nil, errs.ErrClientGetProtoClient
 	}
@@ -1152,6 +1201,7 @@ func (c *client) ScanRegions(ctx context.Context, key, endKey []byte, limit int,
 		Limit:    int32(limit),
 	}
 	serviceClient, cctx := c.getRegionAPIClientAndContext(scanCtx, options.allowFollowerHandle && c.option.getEnableFollowerHandle())
+	cctx = grpcutil.BuildMaxStalenessContext(cctx, options.maxStaleness)
 	if serviceClient == nil {
 		return nil, errs.ErrClientGetProtoClient
 	}
@@ -1206,6 +1256,7 @@ func (c *client) BatchScanRegions(ctx context.Context, ranges []KeyRange, limit
 		ContainAllKeyRange: options.outputMustContainAllKeyRange,
 	}
 	serviceClient, cctx := c.getRegionAPIClientAndContext(scanCtx, options.allowFollowerHandle && c.option.getEnableFollowerHandle())
+	cctx = grpcutil.BuildMaxStalenessContext(cctx, options.maxStaleness)
 	if serviceClient == nil {
 		return nil, errs.ErrClientGetProtoClient
 	}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"crypto/tls"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
@@ -39,6 +40,15 @@ const (
 	ForwardMetadataKey = "pd-forwarded-host"
 	// FollowerHandleMetadataKey is used to mark the permit of follower handle.
 	FollowerHandleMetadataKey = "pd-allow-follower-handle"
+	// ComponentMetadataKey is used to record the component of the caller, e.g. tidb, cdc or br.
+	ComponentMetadataKey = "pd-component"
+	// ClientVersionMetadataKey is used to record the version of the caller component.
+	ClientVersionMetadataKey = "pd-client-version"
+	// KeyspaceMetadataKey is used to record the keyspace of the caller.
+	KeyspaceMetadataKey = "pd-keyspace"
+	// MaxStalenessMetadataKey is used to bound the staleness of the region
+	// metadata served by the followers, in milliseconds.
+	MaxStalenessMetadataKey = "pd-max-staleness"
 )
 
 // GetClientConn returns a gRPC client connection.
@@ -89,6 +99,46 @@ func BuildForwardContext(ctx context.Context, url string) context.Context {
 	return metadata.NewOutgoingContext(ctx, md)
 }
 
+// BuildComponentContext creates a context with the component of the caller in
+// the metadata, the context is not changed if the component is empty.
+// It is used in client side.
+func BuildComponentContext(ctx context.Context, component string) context.Context {
+	if component == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, ComponentMetadataKey, component)
+}
+
+// BuildClientInfoContext creates a context with the component, version and keyspace
+// of the caller in the metadata, the empty ones are omitted.
+// It is used in client side.
+func BuildClientInfoContext(ctx context.Context, component, version, keyspace string) context.Context {
+	kv := make([]string, 0, 6)
+	for _, pair := range [][2]string{
+		{ComponentMetadataKey, component},
+		{ClientVersionMetadataKey, version},
+		{KeyspaceMetadataKey, keyspace},
+	} {
+		if pair[1] != "" {
+			kv = append(kv, pair[0], pair[1])
+		}
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+// BuildMaxStalenessContext creates a context with the max staleness of the
+// region metadata served by the followers, the context is not changed if the
+// staleness is not positive.
+func BuildMaxStalenessContext(ctx context.Context, maxStaleness time.Duration) context.Context {
+	if maxStaleness <= 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, MaxStalenessMetadataKey, strconv.FormatInt(maxStaleness.Milliseconds(), 10))
+}
+
 // GetForwardedHost returns the forwarded host in metadata.
 // Only used for test.
 func GetForwardedHost(ctx context.Context, f func(context.Context) (metadata.MD, bool)) string {
@@ -58,6 +58,10 @@ type option struct {
 	useTSOServerProxy bool
 	metricsLabels     prometheus.Labels
 	initMetrics       bool
+	// component is the component of the caller reported to the server, e.g. tidb, cdc or br.
+	component string
+	// componentVersion is the version of the caller component reported to the server.
+	componentVersion string
 
 	// Dynamic options.
 	dynamicOptions [dynamicOptionCount]atomic.Value
@@ -410,7 +410,7 @@ func (c *tsoClient) tryConnectToTSO(
 		}
 		if cc != nil {
 			cctx, cancel := context.WithCancel(ctx)
-			stream, err = c.tsoStreamBuilderFactory.makeBuilder(cc).build(cctx, cancel, c.option.timeout)
+			stream, err = c.tsoStreamBuilderFactory.makeBuilder(cc).build(buildClientInfoContext(cctx, c.option, c.svcDiscovery.GetKeyspaceID()), cancel, c.option.timeout)
 			failpoint.Inject("unreachableNetwork", func() {
 				stream = nil
 				err = status.New(codes.Unavailable, "unavailable").Err()
@@ -454,7 +454,7 @@ func (c *tsoClient) tryConnectToTSO(
 			// create the follower stream
 			cctx, cancel := context.WithCancel(ctx)
 			cctx = grpcutil.BuildForwardContext(cctx, forwardedHost)
-			stream, err = c.tsoStreamBuilderFactory.makeBuilder(backupClientConn).build(cctx, cancel, c.option.timeout)
+			stream, err = c.tsoStreamBuilderFactory.makeBuilder(backupClientConn).build(buildClientInfoContext(cctx, c.option, c.svcDiscovery.GetKeyspaceID()), cancel, c.option.timeout)
 			if err == nil {
 				forwardedHostTrim := trimHTTPPrefix(forwardedHost)
 				addr := trimHTTPPrefix(backupURL)
@@ -504,7 +504,7 @@ func (c *tsoClient) checkAllocator(
 			if err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_SERVING {
 				// create a stream of the original allocator
 				cctx, cancel := context.WithCancel(ctx)
-				stream, err := c.tsoStreamBuilderFactory.makeBuilder(cc).build(cctx, cancel, c.option.timeout)
+				stream, err := c.tsoStreamBuilderFactory.makeBuilder(cc).build(buildClientInfoContext(cctx, c.option, c.svcDiscovery.GetKeyspaceID()), cancel, c.option.timeout)
 				if err == nil && stream != nil {
 					log.Info("[tso] recover the original tso stream since the network has become normal", zap.String("dc", dc), zap.String("url", url))
 					updateAndClear(url, &tsoConnectionContext{cctx, cancel, url, stream})
@@ -563,7 +563,7 @@ func (c *tsoClient) tryConnectToTSOWithProxy(
 			cctx = grpcutil.BuildForwardContext(cctx, forwardedHost)
 		}
 		// Create the TSO stream.
-		stream, err := tsoStreamBuilder.build(cctx, cancel, c.option.timeout)
+		stream, err := tsoStreamBuilder.build(buildClientInfoContext(cctx, c.option, c.svcDiscovery.GetKeyspaceID()), cancel, c.option.timeout)
 		if err == nil {
 			if addr != leaderAddr {
 				forwardedHostTrim := trimHTTPPrefix(forwardedHost)
@@ -16,6 +16,7 @@ package cluster
 
 import (
 	"context"
+	"math/rand"
 
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/schedule"
@@ -28,13 +29,21 @@ type Cluster interface {
 	GetHotStat() *statistics.HotStat
 	GetRegionStats() *statistics.RegionStatistics
 	GetLabelStats() *statistics.LabelStatistics
+	GetZoneQuorumStats() *statistics.ZoneQuorumStatistics
 	GetCoordinator() *schedule.Coordinator
 	GetRuleManager() *placement.RuleManager
 	GetBasicCluster() *core.BasicCluster
 }
 
-// HandleStatsAsync handles the flow asynchronously.
-func HandleStatsAsync(c Cluster, region *core.RegionInfo) {
+// SampleRegionStats returns whether a region heartbeat should update the
+// expensive statistics according to the sample ratio.
+func SampleRegionStats(ratio float64) bool {
+	return ratio >= 1 || rand.Float64() < ratio
+}
+
+// HandleStatsAsync handles the flow asynchronously, the expired hot peers are
+// always collected while the flow is only checked by the sampled heartbeats.
+func HandleStatsAsync(c Cluster, region *core.RegionInfo, sampled bool) {
 	checkWritePeerTask := func(cache *statistics.HotPeerCache) {
 		reportInterval := region.GetInterval()
 		interval := reportInterval.GetEndTimestamp() - reportInterval.GetStartTimestamp()
@@ -53,7 +62,9 @@ func HandleStatsAsync(c Cluster, region *core.RegionInfo) {
 
 	c.GetHotStat().CheckWriteAsync(checkExpiredTask)
 	c.GetHotStat().CheckReadAsync(checkExpiredTask)
-	c.GetHotStat().CheckWriteAsync(checkWritePeerTask)
+	if sampled {
+		c.GetHotStat().CheckWriteAsync(checkWritePeerTask)
+	}
 	c.GetCoordinator().GetSchedulersController().CheckTransferWitnessLeader(region)
 }
 
@@ -69,6 +80,7 @@ func HandleOverlaps(ctx context.Context, c Cluster, overlaps []*core.RegionInfo)
 			c.GetRegionStats().ClearDefunctRegion(item.GetID())
 		}
 		c.GetLabelStats().ClearDefunctRegion(item.GetID())
+		c.GetZoneQuorumStats().ClearDefunctRegion(item.GetID())
 		c.GetRuleManager().InvalidCache(item.GetID())
 	}
 }
@@ -0,0 +1,193 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+const (
+	storeHeartbeatKind  = "store"
+	regionHeartbeatKind = "region"
+)
+
+var interceptorDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "pd",
+		Subsystem: "cluster",
+		Name:      "heartbeat_interceptor_duration_seconds",
+		Help:      "Bucketed histogram of processing time (s) of heartbeat interceptors.",
+		Buckets:   prometheus.ExponentialBuckets(0.00001, 2, 16),
+	}, []string{"name", "kind"})
+
+func init() {
+	prometheus.MustRegister(interceptorDuration)
+}
+
+// HeartbeatInterceptor is an in-process hook that observes the heartbeats after
+// they are applied to the cluster. It can be used to feed custom statistics or
+// policy engines. Implementations must be fast and must not modify the given
+// store or region since they are shared with the cache.
+type HeartbeatInterceptor interface {
+	// Name returns the unique name of the interceptor.
+	Name() string
+	// OnStoreHeartbeat is called after the store heartbeat is applied. The
+	// interceptor can annotate the response which will be sent back to the store.
+	OnStoreHeartbeat(store *core.StoreInfo, stats *pdpb.StoreStats, resp *pdpb.StoreHeartbeatResponse)
+	// OnRegionHeartbeat is called after the region heartbeat is applied.
+	OnRegionHeartbeat(region *core.RegionInfo)
+}
+
+// InterceptorStats is the latency accounting of an interceptor.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type InterceptorStats struct {
+	Name                 string        `json:"name"`
+	StoreHeartbeatCount  uint64        `json:"store_heartbeat_count"`
+	StoreHeartbeatTotal  time.Duration `json:"store_heartbeat_total"`
+	StoreHeartbeatMax    time.Duration `json:"store_heartbeat_max"`
+	RegionHeartbeatCount uint64        `json:"region_heartbeat_count"`
+	RegionHeartbeatTotal time.Duration `json:"region_heartbeat_total"`
+	RegionHeartbeatMax   time.Duration `json:"region_heartbeat_max"`
+}
+
+type interceptorEntry struct {
+	interceptor HeartbeatInterceptor
+	storeHist   prometheus.Observer
+	regionHist  prometheus.Observer
+
+	mu    syncutil.Mutex
+	stats InterceptorStats
+}
+
+func (e *interceptorEntry) observe(kind string, cost time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch kind {
+	case storeHeartbeatKind:
+		e.storeHist.Observe(cost.Seconds())
+		e.stats.StoreHeartbeatCount++
+		e.stats.StoreHeartbeatTotal += cost
+		if cost > e.stats.StoreHeartbeatMax {
+			e.stats.StoreHeartbeatMax = cost
+		}
+	case regionHeartbeatKind:
+		e.regionHist.Observe(cost.Seconds())
+		e.stats.RegionHeartbeatCount++
+		e.stats.RegionHeartbeatTotal += cost
+		if cost > e.stats.RegionHeartbeatMax {
+			e.stats.RegionHeartbeatMax = cost
+		}
+	}
+}
+
+// HeartbeatInterceptors is the registration point of heartbeat interceptors.
+type HeartbeatInterceptors struct {
+	syncutil.RWMutex
+	entries []*interceptorEntry
+}
+
+// NewHeartbeatInterceptors creates a new HeartbeatInterceptors.
+func NewHeartbeatInterceptors() *HeartbeatInterceptors {
+	return &HeartbeatInterceptors{}
+}
+
+// Register registers an interceptor. The interceptors are invoked in the order of registration.
+func (h *HeartbeatInterceptors) Register(interceptor HeartbeatInterceptor) error {
+	h.Lock()
+	defer h.Unlock()
+	name := interceptor.Name()
+	for _, e := range h.entries {
+		if e.interceptor.Name() == name {
+			return errs.ErrHeartbeatInterceptorExisted.FastGenByArgs(name)
+		}
+	}
+	entry := &interceptorEntry{
+		interceptor: interceptor,
+		storeHist:   interceptorDuration.WithLabelValues(name, storeHeartbeatKind),
+		regionHist:  interceptorDuration.WithLabelValues(name, regionHeartbeatKind),
+		stats:       InterceptorStats{Name: name},
+	}
+	// copy on write to avoid holding the lock when invoking the interceptors.
+	entries := make([]*interceptorEntry, 0, len(h.entries)+1)
+	entries = append(entries, h.entries...)
+	h.entries = append(entries, entry)
+	return nil
+}
+
+// Unregister removes the interceptor with the given name.
+func (h *HeartbeatInterceptors) Unregister(name string) error {
+	h.Lock()
+	defer h.Unlock()
+	for i, e := range h.entries {
+		if e.interceptor.Name() == name {
+			entries := make([]*interceptorEntry, 0, len(h.entries)-1)
+			entries = append(entries, h.entries[:i]...)
+			h.entries = append(entries, h.entries[i+1:]...)
+			interceptorDuration.DeleteLabelValues(name, storeHeartbeatKind)
+			interceptorDuration.DeleteLabelValues(name, regionHeartbeatKind)
+			return nil
+		}
+	}
+	return errs.ErrHeartbeatInterceptorNotFound.FastGenByArgs(name)
+}
+
+func (h *HeartbeatInterceptors) getEntries() []*interceptorEntry {
+	if h == nil {
+		return nil
+	}
+	h.RLock()
+	defer h.RUnlock()
+	return h.entries
+}
+
+// OnStoreHeartbeat invokes all the registered interceptors for a store heartbeat.
+func (h *HeartbeatInterceptors) OnStoreHeartbeat(store *core.StoreInfo, stats *pdpb.StoreStats, resp *pdpb.StoreHeartbeatResponse) {
+	for _, e := range h.getEntries() {
+		start := time.Now()
+		e.interceptor.OnStoreHeartbeat(store, stats, resp)
+		e.observe(storeHeartbeatKind, time.Since(start))
+	}
+}
+
+// OnRegionHeartbeat invokes all the registered interceptors for a region heartbeat.
+func (h *HeartbeatInterceptors) OnRegionHeartbeat(region *core.RegionInfo) {
+	for _, e := range h.getEntries() {
+		start := time.Now()
+		e.interceptor.OnRegionHeartbeat(region)
+		e.observe(regionHeartbeatKind, time.Since(start))
+	}
+}
+
+// GetStats returns the latency accounting of all the registered interceptors sorted by name.
+func (h *HeartbeatInterceptors) GetStats() []InterceptorStats {
+	entries := h.getEntries()
+	stats := make([]InterceptorStats, 0, len(entries))
+	for _, e := range entries {
+		e.mu.Lock()
+		stats = append(stats, e.stats)
+		e.mu.Unlock()
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+)
+
+type countingInterceptor struct {
+	name    string
+	stores  int
+	regions int
+}
+
+func (i *countingInterceptor) Name() string { return i.name }
+
+func (i *countingInterceptor) OnStoreHeartbeat(_ *core.StoreInfo, _ *pdpb.StoreStats, resp *pdpb.StoreHeartbeatResponse) {
+	i.stores++
+	resp.ClusterVersion = i.name
+}
+
+func (i *countingInterceptor) OnRegionHeartbeat(*core.RegionInfo) {
+	i.regions++
+}
+
+func TestHeartbeatInterceptors(t *testing.T) {
+	re := require.New(t)
+	interceptors := NewHeartbeatInterceptors()
+	a, b := &countingInterceptor{name: "a"}, &countingInterceptor{name: "b"}
+	re.NoError(interceptors.Register(b))
+	re.NoError(interceptors.Register(a))
+	re.Error(interceptors.Register(&countingInterceptor{name: "a"}))
+
+	store := core.NewStoreInfo(&metapb.Store{Id: 1})
+	resp := &pdpb.StoreHeartbeatResponse{}
+	interceptors.OnStoreHeartbeat(store, &pdpb.StoreStats{StoreId: 1}, resp)
+	// interceptors are invoked in the order of registration.
+	re.Equal("a", resp.ClusterVersion)
+	region := core.NewRegionInfo(&metapb.Region{Id: 1}, nil)
+	interceptors.OnRegionHeartbeat(region)
+	interceptors.OnRegionHeartbeat(region)
+	re.Equal(1, a.stores)
+	re.Equal(2, b.regions)
+
+	stats := interceptors.GetStats()
+	re.Len(stats, 2)
+	re.Equal("a", stats[0].Name)
+	re.Equal(uint64(1), stats[0].StoreHeartbeatCount)
+	re.Equal(uint64(2), stats[1].RegionHeartbeatCount)
+
+	re.NoError(interceptors.Unregister("a"))
+	re.Error(interceptors.Unregister("a"))
+	interceptors.OnRegionHeartbeat(region)
+	re.Equal(2, a.regions)
+	re.Equal(3, b.regions)
+
+	// nil interceptors should be safe to call.
+	var empty *HeartbeatInterceptors
+	empty.OnRegionHeartbeat(region)
+}
@@ -33,6 +33,11 @@ const (
 	encGroupSize = 8
 	encMarker    = byte(0xFF)
 	encPad       = byte(0x0)
+
+	// keyspacePrefixLen is the length of the mode prefix and the 3 bytes keyspace ID.
+	keyspacePrefixLen = 4
+	rawKeyspacePrefix = byte('r')
+	txnKeyspacePrefix = byte('x')
 )
 
 // Key represents high-level Key type.
@@ -54,6 +59,22 @@ func (k Key) TableID() int64 {
 	return tableID
 }
 
+// KeyspaceID returns the keyspace ID of the key. The second return value is false
+// if the key doesn't belong to any keyspace.
+func (k Key) KeyspaceID() (uint32, bool) {
+	// The keyspace prefix is always in the first group of the encoded key.
+	if len(k) < encGroupSize+1 {
+		return 0, false
+	}
+	if padCount := encMarker - k[encGroupSize]; padCount > encGroupSize-keyspacePrefixLen {
+		return 0, false
+	}
+	if k[0] != rawKeyspacePrefix && k[0] != txnKeyspacePrefix {
+		return 0, false
+	}
+	return uint32(k[1])<<16 | uint32(k[2])<<8 | uint32(k[3]), true
+}
+
 // MetaOrTable checks if the key is a meta key or table key.
 // If the key is a meta key, it returns true and 0.
 // If the key is a table key, it returns false and table ID.
@@ -47,3 +47,26 @@ func TestTableID(t *testing.T) {
 	key = EncodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\xff"))
 	re.Equal(int64(0), key.TableID())
 }
+
+func TestKeyspaceID(t *testing.T) {
+	re := require.New(t)
+	key := EncodeBytes([]byte("r\x00\x01\x02"))
+	id, ok := key.KeyspaceID()
+	re.True(ok)
+	re.Equal(uint32(0x102), id)
+
+	key = EncodeBytes([]byte("x\x01\x00\x00t\x80\x00\x00\x00\x00\x00\x00\xff"))
+	id, ok = key.KeyspaceID()
+	re.True(ok)
+	re.Equal(uint32(0x10000), id)
+
+	for _, k := range []Key{
+		EncodeBytes([]byte("t\x80\x00\x00\x00\x00\x00\x00\xff")),
+		EncodeBytes([]byte("r\x00\x01")),
+		[]byte("r\x00\x01\x02"),
+		{},
+	} {
+		_, ok = k.KeyspaceID()
+		re.False(ok)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"unsafe"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+)
+
+// MemoryUsage is the estimated memory usage of a component.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type MemoryUsage struct {
+	Component string `json:"component"`
+	Count     int    `json:"count"`
+	Bytes     int64  `json:"bytes"`
+}
+
+var (
+	// regionInfoOverhead is the size of RegionInfo and metapb.Region without the variable parts.
+	regionInfoOverhead = int64(unsafe.Sizeof(RegionInfo{}) + unsafe.Sizeof(metapb.Region{}) + unsafe.Sizeof(metapb.RegionEpoch{}))
+	// peerOverhead is the size of metapb.Peer and its pointer.
+	peerOverhead = int64(unsafe.Sizeof(metapb.Peer{}) + unsafe.Sizeof(&metapb.Peer{}))
+)
+
+// GetMemoryUsage returns the estimated memory usage of the cluster per component.
+func (bc *BasicCluster) GetMemoryUsage() []MemoryUsage {
+	regionCount, metaBytes, keyBytes := bc.RegionsInfo.getMemoryUsage()
+	var labelCount int
+	var labelBytes int64
+	for _, store := range bc.GetStores() {
+		for _, label := range store.GetLabels() {
+			labelCount++
+			labelBytes += int64(len(label.GetKey()) + len(label.GetValue()))
+		}
+	}
+	return []MemoryUsage{
+		{Component: "region-meta", Count: regionCount, Bytes: metaBytes},
+		{Component: "region-keys", Count: regionCount, Bytes: keyBytes},
+		{Component: "store-labels", Count: labelCount, Bytes: labelBytes},
+	}
+}
+
+// getMemoryUsage returns the memory usage of the regions, the keys shared by
+// the adjacent regions are only counted once.
+func (r *RegionsInfo) getMemoryUsage() (count int, metaBytes, keyBytes int64) {
+	r.t.RLock()
+	defer r.t.RUnlock()
+	for _, item := range r.regions {
+		metaBytes += regionInfoOverhead + int64(len(item.GetPeers()))*peerOverhead
+	}
+	var prevEndKey []byte
+	r.tree.scanRange(nil, func(region *RegionInfo) bool {
+		startKey := region.GetStartKey()
+		if !isSameSlice(startKey, prevEndKey) {
+			keyBytes += int64(len(startKey))
+		}
+		prevEndKey = region.GetEndKey()
+		keyBytes += int64(len(prevEndKey))
+		return true
+	})
+	return len(r.regions), metaBytes, keyBytes
+}
+
+// shareRegionKeysLocked makes the region reuse the boundary keys held by its
+// previous version, or by its adjacent regions if the range is changed, so each
+// boundary key is only kept once instead of once per region on both sides of
+// it. It must be called with the write lock held before the region is inserted.
+func (r *RegionsInfo) shareRegionKeysLocked(region, origin *RegionInfo) {
+	meta := region.meta
+	if meta == nil {
+		return
+	}
+	if origin != nil {
+		if bytes.Equal(meta.StartKey, origin.GetStartKey()) {
+			meta.StartKey = origin.GetStartKey()
+		}
+		if bytes.Equal(meta.EndKey, origin.GetEndKey()) {
+			meta.EndKey = origin.GetEndKey()
+		}
+		if origin.rangeEqualsTo(region) {
+			return
+		}
+	}
+	if len(meta.StartKey) > 0 {
+		prev, _ := r.tree.getAdjacentRegions(region)
+		if prev != nil && bytes.Equal(prev.GetEndKey(), meta.StartKey) {
+			meta.StartKey = prev.GetEndKey()
+		}
+	}
+	if len(meta.EndKey) > 0 {
+		next := r.tree.search(meta.EndKey)
+		if next != nil && bytes.Equal(next.GetStartKey(), meta.EndKey) {
+			meta.EndKey = next.GetStartKey()
+		}
+	}
+}
+
+// isSameSlice returns whether the two non-empty slices share the same backing array.
+func isSameSlice(a, b []byte) bool {
+	return len(a) > 0 && len(a) == len(b) && unsafe.SliceData(a) == unsafe.SliceData(b)
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMemoryUsage(t *testing.T) {
+	re := require.New(t)
+	bc := NewBasicCluster()
+	bc.PutRegion(NewRegionInfo(&metapb.Region{Id: 1, StartKey: []byte("a"), EndKey: []byte("b"), RegionEpoch: &metapb.RegionEpoch{}}, nil))
+	bc.PutStore(NewStoreInfo(&metapb.Store{Id: 1, Labels: []*metapb.StoreLabel{{Key: "zone", Value: "z1"}}}))
+
+	usage := bc.GetMemoryUsage()
+	re.Len(usage, 3)
+	re.Equal(MemoryUsage{Component: "region-meta", Count: 1, Bytes: regionInfoOverhead}, usage[0])
+	re.Equal(MemoryUsage{Component: "region-keys", Count: 1, Bytes: 2}, usage[1])
+	re.Equal(MemoryUsage{Component: "store-labels", Count: 1, Bytes: 6}, usage[2])
+}
+
+func TestShareRegionKeys(t *testing.T) {
+	re := require.New(t)
+	regions := NewRegionsInfo()
+	newRegion := func(id uint64, start, end string, version uint64) *RegionInfo {
+		return NewRegionInfo(&metapb.Region{
+			Id:          id,
+			StartKey:    []byte(start),
+			EndKey:      []byte(end),
+			RegionEpoch: &metapb.RegionEpoch{Version: version},
+		}, nil)
+	}
+	regions.PutRegion(newRegion(1, "", "b", 1))
+	regions.PutRegion(newRegion(2, "b", "d", 1))
+	re.True(isSameSlice(regions.GetRegion(1).GetEndKey(), regions.GetRegion(2).GetStartKey()))
+
+	// split region 2 into [b, c) and [c, d).
+	regions.PutRegion(newRegion(3, "b", "c", 2))
+	regions.PutRegion(newRegion(2, "c", "d", 2))
+	re.True(isSameSlice(regions.GetRegion(1).GetEndKey(), regions.GetRegion(3).GetStartKey()))
+	re.True(isSameSlice(regions.GetRegion(3).GetEndKey(), regions.GetRegion(2).GetStartKey()))
+
+	// the new version of a region keeps sharing the keys.
+	regions.PutRegion(newRegion(3, "b", "c", 2))
+	re.True(isSameSlice(regions.GetRegion(1).GetEndKey(), regions.GetRegion(3).GetStartKey()))
+	re.True(isSameSlice(regions.GetRegion(3).GetEndKey(), regions.GetRegion(2).GetStartKey()))
+
+	// each boundary key is only counted once.
+	_, _, keyBytes := regions.getMemoryUsage()
+	re.Equal(int64(3), keyBytes)
+}
+
+// BenchmarkRegionKeysMemory reports the heap used per region after every
+// region has sent a heartbeat. The regions are adjacent to share the boundary
+// keys, or separated by gaps so that every key is kept by one region.
+func BenchmarkRegionKeysMemory(b *testing.B) {
+	const regionCount = 100000
+	newKey := func(i int) []byte {
+		// the keys of the record data in TiDB, e.g. t_{table}_r_{handle}.
+		return []byte(fmt.Sprintf("t\x80\x00\x00\x00\x00\x00\x00\xff%08d_r\x80\x00\x00\x00\x00\xff%08d", i/100, i))
+	}
+	newRegion := func(i, step int) *RegionInfo {
+		return NewRegionInfo(&metapb.Region{
+			Id:          uint64(i + 1),
+			StartKey:    newKey(i * step),
+			EndKey:      newKey(i*step + 1),
+			RegionEpoch: &metapb.RegionEpoch{Version: 1, ConfVer: 1},
+			Peers: []*metapb.Peer{
+				{Id: uint64(i*3 + 1), StoreId: 1},
+				{Id: uint64(i*3 + 2), StoreId: 2},
+				{Id: uint64(i*3 + 3), StoreId: 3},
+			},
+		}, &metapb.Peer{Id: uint64(i*3 + 1), StoreId: 1})
+	}
+	heapAlloc := func() uint64 {
+		runtime.GC()
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return stats.HeapAlloc
+	}
+	for _, adjacent := range []bool{false, true} {
+		step := 2
+		if adjacent {
+			step = 1
+		}
+		b.Run(fmt.Sprintf("adjacent-%v", adjacent), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				before := heapAlloc()
+				regions := NewRegionsInfo()
+				for i := 0; i < regionCount; i++ {
+					regions.SetRegion(newRegion(i, step))
+				}
+				b.ReportMetric(float64(heapAlloc()-before)/regionCount, "bytes/region")
+				runtime.KeepAlive(regions)
+			}
+		})
+	}
+}
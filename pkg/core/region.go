@@ -35,6 +35,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/kvproto/pkg/replication_modepb"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/cache"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/tikv/pd/pkg/utils/syncutil"
@@ -916,6 +917,8 @@ type RegionsInfo struct {
 	pendingPeers map[uint64]*regionTree // storeID -> sub regionTree
 	// This tree is used to check the overlaps among all the subtrees.
 	overlapTree *regionTree
+	// tombstones records the regions removed from the cache for diagnostics.
+	tombstones cache.Cache
 }
 
 // NewRegionsInfo creates RegionsInfo with tree, regions, leaders and followers
@@ -930,6 +933,7 @@ func NewRegionsInfo() *RegionsInfo {
 		witnesses:    make(map[uint64]*regionTree),
 		pendingPeers: make(map[uint64]*regionTree),
 		overlapTree:  newRegionTreeWithCountRef(),
+		tombstones:   newTombstoneCache(),
 	}
 }
 
@@ -1206,6 +1210,7 @@ func (r *RegionsInfo) setRegionLocked(region *RegionInfo, withOverlaps bool, ol
 	if item = r.regions[region.GetID()]; item != nil {
 		// If this ID already exists, use the existing regionItem and pick out the origin.
 		origin = item.RegionInfo
+		r.shareRegionKeysLocked(region, origin)
 		rangeChanged = !origin.rangeEqualsTo(region)
 		if rangeChanged {
 			// Delete itself in regionTree so that overlaps will not contain itself.
@@ -1232,6 +1237,7 @@ func (r *RegionsInfo) setRegionLocked(region *RegionInfo, withOverlaps bool, ol
 		}
 	} else {
 		// If this ID does not exist, generate a new regionItem and save it in the regionMap.
+		r.shareRegionKeysLocked(region, nil)
 		item = &regionItem{RegionInfo: region}
 		r.regions[region.GetID()] = item
 	}
@@ -1240,6 +1246,7 @@ func (r *RegionsInfo) setRegionLocked(region *RegionInfo, withOverlaps bool, ol
 		overlaps = r.tree.update(item, withOverlaps, ol...)
 		for _, old := range overlaps {
 			delete(r.regions, old.GetID())
+			r.recordTombstone(old, TombstoneReasonOverlapped, region.GetID())
 		}
 	}
 	// return rangeChanged to prevent duplicated calculation
@@ -1309,6 +1316,7 @@ func (r *RegionsInfo) RemoveRegion(region *RegionInfo) {
 	// Remove from tree and regions.
 	r.tree.remove(region)
 	delete(r.regions, region.GetID())
+	r.recordTombstone(region, TombstoneReasonRemoved, 0)
 }
 
 // ResetRegionCache resets the regions info.
@@ -1805,6 +1813,28 @@ func (r *RegionsInfo) GetRegionCount(startKey, endKey []byte) int {
 	return endIndex - startIndex + 1
 }
 
+// GetPartitionKeys returns the keys which split the key space into at most n
+// partitions holding about the same number of regions, the keys are sorted and
+// each of them is the start key of a region.
+func (r *RegionsInfo) GetPartitionKeys(n int) [][]byte {
+	r.t.RLock()
+	defer r.t.RUnlock()
+	total := r.tree.length()
+	keys := make([][]byte, 0, n)
+	for i := 1; i < n; i++ {
+		index := total * i / n
+		if index == 0 {
+			continue
+		}
+		key := r.tree.tree.GetAt(index).GetStartKey()
+		if len(keys) > 0 && bytes.Equal(keys[len(keys)-1], key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 // ScanRegions scans regions intersecting [start key, end key), returns at most
 // `limit` regions. limit <= 0 means no limit.
 func (r *RegionsInfo) ScanRegions(startKey, endKey []byte, limit int) []*RegionInfo {
@@ -1201,3 +1201,48 @@ func TestScanRegion(t *testing.T) {
 	re.Len(scanNoError([]byte("a"), []byte("e"), 0), 3)
 	re.Len(scanNoError([]byte("c"), []byte("e"), 0), 1)
 }
+
+func TestGetPartitionKeys(t *testing.T) {
+	re := require.New(t)
+	regions := NewRegionsInfo()
+	re.Empty(regions.GetPartitionKeys(4))
+	for i := uint64(0); i < 10; i++ {
+		regions.CheckAndPutRegion(NewTestRegionInfo(i+1, 1, []byte{byte('a' + i)}, []byte{byte('a' + i + 1)}))
+	}
+	re.Empty(regions.GetPartitionKeys(1))
+	re.Equal([][]byte{[]byte("c"), []byte("f"), []byte("h")}, regions.GetPartitionKeys(4))
+	// There are at most as many partitions as regions.
+	re.Len(regions.GetPartitionKeys(20), 9)
+}
+
+func TestRegionTombstone(t *testing.T) {
+	re := require.New(t)
+	regions := NewRegionsInfo()
+	newRegion := func(id uint64, start, end string, version uint64) *RegionInfo {
+		return NewRegionInfo(&metapb.Region{
+			Id:          id,
+			StartKey:    []byte(start),
+			EndKey:      []byte(end),
+			RegionEpoch: &metapb.RegionEpoch{Version: version},
+			Peers:       []*metapb.Peer{{Id: id + 100, StoreId: 1}},
+		}, nil)
+	}
+	regions.CheckAndPutRegion(newRegion(1, "a", "b", 1))
+	regions.CheckAndPutRegion(newRegion(2, "b", "c", 1))
+	re.Nil(regions.GetRegionTombstone(1))
+
+	// region 2 is merged into region 1.
+	regions.CheckAndPutRegion(newRegion(1, "a", "c", 2))
+	tombstone := regions.GetRegionTombstone(2)
+	re.NotNil(tombstone)
+	re.Equal(TombstoneReasonOverlapped, tombstone.Reason)
+	re.Equal(uint64(1), tombstone.ReplacedBy)
+	re.Equal(HexRegionKeyStr([]byte("b")), tombstone.StartKey)
+	re.Equal([]uint64{1}, tombstone.StoreIDs)
+
+	regions.RemoveRegionIfExist(1)
+	tombstone = regions.GetRegionTombstone(1)
+	re.NotNil(tombstone)
+	re.Equal(TombstoneReasonRemoved, tombstone.Reason)
+	re.Equal(uint64(2), tombstone.Version)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"time"
+
+	"github.com/tikv/pd/pkg/cache"
+)
+
+// maxTombstoneRegions is the max number of the removed regions kept for diagnostics.
+const maxTombstoneRegions = 10000
+
+// TombstoneReason is the reason why the region is removed from the cache.
+type TombstoneReason string
+
+const (
+	// TombstoneReasonOverlapped means the region is replaced by a region which
+	// overlaps with it, e.g. after merge.
+	TombstoneReasonOverlapped TombstoneReason = "overlapped"
+	// TombstoneReasonRemoved means the region is removed from the cache directly.
+	TombstoneReasonRemoved TombstoneReason = "removed"
+)
+
+// RegionTombstone is the metadata of a region which has been removed from the cache.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RegionTombstone struct {
+	RegionID uint64          `json:"region_id"`
+	StartKey string          `json:"start_key"`
+	EndKey   string          `json:"end_key"`
+	ConfVer  uint64          `json:"conf_ver"`
+	Version  uint64          `json:"version"`
+	StoreIDs []uint64        `json:"store_ids"`
+	Reason   TombstoneReason `json:"reason"`
+	// ReplacedBy is the ID of the region which overlaps with the removed one.
+	ReplacedBy uint64    `json:"replaced_by,omitempty"`
+	RemovedAt  time.Time `json:"removed_at"`
+}
+
+func newRegionTombstone(region *RegionInfo, reason TombstoneReason, replacedBy uint64) *RegionTombstone {
+	storeIDs := make([]uint64, 0, len(region.GetPeers()))
+	for _, peer := range region.GetPeers() {
+		storeIDs = append(storeIDs, peer.GetStoreId())
+	}
+	return &RegionTombstone{
+		RegionID:   region.GetID(),
+		StartKey:   HexRegionKeyStr(region.GetStartKey()),
+		EndKey:     HexRegionKeyStr(region.GetEndKey()),
+		ConfVer:    region.GetRegionEpoch().GetConfVer(),
+		Version:    region.GetRegionEpoch().GetVersion(),
+		StoreIDs:   storeIDs,
+		Reason:     reason,
+		ReplacedBy: replacedBy,
+		RemovedAt:  time.Now(),
+	}
+}
+
+func newTombstoneCache() cache.Cache {
+	return cache.NewDefaultCache(maxTombstoneRegions)
+}
+
+// recordTombstone records the removed region, the oldest one will be evicted
+// once the history is full.
+func (r *RegionsInfo) recordTombstone(region *RegionInfo, reason TombstoneReason, replacedBy uint64) {
+	if region == nil {
+		return
+	}
+	r.tombstones.Put(region.GetID(), newRegionTombstone(region, reason, replacedBy))
+}
+
+// GetRegionTombstone returns the metadata of the removed region by its original ID.
+func (r *RegionsInfo) GetRegionTombstone(regionID uint64) *RegionTombstone {
+	if v, ok := r.tombstones.Peek(regionID); ok {
+		return v.(*RegionTombstone)
+	}
+	return nil
+}
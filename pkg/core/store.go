@@ -37,6 +37,8 @@ const (
 	initialMinSpace      = 8 * units.GiB // 2^33=8GB
 	slowStoreThreshold   = 80
 	awakenStoreInterval  = 10 * time.Minute // 2 * slowScoreRecoveryTime
+	// The reported compaction stats are ignored after compactionStatsTTL.
+	compactionStatsTTL = 5 * time.Minute
 
 	// EngineKey is the label key used to indicate engine.
 	EngineKey = "engine"
@@ -67,6 +69,22 @@ type StoreInfo struct {
 	limiter             storelimit.StoreLimit
 	minResolvedTS       uint64
 	lastAwakenTime      time.Time
+	// slowStartTime and slowStartWindow are used to ramp the effective weights
+	// of a new store from 0 to the configured ones.
+	slowStartTime   time.Time
+	slowStartWindow time.Duration
+	compactionStats *CompactionStats
+	// replacementTarget is the store replacing this one, the peers of this
+	// store are preferred to be moved to it.
+	replacementTarget uint64
+}
+
+// CompactionStats is the compaction pressure of the storage engine of a store.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type CompactionStats struct {
+	PendingCompactionBytes uint64    `json:"pending_compaction_bytes"`
+	Level0FileCount        uint64    `json:"level0_file_count"`
+	ReportTime             time.Time `json:"report_time"`
 }
 
 // NewStoreInfo creates StoreInfo with meta data.
@@ -154,6 +172,32 @@ func (s *StoreInfo) IsEvictedAsSlowTrend() bool {
 	return s.slowTrendEvicted
 }
 
+// GetReplacementTarget returns the store replacing this one, 0 means the store
+// is not being replaced.
+func (s *StoreInfo) GetReplacementTarget() uint64 {
+	return s.replacementTarget
+}
+
+// GetCompactionStats returns the compaction stats of the store, it returns nil
+// if the stats are not reported or stale.
+func (s *StoreInfo) GetCompactionStats() *CompactionStats {
+	if s.compactionStats == nil || time.Since(s.compactionStats.ReportTime) > compactionStatsTTL {
+		return nil
+	}
+	return s.compactionStats
+}
+
+// IsCompactionSaturated returns true if the pending compaction bytes or the
+// level0 file count of the store exceeds the limit, 0 means no limit.
+func (s *StoreInfo) IsCompactionSaturated(maxPendingCompactionBytes, maxLevel0FileCount uint64) bool {
+	stats := s.GetCompactionStats()
+	if stats == nil {
+		return false
+	}
+	return (maxPendingCompactionBytes > 0 && stats.PendingCompactionBytes > maxPendingCompactionBytes) ||
+		(maxLevel0FileCount > 0 && stats.Level0FileCount > maxLevel0FileCount)
+}
+
 // IsAvailable returns if the store bucket of limitation is available
 func (s *StoreInfo) IsAvailable(limitType storelimit.Type, level constant.PriorityLevel) bool {
 	s.mu.RLock()
@@ -312,6 +356,39 @@ func (s *StoreInfo) GetRegionWeight() float64 {
 	return s.regionWeight
 }
 
+// IsSlowStarting returns whether the effective weights of the store are still ramping up.
+func (s *StoreInfo) IsSlowStarting() bool {
+	return s.slowStartRatio() < 1
+}
+
+// GetSlowStartDeadline returns the time when the store finishes the slow start.
+func (s *StoreInfo) GetSlowStartDeadline() time.Time {
+	return s.slowStartTime.Add(s.slowStartWindow)
+}
+
+// slowStartRatio returns the ratio of the effective weights to the configured ones.
+func (s *StoreInfo) slowStartRatio() float64 {
+	if s.slowStartWindow <= 0 || s.slowStartTime.IsZero() {
+		return 1
+	}
+	elapsed := time.Since(s.slowStartTime)
+	if elapsed >= s.slowStartWindow {
+		return 1
+	}
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(elapsed) / float64(s.slowStartWindow)
+}
+
+func (s *StoreInfo) effectiveLeaderWeight() float64 {
+	return s.GetLeaderWeight() * s.slowStartRatio()
+}
+
+func (s *StoreInfo) effectiveRegionWeight() float64 {
+	return s.GetRegionWeight() * s.slowStartRatio()
+}
+
 // GetLastHeartbeatTS returns the last heartbeat timestamp of the store.
 func (s *StoreInfo) GetLastHeartbeatTS() time.Time {
 	return time.Unix(0, s.meta.GetLastHeartbeat())
@@ -336,9 +413,9 @@ const maxScore = 1024 * 1024 * 1024
 func (s *StoreInfo) LeaderScore(policy constant.SchedulePolicy, delta int64) float64 {
 	switch policy {
 	case constant.BySize:
-		return float64(s.GetLeaderSize()+delta) / math.Max(s.GetLeaderWeight(), minWeight)
+		return float64(s.GetLeaderSize()+delta) / math.Max(s.effectiveLeaderWeight(), minWeight)
 	case constant.ByCount:
-		return float64(int64(s.GetLeaderCount())+delta) / math.Max(s.GetLeaderWeight(), minWeight)
+		return float64(int64(s.GetLeaderCount())+delta) / math.Max(s.effectiveLeaderWeight(), minWeight)
 	default:
 		return 0
 	}
@@ -401,7 +478,7 @@ func (s *StoreInfo) regionScoreV1(highSpaceRatio, lowSpaceRatio float64, delta i
 		score = k*float64(s.GetRegionSize()+delta) + b
 	}
 
-	return score / math.Max(s.GetRegionWeight(), minWeight)
+	return score / math.Max(s.effectiveRegionWeight(), minWeight)
 }
 
 func (s *StoreInfo) regionScoreV2(delta int64, lowSpaceRatio float64) float64 {
@@ -440,7 +517,7 @@ func (s *StoreInfo) regionScoreV2(delta int64, lowSpaceRatio float64) float64 {
 		// store's score will increase rapidly after it has few space. and it will reach similar score when they has no space
 		score = (K+M*math.Log(C)/C)*R + B*(F-A)/F
 	}
-	return score / math.Max(s.GetRegionWeight(), minWeight)
+	return score / math.Max(s.effectiveRegionWeight(), minWeight)
 }
 
 // StorageSize returns store's used storage size reported from tikv.
@@ -499,13 +576,13 @@ func (s *StoreInfo) ResourceSize(kind constant.ResourceKind) int64 {
 func (s *StoreInfo) ResourceWeight(kind constant.ResourceKind) float64 {
 	switch kind {
 	case constant.LeaderKind:
-		leaderWeight := s.GetLeaderWeight()
+		leaderWeight := s.effectiveLeaderWeight()
 		if leaderWeight <= 0 {
 			return minWeight
 		}
 		return leaderWeight
 	case constant.RegionKind:
-		regionWeight := s.GetRegionWeight()
+		regionWeight := s.effectiveRegionWeight()
 		if regionWeight <= 0 {
 			return minWeight
 		}
@@ -589,18 +666,40 @@ const replicaBaseScore = 100
 // DistinctScore returns the score that the other is distinct from the stores.
 // A higher score means the other store is more different from the existed stores.
 func DistinctScore(labels []string, stores []*StoreInfo, other *StoreInfo) float64 {
+	return WeightedDistinctScore(labels, nil, stores, other)
+}
+
+// WeightedDistinctScore is like DistinctScore, but the score of being distinct
+// at a level is weighted by LocationDistinctScore.
+func WeightedDistinctScore(labels []string, weights []float64, stores []*StoreInfo, other *StoreInfo) float64 {
 	var score float64
 	for _, s := range stores {
 		if s.GetID() == other.GetID() {
 			continue
 		}
 		if index := s.CompareLocation(other, labels); index != -1 {
-			score += math.Pow(replicaBaseScore, float64(len(labels)-index-1))
+			score += LocationDistinctScore(labels, weights, index)
 		}
 	}
 	return score
 }
 
+// LocationDistinctScore returns the score of two stores being distinct at
+// labels[index]. Being distinct at a level implies being distinct at all the
+// lower levels, so the score is the sum of weights[index:]. The default weights
+// are used if the length of the weights doesn't match the labels, which make the
+// higher level always outweigh the lower levels.
+func LocationDistinctScore(labels []string, weights []float64, index int) float64 {
+	if len(weights) != len(labels) {
+		return math.Pow(replicaBaseScore, float64(len(labels)-index-1))
+	}
+	var score float64
+	for _, weight := range weights[index:] {
+		score += weight
+	}
+	return score
+}
+
 // MergeLabels merges the passed in labels with origins, overriding duplicated ones.
 // Note: To prevent potential data races, it is advisable to refrain from directly modifying the 'origin' variable.
 func MergeLabels(origin []*metapb.StoreLabel, labels []*metapb.StoreLabel) []*metapb.StoreLabel {
@@ -833,6 +932,18 @@ func (s *StoresInfo) SlowStoreRecovered(storeID uint64) {
 	s.stores[storeID] = store.Clone(SlowStoreRecovered())
 }
 
+// UpdateCompactionStats updates the compaction stats reported by the store.
+func (s *StoresInfo) UpdateCompactionStats(storeID uint64, stats *CompactionStats) error {
+	s.Lock()
+	defer s.Unlock()
+	store, ok := s.stores[storeID]
+	if !ok {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	s.stores[storeID] = store.Clone(SetCompactionStats(stats))
+	return nil
+}
+
 // SlowTrendEvicted marks a store as a slow trend and prevents transferring
 // leader to the store
 func (s *StoresInfo) SlowTrendEvicted(storeID uint64) error {
@@ -144,6 +144,13 @@ func SlowStoreRecovered() StoreCreateOption {
 	}
 }
 
+// SetCompactionStats sets the compaction stats for the store.
+func SetCompactionStats(stats *CompactionStats) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.compactionStats = stats
+	}
+}
+
 // SetLeaderCount sets the leader count for the store.
 func SetLeaderCount(leaderCount int) StoreCreateOption {
 	return func(store *StoreInfo) {
@@ -207,6 +214,22 @@ func SetRegionWeight(regionWeight float64) StoreCreateOption {
 	}
 }
 
+// SetSlowStart makes the effective weights of the store ramp from 0 to the
+// configured ones within the window since the start time.
+func SetSlowStart(startTime time.Time, window time.Duration) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.slowStartTime = startTime
+		store.slowStartWindow = window
+	}
+}
+
+// SetReplacementTarget sets the store replacing this one, 0 means no replacement.
+func SetReplacementTarget(storeID uint64) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.replacementTarget = storeID
+	}
+}
+
 // SetLastHeartbeatTS sets the time of last heartbeat for the store.
 func SetLastHeartbeatTS(lastHeartbeatTS time.Time) StoreCreateOption {
 	return func(store *StoreInfo) {
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/utils/typeutil"
 )
 
@@ -62,6 +63,30 @@ func TestDistinctScore(t *testing.T) {
 	re.Equal(float64(0), DistinctScore(labels, stores, store))
 }
 
+func TestWeightedDistinctScore(t *testing.T) {
+	re := require.New(t)
+	labels := []string{"zone", "rack"}
+	stores := []*StoreInfo{
+		NewStoreInfoWithLabel(1, map[string]string{"zone": "z1", "rack": "r1"}),
+		NewStoreInfoWithLabel(2, map[string]string{"zone": "z1", "rack": "r2"}),
+	}
+	otherZone := NewStoreInfoWithLabel(3, map[string]string{"zone": "z2", "rack": "r1"})
+	otherRack := NewStoreInfoWithLabel(4, map[string]string{"zone": "z1", "rack": "r3"})
+	re.Equal(DistinctScore(labels, stores, otherZone), WeightedDistinctScore(labels, nil, stores, otherZone))
+	re.Equal(float64(2*replicaBaseScore), WeightedDistinctScore(labels, nil, stores, otherZone))
+	re.Equal(float64(2), WeightedDistinctScore(labels, nil, stores, otherRack))
+
+	// the rack diversity is weighted higher than the zone diversity, but a
+	// different zone is also a different rack so it still ranks higher.
+	weights := []float64{1, 10}
+	re.Equal(float64(22), WeightedDistinctScore(labels, weights, stores, otherZone))
+	re.Equal(float64(20), WeightedDistinctScore(labels, weights, stores, otherRack))
+	re.Equal(float64(11), LocationDistinctScore(labels, weights, 0))
+	re.Equal(float64(10), LocationDistinctScore(labels, weights, 1))
+	// the mismatched weights are ignored.
+	re.Equal(float64(2), WeightedDistinctScore(labels, []float64{1}, stores, otherRack))
+}
+
 func TestCloneStore(_ *testing.T) {
 	meta := &metapb.Store{Id: 1, Address: "mock://tikv-1", Labels: []*metapb.StoreLabel{{Key: "zone", Value: "z1"}, {Key: "host", Value: "h1"}}}
 	store := NewStoreInfo(meta)
@@ -235,3 +260,29 @@ func newStoreInfoWithDisk(id, used, available, capacity, regionSize uint64) *Sto
 	)
 	return store
 }
+
+func TestSlowStart(t *testing.T) {
+	re := require.New(t)
+	store := NewStoreInfoWithLabel(1, nil).Clone(SetRegionSize(100), SetLeaderCount(10))
+	re.False(store.IsSlowStarting())
+	regionScore := store.RegionScore("v1", 0.7, 0.8, 0)
+	leaderScore := store.LeaderScore(constant.ByCount, 0)
+
+	// the store is just started, so the effective weights are close to 0.
+	slow := store.Clone(SetSlowStart(time.Now(), time.Hour))
+	re.True(slow.IsSlowStarting())
+	re.Greater(slow.RegionScore("v1", 0.7, 0.8, 0), regionScore*100)
+	re.Greater(slow.LeaderScore(constant.ByCount, 0), leaderScore*100)
+	re.Greater(slow.ResourceWeight(constant.RegionKind), 0.0)
+
+	// half of the window is passed.
+	slow = store.Clone(SetSlowStart(time.Now().Add(-30*time.Minute), time.Hour))
+	re.True(slow.IsSlowStarting())
+	re.InDelta(regionScore*2, slow.RegionScore("v1", 0.7, 0.8, 0), regionScore*0.01)
+
+	// the window is passed.
+	slow = store.Clone(SetSlowStart(time.Now().Add(-time.Hour), time.Hour))
+	re.False(slow.IsSlowStarting())
+	re.Equal(regionScore, slow.RegionScore("v1", 0.7, 0.8, 0))
+	re.Equal(leaderScore, slow.LeaderScore(constant.ByCount, 0))
+}
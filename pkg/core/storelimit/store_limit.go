@@ -18,6 +18,7 @@ import (
 	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/ratelimit"
 	"github.com/tikv/pd/pkg/utils/syncutil"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -110,6 +111,15 @@ func (l *StoreRateLimit) Rate(typ Type) float64 {
 	return l.limits[typ].GetRatePerSec()
 }
 
+// Tokens returns the number of the available tokens, it returns 0 if the rate
+// is not limited.
+func (l *StoreRateLimit) Tokens(typ Type) float64 {
+	if typ == SendSnapshot || l.limits[typ] == nil {
+		return 0.0
+	}
+	return l.limits[typ].Tokens()
+}
+
 // Take takes count tokens from the bucket without blocking.
 // notice that the priority level is not used.
 func (l *StoreRateLimit) Take(cost int64, typ Type, _ constant.PriorityLevel) bool {
@@ -127,6 +137,15 @@ func (l *StoreRateLimit) Reset(rate float64, typ Type) {
 	l.limits[typ].Reset(rate)
 }
 
+// SetRate changes the rate limit but keeps the available tokens, unlike Reset,
+// so that adjusting the rate frequently doesn't refill the bucket.
+func (l *StoreRateLimit) SetRate(rate float64, typ Type) {
+	if typ == SendSnapshot {
+		return
+	}
+	l.limits[typ].SetRate(rate)
+}
+
 // limit the operators of a store
 type limit struct {
 	limiter         *ratelimit.RateLimiter
@@ -156,6 +175,29 @@ func (l *limit) Reset(ratePerSec float64) {
 	l.ratePerSec = rate
 }
 
+// SetRate changes the rate limit and keeps the tokens if it's limited before and
+// after, otherwise it's the same as Reset.
+func (l *limit) SetRate(ratePerSec float64) {
+	l.ratePerSecMutex.Lock()
+	if l.limiter == nil || !isLimited(l.ratePerSec) || !isLimited(ratePerSec) {
+		l.ratePerSecMutex.Unlock()
+		l.Reset(ratePerSec)
+		return
+	}
+	defer l.ratePerSecMutex.Unlock()
+	capacity := int64(influence)
+	if ratePerSec > 1 {
+		capacity = int64(ratePerSec * float64(influence))
+	}
+	l.limiter.SetLimit(rate.Limit(ratePerSec * float64(influence)))
+	l.limiter.SetBurst(int(capacity))
+	l.ratePerSec = ratePerSec
+}
+
+func isLimited(ratePerSec float64) bool {
+	return ratePerSec > 0 && ratePerSec < Unlimited
+}
+
 // Available returns the number of available tokens
 // It returns true if the rate per second is zero.
 func (l *limit) Available(n int64) bool {
@@ -178,6 +220,16 @@ func (l *limit) Take(count int64) bool {
 	return l.limiter.AllowN(int(count))
 }
 
+// Tokens returns the number of the available tokens.
+func (l *limit) Tokens() float64 {
+	l.ratePerSecMutex.RLock()
+	defer l.ratePerSecMutex.RUnlock()
+	if l.ratePerSec == 0 || l.limiter == nil {
+		return 0.0
+	}
+	return l.limiter.Tokens()
+}
+
 func (l *limit) GetRatePerSec() float64 {
 	l.ratePerSecMutex.RLock()
 	defer l.ratePerSecMutex.RUnlock()
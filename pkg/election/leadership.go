@@ -291,7 +291,7 @@ func (ls *Leadership) Watch(serverCtx context.Context, revision int64) {
 		if watcher != nil {
 			watcher.Close()
 		}
-		watcher = clientv3.NewWatcher(ls.client)
+		watcher = etcdutil.NewWatcher(ls.client)
 		// In order to prevent a watch stream being stuck in a partitioned node,
 		// make sure to wrap context with "WithRequireLeader".
 		watcherCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(serverCtx))
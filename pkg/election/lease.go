@@ -31,7 +31,6 @@ import (
 const (
 	revokeLeaseTimeout = time.Second
 	requestTimeout     = etcdutil.DefaultRequestTimeout
-	slowRequestTime    = etcdutil.DefaultSlowRequestTime
 )
 
 // lease is used as the low-level mechanism for campaigning and renewing elected leadership.
@@ -60,7 +59,7 @@ func (l *lease) Grant(leaseTimeout int64) error {
 	if err != nil {
 		return errs.ErrEtcdGrantLease.Wrap(err).GenWithStackByCause()
 	}
-	if cost := time.Since(start); cost > slowRequestTime {
+	if cost := time.Since(start); etcdutil.RecordSlowLease(l.Purpose, cost, nil) {
 		log.Warn("lease grants too slow", zap.Duration("cost", cost), zap.String("purpose", l.Purpose))
 	}
 	log.Info("lease granted", zap.Int64("lease-id", int64(leaseResp.ID)), zap.Int64("lease-timeout", leaseTimeout), zap.String("purpose", l.Purpose))
@@ -58,6 +58,12 @@ var (
 	ErrKeyspaceNotAssigned              = errors.Normalize("the keyspace %d isn't assigned to any keyspace group", errors.RFCCodeText("PD:tso:ErrKeyspaceNotAssigned"))
 	ErrGetMinTS                         = errors.Normalize("get min ts failed, %s", errors.RFCCodeText("PD:tso:ErrGetMinTS"))
 	ErrKeyspaceGroupIsMerging           = errors.Normalize("the keyspace group %d is merging", errors.RFCCodeText("PD:tso:ErrKeyspaceGroupIsMerging"))
+	ErrBenchAPIDisabled                 = errors.Normalize("the bench API is disabled", errors.RFCCodeText("PD:tso:ErrBenchAPIDisabled"))
+	ErrInvalidBenchParams               = errors.Normalize("invalid bench params, %s", errors.RFCCodeText("PD:tso:ErrInvalidBenchParams"))
+	ErrTSOServerOverloaded              = errors.Normalize("the tso server is overloaded, %s", errors.RFCCodeText("PD:tso:ErrTSOServerOverloaded"))
+	ErrTSODrainTimeout                  = errors.Normalize("failed to hand off the primaries of the keyspace groups %v before timeout", errors.RFCCodeText("PD:tso:ErrTSODrainTimeout"))
+	ErrClockDrift                       = errors.Normalize("the drift %v of the local clock exceeds the max clock drift %v", errors.RFCCodeText("PD:tso:ErrClockDrift"))
+	ErrInvalidTSOConfig                 = errors.Normalize("invalid tso config %s, %s", errors.RFCCodeText("PD:tso:ErrInvalidTSOConfig"))
 )
 
 // member errors
@@ -137,12 +143,19 @@ var (
 	ErrInternalGrowth                   = errors.Normalize("unknown interval growth type error", errors.RFCCodeText("PD:scheduler:ErrInternalGrowth"))
 	ErrSchedulerCreateFuncNotRegistered = errors.Normalize("create func of %v is not registered", errors.RFCCodeText("PD:scheduler:ErrSchedulerCreateFuncNotRegistered"))
 	ErrSchedulerTiKVSplitDisabled       = errors.Normalize("tikv split region disabled", errors.RFCCodeText("PD:scheduler:ErrSchedulerTiKVSplitDisabled"))
+	ErrSchedulerEvictionRefused         = errors.Normalize("eviction of store %d is refused, %s", errors.RFCCodeText("PD:scheduler:ErrSchedulerEvictionRefused"))
+	ErrSchedulersNotInitialized         = errors.Normalize("the schedulers are not initialized yet", errors.RFCCodeText("PD:scheduler:ErrSchedulersNotInitialized"))
+	ErrSchedulingProfileNotFound        = errors.Normalize("scheduling profile %s not found", errors.RFCCodeText("PD:scheduler:ErrSchedulingProfileNotFound"))
+	ErrSchedulerNotDryRunnable          = errors.Normalize("%v doesn't support dry run", errors.RFCCodeText("PD:scheduler:ErrSchedulerNotDryRunnable"))
 )
 
 // checker errors
 var (
-	ErrCheckerNotFound   = errors.Normalize("checker not found", errors.RFCCodeText("PD:checker:ErrCheckerNotFound"))
-	ErrCheckerMergeAgain = errors.Normalize("region will be merged again, %s", errors.RFCCodeText("PD:checker:ErrCheckerMergeAgain"))
+	ErrCheckerNotFound        = errors.Normalize("checker not found", errors.RFCCodeText("PD:checker:ErrCheckerNotFound"))
+	ErrCheckerMergeAgain      = errors.Normalize("region will be merged again, %s", errors.RFCCodeText("PD:checker:ErrCheckerMergeAgain"))
+	ErrRangeMergeInvalidRange = errors.Normalize("invalid range to merge, %s", errors.RFCCodeText("PD:checker:ErrRangeMergeInvalidRange"))
+	ErrRangeMergeJobNotFound  = errors.Normalize("range merge job %d not found", errors.RFCCodeText("PD:checker:ErrRangeMergeJobNotFound"))
+	ErrRangeMergeTooManyJobs  = errors.Normalize("too many running range merge jobs, the limit is %d", errors.RFCCodeText("PD:checker:ErrRangeMergeTooManyJobs"))
 )
 
 // diagnostic errors
@@ -155,13 +168,18 @@ var (
 
 // placement errors
 var (
-	ErrRuleContent       = errors.Normalize("invalid rule content, %s", errors.RFCCodeText("PD:placement:ErrRuleContent"))
-	ErrLoadRule          = errors.Normalize("load rule failed", errors.RFCCodeText("PD:placement:ErrLoadRule"))
-	ErrLoadRuleGroup     = errors.Normalize("load rule group failed", errors.RFCCodeText("PD:placement:ErrLoadRuleGroup"))
-	ErrBuildRuleList     = errors.Normalize("build rule list failed, %s", errors.RFCCodeText("PD:placement:ErrBuildRuleList"))
-	ErrPlacementDisabled = errors.Normalize("placement rules feature is disabled", errors.RFCCodeText("PD:placement:ErrPlacementDisabled"))
-	ErrKeyFormat         = errors.Normalize("key should be in hex format, %s", errors.RFCCodeText("PD:placement:ErrKeyFormat"))
-	ErrRuleNotFound      = errors.Normalize("rule not found", errors.RFCCodeText("PD:placement:ErrRuleNotFound"))
+	ErrRuleContent        = errors.Normalize("invalid rule content, %s", errors.RFCCodeText("PD:placement:ErrRuleContent"))
+	ErrLoadRule           = errors.Normalize("load rule failed", errors.RFCCodeText("PD:placement:ErrLoadRule"))
+	ErrLoadRuleGroup      = errors.Normalize("load rule group failed", errors.RFCCodeText("PD:placement:ErrLoadRuleGroup"))
+	ErrBuildRuleList      = errors.Normalize("build rule list failed, %s", errors.RFCCodeText("PD:placement:ErrBuildRuleList"))
+	ErrPlacementDisabled  = errors.Normalize("placement rules feature is disabled", errors.RFCCodeText("PD:placement:ErrPlacementDisabled"))
+	ErrKeyFormat          = errors.Normalize("key should be in hex format, %s", errors.RFCCodeText("PD:placement:ErrKeyFormat"))
+	ErrRuleNotFound       = errors.Normalize("rule not found", errors.RFCCodeText("PD:placement:ErrRuleNotFound"))
+	ErrStoreGroupContent  = errors.Normalize("invalid store group content, %s", errors.RFCCodeText("PD:placement:ErrStoreGroupContent"))
+	ErrLoadStoreGroup     = errors.Normalize("load store group failed", errors.RFCCodeText("PD:placement:ErrLoadStoreGroup"))
+	ErrStoreGroupNotFound = errors.Normalize("store group %s not found", errors.RFCCodeText("PD:placement:ErrStoreGroupNotFound"))
+	ErrStoreGroupInUse    = errors.Normalize("store group %s is used by rule %s", errors.RFCCodeText("PD:placement:ErrStoreGroupInUse"))
+	ErrRulesChanged       = errors.Normalize("the rules are changed concurrently, please retry", errors.RFCCodeText("PD:placement:ErrRulesChanged"))
 )
 
 // region label errors
@@ -172,10 +190,16 @@ var (
 
 // cluster errors
 var (
-	ErrNotBootstrapped    = errors.Normalize("TiKV cluster not bootstrapped, please start TiKV first", errors.RFCCodeText("PD:cluster:ErrNotBootstrapped"))
-	ErrStoreIsUp          = errors.Normalize("store is still up, please remove store gracefully", errors.RFCCodeText("PD:cluster:ErrStoreIsUp"))
-	ErrInvalidStoreID     = errors.Normalize("invalid store id %d, not found", errors.RFCCodeText("PD:cluster:ErrInvalidStoreID"))
-	ErrSchedulingIsHalted = errors.Normalize("scheduling is halted", errors.RFCCodeText("PD:cluster:ErrSchedulingIsHalted"))
+	ErrNotBootstrapped               = errors.Normalize("TiKV cluster not bootstrapped, please start TiKV first", errors.RFCCodeText("PD:cluster:ErrNotBootstrapped"))
+	ErrStoreIsUp                     = errors.Normalize("store is still up, please remove store gracefully", errors.RFCCodeText("PD:cluster:ErrStoreIsUp"))
+	ErrInvalidStoreAddressChange     = errors.Normalize("invalid address change of store %d, %s", errors.RFCCodeText("PD:cluster:ErrInvalidStoreAddressChange"))
+	ErrInvalidStoreID                = errors.Normalize("invalid store id %d, not found", errors.RFCCodeText("PD:cluster:ErrInvalidStoreID"))
+	ErrInvalidStoreLabels            = errors.Normalize("invalid labels of store %d, %s", errors.RFCCodeText("PD:cluster:ErrInvalidStoreLabels"))
+	ErrInvalidStoreReplacement       = errors.Normalize("invalid replacement of store %d, %s", errors.RFCCodeText("PD:cluster:ErrInvalidStoreReplacement"))
+	ErrSchedulingIsHalted            = errors.Normalize("scheduling is halted", errors.RFCCodeText("PD:cluster:ErrSchedulingIsHalted"))
+	ErrHeartbeatInterceptorExisted   = errors.Normalize("heartbeat interceptor %s existed", errors.RFCCodeText("PD:cluster:ErrHeartbeatInterceptorExisted"))
+	ErrHeartbeatInterceptorNotFound  = errors.Normalize("heartbeat interceptor %s not found", errors.RFCCodeText("PD:cluster:ErrHeartbeatInterceptorNotFound"))
+	ErrStoreEventRevisionUnavailable = errors.Normalize("store event revision %d is unavailable, the available revisions are [%d, %d]", errors.RFCCodeText("PD:cluster:ErrStoreEventRevisionUnavailable"))
 )
 
 // versioninfo errors
@@ -200,6 +224,7 @@ var (
 	ErrRedirectNoLeader     = errors.Normalize("redirect finds no leader", errors.RFCCodeText("PD:apiutil:ErrRedirectNoLeader"))
 	ErrRedirectToNotLeader  = errors.Normalize("redirect to not leader", errors.RFCCodeText("PD:apiutil:ErrRedirectToNotLeader"))
 	ErrRedirectToNotPrimary = errors.Normalize("redirect to not primary", errors.RFCCodeText("PD:apiutil:ErrRedirectToNotPrimary"))
+	ErrPreconditionFailed   = errors.Normalize("the resource has been modified, please get it again", errors.RFCCodeText("PD:apiutil:ErrPreconditionFailed"))
 )
 
 // grpcutil errors
@@ -400,6 +425,11 @@ var (
 	ErrUnsafeRecoveryInvalidInput = errors.Normalize("invalid input %s", errors.RFCCodeText("PD:unsaferecovery:ErrUnsafeRecoveryInvalidInput"))
 )
 
+// validation errors
+var (
+	ErrChangeRejected = errors.Normalize("the change of %s is rejected by validator %s: %s", errors.RFCCodeText("PD:validation:ErrChangeRejected"))
+)
+
 // progress errors
 var (
 	ErrProgressWrongStatus = errors.Normalize("progress status is wrong", errors.RFCCodeText("PD:progress:ErrProgressWrongStatus"))
@@ -411,6 +441,7 @@ var (
 	ErrResourceGroupNotExists = errors.Normalize("the %s resource group does not exist", errors.RFCCodeText("PD:resourcemanager:ErrGroupNotExists"))
 	ErrDeleteReservedGroup    = errors.Normalize("cannot delete reserved group", errors.RFCCodeText("PD:resourcemanager:ErrDeleteReservedGroup"))
 	ErrInvalidGroup           = errors.Normalize("invalid group settings, please check the group name, priority and the number of resources", errors.RFCCodeText("PD:resourcemanager:ErrInvalidGroup"))
+	ErrInvalidGroupHierarchy  = errors.Normalize("invalid resource group hierarchy, %s", errors.RFCCodeText("PD:resourcemanager:ErrInvalidGroupHierarchy"))
 )
 
 // Micro service errors
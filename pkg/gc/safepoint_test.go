@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/storage/kv"
+	"github.com/tikv/pd/pkg/utils/tsoutil"
 	"github.com/tikv/pd/server/config"
 )
 
@@ -182,3 +183,35 @@ func TestBlockUpdateSafePointV1(t *testing.T) {
 
 	re.Equal(uint64(0), oldSafePoint)
 }
+
+func TestSimulateGCSafePointAdvance(t *testing.T) {
+	re := require.New(t)
+	now := time.Now()
+	ts := func(d time.Duration) uint64 {
+		return tsoutil.ComposeTS(now.Add(d).UnixMilli(), 0)
+	}
+	ssps := []*endpoint.ServiceSafePoint{
+		{ServiceID: endpoint.GCWorkerServiceSafePointID, ExpiredAt: math.MaxInt64, SafePoint: ts(0)},
+		{ServiceID: "br", ExpiredAt: now.Unix() + 100, SafePoint: ts(-time.Hour)},
+		{ServiceID: "cdc", ExpiredAt: now.Unix() + 100, SafePoint: ts(-10 * time.Minute)},
+		{ServiceID: "lightning", ExpiredAt: now.Unix() + 100, SafePoint: ts(time.Minute)},
+		{ServiceID: "expired", ExpiredAt: now.Unix() - 1, SafePoint: ts(-2 * time.Hour)},
+	}
+	sim := SimulateGCSafePointAdvance(ssps, now)
+	re.Equal(ts(-time.Hour), sim.MinServiceGCSafePoint)
+	re.Equal(ts(0), sim.SimulatedGCSafePoint)
+	re.Equal(time.Hour, sim.Lag.Duration)
+	re.Len(sim.Blockers, 2)
+	re.Equal("br", sim.Blockers[0].ServiceID)
+	re.Equal(time.Hour, sim.Blockers[0].Lag.Duration)
+	re.Equal(50*time.Minute, sim.Blockers[0].Advance.Duration)
+	re.Equal("cdc", sim.Blockers[1].ServiceID)
+	re.Equal(10*time.Minute, sim.Blockers[1].Lag.Duration)
+	re.Zero(sim.Blockers[1].Advance.Duration)
+
+	// nothing blocks the GC worker.
+	sim = SimulateGCSafePointAdvance(ssps[3:], now)
+	re.Equal(ts(time.Minute), sim.MinServiceGCSafePoint)
+	re.Equal(ts(time.Minute), sim.SimulatedGCSafePoint)
+	re.Empty(sim.Blockers)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/tsoutil"
+	"github.com/tikv/pd/pkg/utils/typeutil"
+)
+
+// GCSafePointBlocker is a service whose safepoint holds the GC safepoint back
+// from the one of the GC worker.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type GCSafePointBlocker struct {
+	ServiceID string `json:"service_id"`
+	SafePoint uint64 `json:"safe_point"`
+	ExpiredAt int64  `json:"expired_at"`
+	// Lag is how far the safepoint of the service is behind the GC worker.
+	Lag typeutil.Duration `json:"lag"`
+	// Advance is how far the GC safepoint would advance if only this service
+	// were ignored, it's zero unless the service is the only minimum one.
+	Advance typeutil.Duration `json:"advance"`
+}
+
+// GCSafePointSimulation is the result of simulating the GC safepoint advance
+// with all the blocking services ignored.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type GCSafePointSimulation struct {
+	MinServiceGCSafePoint uint64                `json:"min_service_gc_safe_point"`
+	SimulatedGCSafePoint  uint64                `json:"simulated_gc_safe_point"`
+	Lag                   typeutil.Duration     `json:"lag"`
+	Blockers              []*GCSafePointBlocker `json:"blockers"`
+}
+
+// SimulateGCSafePointAdvance computes what the min service safepoint would be
+// if all the services behind the GC worker were ignored. The expired service
+// safepoints are skipped. Without the GC worker, there is nothing to advance to.
+func SimulateGCSafePointAdvance(ssps []*endpoint.ServiceSafePoint, now time.Time) *GCSafePointSimulation {
+	var (
+		valid    []*endpoint.ServiceSafePoint
+		gcWorker *endpoint.ServiceSafePoint
+	)
+	for _, ssp := range ssps {
+		if ssp.ExpiredAt < now.Unix() {
+			continue
+		}
+		if ssp.ServiceID == endpoint.GCWorkerServiceSafePointID {
+			gcWorker = ssp
+		}
+		valid = append(valid, ssp)
+	}
+	sim := &GCSafePointSimulation{Blockers: []*GCSafePointBlocker{}}
+	if len(valid) == 0 {
+		return sim
+	}
+	sort.SliceStable(valid, func(i, j int) bool {
+		if valid[i].SafePoint != valid[j].SafePoint {
+			return valid[i].SafePoint < valid[j].SafePoint
+		}
+		return valid[i].ServiceID < valid[j].ServiceID
+	})
+	sim.MinServiceGCSafePoint = valid[0].SafePoint
+	sim.SimulatedGCSafePoint = sim.MinServiceGCSafePoint
+	if gcWorker == nil {
+		return sim
+	}
+	sim.SimulatedGCSafePoint = gcWorker.SafePoint
+	sim.Lag = safePointDistance(sim.MinServiceGCSafePoint, gcWorker.SafePoint)
+	for i, ssp := range valid {
+		if ssp.SafePoint >= gcWorker.SafePoint {
+			break
+		}
+		blocker := &GCSafePointBlocker{
+			ServiceID: ssp.ServiceID,
+			SafePoint: ssp.SafePoint,
+			ExpiredAt: ssp.ExpiredAt,
+			Lag:       safePointDistance(ssp.SafePoint, gcWorker.SafePoint),
+		}
+		// The gc_worker is ahead of the blockers, so there is always a next one.
+		if i == 0 && valid[1].SafePoint > ssp.SafePoint {
+			blocker.Advance = safePointDistance(ssp.SafePoint, valid[1].SafePoint)
+		}
+		sim.Blockers = append(sim.Blockers, blocker)
+	}
+	return sim
+}
+
+// safePointDistance returns the physical duration between two safepoints.
+func safePointDistance(from, to uint64) typeutil.Duration {
+	fromTime, _ := tsoutil.ParseTS(from)
+	toTime, _ := tsoutil.ParseTS(to)
+	return typeutil.NewDuration(toTime.Sub(fromTime))
+}
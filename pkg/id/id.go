@@ -15,7 +15,9 @@
 package id
 
 import (
+	"math"
 	"path"
+	"time"
 
 	"github.com/pingcap/log"
 	"github.com/prometheus/client_golang/prometheus"
@@ -38,9 +40,47 @@ type Allocator interface {
 	// which also resets the end of the allocator. (base, end) is the range that can
 	// be allocated in memory.
 	Rebase() error
+	// GetStats returns the diagnostics of the allocator.
+	GetStats() *Stats
 }
 
-const defaultAllocStep = uint64(1000)
+const (
+	defaultAllocStep = uint64(1000)
+	// rateWindow is the time window used to calculate the consumption rate.
+	rateWindow = 10 * time.Minute
+	// fastRebaseInterval and slowRebaseInterval are used to adjust the step
+	// dynamically. If the window is used up faster than fastRebaseInterval,
+	// the step is doubled, and if it's slower than slowRebaseInterval, the
+	// step is halved until it's back to the base step.
+	fastRebaseInterval = 3 * time.Second
+	slowRebaseInterval = time.Minute
+	// exhaustionAlarmHorizon is the projected exhaustion horizon below which
+	// the allocator raises the alarm.
+	exhaustionAlarmHorizon = 365 * 24 * time.Hour
+)
+
+// Stats is the diagnostics of the ID allocator.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type Stats struct {
+	Label string `json:"label"`
+	Base  uint64 `json:"base"`
+	End   uint64 `json:"end"`
+	// Step is the current step of the persistent window boundary increment.
+	Step     uint64 `json:"step"`
+	BaseStep uint64 `json:"base-step"`
+	MaxStep  uint64 `json:"max-step"`
+	// ConsumptionRate is the number of IDs allocated per second in the recent window.
+	ConsumptionRate float64 `json:"consumption-rate"`
+	// ExhaustionHorizon is the projected seconds until the IDs are used up at
+	// the current rate, it's 0 if no consumption is observed.
+	ExhaustionHorizon float64 `json:"exhaustion-horizon"`
+	Alarm             bool    `json:"alarm"`
+}
+
+type allocSample struct {
+	time time.Time
+	base uint64
+}
 
 // allocatorImpl is used to allocate ID.
 type allocatorImpl struct {
@@ -54,12 +94,21 @@ type allocatorImpl struct {
 	label     string
 	member    string
 	step      uint64
+	baseStep  uint64
+	maxStep   uint64
 	metrics   *metrics
+
+	lastRebase time.Time
+	// samples are the allocated bases at the recent rebases, used to
+	// calculate the consumption rate.
+	samples []allocSample
 }
 
 // metrics is a collection of idAllocator's metrics.
 type metrics struct {
-	idGauge prometheus.Gauge
+	idGauge      prometheus.Gauge
+	stepGauge    prometheus.Gauge
+	horizonGauge prometheus.Gauge
 }
 
 // AllocatorParams are parameters needed to create a new ID Allocator.
@@ -70,6 +119,9 @@ type AllocatorParams struct {
 	Label     string // Label used to label metrics and logs.
 	Member    string // Member value, used to check if current pd leader.
 	Step      uint64 // Step size of each persistent window boundary increment, default 1000.
+	// MaxStep is the max step that the step can be enlarged to when the IDs are
+	// allocated quickly, the step is fixed if it's not larger than Step.
+	MaxStep uint64
 }
 
 // NewAllocator creates a new ID Allocator.
@@ -81,11 +133,21 @@ func NewAllocator(params *AllocatorParams) Allocator {
 		label:     params.Label,
 		member:    params.Member,
 		step:      params.Step,
-		metrics:   &metrics{idGauge: idGauge.WithLabelValues(params.Label)},
+		maxStep:   params.MaxStep,
+		metrics: &metrics{
+			idGauge:      idGauge.WithLabelValues(params.Label),
+			stepGauge:    idAllocStepGauge.WithLabelValues(params.Label),
+			horizonGauge: idExhaustionHorizonGauge.WithLabelValues(params.Label),
+		},
 	}
 	if allocator.step == 0 {
 		allocator.step = defaultAllocStep
 	}
+	allocator.baseStep = allocator.step
+	if allocator.maxStep < allocator.step {
+		allocator.maxStep = allocator.step
+	}
+	allocator.metrics.stepGauge.Set(float64(allocator.step))
 	return allocator
 }
 
@@ -95,6 +157,7 @@ func (alloc *allocatorImpl) Alloc() (uint64, error) {
 	defer alloc.mu.Unlock()
 
 	if alloc.base == alloc.end {
+		alloc.adjustStepLocked()
 		if err := alloc.rebaseLocked(true); err != nil {
 			return 0, err
 		}
@@ -111,6 +174,8 @@ func (alloc *allocatorImpl) SetBase(newBase uint64) error {
 
 	// set current end to new base, rebaseLocked will change it later.
 	alloc.end = newBase
+	// the samples before the new base are meaningless for the rate.
+	alloc.samples = nil
 
 	return alloc.rebaseLocked(false)
 }
@@ -169,6 +234,7 @@ func (alloc *allocatorImpl) rebaseLocked(checkCurrEnd bool) error {
 	alloc.metrics.idGauge.Set(float64(end))
 	alloc.end = end
 	alloc.base = end - alloc.step
+	alloc.recordSampleLocked()
 	// please do not reorder the first field, it's need when getting the new-end
 	// see: https://docs.pingcap.com/tidb/dev/pd-recover#get-allocated-id-from-pd-log
 	log.Info("idAllocator allocates a new id", zap.Uint64("new-end", end), zap.Uint64("new-base", alloc.base),
@@ -176,6 +242,80 @@ func (alloc *allocatorImpl) rebaseLocked(checkCurrEnd bool) error {
 	return nil
 }
 
+// adjustStepLocked enlarges the step if the window is used up too fast, e.g.
+// the regions are split at a high rate, to reduce the persistent writes, and
+// shrinks it back if the consumption slows down. Note that a larger step wastes
+// more IDs when the leader changes.
+func (alloc *allocatorImpl) adjustStepLocked() {
+	if alloc.maxStep == alloc.baseStep || alloc.lastRebase.IsZero() {
+		return
+	}
+	step := alloc.step
+	switch interval := time.Since(alloc.lastRebase); {
+	case interval < fastRebaseInterval && step < alloc.maxStep:
+		step = min(step*2, alloc.maxStep)
+	case interval > slowRebaseInterval && step > alloc.baseStep:
+		step = max(step/2, alloc.baseStep)
+	}
+	if step == alloc.step {
+		return
+	}
+	log.Info("idAllocator adjusts the step", zap.String("label", alloc.label),
+		zap.Uint64("old-step", alloc.step), zap.Uint64("new-step", step))
+	alloc.step = step
+	alloc.metrics.stepGauge.Set(float64(step))
+}
+
+func (alloc *allocatorImpl) recordSampleLocked() {
+	now := time.Now()
+	alloc.lastRebase = now
+	alloc.samples = append(alloc.samples, allocSample{time: now, base: alloc.base})
+	// keep the latest sample before the window as the start of the window.
+	for len(alloc.samples) > 1 && now.Sub(alloc.samples[1].time) >= rateWindow {
+		alloc.samples = alloc.samples[1:]
+	}
+	stats := alloc.getStatsLocked()
+	if stats.ConsumptionRate > 0 {
+		alloc.metrics.horizonGauge.Set(stats.ExhaustionHorizon)
+	}
+	if stats.Alarm {
+		log.Warn("idAllocator is running out of ids", zap.String("label", alloc.label),
+			zap.Uint64("base", alloc.base), zap.Float64("consumption-rate", stats.ConsumptionRate),
+			zap.Duration("exhaustion-horizon", time.Duration(stats.ExhaustionHorizon*float64(time.Second))))
+	}
+}
+
+// GetStats returns the consumption rate and the projected exhaustion horizon
+// of the allocator.
+func (alloc *allocatorImpl) GetStats() *Stats {
+	alloc.mu.RLock()
+	defer alloc.mu.RUnlock()
+	return alloc.getStatsLocked()
+}
+
+func (alloc *allocatorImpl) getStatsLocked() *Stats {
+	stats := &Stats{
+		Label:    alloc.label,
+		Base:     alloc.base,
+		End:      alloc.end,
+		Step:     alloc.step,
+		BaseStep: alloc.baseStep,
+		MaxStep:  alloc.maxStep,
+	}
+	if len(alloc.samples) == 0 {
+		return stats
+	}
+	start := alloc.samples[0]
+	elapsed := time.Since(start.time).Seconds()
+	if elapsed <= 0 || alloc.base <= start.base {
+		return stats
+	}
+	stats.ConsumptionRate = float64(alloc.base-start.base) / elapsed
+	stats.ExhaustionHorizon = float64(math.MaxUint64-alloc.base) / stats.ConsumptionRate
+	stats.Alarm = stats.ExhaustionHorizon < exhaustionAlarmHorizon.Seconds()
+	return stats
+}
+
 func (alloc *allocatorImpl) getAllocIDPath() string {
 	return path.Join(alloc.rootPath, alloc.allocPath)
 }
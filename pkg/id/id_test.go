@@ -75,3 +75,37 @@ func testAllocator(re *require.Assertions, allocator Allocator) {
 		re.Equal(i, id)
 	}
 }
+
+func TestDynamicStepAndStats(t *testing.T) {
+	re := require.New(t)
+	_, client, clean := etcdutil.NewTestEtcdCluster(t, 1)
+	defer clean()
+	_, err := client.Put(context.Background(), leaderPath, memberVal)
+	re.NoError(err)
+
+	allocator := NewAllocator(&AllocatorParams{
+		Client:    client,
+		RootPath:  rootPath,
+		AllocPath: allocPath,
+		Label:     label,
+		Member:    memberVal,
+		Step:      step,
+		MaxStep:   step * 4,
+	})
+	stats := allocator.GetStats()
+	re.Equal(step, stats.Step)
+	re.Equal(step*4, stats.MaxStep)
+	re.Zero(stats.ConsumptionRate)
+
+	// The windows are used up quickly, so the step is enlarged up to the max step.
+	for i := uint64(0); i < step*10; i++ {
+		_, err := allocator.Alloc()
+		re.NoError(err)
+	}
+	stats = allocator.GetStats()
+	re.Equal(step*4, stats.Step)
+	re.LessOrEqual(stats.Base, stats.End)
+	re.Positive(stats.ConsumptionRate)
+	re.Positive(stats.ExhaustionHorizon)
+	re.False(stats.Alarm)
+}
@@ -24,8 +24,26 @@ var (
 			Name:      "id",
 			Help:      "Record of id allocator.",
 		}, []string{"type"})
+
+	idAllocStepGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "id_alloc_step",
+			Help:      "The current step of id allocator.",
+		}, []string{"type"})
+
+	idExhaustionHorizonGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "id_exhaustion_horizon_seconds",
+			Help:      "The projected seconds until the ids of id allocator are used up.",
+		}, []string{"type"})
 )
 
 func init() {
 	prometheus.MustRegister(idGauge)
+	prometheus.MustRegister(idAllocStepGauge)
+	prometheus.MustRegister(idExhaustionHorizonGauge)
 }
@@ -187,6 +187,13 @@ func (manager *Manager) CreateKeyspace(request *CreateKeyspaceRequest) (*keyspac
 	if err := validateName(request.Name); err != nil {
 		return nil, err
 	}
+	if metadata, ok := request.Config[MetadataKey]; ok {
+		if err := manager.store.RunInTxn(manager.ctx, func(txn kv.Txn) error {
+			return manager.validateMetadata(txn, metadata)
+		}); err != nil {
+			return nil, err
+		}
+	}
 	// Allocate new keyspaceID.
 	newID, err := manager.allocID()
 	if err != nil {
@@ -493,6 +500,11 @@ func (manager *Manager) UpdateKeyspaceConfig(name string, mutations []*Mutation)
 		for _, mutation := range mutations {
 			switch mutation.Op {
 			case OpPut:
+				if mutation.Key == MetadataKey {
+					if err := manager.validateMetadata(txn, mutation.Value); err != nil {
+						return err
+					}
+				}
 				meta.Config[mutation.Key] = mutation.Value
 			case OpDel:
 				delete(meta.Config, mutation.Key)
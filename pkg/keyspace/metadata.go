@@ -0,0 +1,372 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/keyspacepb"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/slice"
+	"github.com/tikv/pd/pkg/storage/kv"
+	"github.com/tikv/pd/pkg/utils/etcdutil"
+)
+
+// MetadataKey is the key in keyspace config for the custom metadata, whose
+// value is a JSON object validated by the metadata schema.
+const MetadataKey = "metadata"
+
+// The types supported by the metadata schema.
+const (
+	schemaTypeObject  = "object"
+	schemaTypeArray   = "array"
+	schemaTypeString  = "string"
+	schemaTypeNumber  = "number"
+	schemaTypeInteger = "integer"
+	schemaTypeBoolean = "boolean"
+)
+
+var schemaTypes = []string{schemaTypeObject, schemaTypeArray, schemaTypeString, schemaTypeNumber, schemaTypeInteger, schemaTypeBoolean}
+
+// ErrInvalidKeyspaceMetadata indicates the keyspace metadata doesn't match the schema.
+var ErrInvalidKeyspaceMetadata = errors.New("invalid keyspace metadata")
+
+// MetadataSchema is the schema of the keyspace metadata, it's a subset of the
+// JSON schema. The keywords which are not listed are not supported.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type MetadataSchema struct {
+	Type string `json:"type,omitempty"`
+	// Properties, Required and AdditionalProperties are for the object.
+	Properties           map[string]*MetadataSchema `json:"properties,omitempty"`
+	Required             []string                   `json:"required,omitempty"`
+	AdditionalProperties *bool                      `json:"additionalProperties,omitempty"`
+	// Items is for the array.
+	Items *MetadataSchema `json:"items,omitempty"`
+	Enum  []any           `json:"enum,omitempty"`
+	// Minimum and Maximum are for the number and the integer.
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+	// MinLength, MaxLength and Pattern are for the string.
+	MinLength *int   `json:"minLength,omitempty"`
+	MaxLength *int   `json:"maxLength,omitempty"`
+	Pattern   string `json:"pattern,omitempty"`
+
+	// pattern is the compiled Pattern, it's set by Check.
+	pattern *regexp.Regexp
+}
+
+// Check checks whether the schema itself is valid, the metadata must be an object.
+// It also compiles the patterns, so it must be called before Validate.
+func (s *MetadataSchema) Check() error {
+	if s.Type != schemaTypeObject {
+		return errors.New("the type of keyspace metadata schema must be object")
+	}
+	return s.check("$")
+}
+
+func (s *MetadataSchema) check(path string) error {
+	if s.Type != "" && !slice.Contains(schemaTypes, s.Type) {
+		return errors.Errorf("%s: unsupported type %q", path, s.Type)
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return errors.Errorf("%s: invalid pattern %q", path, s.Pattern)
+		}
+		s.pattern = pattern
+	}
+	for _, name := range s.Required {
+		if _, ok := s.Properties[name]; !ok && s.AdditionalProperties != nil && !*s.AdditionalProperties {
+			return errors.Errorf("%s: required property %q is not allowed", path, name)
+		}
+	}
+	for name, property := range s.Properties {
+		if property == nil {
+			return errors.Errorf("%s.%s: missing schema", path, name)
+		}
+		if err := property.check(path + "." + name); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.check(path + "[]")
+	}
+	return nil
+}
+
+// Validate validates the decoded JSON value against the schema.
+func (s *MetadataSchema) Validate(value any) error {
+	if err := s.validate("$", value); err != nil {
+		return errors.Annotate(ErrInvalidKeyspaceMetadata, err.Error())
+	}
+	return nil
+}
+
+func (s *MetadataSchema) validate(path string, value any) error {
+	if len(s.Enum) > 0 {
+		matched := false
+		for _, e := range s.Enum {
+			if jsonEqual(e, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return errors.Errorf("%s must be one of %v", path, s.Enum)
+		}
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if err := s.checkType(path, schemaTypeObject); err != nil {
+			return err
+		}
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return errors.Errorf("%s.%s is required", path, name)
+			}
+		}
+		for name, field := range v {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return errors.Errorf("%s.%s is not allowed", path, name)
+				}
+				continue
+			}
+			if err := property.validate(path+"."+name, field); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if err := s.checkType(path, schemaTypeArray); err != nil {
+			return err
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		if err := s.checkType(path, schemaTypeString); err != nil {
+			return err
+		}
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			return errors.Errorf("%s is shorter than %d", path, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return errors.Errorf("%s is longer than %d", path, *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if s.pattern == nil {
+				return errors.Errorf("%s: the pattern %q is not compiled", path, s.Pattern)
+			}
+			if !s.pattern.MatchString(v) {
+				return errors.Errorf("%s doesn't match the pattern %q", path, s.Pattern)
+			}
+		}
+	case float64:
+		if s.Type == schemaTypeInteger {
+			if v != math.Trunc(v) {
+				return errors.Errorf("%s must be an integer", path)
+			}
+		} else if err := s.checkType(path, schemaTypeNumber); err != nil {
+			return err
+		}
+		if s.Minimum != nil && v < *s.Minimum {
+			return errors.Errorf("%s is less than %v", path, *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			return errors.Errorf("%s is greater than %v", path, *s.Maximum)
+		}
+	case bool:
+		return s.checkType(path, schemaTypeBoolean)
+	case nil:
+		if s.Type != "" {
+			return errors.Errorf("%s must be %s", path, s.Type)
+		}
+	}
+	return nil
+}
+
+func (s *MetadataSchema) checkType(path, typ string) error {
+	if s.Type != "" && s.Type != typ {
+		return errors.Errorf("%s must be %s", path, s.Type)
+	}
+	return nil
+}
+
+func jsonEqual(a, b any) bool {
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(x, y)
+}
+
+// parseMetadata decodes the metadata, which must be a JSON object.
+func parseMetadata(metadata string) (map[string]any, error) {
+	var value map[string]any
+	if err := json.Unmarshal([]byte(metadata), &value); err != nil || value == nil {
+		return nil, errors.Annotate(ErrInvalidKeyspaceMetadata, "metadata must be a JSON object")
+	}
+	return value, nil
+}
+
+// validateMetadata validates the metadata against the schema in storage, any
+// JSON object is valid if there is no schema.
+func (manager *Manager) validateMetadata(txn kv.Txn, metadata string) error {
+	value, err := parseMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	schema, err := manager.loadMetadataSchema(txn)
+	if err != nil || schema == nil {
+		return err
+	}
+	return schema.Validate(value)
+}
+
+func (manager *Manager) loadMetadataSchema(txn kv.Txn) (*MetadataSchema, error) {
+	value, err := manager.store.LoadKeyspaceMetadataSchema(txn)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	schema := &MetadataSchema{}
+	if err := json.Unmarshal([]byte(value), schema); err != nil {
+		return nil, errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByCause()
+	}
+	if err := schema.Check(); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// GetMetadataSchema returns the schema of the keyspace metadata, it returns
+// nil if there is no schema.
+func (manager *Manager) GetMetadataSchema() (*MetadataSchema, error) {
+	var schema *MetadataSchema
+	err := manager.store.RunInTxn(manager.ctx, func(txn kv.Txn) (err error) {
+		schema, err = manager.loadMetadataSchema(txn)
+		return err
+	})
+	return schema, err
+}
+
+// SetMetadataSchema sets the schema of the keyspace metadata, the nil schema
+// removes it. The schema only applies to the later updates of the metadata.
+func (manager *Manager) SetMetadataSchema(schema *MetadataSchema) error {
+	var value string
+	if schema != nil {
+		if err := schema.Check(); err != nil {
+			return err
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return errs.ErrJSONMarshal.Wrap(err).GenWithStackByCause()
+		}
+		value = string(data)
+	}
+	return manager.store.RunInTxn(manager.ctx, func(txn kv.Txn) error {
+		return manager.store.SaveKeyspaceMetadataSchema(txn, value)
+	})
+}
+
+// UpdateKeyspaceMetadata replaces the metadata of the keyspace, the empty
+// metadata removes it.
+func (manager *Manager) UpdateKeyspaceMetadata(name string, metadata json.RawMessage) (*keyspacepb.KeyspaceMeta, error) {
+	trimmed := bytes.TrimSpace(metadata)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return manager.UpdateKeyspaceConfig(name, []*Mutation{{Op: OpDel, Key: MetadataKey}})
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, trimmed); err != nil {
+		return nil, errors.Annotate(ErrInvalidKeyspaceMetadata, "metadata must be a JSON object")
+	}
+	return manager.UpdateKeyspaceConfig(name, []*Mutation{{Op: OpPut, Key: MetadataKey, Value: compacted.String()}})
+}
+
+// LoadRangeKeyspaceByMetadata loads no more than limit keyspaces starting at startID
+// whose metadata matches the filters. The keyspaces are scanned in batches, so the
+// filters are applied before the limit is counted. If limit is 0, it will load all
+// the matched keyspaces.
+func (manager *Manager) LoadRangeKeyspaceByMetadata(startID uint32, limit int, filters map[string]string) ([]*keyspacepb.KeyspaceMeta, error) {
+	if len(filters) == 0 {
+		return manager.LoadRangeKeyspace(startID, limit)
+	}
+	var matched []*keyspacepb.KeyspaceMeta
+	for startID <= spaceIDMax {
+		keyspaces, err := manager.LoadRangeKeyspace(startID, etcdutil.MaxEtcdTxnOps)
+		if err != nil {
+			return nil, err
+		}
+		for _, meta := range keyspaces {
+			if !MatchMetadata(meta, filters) {
+				continue
+			}
+			matched = append(matched, meta)
+			if limit > 0 && len(matched) == limit {
+				return matched, nil
+			}
+		}
+		if len(keyspaces) < etcdutil.MaxEtcdTxnOps {
+			break
+		}
+		startID = keyspaces[len(keyspaces)-1].GetId() + 1
+	}
+	return matched, nil
+}
+
+// MatchMetadata checks whether the top-level fields of the keyspace metadata
+// equal to the filters. The string fields are compared with the values
+// directly, and the other fields are compared in their JSON encoding.
+func MatchMetadata(meta *keyspacepb.KeyspaceMeta, filters map[string]string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	value, err := parseMetadata(meta.GetConfig()[MetadataKey])
+	if err != nil {
+		return false
+	}
+	for name, expected := range filters {
+		field, ok := value[name]
+		if !ok {
+			return false
+		}
+		if s, ok := field.(string); ok {
+			if s != expected {
+				return false
+			}
+			continue
+		}
+		encoded, err := json.Marshal(field)
+		if err != nil || string(encoded) != expected {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/keyspacepb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/storage/kv"
+	"github.com/tikv/pd/pkg/utils/etcdutil"
+)
+
+const testMetadataSchema = `{
+	"type": "object",
+	"required": ["tier"],
+	"additionalProperties": false,
+	"properties": {
+		"tier": {"type": "string", "enum": ["gold", "silver"]},
+		"owner": {"type": "string", "pattern": "^[a-z]+$", "maxLength": 8},
+		"replicas": {"type": "integer", "minimum": 1, "maximum": 5},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func TestMetadataSchema(t *testing.T) {
+	re := require.New(t)
+	schema := &MetadataSchema{}
+	re.NoError(json.Unmarshal([]byte(testMetadataSchema), schema))
+	re.NoError(schema.Check())
+	re.Error((&MetadataSchema{Type: schemaTypeArray}).Check())
+	re.Error((&MetadataSchema{Type: schemaTypeObject, Properties: map[string]*MetadataSchema{"a": {Type: "date"}}}).Check())
+	re.Error((&MetadataSchema{Type: schemaTypeObject, Properties: map[string]*MetadataSchema{"a": {Pattern: "("}}}).Check())
+
+	testCases := []struct {
+		metadata string
+		valid    bool
+	}{
+		{`{"tier": "gold"}`, true},
+		{`{"tier": "gold", "owner": "pd", "replicas": 3, "tags": ["a", "b"]}`, true},
+		{`{}`, false},
+		{`{"tier": "bronze"}`, false},
+		{`{"tier": "gold", "owner": "PD"}`, false},
+		{`{"tier": "gold", "owner": "abcdefghi"}`, false},
+		{`{"tier": "gold", "replicas": 1.5}`, false},
+		{`{"tier": "gold", "replicas": 6}`, false},
+		{`{"tier": "gold", "tags": [1]}`, false},
+		{`{"tier": "gold", "unknown": true}`, false},
+	}
+	for _, testCase := range testCases {
+		value, err := parseMetadata(testCase.metadata)
+		re.NoError(err)
+		err = schema.Validate(value)
+		if testCase.valid {
+			re.NoError(err, testCase.metadata)
+		} else {
+			re.Error(err, testCase.metadata)
+			re.Equal(ErrInvalidKeyspaceMetadata, errors.Cause(err))
+		}
+	}
+	_, err := parseMetadata(`[1, 2]`)
+	re.Error(err)
+
+	// the unchecked pattern is reported instead of being compiled on the fly.
+	unchecked := &MetadataSchema{Type: schemaTypeObject, Properties: map[string]*MetadataSchema{"a": {Pattern: "("}}}
+	re.Error(unchecked.Validate(map[string]any{"a": "b"}))
+}
+
+func TestMatchMetadata(t *testing.T) {
+	re := require.New(t)
+	meta := &keyspacepb.KeyspaceMeta{Config: map[string]string{
+		MetadataKey: `{"tier":"gold","replicas":3,"shared":false}`,
+	}}
+	re.True(MatchMetadata(meta, nil))
+	re.True(MatchMetadata(meta, map[string]string{"tier": "gold", "replicas": "3"}))
+	re.True(MatchMetadata(meta, map[string]string{"shared": "false"}))
+	re.False(MatchMetadata(meta, map[string]string{"tier": "silver"}))
+	re.False(MatchMetadata(meta, map[string]string{"owner": "pd"}))
+	re.False(MatchMetadata(&keyspacepb.KeyspaceMeta{}, map[string]string{"tier": "gold"}))
+}
+
+func (suite *keyspaceTestSuite) TestKeyspaceMetadata() {
+	re := suite.Require()
+	manager := suite.manager
+	schema := &MetadataSchema{}
+	re.NoError(json.Unmarshal([]byte(testMetadataSchema), schema))
+	re.NoError(manager.SetMetadataSchema(schema))
+	loaded, err := manager.GetMetadataSchema()
+	re.NoError(err)
+	re.Equal(schema, loaded)
+
+	// the metadata is validated on creating.
+	request := &CreateKeyspaceRequest{
+		Name:       "metadata_keyspace",
+		Config:     map[string]string{MetadataKey: `{"tier":"bronze"}`},
+		CreateTime: time.Now().Unix(),
+		IsPreAlloc: true,
+	}
+	_, err = manager.CreateKeyspace(request)
+	re.Equal(ErrInvalidKeyspaceMetadata, errors.Cause(err))
+	request.Config[MetadataKey] = `{"tier":"gold"}`
+	_, err = manager.CreateKeyspace(request)
+	re.NoError(err)
+
+	// the metadata is validated on updating.
+	_, err = manager.UpdateKeyspaceMetadata(request.Name, json.RawMessage(`{"tier": "gold", "replicas": 9}`))
+	re.Equal(ErrInvalidKeyspaceMetadata, errors.Cause(err))
+	meta, err := manager.UpdateKeyspaceMetadata(request.Name, json.RawMessage(`{"tier": "silver", "replicas": 3}`))
+	re.NoError(err)
+	re.Equal(`{"tier":"silver","replicas":3}`, meta.GetConfig()[MetadataKey])
+	meta, err = manager.UpdateKeyspaceMetadata(request.Name, json.RawMessage(`null`))
+	re.NoError(err)
+	re.NotContains(meta.GetConfig(), MetadataKey)
+
+	// any object is allowed without the schema.
+	re.NoError(manager.SetMetadataSchema(nil))
+	loaded, err = manager.GetMetadataSchema()
+	re.NoError(err)
+	re.Nil(loaded)
+	_, err = manager.UpdateKeyspaceMetadata(request.Name, json.RawMessage(`{"anything": 1}`))
+	re.NoError(err)
+
+	// the invalid pattern in storage is reported on loading.
+	re.NoError(manager.store.RunInTxn(manager.ctx, func(txn kv.Txn) error {
+		return manager.store.SaveKeyspaceMetadataSchema(txn, `{"type":"object","properties":{"a":{"pattern":"("}}}`)
+	}))
+	_, err = manager.GetMetadataSchema()
+	re.Error(err)
+	_, err = manager.UpdateKeyspaceMetadata(request.Name, json.RawMessage(`{"a": "b"}`))
+	re.Error(err)
+}
+
+func (suite *keyspaceTestSuite) TestLoadRangeKeyspaceByMetadata() {
+	re := suite.Require()
+	manager := suite.manager
+	requests := makeCreateKeyspaceRequests(etcdutil.MaxEtcdTxnOps + 10)
+	for i, request := range requests {
+		// only one in every three keyspaces is gold, and the last ten are all gold.
+		if i%3 == 0 || i >= etcdutil.MaxEtcdTxnOps {
+			request.Config[MetadataKey] = `{"tier":"gold"}`
+		}
+		_, err := manager.CreateKeyspace(request)
+		re.NoError(err)
+	}
+	filters := map[string]string{"tier": "gold"}
+	expected := make([]string, 0, len(requests))
+	for _, request := range requests {
+		if request.Config[MetadataKey] != "" {
+			expected = append(expected, request.Name)
+		}
+	}
+	names := func(keyspaces []*keyspacepb.KeyspaceMeta) []string {
+		result := make([]string, 0, len(keyspaces))
+		for _, meta := range keyspaces {
+			re.True(MatchMetadata(meta, filters))
+			result = append(result, meta.GetName())
+		}
+		return result
+	}
+
+	// the limit counts the matched keyspaces rather than the scanned ones.
+	keyspaces, err := manager.LoadRangeKeyspaceByMetadata(0, 5, filters)
+	re.NoError(err)
+	re.Equal(expected[:5], names(keyspaces))
+	keyspaces, err = manager.LoadRangeKeyspaceByMetadata(keyspaces[4].GetId()+1, 5, filters)
+	re.NoError(err)
+	re.Equal(expected[5:10], names(keyspaces))
+	// the matched keyspaces are collected across the batches.
+	keyspaces, err = manager.LoadRangeKeyspaceByMetadata(0, 0, filters)
+	re.NoError(err)
+	re.Equal(expected, names(keyspaces))
+	keyspaces, err = manager.LoadRangeKeyspaceByMetadata(0, 0, map[string]string{"tier": "silver"})
+	re.NoError(err)
+	re.Empty(keyspaces)
+}
@@ -16,7 +16,7 @@ package keyspace
 
 import (
 	"context"
-	"encoding/json"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -38,7 +38,6 @@ import (
 	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/tikv/pd/pkg/utils/typeutil"
 	"go.etcd.io/etcd/clientv3"
-	"go.etcd.io/etcd/mvcc/mvccpb"
 	"go.uber.org/zap"
 )
 
@@ -47,6 +46,7 @@ const (
 	allocNodesToKeyspaceGroupsInterval = 1 * time.Second
 	allocNodesTimeout                  = 1 * time.Second
 	allocNodesInterval                 = 10 * time.Millisecond
+	tsoNodesResyncInterval             = time.Minute
 )
 
 const (
@@ -73,11 +73,28 @@ type GroupManager struct {
 	// nodeBalancer is the balancer for tso nodes.
 	// TODO: add user kind with different balancer when we ensure where the correspondence between tso node and user kind will be found
 	nodesBalancer balancer.Balancer[string]
-	// serviceRegistryMap stores the mapping from the service registry key to the service address.
-	// Note: it is only used in tsoNodesWatcher.
-	serviceRegistryMap map[string]string
-	// tsoNodesWatcher is the watcher for the registered tso servers.
-	tsoNodesWatcher *etcdutil.LoopWatcher
+	// nodeLabels stores the labels of the tso nodes by the service address.
+	nodeLabels struct {
+		syncutil.RWMutex
+		labels map[string]map[string]string
+	}
+	// tsoNodesInformer is the informer for the registered tso servers.
+	tsoNodesInformer *etcdutil.Informer[*discovery.ServiceRegistryEntry]
+	// tsoQPS estimates the TSO QPS of the keyspaces for the auto split and the primary balance.
+	tsoQPS tsoQPSCollector
+	// primaryBalance is the state of balancing the primaries across the tso nodes.
+	primaryBalance struct {
+		syncutil.Mutex
+		lastMoveTime time.Time
+		// marks are the members whose priorities are raised by the balancer, by the
+		// keyspace group ID.
+		marks map[uint32]primaryBalanceMark
+	}
+	// primaryColocation is the state of co-locating the primaries with the data.
+	primaryColocation struct {
+		syncutil.Mutex
+		states map[uint32]*colocationState
+	}
 }
 
 // NewKeyspaceGroupManager creates a Manager of keyspace group related data.
@@ -93,21 +110,21 @@ func NewKeyspaceGroupManager(
 		groups[endpoint.UserKind(i)] = newIndexedHeap(int(utils.MaxKeyspaceGroupCountInUse))
 	}
 	m := &GroupManager{
-		ctx:                ctx,
-		cancel:             cancel,
-		store:              store,
-		groups:             groups,
-		client:             client,
-		clusterID:          clusterID,
-		nodesBalancer:      balancer.GenByPolicy[string](defaultBalancerPolicy),
-		serviceRegistryMap: make(map[string]string),
+		ctx:           ctx,
+		cancel:        cancel,
+		store:         store,
+		groups:        groups,
+		client:        client,
+		clusterID:     clusterID,
+		nodesBalancer: balancer.GenByPolicy[string](defaultBalancerPolicy),
 	}
+	m.nodeLabels.labels = make(map[string]map[string]string)
 
 	// If the etcd client is not nil, start the watch loop for the registered tso servers.
 	// The PD(TSO) Client relies on this info to discover tso servers.
 	if m.client != nil {
-		m.initTSONodesWatcher(m.client, m.clusterID)
-		m.tsoNodesWatcher.StartWatchLoop()
+		m.initTSONodesInformer(m.client, m.clusterID)
+		m.tsoNodesInformer.StartWatchLoop()
 	}
 	return m
 }
@@ -191,6 +208,11 @@ func (m *GroupManager) allocNodesToAllKeyspaceGroups(ctx context.Context) {
 		if len(groups) == 0 {
 			continue
 		}
+		profiles, err := m.loadKeyspaceGroupProfiles()
+		if err != nil {
+			log.Error("failed to load keyspace group profiles", zap.Error(err))
+			continue
+		}
 		for _, group := range groups {
 			existMembers := make(map[string]struct{})
 			for _, member := range group.Members {
@@ -202,8 +224,9 @@ func (m *GroupManager) allocNodesToAllKeyspaceGroups(ctx context.Context) {
 			if numExistMembers != 0 && numExistMembers == len(group.Members) && numExistMembers == m.GetNodesCount() {
 				continue
 			}
-			if numExistMembers < utils.DefaultKeyspaceGroupReplicaCount {
-				nodes, err := m.AllocNodesForKeyspaceGroup(group.ID, existMembers, utils.DefaultKeyspaceGroupReplicaCount)
+			replicaCount := profiles[endpoint.StringUserKind(group.UserKind)].ReplicaCount
+			if numExistMembers < replicaCount {
+				nodes, err := m.AllocNodesForKeyspaceGroup(group.ID, existMembers, replicaCount)
 				if err != nil {
 					log.Error("failed to alloc nodes for keyspace group", zap.Uint32("keyspace-group-id", group.ID), zap.Error(err))
 					continue
@@ -214,41 +237,40 @@ func (m *GroupManager) allocNodesToAllKeyspaceGroups(ctx context.Context) {
 	}
 }
 
-func (m *GroupManager) initTSONodesWatcher(client *clientv3.Client, clusterID uint64) {
+func (m *GroupManager) initTSONodesInformer(client *clientv3.Client, clusterID uint64) {
 	tsoServiceKey := discovery.TSOPath(clusterID)
 
-	putFn := func(kv *mvccpb.KeyValue) error {
-		s := &discovery.ServiceRegistryEntry{}
-		if err := json.Unmarshal(kv.Value, s); err != nil {
-			log.Warn("failed to unmarshal service registry entry",
-				zap.String("event-kv-key", string(kv.Key)), zap.Error(err))
-			return err
-		}
+	putFn := func(_ string, s *discovery.ServiceRegistryEntry) error {
 		m.nodesBalancer.Put(s.ServiceAddr)
-		m.serviceRegistryMap[string(kv.Key)] = s.ServiceAddr
+		m.setNodeLabels(s.ServiceAddr, s.Labels)
 		return nil
 	}
-	deleteFn := func(kv *mvccpb.KeyValue) error {
-		key := string(kv.Key)
-		if serviceAddr, ok := m.serviceRegistryMap[key]; ok {
-			delete(m.serviceRegistryMap, key)
-			m.nodesBalancer.Delete(serviceAddr)
-			return nil
-		}
-		return errors.Errorf("failed to find the service address for key %s", key)
+	deleteFn := func(_ string, s *discovery.ServiceRegistryEntry) error {
+		m.nodesBalancer.Delete(s.ServiceAddr)
+		m.setNodeLabels(s.ServiceAddr, nil)
+		return nil
 	}
 
-	m.tsoNodesWatcher = etcdutil.NewLoopWatcher(
+	m.tsoNodesInformer = etcdutil.NewInformer(
 		m.ctx,
 		&m.wg,
 		client,
 		"tso-nodes-watcher",
 		tsoServiceKey,
-		func([]*clientv3.Event) error { return nil },
-		putFn,
-		deleteFn,
-		func([]*clientv3.Event) error { return nil },
-		true, /* withPrefix */
+		discovery.DecodeServiceRegistryEntry,
+		etcdutil.InformerHandler[*discovery.ServiceRegistryEntry]{
+			OnAdd: putFn,
+			OnUpdate: func(key string, old, s *discovery.ServiceRegistryEntry) error {
+				if old.ServiceAddr != s.ServiceAddr {
+					if err := deleteFn(key, old); err != nil {
+						return err
+					}
+				}
+				return putFn(key, s)
+			},
+			OnDelete: deleteFn,
+		},
+		tsoNodesResyncInterval,
 	)
 }
 
@@ -256,7 +278,12 @@ func (m *GroupManager) initTSONodesWatcher(client *clientv3.Client, clusterID ui
 func (m *GroupManager) CreateKeyspaceGroups(keyspaceGroups []*endpoint.KeyspaceGroup) error {
 	m.Lock()
 	defer m.Unlock()
-	if err := m.saveKeyspaceGroups(keyspaceGroups, false); err != nil {
+	if err := m.store.RunInTxn(m.ctx, func(txn kv.Txn) error {
+		if err := m.inheritKeyspaceGroupProfiles(txn, keyspaceGroups); err != nil {
+			return err
+		}
+		return m.saveKeyspaceGroupsInTxn(txn, keyspaceGroups, false)
+	}); err != nil {
 		return err
 	}
 
@@ -338,34 +365,183 @@ func (m *GroupManager) DeleteKeyspaceGroupByID(id uint32) (*endpoint.KeyspaceGro
 // If any keyspace group already exists and `overwrite` is false, it will return ErrKeyspaceGroupExists.
 func (m *GroupManager) saveKeyspaceGroups(keyspaceGroups []*endpoint.KeyspaceGroup, overwrite bool) error {
 	return m.store.RunInTxn(m.ctx, func(txn kv.Txn) error {
-		for _, keyspaceGroup := range keyspaceGroups {
-			// Check if keyspace group has already existed.
-			oldKG, err := m.store.LoadKeyspaceGroup(txn, keyspaceGroup.ID)
-			if err != nil {
-				return err
-			}
-			if oldKG != nil && !overwrite {
-				return ErrKeyspaceGroupExists
-			}
-			if oldKG.IsSplitting() && overwrite {
-				return ErrKeyspaceGroupInSplit(keyspaceGroup.ID)
+		return m.saveKeyspaceGroupsInTxn(txn, keyspaceGroups, overwrite)
+	})
+}
+
+func (m *GroupManager) saveKeyspaceGroupsInTxn(txn kv.Txn, keyspaceGroups []*endpoint.KeyspaceGroup, overwrite bool) error {
+	for _, keyspaceGroup := range keyspaceGroups {
+		// Check if keyspace group has already existed.
+		oldKG, err := m.store.LoadKeyspaceGroup(txn, keyspaceGroup.ID)
+		if err != nil {
+			return err
+		}
+		if oldKG != nil && !overwrite {
+			return ErrKeyspaceGroupExists
+		}
+		if oldKG.IsSplitting() && overwrite {
+			return ErrKeyspaceGroupInSplit(keyspaceGroup.ID)
+		}
+		if oldKG.IsMerging() && overwrite {
+			return ErrKeyspaceGroupInMerging(keyspaceGroup.ID)
+		}
+		newKG := &endpoint.KeyspaceGroup{
+			ID:        keyspaceGroup.ID,
+			UserKind:  keyspaceGroup.UserKind,
+			Members:   keyspaceGroup.Members,
+			Keyspaces: keyspaceGroup.Keyspaces,
+			TSOConfig: keyspaceGroup.TSOConfig,
+		}
+		err = m.store.SaveKeyspaceGroup(txn, newKG)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inheritKeyspaceGroupProfiles applies the profiles of the user kinds to the new keyspace groups.
+// The members whose priority is unset take the priority of the profile, and the TSO settings
+// which are not specified by the keyspace group are inherited from the profile.
+func (m *GroupManager) inheritKeyspaceGroupProfiles(txn kv.Txn, keyspaceGroups []*endpoint.KeyspaceGroup) error {
+	for _, kg := range keyspaceGroups {
+		profile, err := m.loadKeyspaceGroupProfile(txn, kg.UserKind)
+		if err != nil {
+			return err
+		}
+		for i := range kg.Members {
+			if kg.Members[i].Priority == utils.UnsetKeyspaceGroupReplicaPriority {
+				kg.Members[i].Priority = profile.Priority
 			}
-			if oldKG.IsMerging() && overwrite {
-				return ErrKeyspaceGroupInMerging(keyspaceGroup.ID)
+		}
+		for k, v := range profile.TSOConfig {
+			if _, ok := kg.TSOConfig[k]; ok {
+				continue
 			}
-			newKG := &endpoint.KeyspaceGroup{
-				ID:        keyspaceGroup.ID,
-				UserKind:  keyspaceGroup.UserKind,
-				Members:   keyspaceGroup.Members,
-				Keyspaces: keyspaceGroup.Keyspaces,
+			if kg.TSOConfig == nil {
+				kg.TSOConfig = make(map[string]string, len(profile.TSOConfig))
 			}
-			err = m.store.SaveKeyspaceGroup(txn, newKG)
+			kg.TSOConfig[k] = v
+		}
+	}
+	return nil
+}
+
+// newDefaultKeyspaceGroupProfile returns the profile used when the user kind has no profile.
+func newDefaultKeyspaceGroupProfile(userKind string) *endpoint.KeyspaceGroupProfile {
+	return &endpoint.KeyspaceGroupProfile{
+		UserKind:     userKind,
+		ReplicaCount: utils.DefaultKeyspaceGroupReplicaCount,
+		Priority:     utils.DefaultKeyspaceGroupReplicaPriority,
+	}
+}
+
+func (m *GroupManager) loadKeyspaceGroupProfile(txn kv.Txn, userKind string) (*endpoint.KeyspaceGroupProfile, error) {
+	userKind = endpoint.StringUserKind(userKind).String()
+	profile, err := m.store.LoadKeyspaceGroupProfile(txn, userKind)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return newDefaultKeyspaceGroupProfile(userKind), nil
+	}
+	return profile, nil
+}
+
+// loadKeyspaceGroupProfiles loads the profiles of all user kinds.
+func (m *GroupManager) loadKeyspaceGroupProfiles() (map[endpoint.UserKind]*endpoint.KeyspaceGroupProfile, error) {
+	profiles := make(map[endpoint.UserKind]*endpoint.KeyspaceGroupProfile, endpoint.UserKindCount)
+	err := m.store.RunInTxn(m.ctx, func(txn kv.Txn) error {
+		for kind := endpoint.Basic; kind < endpoint.UserKindCount; kind++ {
+			profile, err := m.loadKeyspaceGroupProfile(txn, kind.String())
 			if err != nil {
 				return err
 			}
+			profiles[kind] = profile
 		}
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// GetKeyspaceGroupProfiles returns the keyspace group profiles of all user kinds.
+func (m *GroupManager) GetKeyspaceGroupProfiles() ([]*endpoint.KeyspaceGroupProfile, error) {
+	m.RLock()
+	defer m.RUnlock()
+	profiles, err := m.loadKeyspaceGroupProfiles()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*endpoint.KeyspaceGroupProfile, 0, len(profiles))
+	for kind := endpoint.Basic; kind < endpoint.UserKindCount; kind++ {
+		result = append(result, profiles[kind])
+	}
+	return result, nil
+}
+
+// GetKeyspaceGroupProfile returns the keyspace group profile of the user kind.
+func (m *GroupManager) GetKeyspaceGroupProfile(userKind endpoint.UserKind) (*endpoint.KeyspaceGroupProfile, error) {
+	m.RLock()
+	defer m.RUnlock()
+	var profile *endpoint.KeyspaceGroupProfile
+	err := m.store.RunInTxn(m.ctx, func(txn kv.Txn) (err error) {
+		profile, err = m.loadKeyspaceGroupProfile(txn, userKind.String())
+		return err
+	})
+	return profile, err
+}
+
+// SetKeyspaceGroupProfile sets the keyspace group profile of a user kind. It only affects
+// the keyspace groups created later and the members allocated later.
+func (m *GroupManager) SetKeyspaceGroupProfile(profile *endpoint.KeyspaceGroupProfile) error {
+	if !isKeyspaceGroupProfileValid(profile) {
+		return ErrInvalidKeyspaceGroupProfile
+	}
+	m.Lock()
+	defer m.Unlock()
+	if err := m.store.RunInTxn(m.ctx, func(txn kv.Txn) error {
+		return m.store.SaveKeyspaceGroupProfile(txn, profile)
+	}); err != nil {
+		return err
+	}
+	log.Info("set keyspace group profile", zap.Reflect("profile", profile))
+	return nil
+}
+
+// UpdateKeyspaceGroupProfile updates the keyspace group profile of a user kind with the given
+// function. The profile is loaded, updated and saved in one transaction, so the concurrent
+// updates will not overwrite each other.
+func (m *GroupManager) UpdateKeyspaceGroupProfile(
+	userKind endpoint.UserKind, update func(profile *endpoint.KeyspaceGroupProfile),
+) (*endpoint.KeyspaceGroupProfile, error) {
+	m.Lock()
+	defer m.Unlock()
+	var profile *endpoint.KeyspaceGroupProfile
+	if err := m.store.RunInTxn(m.ctx, func(txn kv.Txn) (err error) {
+		profile, err = m.loadKeyspaceGroupProfile(txn, userKind.String())
+		if err != nil {
+			return err
+		}
+		update(profile)
+		if !isKeyspaceGroupProfileValid(profile) {
+			return ErrInvalidKeyspaceGroupProfile
+		}
+		return m.store.SaveKeyspaceGroupProfile(txn, profile)
+	}); err != nil {
+		return nil, err
+	}
+	log.Info("update keyspace group profile", zap.Reflect("profile", profile))
+	return profile, nil
+}
+
+func isKeyspaceGroupProfileValid(profile *endpoint.KeyspaceGroupProfile) bool {
+	return endpoint.IsUserKindValid(profile.UserKind) &&
+		profile.ReplicaCount >= utils.DefaultKeyspaceGroupReplicaCount &&
+		profile.Priority != utils.UnsetKeyspaceGroupReplicaPriority &&
+		endpoint.ValidateTSOConfig(profile.TSOConfig) == nil
 }
 
 // GetKeyspaceConfigByKind returns the keyspace config for the given user kind.
@@ -538,6 +714,25 @@ func (m *GroupManager) UpdateKeyspaceGroup(oldGroupID, newGroupID string, oldUse
 func (m *GroupManager) SplitKeyspaceGroupByID(
 	splitSourceID, splitTargetID uint32,
 	keyspaces []uint32, keyspaceIDRange ...uint32,
+) error {
+	return m.splitKeyspaceGroup(splitSourceID, splitTargetID, "", keyspaces, keyspaceIDRange...)
+}
+
+// SplitKeyspaceGroupWithPrimary splits the keyspace group like SplitKeyspaceGroupByID,
+// and hands off the primary of the split target to the given tso node by giving it the
+// highest priority in the split target. The tso nodes only transfer the primary after
+// the split is finished, i.e. after the TSO of the split target has been fenced above
+// the one of the split source, so there is no timestamp gap or rollback.
+func (m *GroupManager) SplitKeyspaceGroupWithPrimary(
+	splitSourceID, splitTargetID uint32, primary string,
+	keyspaces []uint32, keyspaceIDRange ...uint32,
+) error {
+	return m.splitKeyspaceGroup(splitSourceID, splitTargetID, primary, keyspaces, keyspaceIDRange...)
+}
+
+func (m *GroupManager) splitKeyspaceGroup(
+	splitSourceID, splitTargetID uint32, primary string,
+	keyspaces []uint32, keyspaceIDRange ...uint32,
 ) error {
 	var splitSourceKg, splitTargetKg *endpoint.KeyspaceGroup
 	m.Lock()
@@ -581,6 +776,13 @@ func (m *GroupManager) SplitKeyspaceGroupByID(
 		if splitTargetKg != nil {
 			return ErrKeyspaceGroupExists
 		}
+		splitTargetMembers := splitSourceKg.Members
+		if len(primary) > 0 {
+			splitTargetMembers, err = buildMembersWithPrimary(splitSourceKg.Members, primary)
+			if err != nil {
+				return err
+			}
+		}
 		// Update the old keyspace group.
 		splitSourceKg.Keyspaces = splitSourceKeyspaces
 		splitSourceKg.SplitState = &endpoint.SplitState{
@@ -593,8 +795,9 @@ func (m *GroupManager) SplitKeyspaceGroupByID(
 			ID: splitTargetID,
 			// Keep the same user kind and members as the old keyspace group.
 			UserKind:  splitSourceKg.UserKind,
-			Members:   splitSourceKg.Members,
+			Members:   splitTargetMembers,
 			Keyspaces: splitTargetKeyspaces,
+			TSOConfig: splitSourceKg.TSOConfig,
 			SplitState: &endpoint.SplitState{
 				SplitSource: splitSourceKg.ID,
 			},
@@ -610,6 +813,31 @@ func (m *GroupManager) SplitKeyspaceGroupByID(
 	return nil
 }
 
+// buildMembersWithPrimary copies the members and makes the given node have the
+// highest priority among them.
+func buildMembersWithPrimary(members []endpoint.KeyspaceGroupMember, primary string) ([]endpoint.KeyspaceGroupMember, error) {
+	maxPriority := math.MinInt32
+	inKeyspaceGroup := false
+	for _, member := range members {
+		if member.IsAddressEquivalent(primary) {
+			inKeyspaceGroup = true
+			continue
+		}
+		maxPriority = max(maxPriority, member.Priority)
+	}
+	if !inKeyspaceGroup {
+		return nil, ErrNodeNotInKeyspaceGroup
+	}
+	newMembers := make([]endpoint.KeyspaceGroupMember, 0, len(members))
+	for _, member := range members {
+		if member.IsAddressEquivalent(primary) && member.Priority <= maxPriority {
+			member.Priority = maxPriority + 1
+		}
+		newMembers = append(newMembers, member)
+	}
+	return newMembers, nil
+}
+
 func buildSplitKeyspaces(
 	// `old` is the original keyspace list which will be split out,
 	// `new` is the keyspace list which will be split from the old keyspace list.
@@ -770,11 +998,15 @@ func (m *GroupManager) AllocNodesForKeyspaceGroup(id uint32, existMembers map[st
 		if kg.IsMerging() {
 			return ErrKeyspaceGroupInMerging(id)
 		}
+		profile, err := m.loadKeyspaceGroupProfile(txn, kg.UserKind)
+		if err != nil {
+			return err
+		}
 
 		for addr := range existMembers {
 			nodes = append(nodes, endpoint.KeyspaceGroupMember{
 				Address:  addr,
-				Priority: utils.DefaultKeyspaceGroupReplicaPriority,
+				Priority: profile.Priority,
 			})
 		}
 
@@ -800,7 +1032,7 @@ func (m *GroupManager) AllocNodesForKeyspaceGroup(id uint32, existMembers map[st
 			existMembers[addr] = struct{}{}
 			nodes = append(nodes, endpoint.KeyspaceGroupMember{
 				Address:  addr,
-				Priority: utils.DefaultKeyspaceGroupReplicaPriority,
+				Priority: profile.Priority,
 			})
 		}
 		kg.Members = nodes
@@ -836,11 +1068,15 @@ func (m *GroupManager) SetNodesForKeyspaceGroup(id uint32, nodes []string) error
 		if kg.IsMerging() {
 			return ErrKeyspaceGroupInMerging(id)
 		}
+		profile, err := m.loadKeyspaceGroupProfile(txn, kg.UserKind)
+		if err != nil {
+			return err
+		}
 		members := make([]endpoint.KeyspaceGroupMember, 0, len(nodes))
 		for _, node := range nodes {
 			members = append(members, endpoint.KeyspaceGroupMember{
 				Address:  node,
-				Priority: utils.DefaultKeyspaceGroupReplicaPriority,
+				Priority: profile.Priority,
 			})
 		}
 		kg.Members = members
@@ -895,6 +1131,54 @@ func (m *GroupManager) SetPriorityForKeyspaceGroup(id uint32, node string, prior
 	return nil
 }
 
+// UpdateTSOConfigForKeyspaceGroup updates the TSO settings of the keyspace group.
+// A TSO setting is removed from the keyspace group if its value is nil.
+func (m *GroupManager) UpdateTSOConfigForKeyspaceGroup(id uint32, tsoConfig map[string]*string) (*endpoint.KeyspaceGroup, error) {
+	m.Lock()
+	defer m.Unlock()
+	var kg *endpoint.KeyspaceGroup
+	err := m.store.RunInTxn(m.ctx, func(txn kv.Txn) error {
+		var err error
+		kg, err = m.store.LoadKeyspaceGroup(txn, id)
+		if err != nil {
+			return err
+		}
+		if kg == nil {
+			return ErrKeyspaceGroupNotExists(id)
+		}
+		if kg.IsSplitting() {
+			return ErrKeyspaceGroupInSplit(id)
+		}
+		if kg.IsMerging() {
+			return ErrKeyspaceGroupInMerging(id)
+		}
+		newTSOConfig := make(map[string]string, len(kg.TSOConfig)+len(tsoConfig))
+		for k, v := range kg.TSOConfig {
+			newTSOConfig[k] = v
+		}
+		for k, v := range tsoConfig {
+			if v == nil {
+				delete(newTSOConfig, k)
+				continue
+			}
+			newTSOConfig[k] = *v
+		}
+		if err := endpoint.ValidateTSOConfig(newTSOConfig); err != nil {
+			return err
+		}
+		if len(newTSOConfig) == 0 {
+			newTSOConfig = nil
+		}
+		kg.TSOConfig = newTSOConfig
+		return m.store.SaveKeyspaceGroup(txn, kg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.groups[endpoint.StringUserKind(kg.UserKind)].Put(kg)
+	return kg, nil
+}
+
 // IsExistNode checks if the node exists.
 func (m *GroupManager) IsExistNode(addr string) (bool, string) {
 	nodes := m.nodesBalancer.GetAll()
@@ -1149,7 +1433,11 @@ func (m *GroupManager) GetKeyspaceGroupPrimaryByID(id uint32) (string, error) {
 	if kg == nil {
 		return "", ErrKeyspaceGroupNotExists(id)
 	}
+	return m.loadKeyspaceGroupPrimary(id)
+}
 
+// loadKeyspaceGroupPrimary loads the address of the primary of the keyspace group from etcd.
+func (m *GroupManager) loadKeyspaceGroupPrimary(id uint32) (string, error) {
 	rootPath := endpoint.TSOSvcRootPath(m.clusterID)
 	primaryPath := endpoint.KeyspaceGroupPrimaryPath(rootPath, id)
 	leader := &tsopb.Participant{}
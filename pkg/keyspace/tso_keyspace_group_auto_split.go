@@ -0,0 +1,251 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/mcs/utils"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// tsoKeyspaceRequestsPath is the API of the tso nodes which returns the number
+	// of the TSO requests handled for each keyspace.
+	tsoKeyspaceRequestsPath    = "/tso/api/v1/keyspace-groups/requests"
+	tsoKeyspaceRequestsTimeout = 3 * time.Second
+	// tsoQPSMinCollectInterval is the min interval between two collections, the estimation
+	// of the last collection is reused within it, so that the callers sharing the collector
+	// don't shorten the window of each other.
+	tsoQPSMinCollectInterval = 10 * time.Second
+)
+
+// tsoQPSCollector estimates the TSO QPS of each keyspace by the increments of the
+// TSO request counts reported by the tso nodes between two collections.
+type tsoQPSCollector struct {
+	syncutil.Mutex
+	lastTime time.Time
+	// tso node -> keyspace ID -> the number of the TSO requests
+	lastRequests map[string]map[uint32]uint64
+	lastQPS      map[uint32]float64
+}
+
+// collect returns the TSO QPS of each keyspace since the last collection. The nodes
+// which are failed to reach or newly found are skipped, and it returns nil for the
+// first collection. It returns the last estimation if it's called again within
+// tsoQPSMinCollectInterval.
+func (c *tsoQPSCollector) collect(
+	ctx context.Context, httpClient *http.Client, nodes []string, now time.Time,
+) map[uint32]float64 {
+	c.Lock()
+	defer c.Unlock()
+	if !c.lastTime.IsZero() && now.Sub(c.lastTime) < tsoQPSMinCollectInterval {
+		return c.lastQPS
+	}
+	requests := make(map[string]map[uint32]uint64, len(nodes))
+	for _, node := range nodes {
+		nodeRequests, err := getKeyspaceRequests(ctx, httpClient, node)
+		if err != nil {
+			log.Warn("failed to get the tso requests of the keyspaces", zap.String("node", node), zap.Error(err))
+			continue
+		}
+		requests[node] = nodeRequests
+	}
+	var qps map[uint32]float64
+	if elapsed := now.Sub(c.lastTime).Seconds(); !c.lastTime.IsZero() && elapsed > 0 {
+		qps = make(map[uint32]float64)
+		for node, nodeRequests := range requests {
+			lastNodeRequests, ok := c.lastRequests[node]
+			if !ok {
+				continue
+			}
+			for keyspaceID, count := range nodeRequests {
+				// The counts are reset once the tso node restarts.
+				if last := lastNodeRequests[keyspaceID]; count >= last {
+					count -= last
+				}
+				qps[keyspaceID] += float64(count) / elapsed
+			}
+		}
+	}
+	c.lastTime, c.lastRequests, c.lastQPS = now, requests, qps
+	return qps
+}
+
+func getKeyspaceRequests(ctx context.Context, httpClient *http.Client, node string) (map[uint32]uint64, error) {
+	if httpClient == nil {
+		return nil, errors.New("no http client")
+	}
+	ctx, cancel := context.WithTimeout(ctx, tsoKeyspaceRequestsTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, node+tsoKeyspaceRequestsPath, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %s", resp.Status)
+	}
+	requests := make(map[uint32]uint64)
+	if err := json.NewDecoder(resp.Body).Decode(&requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// AutoSplitKeyspaceGroups splits the keyspace groups whose TSO QPS or keyspace count
+// exceeds the threshold in the same way as the split API, and saves an event for each
+// split. A threshold is disabled if it's zero. The TSO QPS is estimated since the last
+// call, so it should be called periodically.
+func (m *GroupManager) AutoSplitKeyspaceGroups(httpClient *http.Client, maxTSOQPS float64, maxKeyspaceCount int) {
+	var keyspaceQPS map[uint32]float64
+	if maxTSOQPS > 0 {
+		keyspaceQPS = m.tsoQPS.collect(m.ctx, httpClient, m.nodesBalancer.GetAll(), time.Now())
+	}
+	groups, err := m.store.LoadKeyspaceGroups(utils.DefaultKeyspaceGroupID, 0)
+	if err != nil {
+		log.Error("failed to load keyspace groups to split automatically", zap.Error(err))
+		return
+	}
+	usedIDs := make(map[uint32]struct{}, len(groups))
+	for _, group := range groups {
+		usedIDs[group.ID] = struct{}{}
+	}
+	for _, group := range groups {
+		if group.IsSplitting() || group.IsMerging() ||
+			len(group.Keyspaces) < 2 || len(group.Members) < utils.DefaultKeyspaceGroupReplicaCount {
+			continue
+		}
+		var qps float64
+		for _, keyspaceID := range group.Keyspaces {
+			qps += keyspaceQPS[keyspaceID]
+		}
+		var reason string
+		switch {
+		case maxTSOQPS > 0 && qps > maxTSOQPS:
+			reason = endpoint.AutoSplitReasonTSOQPS
+		case maxKeyspaceCount > 0 && len(group.Keyspaces) > maxKeyspaceCount:
+			reason = endpoint.AutoSplitReasonKeyspaceCount
+		default:
+			continue
+		}
+		keyspaces := pickAutoSplitKeyspaces(group.Keyspaces, keyspaceQPS, reason)
+		if len(keyspaces) == 0 {
+			continue
+		}
+		targetID, ok := nextFreeKeyspaceGroupID(usedIDs)
+		if !ok {
+			log.Warn("no keyspace group id is available to split automatically")
+			return
+		}
+		if err := m.SplitKeyspaceGroupByID(group.ID, targetID, keyspaces); err != nil {
+			log.Warn("failed to split the keyspace group automatically",
+				zap.Uint32("split-source", group.ID), zap.Uint32("split-target", targetID),
+				zap.String("reason", reason), zap.Error(err))
+			continue
+		}
+		usedIDs[targetID] = struct{}{}
+		log.Info("split the keyspace group automatically",
+			zap.Uint32("split-source", group.ID), zap.Uint32("split-target", targetID),
+			zap.Uint32s("keyspaces", keyspaces), zap.String("reason", reason),
+			zap.Int("keyspace-count", len(group.Keyspaces)), zap.Float64("tso-qps", qps))
+		if err := m.store.SaveKeyspaceGroupAutoSplitEvent(&endpoint.KeyspaceGroupAutoSplitEvent{
+			Time:          time.Now(),
+			SplitSource:   group.ID,
+			SplitTarget:   targetID,
+			Keyspaces:     keyspaces,
+			Reason:        reason,
+			KeyspaceCount: len(group.Keyspaces),
+			TSOQPS:        qps,
+		}); err != nil {
+			log.Warn("failed to save the keyspace group auto-split event",
+				zap.Uint32("split-source", group.ID), zap.Error(err))
+		}
+	}
+}
+
+// GetAutoSplitEvents returns the keyspace group auto-split events in the time range
+// [startTime, endTime), the end time isn't limited if it's zero.
+func (m *GroupManager) GetAutoSplitEvents(startTime, endTime time.Time) ([]*endpoint.KeyspaceGroupAutoSplitEvent, error) {
+	return m.store.LoadKeyspaceGroupAutoSplitEvents(startTime, endTime)
+}
+
+// pickAutoSplitKeyspaces picks the keyspaces moved to the split target. It moves the half
+// of the keyspaces with the largest IDs if there are too many keyspaces, or the keyspaces
+// sharing about the half of the TSO QPS if the TSO QPS is too high. The default keyspace
+// always stays in the split source.
+func pickAutoSplitKeyspaces(keyspaces []uint32, keyspaceQPS map[uint32]float64, reason string) []uint32 {
+	candidates := make([]uint32, 0, len(keyspaces))
+	for _, keyspaceID := range keyspaces {
+		if keyspaceID != utils.DefaultKeyspaceID {
+			candidates = append(candidates, keyspaceID)
+		}
+	}
+	var picked []uint32
+	switch reason {
+	case endpoint.AutoSplitReasonKeyspaceCount:
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+		count := min(len(keyspaces)/2, len(candidates))
+		picked = append(picked, candidates[len(candidates)-count:]...)
+	case endpoint.AutoSplitReasonTSOQPS:
+		var total float64
+		for _, keyspaceID := range keyspaces {
+			total += keyspaceQPS[keyspaceID]
+		}
+		sort.Slice(candidates, func(i, j int) bool {
+			qi, qj := keyspaceQPS[candidates[i]], keyspaceQPS[candidates[j]]
+			return qi > qj || (qi == qj && candidates[i] < candidates[j])
+		})
+		var moved float64
+		for _, keyspaceID := range candidates {
+			if qps := keyspaceQPS[keyspaceID]; qps > 0 && moved+qps <= total/2 {
+				picked = append(picked, keyspaceID)
+				moved += qps
+			}
+		}
+		// Isolate the busiest keyspace if it takes more than half of the TSO QPS.
+		if len(picked) == 0 && len(candidates) > 0 && keyspaceQPS[candidates[0]] > 0 {
+			picked = append(picked, candidates[0])
+		}
+	}
+	// Keep at least one keyspace in the split source.
+	if len(picked) >= len(keyspaces) {
+		return nil
+	}
+	sort.Slice(picked, func(i, j int) bool { return picked[i] < picked[j] })
+	return picked
+}
+
+// nextFreeKeyspaceGroupID returns the smallest keyspace group ID which is not used.
+func nextFreeKeyspaceGroupID(usedIDs map[uint32]struct{}) (uint32, bool) {
+	for id := utils.DefaultKeyspaceGroupID + 1; id < utils.MaxKeyspaceGroupCountInUse; id++ {
+		if _, ok := usedIDs[id]; !ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+)
+
+func TestPickAutoSplitKeyspaces(t *testing.T) {
+	re := require.New(t)
+
+	// The half of the keyspaces with the largest IDs are moved, the default keyspace stays.
+	re.Equal([]uint32{3, 4}, pickAutoSplitKeyspaces([]uint32{4, 0, 3, 1}, nil, endpoint.AutoSplitReasonKeyspaceCount))
+	re.Equal([]uint32{2}, pickAutoSplitKeyspaces([]uint32{0, 2}, nil, endpoint.AutoSplitReasonKeyspaceCount))
+
+	// The keyspaces sharing about the half of the TSO QPS are moved.
+	qps := map[uint32]float64{1: 40, 2: 30, 3: 20, 4: 10}
+	re.Equal([]uint32{1, 4}, pickAutoSplitKeyspaces([]uint32{1, 2, 3, 4}, qps, endpoint.AutoSplitReasonTSOQPS))
+	// The busiest keyspace is isolated if it takes more than half of the TSO QPS.
+	qps = map[uint32]float64{1: 10, 2: 90}
+	re.Equal([]uint32{1}, pickAutoSplitKeyspaces([]uint32{1, 2}, qps, endpoint.AutoSplitReasonTSOQPS))
+	qps = map[uint32]float64{2: 90}
+	re.Equal([]uint32{2}, pickAutoSplitKeyspaces([]uint32{1, 2}, qps, endpoint.AutoSplitReasonTSOQPS))
+	// The default keyspace is never moved.
+	qps = map[uint32]float64{0: 90, 1: 10}
+	re.Equal([]uint32{1}, pickAutoSplitKeyspaces([]uint32{0, 1}, qps, endpoint.AutoSplitReasonTSOQPS))
+	re.Empty(pickAutoSplitKeyspaces([]uint32{1, 2}, nil, endpoint.AutoSplitReasonTSOQPS))
+}
+
+func TestTSOQPSCollector(t *testing.T) {
+	re := require.New(t)
+
+	var requests atomic.Pointer[map[uint32]uint64]
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		re.Equal(tsoKeyspaceRequestsPath, r.URL.Path)
+		re.NoError(json.NewEncoder(w).Encode(*requests.Load()))
+	}))
+	defer server.Close()
+
+	c := &tsoQPSCollector{}
+	now := time.Now()
+	requests.Store(&map[uint32]uint64{1: 100, 2: 200})
+	re.Nil(c.collect(context.Background(), server.Client(), []string{server.URL}, now))
+	requests.Store(&map[uint32]uint64{1: 300, 2: 250, 3: 10})
+	qps := c.collect(context.Background(), server.Client(), []string{server.URL}, now.Add(10*time.Second))
+	re.Equal(map[uint32]float64{1: 20, 2: 5, 3: 1}, qps)
+	// The last estimation is reused within the min interval.
+	requests.Store(&map[uint32]uint64{1: 400})
+	qps = c.collect(context.Background(), server.Client(), []string{server.URL}, now.Add(15*time.Second))
+	re.Equal(map[uint32]float64{1: 20, 2: 5, 3: 1}, qps)
+	// The counts are reset after the tso node restarts.
+	requests.Store(&map[uint32]uint64{1: 50})
+	qps = c.collect(context.Background(), server.Client(), []string{server.URL}, now.Add(20*time.Second))
+	re.Equal(map[uint32]float64{1: 5}, qps)
+}
+
+func (suite *keyspaceGroupTestSuite) TestAutoSplitKeyspaceGroups() {
+	re := suite.Require()
+
+	re.NoError(suite.kgm.CreateKeyspaceGroups([]*endpoint.KeyspaceGroup{{
+		ID:        uint32(1),
+		UserKind:  endpoint.Standard.String(),
+		Members:   []endpoint.KeyspaceGroupMember{{Address: "a"}, {Address: "b"}},
+		Keyspaces: []uint32{1, 2, 3, 4},
+	}}))
+	// Nothing is split if the thresholds aren't exceeded.
+	suite.kgm.AutoSplitKeyspaceGroups(nil, 0, 4)
+	events, err := suite.kgm.GetAutoSplitEvents(time.Time{}, time.Time{})
+	re.NoError(err)
+	re.Empty(events)
+
+	suite.kgm.AutoSplitKeyspaceGroups(nil, 0, 3)
+	kg, err := suite.kgm.GetKeyspaceGroupByID(1)
+	re.NoError(err)
+	re.Equal([]uint32{1, 2}, kg.Keyspaces)
+	re.True(kg.IsSplitSource())
+	// The split target takes the smallest unused keyspace group ID.
+	kg, err = suite.kgm.GetKeyspaceGroupByID(2)
+	re.NoError(err)
+	re.Equal([]uint32{3, 4}, kg.Keyspaces)
+	re.True(kg.IsSplitTarget())
+	events, err = suite.kgm.GetAutoSplitEvents(time.Time{}, time.Time{})
+	re.NoError(err)
+	re.Len(events, 1)
+	re.Equal(uint32(1), events[0].SplitSource)
+	re.Equal(uint32(2), events[0].SplitTarget)
+	re.Equal([]uint32{3, 4}, events[0].Keyspaces)
+	re.Equal(endpoint.AutoSplitReasonKeyspaceCount, events[0].Reason)
+	re.Equal(4, events[0].KeyspaceCount)
+
+	// The keyspace groups in split are skipped.
+	suite.kgm.AutoSplitKeyspaceGroups(nil, 0, 1)
+	events, err = suite.kgm.GetAutoSplitEvents(time.Time{}, time.Time{})
+	re.NoError(err)
+	re.Len(events, 1)
+}
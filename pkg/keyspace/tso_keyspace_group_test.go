@@ -112,6 +112,145 @@ func (suite *keyspaceGroupTestSuite) TestKeyspaceGroupOperations() {
 	re.Error(err)
 }
 
+func (suite *keyspaceGroupTestSuite) TestKeyspaceGroupProfile() {
+	re := suite.Require()
+
+	// The default profile is used if the user kind has no profile.
+	profile, err := suite.kgm.GetKeyspaceGroupProfile(endpoint.Enterprise)
+	re.NoError(err)
+	re.Equal(endpoint.Enterprise.String(), profile.UserKind)
+	re.Equal(utils.DefaultKeyspaceGroupReplicaCount, profile.ReplicaCount)
+	re.Equal(utils.DefaultKeyspaceGroupReplicaPriority, profile.Priority)
+	profiles, err := suite.kgm.GetKeyspaceGroupProfiles()
+	re.NoError(err)
+	re.Len(profiles, int(endpoint.UserKindCount))
+
+	re.ErrorIs(suite.kgm.SetKeyspaceGroupProfile(&endpoint.KeyspaceGroupProfile{
+		UserKind:     endpoint.Enterprise.String(),
+		ReplicaCount: 1,
+	}), ErrInvalidKeyspaceGroupProfile)
+	re.NoError(suite.kgm.SetKeyspaceGroupProfile(&endpoint.KeyspaceGroupProfile{
+		UserKind:     endpoint.Enterprise.String(),
+		ReplicaCount: 3,
+		Priority:     10,
+		TSOConfig:    map[string]string{"save-interval": "5s", "update-physical-interval": "50ms"},
+	}))
+	profile, err = suite.kgm.GetKeyspaceGroupProfile(endpoint.Enterprise)
+	re.NoError(err)
+	re.Equal(3, profile.ReplicaCount)
+	re.Equal(10, profile.Priority)
+
+	// The new keyspace groups inherit the profile of their user kind.
+	keyspaceGroups := []*endpoint.KeyspaceGroup{
+		{
+			ID:       uint32(1),
+			UserKind: endpoint.Enterprise.String(),
+			Members: []endpoint.KeyspaceGroupMember{
+				{Address: "a", Priority: utils.UnsetKeyspaceGroupReplicaPriority},
+				{Address: "b", Priority: 1},
+				{Address: "c", Priority: utils.DefaultKeyspaceGroupReplicaPriority},
+			},
+			TSOConfig: map[string]string{"save-interval": "3s"},
+		},
+		{
+			ID:       uint32(2),
+			UserKind: endpoint.Standard.String(),
+			Members:  []endpoint.KeyspaceGroupMember{{Address: "a", Priority: utils.UnsetKeyspaceGroupReplicaPriority}},
+		},
+	}
+	re.NoError(suite.kgm.CreateKeyspaceGroups(keyspaceGroups))
+	kg, err := suite.kgm.GetKeyspaceGroupByID(1)
+	re.NoError(err)
+	re.Equal(10, kg.Members[0].Priority)
+	re.Equal(1, kg.Members[1].Priority)
+	// the explicit default priority is kept rather than inherited.
+	re.Equal(utils.DefaultKeyspaceGroupReplicaPriority, kg.Members[2].Priority)
+	re.Equal(map[string]string{"save-interval": "3s", "update-physical-interval": "50ms"}, kg.TSOConfig)
+	kg, err = suite.kgm.GetKeyspaceGroupByID(2)
+	re.NoError(err)
+	re.Equal(utils.DefaultKeyspaceGroupReplicaPriority, kg.Members[0].Priority)
+	re.Empty(kg.TSOConfig)
+
+	// The profile is updated on the latest one, and the invalid update is not saved.
+	profile, err = suite.kgm.UpdateKeyspaceGroupProfile(endpoint.Enterprise, func(profile *endpoint.KeyspaceGroupProfile) {
+		profile.Priority = 20
+		delete(profile.TSOConfig, "save-interval")
+	})
+	re.NoError(err)
+	re.Equal(3, profile.ReplicaCount)
+	re.Equal(20, profile.Priority)
+	re.Equal(map[string]string{"update-physical-interval": "50ms"}, profile.TSOConfig)
+	_, err = suite.kgm.UpdateKeyspaceGroupProfile(endpoint.Enterprise, func(profile *endpoint.KeyspaceGroupProfile) {
+		profile.ReplicaCount = 1
+	})
+	re.ErrorIs(err, ErrInvalidKeyspaceGroupProfile)
+	profile, err = suite.kgm.GetKeyspaceGroupProfile(endpoint.Enterprise)
+	re.NoError(err)
+	re.Equal(3, profile.ReplicaCount)
+	re.Equal(20, profile.Priority)
+}
+
+func (suite *keyspaceGroupTestSuite) TestKeyspaceGroupTSOConfig() {
+	re := suite.Require()
+
+	re.NoError(suite.kgm.CreateKeyspaceGroups([]*endpoint.KeyspaceGroup{{
+		ID:        uint32(1),
+		UserKind:  endpoint.Standard.String(),
+		Members:   []endpoint.KeyspaceGroupMember{{Address: "a"}},
+		TSOConfig: map[string]string{endpoint.TSOSaveIntervalKey: "3s"},
+	}}))
+	interval := "10ms"
+	kg, err := suite.kgm.UpdateTSOConfigForKeyspaceGroup(1, map[string]*string{
+		endpoint.TSOUpdatePhysicalIntervalKey: &interval,
+		endpoint.TSOSaveIntervalKey:           nil,
+	})
+	re.NoError(err)
+	re.Equal(map[string]string{endpoint.TSOUpdatePhysicalIntervalKey: "10ms"}, kg.TSOConfig)
+	kg, err = suite.kgm.GetKeyspaceGroupByID(1)
+	re.NoError(err)
+	d, err := kg.GetTSODuration(endpoint.TSOUpdatePhysicalIntervalKey)
+	re.NoError(err)
+	re.Equal(10*time.Millisecond, d)
+
+	// The invalid settings are rejected.
+	for _, interval := range []string{"abc", "-1s", "20s"} {
+		_, err = suite.kgm.UpdateTSOConfigForKeyspaceGroup(1, map[string]*string{
+			endpoint.TSOUpdatePhysicalIntervalKey: &interval,
+		})
+		re.Error(err)
+	}
+	for _, interval := range []string{"0s", "10ms", "2m"} {
+		_, err = suite.kgm.UpdateTSOConfigForKeyspaceGroup(1, map[string]*string{
+			endpoint.TSOSaveIntervalKey: &interval,
+		})
+		re.Error(err)
+	}
+	kg, err = suite.kgm.UpdateTSOConfigForKeyspaceGroup(1, map[string]*string{
+		endpoint.TSOUpdatePhysicalIntervalKey: nil,
+	})
+	re.NoError(err)
+	re.Empty(kg.TSOConfig)
+
+	// The caps of the adaptive batching.
+	maxBatchWait, maxBatchSize := "2ms", "1024"
+	kg, err = suite.kgm.UpdateTSOConfigForKeyspaceGroup(1, map[string]*string{
+		endpoint.TSOMaxBatchWaitKey: &maxBatchWait,
+		endpoint.TSOMaxBatchSizeKey: &maxBatchSize,
+	})
+	re.NoError(err)
+	wait, size := kg.GetTSOBatchCaps()
+	re.Equal(2*time.Millisecond, wait)
+	re.Equal(uint32(1024), size)
+	for _, maxBatchSize := range []string{"abc", "0", "-1"} {
+		_, err = suite.kgm.UpdateTSOConfigForKeyspaceGroup(1, map[string]*string{
+			endpoint.TSOMaxBatchSizeKey: &maxBatchSize,
+		})
+		re.Error(err)
+	}
+	_, err = suite.kgm.UpdateTSOConfigForKeyspaceGroup(2, nil)
+	re.ErrorContains(err, "does not exist")
+}
+
 func (suite *keyspaceGroupTestSuite) TestKeyspaceAssignment() {
 	re := suite.Require()
 
@@ -329,6 +468,41 @@ func (suite *keyspaceGroupTestSuite) TestKeyspaceGroupSplit() {
 	re.ErrorIs(err, ErrKeyspaceNotInKeyspaceGroup)
 }
 
+func (suite *keyspaceGroupTestSuite) TestKeyspaceGroupSplitWithPrimary() {
+	re := suite.Require()
+
+	members := []endpoint.KeyspaceGroupMember{
+		{Address: "http://127.0.0.1:3379", Priority: 1},
+		{Address: "http://127.0.0.1:3380"},
+	}
+	keyspaceGroups := []*endpoint.KeyspaceGroup{
+		{
+			ID:        uint32(1),
+			UserKind:  endpoint.Standard.String(),
+			Keyspaces: []uint32{111, 222, 333},
+			Members:   members,
+		},
+	}
+	err := suite.kgm.CreateKeyspaceGroups(keyspaceGroups)
+	re.NoError(err)
+	// hand off to a node not in the keyspace group
+	err = suite.kgm.SplitKeyspaceGroupWithPrimary(1, 2, "http://127.0.0.1:3381", []uint32{333})
+	re.ErrorIs(err, ErrNodeNotInKeyspaceGroup)
+	err = suite.kgm.SplitKeyspaceGroupWithPrimary(1, 2, "http://127.0.0.1:3380", []uint32{333})
+	re.NoError(err)
+	kg1, err := suite.kgm.GetKeyspaceGroupByID(1)
+	re.NoError(err)
+	re.Equal(members, kg1.Members)
+	kg2, err := suite.kgm.GetKeyspaceGroupByID(2)
+	re.NoError(err)
+	re.True(kg2.IsSplitTarget())
+	re.Equal([]uint32{333}, kg2.Keyspaces)
+	re.Equal([]endpoint.KeyspaceGroupMember{
+		{Address: "http://127.0.0.1:3379", Priority: 1},
+		{Address: "http://127.0.0.1:3380", Priority: 2},
+	}, kg2.Members)
+}
+
 func (suite *keyspaceGroupTestSuite) TestKeyspaceGroupSplitRange() {
 	re := suite.Require()
 
@@ -562,3 +736,118 @@ func TestBuildSplitKeyspaces(t *testing.T) {
 		}
 	}
 }
+
+func (suite *keyspaceGroupTestSuite) TestColocatePrimaries() {
+	re := suite.Require()
+
+	members := []endpoint.KeyspaceGroupMember{
+		{Address: "http://127.0.0.1:3379"},
+		{Address: "http://127.0.0.1:3380"},
+	}
+	keyspaceGroups := []*endpoint.KeyspaceGroup{
+		{ID: uint32(1), UserKind: endpoint.Standard.String(), Members: members, Keyspaces: []uint32{111}},
+		{ID: uint32(2), UserKind: endpoint.Standard.String(), Members: members, Keyspaces: []uint32{222}},
+		{ID: uint32(3), UserKind: endpoint.Standard.String(), Members: members, Keyspaces: []uint32{333}},
+	}
+	re.NoError(suite.kgm.CreateKeyspaceGroups(keyspaceGroups))
+	suite.kgm.setNodeLabels("http://127.0.0.1:3379", map[string]string{"zone": "z1"})
+	suite.kgm.setNodeLabels("http://127.0.0.1:3380", map[string]string{"zone": "z2"})
+	re.Equal("z2", suite.kgm.GetNodeLabel("127.0.0.1:3380", "zone"))
+
+	dataLocations := map[uint32]string{111: "z1", 222: "z2", 333: "z3"}
+	colocate := func() {
+		suite.kgm.ColocatePrimaries("zone", func(keyspaces []uint32) string {
+			return dataLocations[keyspaces[0]]
+		})
+	}
+	checkPriorities := func(id uint32, expected ...int) {
+		kg, err := suite.kgm.GetKeyspaceGroupByID(id)
+		re.NoError(err)
+		for i, member := range kg.Members {
+			re.Equal(expected[i], member.Priority, "keyspace group %d member %s", id, member.Address)
+		}
+	}
+	colocate()
+	checkPriorities(1, colocatedPriority, utils.DefaultKeyspaceGroupReplicaPriority)
+	checkPriorities(2, utils.DefaultKeyspaceGroupReplicaPriority, colocatedPriority)
+	// No member is in the location of the data.
+	checkPriorities(3, utils.DefaultKeyspaceGroupReplicaPriority, utils.DefaultKeyspaceGroupReplicaPriority)
+
+	// The primary follows the data only after it stays in the new location.
+	dataLocations[111] = "z2"
+	for i := 1; i < colocationStableRounds; i++ {
+		colocate()
+		checkPriorities(1, colocatedPriority, utils.DefaultKeyspaceGroupReplicaPriority)
+	}
+	colocate()
+	checkPriorities(1, utils.DefaultKeyspaceGroupReplicaPriority, colocatedPriority)
+	// The data moving back and forth doesn't move the primary.
+	for i := 0; i < 2*colocationStableRounds; i++ {
+		dataLocations[111] = []string{"z1", "z2"}[i%2]
+		colocate()
+		checkPriorities(1, utils.DefaultKeyspaceGroupReplicaPriority, colocatedPriority)
+	}
+	// The priorities aren't rewritten if the location isn't changed.
+	re.NoError(suite.kgm.SetPriorityForKeyspaceGroup(2, "http://127.0.0.1:3379", 10))
+	colocate()
+	checkPriorities(2, 10, colocatedPriority)
+}
+
+func TestPickPrimaryMove(t *testing.T) {
+	re := require.New(t)
+
+	nodes := []string{"a", "b", "c"}
+	members := []string{"a", "b", "c"}
+	newGroups := func(primaries ...string) []*primaryBalanceGroup {
+		groups := make([]*primaryBalanceGroup, 0, len(primaries))
+		for i, primary := range primaries {
+			groups = append(groups, &primaryBalanceGroup{id: uint32(i + 1), primary: primary, members: members})
+		}
+		return groups
+	}
+
+	// All the primaries pile onto one node after it restarts.
+	group, target, ok := pickPrimaryMove(nodes, newGroups("a", "a", "a", "a", "a", "a"))
+	re.True(ok)
+	re.Equal(uint32(1), group.id)
+	re.Equal("b", target)
+	// The primary is moved to the least loaded node.
+	group, target, ok = pickPrimaryMove(nodes, newGroups("b", "a", "a", "b", "a"))
+	re.True(ok)
+	re.Equal(uint32(2), group.id)
+	re.Equal("c", target)
+	// The pinned groups are counted in the load but never moved.
+	groups := newGroups("b", "a", "a", "b", "a")
+	groups[1].pinned = true
+	group, target, ok = pickPrimaryMove(nodes, groups)
+	re.True(ok)
+	re.Equal(uint32(3), group.id)
+	re.Equal("c", target)
+	for _, group := range groups {
+		group.pinned = true
+	}
+	_, _, ok = pickPrimaryMove(nodes, groups)
+	re.False(ok)
+	// Moving any primary doesn't narrow the gap.
+	_, _, ok = pickPrimaryMove(nodes, newGroups("a", "b", "c", "a", "b"))
+	re.False(ok)
+	// The primaries on the dead nodes and the dead members are ignored.
+	_, _, ok = pickPrimaryMove([]string{"a", "b"}, newGroups("a", "b", "c", "c"))
+	re.False(ok)
+
+	// The TSO QPS is taken into account.
+	groups = newGroups("a", "b", "b", "c", "c")
+	groups[0].qps = 1000
+	_, _, ok = pickPrimaryMove(nodes, groups)
+	re.False(ok)
+	groups = newGroups("a", "a", "b", "c")
+	groups[0].qps, groups[1].qps = 500, 500
+	group, target, ok = pickPrimaryMove(nodes, groups)
+	re.True(ok)
+	re.Equal(uint32(1), group.id)
+	re.Equal("b", target)
+	// The primary can only be moved to the members of the keyspace group.
+	groups[0].members, groups[1].members = []string{"a"}, []string{"a"}
+	_, _, ok = pickPrimaryMove(nodes, groups)
+	re.False(ok)
+}
@@ -0,0 +1,251 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/mcs/utils"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/typeutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// balancedPriority is the priority of the member chosen to be the primary by the
+	// balancer, it's higher than the default one so that the primary moves to it.
+	balancedPriority = utils.DefaultKeyspaceGroupReplicaPriority + 1
+	// primaryBalanceTolerance is the tolerance of the load difference between the tso
+	// nodes, it prevents the primaries from moving back and forth when they're almost
+	// balanced.
+	primaryBalanceTolerance = 0.2
+)
+
+// primaryBalanceGroup is a keyspace group considered by the primary balancer.
+type primaryBalanceGroup struct {
+	id uint32
+	// primary and members are the addresses of the tso nodes without the scheme.
+	primary string
+	members []string
+	qps     float64
+	// pinned means the primary is decided by the priorities set by others or it's
+	// still being moved, it's counted in the load but never moved.
+	pinned bool
+}
+
+// primaryBalanceMark is a member whose priority is raised by the balancer.
+type primaryBalanceMark struct {
+	address string
+	time    time.Time
+}
+
+// BalancePrimaries balances the primaries of the keyspace groups across the tso nodes by
+// the keyspace group count and the TSO QPS. It moves at most one primary each time by
+// raising the priority of the member on the least loaded node, and it doesn't move any
+// primary within the cool-down after the last move, so that the primary has time to be
+// transferred and the load is observed again. The raised priority is reset once the
+// primary is moved or the cool-down passes, and the keyspace groups with the priorities
+// set by others are left as they are. It should be called periodically.
+func (m *GroupManager) BalancePrimaries(httpClient *http.Client, coolDown time.Duration) {
+	m.primaryBalance.Lock()
+	defer m.primaryBalance.Unlock()
+	now := time.Now()
+	nodes := m.nodesBalancer.GetAll()
+	// Keep collecting the TSO QPS in the cool-down, so the estimation is always fresh.
+	keyspaceQPS := m.tsoQPS.collect(m.ctx, httpClient, nodes, now)
+	if m.client == nil {
+		return
+	}
+	groups, err := m.store.LoadKeyspaceGroups(utils.DefaultKeyspaceGroupID, 0)
+	if err != nil {
+		log.Error("failed to load keyspace groups to balance the primaries", zap.Error(err))
+		return
+	}
+	m.resetPrimaryBalanceMarks(groups, coolDown, now)
+	if len(nodes) < 2 || now.Sub(m.primaryBalance.lastMoveTime) < coolDown {
+		return
+	}
+	balanceGroups := make([]*primaryBalanceGroup, 0, len(groups))
+	for _, group := range groups {
+		if len(group.Members) < 2 || group.IsSplitting() || group.IsMerging() {
+			continue
+		}
+		primary, err := m.loadKeyspaceGroupPrimary(group.ID)
+		if err != nil {
+			continue
+		}
+		_, marked := m.primaryBalance.marks[group.ID]
+		balanceGroup := &primaryBalanceGroup{
+			id:      group.ID,
+			primary: typeutil.TrimScheme(primary),
+			members: make([]string, 0, len(group.Members)),
+			pinned:  marked,
+		}
+		for _, member := range group.Members {
+			balanceGroup.members = append(balanceGroup.members, typeutil.TrimScheme(member.Address))
+			if member.Priority != utils.DefaultKeyspaceGroupReplicaPriority {
+				balanceGroup.pinned = true
+			}
+		}
+		for _, keyspaceID := range group.Keyspaces {
+			balanceGroup.qps += keyspaceQPS[keyspaceID]
+		}
+		balanceGroups = append(balanceGroups, balanceGroup)
+	}
+	aliveNodes := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		aliveNodes = append(aliveNodes, typeutil.TrimScheme(node))
+	}
+	group, target, ok := pickPrimaryMove(aliveNodes, balanceGroups)
+	if !ok {
+		return
+	}
+	if err := m.SetPriorityForKeyspaceGroup(group.id, target, balancedPriority); err != nil {
+		log.Warn("failed to set the priority to balance the primary",
+			zap.Uint32("keyspace-group-id", group.id), zap.String("node", target), zap.Error(err))
+		return
+	}
+	if m.primaryBalance.marks == nil {
+		m.primaryBalance.marks = make(map[uint32]primaryBalanceMark)
+	}
+	m.primaryBalance.marks[group.id] = primaryBalanceMark{address: target, time: now}
+	m.primaryBalance.lastMoveTime = now
+	log.Info("move the primary of the keyspace group to balance the tso nodes",
+		zap.Uint32("keyspace-group-id", group.id), zap.String("source", group.primary),
+		zap.String("target", target), zap.Float64("tso-qps", group.qps))
+}
+
+// resetPrimaryBalanceMarks resets the priorities raised by the balancer to the default
+// once the primaries are moved or the cool-down passes. The marks whose priorities have
+// been changed by others are dropped without touching the priorities.
+func (m *GroupManager) resetPrimaryBalanceMarks(groups []*endpoint.KeyspaceGroup, coolDown time.Duration, now time.Time) {
+	if len(m.primaryBalance.marks) == 0 {
+		return
+	}
+	groupByID := make(map[uint32]*endpoint.KeyspaceGroup, len(groups))
+	for _, group := range groups {
+		groupByID[group.ID] = group
+	}
+	for id, mark := range m.primaryBalance.marks {
+		group, ok := groupByID[id]
+		if !ok {
+			delete(m.primaryBalance.marks, id)
+			continue
+		}
+		raised := false
+		for _, member := range group.Members {
+			if typeutil.TrimScheme(member.Address) == mark.address && member.Priority == balancedPriority {
+				raised = true
+				break
+			}
+		}
+		if !raised {
+			delete(m.primaryBalance.marks, id)
+			continue
+		}
+		if now.Sub(mark.time) < coolDown {
+			if primary, err := m.loadKeyspaceGroupPrimary(id); err != nil || typeutil.TrimScheme(primary) != mark.address {
+				continue
+			}
+		}
+		if err := m.SetPriorityForKeyspaceGroup(id, mark.address, utils.DefaultKeyspaceGroupReplicaPriority); err != nil {
+			log.Warn("failed to reset the priority raised to balance the primary",
+				zap.Uint32("keyspace-group-id", id), zap.String("node", mark.address), zap.Error(err))
+			continue
+		}
+		delete(m.primaryBalance.marks, id)
+	}
+}
+
+// pickPrimaryMove picks the keyspace group whose primary should be moved and the node
+// to move it to. The load of a node is the sum of the weights of the groups it serves as
+// the primary, and the weight of a group is its share of the group count plus its share
+// of the TSO QPS. The move is picked from the most loaded node to the member of the group
+// with the least load, and only if it narrows the load gap between the two nodes. The
+// pinned groups are counted in the load but never moved.
+func pickPrimaryMove(nodes []string, groups []*primaryBalanceGroup) (*primaryBalanceGroup, string, bool) {
+	alive := make(map[string]struct{}, len(nodes))
+	loads := make(map[string]float64, len(nodes))
+	for _, node := range nodes {
+		alive[node] = struct{}{}
+		loads[node] = 0
+	}
+	candidates := make([]*primaryBalanceGroup, 0, len(groups))
+	var totalQPS float64
+	for _, group := range groups {
+		if _, ok := alive[group.primary]; ok {
+			candidates = append(candidates, group)
+			totalQPS += group.qps
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, "", false
+	}
+	weight := func(group *primaryBalanceGroup) float64 {
+		w := 1 / float64(len(candidates))
+		if totalQPS > 0 {
+			w += group.qps / totalQPS
+		}
+		return w
+	}
+	for _, group := range candidates {
+		loads[group.primary] += weight(group)
+	}
+	// Try the sources from the most loaded one, break the tie by the address to make it stable.
+	sources := make([]string, 0, len(loads))
+	for node := range loads {
+		sources = append(sources, node)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		li, lj := loads[sources[i]], loads[sources[j]]
+		return li > lj || (li == lj && sources[i] < sources[j])
+	})
+	for _, source := range sources {
+		var (
+			picked *primaryBalanceGroup
+			target string
+			minGap = math.MaxFloat64
+		)
+		for _, group := range candidates {
+			if group.primary != source || group.pinned {
+				continue
+			}
+			w := weight(group)
+			for _, member := range group.members {
+				if _, ok := alive[member]; !ok || member == source {
+					continue
+				}
+				gap := loads[source] - loads[member]
+				if gap <= w*(1+primaryBalanceTolerance) {
+					continue
+				}
+				// Prefer the move which leaves the smallest gap between the two nodes.
+				newGap := math.Abs(gap - 2*w)
+				if newGap < minGap || (newGap == minGap && (group.id < picked.id ||
+					(group.id == picked.id && member < target))) {
+					picked, target, minGap = group, member, newGap
+				}
+			}
+		}
+		if picked != nil {
+			return picked, target, true
+		}
+	}
+	return nil, "", false
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyspace
+
+import (
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/mcs/utils"
+	"github.com/tikv/pd/pkg/utils/typeutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// colocatedPriority is the priority of the members which are co-located with the
+	// data, it's higher than the default one so that they are preferred to be the primary.
+	colocatedPriority = utils.DefaultKeyspaceGroupReplicaPriority + 1
+	// colocationStableRounds is the number of the consecutive rounds in which the data
+	// of a keyspace group is found in a new location before the primary follows it, so
+	// that the primary doesn't move back and forth with the leaders.
+	colocationStableRounds = 3
+)
+
+// colocationState is the location of the data a keyspace group is co-located with.
+type colocationState struct {
+	location string
+	// pending is the new location of the data and the number of the consecutive
+	// rounds in which it's found.
+	pending       string
+	pendingRounds int
+}
+
+// nextLocation returns the location to co-locate the keyspace group with, and whether
+// it's changed, given the location of the data found in this round.
+func (s *colocationState) nextLocation(location string) (string, bool) {
+	if location == s.location {
+		s.pending, s.pendingRounds = "", 0
+		return s.location, false
+	}
+	// The first location is taken at once.
+	if s.location == "" {
+		s.location = location
+		return location, true
+	}
+	if location != s.pending {
+		s.pending, s.pendingRounds = location, 0
+	}
+	s.pendingRounds++
+	if s.pendingRounds < colocationStableRounds {
+		return s.location, false
+	}
+	s.location, s.pending, s.pendingRounds = location, "", 0
+	return location, true
+}
+
+func (m *GroupManager) setNodeLabels(addr string, labels map[string]string) {
+	m.nodeLabels.Lock()
+	defer m.nodeLabels.Unlock()
+	if len(labels) == 0 {
+		delete(m.nodeLabels.labels, addr)
+		return
+	}
+	m.nodeLabels.labels[addr] = labels
+}
+
+// GetNodeLabel returns the value of the label of the tso node, it returns an
+// empty string if the node or the label doesn't exist.
+func (m *GroupManager) GetNodeLabel(addr, key string) string {
+	m.nodeLabels.RLock()
+	defer m.nodeLabels.RUnlock()
+	for nodeAddr, labels := range m.nodeLabels.labels {
+		if typeutil.EqualBaseURLs(nodeAddr, addr) {
+			return labels[key]
+		}
+	}
+	return ""
+}
+
+// ColocatePrimaries sets the priorities of the members of the keyspace groups, so
+// that the primaries are preferred to be the members in the same location as the
+// data of the keyspaces. The location of a tso node is the value of its label
+// with the given key, e.g. zone, and dataLocation returns the location where most
+// of the data of the keyspaces live, or an empty string if it's unknown.
+// The priorities are only written when the location to co-locate with changes, and
+// a new location is only taken after it's found in colocationStableRounds calls in
+// a row. A keyspace group is skipped if none of its members is in the location.
+func (m *GroupManager) ColocatePrimaries(labelKey string, dataLocation func(keyspaces []uint32) string) {
+	m.primaryColocation.Lock()
+	defer m.primaryColocation.Unlock()
+	groups, err := m.store.LoadKeyspaceGroups(utils.DefaultKeyspaceGroupID, 0)
+	if err != nil {
+		log.Error("failed to load keyspace groups to co-locate the primaries", zap.Error(err))
+		return
+	}
+	// Rebuild the states to forget the deleted keyspace groups.
+	states := make(map[uint32]*colocationState, len(groups))
+	for _, group := range groups {
+		state, ok := m.primaryColocation.states[group.ID]
+		if !ok {
+			state = &colocationState{}
+		}
+		states[group.ID] = state
+		if len(group.Members) < 2 || group.IsSplitting() || group.IsMerging() {
+			continue
+		}
+		location := dataLocation(group.Keyspaces)
+		if location == "" {
+			continue
+		}
+		location, changed := state.nextLocation(location)
+		if !changed {
+			continue
+		}
+		priorities := make(map[string]int, len(group.Members))
+		colocated := false
+		for _, member := range group.Members {
+			priority := utils.DefaultKeyspaceGroupReplicaPriority
+			if m.GetNodeLabel(member.Address, labelKey) == location {
+				priority = colocatedPriority
+				colocated = true
+			}
+			priorities[member.Address] = priority
+		}
+		if !colocated {
+			continue
+		}
+		for _, member := range group.Members {
+			priority := priorities[member.Address]
+			if member.Priority == priority {
+				continue
+			}
+			if err := m.SetPriorityForKeyspaceGroup(group.ID, member.Address, priority); err != nil {
+				log.Warn("failed to set the priority to co-locate the primary",
+					zap.Uint32("keyspace-group-id", group.ID), zap.String("node", member.Address), zap.Error(err))
+				// Retry in the next round.
+				state.location = ""
+				break
+			}
+			log.Info("set the priority to co-locate the primary with the data",
+				zap.Uint32("keyspace-group-id", group.ID), zap.String("node", member.Address),
+				zap.String("location", location), zap.Int("priority", priority))
+		}
+	}
+	m.primaryColocation.states = states
+}
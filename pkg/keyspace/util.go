@@ -86,7 +86,9 @@ var (
 	ErrExceedMaxEtcdTxnOps = errors.New("exceed max etcd txn operations")
 	// ErrModifyDefaultKeyspace is used to indicate that default keyspace cannot be modified.
 	ErrModifyDefaultKeyspace = errors.New("cannot modify default keyspace's state")
-	errIllegalOperation      = errors.New("unknown operation")
+	// ErrInvalidKeyspaceGroupProfile is used to indicate the keyspace group profile is invalid.
+	ErrInvalidKeyspaceGroupProfile = errors.New("invalid keyspace group profile")
+	errIllegalOperation            = errors.New("unknown operation")
 
 	// stateTransitionTable lists all allowed next state for the given current state.
 	// Note that transit from any state to itself is allowed for idempotence.
@@ -175,6 +177,60 @@ func MakeRegionBound(id uint32) *RegionBound {
 	}
 }
 
+// The modes of the keys in a keyspace.
+const (
+	KeyModeRaw = "raw"
+	KeyModeTxn = "txn"
+)
+
+// keyModePrefix returns the prefix of the keys in the given mode.
+func keyModePrefix(mode string) (byte, error) {
+	switch mode {
+	case KeyModeRaw:
+		return 'r', nil
+	case KeyModeTxn, "":
+		return 'x', nil
+	default:
+		return 0, errors.Errorf("invalid key mode %s, should be %s or %s", mode, KeyModeRaw, KeyModeTxn)
+	}
+}
+
+// MakeRegionKey translates the user key in the given keyspace into the region key,
+// i.e. the memcomparable encoded key with the keyspace prefix. The empty mode is
+// treated as the txn mode.
+func MakeRegionKey(id uint32, mode string, key []byte) ([]byte, error) {
+	prefix, err := keyModePrefix(mode)
+	if err != nil {
+		return nil, err
+	}
+	keyspaceIDBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(keyspaceIDBytes, id)
+	rawKey := make([]byte, 0, 4+len(key))
+	rawKey = append(append(append(rawKey, prefix), keyspaceIDBytes[1:]...), key...)
+	return codec.EncodeBytes(rawKey), nil
+}
+
+// MakeRegionKeyRange translates the user key range in the given keyspace into
+// the region key range, the empty end key means the end of the keyspace.
+func MakeRegionKeyRange(id uint32, mode string, startKey, endKey []byte) ([]byte, []byte, error) {
+	start, err := MakeRegionKey(id, mode, startKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(endKey) == 0 {
+		bound := MakeRegionBound(id)
+		if mode == KeyModeRaw {
+			return start, bound.RawRightBound, nil
+		}
+		return start, bound.TxnRightBound, nil
+	}
+	end, err := MakeRegionKey(id, mode, endKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return start, end, nil
+}
+
 // MakeKeyRanges encodes keyspace ID to correct LabelRule data.
 func MakeKeyRanges(id uint32) []any {
 	regionBound := MakeRegionBound(id)
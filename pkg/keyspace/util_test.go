@@ -124,3 +124,28 @@ func TestMakeLabelRule(t *testing.T) {
 		re.Equal(testCase.expectedLabelRule, MakeLabelRule(testCase.id))
 	}
 }
+
+func TestMakeRegionKeyRange(t *testing.T) {
+	re := require.New(t)
+	key, err := MakeRegionKey(4242, KeyModeTxn, []byte("a"))
+	re.NoError(err)
+	re.Equal([]byte(codec.EncodeBytes([]byte{'x', 0, 0x10, 0x92, 'a'})), key)
+	key, err = MakeRegionKey(4242, "", []byte("a"))
+	re.NoError(err)
+	re.Equal([]byte(codec.EncodeBytes([]byte{'x', 0, 0x10, 0x92, 'a'})), key)
+	_, err = MakeRegionKey(4242, "unknown", []byte("a"))
+	re.Error(err)
+
+	start, end, err := MakeRegionKeyRange(1, KeyModeRaw, []byte("a"), []byte("b"))
+	re.NoError(err)
+	re.Equal([]byte(codec.EncodeBytes([]byte{'r', 0, 0, 1, 'a'})), start)
+	re.Equal([]byte(codec.EncodeBytes([]byte{'r', 0, 0, 1, 'b'})), end)
+	// the empty end key means the end of the keyspace.
+	start, end, err = MakeRegionKeyRange(1, KeyModeRaw, nil, nil)
+	re.NoError(err)
+	re.Equal([]byte(codec.EncodeBytes([]byte{'r', 0, 0, 1})), start)
+	re.Equal([]byte(codec.EncodeBytes([]byte{'r', 0, 0, 2})), end)
+	_, end, err = MakeRegionKeyRange(1, KeyModeTxn, nil, nil)
+	re.NoError(err)
+	re.Equal([]byte(codec.EncodeBytes([]byte{'x', 0, 0, 2})), end)
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 
 	"github.com/pingcap/log"
+	"go.etcd.io/etcd/mvcc/mvccpb"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +29,8 @@ type ServiceRegistryEntry struct {
 	GitHash        string `json:"git-hash"`
 	DeployPath     string `json:"deploy-path"`
 	StartTimestamp int64  `json:"start-timestamp"`
+	// Labels are the labels of the service, e.g. zone.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // Serialize this service registry entry
@@ -48,3 +51,12 @@ func (e *ServiceRegistryEntry) Deserialize(data []byte) error {
 	}
 	return nil
 }
+
+// DecodeServiceRegistryEntry decodes the service registry entry from the etcd key-value.
+func DecodeServiceRegistryEntry(kv *mvccpb.KeyValue) (*ServiceRegistryEntry, error) {
+	s := &ServiceRegistryEntry{}
+	if err := json.Unmarshal(kv.Value, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
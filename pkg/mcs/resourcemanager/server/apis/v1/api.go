@@ -93,6 +93,7 @@ func NewService(srv *rmserver.Service) *Service {
 func (s *Service) RegisterAdminRouter() {
 	router := s.root.Group("admin")
 	router.PUT("/log", changeLogLevel)
+	router.POST("/shutdown", shutdown)
 }
 
 // RegisterRouter registers the router of the service.
@@ -103,8 +104,11 @@ func (s *Service) RegisterRouter() {
 	configEndpoint.GET("/group/:name", s.getResourceGroup)
 	configEndpoint.GET("/groups", s.getResourceGroupList)
 	configEndpoint.DELETE("/group/:name", s.deleteResourceGroup)
+	configEndpoint.PUT("/group/:name/parent", s.setResourceGroupParent)
+	configEndpoint.PUT("/group/:name/bypass", s.setResourceGroupBypass)
 	configEndpoint.GET("/controller", s.getControllerConfig)
 	configEndpoint.POST("/controller", s.setControllerConfig)
+	s.root.GET("/token-server/load", s.getTokenServerLoad)
 }
 
 func (s *Service) handler() http.Handler {
@@ -113,6 +117,17 @@ func (s *Service) handler() http.Handler {
 	})
 }
 
+// @Tags     admin
+// @Summary  Shut down the server gracefully.
+// @Produce  json
+// @Success  200  {string}  string  "The server is shutting down."
+// @Router   /admin/shutdown [post]
+func shutdown(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*rmserver.Service)
+	svr.Shutdown()
+	c.String(http.StatusOK, "The server is shutting down.")
+}
+
 func changeLogLevel(c *gin.Context) {
 	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*rmserver.Service)
 	var level string
@@ -216,6 +231,51 @@ func (s *Service) getResourceGroupList(c *gin.Context) {
 func (s *Service) deleteResourceGroup(c *gin.Context) {
 	if err := s.manager.DeleteResourceGroup(c.Param("name")); err != nil {
 		c.String(http.StatusNotFound, err.Error())
+		return
+	}
+	c.String(http.StatusOK, "Success!")
+}
+
+// setResourceGroupParent
+//
+//	@Tags		ResourceManager
+//	@Summary	Set the parent of the resource group, the empty parent makes it a root.
+//	@Param		name		path	string	true	"groupName"
+//	@Param		hierarchy	body	object	true	"json params, rmserver.GroupHierarchy"
+//	@Success	200			{string}	string	"Success!"
+//	@Failure	400			{string}	error
+//	@Router		/config/group/{name}/parent [put]
+func (s *Service) setResourceGroupParent(c *gin.Context) {
+	var hierarchy rmserver.GroupHierarchy
+	if err := c.ShouldBindJSON(&hierarchy); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.manager.SetResourceGroupParent(c.Param("name"), &hierarchy); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	c.String(http.StatusOK, "Success!")
+}
+
+// setResourceGroupBypass
+//
+//	@Tags		ResourceManager
+//	@Summary	Set whether the requests of the resource group bypass the throttling, the RU consumption is still accounted.
+//	@Param		name	path	string	true	"groupName"
+//	@Param		bypass	body	object	true	"json params, rmserver.GroupBypass"
+//	@Success	200		{string}	string	"Success!"
+//	@Failure	400		{string}	error
+//	@Router		/config/group/{name}/bypass [put]
+func (s *Service) setResourceGroupBypass(c *gin.Context) {
+	var bypass rmserver.GroupBypass
+	if err := c.ShouldBindJSON(&bypass); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.manager.SetResourceGroupBypass(c.Param("name"), &bypass); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
 	}
 	c.String(http.StatusOK, "Success!")
 }
@@ -259,3 +319,13 @@ func (s *Service) setControllerConfig(c *gin.Context) {
 	}
 	c.String(http.StatusOK, "Success!")
 }
+
+// getTokenServerLoad
+//
+//	@Tags		ResourceManager
+//	@Summary	Get the token request rates, the grant latencies and the queue depths of the token server.
+//	@Success	200	{object}	rmserver.TokenServerLoad
+//	@Router		/token-server/load [get]
+func (s *Service) getTokenServerLoad(c *gin.Context) {
+	c.IndentedJSON(http.StatusOK, s.manager.GetTokenServerLoad())
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+
+	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"go.uber.org/zap"
+)
+
+// GroupBypass is the bypass setting of a resource group. The requests of the
+// bypassed group are not throttled, while their RU consumption is still
+// accounted and reported, e.g. for the import jobs which should be tracked for
+// billing without being slowed down.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type GroupBypass struct {
+	Bypass bool `json:"bypass"`
+}
+
+// loadBypasses loads the bypass settings of the groups, it's called in the Init.
+func (m *Manager) loadBypasses() error {
+	handler := func(k, v string) {
+		bypass := &GroupBypass{}
+		if err := json.Unmarshal([]byte(v), bypass); err != nil {
+			log.Error("failed to parse the resource group bypass setting", zap.Error(err), zap.String("k", k), zap.String("v", v))
+			return
+		}
+		if group, ok := m.groups[k]; ok {
+			group.Bypass = bypass.Bypass
+		}
+	}
+	return m.storage.LoadResourceGroupBypasses(handler)
+}
+
+// SetResourceGroupBypass sets whether the requests of the resource group bypass
+// the throttling.
+func (m *Manager) SetResourceGroupBypass(name string, bypass *GroupBypass) error {
+	m.Lock()
+	defer m.Unlock()
+	group, ok := m.groups[name]
+	if !ok {
+		return errs.ErrResourceGroupNotExists.FastGenByArgs(name)
+	}
+	var err error
+	if bypass.Bypass {
+		err = m.storage.SaveResourceGroupBypass(name, bypass)
+	} else {
+		err = m.storage.DeleteResourceGroupBypass(name)
+	}
+	if err != nil {
+		return err
+	}
+	group.Lock()
+	group.Bypass = bypass.Bypass
+	group.Unlock()
+	log.Info("set resource group bypass", zap.String("name", name), zap.Bool("bypass", bypass.Bypass))
+	return nil
+}
+
+func (rg *ResourceGroup) isBypassed() bool {
+	rg.RLock()
+	defer rg.RUnlock()
+	return rg.Bypass
+}
+
+// grantBypassedRU grants the required tokens without taking them from the
+// bucket. The unlimited burst makes the client stop throttling the requests
+// until the bypass is turned off. It should be called with the lock held.
+func (rg *ResourceGroup) grantBypassedRU(requiredToken float64) *rmpb.GrantedRUTokenBucket {
+	return &rmpb.GrantedRUTokenBucket{
+		GrantedTokens: &rmpb.TokenBucket{
+			Settings: &rmpb.TokenLimitSettings{
+				FillRate:   rg.RUSettings.RU.Settings.GetFillRate(),
+				BurstLimit: -1,
+			},
+			Tokens: requiredToken,
+		},
+	}
+}
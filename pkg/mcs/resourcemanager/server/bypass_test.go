@@ -0,0 +1,62 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/storage"
+)
+
+func TestGroupBypass(t *testing.T) {
+	re := require.New(t)
+	m := &Manager{
+		groups:  make(map[string]*ResourceGroup),
+		storage: storage.NewStorageWithMemoryBackend(),
+	}
+	re.NoError(m.AddResourceGroup(newTestRUGroup("import", 100, 100, 0)))
+	re.Error(m.SetResourceGroupBypass("unknown", &GroupBypass{Bypass: true}))
+	re.NoError(m.SetResourceGroupBypass("import", &GroupBypass{Bypass: true}))
+	// the bypass setting is kept when the group is put again.
+	re.NoError(m.AddResourceGroup(newTestRUGroup("import", 100, 100, 0)))
+	re.True(m.GetResourceGroup("import", false).Bypass)
+
+	// the tokens are granted without throttling and not taken from the bucket.
+	now := time.Now()
+	group := m.GetMutableResourceGroup("import")
+	tokens := group.RequestRU(now, 10000, 1000, 1)
+	re.Equal(10000.0, tokens.GetGrantedTokens().GetTokens())
+	re.Equal(int64(-1), tokens.GetGrantedTokens().GetSettings().GetBurstLimit())
+	re.Zero(tokens.GetTrickleTimeMs())
+	re.Zero(group.getRUToken())
+
+	// the bypass setting is loaded after restarting.
+	m.groups = make(map[string]*ResourceGroup)
+	re.NoError(m.AddResourceGroup(newTestRUGroup("import", 100, 100, 0)))
+	re.False(m.GetResourceGroup("import", false).Bypass)
+	re.NoError(m.loadBypasses())
+	re.True(m.GetResourceGroup("import", false).Bypass)
+
+	// the requests are throttled again after the bypass is turned off.
+	re.NoError(m.SetResourceGroupBypass("import", &GroupBypass{}))
+	group = m.GetMutableResourceGroup("import")
+	tokens = group.RequestRU(now, 1e9, 1000, 1)
+	re.Equal(int64(100), tokens.GetGrantedTokens().GetSettings().GetBurstLimit())
+	re.Less(tokens.GetGrantedTokens().GetTokens(), 1e9)
+	re.NoError(m.loadBypasses())
+	re.False(m.GetResourceGroup("import", false).Bypass)
+}
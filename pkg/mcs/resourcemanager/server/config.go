@@ -61,6 +61,10 @@ const (
 	defaultMaxWaitDuration = 30 * time.Second
 	// defaultLTBTokenRPCMaxDelay is the upper bound of backoff delay for local token bucket RPC.
 	defaultLTBTokenRPCMaxDelay = 1 * time.Second
+
+	defaultMeteringFlushInterval = time.Minute
+	defaultMeteringBufferSize    = 100000
+	defaultMeteringSendTimeout   = 10 * time.Second
 )
 
 // Config is the configuration for the resource manager.
@@ -91,6 +95,8 @@ type Config struct {
 	LeaderLease int64 `toml:"lease" json:"lease"`
 
 	Controller ControllerConfig `toml:"controller" json:"controller"`
+
+	Metering MeteringConfig `toml:"metering" json:"metering"`
 }
 
 // ControllerConfig is the configuration of the resource manager controller which includes some option for client needed.
@@ -132,6 +138,40 @@ func (rmc *ControllerConfig) Adjust(meta *configutil.ConfigMetaData) {
 	})
 }
 
+// MeteringConfig is the configuration of exporting the RU consumption records
+// of the resource groups to an external metering sink.
+type MeteringConfig struct {
+	// SinkURL is the HTTP endpoint which receives the metering records,
+	// the exporter is disabled if it's empty.
+	SinkURL string `toml:"sink-url" json:"sink-url"`
+	// FlushInterval is the length of the aggregation window, the records of
+	// a window are pushed to the sink when the window is closed.
+	FlushInterval typeutil.Duration `toml:"flush-interval" json:"flush-interval"`
+	// BufferSize is the max number of the records kept locally when the sink
+	// is unavailable, the oldest records are dropped once it's exceeded.
+	BufferSize int `toml:"buffer-size" json:"buffer-size"`
+	// SendTimeout is the timeout of pushing one batch to the sink.
+	SendTimeout typeutil.Duration `toml:"send-timeout" json:"send-timeout"`
+}
+
+// Adjust adjusts the configuration and initializes it with the default value if necessary.
+func (mc *MeteringConfig) Adjust(meta *configutil.ConfigMetaData) {
+	if !meta.IsDefined("flush-interval") {
+		configutil.AdjustDuration(&mc.FlushInterval, defaultMeteringFlushInterval)
+	}
+	if !meta.IsDefined("buffer-size") {
+		configutil.AdjustInt(&mc.BufferSize, defaultMeteringBufferSize)
+	}
+	if !meta.IsDefined("send-timeout") {
+		configutil.AdjustDuration(&mc.SendTimeout, defaultMeteringSendTimeout)
+	}
+}
+
+// IsEnabled returns whether the metering exporter is enabled.
+func (mc *MeteringConfig) IsEnabled() bool {
+	return mc != nil && len(mc.SinkURL) > 0
+}
+
 // RequestUnitConfig is the configuration of the request units, which determines the coefficients of
 // the RRU and WRU cost. This configuration should be modified carefully.
 type RequestUnitConfig struct {
@@ -250,6 +290,7 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	}
 
 	c.Controller.Adjust(configMetaData.Child("controller"))
+	c.Metering.Adjust(configMetaData.Child("metering"))
 	configutil.AdjustInt64(&c.LeaderLease, utils.DefaultLeaderLease)
 
 	return nil
@@ -211,16 +211,23 @@ func (s *Service) AcquireTokenBuckets(stream rmpb.ResourceManager_AcquireTokenBu
 			}
 			switch rg.Mode {
 			case rmpb.GroupMode_RUMode:
-				var tokens *rmpb.GrantedRUTokenBucket
+				done := s.manager.tokenLoad.begin(rg.Name)
+				var (
+					tokens  *rmpb.GrantedRUTokenBucket
+					granted float64
+				)
 				for _, re := range req.GetRuItems().GetRequestRU() {
 					if re.Type == rmpb.RequestUnitType_RU {
+						s.manager.borrowRU(rg, now, re.Value)
 						tokens = rg.RequestRU(now, re.Value, targetPeriodMs, clientUniqueID)
 					}
 					if tokens == nil {
 						continue
 					}
+					granted += tokens.GetGrantedTokens().GetTokens()
 					resp.GrantedRUTokens = append(resp.GrantedRUTokens, tokens)
 				}
+				done(granted)
 			case rmpb.GroupMode_RawMode:
 				log.Warn("not supports the resource type", zap.String("resource-group", resourceGroupName), zap.String("mode", rmpb.GroupMode_name[int32(rmpb.GroupMode_RawMode)]))
 				continue
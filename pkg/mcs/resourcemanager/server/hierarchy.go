@@ -0,0 +1,284 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxGroupHierarchyDepth is the max number of the levels of the group tree.
+	maxGroupHierarchyDepth = 8
+	// loanPeriod is the period in which the borrowed tokens of a child are
+	// limited, it's the unit of the fill rate.
+	loanPeriod = time.Second
+)
+
+// GroupHierarchy is the parent of a resource group, the empty parent means
+// the group is a root. The group can borrow the RU tokens from the parent when
+// its own tokens run out, the tokens borrowed by each child in a loan period
+// are limited by the parent's tokens at the start of the period in proportion
+// to their shares, the zero shares is 1.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type GroupHierarchy struct {
+	Parent string `json:"parent"`
+	Shares uint64 `json:"shares,omitempty"`
+}
+
+func getShares(shares uint64) uint64 {
+	if shares == 0 {
+		return 1
+	}
+	return shares
+}
+
+// loadHierarchies loads the parents of the groups, it's called in the Init.
+func (m *Manager) loadHierarchies() error {
+	handler := func(k, v string) {
+		hierarchy := &GroupHierarchy{}
+		if err := json.Unmarshal([]byte(v), hierarchy); err != nil {
+			log.Error("failed to parse the resource group hierarchy", zap.Error(err), zap.String("k", k), zap.String("v", v))
+			return
+		}
+		if group, ok := m.groups[k]; ok {
+			group.Parent, group.Shares = hierarchy.Parent, hierarchy.Shares
+		}
+	}
+	if err := m.storage.LoadResourceGroupHierarchies(handler); err != nil {
+		return err
+	}
+	m.rebuildShareSums()
+	return nil
+}
+
+// rebuildShareSums sums up the shares of the children of each parent, it
+// should be called with the lock held.
+func (m *Manager) rebuildShareSums() {
+	m.shareSums = make(map[string]uint64)
+	for _, group := range m.groups {
+		if group.Parent != "" {
+			m.shareSums[group.Parent] += getShares(group.Shares)
+		}
+	}
+}
+
+// hasChildren returns whether there are groups whose parent is the given one,
+// it should be called with the lock held.
+func (m *Manager) hasChildren(name string) bool {
+	return m.shareSums[name] > 0
+}
+
+// subtreeHeight returns the number of the levels of the subtree rooted at the
+// given group, it should be called with the lock held.
+func (m *Manager) subtreeHeight(name string) int {
+	height := 1
+	for childName, child := range m.groups {
+		if child.Parent == name && childName != name {
+			height = max(height, m.subtreeHeight(childName)+1)
+		}
+	}
+	return height
+}
+
+// SetResourceGroupParent sets the parent of the resource group.
+func (m *Manager) SetResourceGroupParent(name string, hierarchy *GroupHierarchy) error {
+	m.Lock()
+	defer m.Unlock()
+	group, ok := m.groups[name]
+	if !ok {
+		return errs.ErrResourceGroupNotExists.FastGenByArgs(name)
+	}
+	if hierarchy.Parent != "" {
+		if name == reservedDefaultGroupName {
+			return errs.ErrInvalidGroupHierarchy.FastGenByArgs("the default group can't have a parent")
+		}
+		parent, ok := m.groups[hierarchy.Parent]
+		if !ok {
+			return errs.ErrResourceGroupNotExists.FastGenByArgs(hierarchy.Parent)
+		}
+		if group.Mode != rmpb.GroupMode_RUMode || parent.Mode != rmpb.GroupMode_RUMode {
+			return errs.ErrInvalidGroupHierarchy.FastGenByArgs("only the groups in RU mode can be organized")
+		}
+		depth := m.subtreeHeight(name)
+		for ancestor := hierarchy.Parent; ancestor != ""; ancestor = m.groups[ancestor].Parent {
+			if ancestor == name {
+				return errs.ErrInvalidGroupHierarchy.FastGenByArgs(fmt.Sprintf("the group %s can't be the parent of its ancestor", name))
+			}
+			depth++
+		}
+		if depth > maxGroupHierarchyDepth {
+			return errs.ErrInvalidGroupHierarchy.FastGenByArgs(fmt.Sprintf("the depth exceeds %d", maxGroupHierarchyDepth))
+		}
+	}
+	var err error
+	if hierarchy.Parent == "" {
+		err = m.storage.DeleteResourceGroupHierarchy(name)
+	} else {
+		err = m.storage.SaveResourceGroupHierarchy(name, hierarchy)
+	}
+	if err != nil {
+		return err
+	}
+	group.Lock()
+	group.Parent, group.Shares = hierarchy.Parent, hierarchy.Shares
+	group.Unlock()
+	m.rebuildShareSums()
+	log.Info("set resource group parent", zap.String("name", name), zap.String("parent", hierarchy.Parent), zap.Uint64("shares", hierarchy.Shares))
+	return nil
+}
+
+// lender is an ancestor group which lends the RU tokens to the child, ratio
+// is the share of its tokens which can be lent to the child.
+type lender struct {
+	group *ResourceGroup
+	child string
+	ratio float64
+}
+
+// loanLedger records the tokens lent to each child in the current loan period.
+type loanLedger struct {
+	start time.Time
+	// base is the available tokens of the lender at the start of the period.
+	base float64
+	lent map[string]float64
+}
+
+// getLenders returns the ancestors of the group from the parent to the root.
+func (m *Manager) getLenders(rg *ResourceGroup) []lender {
+	m.RLock()
+	defer m.RUnlock()
+	var lenders []lender
+	for child := rg; child.Parent != "" && len(lenders) < maxGroupHierarchyDepth; {
+		parent, ok := m.groups[child.Parent]
+		if !ok {
+			break
+		}
+		ratio := 1.0
+		if sum := m.shareSums[child.Parent]; sum > 0 {
+			ratio = float64(getShares(child.Shares)) / float64(sum)
+		}
+		lenders = append(lenders, lender{group: parent, child: child.Name, ratio: ratio})
+		child = parent
+	}
+	return lenders
+}
+
+// borrowRU borrows the RU tokens from the ancestors if the tokens of the group
+// can't meet the requirement.
+func (m *Manager) borrowRU(rg *ResourceGroup, now time.Time, requiredToken float64) {
+	// the bypassed group doesn't consume its own tokens, no need to borrow.
+	if rg.isBypassed() {
+		return
+	}
+	lenders := m.getLenders(rg)
+	if len(lenders) == 0 {
+		return
+	}
+	deficit := rg.getRUDeficit(now, requiredToken)
+	if deficit <= 0 {
+		return
+	}
+	if borrowed := borrowFromLenders(now, lenders, deficit); borrowed > 0 {
+		rg.borrowRU(borrowed)
+		borrowedRequestUnit.WithLabelValues(rg.Name).Add(borrowed)
+	}
+}
+
+// borrowFromLenders borrows the tokens from the first lender, which borrows
+// from the next lender in turn if it can't lend enough itself.
+func borrowFromLenders(now time.Time, lenders []lender, tokens float64) float64 {
+	l := lenders[0]
+	if len(lenders) > 1 && l.group.getRUBurstLimit() > 0 {
+		if short := tokens/l.ratio - l.group.getAvailableRU(now); short > 0 {
+			if borrowed := borrowFromLenders(now, lenders[1:], short); borrowed > 0 {
+				l.group.borrowRU(borrowed)
+				borrowedRequestUnit.WithLabelValues(l.group.Name).Add(borrowed)
+			}
+		}
+	}
+	return l.group.lendRU(now, l.child, tokens, l.ratio)
+}
+
+func (rg *ResourceGroup) getRUBurstLimit() int64 {
+	rg.RLock()
+	defer rg.RUnlock()
+	if rg.RUSettings == nil || rg.RUSettings.RU == nil {
+		return 0
+	}
+	return rg.RUSettings.RU.Settings.GetBurstLimit()
+}
+
+func (rg *ResourceGroup) getAvailableRU(now time.Time) float64 {
+	rg.RLock()
+	defer rg.RUnlock()
+	if rg.RUSettings == nil || rg.RUSettings.RU == nil {
+		return 0
+	}
+	return rg.RUSettings.RU.available(now)
+}
+
+// getRUDeficit returns the tokens lacked to meet the requirement, the
+// requirement beyond the burst limit is ignored because the tokens can't be
+// accumulated more than it.
+func (rg *ResourceGroup) getRUDeficit(now time.Time, requiredToken float64) float64 {
+	rg.RLock()
+	defer rg.RUnlock()
+	if rg.RUSettings == nil || rg.RUSettings.RU == nil {
+		return 0
+	}
+	burstLimit := rg.RUSettings.RU.Settings.GetBurstLimit()
+	if burstLimit <= 0 {
+		return 0
+	}
+	return math.Min(requiredToken, float64(burstLimit)) - rg.RUSettings.RU.available(now)
+}
+
+// lendRU lends the tokens to the child, the tokens lent to the child in a loan
+// period are at most the ratio of the available tokens at the start of it.
+func (rg *ResourceGroup) lendRU(now time.Time, child string, tokens, ratio float64) float64 {
+	rg.Lock()
+	defer rg.Unlock()
+	if rg.RUSettings == nil || rg.RUSettings.RU == nil {
+		return 0
+	}
+	ru := rg.RUSettings.RU
+	if rg.loans == nil || now.Before(rg.loans.start) || now.Sub(rg.loans.start) >= loanPeriod {
+		rg.loans = &loanLedger{start: now, base: ru.available(now), lent: make(map[string]float64)}
+	}
+	quota := rg.loans.base*ratio - rg.loans.lent[child]
+	if quota <= 0 {
+		return 0
+	}
+	lent := ru.lend(now, math.Min(tokens, quota))
+	rg.loans.lent[child] += lent
+	return lent
+}
+
+func (rg *ResourceGroup) borrowRU(tokens float64) {
+	rg.Lock()
+	defer rg.Unlock()
+	if rg.RUSettings == nil || rg.RUSettings.RU == nil {
+		return
+	}
+	rg.RUSettings.RU.borrow(tokens)
+}
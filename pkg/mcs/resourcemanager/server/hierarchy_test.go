@@ -0,0 +1,115 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/storage"
+)
+
+func newTestRUGroup(name string, fillRate uint64, burstLimit int64, tokens float64) *rmpb.ResourceGroup {
+	return &rmpb.ResourceGroup{
+		Name: name,
+		Mode: rmpb.GroupMode_RUMode,
+		RUSettings: &rmpb.GroupRequestUnitSettings{
+			RU: &rmpb.TokenBucket{
+				Settings: &rmpb.TokenLimitSettings{FillRate: fillRate, BurstLimit: burstLimit},
+				Tokens:   tokens,
+			},
+		},
+	}
+}
+
+func TestGroupHierarchy(t *testing.T) {
+	re := require.New(t)
+	m := &Manager{
+		groups:  make(map[string]*ResourceGroup),
+		storage: storage.NewStorageWithMemoryBackend(),
+	}
+	re.NoError(m.AddResourceGroup(newTestRUGroup(reservedDefaultGroupName, 1000, -1, 0)))
+	re.NoError(m.AddResourceGroup(newTestRUGroup("dept", 1000, 10000, 8000)))
+	re.NoError(m.AddResourceGroup(newTestRUGroup("team-a", 100, 1000, 0)))
+	re.NoError(m.AddResourceGroup(newTestRUGroup("team-b", 100, 1000, 0)))
+	re.NoError(m.AddResourceGroup(newTestRUGroup("tenant", 100, 500, 0)))
+
+	re.NoError(m.SetResourceGroupParent("team-a", &GroupHierarchy{Parent: "dept", Shares: 3}))
+	re.NoError(m.SetResourceGroupParent("team-b", &GroupHierarchy{Parent: "dept"}))
+	re.NoError(m.SetResourceGroupParent("tenant", &GroupHierarchy{Parent: "team-b"}))
+	re.Error(m.SetResourceGroupParent("dept", &GroupHierarchy{Parent: "tenant"}))
+	re.Error(m.SetResourceGroupParent("dept", &GroupHierarchy{Parent: "unknown"}))
+	re.Error(m.SetResourceGroupParent(reservedDefaultGroupName, &GroupHierarchy{Parent: "dept"}))
+	re.Error(m.DeleteResourceGroup("dept"))
+	// the hierarchy is kept when the group is put again.
+	re.NoError(m.AddResourceGroup(newTestRUGroup("team-a", 100, 1000, 0)))
+	re.Equal("dept", m.GetResourceGroup("team-a", false).Parent)
+	re.Equal(uint64(3), m.GetResourceGroup("team-a", false).Shares)
+
+	// team-a borrows at most 3/4 of the tokens of dept, and the requirement
+	// beyond its burst limit is ignored.
+	now := time.Now()
+	teamA, teamB, tenant, dept := m.GetMutableResourceGroup("team-a"), m.GetMutableResourceGroup("team-b"),
+		m.GetMutableResourceGroup("tenant"), m.GetMutableResourceGroup("dept")
+	m.borrowRU(teamA, now, 5000)
+	re.Equal(1000.0, teamA.getAvailableRU(now))
+	re.Equal(7000.0, dept.getAvailableRU(now))
+	// tenant borrows from team-b, which borrows from dept in turn.
+	m.borrowRU(tenant, now, 500)
+	re.Equal(500.0, tenant.getAvailableRU(now))
+	re.Equal(0.0, teamB.getAvailableRU(now))
+	re.Equal(6500.0, dept.getAvailableRU(now))
+	// the group doesn't borrow if its own tokens are enough.
+	m.borrowRU(tenant, now, 100)
+	re.Equal(6500.0, dept.getAvailableRU(now))
+
+	re.NoError(m.SetResourceGroupParent("tenant", &GroupHierarchy{}))
+	re.NoError(m.DeleteResourceGroup("team-b"))
+	m.borrowRU(tenant, now, 1000)
+	re.Equal(500.0, tenant.getAvailableRU(now))
+}
+
+func TestLendRUPerPeriod(t *testing.T) {
+	re := require.New(t)
+	m := &Manager{
+		groups:  make(map[string]*ResourceGroup),
+		storage: storage.NewStorageWithMemoryBackend(),
+	}
+	re.NoError(m.AddResourceGroup(newTestRUGroup("dept", 1000, 10000, 8000)))
+	dept := m.GetMutableResourceGroup("dept")
+	now := time.Now()
+	dept.RUSettings.RU.LastUpdate = &now
+	lastBurstTokens := dept.RUSettings.RU.lastBurstTokens
+
+	// the repeated loans in a period don't exceed the share of the tokens at
+	// the start of the period, and the state of the bucket is left alone.
+	var lent float64
+	for i := 0; i < 10; i++ {
+		lent += dept.lendRU(now, "team-a", 1000, 0.5)
+	}
+	re.Equal(4000.0, lent)
+	re.Equal(4000.0, dept.getAvailableRU(now))
+	re.Equal(2000.0, dept.lendRU(now, "team-b", 2000, 0.5))
+	re.Equal(now, *dept.RUSettings.RU.LastUpdate)
+	re.Equal(lastBurstTokens, dept.RUSettings.RU.lastBurstTokens)
+
+	// the quota is renewed in the next period.
+	next := now.Add(loanPeriod)
+	re.Equal(3000.0, dept.getAvailableRU(next))
+	re.Equal(1500.0, dept.lendRU(next, "team-a", 2000, 0.5))
+	re.Equal(1500.0, dept.getAvailableRU(next))
+}
@@ -17,6 +17,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"math"
 	"sort"
 	"strings"
@@ -67,6 +68,13 @@ type Manager struct {
 	}
 	// record update time of each resource group
 	consumptionRecord map[consumptionRecordKey]time.Time
+	// metering is used to export the consumption to the external sink,
+	// it's nil if the metering is disabled.
+	metering *meteringExporter
+	// tokenLoad records the load of the token requests.
+	tokenLoad tokenLoadRecorder
+	// shareSums is the sum of the shares of the children of each group.
+	shareSums map[string]uint64
 }
 
 type consumptionRecordKey struct {
@@ -94,6 +102,11 @@ func NewManager[T ConfigProvider](srv bs.Server) *Manager {
 		}, defaultConsumptionChanSize),
 		consumptionRecord: make(map[consumptionRecordKey]time.Time),
 	}
+	if p, ok := srv.(MeteringConfigProvider); ok {
+		if cfg := p.GetMeteringConfig(); cfg.IsEnabled() {
+			m.metering = newMeteringExporter(cfg, newHTTPMeteringSink(cfg.SinkURL))
+		}
+	}
 	// The first initialization after the server is started.
 	srv.AddStartCallback(func() {
 		log.Info("resource group manager starts to initialize", zap.String("name", srv.Name()))
@@ -157,6 +170,12 @@ func (m *Manager) Init(ctx context.Context) error {
 	if err := m.storage.LoadResourceGroupStates(tokenHandler); err != nil {
 		return err
 	}
+	if err := m.loadHierarchies(); err != nil {
+		return err
+	}
+	if err := m.loadBypasses(); err != nil {
+		return err
+	}
 
 	// Add default group if it's not inited.
 	if _, ok := m.groups[reservedDefaultGroupName]; !ok {
@@ -180,6 +199,9 @@ func (m *Manager) Init(ctx context.Context) error {
 
 	// Start the background metrics flusher.
 	go m.backgroundMetricsFlush(ctx)
+	if m.metering != nil {
+		go m.metering.run(ctx)
+	}
 	go func() {
 		defer logutil.LogPanic()
 		m.persistLoop(ctx)
@@ -244,6 +266,12 @@ func (m *Manager) AddResourceGroup(grouppb *rmpb.ResourceGroup) error {
 	group := FromProtoResourceGroup(grouppb)
 	m.Lock()
 	defer m.Unlock()
+	// the hierarchy and the bypass setting are not a part of the settings, keep
+	// them when the group is put again.
+	if curGroup, ok := m.groups[group.Name]; ok {
+		group.Parent, group.Shares = curGroup.Parent, curGroup.Shares
+		group.Bypass = curGroup.Bypass
+	}
 	if err := group.persistSettings(m.storage); err != nil {
 		return err
 	}
@@ -278,12 +306,29 @@ func (m *Manager) DeleteResourceGroup(name string) error {
 	if name == reservedDefaultGroupName {
 		return errs.ErrDeleteReservedGroup
 	}
+	m.Lock()
+	defer m.Unlock()
+	if m.hasChildren(name) {
+		return errs.ErrInvalidGroupHierarchy.FastGenByArgs(fmt.Sprintf("the group %s has child groups", name))
+	}
 	if err := m.storage.DeleteResourceGroupSetting(name); err != nil {
 		return err
 	}
-	m.Lock()
+	if group, ok := m.groups[name]; ok && group.Parent != "" {
+		if err := m.storage.DeleteResourceGroupHierarchy(name); err != nil {
+			return err
+		}
+	}
+	if group, ok := m.groups[name]; ok && group.Bypass {
+		if err := m.storage.DeleteResourceGroupBypass(name); err != nil {
+			return err
+		}
+	}
 	delete(m.groups, name)
-	m.Unlock()
+	m.rebuildShareSums()
+	m.tokenLoad.delete(name)
+	borrowedRequestUnit.DeleteLabelValues(name)
+	bypassedRequestUnit.DeleteLabelValues(name)
 	return nil
 }
 
@@ -434,9 +479,17 @@ func (m *Manager) backgroundMetricsFlush(ctx context.Context) {
 			}
 
 			m.consumptionRecord[consumptionRecordKey{name: name, ruType: ruLabelType}] = time.Now()
+			rg := m.GetMutableResourceGroup(name)
+			isBypassed := rg != nil && rg.isBypassed()
+			if isBypassed {
+				bypassedRequestUnit.WithLabelValues(name).Add(consumption.RRU + consumption.WRU)
+			}
+			if m.metering != nil {
+				m.metering.record(name, consumption, consumptionInfo.isBackground, isBypassed)
+			}
 
 			// TODO: maybe we need to distinguish background ru.
-			if rg := m.GetMutableResourceGroup(name); rg != nil {
+			if rg != nil {
 				rg.UpdateRUConsumption(consumptionInfo.Consumption)
 			}
 		case <-cleanUpTicker.C:
@@ -0,0 +1,226 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pingcap/errors"
+	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/utils/logutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+// maxMeteringBatchSize is the max number of the records pushed to the sink in one batch.
+const maxMeteringBatchSize = 1000
+
+// MeteringRecord is the RU consumption of a resource group within a window.
+// The ID is stable across the retries, so the sink can deduplicate the records
+// which are delivered more than once.
+type MeteringRecord struct {
+	ID               string    `json:"id"`
+	ResourceGroup    string    `json:"resource_group"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	RRU              float64   `json:"rru"`
+	WRU              float64   `json:"wru"`
+	ReadBytes        float64   `json:"read_bytes"`
+	WriteBytes       float64   `json:"write_bytes"`
+	TotalCPUTimeMs   float64   `json:"total_cpu_time_ms"`
+	SQLLayerCPUMs    float64   `json:"sql_layer_cpu_time_ms"`
+	ReadRPCCount     float64   `json:"read_rpc_count"`
+	WriteRPCCount    float64   `json:"write_rpc_count"`
+	BackgroundRRU    float64   `json:"background_rru"`
+	BackgroundWRU    float64   `json:"background_wru"`
+	BypassedRRU      float64   `json:"bypassed_rru"`
+	BypassedWRU      float64   `json:"bypassed_wru"`
+	ConsumptionCount uint64    `json:"consumption_count"`
+}
+
+// MeteringSink is the external system which receives the metering records, e.g.
+// an HTTP endpoint or a message queue producer. Send should only return nil
+// after the records are durably accepted, otherwise they will be retried.
+type MeteringSink interface {
+	Send(ctx context.Context, records []*MeteringRecord) error
+}
+
+// MeteringConfigProvider is implemented by the servers which support exporting
+// the metering records. It's optional for the servers creating the Manager.
+type MeteringConfigProvider interface {
+	GetMeteringConfig() *MeteringConfig
+}
+
+// httpMeteringSink pushes the records to an HTTP endpoint as a JSON array.
+type httpMeteringSink struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPMeteringSink(url string) *httpMeteringSink {
+	return &httpMeteringSink{url: url, client: &http.Client{}}
+}
+
+// Send implements the MeteringSink interface.
+func (s *httpMeteringSink) Send(ctx context.Context, records []*MeteringRecord) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("metering sink responds with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// meteringExporter aggregates the consumption of each resource group within a
+// window and pushes the records to the sink with at-least-once delivery. The
+// records which fail to be pushed are buffered in memory and retried in the
+// next flush, the oldest ones are dropped once the buffer is full.
+type meteringExporter struct {
+	syncutil.Mutex
+	sink        MeteringSink
+	cfg         *MeteringConfig
+	windowStart time.Time
+	window      map[string]*MeteringRecord
+	pending     []*MeteringRecord
+}
+
+func newMeteringExporter(cfg *MeteringConfig, sink MeteringSink) *meteringExporter {
+	return &meteringExporter{
+		sink:        sink,
+		cfg:         cfg,
+		windowStart: time.Now(),
+		window:      make(map[string]*MeteringRecord),
+	}
+}
+
+// record adds the consumption to the current window.
+func (e *meteringExporter) record(name string, consumption *rmpb.Consumption, isBackground, isBypassed bool) {
+	e.Lock()
+	defer e.Unlock()
+	r, ok := e.window[name]
+	if !ok {
+		r = &MeteringRecord{ResourceGroup: name}
+		e.window[name] = r
+	}
+	r.RRU += consumption.RRU
+	r.WRU += consumption.WRU
+	r.ReadBytes += consumption.ReadBytes
+	r.WriteBytes += consumption.WriteBytes
+	r.TotalCPUTimeMs += consumption.TotalCpuTimeMs
+	r.SQLLayerCPUMs += consumption.SqlLayerCpuTimeMs
+	r.ReadRPCCount += consumption.KvReadRpcCount
+	r.WriteRPCCount += consumption.KvWriteRpcCount
+	if isBackground {
+		r.BackgroundRRU += consumption.RRU
+		r.BackgroundWRU += consumption.WRU
+	}
+	if isBypassed {
+		r.BypassedRRU += consumption.RRU
+		r.BypassedWRU += consumption.WRU
+	}
+	r.ConsumptionCount++
+}
+
+// closeWindow moves the records of the current window to the pending buffer.
+func (e *meteringExporter) closeWindow(now time.Time) {
+	e.Lock()
+	defer e.Unlock()
+	for name, r := range e.window {
+		r.ID = fmt.Sprintf("%s-%d", name, e.windowStart.UnixNano())
+		r.StartTime = e.windowStart
+		r.EndTime = now
+		e.pending = append(e.pending, r)
+	}
+	e.window = make(map[string]*MeteringRecord)
+	e.windowStart = now
+	if dropped := len(e.pending) - e.cfg.BufferSize; dropped > 0 {
+		log.Warn("metering buffer is full, drop the oldest records", zap.Int("dropped", dropped))
+		meteringRecordCounter.WithLabelValues("dropped").Add(float64(dropped))
+		e.pending = append([]*MeteringRecord(nil), e.pending[dropped:]...)
+	}
+	meteringPendingGauge.Set(float64(len(e.pending)))
+}
+
+// flush closes the current window and pushes the pending records to the sink
+// in batches. It stops at the first failure and keeps the rest for the retry.
+func (e *meteringExporter) flush(ctx context.Context, now time.Time) error {
+	e.closeWindow(now)
+	for {
+		e.Lock()
+		n := len(e.pending)
+		if n > maxMeteringBatchSize {
+			n = maxMeteringBatchSize
+		}
+		batch := e.pending[:n]
+		e.Unlock()
+		if n == 0 {
+			return nil
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, e.cfg.SendTimeout.Duration)
+		err := e.sink.Send(sendCtx, batch)
+		cancel()
+		if err != nil {
+			meteringRecordCounter.WithLabelValues("failed").Add(float64(n))
+			return err
+		}
+		meteringRecordCounter.WithLabelValues("sent").Add(float64(n))
+		e.Lock()
+		e.pending = e.pending[n:]
+		meteringPendingGauge.Set(float64(len(e.pending)))
+		e.Unlock()
+	}
+}
+
+func (e *meteringExporter) run(ctx context.Context) {
+	defer logutil.LogPanic()
+	ticker := time.NewTicker(e.cfg.FlushInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// Try to push the last window before exiting.
+			flushCtx, cancel := context.WithTimeout(context.Background(), e.cfg.SendTimeout.Duration)
+			if err := e.flush(flushCtx, time.Now()); err != nil {
+				log.Warn("failed to flush the metering records before exiting", zap.Error(err))
+			}
+			cancel()
+			return
+		case now := <-ticker.C:
+			if err := e.flush(ctx, now); err != nil {
+				log.Warn("failed to push the metering records, will retry later", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rmpb "github.com/pingcap/kvproto/pkg/resource_manager"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"github.com/tikv/pd/pkg/utils/typeutil"
+)
+
+func TestMeteringExporter(t *testing.T) {
+	re := require.New(t)
+	var (
+		available atomic.Bool
+		mu        syncutil.Mutex
+		delivered []*MeteringRecord
+	)
+	// takeDelivered returns the records delivered to the sink and resets them.
+	takeDelivered := func() []*MeteringRecord {
+		mu.Lock()
+		defer mu.Unlock()
+		records := delivered
+		delivered = nil
+		return records
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var records []*MeteringRecord
+		if err := json.NewDecoder(r.Body).Decode(&records); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, records...)
+	}))
+	defer ts.Close()
+
+	cfg := &MeteringConfig{
+		SinkURL:     ts.URL,
+		BufferSize:  2,
+		SendTimeout: typeutil.NewDuration(time.Second),
+	}
+	e := newMeteringExporter(cfg, newHTTPMeteringSink(cfg.SinkURL))
+	ctx := context.Background()
+	now := time.Now()
+
+	// The records are kept when the sink is unavailable.
+	e.record("g1", &rmpb.Consumption{RRU: 1, WRU: 2}, false, false)
+	e.record("g1", &rmpb.Consumption{RRU: 3, WRU: 4}, true, false)
+	re.Error(e.flush(ctx, now))
+	re.Len(e.pending, 1)
+
+	// The oldest record is dropped once the buffer is full.
+	e.record("g2", &rmpb.Consumption{RRU: 5}, false, false)
+	re.Error(e.flush(ctx, now.Add(time.Minute)))
+	e.record("g3", &rmpb.Consumption{WRU: 6}, false, false)
+	re.Error(e.flush(ctx, now.Add(2*time.Minute)))
+	re.Len(e.pending, 2)
+
+	// The buffered records are delivered once the sink is back.
+	available.Store(true)
+	re.NoError(e.flush(ctx, now.Add(3*time.Minute)))
+	re.Empty(e.pending)
+	received := takeDelivered()
+	re.Len(received, 2)
+	re.Equal("g2", received[0].ResourceGroup)
+	re.Equal(5.0, received[0].RRU)
+	re.Equal("g3", received[1].ResourceGroup)
+	re.NotEqual(received[0].ID, received[1].ID)
+
+	// Check the aggregation within a window.
+	e.record("g1", &rmpb.Consumption{RRU: 1, WRU: 2}, false, false)
+	e.record("g1", &rmpb.Consumption{RRU: 3, WRU: 4}, true, true)
+	re.NoError(e.flush(ctx, now.Add(4*time.Minute)))
+	received = takeDelivered()
+	re.Len(received, 1)
+	re.Equal(4.0, received[0].RRU)
+	re.Equal(6.0, received[0].WRU)
+	re.Equal(3.0, received[0].BackgroundRRU)
+	re.Equal(4.0, received[0].BypassedWRU)
+	re.Equal(uint64(2), received[0].ConsumptionCount)
+	re.Equal(now.Add(3*time.Minute).Unix(), received[0].StartTime.Unix())
+}
@@ -20,6 +20,7 @@ const (
 	namespace                 = "resource_manager"
 	serverSubsystem           = "server"
 	ruSubsystem               = "resource_unit"
+	meteringSubsystem         = "metering"
 	resourceSubsystem         = "resource"
 	resourceGroupNameLabel    = "name"
 	typeLabel                 = "type"
@@ -116,6 +117,46 @@ var (
 			Name:      "available_ru",
 			Help:      "Counter of the available RU for all resource groups.",
 		}, []string{resourceGroupNameLabel, newResourceGroupNameLabel})
+
+	borrowedRequestUnit = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: ruSubsystem,
+			Name:      "borrowed_request_unit_sum",
+			Help:      "Counter of the request units borrowed from the parent groups.",
+		}, []string{newResourceGroupNameLabel})
+
+	bypassedRequestUnit = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: ruSubsystem,
+			Name:      "bypassed_request_unit_sum",
+			Help:      "Counter of the request units consumed by the groups bypassing the throttling.",
+		}, []string{newResourceGroupNameLabel})
+
+	tokenGrantDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: serverSubsystem,
+			Name:      "token_grant_duration_seconds",
+			Help:      "Bucketed histogram of the duration to grant the tokens for all resource groups.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 16), // 0.1ms ~ 3.2s
+		}, []string{newResourceGroupNameLabel})
+
+	meteringRecordCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: meteringSubsystem,
+			Name:      "records_total",
+			Help:      "Counter of the metering records which are sent, failed to be sent or dropped.",
+		}, []string{typeLabel})
+	meteringPendingGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: meteringSubsystem,
+			Name:      "pending_records",
+			Help:      "The number of the metering records buffered locally and waiting to be sent.",
+		})
 )
 
 func init() {
@@ -130,4 +171,9 @@ func init() {
 	prometheus.MustRegister(availableRUCounter)
 	prometheus.MustRegister(readRequestUnitMaxPerSecCost)
 	prometheus.MustRegister(writeRequestUnitMaxPerSecCost)
+	prometheus.MustRegister(meteringRecordCounter)
+	prometheus.MustRegister(meteringPendingGauge)
+	prometheus.MustRegister(tokenGrantDuration)
+	prometheus.MustRegister(borrowedRequestUnit)
+	prometheus.MustRegister(bypassedRequestUnit)
 }
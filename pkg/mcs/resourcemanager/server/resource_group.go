@@ -40,6 +40,17 @@ type ResourceGroup struct {
 	Background *rmpb.BackgroundSettings `json:"background_settings,omitempty"`
 	// total ru consumption
 	RUConsumption *rmpb.Consumption `json:"ru_consumption,omitempty"`
+	// Parent is the group whose RU budget can be borrowed when the tokens of
+	// this group run out, and Shares is the weight of the borrowing relative
+	// to the sibling groups.
+	Parent string `json:"parent,omitempty"`
+	Shares uint64 `json:"shares,omitempty"`
+	// Bypass means the requests of the group are not throttled, while their
+	// RU consumption is still accounted and reported.
+	Bypass bool `json:"bypass,omitempty"`
+	// loans records the tokens lent to the children, it's only used by the
+	// group with children.
+	loans *loanLedger
 }
 
 // RequestUnitSettings is the definition of the RU settings.
@@ -86,6 +97,9 @@ func (rg *ResourceGroup) Clone(withStats bool) *ResourceGroup {
 		Mode:       rg.Mode,
 		Priority:   rg.Priority,
 		RUSettings: rg.RUSettings.Clone(),
+		Parent:     rg.Parent,
+		Shares:     rg.Shares,
+		Bypass:     rg.Bypass,
 	}
 	if rg.Runaway != nil {
 		newRG.Runaway = proto.Clone(rg.Runaway).(*rmpb.RunawaySettings)
@@ -176,6 +190,9 @@ func (rg *ResourceGroup) RequestRU(
 	if rg.RUSettings == nil || rg.RUSettings.RU.Settings == nil {
 		return nil
 	}
+	if rg.Bypass {
+		return rg.grantBypassedRU(requiredToken)
+	}
 	tb, trickleTimeMs := rg.RUSettings.RU.request(now, requiredToken, targetPeriodMs, clientUniqueID)
 	return &rmpb.GrantedRUTokenBucket{GrantedTokens: tb, TrickleTimeMs: trickleTimeMs}
 }
@@ -215,9 +215,7 @@ func (s *Server) Close() {
 	}
 
 	log.Info("closing resource manager server ...")
-	if err := s.serviceRegister.Deregister(); err != nil {
-		log.Error("failed to deregister the service", errs.ZapError(err))
-	}
+	s.StopServing(s.serviceRegister.Deregister, s.participant.ResetLeader)
 	utils.StopHTTPServer(s)
 	utils.StopGRPCServer(s)
 	s.GetListener().Close()
@@ -243,6 +241,17 @@ func (s *Server) GetControllerConfig() *ControllerConfig {
 	return &s.cfg.Controller
 }
 
+// GetMeteringConfig returns the metering config.
+func (s *Server) GetMeteringConfig() *MeteringConfig {
+	return &s.cfg.Metering
+}
+
+// ResignPrimary resigns the primary of the resource manager service, the
+// servers including this one campaign for the primary again.
+func (s *Server) ResignPrimary() {
+	s.participant.ResetLeader()
+}
+
 // IsServing returns whether the server is the leader, if there is embedded etcd, or the primary otherwise.
 func (s *Server) IsServing() bool {
 	return !s.IsClosed() && s.participant.IsLeader()
@@ -416,7 +425,7 @@ func CreateServerWrapper(cmd *cobra.Command, args []string) {
 
 	var sig os.Signal
 	go func() {
-		sig = <-sc
+		sig = svr.WaitForShutdown(sc, svr.Close)
 		cancel()
 	}()
 
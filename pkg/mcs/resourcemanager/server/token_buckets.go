@@ -91,6 +91,9 @@ type GroupTokenBucketState struct {
 	tokenSlots                 map[uint64]*TokenSlot
 	clientConsumptionTokensSum float64
 	lastBurstTokens            float64
+	// loanTokens is the net tokens lent to the child groups since the last
+	// update, it's negative if more tokens are borrowed from the parent.
+	loanTokens float64
 
 	LastUpdate  *time.Time `json:"last_update,omitempty"`
 	Initialized bool       `json:"initialized"`
@@ -313,8 +316,9 @@ func (gtb *GroupTokenBucket) updateTokens(now time.Time, burstLimit int64, clien
 		gtb.init(now, clientUniqueID)
 	} else if burst := float64(burstLimit); burst > 0 {
 		if delta := now.Sub(*gtb.LastUpdate); delta > 0 {
-			elapseTokens = float64(gtb.Settings.GetFillRate())*delta.Seconds() + gtb.lastBurstTokens
+			elapseTokens = float64(gtb.Settings.GetFillRate())*delta.Seconds() + gtb.lastBurstTokens - gtb.loanTokens
 			gtb.lastBurstTokens = 0
+			gtb.loanTokens = 0
 			gtb.Tokens += elapseTokens
 		}
 		if gtb.Tokens > burst {
@@ -351,6 +355,48 @@ func (gtb *GroupTokenBucket) request(now time.Time,
 	return res, trickleDuration
 }
 
+// available returns the tokens which can be used now, including the tokens
+// filled since the last update. It's infinite if the capacity is unlimited,
+// and it's zero if the tokens are not tracked by the server.
+func (gtb *GroupTokenBucket) available(now time.Time) float64 {
+	burstLimit := gtb.Settings.GetBurstLimit()
+	switch {
+	case gtb.Settings == nil || burstLimit == 0:
+		return 0
+	case burstLimit < 0:
+		return math.Inf(1)
+	}
+	tokens := gtb.Tokens + gtb.lastBurstTokens - gtb.loanTokens
+	if gtb.LastUpdate != nil {
+		if delta := now.Sub(*gtb.LastUpdate); delta > 0 {
+			tokens += float64(gtb.Settings.GetFillRate()) * delta.Seconds()
+		}
+	}
+	return math.Min(tokens, float64(burstLimit))
+}
+
+// lend takes at most the given tokens away for a child group and returns the
+// lent tokens. The lent tokens are deducted from the slots at the next update,
+// so the slots of the group are kept balanced.
+func (gtb *GroupTokenBucket) lend(now time.Time, tokens float64) float64 {
+	available := gtb.available(now)
+	if math.IsInf(available, 1) {
+		return tokens
+	}
+	if available <= 0 {
+		return 0
+	}
+	lent := math.Min(tokens, available)
+	gtb.loanTokens += lent
+	return lent
+}
+
+// borrow adds the tokens borrowed from the parent group, which are assigned
+// to the slots at the next update.
+func (gtb *GroupTokenBucket) borrow(tokens float64) {
+	gtb.loanTokens -= tokens
+}
+
 func (ts *TokenSlot) assignSlotTokens(requiredToken float64, targetPeriodMs uint64) (*rmpb.TokenBucket, int64) {
 	var res rmpb.TokenBucket
 	burstLimit := ts.settings.GetBurstLimit()
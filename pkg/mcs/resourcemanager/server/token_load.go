@@ -0,0 +1,180 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+// tokenLoadWindow is the window to calculate the rates and the latencies of
+// the token requests.
+const tokenLoadWindow = 10 * time.Second
+
+// TokenServerLoad is the load of the token server, it tells whether the token
+// server itself is the bottleneck of the token requests.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type TokenServerLoad struct {
+	// WindowSeconds is the window to calculate the rates and the latencies.
+	WindowSeconds float64 `json:"window_seconds"`
+	// ConsumptionQueueDepth is the number of the consumptions waiting to be
+	// recorded, the token requests are blocked once the queue is full.
+	ConsumptionQueueDepth    int               `json:"consumption_queue_depth"`
+	ConsumptionQueueCapacity int               `json:"consumption_queue_capacity"`
+	Groups                   []*TokenGroupLoad `json:"groups"`
+}
+
+// TokenGroupLoad is the load of the token requests of a resource group.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type TokenGroupLoad struct {
+	Name string `json:"name"`
+	// RequestRate is the number of the token requests per second.
+	RequestRate float64 `json:"request_rate"`
+	// GrantedTokenRate is the number of the granted tokens per second.
+	GrantedTokenRate  float64 `json:"granted_token_rate"`
+	AvgGrantLatencyMs float64 `json:"avg_grant_latency_ms"`
+	MaxGrantLatencyMs float64 `json:"max_grant_latency_ms"`
+	// QueueDepth is the number of the token requests being granted, most of
+	// them are waiting for the lock of the resource group.
+	QueueDepth int64 `json:"queue_depth"`
+}
+
+type tokenLoadStats struct {
+	start        time.Time
+	requests     uint64
+	tokens       float64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+}
+
+// tokenGroupLoad records the token requests of a resource group in the
+// current window, and keeps the last window for reporting.
+type tokenGroupLoad struct {
+	queueDepth atomic.Int64
+
+	mu struct {
+		syncutil.Mutex
+		current tokenLoadStats
+		last    tokenLoadStats
+	}
+}
+
+// rotateLocked starts a new window if the current one is expired.
+func (l *tokenGroupLoad) rotateLocked(now time.Time) {
+	if now.Sub(l.mu.current.start) < tokenLoadWindow {
+		return
+	}
+	if now.Sub(l.mu.current.start) < 2*tokenLoadWindow {
+		l.mu.last = l.mu.current
+	} else {
+		// no requests in the last window.
+		l.mu.last = tokenLoadStats{start: now.Add(-tokenLoadWindow)}
+	}
+	l.mu.current = tokenLoadStats{start: now}
+}
+
+func (l *tokenGroupLoad) record(now time.Time, tokens float64, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rotateLocked(now)
+	stats := &l.mu.current
+	stats.requests++
+	stats.tokens += tokens
+	stats.totalLatency += latency
+	if latency > stats.maxLatency {
+		stats.maxLatency = latency
+	}
+}
+
+func (l *tokenGroupLoad) report(name string, now time.Time) *TokenGroupLoad {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rotateLocked(now)
+	last := l.mu.last
+	load := &TokenGroupLoad{
+		Name:       name,
+		QueueDepth: l.queueDepth.Load(),
+	}
+	if last.requests > 0 {
+		seconds := tokenLoadWindow.Seconds()
+		load.RequestRate = float64(last.requests) / seconds
+		load.GrantedTokenRate = last.tokens / seconds
+		// Keep the fractions, most grants take far less than a millisecond.
+		load.AvgGrantLatencyMs = durationToMs(last.totalLatency) / float64(last.requests)
+		load.MaxGrantLatencyMs = durationToMs(last.maxLatency)
+	}
+	return load
+}
+
+func durationToMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// tokenLoadRecorder records the load of the token requests of all resource groups.
+type tokenLoadRecorder struct {
+	groups sync.Map // name -> *tokenGroupLoad
+}
+
+func (r *tokenLoadRecorder) getOrCreate(name string) *tokenGroupLoad {
+	if load, ok := r.groups.Load(name); ok {
+		return load.(*tokenGroupLoad)
+	}
+	load, _ := r.groups.LoadOrStore(name, &tokenGroupLoad{})
+	return load.(*tokenGroupLoad)
+}
+
+// begin marks a token request of the group is being granted, the returned
+// function should be called with the granted tokens once it's done.
+func (r *tokenLoadRecorder) begin(name string) func(tokens float64) {
+	load := r.getOrCreate(name)
+	load.queueDepth.Add(1)
+	start := time.Now()
+	return func(tokens float64) {
+		load.queueDepth.Add(-1)
+		now := time.Now()
+		latency := now.Sub(start)
+		tokenGrantDuration.WithLabelValues(name).Observe(latency.Seconds())
+		load.record(now, tokens, latency)
+	}
+}
+
+func (r *tokenLoadRecorder) delete(name string) {
+	r.groups.Delete(name)
+	tokenGrantDuration.DeleteLabelValues(name)
+}
+
+func (r *tokenLoadRecorder) report(now time.Time) []*TokenGroupLoad {
+	groups := make([]*TokenGroupLoad, 0)
+	r.groups.Range(func(key, value any) bool {
+		groups = append(groups, value.(*tokenGroupLoad).report(key.(string), now))
+		return true
+	})
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// GetTokenServerLoad returns the load of the token server.
+func (m *Manager) GetTokenServerLoad() *TokenServerLoad {
+	return &TokenServerLoad{
+		WindowSeconds:            tokenLoadWindow.Seconds(),
+		ConsumptionQueueDepth:    len(m.consumptionDispatcher),
+		ConsumptionQueueCapacity: cap(m.consumptionDispatcher),
+		Groups:                   m.tokenLoad.report(time.Now()),
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenLoad(t *testing.T) {
+	re := require.New(t)
+	r := &tokenLoadRecorder{}
+	load := r.getOrCreate("test")
+	now := time.Now()
+	load.mu.current.start = now
+
+	// the current window is not reported until it's finished.
+	load.record(now, 100, 10*time.Millisecond)
+	load.record(now.Add(time.Second), 300, 30*time.Millisecond)
+	report := r.report(now.Add(time.Second))
+	re.Len(report, 1)
+	re.Zero(report[0].RequestRate)
+
+	report = r.report(now.Add(tokenLoadWindow))
+	re.Equal("test", report[0].Name)
+	re.InDelta(2/tokenLoadWindow.Seconds(), report[0].RequestRate, 1e-9)
+	re.InDelta(400/tokenLoadWindow.Seconds(), report[0].GrantedTokenRate, 1e-9)
+	re.Equal(20.0, report[0].AvgGrantLatencyMs)
+	re.Equal(30.0, report[0].MaxGrantLatencyMs)
+
+	// the sub-millisecond latencies are kept.
+	load.record(now.Add(tokenLoadWindow), 100, 100*time.Microsecond)
+	load.record(now.Add(tokenLoadWindow), 100, 400*time.Microsecond)
+	report = r.report(now.Add(2 * tokenLoadWindow))
+	re.InDelta(0.25, report[0].AvgGrantLatencyMs, 1e-9)
+	re.InDelta(0.4, report[0].MaxGrantLatencyMs, 1e-9)
+
+	// the rates fall back to zero if there are no requests.
+	report = r.report(now.Add(3 * tokenLoadWindow))
+	re.Zero(report[0].RequestRate)
+
+	// the queue depth is the number of the requests being granted.
+	done := r.begin("test")
+	re.Equal(int64(1), r.report(time.Now())[0].QueueDepth)
+	done(100)
+	re.Zero(r.report(time.Now())[0].QueueDepth)
+
+	r.delete("test")
+	re.Empty(r.report(time.Now()))
+}
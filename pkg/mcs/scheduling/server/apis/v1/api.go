@@ -16,10 +16,12 @@ package apis
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -29,6 +31,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/errs"
 	scheserver "github.com/tikv/pd/pkg/mcs/scheduling/server"
 	mcsutils "github.com/tikv/pd/pkg/mcs/utils"
@@ -94,7 +97,7 @@ func NewService(srv *scheserver.Service) *Service {
 	apiHandlerEngine := gin.New()
 	apiHandlerEngine.Use(gin.Recovery())
 	apiHandlerEngine.Use(cors.Default())
-	apiHandlerEngine.Use(gzip.Gzip(gzip.DefaultCompression))
+	apiHandlerEngine.Use(gzipExcept(APIPathPrefix + "/operators/progress"))
 	apiHandlerEngine.Use(func(c *gin.Context) {
 		c.Set(multiservicesapi.ServiceContextKey, srv.Server)
 		c.Set(handlerKey, handler.NewHandler(&server{srv.Server}))
@@ -119,17 +122,44 @@ func NewService(srv *scheserver.Service) *Service {
 	s.RegisterHotspotRouter()
 	s.RegisterRegionsRouter()
 	s.RegisterStoresRouter()
+	s.RegisterStatsRouter()
+	s.RegisterCompatibleRouter()
+	s.RegisterDebugRouter()
 	return s
 }
 
+// gzipExcept compresses the responses except the given paths, which stream the
+// responses since the gzip writer can't be flushed.
+func gzipExcept(paths ...string) gin.HandlerFunc {
+	compress := gzip.Gzip(gzip.DefaultCompression)
+	return func(c *gin.Context) {
+		for _, path := range paths {
+			if c.Request.URL.Path == path {
+				return
+			}
+		}
+		compress(c)
+	}
+}
+
 // RegisterAdminRouter registers the router of the admin handler.
 func (s *Service) RegisterAdminRouter() {
 	router := s.root.Group("admin")
 	router.PUT("/log", changeLogLevel)
+	router.POST("/shutdown", shutdown)
 	router.DELETE("cache/regions", deleteAllRegionCache)
 	router.DELETE("cache/regions/:id", deleteRegionCacheByID)
 }
 
+// RegisterDebugRouter registers the router of the debug handler. The debug
+// API reports the state of this server, so it's not redirected to the primary,
+// and only the authenticated clients can access it.
+func (s *Service) RegisterDebugRouter() {
+	router := s.apiHandlerEngine.Group(APIPathPrefix + "/debug")
+	router.Use(debugAuthenticator(s.srv.GetTLSConfig()))
+	router.GET("/operators", getOperatorControllerState)
+}
+
 // RegisterSchedulersRouter registers the router of the schedulers handler.
 func (s *Service) RegisterSchedulersRouter() {
 	router := s.root.Group("schedulers")
@@ -137,6 +167,7 @@ func (s *Service) RegisterSchedulersRouter() {
 	router.GET("/diagnostic/:name", getDiagnosticResult)
 	router.GET("/config", getSchedulerConfig)
 	router.GET("/config/:name/list", getSchedulerConfigByName)
+	router.GET("/:name/dry-run", dryRunScheduler)
 	// TODO: in the future, we should split pauseOrResumeScheduler to two different APIs.
 	// And we need to do one-to-two forwarding in the API middleware.
 	router.POST("/:name", pauseOrResumeScheduler)
@@ -145,6 +176,8 @@ func (s *Service) RegisterSchedulersRouter() {
 // RegisterCheckersRouter registers the router of the checkers handler.
 func (s *Service) RegisterCheckersRouter() {
 	router := s.root.Group("checkers")
+	router.GET("/patrol-progress", getPatrolRegionsProgress)
+	router.GET("/merge-checker/diagnostic/:region_id", diagnoseMergeTargets)
 	router.GET("/:name", getCheckerByName)
 	router.POST("/:name", pauseOrResumeChecker)
 }
@@ -157,6 +190,7 @@ func (s *Service) RegisterHotspotRouter() {
 	router.GET("/regions/history", getHistoryHotRegions)
 	router.GET("/stores", getHotStores)
 	router.GET("/buckets", getHotBuckets)
+	router.GET("/analysis", analyzeHotRegions)
 }
 
 // RegisterOperatorsRouter registers the router of the operators handler.
@@ -167,7 +201,10 @@ func (s *Service) RegisterOperatorsRouter() {
 	router.DELETE("", deleteOperators)
 	router.GET("/:id", getOperatorByRegion)
 	router.DELETE("/:id", deleteOperatorByRegion)
+	router.POST("/:id/placement", setRegionPlacement)
 	router.GET("/records", getOperatorRecords)
+	router.GET("/progress", watchOperatorProgress)
+	router.GET("/leader-transfer-blacklist", getLeaderTransferBlacklist)
 }
 
 // RegisterStoresRouter registers the router of the stores handler.
@@ -177,6 +214,12 @@ func (s *Service) RegisterStoresRouter() {
 	router.GET("/:id", getStoreByID)
 }
 
+// RegisterStatsRouter registers the router of the stats handler.
+func (s *Service) RegisterStatsRouter() {
+	router := s.root.Group("stats")
+	router.GET("/zone-quorum", getZoneQuorumStatus)
+}
+
 // RegisterRegionsRouter registers the router of the regions handler.
 func (s *Service) RegisterRegionsRouter() {
 	router := s.root.Group("regions")
@@ -185,11 +228,30 @@ func (s *Service) RegisterRegionsRouter() {
 	router.GET("/count", getRegionCount)
 	router.POST("/accelerate-schedule", accelerateRegionsScheduleInRange)
 	router.POST("/accelerate-schedule/batch", accelerateRegionsScheduleInRanges)
+	router.POST("/merge", mergeRegionsInRange)
+	router.GET("/merge", getRangeMergeJobs)
+	router.DELETE("/merge/:id", cancelRangeMergeJob)
 	router.POST("/scatter", scatterRegions)
 	router.POST("/split", splitRegions)
 	router.GET("/replicated", checkRegionsReplicated)
 }
 
+// RegisterCompatibleRouter registers the common read-only routes of the PD API
+// at the same paths as apiv1, so the tools which work with the PD API can also
+// work with the scheduling server directly.
+func (s *Service) RegisterCompatibleRouter() {
+	router := s.apiHandlerEngine.Group(apiutil.CorePath)
+	router.Use(multiservicesapi.ServiceRedirector())
+	router.GET("/regions", getAllRegions)
+	router.GET("/regions/count", getRegionCount)
+	router.GET("/region/id/:id", getRegionByID)
+	router.GET("/region/key/:key", getRegionByKey)
+	router.GET("/stores", getAllStores)
+	router.GET("/store/:id", getStoreByID)
+	router.GET("/operators", getOperators)
+	router.GET("/operators/:id", getOperatorByRegion)
+}
+
 // RegisterConfigRouter registers the router of the config handler.
 func (s *Service) RegisterConfigRouter() {
 	router := s.root.Group("config")
@@ -201,6 +263,7 @@ func (s *Service) RegisterConfigRouter() {
 	rules.GET("/region/:region", getRulesByRegion)
 	rules.GET("/region/:region/detail", checkRegionPlacementRule)
 	rules.GET("/key/:key", getRulesByKey)
+	rules.GET("/zone-distribution", getZoneDistribution)
 
 	// We cannot merge `/rule` and `/rules`, because we allow `group_id` to be "group",
 	// which is the same as the prefix of `/rules/group/:group`.
@@ -218,6 +281,7 @@ func (s *Service) RegisterConfigRouter() {
 	regionLabel := router.Group("region-label")
 	regionLabel.GET("/rules", getAllRegionLabelRules)
 	regionLabel.GET("/rules/ids", getRegionLabelRulesByIDs)
+	regionLabel.GET("/rules/export", exportRegionLabelRules)
 	regionLabel.GET("/rules/:id", getRegionLabelRuleByID)
 
 	regions := router.Group("regions")
@@ -247,6 +311,17 @@ func changeLogLevel(c *gin.Context) {
 	c.String(http.StatusOK, "The log level is updated.")
 }
 
+// @Tags     admin
+// @Summary  Shut down the server gracefully.
+// @Produce  json
+// @Success  200  {string}  string  "The server is shutting down."
+// @Router   /admin/shutdown [post]
+func shutdown(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*scheserver.Server)
+	svr.Shutdown()
+	c.String(http.StatusOK, "The server is shutting down.")
+}
+
 // @Tags     config
 // @Summary  Get full config.
 // @Produce  json
@@ -330,10 +405,13 @@ func getOperatorByRegion(c *gin.Context) {
 
 // @Tags     operators
 // @Summary  List operators.
-// @Param    kind   query  string  false  "Specify the operator kind."  Enums(admin, leader, region, waiting)
-// @Param    object query  bool    false  "Whether to return as JSON object."
+// @Param    kind        query  string   false  "Specify the operator kind."  Enums(admin, leader, region, waiting)
+// @Param    object      query  bool     false  "Whether to return as JSON object."
+// @Param    page_token  query  string   false  "The X-Next-Page-Token header of the previous page"
+// @Param    limit       query  integer  false  "Limit count of the page"
 // @Produce  json
 // @Success  200  {array}   operator.Operator
+// @Failure  400  {string}  string  "The input is invalid."
 // @Failure  500  {string}  string  "PD server failed to proceed the request."
 // @Router   /operators [get]
 func getOperators(c *gin.Context) {
@@ -343,6 +421,11 @@ func getOperators(c *gin.Context) {
 		err     error
 	)
 
+	page, err := apiutil.ParsePageQuery(c.Request.URL.Query())
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
 	kinds := c.QueryArray("kind")
 	_, objectFlag := c.GetQuery("object")
 	if len(kinds) == 0 {
@@ -355,6 +438,12 @@ func getOperators(c *gin.Context) {
 		c.String(http.StatusInternalServerError, err.Error())
 		return
 	}
+	results, nextPageToken, err := apiutil.PaginateByID(results, page, (*operator.Operator).RegionID)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	apiutil.SetNextPageToken(c.Writer, nextPageToken)
 	if objectFlag {
 		objResults := make([]*operator.OpObject, len(results))
 		for i, op := range results {
@@ -431,6 +520,81 @@ func getOperatorRecords(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, records)
 }
 
+// @Tags     operator
+// @Summary  Watch the step progress of the operators on the given regions, the events are streamed as the JSON lines until all the operators end or the request is canceled.
+// @Param    region_id  query  integer  true  "The region ID, it can be given multiple times to watch a batch of operators"
+// @Produce  json
+// @Success  200  {array}   operator.ProgressEvent
+// @Failure  400  {string}  string  "The request is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /operators/progress [get]
+func watchOperatorProgress(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	ids := c.QueryArray("region_id")
+	if len(ids) == 0 {
+		c.String(http.StatusBadRequest, "region_id is required")
+		return
+	}
+	regionIDs := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		regionID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		regionIDs = append(regionIDs, regionID)
+	}
+	if _, err := handler.GetOperatorController(); err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Header("Content-Type", "application/json")
+	c.Status(http.StatusOK)
+	encoder := json.NewEncoder(c.Writer)
+	_ = handler.WatchOperatorProgress(c.Request.Context(), regionIDs, func(events []*operator.ProgressEvent) error {
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+}
+
+// @Tags     operator
+// @Summary  lists the stores which are temporarily excluded as the leader target after failed leader transfers.
+// @Produce  json
+// @Success  200  {object}  []operator.LeaderTransferExclusion
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /operators/leader-transfer-blacklist [get]
+func getLeaderTransferBlacklist(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	exclusions, err := handler.GetLeaderTransferBlacklist()
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, exclusions)
+}
+
+// @Tags     debug
+// @Summary  Get the running operators, the waiting queues and the store limit tokens of this server.
+// @Produce  json
+// @Success  200  {object}  operator.ControllerState
+// @Failure  403  {string}  string  "The client is not authenticated."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /debug/operators [get]
+func getOperatorControllerState(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	state, err := handler.GetOperatorControllerState()
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, state)
+}
+
 // FIXME: details of input json body params
 // @Tags     operator
 // @Summary  Create an operator.
@@ -460,6 +624,89 @@ func createOperator(c *gin.Context) {
 	c.IndentedJSON(statusCode, result)
 }
 
+// @Tags     operators
+// @Summary  Move a Region to the desired placement with the minimal steps planned by PD.
+// @Param    id    path  int                      true  "A Region's Id"
+// @Param    body  body  handler.RegionPlacement  true  "The desired peers and their roles"
+// @Accept   json
+// @Produce  json
+// @Success  200  {object}  handler.PlacementPlan
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The region does not exist."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /operators/{id}/placement [post]
+func setRegionPlacement(c *gin.Context) {
+	h := c.MustGet(handlerKey).(*handler.Handler)
+	regionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	var input handler.RegionPlacement
+	if err := c.BindJSON(&input); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	plan, err := h.AddRegionPlacementOperator(regionID, &input)
+	if err != nil {
+		if errs.ErrRegionNotFound.Equal(err) {
+			c.String(http.StatusNotFound, err.Error())
+			return
+		}
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, plan)
+}
+
+// @Tags     checkers
+// @Summary  Get the progress of the current patrol round of the checkers.
+// @Produce  json
+// @Success  200  {object}  schedule.PatrolRegionsProgress
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /checkers/patrol-progress [get]
+func getPatrolRegionsProgress(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	progress, err := handler.GetPatrolRegionsProgress()
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, progress)
+}
+
+// @Tags     checkers
+// @Summary  Diagnose the adjacent regions of a region as the merge target.
+// @Param    region_id  path  integer  true  "Region Id"
+// @Produce  json
+// @Success  200  {array}   checker.MergeTargetDiagnosis
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The region does not exist."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /checkers/merge-checker/diagnostic/{region_id} [get]
+func diagnoseMergeTargets(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	regionID, err := strconv.ParseUint(c.Param("region_id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	diagnoses, err := handler.DiagnoseMergeTargets(regionID)
+	if err != nil {
+		if errs.ErrRegionNotFound.Equal(err) {
+			c.String(http.StatusNotFound, err.Error())
+			return
+		}
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, diagnoses)
+}
+
 // @Tags     checkers
 // @Summary  Get checker by name
 // @Param    name  path  string  true  "The name of the checker."
@@ -607,6 +854,34 @@ func getDiagnosticResult(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, result)
 }
 
+// @Tags     schedulers
+// @Summary  Run one scheduling round of a scheduler and return the operators without adding them.
+// @Param    name  path  string  true  "The name of the scheduler."
+// @Produce  json
+// @Success  200  {object}  handler.SchedulerDryRunResult
+// @Failure  400  {string}  string  "The scheduler doesn't support dry run."
+// @Failure  404  {string}  string  "The scheduler is not found."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /schedulers/{name}/dry-run [get]
+func dryRunScheduler(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	name := c.Param("name")
+	result, err := handler.DryRunScheduler(name)
+	if err != nil {
+		if errs.ErrSchedulerNotDryRunnable.Equal(err) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		if errs.ErrSchedulerNotFound.Equal(err) {
+			c.String(http.StatusNotFound, err.Error())
+			return
+		}
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, result)
+}
+
 // FIXME: details of input json body params
 // @Tags     scheduler
 // @Summary  Pause or resume a scheduler.
@@ -739,6 +1014,34 @@ func getHotBuckets(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, ret)
 }
 
+// @Tags     hotspot
+// @Summary  Analyze the hot regions and recommend the actions to mitigate the hotspot.
+// @Param    type  query  string  false  "The type of the hotspot, write or read"  default(write)
+// @Produce  json
+// @Success  200  {object}  handler.HotAnalysis
+// @Failure  400  {string}  string  "The request is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /hotspot/analysis [get]
+func analyzeHotRegions(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+
+	typ := utils.Write
+	switch t := c.Query("type"); t {
+	case "", utils.Write.String():
+	case utils.Read.String():
+		typ = utils.Read
+	default:
+		c.String(http.StatusBadRequest, fmt.Sprintf("invalid hotspot type: %s", t))
+		return
+	}
+	analysis, err := handler.AnalyzeHotRegions(typ)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, analysis)
+}
+
 // @Tags     hotspot
 // @Summary  List the history hot regions.
 // @Accept   json
@@ -771,6 +1074,9 @@ func getAllRules(c *gin.Context) {
 		return
 	}
 	rules := manager.GetAllRules()
+	if apiutil.WriteETag(c.Writer, c.Request, rules) {
+		return
+	}
 	c.IndentedJSON(http.StatusOK, rules)
 }
 
@@ -859,6 +1165,44 @@ func checkRegionPlacementRule(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, regionFit)
 }
 
+// @Tags     rule
+// @Summary  Show how the replicas are distributed across the zones versus what the rules prescribe.
+// @Param    start_key   query  string  false  "The start key of the range in hex"
+// @Param    end_key     query  string  false  "The end key of the range in hex"
+// @Param    group       query  string  false  "Only take the rules of the group into account"
+// @Param    zone_label  query  string  false  "The store label key of the zones, default to zone"
+// @Param    page_token  query  string  false  "The next_page_token of the previous page"
+// @Param    limit       query  integer  false  "Limit count of the regions of the page, at most 10240"
+// @Produce  json
+// @Success  200  {object}  placement.ZoneDistribution
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  412  {string}  string  "Placement rules feature is disabled."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /config/rules/zone-distribution [get]
+func getZoneDistribution(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	page, err := apiutil.ParsePageQuery(c.Request.URL.Query())
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	distribution, err := handler.GetZoneDistribution(c.Query("start_key"), c.Query("end_key"), c.Query("group"), c.Query("zone_label"), page)
+	if err == errs.ErrPlacementDisabled {
+		c.String(http.StatusPreconditionFailed, err.Error())
+		return
+	}
+	if errs.ErrKeyFormat.Equal(err) {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	apiutil.SetNextPageToken(c.Writer, distribution.NextPageToken)
+	c.IndentedJSON(http.StatusOK, distribution)
+}
+
 // @Tags     rule
 // @Summary  List all rules of cluster by key.
 // @Param    key  path  string  true  "The name of key"
@@ -988,6 +1332,9 @@ func getPlacementRules(c *gin.Context) {
 		return
 	}
 	bundles := manager.GetAllGroupBundles()
+	if apiutil.WriteETag(c.Writer, c.Request, bundles) {
+		return
+	}
 	c.IndentedJSON(http.StatusOK, bundles)
 }
 
@@ -1106,9 +1453,35 @@ func getAllRegionLabelRules(c *gin.Context) {
 		return
 	}
 	rules := l.GetAllLabelRules()
+	if apiutil.WriteETag(c.Writer, c.Request, rules) {
+		return
+	}
 	c.IndentedJSON(http.StatusOK, rules)
 }
 
+// @Tags     region_label
+// @Summary  Export all label rules of cluster.
+// @Produce  json
+// @Success  200  {array}  labeler.LabelRule
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /config/region-label/rules/export [get]
+func exportRegionLabelRules(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	l, err := handler.GetRegionLabeler()
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	rules := l.GetAllLabelRules()
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	c.Header("Content-Type", "application/json")
+	c.Status(http.StatusOK)
+	// the response is compressed by the gzip middleware.
+	if err := apiutil.WriteJSONStream(c.Writer, rules); err != nil {
+		log.Warn("failed to export label rules", errs.ZapError(err))
+	}
+}
+
 // @Tags     region_label
 // @Summary  Get label rules of cluster by ids.
 // @Param    body  body  []string  true  "IDs of query rules"
@@ -1260,7 +1633,89 @@ func accelerateRegionsScheduleInRanges(c *gin.Context) {
 }
 
 // @Tags     region
-// @Summary  Scatter regions by given key ranges or regions id distributed by given group with given retry limit
+// @Summary  Merge the undersized regions in a given range aggressively, only receive hex format for keys
+// @Accept   json
+// @Param    body  body  object  true  "json params"
+// @Produce  json
+// @Success  200  {object}  checker.RangeMergeProgress
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /regions/merge [post]
+func mergeRegionsInRange(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+
+	var input map[string]any
+	if err := c.BindJSON(&input); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	rawStartKey, ok1 := input["start_key"].(string)
+	rawEndKey, ok2 := input["end_key"].(string)
+	if !ok1 || !ok2 {
+		c.String(http.StatusBadRequest, "start_key or end_key is not string")
+		return
+	}
+	targetCount := 1
+	if tc, ok := input["target_count"].(float64); ok {
+		targetCount = int(tc)
+	}
+	progress, err := handler.MergeRegionsInRange(rawStartKey, rawEndKey, targetCount)
+	if err != nil {
+		if errs.ErrRangeMergeInvalidRange.Equal(err) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, progress)
+}
+
+// @Tags     region
+// @Summary  List the progress of the recent range merge jobs.
+// @Produce  json
+// @Success  200  {array}   checker.RangeMergeProgress
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /regions/merge [get]
+func getRangeMergeJobs(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	jobs, err := handler.GetRangeMergeJobs()
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, jobs)
+}
+
+// @Tags     region
+// @Summary  Cancel the running range merge job.
+// @Param    id  path  integer  true  "Job Id"
+// @Produce  json
+// @Success  200  {string}  string  "The job is canceled."
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The job does not exist."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /regions/merge/{id} [delete]
+func cancelRangeMergeJob(c *gin.Context) {
+	handler := c.MustGet(handlerKey).(*handler.Handler)
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := handler.CancelRangeMergeJob(id); err != nil {
+		if errs.ErrRangeMergeJobNotFound.Equal(err) {
+			c.String(http.StatusNotFound, err.Error())
+			return
+		}
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.String(http.StatusOK, "The job is canceled.")
+}
+
+// @Tags     region
+// @Summary  Scatter regions by given key ranges or regions id distributed by given group with given retry limit, the target stores can be limited by the store group
 // @Accept   json
 // @Param    body  body  object  true  "json params"
 // @Produce  json
@@ -1279,6 +1734,7 @@ func scatterRegions(c *gin.Context) {
 	rawStartKey, ok1 := input["start_key"].(string)
 	rawEndKey, ok2 := input["end_key"].(string)
 	group, _ := input["group"].(string)
+	storeGroup, _ := input["store_group"].(string)
 	retryLimit := 5
 	if rl, ok := input["retry_limit"].(float64); ok {
 		retryLimit = int(rl)
@@ -1286,13 +1742,13 @@ func scatterRegions(c *gin.Context) {
 
 	opsCount, failures, err := func() (int, map[uint64]error, error) {
 		if ok1 && ok2 {
-			return handler.ScatterRegionsByRange(rawStartKey, rawEndKey, group, retryLimit)
+			return handler.ScatterRegionsByRange(rawStartKey, rawEndKey, group, retryLimit, storeGroup)
 		}
 		ids, ok := typeutil.JSONToUint64Slice(input["regions_id"])
 		if !ok {
 			return 0, nil, errors.New("regions_id is invalid")
 		}
-		return handler.ScatterRegionsByID(ids, group, retryLimit)
+		return handler.ScatterRegionsByID(ids, group, retryLimit, storeGroup)
 	}()
 	if err != nil {
 		c.String(http.StatusInternalServerError, err.Error())
@@ -1395,12 +1851,20 @@ func getStoreByID(c *gin.Context) {
 
 // @Tags        store
 // @Summary     Get all stores in the cluster.
+// @Param       page_token  query  string   false  "The next_page_token of the previous page"
+// @Param       limit       query  integer  false  "Limit count of the page"
 // @Produce     json
 // @Success     200 {object} response.StoresInfo
+// @Failure     400 {string} string "The input is invalid."
 // @Failure     500 {string} string "PD server failed to proceed the request."
 // @Router      /stores [get]
 func getAllStores(c *gin.Context) {
 	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*scheserver.Server)
+	page, err := apiutil.ParsePageQuery(c.Request.URL.Query())
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
 	stores := svr.GetBasicCluster().GetMetaStores()
 	StoresInfo := &response.StoresInfo{
 		Stores: make([]*response.StoreInfo, 0, len(stores)),
@@ -1419,19 +1883,73 @@ func getAllStores(c *gin.Context) {
 		storeInfo := response.BuildStoreInfo(&svr.GetConfig().Schedule, store)
 		StoresInfo.Stores = append(StoresInfo.Stores, storeInfo)
 	}
-	StoresInfo.Count = len(StoresInfo.Stores)
+	if err := StoresInfo.Paginate(page); err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	apiutil.SetNextPageToken(c.Writer, StoresInfo.NextPageToken)
 	c.IndentedJSON(http.StatusOK, StoresInfo)
 }
 
 // @Tags     region
 // @Summary  List all regions in the cluster.
+// @Param    page_token  query  string   false  "The next_page_token of the previous page"
+// @Param    limit       query  integer  false  "Limit count of the page"
 // @Produce  json
 // @Success  200  {object}  response.RegionsInfo
+// @Failure  400  {string}  string  "The input is invalid."
 // @Router   /regions [get]
 func getAllRegions(c *gin.Context) {
 	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*scheserver.Server)
-	regions := svr.GetBasicCluster().GetRegions()
-	b, err := response.MarshalRegionsInfoJSON(c.Request.Context(), regions)
+	page, err := apiutil.ParsePageQuery(c.Request.URL.Query())
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	var (
+		regions       []*core.RegionInfo
+		nextPageToken string
+	)
+	if page.IsPaged() {
+		regions, nextPageToken, err = response.ScanRegionsPage(svr.GetBasicCluster().ScanRegions, nil, nil, page)
+		if err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+	} else {
+		regions = svr.GetBasicCluster().GetRegions()
+	}
+	apiutil.SetNextPageToken(c.Writer, nextPageToken)
+	b, err := response.MarshalRegionsPageJSON(c.Request.Context(), regions, nextPageToken)
+	if err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/json", b)
+}
+
+// @Tags     region
+// @Summary  Search for a region by a key.
+// @Param    key     path   string  true   "Region key"
+// @Param    format  query  string  false  "The format of the key, raw or hex"
+// @Produce  json
+// @Success  200  {object}  response.RegionInfo
+// @Failure  400  {string}  string  "The input is invalid."
+// @Router   /region/key/{key} [get]
+func getRegionByKey(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*scheserver.Server)
+	key, err := url.QueryUnescape(c.Param("key"))
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	keys, err := apiutil.ParseHexKeys(c.Query("format"), [][]byte{[]byte(key)})
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	regionInfo := svr.GetBasicCluster().GetRegionByKey(keys[0])
+	b, err := response.MarshalRegionInfoJSON(c.Request.Context(), regionInfo)
 	if err != nil {
 		c.String(http.StatusInternalServerError, err.Error())
 		return
@@ -1477,3 +1995,19 @@ func getRegionByID(c *gin.Context) {
 	}
 	c.Data(http.StatusOK, "application/json", b)
 }
+
+// @Tags     stats
+// @Summary  Get the number of regions which would lose quorum if the zone failed.
+// @Produce  json
+// @Success  200  {object}  map[string]int
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /stats/zone-quorum [get]
+func getZoneQuorumStatus(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*scheserver.Server)
+	cluster := svr.GetCluster()
+	if cluster == nil {
+		c.String(http.StatusInternalServerError, errs.ErrNotBootstrapped.GenWithStackByArgs().Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, cluster.GetZoneQuorumStats().GetZoneQuorumLossCount())
+}
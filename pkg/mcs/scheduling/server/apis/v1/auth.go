@@ -0,0 +1,56 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apis
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tikv/pd/pkg/slice"
+	"github.com/tikv/pd/pkg/utils/grpcutil"
+)
+
+// debugAuthenticator authenticates the clients of the debug API. If the TLS is
+// enabled, the client must provide a verified certificate, whose common name
+// must be allowed if the cert-allowed-cn is set. Otherwise, only the clients
+// on the same host can access the debug API.
+func debugAuthenticator(tlsCfg *grpcutil.TLSConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isDebugClientAuthenticated(c.Request, tlsCfg) {
+			c.AbortWithStatusJSON(http.StatusForbidden, "the client is not authenticated")
+			return
+		}
+		c.Next()
+	}
+}
+
+func isDebugClientAuthenticated(r *http.Request, tlsCfg *grpcutil.TLSConfig) bool {
+	if tlsCfg != nil && tlsCfg.CertPath != "" {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			return false
+		}
+		if len(tlsCfg.CertAllowedCN) == 0 {
+			return true
+		}
+		return slice.Contains(tlsCfg.CertAllowedCN, r.TLS.VerifiedChains[0][0].Subject.CommonName)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
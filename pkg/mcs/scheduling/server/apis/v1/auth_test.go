@@ -0,0 +1,50 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/utils/grpcutil"
+)
+
+func TestDebugClientAuthentication(t *testing.T) {
+	re := require.New(t)
+	req := httptest.NewRequest("GET", "/scheduling/api/v1/debug/operators", nil)
+	req.RemoteAddr = "127.0.0.1:34567"
+	re.True(isDebugClientAuthenticated(req, &grpcutil.TLSConfig{}))
+	req.RemoteAddr = "[::1]:34567"
+	re.True(isDebugClientAuthenticated(req, nil))
+	req.RemoteAddr = "10.0.0.1:34567"
+	re.False(isDebugClientAuthenticated(req, &grpcutil.TLSConfig{}))
+
+	// the client certificate is required once the TLS is enabled.
+	tlsCfg := &grpcutil.TLSConfig{CertPath: "server.pem", CertAllowedCN: []string{"pd-ctl"}}
+	req.RemoteAddr = "127.0.0.1:34567"
+	re.False(isDebugClientAuthenticated(req, tlsCfg))
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "tidb"}}
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	re.False(isDebugClientAuthenticated(req, tlsCfg))
+	cert.Subject.CommonName = "pd-ctl"
+	re.True(isDebugClientAuthenticated(req, tlsCfg))
+	tlsCfg.CertAllowedCN = nil
+	cert.Subject.CommonName = "tidb"
+	re.True(isDebugClientAuthenticated(req, tlsCfg))
+}
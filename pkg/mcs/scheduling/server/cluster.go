@@ -47,6 +47,7 @@ type Cluster struct {
 	labelerManager    *labeler.RegionLabeler
 	regionStats       *statistics.RegionStatistics
 	labelStats        *statistics.LabelStatistics
+	zoneStats         *statistics.ZoneQuorumStatistics
 	hotStat           *statistics.HotStat
 	storage           storage.Storage
 	coordinator       *schedule.Coordinator
@@ -94,6 +95,7 @@ func NewCluster(parentCtx context.Context, persistConfig *config.PersistConfig,
 		persistConfig:     persistConfig,
 		hotStat:           statistics.NewHotStat(ctx),
 		labelStats:        statistics.NewLabelStatistics(),
+		zoneStats:         statistics.NewZoneQuorumStatistics(),
 		regionStats:       statistics.NewRegionStatistics(basicCluster, persistConfig, ruleManager),
 		storage:           storage,
 		clusterID:         clusterID,
@@ -138,6 +140,11 @@ func (c *Cluster) GetLabelStats() *statistics.LabelStatistics {
 	return c.labelStats
 }
 
+// GetZoneQuorumStats gets zone quorum statistics.
+func (c *Cluster) GetZoneQuorumStats() *statistics.ZoneQuorumStatistics {
+	return c.zoneStats
+}
+
 // GetBasicCluster returns the basic cluster.
 func (c *Cluster) GetBasicCluster() *core.BasicCluster {
 	return c.BasicCluster
@@ -380,8 +387,14 @@ func (c *Cluster) waitSchedulersInitialized() {
 
 // UpdateRegionsLabelLevelStats updates the status of the region label level by types.
 func (c *Cluster) UpdateRegionsLabelLevelStats(regions []*core.RegionInfo) {
+	ratio := c.persistConfig.GetRegionStatsSampleRatio()
 	for _, region := range regions {
-		c.labelStats.Observe(region, c.getStoresWithoutLabelLocked(region, core.EngineKey, core.EngineTiFlash), c.persistConfig.GetLocationLabels())
+		stores := c.getStoresWithoutLabelLocked(region, core.EngineKey, core.EngineTiFlash)
+		// Only the sampled regions update the label statistics.
+		if cluster.SampleRegionStats(ratio) {
+			c.labelStats.Observe(region, stores, c.persistConfig.GetLocationLabels())
+		}
+		c.zoneStats.Observe(region, stores, c.persistConfig.GetLocationLabels())
 	}
 }
 
@@ -406,7 +419,6 @@ func (c *Cluster) HandleStoreHeartbeat(heartbeat *schedulingpb.StoreHeartbeatReq
 
 	nowTime := time.Now()
 	newStore := store.Clone(core.SetStoreStats(stats), core.SetLastHeartbeatTS(nowTime))
-
 	if store := c.GetStore(storeID); store != nil {
 		statistics.UpdateStoreHeartbeatMetrics(store)
 	}
@@ -530,6 +542,7 @@ func (c *Cluster) collectMetrics() {
 	}
 	c.regionStats.Collect()
 	c.labelStats.Collect()
+	c.zoneStats.Collect()
 	// collect hot cache metrics
 	c.hotStat.CollectMetrics()
 	// collect the lock metrics
@@ -613,7 +626,9 @@ func (c *Cluster) processRegionHeartbeat(ctx *core.MetaProcessContext, region *c
 		return err
 	}
 	region.Inherit(origin, c.GetStoreConfig().IsEnableRegionBucket())
-	cluster.HandleStatsAsync(c, region)
+	// Only the sampled heartbeats update the flow statistics.
+	sampled := cluster.SampleRegionStats(c.persistConfig.GetRegionStatsSampleRatio())
+	cluster.HandleStatsAsync(c, region, sampled)
 	tracer.OnAsyncHotStatsFinished()
 	hasRegionStats := c.regionStats != nil
 	// Save to storage if meta is updated, except for flashback.
@@ -368,6 +368,21 @@ func (o *PersistConfig) GetLocationLabels() []string {
 	return o.GetReplicationConfig().LocationLabels
 }
 
+// GetLocationLabelWeights returns the weights of the location labels.
+func (o *PersistConfig) GetLocationLabelWeights() []float64 {
+	return o.GetReplicationConfig().LocationLabelWeights
+}
+
+// GetLabelTaxonomy returns the constraints of the store labels.
+func (o *PersistConfig) GetLabelTaxonomy() []sc.StoreLabelConstraint {
+	return o.GetReplicationConfig().LabelTaxonomy
+}
+
+// GetLabelTaxonomyAction returns the action on the stores violating the label taxonomy.
+func (o *PersistConfig) GetLabelTaxonomyAction() string {
+	return o.GetReplicationConfig().LabelTaxonomyAction
+}
+
 // IsUseJointConsensus returns if the joint consensus is enabled.
 func (o *PersistConfig) IsUseJointConsensus() bool {
 	return o.GetScheduleConfig().EnableJointConsensus
@@ -418,11 +433,61 @@ func (o *PersistConfig) GetSlowStoreEvictingAffectedStoreRatioThreshold() float6
 	return o.GetScheduleConfig().SlowStoreEvictingAffectedStoreRatioThreshold
 }
 
+// GetLabelDomainOperatorLimits returns the limits of the coexist region movements in each label domain.
+func (o *PersistConfig) GetLabelDomainOperatorLimits() []sc.LabelDomainOperatorLimit {
+	return o.GetScheduleConfig().LabelDomainOperatorLimits
+}
+
+// GetClusterSnapshotBandwidth returns the aggregate bandwidth of the snapshots per second in the cluster.
+func (o *PersistConfig) GetClusterSnapshotBandwidth() uint64 {
+	return uint64(o.GetScheduleConfig().ClusterSnapshotBandwidth)
+}
+
+// GetRegionStatsSampleRatio returns the fraction of the region heartbeats which update the expensive statistics.
+func (o *PersistConfig) GetRegionStatsSampleRatio() float64 {
+	return o.GetScheduleConfig().RegionStatsSampleRatio
+}
+
+// GetMaxStorePendingCompactionBytes returns the limit of the pending compaction bytes of a store.
+func (o *PersistConfig) GetMaxStorePendingCompactionBytes() uint64 {
+	return uint64(o.GetScheduleConfig().MaxStorePendingCompactionBytes)
+}
+
+// GetMaxStoreLevel0FileCount returns the limit of the level0 file count of a store.
+func (o *PersistConfig) GetMaxStoreLevel0FileCount() uint64 {
+	return o.GetScheduleConfig().MaxStoreLevel0FileCount
+}
+
+// GetMaxEvictingStoreRatio returns the max fraction of the stores which are evicting or offline.
+func (o *PersistConfig) GetMaxEvictingStoreRatio() float64 {
+	return o.GetScheduleConfig().MaxEvictingStoreRatio
+}
+
+// GetLeaderTransferBlacklistWindow returns the base window to exclude a store as the leader target.
+func (o *PersistConfig) GetLeaderTransferBlacklistWindow() time.Duration {
+	return o.GetScheduleConfig().LeaderTransferBlacklistWindow.Duration
+}
+
+// GetMaxLearnerCatchUpTime returns the max duration for a new learner to catch up.
+func (o *PersistConfig) GetMaxLearnerCatchUpTime() time.Duration {
+	return o.GetScheduleConfig().MaxLearnerCatchUpTime.Duration
+}
+
+// GetStoreSlowStartWindow returns the window to ramp the weights of a new store.
+func (o *PersistConfig) GetStoreSlowStartWindow() time.Duration {
+	return o.GetScheduleConfig().StoreSlowStartWindow.Duration
+}
+
 // GetPatrolRegionInterval returns the interval of patrolling region.
 func (o *PersistConfig) GetPatrolRegionInterval() time.Duration {
 	return o.GetScheduleConfig().PatrolRegionInterval.Duration
 }
 
+// GetPatrolRegionConcurrency returns the number of the key space partitions patrolled concurrently.
+func (o *PersistConfig) GetPatrolRegionConcurrency() int {
+	return o.GetScheduleConfig().PatrolRegionConcurrency
+}
+
 // GetTolerantSizeRatio gets the tolerant size ratio.
 func (o *PersistConfig) GetTolerantSizeRatio() float64 {
 	return o.GetScheduleConfig().TolerantSizeRatio
@@ -438,6 +503,21 @@ func (o *PersistConfig) IsDiagnosticAllowed() bool {
 	return o.GetScheduleConfig().EnableDiagnostic
 }
 
+// GetDecisionLogFile returns the file of the scheduling decision log.
+func (o *PersistConfig) GetDecisionLogFile() string {
+	return o.GetScheduleConfig().DecisionLogFile
+}
+
+// GetDecisionLogMaxSize returns the max size in MB of the decision log file.
+func (o *PersistConfig) GetDecisionLogMaxSize() int {
+	return o.GetScheduleConfig().DecisionLogMaxSize
+}
+
+// GetDecisionLogMaxBackups returns the max number of the rotated decision log files.
+func (o *PersistConfig) GetDecisionLogMaxBackups() int {
+	return o.GetScheduleConfig().DecisionLogMaxBackups
+}
+
 // IsRemoveDownReplicaEnabled returns if remove down replica is enabled.
 func (o *PersistConfig) IsRemoveDownReplicaEnabled() bool {
 	return o.GetScheduleConfig().EnableRemoveDownReplica
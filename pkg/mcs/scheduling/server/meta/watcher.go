@@ -16,13 +16,18 @@ package meta
 
 import (
 	"context"
+	"encoding/json"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/statistics"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/utils/etcdutil"
@@ -31,6 +36,14 @@ import (
 	"go.uber.org/zap"
 )
 
+// storeResyncInterval is the interval to relist the stores from etcd.
+const storeResyncInterval = time.Minute
+
+// ConfigProvider is the config used by the watcher.
+type ConfigProvider interface {
+	GetStoreSlowStartWindow() time.Duration
+}
+
 // Watcher is used to watch the PD API server for any meta changes.
 type Watcher struct {
 	wg        sync.WaitGroup
@@ -41,10 +54,19 @@ type Watcher struct {
 	//  - Key: /pd/{cluster_id}/raft/s/
 	//  - Value: meta store proto.
 	storePathPrefix string
+	// compactionStatsPathPrefix is the path of the compaction stats of the store in etcd:
+	//  - Key: /pd/{cluster_id}/raft/store_compaction_stats/{store_id}
+	//  - Value: the JSON of core.CompactionStats.
+	compactionStatsPathPrefix string
 
 	etcdClient   *clientv3.Client
 	basicCluster *core.BasicCluster
-	storeWatcher *etcdutil.LoopWatcher
+	cfg          ConfigProvider
+	// storeLoaded is set after the existing stores are loaded, the stores added
+	// after that are the newly registered ones.
+	storeLoaded            atomic.Bool
+	storeWatcher           *etcdutil.Informer[*metapb.Store]
+	compactionStatsWatcher *etcdutil.Informer[*core.CompactionStats]
 }
 
 // NewWatcher creates a new watcher to watch the meta change from PD API server.
@@ -53,35 +75,49 @@ func NewWatcher(
 	etcdClient *clientv3.Client,
 	clusterID uint64,
 	basicCluster *core.BasicCluster,
+	cfg ConfigProvider,
 ) (*Watcher, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	w := &Watcher{
-		ctx:             ctx,
-		cancel:          cancel,
-		clusterID:       clusterID,
-		storePathPrefix: endpoint.StorePathPrefix(clusterID),
-		etcdClient:      etcdClient,
-		basicCluster:    basicCluster,
+		ctx:                       ctx,
+		cancel:                    cancel,
+		clusterID:                 clusterID,
+		storePathPrefix:           endpoint.StorePathPrefix(clusterID),
+		compactionStatsPathPrefix: endpoint.StoreCompactionStatsPathPrefix(clusterID),
+		etcdClient:                etcdClient,
+		basicCluster:              basicCluster,
+		cfg:                       cfg,
 	}
 	err := w.initializeStoreWatcher()
 	if err != nil {
 		return nil, err
 	}
+	// the stats are applied to the stores, so they are watched after the stores are loaded.
+	err = w.initializeCompactionStatsWatcher()
+	if err != nil {
+		return nil, err
+	}
 	return w, nil
 }
 
 func (w *Watcher) initializeStoreWatcher() error {
-	putFn := func(kv *mvccpb.KeyValue) error {
+	decodeFn := func(kv *mvccpb.KeyValue) (*metapb.Store, error) {
 		store := &metapb.Store{}
 		if err := proto.Unmarshal(kv.Value, store); err != nil {
-			log.Warn("failed to unmarshal store entry",
-				zap.String("event-kv-key", string(kv.Key)), zap.Error(err))
-			return err
+			return nil, err
 		}
+		return store, nil
+	}
+	putFn := func(_ string, store *metapb.Store) error {
 		log.Debug("update store meta", zap.Stringer("store", store))
 		origin := w.basicCluster.GetStore(store.GetId())
 		if origin == nil {
-			w.basicCluster.PutStore(core.NewStoreInfo(store))
+			var opts []core.StoreCreateOption
+			if window := w.cfg.GetStoreSlowStartWindow(); window > 0 && w.storeLoaded.Load() {
+				log.Info("new store starts to ramp its weights", zap.Uint64("store-id", store.GetId()), zap.Duration("window", window))
+				opts = append(opts, core.SetSlowStart(time.Now(), window))
+			}
+			w.basicCluster.PutStore(core.NewStoreInfo(store, opts...))
 		} else {
 			w.basicCluster.PutStore(origin.Clone(core.SetStoreMeta(store)))
 		}
@@ -90,33 +126,73 @@ func (w *Watcher) initializeStoreWatcher() error {
 			statistics.ResetStoreStatistics(store.GetAddress(), strconv.FormatUint(store.GetId(), 10))
 			// TODO: remove hot stats
 		}
-
 		return nil
 	}
-	deleteFn := func(kv *mvccpb.KeyValue) error {
-		key := string(kv.Key)
-		storeID, err := endpoint.ExtractStoreIDFromPath(w.clusterID, key)
-		if err != nil {
-			return err
-		}
-		origin := w.basicCluster.GetStore(storeID)
+	deleteFn := func(_ string, store *metapb.Store) error {
+		origin := w.basicCluster.GetStore(store.GetId())
 		if origin != nil {
 			w.basicCluster.DeleteStore(origin)
-			log.Info("delete store meta", zap.Uint64("store-id", storeID))
+			log.Info("delete store meta", zap.Uint64("store-id", store.GetId()))
 		}
 		return nil
 	}
-	w.storeWatcher = etcdutil.NewLoopWatcher(
+	w.storeWatcher = etcdutil.NewInformer(
 		w.ctx, &w.wg,
 		w.etcdClient,
 		"scheduling-store-watcher", w.storePathPrefix,
-		func([]*clientv3.Event) error { return nil },
-		putFn, deleteFn,
-		func([]*clientv3.Event) error { return nil },
-		true, /* withPrefix */
+		decodeFn,
+		etcdutil.InformerHandler[*metapb.Store]{
+			OnAdd:    putFn,
+			OnUpdate: func(key string, _, store *metapb.Store) error { return putFn(key, store) },
+			OnDelete: deleteFn,
+		},
+		storeResyncInterval,
 	)
 	w.storeWatcher.StartWatchLoop()
-	return w.storeWatcher.WaitLoad()
+	if err := w.storeWatcher.WaitLoad(); err != nil {
+		return err
+	}
+	w.storeLoaded.Store(true)
+	return nil
+}
+
+func (w *Watcher) initializeCompactionStatsWatcher() error {
+	decodeFn := func(kv *mvccpb.KeyValue) (*core.CompactionStats, error) {
+		stats := &core.CompactionStats{}
+		if err := json.Unmarshal(kv.Value, stats); err != nil {
+			return nil, err
+		}
+		return stats, nil
+	}
+	updateFn := func(key string, stats *core.CompactionStats) error {
+		storeID, err := strconv.ParseUint(strings.TrimPrefix(key, w.compactionStatsPathPrefix), 10, 64)
+		if err != nil {
+			log.Warn("failed to parse the store id of the compaction stats", zap.String("key", key), zap.Error(err))
+			return nil
+		}
+		// the store may be not watched yet, the informer retries it later.
+		return w.basicCluster.UpdateCompactionStats(storeID, stats)
+	}
+	w.compactionStatsWatcher = etcdutil.NewInformer(
+		w.ctx, &w.wg,
+		w.etcdClient,
+		"scheduling-compaction-stats-watcher", w.compactionStatsPathPrefix,
+		decodeFn,
+		etcdutil.InformerHandler[*core.CompactionStats]{
+			OnAdd:    updateFn,
+			OnUpdate: func(key string, _, stats *core.CompactionStats) error { return updateFn(key, stats) },
+			OnDelete: func(key string, _ *core.CompactionStats) error {
+				// the stats are deleted together with the store.
+				if err := updateFn(key, nil); err != nil && !errs.ErrStoreNotFound.Equal(err) {
+					return err
+				}
+				return nil
+			},
+		},
+		storeResyncInterval,
+	)
+	w.compactionStatsWatcher.StartWatchLoop()
+	return w.compactionStatsWatcher.WaitLoad()
 }
 
 // Close closes the watcher.
@@ -126,6 +202,6 @@ func (w *Watcher) Close() {
 }
 
 // GetStoreWatcher returns the store watcher.
-func (w *Watcher) GetStoreWatcher() *etcdutil.LoopWatcher {
+func (w *Watcher) GetStoreWatcher() *etcdutil.Informer[*metapb.Store] {
 	return w.storeWatcher
 }
@@ -39,7 +39,7 @@ type Watcher struct {
 
 	// ruleCommonPathPrefix:
 	//  - Key: /pd/{cluster_id}/rule
-	//  - Value: placement.Rule or placement.RuleGroup
+	//  - Value: placement.Rule, placement.RuleGroup or placement.StoreGroup
 	ruleCommonPathPrefix string
 	// rulesPathPrefix:
 	//   - Key: /pd/{cluster_id}/rules/{group_id}-{rule_id}
@@ -49,6 +49,10 @@ type Watcher struct {
 	//   - Key: /pd/{cluster_id}/rule_group/{group_id}
 	//   - Value: placement.RuleGroup
 	ruleGroupPathPrefix string
+	// storeGroupPathPrefix:
+	//   - Key: /pd/{cluster_id}/rule_store_group/{store_group_id}
+	//   - Value: placement.StoreGroup
+	storeGroupPathPrefix string
 	// regionLabelPathPrefix:
 	//   - Key: /pd/{cluster_id}/region_label/{rule_id}
 	//  - Value: labeler.LabelRule
@@ -88,6 +92,7 @@ func NewWatcher(
 		rulesPathPrefix:       endpoint.RulesPathPrefix(clusterID),
 		ruleCommonPathPrefix:  endpoint.RuleCommonPathPrefix(clusterID),
 		ruleGroupPathPrefix:   endpoint.RuleGroupPathPrefix(clusterID),
+		storeGroupPathPrefix:  endpoint.StoreGroupPathPrefix(clusterID),
 		regionLabelPathPrefix: endpoint.RegionLabelPathPrefix(clusterID),
 		etcdClient:            etcdClient,
 		ruleStorage:           ruleStorage,
@@ -149,6 +154,19 @@ func (rw *Watcher) initializeRuleWatcher() error {
 				suspectKeyRanges.Append(rule.StartKey, rule.EndKey)
 			}
 			return nil
+		} else if strings.HasPrefix(key, rw.storeGroupPathPrefix) {
+			log.Info("update store group", zap.String("key", key), zap.String("value", string(kv.Value)))
+			storeGroup, err := placement.NewStoreGroupFromJSON(kv.Value)
+			if err != nil {
+				return err
+			}
+			// Try to add the store group change to the patch.
+			rw.patch.SetStoreGroup(storeGroup)
+			// Update the suspect key ranges
+			for _, rule := range rw.ruleManager.GetRulesByStoreGroupLocked(storeGroup.ID) {
+				suspectKeyRanges.Append(rule.StartKey, rule.EndKey)
+			}
+			return nil
 		}
 		log.Warn("unknown key when updating placement rule", zap.String("key", key))
 		return nil
@@ -180,6 +198,16 @@ func (rw *Watcher) initializeRuleWatcher() error {
 				suspectKeyRanges.Append(rule.StartKey, rule.EndKey)
 			}
 			return nil
+		} else if strings.HasPrefix(key, rw.storeGroupPathPrefix) {
+			log.Info("delete store group", zap.String("key", key))
+			trimmedKey := strings.TrimPrefix(key, rw.storeGroupPathPrefix+"/")
+			// Try to add the store group change to the patch.
+			rw.patch.DeleteStoreGroup(trimmedKey)
+			// Update the suspect key ranges
+			for _, rule := range rw.ruleManager.GetRulesByStoreGroupLocked(trimmedKey) {
+				suspectKeyRanges.Append(rule.StartKey, rule.EndKey)
+			}
+			return nil
 		}
 		log.Warn("unknown key when deleting placement rule", zap.String("key", key))
 		return nil
@@ -316,9 +316,7 @@ func (s *Server) Close() {
 	}
 
 	log.Info("closing scheduling server ...")
-	if err := s.serviceRegister.Deregister(); err != nil {
-		log.Error("failed to deregister the service", errs.ZapError(err))
-	}
+	s.StopServing(s.serviceRegister.Deregister, s.participant.ResetLeader)
 	utils.StopHTTPServer(s)
 	utils.StopGRPCServer(s)
 	s.GetListener().Close()
@@ -338,6 +336,12 @@ func (s *Server) Close() {
 	log.Info("scheduling server is closed")
 }
 
+// ResignPrimary resigns the primary of the scheduling service, the servers
+// including this one campaign for the primary again.
+func (s *Server) ResignPrimary() {
+	s.participant.ResetLeader()
+}
+
 // IsServing returns whether the server is the leader, if there is embedded etcd, or the primary otherwise.
 func (s *Server) IsServing() bool {
 	return !s.IsClosed() && s.participant.IsLeader()
@@ -475,6 +479,31 @@ func (s *Server) startServer() (err error) {
 	return nil
 }
 
+// clusterStorage is the storage of the scheduling cluster. The meta, rules and
+// configs are synchronized into the memory by the watchers, while the slow store
+// events are written to the etcd under the PD root path, so they are shared
+// with the PD servers and survive the primary changes. The events storage is
+// created on every primary election, so its cached event counts are reloaded.
+type clusterStorage struct {
+	*endpoint.StorageEndpoint
+	events *endpoint.StorageEndpoint
+}
+
+// SaveSlowStoreEvent saves the slow store event to the shared storage.
+func (s *clusterStorage) SaveSlowStoreEvent(event *endpoint.SlowStoreEvent) error {
+	return s.events.SaveSlowStoreEvent(event)
+}
+
+// LoadSlowStoreEvents loads the slow store events from the shared storage.
+func (s *clusterStorage) LoadSlowStoreEvents(storeID uint64, startTime, endTime time.Time) ([]*endpoint.SlowStoreEvent, error) {
+	return s.events.LoadSlowStoreEvents(storeID, startTime, endTime)
+}
+
+// ResetBoundedEventCounts resets the cached event counts of the shared storage.
+func (s *clusterStorage) ResetBoundedEventCounts() {
+	s.events.ResetBoundedEventCounts()
+}
+
 func (s *Server) startCluster(context.Context) error {
 	s.basicCluster = core.NewBasicCluster()
 	s.storage = endpoint.NewStorageEndpoint(kv.NewMemoryKV(), nil)
@@ -483,7 +512,11 @@ func (s *Server) startCluster(context.Context) error {
 		return err
 	}
 	s.hbStreams = hbstream.NewHeartbeatStreams(s.Context(), s.clusterID, utils.SchedulingServiceName, s.basicCluster)
-	s.cluster, err = NewCluster(s.Context(), s.persistConfig, s.storage, s.basicCluster, s.hbStreams, s.clusterID, s.checkMembershipCh)
+	clusterStorage := &clusterStorage{
+		StorageEndpoint: s.storage,
+		events:          endpoint.NewStorageEndpoint(kv.NewEtcdKVBase(s.GetClient(), endpoint.PDRootPath(s.clusterID)), nil),
+	}
+	s.cluster, err = NewCluster(s.Context(), s.persistConfig, clusterStorage, s.basicCluster, s.hbStreams, s.clusterID, s.checkMembershipCh)
 	if err != nil {
 		return err
 	}
@@ -504,7 +537,7 @@ func (s *Server) stopCluster() {
 }
 
 func (s *Server) startMetaConfWatcher() (err error) {
-	s.metaWatcher, err = meta.NewWatcher(s.Context(), s.GetClient(), s.clusterID, s.basicCluster)
+	s.metaWatcher, err = meta.NewWatcher(s.Context(), s.GetClient(), s.clusterID, s.basicCluster, s.persistConfig)
 	if err != nil {
 		return err
 	}
@@ -616,7 +649,7 @@ func CreateServerWrapper(cmd *cobra.Command, args []string) {
 
 	var sig os.Signal
 	go func() {
-		sig = <-sc
+		sig = svr.WaitForShutdown(sc, svr.Close)
 		cancel()
 	}()
 
@@ -52,6 +52,9 @@ func GenerateConfig(c *config.Config) (*config.Config, error) {
 		"--listen-addr=" + c.ListenAddr,
 		"--advertise-listen-addr=" + c.AdvertiseListenAddr,
 		"--backend-endpoints=" + c.BackendEndpoints,
+		"--cacert=" + c.Security.CAPath,
+		"--cert=" + c.Security.CertPath,
+		"--key=" + c.Security.KeyPath,
 	}
 
 	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
@@ -20,10 +20,13 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/utils/grpcutil"
 	"go.etcd.io/etcd/clientv3"
@@ -47,6 +50,9 @@ type BaseServer struct {
 	// startCallbacks will be called after the server is started.
 	startCallbacks []func()
 	startTimestamp int64
+	// shutdownCh is closed once the graceful shutdown is requested.
+	shutdownCh   chan struct{}
+	shutdownOnce sync.Once
 }
 
 // NewBaseServer creates a new BaseServer.
@@ -54,6 +60,7 @@ func NewBaseServer(ctx context.Context) *BaseServer {
 	return &BaseServer{
 		ctx:            ctx,
 		startTimestamp: time.Now().Unix(),
+		shutdownCh:     make(chan struct{}),
 	}
 }
 
@@ -179,3 +186,47 @@ func (bs *BaseServer) CloseClientConns() {
 		return true
 	})
 }
+
+// Shutdown requests the server to shut down gracefully as if it receives
+// SIGTERM, it's idempotent.
+func (bs *BaseServer) Shutdown() {
+	bs.shutdownOnce.Do(func() {
+		close(bs.shutdownCh)
+	})
+}
+
+// ShutdownRequested returns a channel which is closed once the graceful
+// shutdown is requested.
+func (bs *BaseServer) ShutdownRequested() <-chan struct{} {
+	return bs.shutdownCh
+}
+
+// StopServing stops the new requests from reaching the server before closing
+// it. It deregisters the server first to stop the new clients from discovering
+// it, then resigns the primary, so that the other servers could take over while
+// the existing streams are being drained.
+func (*BaseServer) StopServing(deregister func() error, resign func()) {
+	if err := deregister(); err != nil {
+		log.Error("failed to deregister the service", errs.ZapError(err))
+	}
+	resign()
+}
+
+// WaitForShutdown waits for the exit signal or the shutdown request and returns
+// the signal, the shutdown request is treated as SIGTERM. On SIGTERM, the server
+// is closed by closeFn before returning, so the caller could cancel the server
+// context after it while the connections have been drained with the server
+// loops still running.
+func (bs *BaseServer) WaitForShutdown(sc <-chan os.Signal, closeFn func()) os.Signal {
+	var sig os.Signal
+	select {
+	case sig = <-sc:
+	case <-bs.shutdownCh:
+		sig = syscall.SIGTERM
+	}
+	if sig == syscall.SIGTERM {
+		log.Info("shutting down the server gracefully")
+		closeFn()
+	}
+	return sig
+}
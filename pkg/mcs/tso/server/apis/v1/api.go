@@ -15,9 +15,11 @@
 package apis
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
@@ -29,8 +31,10 @@ import (
 	tsoserver "github.com/tikv/pd/pkg/mcs/tso/server"
 	"github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/tso"
 	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/pkg/utils/apiutil/multiservicesapi"
+	"github.com/tikv/pd/pkg/utils/etcdutil"
 	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/unrolled/render"
 	"go.uber.org/zap"
@@ -39,6 +43,8 @@ import (
 const (
 	// APIPathPrefix is the prefix of the API path.
 	APIPathPrefix = "/tso/api/v1"
+	// drainTimeout is the timeout to wait for the primaries to be handed off when draining.
+	drainTimeout = 30 * time.Second
 )
 
 var (
@@ -97,6 +103,9 @@ func NewService(srv *tsoserver.Service) *Service {
 	s.RegisterKeyspaceGroupRouter()
 	s.RegisterHealthRouter()
 	s.RegisterConfigRouter()
+	s.RegisterBenchRouter()
+	s.RegisterWatchdogRouter()
+	s.RegisterComponentRouter()
 	return s
 }
 
@@ -105,18 +114,24 @@ func (s *Service) RegisterAdminRouter() {
 	router := s.root.Group("admin")
 	router.POST("/reset-ts", ResetTS)
 	router.PUT("/log", changeLogLevel)
+	router.POST("/shutdown", shutdown)
+	router.POST("/drain", drain)
+	router.POST("/undrain", undrain)
+	router.POST("/resign-primaries", resignPrimaries)
 }
 
 // RegisterKeyspaceGroupRouter registers the router of the TSO keyspace group handler.
 func (s *Service) RegisterKeyspaceGroupRouter() {
 	router := s.root.Group("keyspace-groups")
 	router.GET("/members", GetKeyspaceGroupMembers)
+	router.GET("/requests", getKeyspaceRequests)
 }
 
 // RegisterHealthRouter registers the router of the health handler.
 func (s *Service) RegisterHealthRouter() {
 	router := s.root.Group("health")
 	router.GET("", GetHealth)
+	router.GET("/detail", GetHealthDetail)
 }
 
 // RegisterConfigRouter registers the router of the config handler.
@@ -125,6 +140,24 @@ func (s *Service) RegisterConfigRouter() {
 	router.GET("", getConfig)
 }
 
+// RegisterBenchRouter registers the router of the bench handler.
+func (s *Service) RegisterBenchRouter() {
+	router := s.root.Group("bench")
+	router.POST("", bench)
+}
+
+// RegisterWatchdogRouter registers the router of the watchdog handler.
+func (s *Service) RegisterWatchdogRouter() {
+	router := s.root.Group("watchdog")
+	router.GET("", getWatchdogStatus)
+}
+
+// RegisterComponentRouter registers the router of the component allocations handler.
+func (s *Service) RegisterComponentRouter() {
+	router := s.root.Group("components")
+	router.GET("", getComponentAllocations)
+}
+
 func changeLogLevel(c *gin.Context) {
 	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
 	var level string
@@ -141,6 +174,59 @@ func changeLogLevel(c *gin.Context) {
 	c.String(http.StatusOK, "The log level is updated.")
 }
 
+// @Tags     admin
+// @Summary  Shut down the server gracefully.
+// @Produce  json
+// @Success  200  {string}  string  "The server is shutting down."
+// @Router   /admin/shutdown [post]
+func shutdown(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
+	svr.Shutdown()
+	c.String(http.StatusOK, "The server is shutting down.")
+}
+
+// @Tags     admin
+// @Summary  Hand off the primaries held by the server to the other members and stop accepting the TSO requests.
+// @Produce  json
+// @Success  200  {string}  string  "The server is drained."
+// @Failure  500  {string}  string  "TSO server failed to proceed the request."
+// @Router   /admin/drain [post]
+func drain(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), drainTimeout)
+	defer cancel()
+	if err := svr.Drain(ctx); err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.String(http.StatusOK, "The server is drained.")
+}
+
+// @Tags     admin
+// @Summary  Make the drained server accept the TSO requests and campaign the primaries again.
+// @Produce  json
+// @Success  200  {string}  string  "The server is undrained."
+// @Failure  500  {string}  string  "TSO server failed to proceed the request."
+// @Router   /admin/undrain [post]
+func undrain(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
+	if err := svr.Undrain(); err != nil {
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.String(http.StatusOK, "The server is undrained.")
+}
+
+// @Tags     admin
+// @Summary  Resign the primaries held by the server, the keyspace groups elect the primaries again.
+// @Produce  json
+// @Success  200  {array}  uint32  "The IDs of the keyspace groups whose primaries are resigned."
+// @Router   /admin/resign-primaries [post]
+func resignPrimaries(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
+	c.IndentedJSON(http.StatusOK, svr.GetKeyspaceGroupManager().ResignPrimaries())
+}
+
 // ResetTSParams is the input json body params of ResetTS
 type ResetTSParams struct {
 	TSO           string `json:"tso"`
@@ -208,6 +294,14 @@ func ResetTS(c *gin.Context) {
 	c.String(http.StatusOK, "Reset ts successfully.")
 }
 
+// Health is the health status of the TSO server.
+type Health struct {
+	// BackendConnected is true if the server can reach the backend, i.e. etcd.
+	BackendConnected bool `json:"backend_connected"`
+	// KeyspaceGroups are the health status of the keyspace groups served by the server.
+	KeyspaceGroups map[uint32]*tso.KeyspaceGroupHealth `json:"keyspace_groups"`
+}
+
 // GetHealth returns the health status of the TSO service.
 func GetHealth(c *gin.Context) {
 	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
@@ -224,6 +318,25 @@ func GetHealth(c *gin.Context) {
 	c.String(http.StatusInternalServerError, "no leader elected")
 }
 
+// @Tags     health
+// @Summary  Get the health status of the TSO server, including the serving status and the last saved timestamp of each keyspace group and the backend connectivity.
+// @Produce  json
+// @Success  200  {object}  Health
+// @Failure  503  {object}  Health  "The backend is unreachable or the default keyspace group has no primary."
+// @Router   /health/detail [get]
+func GetHealthDetail(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
+	health := &Health{
+		BackendConnected: etcdutil.IsHealthy(c.Request.Context(), svr.GetClient()),
+		KeyspaceGroups:   svr.GetKeyspaceGroupManager().GetKeyspaceGroupHealth(),
+	}
+	if defaultGroup, ok := health.KeyspaceGroups[utils.DefaultKeyspaceGroupID]; health.BackendConnected && ok && defaultGroup.PrimaryElected {
+		c.IndentedJSON(http.StatusOK, health)
+		return
+	}
+	c.IndentedJSON(http.StatusServiceUnavailable, health)
+}
+
 // KeyspaceGroupMember contains the keyspace group and its member information.
 type KeyspaceGroupMember struct {
 	Group     *endpoint.KeyspaceGroup
@@ -256,6 +369,16 @@ func GetKeyspaceGroupMembers(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, members)
 }
 
+// @Tags     tso
+// @Summary  Get the number of the TSO requests handled for each keyspace since the server started.
+// @Produce  json
+// @Success  200  {object}  map[uint32]uint64
+// @Router   /keyspace-groups/requests [get]
+func getKeyspaceRequests(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
+	c.IndentedJSON(http.StatusOK, svr.GetKeyspaceGroupManager().GetKeyspaceRequests())
+}
+
 // @Tags     config
 // @Summary  Get full config.
 // @Produce  json
@@ -265,3 +388,58 @@ func getConfig(c *gin.Context) {
 	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
 	c.IndentedJSON(http.StatusOK, svr.GetConfig())
 }
+
+// @Tags     bench
+// @Summary  Generate the synthetic TSO allocation load and report the throughput and latency.
+// @Accept   json
+// @Param    body  body  tsoserver.BenchParams  false  "json params"
+// @Produce  json
+// @Success  200  {object}  tsoserver.BenchResult
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  403  {string}  string  "The bench API is disabled."
+// @Failure  500  {string}  string  "TSO server failed to proceed the request."
+// @Router   /bench [post]
+func bench(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
+	var params tsoserver.BenchParams
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&params); err != nil {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	result, err := svr.Bench(c.Request.Context(), &params)
+	if err != nil {
+		if errs.ErrBenchAPIDisabled.Equal(err) {
+			c.String(http.StatusForbidden, err.Error())
+			return
+		}
+		if errs.ErrInvalidBenchParams.Equal(err) {
+			c.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.String(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// @Tags     watchdog
+// @Summary  Get the resource usage and the self-protective actions of the watchdog.
+// @Produce  json
+// @Success  200  {object}  tsoserver.WatchdogStatus
+// @Router   /watchdog [get]
+func getWatchdogStatus(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
+	c.IndentedJSON(http.StatusOK, svr.GetWatchdogStatus())
+}
+
+// @Tags     tso
+// @Summary  Get the TSO allocation counts of the calling components since the server started.
+// @Produce  json
+// @Success  200  {array}  tso.ComponentAllocation
+// @Router   /components [get]
+func getComponentAllocations(c *gin.Context) {
+	svr := c.MustGet(multiservicesapi.ServiceContextKey).(*tsoserver.Service)
+	c.IndentedJSON(http.StatusOK, svr.GetComponentAllocations())
+}
@@ -0,0 +1,230 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/tso"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBenchConcurrency = 16
+	maxBenchConcurrency     = 1024
+	defaultBenchBatchSize   = 1
+	maxBenchBatchSize       = 10000
+	defaultBenchDuration    = 10 * time.Second
+	maxBenchDuration        = time.Minute
+	// benchErrorBackoff is how long a worker waits after a failed request, so
+	// the workers don't spin on the persistent errors.
+	benchErrorBackoff = 10 * time.Millisecond
+	// The latencies are recorded in the buckets growing by latencyBucketGrowth
+	// from latencyBucketMin, so the quantiles are within 5% of the real ones and
+	// the memory doesn't grow with the requests.
+	latencyBucketMin    = time.Microsecond
+	latencyBucketGrowth = 1.05
+	latencyBucketCount  = 512
+)
+
+// BenchParams is the params of the TSO bench.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type BenchParams struct {
+	KeyspaceID      uint32 `json:"keyspace-id"`
+	KeyspaceGroupID uint32 `json:"keyspace-group-id"`
+	// Concurrency is the number of the workers allocating the TSO concurrently.
+	Concurrency int `json:"concurrency"`
+	// BatchSize is the count of the TSO allocated in one request.
+	BatchSize uint32 `json:"batch-size"`
+	// Duration is how long the bench lasts, e.g. "10s".
+	Duration string `json:"duration"`
+}
+
+// BenchResult is the result of the TSO bench.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type BenchResult struct {
+	Concurrency  int           `json:"concurrency"`
+	BatchSize    uint32        `json:"batch-size"`
+	Duration     time.Duration `json:"duration"`
+	Requests     uint64        `json:"requests"`
+	Timestamps   uint64        `json:"timestamps"`
+	Errors       uint64        `json:"errors"`
+	RequestQPS   float64       `json:"request-qps"`
+	TimestampQPS float64       `json:"timestamp-qps"`
+	LatencyAvg   time.Duration `json:"latency-avg"`
+	LatencyP50   time.Duration `json:"latency-p50"`
+	LatencyP99   time.Duration `json:"latency-p99"`
+	LatencyMax   time.Duration `json:"latency-max"`
+}
+
+func (p *BenchParams) adjust() (time.Duration, error) {
+	if p.Concurrency <= 0 {
+		p.Concurrency = defaultBenchConcurrency
+	} else if p.Concurrency > maxBenchConcurrency {
+		p.Concurrency = maxBenchConcurrency
+	}
+	if p.BatchSize == 0 {
+		p.BatchSize = defaultBenchBatchSize
+	} else if p.BatchSize > maxBenchBatchSize {
+		p.BatchSize = maxBenchBatchSize
+	}
+	duration := defaultBenchDuration
+	if len(p.Duration) > 0 {
+		d, err := time.ParseDuration(p.Duration)
+		if err != nil {
+			return 0, errs.ErrInvalidBenchParams.FastGenByArgs(err.Error())
+		}
+		duration = d
+	}
+	if duration <= 0 || duration > maxBenchDuration {
+		duration = maxBenchDuration
+	}
+	return duration, nil
+}
+
+// Bench generates the synthetic TSO allocation load locally and reports the
+// throughput and latency of the allocation. It only works when the bench API is enabled,
+// and returns ErrInvalidBenchParams if the params are invalid.
+func (s *Server) Bench(ctx context.Context, params *BenchParams) (*BenchResult, error) {
+	if !s.GetConfig().IsBenchAPIEnabled() {
+		return nil, errs.ErrBenchAPIDisabled
+	}
+	duration, err := params.adjust()
+	if err != nil {
+		return nil, err
+	}
+	// make sure the TSO can be allocated before generating the load.
+	if _, _, err := s.keyspaceGroupManager.HandleTSORequest(ctx, params.KeyspaceID, params.KeyspaceGroupID, tso.GlobalDCLocation, 1); err != nil {
+		return nil, err
+	}
+	log.Info("start tso bench",
+		zap.Uint32("keyspace-id", params.KeyspaceID),
+		zap.Uint32("keyspace-group-id", params.KeyspaceGroupID),
+		zap.Int("concurrency", params.Concurrency),
+		zap.Uint32("batch-size", params.BatchSize),
+		zap.Duration("duration", duration))
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		latencies latencyHistogram
+		errCount  uint64
+	)
+	start := time.Now()
+	for i := 0; i < params.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var (
+				local  latencyHistogram
+				failed uint64
+			)
+			for ctx.Err() == nil {
+				reqStart := time.Now()
+				_, _, err := s.keyspaceGroupManager.HandleTSORequest(ctx, params.KeyspaceID, params.KeyspaceGroupID, tso.GlobalDCLocation, params.BatchSize)
+				if err != nil {
+					failed++
+					select {
+					case <-ctx.Done():
+					case <-time.After(benchErrorBackoff):
+					}
+					continue
+				}
+				local.observe(time.Since(reqStart))
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			latencies.merge(&local)
+			errCount += failed
+		}()
+	}
+	wg.Wait()
+	return newBenchResult(params, time.Since(start), &latencies, errCount), nil
+}
+
+func newBenchResult(params *BenchParams, elapsed time.Duration, latencies *latencyHistogram, errCount uint64) *BenchResult {
+	result := &BenchResult{
+		Concurrency: params.Concurrency,
+		BatchSize:   params.BatchSize,
+		Duration:    elapsed,
+		Requests:    latencies.count,
+		Timestamps:  latencies.count * uint64(params.BatchSize),
+		Errors:      errCount,
+	}
+	if latencies.count == 0 || elapsed <= 0 {
+		return result
+	}
+	result.RequestQPS = float64(result.Requests) / elapsed.Seconds()
+	result.TimestampQPS = float64(result.Timestamps) / elapsed.Seconds()
+	result.LatencyAvg = latencies.sum / time.Duration(latencies.count)
+	result.LatencyP50 = latencies.quantile(50)
+	result.LatencyP99 = latencies.quantile(99)
+	result.LatencyMax = latencies.max
+	return result
+}
+
+// latencyHistogram records the latencies of the bench requests.
+type latencyHistogram struct {
+	buckets [latencyBucketCount]uint64
+	count   uint64
+	sum     time.Duration
+	max     time.Duration
+}
+
+func latencyBucket(d time.Duration) int {
+	if d <= latencyBucketMin {
+		return 0
+	}
+	idx := int(math.Ceil(math.Log(float64(d)/float64(latencyBucketMin)) / math.Log(latencyBucketGrowth)))
+	return min(idx, latencyBucketCount-1)
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	h.buckets[latencyBucket(d)]++
+	h.count++
+	h.sum += d
+	h.max = max(h.max, d)
+}
+
+func (h *latencyHistogram) merge(other *latencyHistogram) {
+	for i, c := range other.buckets {
+		h.buckets[i] += c
+	}
+	h.count += other.count
+	h.sum += other.sum
+	h.max = max(h.max, other.max)
+}
+
+// quantile returns the upper bound of the bucket holding the given percentile.
+func (h *latencyHistogram) quantile(percent uint64) time.Duration {
+	rank := h.count * percent / 100
+	var seen uint64
+	for i, c := range h.buckets {
+		seen += c
+		// the last bucket holds all the larger latencies, so fall back to the max.
+		if seen > rank && i < latencyBucketCount-1 {
+			upper := time.Duration(float64(latencyBucketMin) * math.Pow(latencyBucketGrowth, float64(i)))
+			return min(upper, h.max)
+		}
+	}
+	return h.max
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/errs"
+)
+
+func TestBenchParams(t *testing.T) {
+	re := require.New(t)
+	params := &BenchParams{}
+	duration, err := params.adjust()
+	re.NoError(err)
+	re.Equal(defaultBenchDuration, duration)
+	re.Equal(defaultBenchConcurrency, params.Concurrency)
+	re.Equal(uint32(defaultBenchBatchSize), params.BatchSize)
+
+	params = &BenchParams{Concurrency: 1 << 20, BatchSize: 1 << 20, Duration: "1h"}
+	duration, err = params.adjust()
+	re.NoError(err)
+	re.Equal(maxBenchDuration, duration)
+	re.Equal(maxBenchConcurrency, params.Concurrency)
+	re.Equal(uint32(maxBenchBatchSize), params.BatchSize)
+
+	params = &BenchParams{Duration: "abc"}
+	_, err = params.adjust()
+	re.True(errs.ErrInvalidBenchParams.Equal(err))
+
+	// the bench API is disabled by default.
+	s := &Server{cfg: NewConfig()}
+	_, err = s.Bench(context.Background(), &BenchParams{})
+	re.ErrorIs(err, errs.ErrBenchAPIDisabled)
+}
+
+func TestBenchResult(t *testing.T) {
+	re := require.New(t)
+	params := &BenchParams{Concurrency: 2, BatchSize: 10}
+	result := newBenchResult(params, time.Second, &latencyHistogram{}, 3)
+	re.Zero(result.Requests)
+	re.Equal(uint64(3), result.Errors)
+	re.Zero(result.RequestQPS)
+
+	var latencies latencyHistogram
+	for i := 100; i > 0; i-- {
+		latencies.observe(time.Duration(i) * time.Millisecond)
+	}
+	result = newBenchResult(params, 2*time.Second, &latencies, 0)
+	re.Equal(uint64(100), result.Requests)
+	re.Equal(uint64(1000), result.Timestamps)
+	re.Equal(50.0, result.RequestQPS)
+	re.Equal(500.0, result.TimestampQPS)
+	re.Equal(50500*time.Microsecond, result.LatencyAvg)
+	re.InEpsilon(51*time.Millisecond, result.LatencyP50, latencyBucketGrowth-1)
+	re.InEpsilon(100*time.Millisecond, result.LatencyP99, latencyBucketGrowth-1)
+	re.Equal(100*time.Millisecond, result.LatencyMax)
+
+	// the latencies out of the buckets are still counted.
+	latencies = latencyHistogram{}
+	latencies.observe(0)
+	latencies.observe(1000 * time.Hour)
+	re.Equal(uint64(1), latencies.buckets[0])
+	re.Equal(uint64(1), latencies.buckets[latencyBucketCount-1])
+	re.Equal(1000*time.Hour, latencies.quantile(99))
+}
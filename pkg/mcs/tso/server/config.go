@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/spf13/pflag"
+	"github.com/tikv/pd/pkg/mcs/discovery"
 	"github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/tso"
 	"github.com/tikv/pd/pkg/utils/configutil"
@@ -43,8 +44,8 @@ const (
 
 	defaultTSOSaveInterval           = time.Duration(utils.DefaultLeaderLease) * time.Second
 	defaultTSOUpdatePhysicalInterval = 50 * time.Millisecond
-	maxTSOUpdatePhysicalInterval     = 10 * time.Second
-	minTSOUpdatePhysicalInterval     = 1 * time.Millisecond
+
+	defaultWatchdogCheckInterval = time.Second
 )
 
 var _ tso.ServiceConfig = (*Config)(nil)
@@ -64,6 +65,10 @@ type Config struct {
 	// the primary/leader again. Etcd only supports seconds TTL, so here is second too.
 	LeaderLease int64 `toml:"lease" json:"lease"`
 
+	// DiscoveryLease is the TTL in seconds of the registry of the server in the service
+	// discovery. The server is considered gone once it fails to keep the lease alive.
+	DiscoveryLease int64 `toml:"discovery-lease" json:"discovery-lease"`
+
 	// EnableLocalTSO is used to enable the Local TSO Allocator feature,
 	// which allows the PD server to generate Local TSO for certain DC-level transactions.
 	// To make this feature meaningful, user has to set the "zone" label for the PD server
@@ -80,11 +85,42 @@ type Config struct {
 	// be automatically clamped to the range.
 	TSOUpdatePhysicalInterval typeutil.Duration `toml:"tso-update-physical-interval" json:"tso-update-physical-interval"`
 
+	// TSOProxyMaxBatchWait is the max time to wait for more requests to merge when forwarding
+	// the TSO requests to the primaries on the other servers. The wait window grows with the
+	// request rate up to it, and 0 disables the adaptive batching. It can be capped by the
+	// keyspace group.
+	TSOProxyMaxBatchWait typeutil.Duration `toml:"tso-proxy-max-batch-wait" json:"tso-proxy-max-batch-wait"`
+	// TSOProxyMaxBatchSize is the max logical count of a merged request forwarded to the
+	// primaries on the other servers. 0 means no limit. It can be capped by the keyspace group.
+	TSOProxyMaxBatchSize uint32 `toml:"tso-proxy-max-batch-size" json:"tso-proxy-max-batch-size"`
+
 	// MaxResetTSGap is the max gap to reset the TSO.
 	MaxResetTSGap typeutil.Duration `toml:"max-gap-reset-ts" json:"max-gap-reset-ts"`
 
+	// MaxClockDrift is the max drift of the local clock against the peers. Once
+	// it's exceeded, the TSO primary/leader resigns and the member can't become
+	// the TSO primary/leader again until the drift recovers. 0 means no limit.
+	MaxClockDrift typeutil.Duration `toml:"max-clock-drift" json:"max-clock-drift"`
+
+	// EnableBenchAPI is used to enable the bench API, which generates the synthetic
+	// TSO allocation load locally. It should not be enabled in production.
+	EnableBenchAPI bool `toml:"enable-bench-api" json:"enable-bench-api"`
+
+	// Watchdog is used to protect the TSO server from being overloaded.
+	Watchdog WatchdogConfig `toml:"watchdog" json:"watchdog"`
+
+	// Labels are the labels of the TSO server, e.g. zone. They are registered
+	// with the service, so that the primaries of the keyspace groups can be
+	// co-located with the data.
+	Labels map[string]string `toml:"labels" json:"labels"`
+
 	Metric metricutil.MetricConfig `toml:"metric" json:"metric"`
 
+	// RootPathPrefix namespaces all the keys of the server in the backend, so
+	// the servers with different prefixes can share one backend without seeing
+	// each other. It's only used in tests.
+	RootPathPrefix string `toml:"-" json:"-"`
+
 	// WarningMsgs contains all warnings during parsing.
 	WarningMsgs []string
 
@@ -97,6 +133,29 @@ type Config struct {
 	Security configutil.SecurityConfig `toml:"security" json:"security"`
 }
 
+// WatchdogConfig is the configuration of the watchdog, which sheds the load by
+// rejecting the new TSO streams when the resource limits are breached.
+type WatchdogConfig struct {
+	// CheckInterval is the interval to check the resource usage.
+	CheckInterval typeutil.Duration `toml:"check-interval" json:"check-interval"`
+	// MaxGoroutines is the max number of the goroutines, 0 means no limit.
+	MaxGoroutines int `toml:"max-goroutines" json:"max-goroutines"`
+	// MaxTSOStreams is the max number of the concurrent TSO streams, 0 means no limit.
+	MaxTSOStreams int `toml:"max-tso-streams" json:"max-tso-streams"`
+	// MaxMemory is the max heap memory in use, 0 means no limit.
+	MaxMemory typeutil.ByteSize `toml:"max-memory" json:"max-memory"`
+}
+
+func (c *WatchdogConfig) adjust() {
+	configutil.AdjustDuration(&c.CheckInterval, defaultWatchdogCheckInterval)
+}
+
+// hasResourceLimits returns whether any resource usage needs to be checked
+// periodically, the TSO streams are limited when they are established.
+func (c *WatchdogConfig) hasResourceLimits() bool {
+	return c.MaxGoroutines > 0 || c.MaxMemory > 0
+}
+
 // NewConfig creates a new config.
 func NewConfig() *Config {
 	return &Config{}
@@ -127,6 +186,11 @@ func (c *Config) GetLeaderLease() int64 {
 	return c.LeaderLease
 }
 
+// GetDiscoveryLease returns the TTL of the registry in the service discovery.
+func (c *Config) GetDiscoveryLease() int64 {
+	return c.DiscoveryLease
+}
+
 // IsLocalTSOEnabled returns if the local TSO is enabled.
 func (c *Config) IsLocalTSOEnabled() bool {
 	return c.EnableLocalTSO
@@ -142,11 +206,31 @@ func (c *Config) GetTSOSaveInterval() time.Duration {
 	return c.TSOSaveInterval.Duration
 }
 
+// GetTSOProxyMaxBatchWait returns the max time to wait for more requests to merge when forwarding them.
+func (c *Config) GetTSOProxyMaxBatchWait() time.Duration {
+	return c.TSOProxyMaxBatchWait.Duration
+}
+
+// GetTSOProxyMaxBatchSize returns the max logical count of a merged request forwarded to the primaries.
+func (c *Config) GetTSOProxyMaxBatchSize() uint32 {
+	return c.TSOProxyMaxBatchSize
+}
+
 // GetMaxResetTSGap returns the MaxResetTSGap.
 func (c *Config) GetMaxResetTSGap() time.Duration {
 	return c.MaxResetTSGap.Duration
 }
 
+// GetMaxClockDrift returns the max drift of the local clock.
+func (c *Config) GetMaxClockDrift() time.Duration {
+	return c.MaxClockDrift.Duration
+}
+
+// IsBenchAPIEnabled returns if the bench API is enabled.
+func (c *Config) IsBenchAPIEnabled() bool {
+	return c.EnableBenchAPI
+}
+
 // GetTLSConfig returns the TLS config.
 func (c *Config) GetTLSConfig() *grpcutil.TLSConfig {
 	return &c.Security.TLSConfig
@@ -206,19 +290,22 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 
 	configutil.AdjustDuration(&c.MaxResetTSGap, defaultMaxResetTSGap)
 	configutil.AdjustInt64(&c.LeaderLease, utils.DefaultLeaderLease)
+	configutil.AdjustInt64(&c.DiscoveryLease, discovery.DefaultLeaseInSeconds)
 	configutil.AdjustDuration(&c.TSOSaveInterval, defaultTSOSaveInterval)
 	configutil.AdjustDuration(&c.TSOUpdatePhysicalInterval, defaultTSOUpdatePhysicalInterval)
 
-	if c.TSOUpdatePhysicalInterval.Duration > maxTSOUpdatePhysicalInterval {
-		c.TSOUpdatePhysicalInterval.Duration = maxTSOUpdatePhysicalInterval
-	} else if c.TSOUpdatePhysicalInterval.Duration < minTSOUpdatePhysicalInterval {
-		c.TSOUpdatePhysicalInterval.Duration = minTSOUpdatePhysicalInterval
+	if c.TSOUpdatePhysicalInterval.Duration > utils.MaxTSOUpdatePhysicalInterval {
+		c.TSOUpdatePhysicalInterval.Duration = utils.MaxTSOUpdatePhysicalInterval
+	} else if c.TSOUpdatePhysicalInterval.Duration < utils.MinTSOUpdatePhysicalInterval {
+		c.TSOUpdatePhysicalInterval.Duration = utils.MinTSOUpdatePhysicalInterval
 	}
 	if c.TSOUpdatePhysicalInterval.Duration != defaultTSOUpdatePhysicalInterval {
 		log.Warn("tso update physical interval is non-default",
 			zap.Duration("update-physical-interval", c.TSOUpdatePhysicalInterval.Duration))
 	}
 
+	c.Watchdog.adjust()
+
 	if !configMetaData.IsDefined("enable-grpc-gateway") {
 		c.EnableGRPCGateway = utils.DefaultEnableGRPCGateway
 	}
@@ -252,6 +339,9 @@ func (c *Config) Validate() error {
 	if !strings.HasPrefix(rel, "..") {
 		return errors.New("log directory shouldn't be the subdirectory of data directory")
 	}
+	if c.DiscoveryLease < 0 {
+		return errors.Errorf("discovery-lease %d shouldn't be negative", c.DiscoveryLease)
+	}
 
 	return nil
 }
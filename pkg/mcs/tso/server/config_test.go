@@ -21,6 +21,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/mcs/discovery"
 	"github.com/tikv/pd/pkg/mcs/utils"
 )
 
@@ -36,11 +37,14 @@ func TestConfigBasic(t *testing.T) {
 	re.Equal(defaultBackendEndpoints, cfg.BackendEndpoints)
 	re.Equal(defaultListenAddr, cfg.ListenAddr)
 	re.Equal(utils.DefaultLeaderLease, cfg.LeaderLease)
+	re.Equal(int64(discovery.DefaultLeaseInSeconds), cfg.DiscoveryLease)
 	re.False(cfg.EnableLocalTSO)
 	re.True(cfg.EnableGRPCGateway)
 	re.Equal(defaultTSOSaveInterval, cfg.TSOSaveInterval.Duration)
 	re.Equal(defaultTSOUpdatePhysicalInterval, cfg.TSOUpdatePhysicalInterval.Duration)
 	re.Equal(defaultMaxResetTSGap, cfg.MaxResetTSGap.Duration)
+	re.Equal(defaultWatchdogCheckInterval, cfg.Watchdog.CheckInterval.Duration)
+	re.Zero(cfg.Watchdog.MaxTSOStreams)
 
 	// Test setting values.
 	cfg.Name = "test-name"
@@ -48,6 +52,7 @@ func TestConfigBasic(t *testing.T) {
 	cfg.ListenAddr = "test-listen-addr"
 	cfg.AdvertiseListenAddr = "test-advertise-listen-addr"
 	cfg.LeaderLease = 123
+	cfg.DiscoveryLease = 5
 	cfg.EnableLocalTSO = true
 	cfg.TSOSaveInterval.Duration = time.Duration(10) * time.Second
 	cfg.TSOUpdatePhysicalInterval.Duration = time.Duration(100) * time.Millisecond
@@ -58,6 +63,7 @@ func TestConfigBasic(t *testing.T) {
 	re.Equal("test-listen-addr", cfg.GetListenAddr())
 	re.Equal("test-advertise-listen-addr", cfg.GetAdvertiseListenAddr())
 	re.Equal(int64(123), cfg.GetLeaderLease())
+	re.Equal(int64(5), cfg.GetDiscoveryLease())
 	re.True(cfg.EnableLocalTSO)
 	re.Equal(time.Duration(10)*time.Second, cfg.TSOSaveInterval.Duration)
 	re.Equal(time.Duration(100)*time.Millisecond, cfg.TSOUpdatePhysicalInterval.Duration)
@@ -74,6 +80,7 @@ name = "tso-test-name"
 data-dir = "/var/lib/tso"
 enable-grpc-gateway = false
 lease = 123
+discovery-lease = 5
 enable-local-tso = true
 tso-save-interval = "10s"
 tso-update-physical-interval = "100ms"
@@ -92,6 +99,7 @@ max-gap-reset-ts = "1h"
 	re.Equal("test-advertise-listen-addr", cfg.GetAdvertiseListenAddr())
 	re.Equal("/var/lib/tso", cfg.DataDir)
 	re.Equal(int64(123), cfg.GetLeaderLease())
+	re.Equal(int64(5), cfg.GetDiscoveryLease())
 	re.True(cfg.EnableLocalTSO)
 	re.Equal(time.Duration(10)*time.Second, cfg.TSOSaveInterval.Duration)
 	re.Equal(time.Duration(100)*time.Millisecond, cfg.TSOUpdatePhysicalInterval.Duration)
@@ -0,0 +1,109 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/pingcap/kvproto/pkg/tsopb"
+	"github.com/tikv/pd/pkg/utils/logutil"
+	"github.com/tikv/pd/pkg/utils/tsoutil"
+)
+
+// maxPendingTSOResponses is the max number of the responses waiting to be sent
+// back through one stream.
+const maxPendingTSOResponses = 1024
+
+// tsoResponseSender sends the responses of a Tso stream back in the order of the
+// requests. The responses of the local requests are ready at once while the ones
+// of the forwarded requests are filled by the dispatcher later, sending all of them
+// from one goroutine keeps the stream from being written concurrently.
+type tsoResponseSender struct {
+	stream  tsopb.TSO_TsoServer
+	pending chan chan *tsoutil.TSOProtoResult
+	// done is closed after the sender exits, err is the reason if it's not nil.
+	done chan struct{}
+	err  error
+}
+
+func newTSOResponseSender(ctx context.Context, stream tsopb.TSO_TsoServer) *tsoResponseSender {
+	sender := &tsoResponseSender{
+		stream:  stream,
+		pending: make(chan chan *tsoutil.TSOProtoResult, maxPendingTSOResponses),
+		done:    make(chan struct{}),
+	}
+	go sender.run(ctx, sender.pending)
+	return sender
+}
+
+// reserve reserves the place of the response of the next request, the result
+// should be sent to the returned channel exactly once.
+func (s *tsoResponseSender) reserve() (chan *tsoutil.TSOProtoResult, error) {
+	resultCh := make(chan *tsoutil.TSOProtoResult, 1)
+	select {
+	case s.pending <- resultCh:
+		return resultCh, nil
+	case <-s.done:
+		return nil, s.err
+	}
+}
+
+// exited returns the error if the sender has exited.
+func (s *tsoResponseSender) exited() (bool, error) {
+	select {
+	case <-s.done:
+		return true, s.err
+	default:
+		return false, nil
+	}
+}
+
+// wait waits for the reserved responses to be sent and returns the first error.
+func (s *tsoResponseSender) wait() error {
+	s.close()
+	<-s.done
+	return s.err
+}
+
+// close stops accepting new responses, it's safe to be called more than once
+// by the goroutine receiving the requests.
+func (s *tsoResponseSender) close() {
+	if s.pending != nil {
+		close(s.pending)
+		s.pending = nil
+	}
+}
+
+func (s *tsoResponseSender) run(ctx context.Context, pending <-chan chan *tsoutil.TSOProtoResult) {
+	defer logutil.LogPanic()
+	defer close(s.done)
+	for resultCh := range pending {
+		var result *tsoutil.TSOProtoResult
+		select {
+		case result = <-resultCh:
+		case <-ctx.Done():
+			s.err = ctx.Err()
+			return
+		}
+		if result.Err != nil {
+			s.err = result.Err
+			return
+		}
+		if err := s.stream.Send(result.Response); err != nil {
+			s.err = err
+			return
+		}
+	}
+}
@@ -27,6 +27,8 @@ import (
 	bs "github.com/tikv/pd/pkg/basicserver"
 	"github.com/tikv/pd/pkg/mcs/registry"
 	"github.com/tikv/pd/pkg/utils/apiutil"
+	"github.com/tikv/pd/pkg/utils/grpcutil"
+	"github.com/tikv/pd/pkg/utils/tsoutil"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -36,6 +38,7 @@ import (
 var (
 	ErrNotStarted        = status.Errorf(codes.Unavailable, "server not started")
 	ErrClusterMismatched = status.Errorf(codes.Unavailable, "cluster mismatched")
+	ErrDraining          = status.Errorf(codes.Unavailable, "server is draining")
 )
 
 var _ tsopb.TSOServer = (*Service)(nil)
@@ -85,12 +88,27 @@ func (s *Service) RegisterRESTHandler(userDefineHandlers map[string]http.Handler
 
 // Tso returns a stream of timestamps
 func (s *Service) Tso(stream tsopb.TSO_TsoServer) error {
+	if err := s.watchdog.acquireStream(); err != nil {
+		return status.Errorf(codes.ResourceExhausted, err.Error())
+	}
+	defer s.watchdog.releaseStream()
 	ctx, cancel := context.WithCancel(stream.Context())
 	defer cancel()
+	// All the responses are sent by the sender in the order of the requests, no matter
+	// whether they are handled locally or forwarded to the primaries.
+	sender := newTSOResponseSender(ctx, stream)
+	defer sender.close()
+	component := grpcutil.GetComponent(stream.Context())
+	// The requests forwarded by the other members are always handled locally to avoid
+	// forwarding them around when the members have different views of the primary.
+	forwarded := grpcutil.GetForwardedHost(stream.Context()) != ""
 	for {
+		if exited, err := sender.exited(); exited {
+			return errors.WithStack(err)
+		}
 		request, err := stream.Recv()
 		if err == io.EOF {
-			return nil
+			return errors.WithStack(sender.wait())
 		}
 		if err != nil {
 			return errors.WithStack(err)
@@ -101,6 +119,9 @@ func (s *Service) Tso(stream tsopb.TSO_TsoServer) error {
 		if s.IsClosed() {
 			return status.Errorf(codes.Unknown, "server not started")
 		}
+		if s.keyspaceGroupManager.IsDraining() {
+			return ErrDraining
+		}
 		header := request.GetHeader()
 		clusterID := header.GetClusterId()
 		if clusterID != s.clusterID {
@@ -108,8 +129,29 @@ func (s *Service) Tso(stream tsopb.TSO_TsoServer) error {
 				codes.FailedPrecondition, "mismatch cluster id, need %d but got %d",
 				s.clusterID, clusterID)
 		}
+		resultCh, err := sender.reserve()
+		if err != nil {
+			return errors.WithStack(err)
+		}
 		keyspaceID := header.GetKeyspaceId()
 		keyspaceGroupID := header.GetKeyspaceGroupId()
+		if !forwarded {
+			if primaryAddr, curKeyspaceGroupID := s.keyspaceGroupManager.GetPrimaryToForward(
+				keyspaceID, keyspaceGroupID); primaryAddr != "" {
+				clientConn, err := s.GetDelegateClient(s.Context(), s.GetTLSConfig(), primaryAddr)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				// Forward the request to the keyspace group serving the keyspace, so that
+				// the requests of the same keyspace group can be merged. The dispatcher is
+				// shared by the streams, so the errors are reported by the requests.
+				header.KeyspaceGroupId = curKeyspaceGroupID
+				tsoRequest := tsoutil.NewTSOProtoRequest(primaryAddr, clientConn, request, resultCh)
+				s.tsoDispatcher.DispatchRequest(grpcutil.BuildForwardContext(s.serverLoopCtx, primaryAddr),
+					tsoRequest, s.tsoProtoFactory, nil, nil)
+				continue
+			}
+		}
 		dcLocation := request.GetDcLocation()
 		count := request.GetCount()
 		ts, keyspaceGroupBelongTo, err := s.keyspaceGroupManager.HandleTSORequest(
@@ -119,15 +161,15 @@ func (s *Service) Tso(stream tsopb.TSO_TsoServer) error {
 		if err != nil {
 			return status.Errorf(codes.Unknown, err.Error())
 		}
+		s.componentAllocations.Observe(component, count)
 		keyspaceGroupIDStr := strconv.FormatUint(uint64(keyspaceGroupID), 10)
 		tsoHandleDuration.WithLabelValues(keyspaceGroupIDStr).Observe(time.Since(start).Seconds())
-		response := &tsopb.TsoResponse{
-			Header:    s.header(keyspaceGroupBelongTo),
-			Timestamp: &ts,
-			Count:     count,
-		}
-		if err := stream.Send(response); err != nil {
-			return errors.WithStack(err)
+		resultCh <- &tsoutil.TSOProtoResult{
+			Response: &tsopb.TsoResponse{
+				Header:    s.header(keyspaceGroupBelongTo),
+				Timestamp: &ts,
+				Count:     count,
+			},
 		}
 	}
 }
@@ -43,10 +43,66 @@ var (
 			Help:      "Bucketed histogram of processing time (s) of handled tso requests.",
 			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 13),
 		}, []string{"group"})
+
+	tsoProxyHandleDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "server",
+			Name:      "handle_tso_proxy_duration_seconds",
+			Help:      "Bucketed histogram of processing time (s) of the tso requests forwarded to the primaries.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 13),
+		})
+
+	tsoProxyBatchSize = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "server",
+			Name:      "handle_tso_proxy_batch_size",
+			Help:      "Bucketed histogram of the batch size of the tso requests forwarded to the primaries.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 13),
+		})
+
+	tsoStreamGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "server",
+			Name:      "tso_streams",
+			Help:      "The number of the concurrent tso streams.",
+		})
+
+	watchdogSheddingGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "watchdog",
+			Name:      "shedding",
+			Help:      "Whether the watchdog is shedding the load, 1 means shedding.",
+		})
+
+	watchdogActionCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "watchdog",
+			Name:      "actions_total",
+			Help:      "Counter of the self-protective actions taken by the watchdog.",
+		}, []string{"action"})
+
+	watchdogRejectedStreamCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "watchdog",
+			Name:      "rejected_streams_total",
+			Help:      "Counter of the tso streams rejected by the watchdog.",
+		}, []string{"reason"})
 )
 
 func init() {
 	prometheus.MustRegister(timeJumpBackCounter)
 	prometheus.MustRegister(metaDataGauge)
 	prometheus.MustRegister(tsoHandleDuration)
+	prometheus.MustRegister(tsoProxyHandleDuration)
+	prometheus.MustRegister(tsoProxyBatchSize)
+	prometheus.MustRegister(tsoStreamGauge)
+	prometheus.MustRegister(watchdogSheddingGauge)
+	prometheus.MustRegister(watchdogActionCounter)
+	prometheus.MustRegister(watchdogRejectedStreamCounter)
 }
@@ -46,9 +46,11 @@ import (
 	"github.com/tikv/pd/pkg/systimemon"
 	"github.com/tikv/pd/pkg/tso"
 	"github.com/tikv/pd/pkg/utils/apiutil"
+	"github.com/tikv/pd/pkg/utils/etcdutil"
 	"github.com/tikv/pd/pkg/utils/grpcutil"
 	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/tikv/pd/pkg/utils/metricutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/tikv/pd/pkg/utils/tsoutil"
 	"github.com/tikv/pd/pkg/versioninfo"
 	"go.uber.org/zap"
@@ -79,14 +81,23 @@ type Server struct {
 
 	service              *Service
 	keyspaceGroupManager *tso.KeyspaceGroupManager
+	watchdog             *watchdog
+	// componentAllocations counts the TSO allocations by the calling components.
+	componentAllocations *tso.ComponentAllocations
 
 	// tsoProtoFactory is the abstract factory for creating tso
 	// related data structures defined in the tso grpc protocol
 	tsoProtoFactory *tsoutil.TSOProtoFactory
+	// tsoDispatcher batches and forwards the tso requests received by the
+	// non-primary members to the primaries.
+	tsoDispatcher *tsoutil.TSODispatcher
 
 	// for service registry
 	serviceID       *discovery.ServiceRegistryEntry
 	serviceRegister *discovery.ServiceRegister
+	// drainMu serializes draining and undraining the server, which deregister and
+	// register the service.
+	drainMu syncutil.Mutex
 }
 
 // Implement the following methods defined in bs.Server
@@ -153,14 +164,12 @@ func (s *Server) Run() error {
 			return err
 		}
 	}
-	go systimemon.StartMonitor(s.Context(), time.Now, func() {
-		log.Error("system time jumps backward", errs.ZapError(errs.ErrIncorrectSystemTime))
-		timeJumpBackCounter.Inc()
-	})
-
 	if err := utils.InitClient(s); err != nil {
 		return err
 	}
+	if prefix := s.cfg.RootPathPrefix; prefix != "" {
+		etcdutil.WithNamespace(s.GetClient(), prefix)
+	}
 	return s.startServer()
 }
 
@@ -172,11 +181,9 @@ func (s *Server) Close() {
 	}
 
 	log.Info("closing tso server ...")
-	// close tso service loops in the keyspace group manager
-	s.keyspaceGroupManager.Close()
-	if err := s.serviceRegister.Deregister(); err != nil {
-		log.Error("failed to deregister the service", errs.ZapError(err))
-	}
+	// Closing the tso service loops in the keyspace group manager resigns the
+	// primaries of the keyspace groups.
+	s.StopServing(s.serviceRegister.Deregister, s.keyspaceGroupManager.Close)
 	utils.StopHTTPServer(s)
 	utils.StopGRPCServer(s)
 	s.GetListener().Close()
@@ -250,6 +257,64 @@ func (s *Server) ResignPrimary(keyspaceID, keyspaceGroupID uint32) error {
 	return nil
 }
 
+// Drain hands off the primaries of the keyspace groups held by this server to the other
+// members and stops accepting the TSO requests, so that shutting down the server afterwards
+// won't stall the TSO of the keyspace groups it serves until the primary leases expire.
+func (s *Server) Drain(ctx context.Context) error {
+	if s.IsClosed() {
+		return ErrNotStarted
+	}
+	s.drainMu.Lock()
+	defer s.drainMu.Unlock()
+	if s.keyspaceGroupManager.IsDraining() {
+		return nil
+	}
+	log.Info("draining tso server ...")
+	// Deregister first to stop the new clients from discovering the server, and the other
+	// members won't defer the primary campaign for this server because of its priority.
+	if err := s.serviceRegister.Deregister(); err != nil {
+		log.Error("failed to deregister the service", errs.ZapError(err))
+	}
+	if err := s.keyspaceGroupManager.Drain(ctx); err != nil {
+		log.Warn("failed to drain tso server", errs.ZapError(err))
+		// The server is undrained by the keyspace group manager, register it again.
+		if err := s.registerService(); err != nil {
+			log.Error("failed to register the service", errs.ZapError(err))
+		}
+		return err
+	}
+	log.Info("tso server is drained")
+	return nil
+}
+
+// Undrain makes the drained server accept the TSO requests and campaign the primaries again.
+func (s *Server) Undrain() error {
+	if s.IsClosed() {
+		return ErrNotStarted
+	}
+	s.drainMu.Lock()
+	defer s.drainMu.Unlock()
+	if !s.keyspaceGroupManager.IsDraining() {
+		return nil
+	}
+	s.keyspaceGroupManager.Undrain()
+	return s.registerService()
+}
+
+func (s *Server) registerService() error {
+	serializedEntry, err := s.serviceID.Serialize()
+	if err != nil {
+		return err
+	}
+	s.serviceRegister = discovery.NewServiceRegister(s.Context(), s.GetClient(), strconv.FormatUint(s.clusterID, 10),
+		utils.TSOServiceName, s.cfg.AdvertiseListenAddr, serializedEntry, s.cfg.GetDiscoveryLease())
+	if err := s.serviceRegister.Register(); err != nil {
+		log.Error("failed to register the service", zap.String("service-name", utils.TSOServiceName), errs.ZapError(err))
+		return err
+	}
+	return nil
+}
+
 // AddServiceReadyCallback implements basicserver.
 // It adds callbacks when it's ready for providing tso service.
 func (*Server) AddServiceReadyCallback(...func(context.Context) error) {
@@ -274,6 +339,11 @@ func (s *Server) GetKeyspaceGroupManager() *tso.KeyspaceGroupManager {
 	return s.keyspaceGroupManager
 }
 
+// GetComponentAllocations returns the TSO allocation counts of the calling components.
+func (s *Server) GetComponentAllocations() []*tso.ComponentAllocation {
+	return s.componentAllocations.GetAll()
+}
+
 // GetTSOAllocatorManager returns the manager of TSO Allocator.
 func (s *Server) GetTSOAllocatorManager(keyspaceGroupID uint32) (*tso.AllocatorManager, error) {
 	return s.keyspaceGroupManager.GetAllocatorManager(keyspaceGroupID)
@@ -349,6 +419,17 @@ func (s *Server) GetConfig() *Config {
 	return s.cfg
 }
 
+// getTSOProxyBatchPolicy returns the policy to batch the TSO requests forwarded to the
+// keyspace group, the config of the server is capped by the one of the keyspace group.
+func (s *Server) getTSOProxyBatchPolicy(keyspaceGroupID uint32) tsoutil.BatchPolicy {
+	policy := tsoutil.BatchPolicy{
+		MaxBatchWait: s.cfg.GetTSOProxyMaxBatchWait(),
+		MaxBatchSize: s.cfg.GetTSOProxyMaxBatchSize(),
+	}
+	group := s.keyspaceGroupManager.GetKeyspaceGroup(keyspaceGroupID)
+	return policy.Cap(group.GetTSOBatchCaps())
+}
+
 // GetTLSConfig gets the security config.
 func (s *Server) GetTLSConfig() *grpcutil.TLSConfig {
 	return &s.cfg.Security.TLSConfig
@@ -382,6 +463,7 @@ func (s *Server) startServer() (err error) {
 		GitHash:        versioninfo.PDGitHash,
 		DeployPath:     deployPath,
 		StartTimestamp: s.StartTimestamp(),
+		Labels:         s.cfg.Labels,
 	}
 	s.keyspaceGroupManager = tso.NewKeyspaceGroupManager(
 		s.serverLoopCtx, s.serviceID, s.GetClient(), s.GetHTTPClient(), s.cfg.AdvertiseListenAddr,
@@ -391,12 +473,27 @@ func (s *Server) startServer() (err error) {
 	}
 
 	s.tsoProtoFactory = &tsoutil.TSOProtoFactory{}
+	s.tsoDispatcher = tsoutil.NewTSODispatcher(tsoProxyHandleDuration, tsoProxyBatchSize, s.getTSOProxyBatchPolicy)
 	s.service = &Service{Server: s}
 
 	if err := s.InitListener(s.GetTLSConfig(), s.cfg.ListenAddr); err != nil {
 		return err
 	}
 
+	if s.cfg.Watchdog.hasResourceLimits() {
+		s.serverLoopWg.Add(1)
+		go s.watchdogLoop()
+	}
+	// Stop the monitor with the server loops, so it doesn't outlive the server.
+	s.serverLoopWg.Add(1)
+	go func() {
+		defer s.serverLoopWg.Done()
+		systimemon.StartMonitor(s.serverLoopCtx, time.Now, func() {
+			log.Error("system time jumps backward", errs.ZapError(errs.ErrIncorrectSystemTime))
+			timeJumpBackCounter.Inc()
+		})
+	}()
+
 	serverReadyChan := make(chan struct{})
 	defer close(serverReadyChan)
 	s.serverLoopWg.Add(1)
@@ -410,14 +507,7 @@ func (s *Server) startServer() (err error) {
 	}
 
 	// Server has started.
-	serializedEntry, err := s.serviceID.Serialize()
-	if err != nil {
-		return err
-	}
-	s.serviceRegister = discovery.NewServiceRegister(s.Context(), s.GetClient(), strconv.FormatUint(s.clusterID, 10),
-		utils.TSOServiceName, s.cfg.AdvertiseListenAddr, serializedEntry, discovery.DefaultLeaseInSeconds)
-	if err := s.serviceRegister.Register(); err != nil {
-		log.Error("failed to register the service", zap.String("service-name", utils.TSOServiceName), errs.ZapError(err))
+	if err := s.registerService(); err != nil {
 		return err
 	}
 
@@ -428,9 +518,11 @@ func (s *Server) startServer() (err error) {
 // CreateServer creates the Server
 func CreateServer(ctx context.Context, cfg *Config) *Server {
 	svr := &Server{
-		BaseServer:        server.NewBaseServer(ctx),
-		DiagnosticsServer: sysutil.NewDiagnosticsServer(cfg.Log.File.Filename),
-		cfg:               cfg,
+		BaseServer:           server.NewBaseServer(ctx),
+		DiagnosticsServer:    sysutil.NewDiagnosticsServer(cfg.Log.File.Filename),
+		cfg:                  cfg,
+		watchdog:             newWatchdog(&cfg.Watchdog),
+		componentAllocations: tso.NewComponentAllocations(),
 	}
 	return svr
 }
@@ -488,7 +580,7 @@ func CreateServerWrapper(cmd *cobra.Command, args []string) {
 
 	var sig os.Signal
 	go func() {
-		sig = <-sc
+		sig = svr.WaitForShutdown(sc, svr.Close)
 		cancel()
 	}()
 
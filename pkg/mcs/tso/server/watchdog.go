@@ -0,0 +1,195 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/memory"
+	"github.com/tikv/pd/pkg/utils/logutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+const (
+	watchdogStartShedding = "start-shedding"
+	watchdogStopShedding  = "stop-shedding"
+
+	rejectReasonShedding    = "shedding"
+	rejectReasonStreamLimit = "stream-limit"
+
+	maxWatchdogActions = 16
+)
+
+// WatchdogStatus is the status of the watchdog.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type WatchdogStatus struct {
+	// Shedding indicates whether the new TSO streams are rejected.
+	Shedding bool `json:"shedding"`
+	// Reasons are the breached limits which cause the shedding.
+	Reasons []string `json:"reasons,omitempty"`
+	// Goroutines is collected if the goroutines or the memory is limited, and
+	// MemoryInuse is only collected if the memory is limited.
+	Goroutines      int               `json:"goroutines"`
+	MemoryInuse     uint64            `json:"memory-inuse"`
+	TSOStreams      int64             `json:"tso-streams"`
+	RejectedStreams uint64            `json:"rejected-streams"`
+	LastCheckTime   time.Time         `json:"last-check-time"`
+	Actions         []*WatchdogAction `json:"actions"`
+}
+
+// WatchdogAction is a self-protective action taken by the watchdog.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type WatchdogAction struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Reasons []string  `json:"reasons,omitempty"`
+}
+
+// watchdog monitors the goroutines, the TSO streams and the memory of the TSO
+// server. Once any limit is breached, it sheds the load by rejecting the new
+// TSO streams until the resource usage falls back below the limits. The TSO
+// streams which have been established are not affected.
+type watchdog struct {
+	cfg *WatchdogConfig
+
+	streams  atomic.Int64
+	rejected atomic.Uint64
+	shedding atomic.Bool
+
+	mu struct {
+		syncutil.RWMutex
+		reasons       []string
+		goroutines    int
+		memoryInuse   uint64
+		lastCheckTime time.Time
+		actions       []*WatchdogAction
+	}
+}
+
+func newWatchdog(cfg *WatchdogConfig) *watchdog {
+	return &watchdog{cfg: cfg}
+}
+
+func (s *Server) watchdogLoop() {
+	defer logutil.LogPanic()
+	defer s.serverLoopWg.Done()
+
+	ticker := time.NewTicker(s.cfg.Watchdog.CheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.serverLoopCtx.Done():
+			log.Info("watchdog loop is stopped")
+			return
+		case now := <-ticker.C:
+			// reading the memory stats stops the world, so skip it if there is no limit.
+			var memoryInuse uint64
+			if s.cfg.Watchdog.MaxMemory > 0 {
+				memoryInuse = memory.ForceReadMemStats().HeapInuse
+			}
+			s.watchdog.check(now, runtime.NumGoroutine(), memoryInuse)
+		}
+	}
+}
+
+// check checks the resource usage against the limits and starts or stops
+// shedding the load accordingly.
+func (w *watchdog) check(now time.Time, goroutines int, memoryInuse uint64) {
+	var reasons []string
+	if limit := w.cfg.MaxGoroutines; limit > 0 && goroutines > limit {
+		reasons = append(reasons, fmt.Sprintf("goroutines %d exceed the limit %d", goroutines, limit))
+	}
+	if limit := uint64(w.cfg.MaxMemory); limit > 0 && memoryInuse > limit {
+		reasons = append(reasons, fmt.Sprintf("memory in use %d exceeds the limit %d", memoryInuse, limit))
+	}
+	shedding := len(reasons) > 0
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mu.reasons = reasons
+	w.mu.goroutines = goroutines
+	w.mu.memoryInuse = memoryInuse
+	w.mu.lastCheckTime = now
+	if w.shedding.Swap(shedding) == shedding {
+		return
+	}
+	action := &WatchdogAction{Time: now, Action: watchdogStopShedding}
+	if shedding {
+		action.Action = watchdogStartShedding
+		action.Reasons = reasons
+		watchdogSheddingGauge.Set(1)
+		log.Warn("tso server is overloaded, start shedding the load", zap.Strings("reasons", reasons))
+	} else {
+		watchdogSheddingGauge.Set(0)
+		log.Info("tso server is recovered, stop shedding the load")
+	}
+	watchdogActionCounter.WithLabelValues(action.Action).Inc()
+	w.mu.actions = append(w.mu.actions, action)
+	if len(w.mu.actions) > maxWatchdogActions {
+		w.mu.actions = w.mu.actions[len(w.mu.actions)-maxWatchdogActions:]
+	}
+}
+
+// acquireStream admits a new TSO stream, it returns an error if the stream is rejected.
+func (w *watchdog) acquireStream() error {
+	if w.shedding.Load() {
+		w.reject(rejectReasonShedding)
+		return errs.ErrTSOServerOverloaded.FastGenByArgs("the resource limits are breached")
+	}
+	n := w.streams.Add(1)
+	if limit := int64(w.cfg.MaxTSOStreams); limit > 0 && n > limit {
+		w.streams.Add(-1)
+		w.reject(rejectReasonStreamLimit)
+		return errs.ErrTSOServerOverloaded.FastGenByArgs(fmt.Sprintf("tso streams reach the limit %d", limit))
+	}
+	tsoStreamGauge.Set(float64(n))
+	return nil
+}
+
+// releaseStream releases the TSO stream admitted by acquireStream.
+func (w *watchdog) releaseStream() {
+	tsoStreamGauge.Set(float64(w.streams.Add(-1)))
+}
+
+func (w *watchdog) reject(reason string) {
+	w.rejected.Add(1)
+	watchdogRejectedStreamCounter.WithLabelValues(reason).Inc()
+}
+
+func (w *watchdog) status() *WatchdogStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return &WatchdogStatus{
+		Shedding:        w.shedding.Load(),
+		Reasons:         append([]string(nil), w.mu.reasons...),
+		Goroutines:      w.mu.goroutines,
+		MemoryInuse:     w.mu.memoryInuse,
+		TSOStreams:      w.streams.Load(),
+		RejectedStreams: w.rejected.Load(),
+		LastCheckTime:   w.mu.lastCheckTime,
+		Actions:         append([]*WatchdogAction(nil), w.mu.actions...),
+	}
+}
+
+// GetWatchdogStatus returns the status of the watchdog.
+func (s *Server) GetWatchdogStatus() *WatchdogStatus {
+	return s.watchdog.status()
+}
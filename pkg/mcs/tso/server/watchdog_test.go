@@ -0,0 +1,64 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/errs"
+)
+
+func TestWatchdog(t *testing.T) {
+	re := require.New(t)
+	w := newWatchdog(&WatchdogConfig{MaxGoroutines: 100, MaxTSOStreams: 2, MaxMemory: 1 << 20})
+
+	// the streams beyond the limit are rejected.
+	re.NoError(w.acquireStream())
+	re.NoError(w.acquireStream())
+	re.True(errs.ErrTSOServerOverloaded.Equal(w.acquireStream()))
+	w.releaseStream()
+	re.NoError(w.acquireStream())
+
+	// all the new streams are rejected once any limit is breached.
+	now := time.Now()
+	w.check(now, 200, 1<<10)
+	w.releaseStream()
+	re.True(errs.ErrTSOServerOverloaded.Equal(w.acquireStream()))
+	status := w.status()
+	re.True(status.Shedding)
+	re.Len(status.Reasons, 1)
+	re.Equal(int64(1), status.TSOStreams)
+	re.Equal(uint64(2), status.RejectedStreams)
+
+	// keep shedding if the limits are still breached.
+	w.check(now.Add(time.Second), 50, 1<<30)
+	re.True(errs.ErrTSOServerOverloaded.Equal(w.acquireStream()))
+
+	// stop shedding after the resource usage falls back.
+	w.check(now.Add(2*time.Second), 50, 1<<10)
+	re.NoError(w.acquireStream())
+	status = w.status()
+	re.False(status.Shedding)
+	re.Empty(status.Reasons)
+	re.Len(status.Actions, 2)
+	re.Equal(watchdogStartShedding, status.Actions[0].Action)
+	re.Equal(watchdogStopShedding, status.Actions[1].Action)
+
+	// the resource usage is not checked if only the streams are limited.
+	re.True((&WatchdogConfig{MaxMemory: 1 << 20}).hasResourceLimits())
+	re.False((&WatchdogConfig{MaxTSOStreams: 2}).hasResourceLimits())
+}
@@ -14,7 +14,10 @@
 
 package utils
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 const (
 	// RetryIntervalWaitAPIService is the interval to retry.
@@ -40,6 +43,15 @@ const (
 	DefaultLeaderLease = int64(3)
 	// LeaderTickInterval is the interval to check leader
 	LeaderTickInterval = 50 * time.Millisecond
+	// MinTSOUpdatePhysicalInterval is the min interval to update the physical part of the TSO.
+	MinTSOUpdatePhysicalInterval = time.Millisecond
+	// MaxTSOUpdatePhysicalInterval is the max interval to update the physical part of the TSO.
+	MaxTSOUpdatePhysicalInterval = 10 * time.Second
+	// MinTSOSaveInterval is the min interval to save the timestamp window overridden by the keyspace group.
+	MinTSOSaveInterval = 100 * time.Millisecond
+	// MaxTSOSaveInterval is the max interval to save the timestamp window overridden by the keyspace group.
+	// The TSO may jump forward by the interval after the primary changes, so it can't be too long.
+	MaxTSOSaveInterval = time.Minute
 
 	// DefaultKeyspaceName is the name reserved for default keyspace.
 	DefaultKeyspaceName = "DEFAULT"
@@ -90,4 +102,7 @@ const (
 	// Among multiple replicas of a keyspace group, the higher the priority, the more likely
 	// the replica is to be elected as primary.
 	DefaultKeyspaceGroupReplicaPriority = 0
+	// UnsetKeyspaceGroupReplicaPriority marks a keyspace group replica whose priority is not
+	// specified when creating the keyspace group, so it inherits the priority of the profile.
+	UnsetKeyspaceGroupReplicaPriority = math.MinInt32
 )
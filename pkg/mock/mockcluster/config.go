@@ -130,11 +130,56 @@ func (mc *Cluster) SetLocationLabels(v []string) {
 	mc.updateReplicationConfig(func(r *sc.ReplicationConfig) { r.LocationLabels = v })
 }
 
+// SetLocationLabelWeights updates the LocationLabelWeights configuration.
+func (mc *Cluster) SetLocationLabelWeights(v []float64) {
+	mc.updateReplicationConfig(func(r *sc.ReplicationConfig) { r.LocationLabelWeights = v })
+}
+
 // SetIsolationLevel updates the IsolationLevel configuration.
 func (mc *Cluster) SetIsolationLevel(v string) {
 	mc.updateReplicationConfig(func(r *sc.ReplicationConfig) { r.IsolationLevel = v })
 }
 
+// SetLabelDomainOperatorLimits updates the LabelDomainOperatorLimits configuration.
+func (mc *Cluster) SetLabelDomainOperatorLimits(v []sc.LabelDomainOperatorLimit) {
+	mc.updateScheduleConfig(func(s *sc.ScheduleConfig) { s.LabelDomainOperatorLimits = v })
+}
+
+// SetClusterSnapshotBandwidth updates the ClusterSnapshotBandwidth configuration.
+func (mc *Cluster) SetClusterSnapshotBandwidth(v uint64) {
+	mc.updateScheduleConfig(func(s *sc.ScheduleConfig) { s.ClusterSnapshotBandwidth = typeutil.ByteSize(v) })
+}
+
+// SetMaxLearnerCatchUpTime updates the MaxLearnerCatchUpTime configuration.
+func (mc *Cluster) SetMaxLearnerCatchUpTime(v time.Duration) {
+	mc.updateScheduleConfig(func(s *sc.ScheduleConfig) { s.MaxLearnerCatchUpTime = typeutil.NewDuration(v) })
+}
+
+// SetRegionStatsSampleRatio updates the RegionStatsSampleRatio configuration.
+func (mc *Cluster) SetRegionStatsSampleRatio(v float64) {
+	mc.updateScheduleConfig(func(s *sc.ScheduleConfig) { s.RegionStatsSampleRatio = v })
+}
+
+// SetMaxStorePendingCompactionBytes updates the MaxStorePendingCompactionBytes configuration.
+func (mc *Cluster) SetMaxStorePendingCompactionBytes(v uint64) {
+	mc.updateScheduleConfig(func(s *sc.ScheduleConfig) { s.MaxStorePendingCompactionBytes = typeutil.ByteSize(v) })
+}
+
+// SetMaxStoreLevel0FileCount updates the MaxStoreLevel0FileCount configuration.
+func (mc *Cluster) SetMaxStoreLevel0FileCount(v uint64) {
+	mc.updateScheduleConfig(func(s *sc.ScheduleConfig) { s.MaxStoreLevel0FileCount = v })
+}
+
+// SetMaxEvictingStoreRatio updates the MaxEvictingStoreRatio configuration.
+func (mc *Cluster) SetMaxEvictingStoreRatio(v float64) {
+	mc.updateScheduleConfig(func(s *sc.ScheduleConfig) { s.MaxEvictingStoreRatio = v })
+}
+
+// SetDecisionLogFile updates the DecisionLogFile configuration.
+func (mc *Cluster) SetDecisionLogFile(v string) {
+	mc.updateScheduleConfig(func(s *sc.ScheduleConfig) { s.DecisionLogFile = v })
+}
+
 func (mc *Cluster) updateScheduleConfig(f func(*sc.ScheduleConfig)) {
 	s := mc.GetScheduleConfig().Clone()
 	f(s)
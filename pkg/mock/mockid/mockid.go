@@ -14,7 +14,11 @@
 
 package mockid
 
-import "sync/atomic"
+import (
+	"sync/atomic"
+
+	"github.com/tikv/pd/pkg/id"
+)
 
 // IDAllocator mocks IDAllocator and it is only used for test.
 type IDAllocator struct {
@@ -41,3 +45,9 @@ func (alloc *IDAllocator) SetBase(newBase uint64) error {
 func (*IDAllocator) Rebase() error {
 	return nil
 }
+
+// GetStats implements the IDAllocator interface.
+func (alloc *IDAllocator) GetStats() *id.Stats {
+	base := atomic.LoadUint64(&alloc.base)
+	return &id.Stats{Label: "mock", Base: base, End: base}
+}
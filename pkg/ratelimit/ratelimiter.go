@@ -88,6 +88,13 @@ func (l *RateLimiter) Burst() int {
 	return l.limiter.Burst()
 }
 
+// Tokens returns the number of the tokens available now.
+func (l *RateLimiter) Tokens() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limiter.Tokens()
+}
+
 // WaitN blocks until lim permits n events to happen.
 // It returns an error if n exceeds the Limiter's burst size, the Context is
 // canceled, or the expected wait time exceeds the Context's Deadline.
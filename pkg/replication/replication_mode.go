@@ -391,12 +391,12 @@ func minimalUpVoters(rule *placement.Rule, upStores, downStores []*core.StoreInf
 	}
 	var up, down int
 	for _, s := range upStores {
-		if placement.MatchLabelConstraints(s, rule.LabelConstraints) {
+		if rule.MatchStore(s) {
 			up++
 		}
 	}
 	for _, s := range downStores {
-		if placement.MatchLabelConstraints(s, rule.LabelConstraints) {
+		if rule.MatchStore(s) {
 			down++
 		}
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/kvproto/pkg/replication_modepb"
 	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/utils/apiutil"
 )
 
 // MetaPeer is api compatible with *metapb.Peer.
@@ -201,6 +202,8 @@ func (r *RegionInfo) Adjust() {
 type RegionsInfo struct {
 	Count   int          `json:"count"`
 	Regions []RegionInfo `json:"regions"`
+	// NextPageToken is the page token of the next page, it's empty if there are no more regions.
+	NextPageToken string `json:"next_page_token,omitempty"`
 }
 
 // Adjust is only used in testing, in order to compare the data from json deserialization.
@@ -210,6 +213,26 @@ func (s *RegionsInfo) Adjust() {
 	}
 }
 
+// ScanRegionsPage scans a page of the regions in [startKey, endKey) sorted by the
+// start key, the start key is replaced by the page token if it's set. It returns
+// the page and the token of the next page, which is the start key of the first
+// region of the next page.
+func ScanRegionsPage(
+	scan func(startKey, endKey []byte, limit int) []*core.RegionInfo,
+	startKey, endKey []byte, q *apiutil.PageQuery,
+) ([]*core.RegionInfo, string, error) {
+	if q.Token != "" {
+		var err error
+		if startKey, err = q.KeyToken(); err != nil {
+			return nil, "", err
+		}
+	}
+	regions, next := apiutil.TrimPage(scan(startKey, endKey, q.ScanLimit()), q, func(r *core.RegionInfo) string {
+		return apiutil.KeyPageToken(r.GetStartKey())
+	})
+	return regions, next, nil
+}
+
 // MarshalRegionInfoJSON marshals region to bytes in `RegionInfo`'s JSON format.
 // It is used to reduce the cost of JSON serialization.
 func MarshalRegionInfoJSON(ctx context.Context, r *core.RegionInfo) ([]byte, error) {
@@ -231,6 +254,11 @@ func MarshalRegionInfoJSON(ctx context.Context, r *core.RegionInfo) ([]byte, err
 // MarshalRegionsInfoJSON marshals regions to bytes in `RegionsInfo`'s JSON format.
 // It is used to reduce the cost of JSON serialization.
 func MarshalRegionsInfoJSON(ctx context.Context, regions []*core.RegionInfo) ([]byte, error) {
+	return MarshalRegionsPageJSON(ctx, regions, "")
+}
+
+// MarshalRegionsPageJSON marshals a page of regions to bytes in `RegionsInfo`'s JSON format.
+func MarshalRegionsPageJSON(ctx context.Context, regions []*core.RegionInfo, nextPageToken string) ([]byte, error) {
 	out := &jwriter.Writer{}
 	out.RawByte('{')
 
@@ -255,6 +283,10 @@ func MarshalRegionsInfoJSON(ctx context.Context, regions []*core.RegionInfo) ([]
 	}
 	out.RawByte(']')
 
+	if nextPageToken != "" {
+		out.RawString(",\"next_page_token\":")
+		out.String(nextPageToken)
+	}
 	out.RawByte('}')
 	return out.Buffer.BuildBytes(), out.Error
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/constant"
 	sc "github.com/tikv/pd/pkg/schedule/config"
+	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/pkg/utils/typeutil"
 )
 
@@ -51,28 +52,30 @@ type SlowTrend struct {
 
 // StoreStatus contains status about a store.
 type StoreStatus struct {
-	Capacity           typeutil.ByteSize  `json:"capacity"`
-	Available          typeutil.ByteSize  `json:"available"`
-	UsedSize           typeutil.ByteSize  `json:"used_size"`
-	LeaderCount        int                `json:"leader_count"`
-	LeaderWeight       float64            `json:"leader_weight"`
-	LeaderScore        float64            `json:"leader_score"`
-	LeaderSize         int64              `json:"leader_size"`
-	RegionCount        int                `json:"region_count"`
-	RegionWeight       float64            `json:"region_weight"`
-	RegionScore        float64            `json:"region_score"`
-	RegionSize         int64              `json:"region_size"`
-	LearnerCount       int                `json:"learner_count,omitempty"`
-	WitnessCount       int                `json:"witness_count,omitempty"`
-	PendingPeerCount   int                `json:"pending_peer_count,omitempty"`
-	SlowScore          uint64             `json:"slow_score,omitempty"`
-	SlowTrend          *SlowTrend         `json:"slow_trend,omitempty"`
-	SendingSnapCount   uint32             `json:"sending_snap_count,omitempty"`
-	ReceivingSnapCount uint32             `json:"receiving_snap_count,omitempty"`
-	IsBusy             bool               `json:"is_busy,omitempty"`
-	StartTS            *time.Time         `json:"start_ts,omitempty"`
-	LastHeartbeatTS    *time.Time         `json:"last_heartbeat_ts,omitempty"`
-	Uptime             *typeutil.Duration `json:"uptime,omitempty"`
+	Capacity           typeutil.ByteSize     `json:"capacity"`
+	Available          typeutil.ByteSize     `json:"available"`
+	UsedSize           typeutil.ByteSize     `json:"used_size"`
+	LeaderCount        int                   `json:"leader_count"`
+	LeaderWeight       float64               `json:"leader_weight"`
+	LeaderScore        float64               `json:"leader_score"`
+	LeaderSize         int64                 `json:"leader_size"`
+	RegionCount        int                   `json:"region_count"`
+	RegionWeight       float64               `json:"region_weight"`
+	RegionScore        float64               `json:"region_score"`
+	RegionSize         int64                 `json:"region_size"`
+	LearnerCount       int                   `json:"learner_count,omitempty"`
+	WitnessCount       int                   `json:"witness_count,omitempty"`
+	PendingPeerCount   int                   `json:"pending_peer_count,omitempty"`
+	SlowScore          uint64                `json:"slow_score,omitempty"`
+	SlowTrend          *SlowTrend            `json:"slow_trend,omitempty"`
+	SendingSnapCount   uint32                `json:"sending_snap_count,omitempty"`
+	ReceivingSnapCount uint32                `json:"receiving_snap_count,omitempty"`
+	IsBusy             bool                  `json:"is_busy,omitempty"`
+	CompactionStats    *core.CompactionStats `json:"compaction_stats,omitempty"`
+	SlowStartUntil     *time.Time            `json:"slow_start_until,omitempty"`
+	StartTS            *time.Time            `json:"start_ts,omitempty"`
+	LastHeartbeatTS    *time.Time            `json:"last_heartbeat_ts,omitempty"`
+	Uptime             *typeutil.Duration    `json:"uptime,omitempty"`
 }
 
 // StoreInfo contains information about a store.
@@ -127,6 +130,11 @@ func BuildStoreInfo(opt *sc.ScheduleConfig, store *core.StoreInfo) *StoreInfo {
 		startTS := store.GetStartTime()
 		s.Status.StartTS = &startTS
 	}
+	s.Status.CompactionStats = store.GetCompactionStats()
+	if store.IsSlowStarting() {
+		slowStartUntil := store.GetSlowStartDeadline()
+		s.Status.SlowStartUntil = &slowStartUntil
+	}
 	if lastHeartbeat := store.GetLastHeartbeatTS(); !lastHeartbeat.IsZero() {
 		s.Status.LastHeartbeatTS = &lastHeartbeat
 	}
@@ -149,4 +157,19 @@ func BuildStoreInfo(opt *sc.ScheduleConfig, store *core.StoreInfo) *StoreInfo {
 type StoresInfo struct {
 	Count  int          `json:"count"`
 	Stores []*StoreInfo `json:"stores"`
+	// NextPageToken is the page token of the next page, it's empty if there are no more stores.
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
+
+// Paginate trims the stores to the page of the query sorted by the store ID, and
+// sets the count and the token of the next page.
+func (s *StoresInfo) Paginate(q *apiutil.PageQuery) error {
+	stores, next, err := apiutil.PaginateByID(s.Stores, q, func(store *StoreInfo) uint64 {
+		return store.Store.GetId()
+	})
+	if err != nil {
+		return err
+	}
+	s.Stores, s.Count, s.NextPageToken = stores, len(stores), next
+	return nil
 }
@@ -45,8 +45,10 @@ type Controller struct {
 	ruleChecker             *RuleChecker
 	splitChecker            *SplitChecker
 	mergeChecker            *MergeChecker
+	rangeMerger             *RangeMerger
 	jointStateChecker       *JointStateChecker
 	priorityInspector       *PriorityInspector
+	repairPlanner           *RepairPlanner
 	pendingProcessedRegions cache.Cache
 	suspectKeyRanges        *cache.TTLString // suspect key-range regions that may need fix
 }
@@ -60,11 +62,13 @@ func NewController(ctx context.Context, cluster sche.CheckerCluster, conf config
 		opController:            opController,
 		learnerChecker:          NewLearnerChecker(cluster),
 		replicaChecker:          NewReplicaChecker(cluster, conf, pendingProcessedRegions),
-		ruleChecker:             NewRuleChecker(ctx, cluster, ruleManager, pendingProcessedRegions),
+		ruleChecker:             NewRuleChecker(ctx, cluster, ruleManager, pendingProcessedRegions, opController),
 		splitChecker:            NewSplitChecker(cluster, ruleManager, labeler),
 		mergeChecker:            NewMergeChecker(ctx, cluster, conf),
+		rangeMerger:             NewRangeMerger(ctx, cluster, opController),
 		jointStateChecker:       NewJointStateChecker(cluster),
 		priorityInspector:       NewPriorityInspector(cluster, conf),
+		repairPlanner:           NewRepairPlanner(cluster),
 		pendingProcessedRegions: pendingProcessedRegions,
 		suspectKeyRanges:        cache.NewStringTTL(ctx, time.Minute, 3*time.Minute),
 	}
@@ -99,11 +103,17 @@ func (c *Controller) CheckRegion(region *core.RegionInfo) []*operator.Operator {
 			})
 			fit := c.priorityInspector.Inspect(region)
 			if op := c.ruleChecker.CheckWithFit(region, fit); op != nil {
+				// The region may be checked again before the operator is added, so the
+				// violation is counted by the operator once it's added.
+				if label, ok := operator.KeyspaceMetricsLabel(region); ok {
+					op.Counters = append(op.Counters, keyspaceRuleViolationCounter.WithLabelValues(label, op.Desc()))
+				}
 				if opController.OperatorCount(operator.OpReplica) < c.conf.GetReplicaScheduleLimit() {
 					return []*operator.Operator{op}
 				}
 				operator.OperatorLimitCounter.WithLabelValues(c.ruleChecker.Name(), operator.OpReplica.String()).Inc()
 				c.pendingProcessedRegions.Put(region.GetID(), nil)
+				c.repairPlanner.Add(region)
 			}
 		}
 	} else {
@@ -116,6 +126,7 @@ func (c *Controller) CheckRegion(region *core.RegionInfo) []*operator.Operator {
 			}
 			operator.OperatorLimitCounter.WithLabelValues(c.replicaChecker.Name(), operator.OpReplica.String()).Inc()
 			c.pendingProcessedRegions.Put(region.GetID(), nil)
+			c.repairPlanner.Add(region)
 		}
 	}
 	// skip the joint checker, split checker and rule checker when region label is set to "schedule=deny".
@@ -145,6 +156,11 @@ func (c *Controller) GetMergeChecker() *MergeChecker {
 	return c.mergeChecker
 }
 
+// GetRangeMerger returns the range merger.
+func (c *Controller) GetRangeMerger() *RangeMerger {
+	return c.rangeMerger
+}
+
 // GetRuleChecker returns the rule checker.
 func (c *Controller) GetRuleChecker() *RuleChecker {
 	return c.ruleChecker
@@ -181,6 +197,11 @@ func (c *Controller) RemovePriorityRegions(id uint64) {
 	c.priorityInspector.RemovePriorityRegion(id)
 }
 
+// GetRepairPlanner returns the repair planner.
+func (c *Controller) GetRepairPlanner() *RepairPlanner {
+	return c.repairPlanner
+}
+
 // AddSuspectKeyRange adds the key range with the its ruleID as the key
 // The instance of each keyRange is like following format:
 // [2][]byte: start key/end key
@@ -0,0 +1,56 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/pkg/mock/mockconfig"
+	"github.com/tikv/pd/pkg/schedule/hbstream"
+	"github.com/tikv/pd/pkg/schedule/operator"
+)
+
+func TestKeyspaceRuleViolationCounter(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := mockcluster.NewCluster(ctx, mockconfig.NewTestOptions())
+	tc.SetEnablePlacementRules(true)
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddLeaderStore(i, 1)
+	}
+	stream := hbstream.NewTestHeartbeatStreams(ctx, tc.ID, tc, false /* no need to run */)
+	oc := operator.NewController(ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	controller := NewController(ctx, tc, tc.GetCheckerConfig(), tc.GetRuleManager(), tc.GetRegionLabeler(), oc)
+
+	// the violation is carried by the operator instead of being counted on every check.
+	tc.AddLeaderRegion(1, 1, 2)
+	ops := controller.CheckRegion(tc.GetRegion(1))
+	re.Len(ops, 1)
+	re.Empty(ops[0].Counters)
+	region := tc.GetRegion(1).Clone(core.WithStartKey(codec.EncodeBytes([]byte("x\x00\x00\x05"))))
+	tc.PutRegion(region)
+	for i := 0; i < 2; i++ {
+		ops = controller.CheckRegion(region)
+		re.Len(ops, 1)
+		re.Equal("add-rule-peer", ops[0].Desc())
+		re.Len(ops[0].Counters, 1)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/pingcap/log"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/pd/pkg/cache"
 	"github.com/tikv/pd/pkg/codec"
 	"github.com/tikv/pd/pkg/core"
@@ -39,13 +40,6 @@ const (
 	maxTargetRegionFactor = 4
 )
 
-// When a region has label `merge_option=deny`, skip merging the region.
-// If label value is `allow` or other value, it will be treated as `allow`.
-const (
-	mergeOptionLabel     = "merge_option"
-	mergeOptionValueDeny = "deny"
-)
-
 var gcInterval = time.Minute
 
 // MergeChecker ensures region to merge with adjacent region when size is small
@@ -136,21 +130,49 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
 
 	prev, next := m.cluster.GetAdjacentRegions(region)
 
-	var target *core.RegionInfo
+	var candidates []*core.RegionInfo
 	if m.checkTarget(region, next) {
-		target = next
+		candidates = append(candidates, next)
 	}
 	if !m.conf.IsOneWayMergeEnabled() && m.checkTarget(region, prev) { // allow a region can be merged by two ways.
-		if target == nil || prev.GetApproximateSize() < next.GetApproximateSize() { // pick smaller
-			target = prev
+		candidates = append(candidates, prev)
+	}
+	if len(candidates) == 0 {
+		mergeCheckerNoTargetCounter.Inc()
+		return nil
+	}
+	// Drop the candidates breaking the size limits before picking, otherwise a
+	// larger neighbour with a better score may block the smaller one from merging.
+	sizedCandidates := candidates[:0]
+	for _, candidate := range candidates {
+		if m.checkMergedSize(region, candidate) {
+			sizedCandidates = append(sizedCandidates, candidate)
 		}
 	}
-
+	target := m.pickTarget(region, sizedCandidates)
 	if target == nil {
-		mergeCheckerNoTargetCounter.Inc()
 		return nil
 	}
 
+	log.Debug("try to merge region",
+		logutil.ZapRedactStringer("from", core.RegionToHexMeta(region.GetMeta())),
+		logutil.ZapRedactStringer("to", core.RegionToHexMeta(target.GetMeta())))
+	ops, err := operator.CreateMergeRegionOperator("merge-region", m.cluster, region, target, operator.OpMerge)
+	if err != nil {
+		log.Warn("create merge region operator failed", errs.ZapError(err))
+		return nil
+	}
+	mergeCheckerNewOpCounter.Inc()
+	if region.GetApproximateSize() > target.GetApproximateSize() ||
+		region.GetApproximateKeys() > target.GetApproximateKeys() {
+		mergeCheckerLargerSourceCounter.Inc()
+	}
+	return ops
+}
+
+// checkMergedSize checks whether the target isn't too large, and the region
+// merged into it doesn't exceed the size and keys to split.
+func (m *MergeChecker) checkMergedSize(region, target *core.RegionInfo) bool {
 	regionMaxSize := m.cluster.GetStoreConfig().GetRegionMaxSize()
 	maxTargetRegionSizeThreshold := int64(float64(regionMaxSize) * float64(maxTargetRegionFactor))
 	if maxTargetRegionSizeThreshold < maxTargetRegionSize {
@@ -158,83 +180,227 @@ func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
 	}
 	if target.GetApproximateSize() > maxTargetRegionSizeThreshold {
 		mergeCheckerTargetTooLargeCounter.Inc()
-		return nil
+		return false
 	}
 	if err := m.cluster.GetStoreConfig().CheckRegionSize(uint64(target.GetApproximateSize()+region.GetApproximateSize()),
 		m.conf.GetMaxMergeRegionSize()); err != nil {
 		mergeCheckerSplitSizeAfterMergeCounter.Inc()
-		return nil
+		return false
 	}
 
 	if err := m.cluster.GetStoreConfig().CheckRegionKeys(uint64(target.GetApproximateKeys()+region.GetApproximateKeys()),
 		m.conf.GetMaxMergeRegionKeys()); err != nil {
 		mergeCheckerSplitKeysAfterMergeCounter.Inc()
-		return nil
+		return false
 	}
+	return true
+}
 
-	log.Debug("try to merge region",
-		logutil.ZapRedactStringer("from", core.RegionToHexMeta(region.GetMeta())),
-		logutil.ZapRedactStringer("to", core.RegionToHexMeta(target.GetMeta())))
-	ops, err := operator.CreateMergeRegionOperator("merge-region", m.cluster, region, target, operator.OpMerge)
-	if err != nil {
-		log.Warn("create merge region operator failed", errs.ZapError(err))
-		return nil
-	}
-	mergeCheckerNewOpCounter.Inc()
-	if region.GetApproximateSize() > target.GetApproximateSize() ||
-		region.GetApproximateKeys() > target.GetApproximateKeys() {
-		mergeCheckerLargerSourceCounter.Inc()
+func (m *MergeChecker) checkTarget(region, adjacent *core.RegionInfo) bool {
+	if counter, _ := m.rejectTarget(region, adjacent); counter != nil {
+		counter.Inc()
+		return false
 	}
-	return ops
+	return true
 }
 
-func (m *MergeChecker) checkTarget(region, adjacent *core.RegionInfo) bool {
+// rejectTarget returns the counter and the reason if the adjacent region can't
+// be the merge target of the region.
+func (m *MergeChecker) rejectTarget(region, adjacent *core.RegionInfo) (prometheus.Counter, string) {
 	if adjacent == nil {
-		mergeCheckerAdjNotExistCounter.Inc()
-		return false
+		return mergeCheckerAdjNotExistCounter, "adj-not-exist"
 	}
 
 	if m.splitCache.Exists(adjacent.GetID()) {
-		mergeCheckerAdjRecentlySplitCounter.Inc()
-		return false
+		return mergeCheckerAdjRecentlySplitCounter, "adj-recently-split"
 	}
 
 	if m.cluster.IsRegionHot(adjacent) {
-		mergeCheckerAdjRegionHotCounter.Inc()
-		return false
+		return mergeCheckerAdjRegionHotCounter, "adj-region-hot"
 	}
 
 	if !AllowMerge(m.cluster, region, adjacent) {
-		mergeCheckerAdjDisallowMergeCounter.Inc()
-		return false
+		return mergeCheckerAdjDisallowMergeCounter, "adj-disallow-merge"
 	}
 
 	if !checkPeerStore(m.cluster, region, adjacent) {
-		mergeCheckerAdjAbnormalPeerStoreCounter.Inc()
-		return false
+		return mergeCheckerAdjAbnormalPeerStoreCounter, "adj-abnormal-peerstore"
 	}
 
 	if !filter.IsRegionHealthy(adjacent) {
-		mergeCheckerAdjSpecialPeerCounter.Inc()
-		return false
+		return mergeCheckerAdjSpecialPeerCounter, "adj-special-peer"
 	}
 
 	if !filter.IsRegionReplicated(m.cluster, adjacent) {
-		mergeCheckerAdjAbnormalReplicaCounter.Inc()
-		return false
+		return mergeCheckerAdjAbnormalReplicaCounter, "adj-abnormal-replica"
 	}
 
-	return true
+	return nil, ""
+}
+
+// MergeTargetScore is the compatibility of the peer placement of a merge target
+// with the rule fit of the merged region. The merged region keeps the peers of
+// the target, so the more compatible the target is, the fewer peers need to be
+// repaired after merging.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type MergeTargetScore struct {
+	// MisplacedPeers is the number of peers to add, remove or change the role
+	// of to make the merged region fit the rules.
+	MisplacedPeers int `json:"misplaced_peers"`
+	// IsolationScore is the total isolation score of the rule fits of the merged
+	// region, a larger value is better.
+	IsolationScore float64 `json:"isolation_score"`
+	// PeerMoves is the number of peers of the source region to move to the
+	// stores of the target before merging.
+	PeerMoves int `json:"peer_moves"`
+}
+
+// compare returns a positive value if s is more compatible than other, a
+// negative value if it's less compatible, and zero if they are the same.
+func (s *MergeTargetScore) compare(other *MergeTargetScore) int {
+	switch {
+	case s.MisplacedPeers != other.MisplacedPeers:
+		return other.MisplacedPeers - s.MisplacedPeers
+	case s.IsolationScore > other.IsolationScore:
+		return 1
+	case s.IsolationScore < other.IsolationScore:
+		return -1
+	default:
+		return other.PeerMoves - s.PeerMoves
+	}
+}
+
+func (m *MergeChecker) scoreTarget(region, target *core.RegionInfo) *MergeTargetScore {
+	score := &MergeTargetScore{}
+	regionStoreIDs := region.GetStoreIDs()
+	for storeID := range target.GetStoreIDs() {
+		if _, ok := regionStoreIDs[storeID]; !ok {
+			score.PeerMoves++
+		}
+	}
+	conf := m.cluster.GetSharedConfig()
+	if !conf.IsPlacementRulesEnabled() {
+		diff := len(target.GetVoters()) - conf.GetMaxReplicas()
+		if diff < 0 {
+			diff = -diff
+		}
+		score.MisplacedPeers = diff + len(target.GetLearners())
+		return score
+	}
+	merged := target
+	if start, end, ok := mergedRange(region, target); ok {
+		merged = target.Clone(core.WithStartKey(start), core.WithEndKey(end))
+	}
+	fit := m.cluster.GetRuleManager().FitRegion(m.cluster, merged)
+	score.MisplacedPeers = len(fit.OrphanPeers)
+	for _, rf := range fit.RuleFits {
+		if rf.Rule.Count > len(rf.Peers) {
+			score.MisplacedPeers += rf.Rule.Count - len(rf.Peers)
+		}
+		score.MisplacedPeers += len(rf.PeersWithDifferentRole)
+		score.IsolationScore += rf.IsolationScore
+	}
+	return score
+}
+
+// pickTarget picks the most compatible candidate as the merge target, and the
+// smaller one if they are equally compatible. The candidates are only scored
+// when there is more than one of them, since fitting the merged region is
+// expensive for the hot path.
+func (m *MergeChecker) pickTarget(region *core.RegionInfo, candidates []*core.RegionInfo) *core.RegionInfo {
+	switch len(candidates) {
+	case 0:
+		return nil
+	case 1:
+		return candidates[0]
+	}
+	var (
+		target      *core.RegionInfo
+		targetScore *MergeTargetScore
+	)
+	for _, candidate := range candidates {
+		score := m.scoreTarget(region, candidate)
+		if target != nil {
+			cmp := score.compare(targetScore)
+			if cmp < 0 || cmp == 0 && candidate.GetApproximateSize() >= target.GetApproximateSize() {
+				continue
+			}
+		}
+		target, targetScore = candidate, score
+	}
+	return target
+}
+
+// MergeTargetDiagnosis is the diagnosis of an adjacent region as the merge target.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type MergeTargetDiagnosis struct {
+	RegionID uint64 `json:"region_id"`
+	// Direction is "prev" or "next", which is the side of the source region
+	// the adjacent region is on.
+	Direction       string `json:"direction"`
+	ApproximateSize int64  `json:"approximate_size"`
+	// Reason is why the region can't be the merge target, it's empty if it can.
+	Reason   string            `json:"reason,omitempty"`
+	Selected bool              `json:"selected"`
+	Score    *MergeTargetScore `json:"score"`
+}
+
+// DiagnoseTargets returns the diagnosis of the adjacent regions of the region
+// as the merge target. It doesn't check whether the region needs to be merged,
+// and the size limits of the merged region.
+func (m *MergeChecker) DiagnoseTargets(region *core.RegionInfo) []*MergeTargetDiagnosis {
+	prev, next := m.cluster.GetAdjacentRegions(region)
+	var (
+		diagnoses  []*MergeTargetDiagnosis
+		candidates []*core.RegionInfo
+	)
+	for _, adjacent := range []*core.RegionInfo{next, prev} {
+		if adjacent == nil {
+			continue
+		}
+		diagnosis := &MergeTargetDiagnosis{
+			RegionID:        adjacent.GetID(),
+			Direction:       "next",
+			ApproximateSize: adjacent.GetApproximateSize(),
+			Score:           m.scoreTarget(region, adjacent),
+		}
+		if adjacent == prev {
+			diagnosis.Direction = "prev"
+		}
+		if adjacent == prev && m.conf.IsOneWayMergeEnabled() {
+			diagnosis.Reason = "one-way-merge"
+		} else {
+			_, diagnosis.Reason = m.rejectTarget(region, adjacent)
+		}
+		if diagnosis.Reason == "" {
+			candidates = append(candidates, adjacent)
+		}
+		diagnoses = append(diagnoses, diagnosis)
+	}
+	if target := m.pickTarget(region, candidates); target != nil {
+		for _, diagnosis := range diagnoses {
+			diagnosis.Selected = diagnosis.RegionID == target.GetID()
+		}
+	}
+	return diagnoses
+}
+
+// mergedRange returns the key range of the region merged from the two adjacent
+// regions.
+func mergedRange(region, adjacent *core.RegionInfo) (start, end []byte, ok bool) {
+	if bytes.Equal(region.GetEndKey(), adjacent.GetStartKey()) && len(region.GetEndKey()) != 0 {
+		return region.GetStartKey(), adjacent.GetEndKey(), true
+	}
+	if bytes.Equal(adjacent.GetEndKey(), region.GetStartKey()) && len(adjacent.GetEndKey()) != 0 {
+		return adjacent.GetStartKey(), region.GetEndKey(), true
+	}
+	return nil, nil, false
 }
 
 // AllowMerge returns true if two regions can be merged according to the key type.
 func AllowMerge(cluster sche.SharedCluster, region, adjacent *core.RegionInfo) bool {
-	var start, end []byte
-	if bytes.Equal(region.GetEndKey(), adjacent.GetStartKey()) && len(region.GetEndKey()) != 0 {
-		start, end = region.GetStartKey(), adjacent.GetEndKey()
-	} else if bytes.Equal(adjacent.GetEndKey(), region.GetStartKey()) && len(adjacent.GetEndKey()) != 0 {
-		start, end = adjacent.GetStartKey(), region.GetEndKey()
-	} else {
+	start, end, ok := mergedRange(region, adjacent)
+	if !ok {
 		return false
 	}
 
@@ -256,7 +422,7 @@ func AllowMerge(cluster sche.SharedCluster, region, adjacent *core.RegionInfo) b
 		if len(l.GetSplitKeys(start, end)) > 0 {
 			return false
 		}
-		if l.GetRegionLabel(region, mergeOptionLabel) == mergeOptionValueDeny || l.GetRegionLabel(adjacent, mergeOptionLabel) == mergeOptionValueDeny {
+		if l.GetRangeOverrides(region).MergeDisabled || l.GetRangeOverrides(adjacent).MergeDisabled {
 			return false
 		}
 	}
@@ -210,7 +210,7 @@ func (suite *mergeCheckerTestSuite) TestBasic() {
 	//  check 'merge_option' label
 	suite.cluster.GetRegionLabeler().SetLabelRule(&labeler.LabelRule{
 		ID:       "test",
-		Labels:   []labeler.RegionLabel{{Key: mergeOptionLabel, Value: mergeOptionValueDeny}},
+		Labels:   []labeler.RegionLabel{{Key: labeler.MergeOptionLabel, Value: labeler.MergeOptionValueDeny}},
 		RuleType: labeler.KeyRange,
 		Data:     makeKeyRanges("", "74"),
 	})
@@ -560,6 +560,78 @@ func (suite *mergeCheckerTestSuite) TestCache() {
 	re.NotNil(ops)
 }
 
+func (suite *mergeCheckerTestSuite) TestPickTargetByRuleFit() {
+	re := suite.Require()
+	cfg := mockconfig.NewTestOptions()
+	suite.cluster = mockcluster.NewCluster(suite.ctx, cfg)
+	suite.cluster.SetMaxMergeRegionSize(2)
+	suite.cluster.SetMaxMergeRegionKeys(2)
+	suite.cluster.SetSplitMergeInterval(0)
+	suite.cluster.SetEnablePlacementRules(true)
+	suite.cluster.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	re.NoError(suite.cluster.RuleManager.SetRule(&placement.Rule{
+		GroupID:        placement.DefaultGroupID,
+		ID:             placement.DefaultRuleID,
+		Role:           placement.Voter,
+		Count:          3,
+		LocationLabels: []string{"zone"},
+	}))
+	zones := map[uint64]string{1: "z1", 2: "z1", 3: "z2", 4: "z3"}
+	for storeID, zone := range zones {
+		suite.cluster.AddLabelsStore(storeID, 0, map[string]string{"zone": zone})
+	}
+	suite.regions = []*core.RegionInfo{
+		// the peers of the previous region are isolated in all the zones.
+		newRegionInfo(1, "a", "b", 10, 10, []uint64{101, 1}, []uint64{101, 1}, []uint64{102, 3}, []uint64{103, 4}),
+		newRegionInfo(2, "b", "c", 1, 1, []uint64{104, 1}, []uint64{104, 1}, []uint64{105, 3}, []uint64{106, 4}),
+		// the next region is smaller, but two of its peers are in the same zone.
+		newRegionInfo(3, "c", "d", 5, 5, []uint64{107, 1}, []uint64{107, 1}, []uint64{108, 2}, []uint64{109, 3}),
+	}
+	for _, region := range suite.regions {
+		suite.cluster.PutRegion(region)
+	}
+	suite.mc = NewMergeChecker(suite.ctx, suite.cluster, suite.cluster.GetCheckerConfig())
+
+	ops := suite.mc.Check(suite.regions[1])
+	re.Len(ops, 2)
+	re.Equal(suite.regions[1].GetID(), ops[0].RegionID())
+	re.Equal(suite.regions[0].GetID(), ops[1].RegionID())
+
+	diagnoses := suite.mc.DiagnoseTargets(suite.regions[1])
+	re.Len(diagnoses, 2)
+	next, prev := diagnoses[0], diagnoses[1]
+	re.Equal(uint64(3), next.RegionID)
+	re.Equal("next", next.Direction)
+	re.Empty(next.Reason)
+	re.False(next.Selected)
+	re.Equal(1, next.Score.PeerMoves)
+	re.Equal(uint64(1), prev.RegionID)
+	re.Equal("prev", prev.Direction)
+	re.Empty(prev.Reason)
+	re.True(prev.Selected)
+	re.Zero(prev.Score.MisplacedPeers)
+	re.Zero(prev.Score.PeerMoves)
+	re.Greater(prev.Score.IsolationScore, next.Score.IsolationScore)
+
+	// only the next region can be the target with one way merge.
+	suite.cluster.SetEnableOneWayMerge(true)
+	diagnoses = suite.mc.DiagnoseTargets(suite.regions[1])
+	re.True(diagnoses[0].Selected)
+	re.Equal("one-way-merge", diagnoses[1].Reason)
+	re.False(diagnoses[1].Selected)
+	ops = suite.mc.Check(suite.regions[1])
+	re.Len(ops, 2)
+	re.Equal(suite.regions[2].GetID(), ops[1].RegionID())
+
+	// the more compatible region is skipped if it's too large to merge into.
+	suite.cluster.SetEnableOneWayMerge(false)
+	suite.regions[0] = suite.regions[0].Clone(core.SetApproximateSize(1000))
+	suite.cluster.PutRegion(suite.regions[0])
+	ops = suite.mc.Check(suite.regions[1])
+	re.Len(ops, 2)
+	re.Equal(suite.regions[2].GetID(), ops[1].RegionID())
+}
+
 func makeKeyRanges(keys ...string) []any {
 	var res []any
 	for i := 0; i < len(keys); i += 2 {
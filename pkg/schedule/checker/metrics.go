@@ -24,10 +24,19 @@ var (
 			Name:      "event_count",
 			Help:      "Counter of checker events.",
 		}, []string{"type", "name"})
+
+	keyspaceRuleViolationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "checker",
+			Name:      "keyspace_rule_violation_count",
+			Help:      "Counter of the operators added to fix the placement rule violations per keyspace.",
+		}, []string{"keyspace", "type"})
 )
 
 func init() {
 	prometheus.MustRegister(checkerCounter)
+	prometheus.MustRegister(keyspaceRuleViolationCounter)
 }
 
 const (
@@ -89,6 +98,7 @@ var (
 	ruleCheckerRemoveOrphanPeerCounter            = ruleCheckerCounterWithEvent("remove-orphan-peer")
 	ruleCheckerReplaceOrphanPeerCounter           = ruleCheckerCounterWithEvent("replace-orphan-peer")
 	ruleCheckerReplaceOrphanPeerNoFitCounter      = ruleCheckerCounterWithEvent("replace-orphan-peer-no-fit")
+	ruleCheckerRemoveSlowLearnerCounter           = ruleCheckerCounterWithEvent("remove-slow-learner")
 
 	jointCheckCounter                 = jointStateCheckerCounterWithEvent("check")
 	jointCheckerPausedCounter         = jointStateCheckerCounterWithEvent("paused")
@@ -0,0 +1,346 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
+	sche "github.com/tikv/pd/pkg/schedule/core"
+	"github.com/tikv/pd/pkg/schedule/filter"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/utils/logutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+// The status of the range merge jobs.
+const (
+	RangeMergeRunning  = "running"
+	RangeMergeFinished = "finished"
+	RangeMergeStopped  = "stopped"
+	RangeMergeCanceled = "canceled"
+)
+
+const (
+	rangeMergeDesc       = "merge-range"
+	maxRangeMergeHistory = 16
+	// maxRunningRangeMergeJobs is the max number of the jobs running at the same
+	// time, each of them runs in its own goroutine.
+	maxRunningRangeMergeJobs = 4
+	// rangeMergeBatchSize is the max number of the regions scanned by a job in
+	// each round, the job goes through the range batch by batch.
+	rangeMergeBatchSize = 512
+)
+
+var rangeMergeInterval = time.Second
+
+// RangeMergeProgress is the progress of a range merge job.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RangeMergeProgress struct {
+	ID       uint64 `json:"id"`
+	StartKey string `json:"start_key"`
+	EndKey   string `json:"end_key"`
+	// TargetCount is the expected number of the regions in the range after merging.
+	TargetCount  int `json:"target_count"`
+	InitialCount int `json:"initial_count"`
+	CurrentCount int `json:"current_count"`
+	// Operators is the number of the merge operators created by the job.
+	Operators int `json:"operators"`
+	// Progress is the ratio of the merged regions to the regions to merge.
+	Progress   float64   `json:"progress"`
+	Status     string    `json:"status"`
+	StartTime  time.Time `json:"start_time"`
+	FinishTime time.Time `json:"finish_time"`
+}
+
+type rangeMergeJob struct {
+	RangeMergeProgress
+	startKey, endKey []byte
+	cancel           context.CancelFunc
+
+	// cursor is the key to scan the next batch from, it goes back to the start
+	// key after reaching the end of the range.
+	cursor []byte
+	// pass records what happened since the cursor started from the start key.
+	pass struct {
+		created, busy int
+		limited       bool
+	}
+}
+
+func (j *rangeMergeJob) update(count int) {
+	j.CurrentCount = count
+	toMerge := j.InitialCount - j.TargetCount
+	switch {
+	case toMerge <= 0 || count <= j.TargetCount:
+		j.Progress = 1
+	case count < j.InitialCount:
+		j.Progress = float64(j.InitialCount-count) / float64(toMerge)
+	}
+}
+
+// RangeMerger merges the undersized regions in the key ranges aggressively on
+// demand, e.g. after dropping or truncating tables. Unlike the merge checker,
+// it doesn't wait for the split merge interval and doesn't skip the hot
+// regions, but it still respects the merge schedule limit.
+type RangeMerger struct {
+	ctx          context.Context
+	cluster      sche.CheckerCluster
+	opController *operator.Controller
+	batchSize    int
+
+	mu struct {
+		syncutil.RWMutex
+		nextID uint64
+		jobs   []*rangeMergeJob
+	}
+}
+
+// NewRangeMerger creates a range merger.
+func NewRangeMerger(ctx context.Context, cluster sche.CheckerCluster, opController *operator.Controller) *RangeMerger {
+	return &RangeMerger{
+		ctx:          ctx,
+		cluster:      cluster,
+		opController: opController,
+		batchSize:    rangeMergeBatchSize,
+	}
+}
+
+// MergeRange starts a job to merge the undersized regions in the key range
+// until there are no more than targetCount regions in the range.
+func (m *RangeMerger) MergeRange(startKey, endKey []byte, targetCount int) (*RangeMergeProgress, error) {
+	if len(endKey) > 0 && bytes.Compare(startKey, endKey) >= 0 {
+		return nil, errs.ErrRangeMergeInvalidRange.FastGenByArgs("start key should be less than end key")
+	}
+	if targetCount < 1 {
+		targetCount = 1
+	}
+	m.mu.Lock()
+	if m.runningCountLocked() >= maxRunningRangeMergeJobs {
+		m.mu.Unlock()
+		return nil, errs.ErrRangeMergeTooManyJobs.FastGenByArgs(maxRunningRangeMergeJobs)
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	job := &rangeMergeJob{
+		RangeMergeProgress: RangeMergeProgress{
+			StartKey:    core.HexRegionKeyStr(startKey),
+			EndKey:      core.HexRegionKeyStr(endKey),
+			TargetCount: targetCount,
+			Status:      RangeMergeRunning,
+			StartTime:   time.Now(),
+		},
+		startKey: startKey,
+		endKey:   endKey,
+		cancel:   cancel,
+		cursor:   startKey,
+	}
+	job.InitialCount = m.cluster.GetBasicCluster().GetRegionCount(startKey, endKey)
+	job.update(job.InitialCount)
+	m.mu.nextID++
+	job.ID = m.mu.nextID
+	m.mu.jobs = append(m.mu.jobs, job)
+	m.gcLocked()
+	progress := job.RangeMergeProgress
+	m.mu.Unlock()
+
+	log.Info("start merging regions in range",
+		zap.Uint64("job-id", job.ID),
+		zap.String("start-key", job.StartKey),
+		zap.String("end-key", job.EndKey),
+		zap.Int("region-count", job.InitialCount),
+		zap.Int("target-count", targetCount))
+	go m.run(ctx, job)
+	return &progress, nil
+}
+
+// CancelJob cancels the running range merge job.
+func (m *RangeMerger) CancelJob(id uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.mu.jobs {
+		if job.ID == id {
+			if job.Status == RangeMergeRunning {
+				job.cancel()
+				m.finishLocked(job, RangeMergeCanceled)
+			}
+			return nil
+		}
+	}
+	return errs.ErrRangeMergeJobNotFound.FastGenByArgs(id)
+}
+
+// GetJobs returns the progress of the recent range merge jobs.
+func (m *RangeMerger) GetJobs() []*RangeMergeProgress {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	jobs := make([]*RangeMergeProgress, 0, len(m.mu.jobs))
+	for _, job := range m.mu.jobs {
+		progress := job.RangeMergeProgress
+		jobs = append(jobs, &progress)
+	}
+	return jobs
+}
+
+func (m *RangeMerger) run(ctx context.Context, job *rangeMergeJob) {
+	defer logutil.LogPanic()
+
+	ticker := time.NewTicker(rangeMergeInterval)
+	defer ticker.Stop()
+	for {
+		if status, done := m.mergeOnce(job); done {
+			m.mu.Lock()
+			m.finishLocked(job, status)
+			m.mu.Unlock()
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// mergeOnce creates the merge operators for the undersized regions in the next
+// batch of the range, it returns true along with the final status if the job is
+// done.
+func (m *RangeMerger) mergeOnce(job *rangeMergeJob) (string, bool) {
+	count := m.cluster.GetBasicCluster().GetRegionCount(job.startKey, job.endKey)
+	m.mu.Lock()
+	job.update(count)
+	m.mu.Unlock()
+	if count <= job.TargetCount {
+		return RangeMergeFinished, true
+	}
+
+	regions := m.cluster.ScanRegions(job.cursor, job.endKey, m.batchSize)
+	conf := m.cluster.GetCheckerConfig()
+	limit := int(conf.GetMergeScheduleLimit()) - int(m.opController.OperatorCount(operator.OpMerge))
+	var created, busy int
+	for i := 0; i+1 < len(regions); i++ {
+		source, target := regions[i], regions[i+1]
+		if m.opController.GetOperator(source.GetID()) != nil || m.opController.GetOperator(target.GetID()) != nil {
+			busy++
+			continue
+		}
+		if limit <= 0 {
+			job.pass.limited = true
+			continue
+		}
+		if count-created <= job.TargetCount {
+			continue
+		}
+		if !m.allowMerge(job, source, target) {
+			continue
+		}
+		ops, err := operator.CreateMergeRegionOperator(rangeMergeDesc, m.cluster, source, target, operator.OpMerge|operator.OpAdmin)
+		if err != nil {
+			log.Debug("create merge region operator failed", zap.Uint64("job-id", job.ID), errs.ZapError(err))
+			continue
+		}
+		if m.opController.AddWaitingOperator(ops...) < len(ops) {
+			continue
+		}
+		created++
+		limit -= len(ops)
+		// the target is merged in this round, skip it as the source of the next pair.
+		i++
+	}
+	m.mu.Lock()
+	job.Operators += created
+	m.mu.Unlock()
+	job.pass.created += created
+	job.pass.busy += busy
+	if len(regions) == m.batchSize {
+		// the last region is left to be the source of the next batch.
+		job.cursor = regions[len(regions)-1].GetStartKey()
+		return "", false
+	}
+	// no more regions can be merged if nothing is created or in progress in
+	// the whole pass.
+	if job.pass.created == 0 && job.pass.busy == 0 && !job.pass.limited {
+		return RangeMergeStopped, true
+	}
+	job.cursor = job.startKey
+	job.pass.created, job.pass.busy, job.pass.limited = 0, 0, false
+	return "", false
+}
+
+func (m *RangeMerger) allowMerge(job *rangeMergeJob, source, target *core.RegionInfo) bool {
+	// only merge the regions inside the range.
+	if bytes.Compare(source.GetStartKey(), job.startKey) < 0 ||
+		(len(job.endKey) > 0 && (len(target.GetEndKey()) == 0 || bytes.Compare(target.GetEndKey(), job.endKey) > 0)) {
+		return false
+	}
+	if !bytes.Equal(source.GetEndKey(), target.GetStartKey()) {
+		return false
+	}
+	conf := m.cluster.GetCheckerConfig()
+	if !source.NeedMerge(int64(conf.GetMaxMergeRegionSize()), int64(conf.GetMaxMergeRegionKeys())) {
+		return false
+	}
+	for _, region := range []*core.RegionInfo{source, target} {
+		if region.GetLeader() == nil || !filter.IsRegionHealthy(region) || !filter.IsRegionReplicated(m.cluster, region) {
+			return false
+		}
+	}
+	if !AllowMerge(m.cluster, source, target) || !checkPeerStore(m.cluster, source, target) {
+		return false
+	}
+	storeConfig := m.cluster.GetStoreConfig()
+	return storeConfig.CheckRegionSize(uint64(source.GetApproximateSize()+target.GetApproximateSize()), conf.GetMaxMergeRegionSize()) == nil &&
+		storeConfig.CheckRegionKeys(uint64(source.GetApproximateKeys()+target.GetApproximateKeys()), conf.GetMaxMergeRegionKeys()) == nil
+}
+
+func (m *RangeMerger) finishLocked(job *rangeMergeJob, status string) {
+	if job.Status != RangeMergeRunning {
+		return
+	}
+	job.Status = status
+	job.FinishTime = time.Now()
+	log.Info("finish merging regions in range",
+		zap.Uint64("job-id", job.ID),
+		zap.String("status", status),
+		zap.Int("region-count", job.CurrentCount),
+		zap.Int("operators", job.Operators))
+}
+
+func (m *RangeMerger) runningCountLocked() int {
+	var count int
+	for _, job := range m.mu.jobs {
+		if job.Status == RangeMergeRunning {
+			count++
+		}
+	}
+	return count
+}
+
+// gcLocked removes the oldest finished jobs if there are too many jobs.
+func (m *RangeMerger) gcLocked() {
+	for len(m.mu.jobs) > maxRangeMergeHistory {
+		i := 0
+		for ; i < len(m.mu.jobs) && m.mu.jobs[i].Status == RangeMergeRunning; i++ {
+		}
+		if i == len(m.mu.jobs) {
+			return
+		}
+		m.mu.jobs = append(m.mu.jobs[:i], m.mu.jobs[i+1:]...)
+	}
+}
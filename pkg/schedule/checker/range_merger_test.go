@@ -0,0 +1,141 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/pkg/mock/mockconfig"
+	"github.com/tikv/pd/pkg/schedule/hbstream"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/utils/testutil"
+)
+
+func TestRangeMerger(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := mockcluster.NewCluster(ctx, mockconfig.NewTestOptions())
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddLeaderStore(i, 10)
+	}
+	keys := []string{"", "a", "b", "c", "d", "e", ""}
+	for i := 0; i+1 < len(keys); i++ {
+		id := uint64(i + 1)
+		tc.PutRegion(newRegionInfo(id, keys[i], keys[i+1], 1, 1, []uint64{id*10 + 1, 1},
+			[]uint64{id*10 + 1, 1}, []uint64{id*10 + 2, 2}, []uint64{id*10 + 3, 3}))
+	}
+	stream := hbstream.NewTestHeartbeatStreams(ctx, tc.ID, tc, false /* no need to run */)
+	oc := operator.NewController(ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	merger := NewRangeMerger(ctx, tc, oc)
+
+	_, err := merger.MergeRange([]byte("e"), []byte("a"), 1)
+	re.True(errs.ErrRangeMergeInvalidRange.Equal(err))
+
+	// only the regions inside [a, e) are merged, and no more than the target count.
+	progress, err := merger.MergeRange([]byte("a"), []byte("e"), 2)
+	re.NoError(err)
+	re.Equal(4, progress.InitialCount)
+	re.Equal(RangeMergeRunning, progress.Status)
+	testutil.Eventually(re, func() bool {
+		return merger.GetJobs()[0].Operators == 2
+	})
+	for _, id := range []uint64{2, 3, 4, 5} {
+		op := oc.GetOperator(id)
+		re.NotNil(op)
+		re.Equal(rangeMergeDesc, op.Desc())
+	}
+	re.Nil(oc.GetOperator(1))
+	re.Nil(oc.GetOperator(6))
+
+	// the job finishes once the regions are merged, the overlapped regions are replaced.
+	tc.PutRegion(newRegionInfo(3, "a", "c", 2, 2, []uint64{31, 1}, []uint64{31, 1}, []uint64{32, 2}, []uint64{33, 3}))
+	tc.PutRegion(newRegionInfo(5, "c", "e", 2, 2, []uint64{51, 1}, []uint64{51, 1}, []uint64{52, 2}, []uint64{53, 3}))
+	testutil.Eventually(re, func() bool {
+		return merger.GetJobs()[0].Status == RangeMergeFinished
+	})
+	jobs := merger.GetJobs()
+	re.Equal(2, jobs[0].CurrentCount)
+	re.Equal(1.0, jobs[0].Progress)
+
+	// the running job can be canceled.
+	progress, err = merger.MergeRange([]byte("a"), []byte("e"), 1)
+	re.NoError(err)
+	re.NoError(merger.CancelJob(progress.ID))
+	re.Equal(RangeMergeCanceled, merger.GetJobs()[1].Status)
+	re.True(errs.ErrRangeMergeJobNotFound.Equal(merger.CancelJob(100)))
+
+	// the number of the running jobs is limited.
+	for i := 0; i < maxRunningRangeMergeJobs; i++ {
+		_, err = merger.MergeRange([]byte("a"), []byte("e"), 1)
+		re.NoError(err)
+	}
+	_, err = merger.MergeRange([]byte("a"), []byte("e"), 1)
+	re.True(errs.ErrRangeMergeTooManyJobs.Equal(err))
+	jobs = merger.GetJobs()
+	re.NoError(merger.CancelJob(jobs[len(jobs)-1].ID))
+	_, err = merger.MergeRange([]byte("a"), []byte("e"), 1)
+	re.NoError(err)
+}
+
+func TestRangeMergerBatch(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := mockcluster.NewCluster(ctx, mockconfig.NewTestOptions())
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddLeaderStore(i, 10)
+	}
+	keys := []string{"", "a", "b", "c", "d", "e", ""}
+	for i := 0; i+1 < len(keys); i++ {
+		id := uint64(i + 1)
+		tc.PutRegion(newRegionInfo(id, keys[i], keys[i+1], 1, 1, []uint64{id*10 + 1, 1},
+			[]uint64{id*10 + 1, 1}, []uint64{id*10 + 2, 2}, []uint64{id*10 + 3, 3}))
+	}
+	stream := hbstream.NewTestHeartbeatStreams(ctx, tc.ID, tc, false /* no need to run */)
+	oc := operator.NewController(ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	merger := NewRangeMerger(ctx, tc, oc)
+	merger.batchSize = 2
+	job := &rangeMergeJob{startKey: []byte("a"), endKey: []byte("e"), cursor: []byte("a")}
+	job.TargetCount = 1
+	job.InitialCount = 4
+
+	// the range is scanned batch by batch, the last region of a batch is the
+	// first one of the next batch.
+	_, done := merger.mergeOnce(job)
+	re.False(done)
+	re.Equal([]byte("b"), job.cursor)
+	re.NotNil(oc.GetOperator(2))
+	re.NotNil(oc.GetOperator(3))
+	_, done = merger.mergeOnce(job)
+	re.False(done)
+	re.Equal([]byte("c"), job.cursor)
+	re.Nil(oc.GetOperator(4))
+	_, done = merger.mergeOnce(job)
+	re.False(done)
+	re.Equal([]byte("d"), job.cursor)
+	re.NotNil(oc.GetOperator(4))
+	re.NotNil(oc.GetOperator(5))
+	// the cursor goes back to the start key after the whole range is scanned.
+	_, done = merger.mergeOnce(job)
+	re.False(done)
+	re.Equal([]byte("a"), job.cursor)
+	re.Equal(2, job.Operators)
+	re.Equal(4, job.CurrentCount)
+}
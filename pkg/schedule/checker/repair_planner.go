@@ -0,0 +1,176 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tikv/pd/pkg/core"
+	sche "github.com/tikv/pd/pkg/schedule/core"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+// defaultRepairQueueSize is the max number of the regions waiting to be repaired.
+const defaultRepairQueueSize = 4096
+
+// repairItem is a region waiting to be repaired.
+type repairItem struct {
+	regionID      uint64
+	healthyVoters int
+	hasLeader     bool
+	since         time.Time
+}
+
+// moreUrgent returns true if the item should be repaired before the other.
+func (i *repairItem) moreUrgent(other *repairItem) bool {
+	if i.healthyVoters != other.healthyVoters {
+		return i.healthyVoters < other.healthyVoters
+	}
+	if i.hasLeader != other.hasLeader {
+		return !i.hasLeader
+	}
+	if !i.since.Equal(other.since) {
+		return i.since.Before(other.since)
+	}
+	return i.regionID < other.regionID
+}
+
+// RepairPlanner plans the repairs of the under-replicated regions globally.
+// When multiple stores fail concurrently, many regions become under-replicated
+// at the same time. Repairing them in the patrol order may leave the regions
+// which are about to lose the quorum behind the others, so the planner queues
+// the regions which cannot be repaired immediately due to the replica schedule
+// limit, and repairs the most urgent ones first, i.e. the regions with the
+// fewest healthy voters and then the regions without a healthy leader.
+type RepairPlanner struct {
+	cluster sche.CheckerCluster
+	// capacity is the max number of the queued regions.
+	capacity int
+
+	mu struct {
+		syncutil.Mutex
+		regions map[uint64]*repairItem
+	}
+}
+
+// NewRepairPlanner creates a repair planner.
+func NewRepairPlanner(cluster sche.CheckerCluster) *RepairPlanner {
+	p := &RepairPlanner{cluster: cluster, capacity: defaultRepairQueueSize}
+	p.mu.regions = make(map[uint64]*repairItem)
+	return p
+}
+
+// Add queues the region if it has unhealthy voters.
+func (p *RepairPlanner) Add(region *core.RegionInfo) {
+	item, ok := p.evaluate(region)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if old, ok := p.mu.regions[item.regionID]; ok {
+		item.since = old.since
+	} else if len(p.mu.regions) >= p.capacity {
+		// Evict the least urgent region to make room for a more urgent one.
+		var least *repairItem
+		for _, queued := range p.mu.regions {
+			if least == nil || least.moreUrgent(queued) {
+				least = queued
+			}
+		}
+		if !item.moreUrgent(least) {
+			return
+		}
+		delete(p.mu.regions, least.regionID)
+	}
+	p.mu.regions[item.regionID] = item
+}
+
+// Plan re-evaluates the queued regions and returns at most budget regions in
+// the order of the urgency, the regions matching skip are not returned, e.g.
+// the regions being repaired. The regions which are repaired or gone are removed.
+func (p *RepairPlanner) Plan(budget int, skip func(regionID uint64) bool) []*core.RegionInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if budget <= 0 || len(p.mu.regions) == 0 {
+		return nil
+	}
+	items := make([]*repairItem, 0, len(p.mu.regions))
+	regions := make(map[uint64]*core.RegionInfo, len(p.mu.regions))
+	for id, old := range p.mu.regions {
+		region := p.cluster.GetRegion(id)
+		item, ok := p.evaluate(region)
+		if !ok {
+			delete(p.mu.regions, id)
+			continue
+		}
+		item.since = old.since
+		p.mu.regions[id] = item
+		if skip != nil && skip(id) {
+			continue
+		}
+		items = append(items, item)
+		regions[id] = region
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].moreUrgent(items[j]) })
+	if len(items) > budget {
+		items = items[:budget]
+	}
+	planned := make([]*core.RegionInfo, 0, len(items))
+	for _, item := range items {
+		planned = append(planned, regions[item.regionID])
+	}
+	return planned
+}
+
+// Len returns the number of the regions waiting to be repaired.
+func (p *RepairPlanner) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.mu.regions)
+}
+
+// evaluate evaluates the urgency of the repair, it returns false if the region
+// doesn't need to be repaired.
+func (p *RepairPlanner) evaluate(region *core.RegionInfo) (*repairItem, bool) {
+	if region == nil {
+		return nil, false
+	}
+	item := &repairItem{regionID: region.GetID(), since: time.Now()}
+	unhealthy := 0
+	for _, peer := range region.GetVoters() {
+		if !p.isHealthy(region, peer.GetId(), peer.GetStoreId()) {
+			unhealthy++
+			continue
+		}
+		item.healthyVoters++
+	}
+	if unhealthy == 0 {
+		return nil, false
+	}
+	if leader := region.GetLeader(); leader != nil {
+		item.hasLeader = p.isHealthy(region, leader.GetId(), leader.GetStoreId())
+	}
+	return item, true
+}
+
+func (p *RepairPlanner) isHealthy(region *core.RegionInfo, peerID, storeID uint64) bool {
+	if region.GetDownPeer(peerID) != nil {
+		return false
+	}
+	store := p.cluster.GetStore(storeID)
+	return store != nil && !store.IsRemoved() && !store.IsDisconnected()
+}
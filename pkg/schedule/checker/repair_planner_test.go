@@ -0,0 +1,80 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/pkg/mock/mockconfig"
+)
+
+func TestRepairPlanner(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := mockcluster.NewCluster(ctx, mockconfig.NewTestOptions())
+	for i := uint64(1); i <= 5; i++ {
+		tc.AddRegionStore(i, 10)
+	}
+	tc.SetStoreDisconnect(4)
+	tc.SetStoreDisconnect(5)
+
+	planner := NewRepairPlanner(tc)
+	// two healthy voters with a healthy leader.
+	planner.Add(tc.AddLeaderRegion(1, 1, 2, 4))
+	// two healthy voters without a healthy leader.
+	planner.Add(tc.AddLeaderRegion(2, 4, 1, 2))
+	// one healthy voter.
+	planner.Add(tc.AddLeaderRegion(3, 1, 4, 5))
+	// healthy regions are not queued.
+	planner.Add(tc.AddLeaderRegion(4, 1, 2, 3))
+	re.Equal(3, planner.Len())
+
+	ids := func(regions []*core.RegionInfo) []uint64 {
+		var ids []uint64
+		for _, region := range regions {
+			ids = append(ids, region.GetID())
+		}
+		return ids
+	}
+	re.Empty(planner.Plan(0, nil))
+	re.Equal([]uint64{3, 2}, ids(planner.Plan(2, nil)))
+	re.Equal([]uint64{3, 2, 1}, ids(planner.Plan(10, nil)))
+	// the skipped regions are kept in the queue.
+	re.Equal([]uint64{2, 1}, ids(planner.Plan(10, func(id uint64) bool { return id == 3 })))
+	re.Equal(3, planner.Len())
+
+	// the repaired regions are removed.
+	tc.AddLeaderRegion(3, 1, 2, 3)
+	re.Equal([]uint64{2, 1}, ids(planner.Plan(10, nil)))
+	re.Equal(2, planner.Len())
+	tc.SetStoreUp(4)
+	re.Empty(planner.Plan(10, nil))
+	re.Zero(planner.Len())
+
+	// the least urgent region is evicted once the queue is full.
+	planner.capacity = 2
+	planner.Add(tc.AddLeaderRegion(5, 1, 2, 5))
+	planner.Add(tc.AddLeaderRegion(6, 5, 1, 2))
+	planner.Add(tc.AddLeaderRegion(7, 1, 2, 5))
+	re.Equal([]uint64{6, 5}, ids(planner.Plan(10, nil)))
+	tc.SetStoreDisconnect(4)
+	planner.Add(tc.AddLeaderRegion(8, 1, 5, 4))
+	re.Equal([]uint64{8, 6}, ids(planner.Plan(10, nil)))
+}
@@ -272,10 +272,11 @@ func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, storeID uint64, status
 
 func (r *ReplicaChecker) strategy(region *core.RegionInfo) *ReplicaStrategy {
 	return &ReplicaStrategy{
-		checkerName:    r.Name(),
-		cluster:        r.cluster,
-		locationLabels: r.conf.GetLocationLabels(),
-		isolationLevel: r.conf.GetIsolationLevel(),
-		region:         region,
+		checkerName:     r.Name(),
+		cluster:         r.cluster,
+		locationLabels:  r.conf.GetLocationLabels(),
+		locationWeights: r.conf.GetLocationLabelWeights(),
+		isolationLevel:  r.conf.GetIsolationLevel(),
+		region:          region,
 	}
 }
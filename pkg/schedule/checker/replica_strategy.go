@@ -18,8 +18,10 @@ import (
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/constant"
+	"github.com/tikv/pd/pkg/schedule/config"
 	sche "github.com/tikv/pd/pkg/schedule/core"
 	"github.com/tikv/pd/pkg/schedule/filter"
+	"github.com/tikv/pd/pkg/schedule/labeler"
 	"go.uber.org/zap"
 )
 
@@ -29,10 +31,13 @@ type ReplicaStrategy struct {
 	checkerName    string // replica-checker / rule-checker
 	cluster        sche.CheckerCluster
 	locationLabels []string
-	isolationLevel string
-	region         *core.RegionInfo
-	extraFilters   []filter.Filter
-	fastFailover   bool
+	// locationWeights are the weights of the location labels, nil for the
+	// default weights.
+	locationWeights []float64
+	isolationLevel  string
+	region          *core.RegionInfo
+	extraFilters    []filter.Filter
+	fastFailover    bool
 }
 
 // SelectStoreToAdd returns the store to add a replica to a region.
@@ -46,6 +51,11 @@ type ReplicaStrategy struct {
 // Meanwhile, we need to provide more constraints to ensure that the isolation
 // level cannot be reduced after replacement.
 func (s *ReplicaStrategy) SelectStoreToAdd(coLocationStores []*core.StoreInfo, extraFilters ...filter.Filter) (uint64, bool) {
+	return s.selectStoreToAdd(s.cluster.GetStores(), coLocationStores, extraFilters...)
+}
+
+// selectStoreToAdd selects the store to add a replica from the candidates.
+func (s *ReplicaStrategy) selectStoreToAdd(candidates, coLocationStores []*core.StoreInfo, extraFilters ...filter.Filter) (uint64, bool) {
 	// The selection process uses a two-stage fashion. The first stage
 	// ignores the temporary state of the stores and selects the stores
 	// with the highest score according to the location label. The second
@@ -74,15 +84,15 @@ func (s *ReplicaStrategy) SelectStoreToAdd(coLocationStores []*core.StoreInfo, e
 		filters = append(filters, s.extraFilters...)
 	}
 
-	isolationComparer := filter.IsolationComparer(s.locationLabels, coLocationStores)
+	isolationComparer := filter.IsolationComparer(s.locationLabels, s.locationWeights, coLocationStores)
 	strictStateFilter := &filter.StoreStateFilter{ActionScope: s.checkerName, MoveRegion: true, AllowFastFailover: s.fastFailover, OperatorLevel: level}
-	targetCandidate := filter.NewCandidates(s.cluster.GetStores()).
+	targetCandidate := filter.NewCandidates(candidates).
 		FilterTarget(s.cluster.GetCheckerConfig(), nil, nil, filters...).
 		KeepTheTopStores(isolationComparer, false) // greater isolation score is better
 	if targetCandidate.Len() == 0 {
 		return 0, false
 	}
-	target := targetCandidate.FilterTarget(s.cluster.GetCheckerConfig(), nil, nil, strictStateFilter).
+	target := targetCandidate.FilterTarget(s.getRegionConfig(), nil, nil, strictStateFilter).
 		PickTheTopStore(filter.RegionScoreComparer(s.cluster.GetCheckerConfig()), true) // less region score is better
 	if target == nil {
 		return 0, true // filter by temporary states
@@ -90,8 +100,19 @@ func (s *ReplicaStrategy) SelectStoreToAdd(coLocationStores []*core.StoreInfo, e
 	return target.GetID(), false
 }
 
+// getRegionConfig returns the config with the range overrides of the region applied.
+func (s *ReplicaStrategy) getRegionConfig() config.SharedConfigProvider {
+	conf := s.cluster.GetCheckerConfig()
+	if cl, ok := s.cluster.(interface{ GetRegionLabeler() *labeler.RegionLabeler }); ok && cl.GetRegionLabeler() != nil {
+		return config.WithRangeOverrides(conf, cl.GetRegionLabeler().GetRangeOverrides(s.region))
+	}
+	return conf
+}
+
 // SelectStoreToFix returns a store to replace down/offline old peer. The location
-// placement after scheduling is allowed to be worse than original.
+// placement after scheduling is allowed to be worse than original. If the old
+// store is being replaced, the peer is moved to the replacement target unless
+// it makes the placement worse.
 func (s *ReplicaStrategy) SelectStoreToFix(coLocationStores []*core.StoreInfo, old uint64) (uint64, bool) {
 	if len(coLocationStores) == 0 {
 		return 0, false
@@ -103,6 +124,15 @@ func (s *ReplicaStrategy) SelectStoreToFix(coLocationStores []*core.StoreInfo, o
 	if len(coLocationStores) > 1 {
 		coLocationStores = coLocationStores[1:]
 	}
+	if oldStore := s.cluster.GetStore(old); oldStore != nil && oldStore.GetReplacementTarget() != 0 {
+		if target := s.cluster.GetStore(oldStore.GetReplacementTarget()); target != nil {
+			safeguard := filter.NewLocationSafeguard(s.checkerName, s.locationLabels, s.locationWeights, coLocationStores, oldStore)
+			// Wait for the target if it's only filtered by the temporary states.
+			if id, filterByTempState := s.selectStoreToAdd([]*core.StoreInfo{target}, coLocationStores, safeguard); id != 0 || filterByTempState {
+				return id, filterByTempState
+			}
+		}
+	}
 	return s.SelectStoreToAdd(coLocationStores)
 }
 
@@ -119,7 +149,7 @@ func (s *ReplicaStrategy) SelectStoreToImprove(coLocationStores []*core.StoreInf
 		return 0, false
 	}
 	filters := []filter.Filter{
-		filter.NewLocationImprover(s.checkerName, s.locationLabels, coLocationStores, oldStore),
+		filter.NewLocationImprover(s.checkerName, s.locationLabels, s.locationWeights, coLocationStores, oldStore),
 	}
 	if len(s.locationLabels) > 0 && s.isolationLevel != "" {
 		filters = append(filters, filter.NewIsolationFilter(s.checkerName, s.isolationLevel, s.locationLabels, coLocationStores[1:]))
@@ -138,7 +168,7 @@ func swapStoreToFirst(stores []*core.StoreInfo, id uint64) {
 
 // SelectStoreToRemove returns the best option to remove from the region.
 func (s *ReplicaStrategy) SelectStoreToRemove(coLocationStores []*core.StoreInfo) uint64 {
-	isolationComparer := filter.IsolationComparer(s.locationLabels, coLocationStores)
+	isolationComparer := filter.IsolationComparer(s.locationLabels, s.locationWeights, coLocationStores)
 	level := constant.High
 	if s.fastFailover {
 		level = constant.Urgent
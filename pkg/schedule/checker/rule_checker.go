@@ -27,11 +27,13 @@ import (
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/schedule/config"
 	sche "github.com/tikv/pd/pkg/schedule/core"
 	"github.com/tikv/pd/pkg/schedule/filter"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/schedule/placement"
 	types "github.com/tikv/pd/pkg/schedule/type"
+	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/tikv/pd/pkg/versioninfo"
 	"go.uber.org/zap"
 )
@@ -56,12 +58,15 @@ type RuleChecker struct {
 	pendingList        cache.Cache
 	switchWitnessCache *cache.TTLUint64
 	record             *recorder
+	// opController is used to get the learners which failed to catch up in time.
+	opController *operator.Controller
 }
 
 // NewRuleChecker creates a checker instance.
-func NewRuleChecker(ctx context.Context, cluster sche.CheckerCluster, ruleManager *placement.RuleManager, regionWaitingList cache.Cache) *RuleChecker {
+func NewRuleChecker(ctx context.Context, cluster sche.CheckerCluster, ruleManager *placement.RuleManager, regionWaitingList cache.Cache, opController *operator.Controller) *RuleChecker {
 	return &RuleChecker{
 		cluster:            cluster,
+		opController:       opController,
 		ruleManager:        ruleManager,
 		regionWaitingList:  regionWaitingList,
 		pendingList:        cache.NewDefaultCache(maxPendingListLen),
@@ -114,6 +119,10 @@ func (c *RuleChecker) CheckWithFit(region *core.RegionInfo, fit *placement.Regio
 		// multiple rules.
 		return nil
 	}
+	if op := c.fixSlowLearner(region); op != nil {
+		c.pendingList.Remove(region.GetID())
+		return op
+	}
 	op, err := c.fixOrphanPeers(region, fit)
 	if err != nil {
 		log.Debug("fail to fix orphan peer", errs.ZapError(err))
@@ -153,6 +162,35 @@ func (c *RuleChecker) isWitnessEnabled() bool {
 		c.cluster.GetCheckerConfig().IsWitnessAllowed()
 }
 
+// getSlowLearnerStore returns the store of the learner which failed to catch up
+// in time recently, the learner should not be added to the store again.
+func (c *RuleChecker) getSlowLearnerStore(regionID uint64) (uint64, bool) {
+	if c.opController == nil {
+		return 0, false
+	}
+	return c.opController.GetLearnerCatchUpTimeoutStore(regionID)
+}
+
+// fixSlowLearner removes the learner which failed to catch up in time, so that
+// it can be added to another store.
+func (c *RuleChecker) fixSlowLearner(region *core.RegionInfo) *operator.Operator {
+	storeID, ok := c.getSlowLearnerStore(region.GetID())
+	if !ok {
+		return nil
+	}
+	peer := region.GetStoreLearner(storeID)
+	if peer == nil || region.GetPendingLearner(peer.GetId()) == nil {
+		return nil
+	}
+	op, err := operator.CreateRemovePeerOperator("remove-slow-learner", c.cluster, operator.OpReplica, region, storeID)
+	if err != nil {
+		log.Debug("fail to remove slow learner", zap.Uint64("region-id", region.GetID()), errs.ZapError(err))
+		return nil
+	}
+	ruleCheckerRemoveSlowLearnerCounter.Inc()
+	return op
+}
+
 func (c *RuleChecker) fixRulePeer(region *core.RegionInfo, fit *placement.RegionFit, rf *placement.RuleFit) (*operator.Operator, error) {
 	// make up peers.
 	if len(rf.Peers) < rf.Rule.Count {
@@ -204,7 +242,7 @@ func (c *RuleChecker) addRulePeer(region *core.RegionInfo, fit *placement.Region
 		// issue: https://github.com/tikv/pd/issues/7185
 		for _, p := range region.GetPeers() {
 			s := c.cluster.GetStore(p.GetStoreId())
-			if placement.MatchLabelConstraints(s, rf.Rule.LabelConstraints) {
+			if rf.Rule.MatchStore(s) {
 				oldPeerRuleFit := fit.GetRuleFit(p.GetId())
 				if oldPeerRuleFit == nil || !oldPeerRuleFit.IsSatisfied() || oldPeerRuleFit == rf {
 					continue
@@ -374,7 +412,7 @@ func (c *RuleChecker) allowLeader(fit *placement.RegionFit, peer *metapb.Peer) b
 	}
 	for _, rf := range fit.RuleFits {
 		if (rf.Rule.Role == placement.Leader || rf.Rule.Role == placement.Voter) &&
-			placement.MatchLabelConstraints(s, rf.Rule.LabelConstraints) {
+			rf.Rule.MatchStore(s) {
 			return true
 		}
 	}
@@ -397,7 +435,7 @@ func (c *RuleChecker) fixBetterLocation(region *core.RegionInfo, rf *placement.R
 	var coLocationStores []*core.StoreInfo
 	regionStores := c.cluster.GetRegionStores(region)
 	for _, s := range regionStores {
-		if placement.MatchLabelConstraints(s, rf.Rule.LabelConstraints) {
+		if rf.Rule.MatchStore(s) {
 			coLocationStores = append(coLocationStores, s)
 		}
 	}
@@ -614,14 +652,19 @@ func (c *RuleChecker) hasAvailableWitness(region *core.RegionInfo, peer *metapb.
 }
 
 func (c *RuleChecker) strategy(region *core.RegionInfo, rule *placement.Rule, fastFailover bool) *ReplicaStrategy {
+	extraFilters := []filter.Filter{filter.NewLabelConstraintFilter(c.Name(), rule.StoreConstraints())}
+	if storeID, ok := c.getSlowLearnerStore(region.GetID()); ok {
+		extraFilters = append(extraFilters, filter.NewExcludedFilter(c.Name(), nil, map[uint64]struct{}{storeID: {}}))
+	}
 	return &ReplicaStrategy{
-		checkerName:    c.Name(),
-		cluster:        c.cluster,
-		isolationLevel: rule.IsolationLevel,
-		locationLabels: rule.LocationLabels,
-		region:         region,
-		extraFilters:   []filter.Filter{filter.NewLabelConstraintFilter(c.Name(), rule.LabelConstraints)},
-		fastFailover:   fastFailover,
+		checkerName:     c.Name(),
+		cluster:         c.cluster,
+		isolationLevel:  rule.IsolationLevel,
+		locationLabels:  rule.LocationLabels,
+		locationWeights: config.LocationWeightsOf(c.cluster.GetSharedConfig(), rule.LocationLabels),
+		region:          region,
+		extraFilters:    extraFilters,
+		fastFailover:    fastFailover,
 	}
 }
 
@@ -645,6 +688,7 @@ func (c *RuleChecker) handleFilterState(region *core.RegionInfo, filterByTempSta
 }
 
 type recorder struct {
+	syncutil.Mutex
 	offlineLeaderCounter map[uint64]uint64
 	lastUpdateTime       time.Time
 }
@@ -657,10 +701,14 @@ func newRecord() *recorder {
 }
 
 func (o *recorder) getOfflineLeaderCount(storeID uint64) uint64 {
+	o.Lock()
+	defer o.Unlock()
 	return o.offlineLeaderCounter[storeID]
 }
 
 func (o *recorder) incOfflineLeaderCount(storeID uint64) {
+	o.Lock()
+	defer o.Unlock()
 	o.offlineLeaderCounter[storeID] += 1
 	o.lastUpdateTime = time.Now()
 }
@@ -669,6 +717,8 @@ func (o *recorder) incOfflineLeaderCount(storeID uint64) {
 var offlineCounterTTL = 5 * time.Minute
 
 func (o *recorder) refresh(cluster sche.CheckerCluster) {
+	o.Lock()
+	defer o.Unlock()
 	// re-count the offlineLeaderCounter if the store is already tombstone or store is gone.
 	if len(o.offlineLeaderCounter) > 0 && time.Since(o.lastUpdateTime) > offlineCounterTTL {
 		needClean := false
@@ -33,6 +33,7 @@ import (
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/mock/mockconfig"
+	"github.com/tikv/pd/pkg/schedule/hbstream"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/schedule/placement"
 	"github.com/tikv/pd/pkg/utils/operatorutil"
@@ -62,7 +63,7 @@ func (suite *ruleCheckerTestSuite) SetupTest() {
 	suite.cluster.SetEnableWitness(true)
 	suite.cluster.SetEnableUseJointConsensus(false)
 	suite.ruleManager = suite.cluster.RuleManager
-	suite.rc = NewRuleChecker(suite.ctx, suite.cluster, suite.ruleManager, cache.NewDefaultCache(10))
+	suite.rc = NewRuleChecker(suite.ctx, suite.cluster, suite.ruleManager, cache.NewDefaultCache(10), nil)
 }
 
 func (suite *ruleCheckerTestSuite) TearDownTest() {
@@ -82,6 +83,41 @@ func (suite *ruleCheckerTestSuite) TestAddRulePeer() {
 	re.Equal(uint64(3), op.Step(0).(operator.AddLearner).ToStore)
 }
 
+func (suite *ruleCheckerTestSuite) TestRemoveSlowLearner() {
+	re := suite.Require()
+	for i := uint64(1); i <= 4; i++ {
+		suite.cluster.AddLeaderStore(i, 1)
+	}
+	suite.cluster.AddLeaderRegionWithRange(1, "", "", 1, 2)
+	region := suite.cluster.GetRegion(1)
+	learner := &metapb.Peer{Id: 100, StoreId: 3, Role: metapb.PeerRole_Learner}
+	region = region.Clone(core.WithAddPeer(learner), core.WithPendingPeers([]*metapb.Peer{learner}))
+	suite.cluster.PutRegion(region)
+
+	// cancel the operator whose learner fails to catch up in time.
+	stream := hbstream.NewTestHeartbeatStreams(suite.ctx, suite.cluster.ID, suite.cluster, false /* no need to run */)
+	oc := operator.NewController(suite.ctx, suite.cluster.GetBasicCluster(), suite.cluster.GetSharedConfig(), stream)
+	op := operator.NewTestOperator(1, region.GetRegionEpoch(), operator.OpRegion,
+		operator.AddLearner{ToStore: 3, PeerID: 100},
+		operator.PromoteLearner{ToStore: 3, PeerID: 100})
+	re.True(oc.AddOperator(op))
+	op.SetStatusReachTime(operator.STARTED, time.Now().Add(-2*time.Minute))
+	suite.cluster.SetMaxLearnerCatchUpTime(time.Minute)
+	oc.Dispatch(region, operator.DispatchFromHeartBeat, nil)
+	re.Nil(oc.GetOperator(1))
+
+	// the slow learner is removed, and the new peer is added to another store.
+	suite.rc = NewRuleChecker(suite.ctx, suite.cluster, suite.ruleManager, cache.NewDefaultCache(10), oc)
+	op = suite.rc.Check(region)
+	re.NotNil(op)
+	re.Equal("remove-slow-learner", op.Desc())
+	re.Equal(uint64(3), op.Step(0).(operator.RemovePeer).FromStore)
+	op = suite.rc.Check(suite.cluster.GetRegion(1).Clone(core.WithRemoveStorePeer(3)))
+	re.NotNil(op)
+	re.Equal("add-rule-peer", op.Desc())
+	re.Equal(uint64(4), op.Step(0).(operator.AddLearner).ToStore)
+}
+
 func (suite *ruleCheckerTestSuite) TestAddRulePeerWithIsolationLevel() {
 	re := suite.Require()
 	suite.cluster.AddLabelsStore(1, 1, map[string]string{"zone": "z1", "rack": "r1", "host": "h1"})
@@ -742,6 +778,22 @@ func (suite *ruleCheckerTestSuite) TestBetterReplacement2() {
 	re.Nil(op)
 }
 
+func (suite *ruleCheckerTestSuite) TestStrategyLocationWeights() {
+	re := suite.Require()
+	suite.cluster.SetLocationLabels([]string{"zone", "rack", "host"})
+	suite.cluster.SetLocationLabelWeights([]float64{1, 10, 1})
+	suite.cluster.AddLeaderRegionWithRange(1, "", "", 1, 2, 3)
+	region := suite.cluster.GetRegion(1)
+	rule := &placement.Rule{LocationLabels: []string{"zone", "rack"}}
+	re.Equal([]float64{1, 10}, suite.rc.strategy(region, rule, false).locationWeights)
+	// the default weights are used if any label of the rule has no weight.
+	rule.LocationLabels = []string{"zone", "dc"}
+	re.Nil(suite.rc.strategy(region, rule, false).locationWeights)
+	suite.cluster.SetLocationLabelWeights(nil)
+	rule.LocationLabels = []string{"zone", "rack"}
+	re.Nil(suite.rc.strategy(region, rule, false).locationWeights)
+}
+
 func (suite *ruleCheckerTestSuite) TestNoBetterReplacement() {
 	re := suite.Require()
 	suite.cluster.AddLabelsStore(1, 1, map[string]string{"host": "host1"})
@@ -1571,6 +1623,34 @@ func (suite *ruleCheckerTestSuite) TestFixOfflinePeer() {
 	re.Nil(suite.rc.Check(region))
 }
 
+func (suite *ruleCheckerTestSuite) TestFixOfflinePeerToReplacementTarget() {
+	re := suite.Require()
+	suite.cluster.AddLabelsStore(1, 1, map[string]string{"zone": "z1"})
+	suite.cluster.AddLabelsStore(2, 1, map[string]string{"zone": "z2"})
+	suite.cluster.AddLabelsStore(3, 1, map[string]string{"zone": "z3"})
+	suite.cluster.AddLabelsStore(4, 10, map[string]string{"zone": "z3"})
+	suite.cluster.AddLabelsStore(5, 1, map[string]string{"zone": "z3"})
+	suite.cluster.AddLabelsStore(6, 1, map[string]string{"zone": "z1"})
+	suite.cluster.AddLeaderRegion(1, 1, 2, 3)
+	suite.ruleManager.SetRule(&placement.Rule{
+		GroupID:        placement.DefaultGroupID,
+		ID:             placement.DefaultRuleID,
+		Role:           placement.Voter,
+		Count:          3,
+		LocationLabels: []string{"zone"},
+	})
+	region := suite.cluster.GetRegion(1)
+
+	// the peer is moved to the replacement target rather than the least loaded store.
+	suite.cluster.PutStore(suite.cluster.GetStore(3).Clone(
+		core.SetStoreState(metapb.StoreState_Offline, false), core.SetReplacementTarget(4)))
+	operatorutil.CheckTransferPeer(re, suite.rc.Check(region), operator.OpRegion, 3, 4)
+
+	// the replacement target is ignored if it makes the placement worse.
+	suite.cluster.PutStore(suite.cluster.GetStore(3).Clone(core.SetReplacementTarget(6)))
+	operatorutil.CheckTransferPeer(re, suite.rc.Check(region), operator.OpRegion, 3, 5)
+}
+
 func (suite *ruleCheckerTestSuite) TestFixOfflinePeerWithAvailableWitness() {
 	re := suite.Require()
 	suite.cluster.AddLabelsStore(1, 1, map[string]string{"zone": "z1"})
@@ -1955,7 +2035,7 @@ func (suite *ruleCheckerTestAdvancedSuite) SetupTest() {
 	suite.cluster.SetEnableWitness(true)
 	suite.cluster.SetEnableUseJointConsensus(true)
 	suite.ruleManager = suite.cluster.RuleManager
-	suite.rc = NewRuleChecker(suite.ctx, suite.cluster, suite.ruleManager, cache.NewDefaultCache(10))
+	suite.rc = NewRuleChecker(suite.ctx, suite.cluster, suite.ruleManager, cache.NewDefaultCache(10), nil)
 }
 
 func (suite *ruleCheckerTestAdvancedSuite) TearDownTest() {
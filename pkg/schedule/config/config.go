@@ -15,6 +15,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -40,6 +41,12 @@ const (
 	defaultTolerantSizeRatio      = 0
 	defaultLowSpaceRatio          = 0.8
 	defaultHighSpaceRatio         = 0.7
+	defaultRegionStatsSampleRatio = 1.0
+	defaultMaxEvictingStoreRatio  = 0.5
+	// defaultDecisionLogMaxSize is the max size in MB of the decision log file
+	// before it's rotated.
+	defaultDecisionLogMaxSize    = 300
+	defaultDecisionLogMaxBackups = 5
 	// defaultHotRegionCacheHitsThreshold is the low hit number threshold of the
 	// hot region.
 	defaultHotRegionCacheHitsThreshold = 3
@@ -67,10 +74,17 @@ const (
 	DefaultSplitMergeInterval      = time.Hour
 	defaultSwitchWitnessInterval   = time.Hour
 	defaultPatrolRegionInterval    = 10 * time.Millisecond
+	defaultPatrolRegionConcurrency = 1
+	maxPatrolRegionConcurrency     = 64
 	defaultMaxStoreDownTime        = 30 * time.Minute
+	defaultDegradedZoneDownTime    = 10 * time.Minute
+	defaultDegradedZoneLabel       = "zone"
 	defaultHotRegionsWriteInterval = 10 * time.Minute
 	// It means we skip the preparing stage after the 48 hours no matter if the store has finished preparing stage.
 	defaultMaxStorePreparingTime = 48 * time.Hour
+	// defaultLeaderTransferBlacklistWindow is the base window to exclude a store
+	// as the leader target after it fails to accept the leader transfers.
+	defaultLeaderTransferBlacklistWindow = 30 * time.Second
 )
 
 var (
@@ -176,12 +190,26 @@ type ScheduleConfig struct {
 	EnableCrossTableMerge bool `toml:"enable-cross-table-merge" json:"enable-cross-table-merge,string"`
 	// PatrolRegionInterval is the interval for scanning region during patrol.
 	PatrolRegionInterval typeutil.Duration `toml:"patrol-region-interval" json:"patrol-region-interval"`
+	// PatrolRegionConcurrency is the number of the key space partitions patrolled concurrently.
+	PatrolRegionConcurrency int `toml:"patrol-region-concurrency" json:"patrol-region-concurrency"`
 	// MaxStoreDownTime is the max duration after which
 	// a store will be considered to be down if it hasn't reported heartbeats.
 	MaxStoreDownTime typeutil.Duration `toml:"max-store-down-time" json:"max-store-down-time"`
 	// MaxStorePreparingTime is the max duration after which
 	// a store will be considered to be preparing.
 	MaxStorePreparingTime typeutil.Duration `toml:"max-store-preparing-time" json:"max-store-preparing-time"`
+	// StoreSlowStartWindow is the window within which the effective weights of a new
+	// store ramp from 0 to the configured ones, so the balancers won't move a lot of
+	// regions to it at once. 0 means disabling the slow start.
+	StoreSlowStartWindow typeutil.Duration `toml:"store-slow-start-window" json:"store-slow-start-window"`
+	// LeaderTransferBlacklistWindow is the base window to exclude a store as the leader
+	// target after it repeatedly fails to accept the leader transfers. The window grows
+	// exponentially with the consecutive failures. 0 means disabling the exclusion.
+	LeaderTransferBlacklistWindow typeutil.Duration `toml:"leader-transfer-blacklist-window" json:"leader-transfer-blacklist-window"`
+	// MaxLearnerCatchUpTime is the max duration for a newly added learner to receive the
+	// snapshot and catch up with the leader. The operator is canceled once it exceeds, and
+	// the learner will be removed and added to another store. 0 means no limit.
+	MaxLearnerCatchUpTime typeutil.Duration `toml:"max-learner-catch-up-time" json:"max-learner-catch-up-time"`
 	// LeaderScheduleLimit is the max coexist leader schedules.
 	LeaderScheduleLimit uint64 `toml:"leader-schedule-limit" json:"leader-schedule-limit"`
 	// LeaderSchedulePolicy is the option to balance leader, there are some policies supported: ["count", "size"], default: "count"
@@ -196,6 +224,29 @@ type ScheduleConfig struct {
 	MergeScheduleLimit uint64 `toml:"merge-schedule-limit" json:"merge-schedule-limit"`
 	// HotRegionScheduleLimit is the max coexist hot region schedules.
 	HotRegionScheduleLimit uint64 `toml:"hot-region-schedule-limit" json:"hot-region-schedule-limit"`
+	// LabelDomainOperatorLimits limit the coexist region movements in each domain
+	// of the labels, e.g. at most 2 region movements to each zone, so that the
+	// cross-domain bandwidth limits are respected.
+	LabelDomainOperatorLimits []LabelDomainOperatorLimit `toml:"label-domain-operator-limits" json:"label-domain-operator-limits"`
+	// ClusterSnapshotBandwidth is the aggregate bandwidth of the snapshots per
+	// second in the cluster. It is allocated across the stores by lowering the
+	// add peer limits dynamically, 0 means every store uses its own limit.
+	ClusterSnapshotBandwidth typeutil.ByteSize `toml:"cluster-snapshot-bandwidth" json:"cluster-snapshot-bandwidth"`
+	// RegionStatsSampleRatio is the fraction of the region heartbeats which
+	// update the flow histories, and of the patrolled regions which update the
+	// label statistics. All heartbeats still update the region meta, the peer
+	// states and the expired hot peers, so it trades the precision of the
+	// expensive statistics for CPU on very large clusters.
+	RegionStatsSampleRatio float64 `toml:"region-stats-sample-ratio" json:"region-stats-sample-ratio"`
+	// MaxStorePendingCompactionBytes and MaxStoreLevel0FileCount are the limits
+	// of the compaction pressure of a store. The stores exceeding either limit
+	// don't receive the regions or the leaders, 0 means no limit.
+	MaxStorePendingCompactionBytes typeutil.ByteSize `toml:"max-store-pending-compaction-bytes" json:"max-store-pending-compaction-bytes"`
+	MaxStoreLevel0FileCount        uint64            `toml:"max-store-level0-file-count" json:"max-store-level0-file-count"`
+	// MaxEvictingStoreRatio is the max fraction of the stores which are evicting
+	// the leaders or offline. The new evictions of the slow stores are refused
+	// once it's exceeded, to avoid the cascading evictions collapsing the capacity.
+	MaxEvictingStoreRatio float64 `toml:"max-evicting-store-ratio" json:"max-evicting-store-ratio"`
 	// HotRegionCacheHitThreshold is the cache hits threshold of the hot region.
 	// If the number of times a region hits the hot cache is greater than this
 	// threshold, it is considered a hot region.
@@ -290,6 +341,17 @@ type ScheduleConfig struct {
 	// EnableDiagnostic is the option to enable using diagnostic
 	EnableDiagnostic bool `toml:"enable-diagnostic" json:"enable-diagnostic,string"`
 
+	// DecisionLogFile is the file to write the scheduling decisions as JSONL, one
+	// decision per line. The decision log is disabled if it's empty. It can only
+	// be set in the config file and takes effect after restart.
+	DecisionLogFile string `toml:"decision-log-file" json:"decision-log-file"`
+	// DecisionLogMaxSize is the max size in MB of the decision log file before
+	// it's rotated.
+	DecisionLogMaxSize int `toml:"decision-log-max-size" json:"decision-log-max-size"`
+	// DecisionLogMaxBackups is the max number of the rotated decision log files
+	// to retain.
+	DecisionLogMaxBackups int `toml:"decision-log-max-backups" json:"decision-log-max-backups"`
+
 	// EnableWitness is the option to enable using witness
 	EnableWitness bool `toml:"enable-witness" json:"enable-witness,string"`
 
@@ -307,6 +369,112 @@ type ScheduleConfig struct {
 	HaltScheduling bool `toml:"halt-scheduling" json:"halt-scheduling,string,omitempty"`
 }
 
+// The subsystems affected by the config items.
+const (
+	SubsystemMergeChecker   = "merge-checker"
+	SubsystemReplicaChecker = "replica-checker"
+	SubsystemRuleChecker    = "rule-checker"
+	SubsystemSplit          = "split"
+	SubsystemOperator       = "operator"
+	SubsystemOperatorLimit  = "operator-limit"
+	SubsystemStoreLimit     = "store-limit"
+	SubsystemStoreState     = "store-state"
+	SubsystemHotScheduler   = "hot-region-scheduler"
+	SubsystemBalance        = "balance-scheduler"
+	SubsystemEvictScheduler = "evict-scheduler"
+	SubsystemSchedulers     = "schedulers"
+	SubsystemScheduling     = "scheduling"
+	SubsystemPatrol         = "region-patrol"
+	SubsystemWitness        = "witness"
+	SubsystemHeartbeat      = "heartbeat"
+	SubsystemStatistics     = "statistics"
+	SubsystemDiagnostic     = "diagnostic"
+)
+
+// ItemImpact is the impact of changing a config item.
+type ItemImpact struct {
+	// Subsystem is the subsystem affected by the item.
+	Subsystem string
+	// NeedReschedule means changing the item may reschedule the regions.
+	NeedReschedule bool
+}
+
+// scheduleItemImpacts maps the json keys of ScheduleConfig to the impacts of
+// changing them. A new item of ScheduleConfig must be added here as well.
+var scheduleItemImpacts = map[string]ItemImpact{
+	"max-snapshot-count":                                 {SubsystemStoreLimit, false},
+	"max-pending-peer-count":                             {SubsystemStoreLimit, false},
+	"max-merge-region-size":                              {SubsystemMergeChecker, true},
+	"max-merge-region-keys":                              {SubsystemMergeChecker, true},
+	"split-merge-interval":                               {SubsystemMergeChecker, true},
+	"switch-witness-interval":                            {SubsystemWitness, false},
+	"enable-one-way-merge":                               {SubsystemMergeChecker, true},
+	"enable-cross-table-merge":                           {SubsystemMergeChecker, true},
+	"patrol-region-interval":                             {SubsystemPatrol, false},
+	"patrol-region-concurrency":                          {SubsystemPatrol, false},
+	"max-store-down-time":                                {SubsystemReplicaChecker, true},
+	"max-store-preparing-time":                           {SubsystemStoreState, false},
+	"store-slow-start-window":                            {SubsystemBalance, false},
+	"leader-transfer-blacklist-window":                   {SubsystemBalance, false},
+	"max-learner-catch-up-time":                          {SubsystemOperator, false},
+	"leader-schedule-limit":                              {SubsystemOperatorLimit, false},
+	"leader-schedule-policy":                             {SubsystemBalance, true},
+	"region-schedule-limit":                              {SubsystemOperatorLimit, false},
+	"witness-schedule-limit":                             {SubsystemOperatorLimit, false},
+	"replica-schedule-limit":                             {SubsystemOperatorLimit, false},
+	"merge-schedule-limit":                               {SubsystemOperatorLimit, false},
+	"hot-region-schedule-limit":                          {SubsystemOperatorLimit, false},
+	"label-domain-operator-limits":                       {SubsystemOperatorLimit, false},
+	"cluster-snapshot-bandwidth":                         {SubsystemStoreLimit, false},
+	"region-stats-sample-ratio":                          {SubsystemStatistics, false},
+	"max-store-pending-compaction-bytes":                 {SubsystemBalance, false},
+	"max-store-level0-file-count":                        {SubsystemBalance, false},
+	"max-evicting-store-ratio":                           {SubsystemEvictScheduler, false},
+	"hot-region-cache-hits-threshold":                    {SubsystemHotScheduler, true},
+	"store-balance-rate":                                 {SubsystemStoreLimit, false},
+	"store-limit":                                        {SubsystemStoreLimit, false},
+	"tolerant-size-ratio":                                {SubsystemBalance, true},
+	"low-space-ratio":                                    {SubsystemBalance, true},
+	"high-space-ratio":                                   {SubsystemBalance, true},
+	"region-score-formula-version":                       {SubsystemBalance, true},
+	"scheduler-max-waiting-operator":                     {SubsystemOperatorLimit, false},
+	"disable-raft-learner":                               {SubsystemOperator, false},
+	"disable-remove-down-replica":                        {SubsystemReplicaChecker, false},
+	"disable-replace-offline-replica":                    {SubsystemReplicaChecker, false},
+	"disable-make-up-replica":                            {SubsystemReplicaChecker, false},
+	"disable-remove-extra-replica":                       {SubsystemReplicaChecker, false},
+	"disable-location-replacement":                       {SubsystemReplicaChecker, false},
+	"enable-remove-down-replica":                         {SubsystemReplicaChecker, false},
+	"enable-replace-offline-replica":                     {SubsystemReplicaChecker, false},
+	"enable-make-up-replica":                             {SubsystemReplicaChecker, false},
+	"enable-remove-extra-replica":                        {SubsystemReplicaChecker, false},
+	"enable-location-replacement":                        {SubsystemReplicaChecker, false},
+	"enable-debug-metrics":                               {SubsystemDiagnostic, false},
+	"enable-joint-consensus":                             {SubsystemOperator, false},
+	"enable-tikv-split-region":                           {SubsystemSplit, false},
+	"enable-heartbeat-breakdown-metrics":                 {SubsystemDiagnostic, false},
+	"enable-heartbeat-concurrent-runner":                 {SubsystemHeartbeat, false},
+	"schedulers-v2":                                      {SubsystemSchedulers, true},
+	"schedulers-payload":                                 {SubsystemSchedulers, true},
+	"hot-regions-write-interval":                         {SubsystemStatistics, false},
+	"hot-regions-reserved-days":                          {SubsystemStatistics, false},
+	"max-movable-hot-peer-size":                          {SubsystemHotScheduler, true},
+	"enable-diagnostic":                                  {SubsystemDiagnostic, false},
+	"decision-log-file":                                  {SubsystemDiagnostic, false},
+	"decision-log-max-size":                              {SubsystemDiagnostic, false},
+	"decision-log-max-backups":                           {SubsystemDiagnostic, false},
+	"enable-witness":                                     {SubsystemWitness, true},
+	"slow-store-evicting-affected-store-ratio-threshold": {SubsystemEvictScheduler, false},
+	"store-limit-version":                                {SubsystemStoreLimit, false},
+	"halt-scheduling":                                    {SubsystemScheduling, false},
+}
+
+// GetScheduleItemImpact returns the impact of changing the schedule config item.
+func GetScheduleItemImpact(item string) (ItemImpact, bool) {
+	impact, ok := scheduleItemImpacts[item]
+	return impact, ok
+}
+
 // Clone returns a cloned scheduling configuration.
 func (c *ScheduleConfig) Clone() *ScheduleConfig {
 	schedulers := append(c.Schedulers[:0:0], c.Schedulers...)
@@ -319,6 +487,7 @@ func (c *ScheduleConfig) Clone() *ScheduleConfig {
 	}
 	cfg := *c
 	cfg.StoreLimit = storeLimit
+	cfg.LabelDomainOperatorLimits = append(c.LabelDomainOperatorLimits[:0:0], c.LabelDomainOperatorLimits...)
 	cfg.Schedulers = schedulers
 	cfg.SchedulersPayload = nil
 	return &cfg
@@ -338,9 +507,13 @@ func (c *ScheduleConfig) Adjust(meta *configutil.ConfigMetaData, reloading bool)
 	configutil.AdjustDuration(&c.SplitMergeInterval, DefaultSplitMergeInterval)
 	configutil.AdjustDuration(&c.SwitchWitnessInterval, defaultSwitchWitnessInterval)
 	configutil.AdjustDuration(&c.PatrolRegionInterval, defaultPatrolRegionInterval)
+	configutil.AdjustInt(&c.PatrolRegionConcurrency, defaultPatrolRegionConcurrency)
 	configutil.AdjustDuration(&c.MaxStoreDownTime, defaultMaxStoreDownTime)
 	configutil.AdjustDuration(&c.HotRegionsWriteInterval, defaultHotRegionsWriteInterval)
 	configutil.AdjustDuration(&c.MaxStorePreparingTime, defaultMaxStorePreparingTime)
+	if !meta.IsDefined("leader-transfer-blacklist-window") {
+		configutil.AdjustDuration(&c.LeaderTransferBlacklistWindow, defaultLeaderTransferBlacklistWindow)
+	}
 	if !meta.IsDefined("leader-schedule-limit") {
 		configutil.AdjustUint64(&c.LeaderScheduleLimit, defaultLeaderScheduleLimit)
 	}
@@ -395,9 +568,13 @@ func (c *ScheduleConfig) Adjust(meta *configutil.ConfigMetaData, reloading bool)
 	}
 	configutil.AdjustFloat64(&c.LowSpaceRatio, defaultLowSpaceRatio)
 	configutil.AdjustFloat64(&c.HighSpaceRatio, defaultHighSpaceRatio)
+	configutil.AdjustFloat64(&c.RegionStatsSampleRatio, defaultRegionStatsSampleRatio)
+	configutil.AdjustFloat64(&c.MaxEvictingStoreRatio, defaultMaxEvictingStoreRatio)
 	if !meta.IsDefined("enable-diagnostic") {
 		c.EnableDiagnostic = defaultEnableDiagnostic
 	}
+	configutil.AdjustInt(&c.DecisionLogMaxSize, defaultDecisionLogMaxSize)
+	configutil.AdjustInt(&c.DecisionLogMaxBackups, defaultDecisionLogMaxBackups)
 
 	if !meta.IsDefined("enable-witness") {
 		c.EnableWitness = defaultEnableWitness
@@ -512,12 +689,31 @@ func (c *ScheduleConfig) Validate() error {
 	if c.LowSpaceRatio <= c.HighSpaceRatio {
 		return errors.New("low-space-ratio should be larger than high-space-ratio")
 	}
+	if c.RegionStatsSampleRatio <= 0 || c.RegionStatsSampleRatio > 1 {
+		return errors.New("region-stats-sample-ratio should be larger than 0 and not larger than 1")
+	}
+	if c.MaxEvictingStoreRatio <= 0 || c.MaxEvictingStoreRatio > 1 {
+		return errors.New("max-evicting-store-ratio should be larger than 0 and not larger than 1")
+	}
 	if c.LeaderSchedulePolicy != "count" && c.LeaderSchedulePolicy != "size" {
 		return errors.Errorf("leader-schedule-policy %v is invalid", c.LeaderSchedulePolicy)
 	}
 	if c.SlowStoreEvictingAffectedStoreRatioThreshold == 0 {
 		return errors.Errorf("slow-store-evicting-affected-store-ratio-threshold is not set")
 	}
+	if c.PatrolRegionConcurrency < 1 || c.PatrolRegionConcurrency > maxPatrolRegionConcurrency {
+		return errors.Errorf("patrol-region-concurrency should be between 1 and %d", maxPatrolRegionConcurrency)
+	}
+	keys := make(map[string]struct{}, len(c.LabelDomainOperatorLimits))
+	for _, l := range c.LabelDomainOperatorLimits {
+		if l.Key == "" || l.Limit == 0 {
+			return errors.Errorf("label-domain-operator-limits should have a non-empty key and a positive limit")
+		}
+		if _, ok := keys[l.Key]; ok {
+			return errors.Errorf("label-domain-operator-limits has duplicated key %s", l.Key)
+		}
+		keys[l.Key] = struct{}{}
+	}
 	return nil
 }
 
@@ -553,6 +749,28 @@ type StoreLimitConfig struct {
 	RemovePeer float64 `toml:"remove-peer" json:"remove-peer"`
 }
 
+// LabelDomainOperatorLimit limits the coexist region movements in each domain of a label,
+// the stores with the same value of the label key belong to the same domain.
+type LabelDomainOperatorLimit struct {
+	Key   string `toml:"key" json:"key"`
+	Limit uint64 `toml:"limit" json:"limit"`
+}
+
+// The actions on the stores violating the label taxonomy.
+const (
+	LabelTaxonomyReject     = "reject"
+	LabelTaxonomyQuarantine = "quarantine"
+)
+
+// StoreLabelConstraint constrains a label key of the stores.
+type StoreLabelConstraint struct {
+	Key string `toml:"key" json:"key"`
+	// Required makes the stores without the label invalid.
+	Required bool `toml:"required" json:"required"`
+	// Values are the allowed values of the label, any value is allowed if it's empty.
+	Values []string `toml:"values" json:"values,omitempty"`
+}
+
 // SchedulerConfigs is a slice of customized scheduler configuration.
 type SchedulerConfigs []SchedulerConfig
 
@@ -595,8 +813,24 @@ type ReplicationConfig struct {
 	// For example, ["zone", "rack"] means that we should place replicas to
 	// different zones first, then to different racks if we don't have enough zones.
 	LocationLabels typeutil.StringSlice `toml:"location-labels" json:"location-labels"`
+	// LocationLabelWeights are the weights of being distinct at each level of
+	// LocationLabels when choosing the stores for the replicas and scoring the
+	// isolation of the placement rules. They are cumulative down the hierarchy
+	// since being distinct at a level implies being distinct at the lower levels.
+	// For example, [100, 1] for ["zone", "rack"] scores 101 for a replica in a
+	// different zone and 1 for a replica in a different rack of the same zone.
+	// It's empty by default, which makes the higher level always outweigh the
+	// lower levels.
+	LocationLabelWeights []float64 `toml:"location-label-weights" json:"location-label-weights"`
 	// StrictlyMatchLabel strictly checks if the label of TiKV is matched with LocationLabels.
 	StrictlyMatchLabel bool `toml:"strictly-match-label" json:"strictly-match-label,string"`
+	// LabelTaxonomy constrains the keys and values of the store labels, the keys
+	// not in it are not constrained. The store registrations and label updates
+	// which violate it are handled by LabelTaxonomyAction.
+	LabelTaxonomy []StoreLabelConstraint `toml:"label-taxonomy" json:"label-taxonomy"`
+	// LabelTaxonomyAction is "reject" to refuse the stores violating LabelTaxonomy,
+	// or "quarantine" to accept them but not to schedule any region or leader to them.
+	LabelTaxonomyAction string `toml:"label-taxonomy-action" json:"label-taxonomy-action"`
 
 	// When PlacementRules feature is enabled. MaxReplicas, LocationLabels and IsolationLabels are not used any more.
 	EnablePlacementRules bool `toml:"enable-placement-rules" json:"enable-placement-rules,string"`
@@ -613,6 +847,17 @@ type ReplicationConfig struct {
 	// Even if a zone is down, PD will not try to make up replicas in other zone
 	// because other zones already have replicas on it.
 	IsolationLevel string `toml:"isolation-level" json:"isolation-level"`
+
+	// EnableDegradedPlacement enables relaxing the placement rules temporarily
+	// when a zone is entirely down for DegradedZoneDownTime, so that the replicas
+	// can be placed in the remaining zones. The original rules are restored once
+	// the zone recovers.
+	EnableDegradedPlacement bool `toml:"enable-degraded-placement" json:"enable-degraded-placement,string"`
+	// DegradedZoneLabel is the label key of the zones for the degraded placement.
+	DegradedZoneLabel string `toml:"degraded-zone-label" json:"degraded-zone-label"`
+	// DegradedZoneDownTime is the time after which an entirely down zone turns
+	// the placement into the degraded mode.
+	DegradedZoneDownTime typeutil.Duration `toml:"degraded-zone-down-time" json:"degraded-zone-down-time"`
 }
 
 // Clone makes a deep copy of the config.
@@ -620,6 +865,11 @@ func (c *ReplicationConfig) Clone() *ReplicationConfig {
 	locationLabels := append(c.LocationLabels[:0:0], c.LocationLabels...)
 	cfg := *c
 	cfg.LocationLabels = locationLabels
+	cfg.LocationLabelWeights = append(c.LocationLabelWeights[:0:0], c.LocationLabelWeights...)
+	cfg.LabelTaxonomy = append(c.LabelTaxonomy[:0:0], c.LabelTaxonomy...)
+	for i := range cfg.LabelTaxonomy {
+		cfg.LabelTaxonomy[i].Values = append(c.LabelTaxonomy[i].Values[:0:0], c.LabelTaxonomy[i].Values...)
+	}
 	return &cfg
 }
 
@@ -639,6 +889,35 @@ func (c *ReplicationConfig) Validate() error {
 	if c.IsolationLevel != "" && !foundIsolationLevel {
 		return errors.New("isolation-level must be one of location-labels or empty")
 	}
+	if len(c.LocationLabelWeights) > 0 && len(c.LocationLabelWeights) != len(c.LocationLabels) {
+		return errors.New("location-label-weights must be empty or have the same length as location-labels")
+	}
+	for _, weight := range c.LocationLabelWeights {
+		if weight <= 0 {
+			return errors.New("location-label-weights must be positive")
+		}
+	}
+	keys := make(map[string]struct{}, len(c.LabelTaxonomy))
+	for _, constraint := range c.LabelTaxonomy {
+		if err := ValidateLabelKey(constraint.Key); err != nil {
+			return err
+		}
+		key := strings.ToLower(constraint.Key)
+		if _, ok := keys[key]; ok {
+			return errors.Errorf("duplicated label-taxonomy key %s", constraint.Key)
+		}
+		keys[key] = struct{}{}
+		for _, value := range constraint.Values {
+			if err := ValidateLabels([]*metapb.StoreLabel{{Key: constraint.Key, Value: value}}); err != nil {
+				return err
+			}
+		}
+	}
+	switch c.LabelTaxonomyAction {
+	case "", LabelTaxonomyReject, LabelTaxonomyQuarantine:
+	default:
+		return errors.Errorf("label-taxonomy-action must be %s or %s", LabelTaxonomyReject, LabelTaxonomyQuarantine)
+	}
 	return nil
 }
 
@@ -654,5 +933,34 @@ func (c *ReplicationConfig) Adjust(meta *configutil.ConfigMetaData) error {
 	if !meta.IsDefined("location-labels") {
 		c.LocationLabels = defaultLocationLabels
 	}
+	configutil.AdjustString(&c.LabelTaxonomyAction, LabelTaxonomyReject)
+	configutil.AdjustString(&c.DegradedZoneLabel, defaultDegradedZoneLabel)
+	configutil.AdjustDuration(&c.DegradedZoneDownTime, defaultDegradedZoneDownTime)
 	return c.Validate()
 }
+
+// LocationWeightsOf returns the weights of the given location labels, which may
+// be the location labels of a placement rule, by matching them with the keys of
+// the configured location labels. It returns nil, which means the default
+// weights, if any of them has no configured weight.
+func LocationWeightsOf(conf SharedConfigProvider, labels []string) []float64 {
+	keys, weights := conf.GetLocationLabels(), conf.GetLocationLabelWeights()
+	if len(labels) == 0 || len(weights) != len(keys) {
+		return nil
+	}
+	result := make([]float64, 0, len(labels))
+	for _, label := range labels {
+		found := false
+		for i, key := range keys {
+			if key == label {
+				result = append(result, weights[i])
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return result
+}
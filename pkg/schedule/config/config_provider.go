@@ -68,6 +68,9 @@ type SchedulerConfigProvider interface {
 
 	IsDebugMetricsEnabled() bool
 	IsDiagnosticAllowed() bool
+	GetDecisionLogFile() string
+	GetDecisionLogMaxSize() int
+	GetDecisionLogMaxBackups() int
 	GetSlowStoreEvictingAffectedStoreRatioThreshold() float64
 
 	GetScheduleConfig() *ScheduleConfig
@@ -88,6 +91,7 @@ type CheckerConfigProvider interface {
 	GetIsolationLevel() string
 	GetSplitMergeInterval() time.Duration
 	GetPatrolRegionInterval() time.Duration
+	GetPatrolRegionConcurrency() int
 	GetMaxMergeRegionSize() uint64
 	GetMaxMergeRegionKeys() uint64
 	GetReplicaScheduleLimit() uint64
@@ -102,7 +106,12 @@ type SharedConfigProvider interface {
 	GetLowSpaceRatio() float64
 	GetHighSpaceRatio() float64
 	GetMaxStoreDownTime() time.Duration
+	GetLeaderTransferBlacklistWindow() time.Duration
+	GetMaxLearnerCatchUpTime() time.Duration
 	GetLocationLabels() []string
+	GetLocationLabelWeights() []float64
+	GetLabelTaxonomy() []StoreLabelConstraint
+	GetLabelTaxonomyAction() string
 	CheckLabelProperty(string, []*metapb.StoreLabel) bool
 	GetClusterVersion() *semver.Version
 	IsUseJointConsensus() bool
@@ -112,6 +121,12 @@ type SharedConfigProvider interface {
 	GetMergeScheduleLimit() uint64
 	GetRegionScoreFormulaVersion() string
 	GetSchedulerMaxWaitingOperator() uint64
+	GetLabelDomainOperatorLimits() []LabelDomainOperatorLimit
+	GetClusterSnapshotBandwidth() uint64
+	GetRegionStatsSampleRatio() float64
+	GetMaxStorePendingCompactionBytes() uint64
+	GetMaxStoreLevel0FileCount() uint64
+	GetMaxEvictingStoreRatio() float64
 	GetStoreLimitByType(uint64, storelimit.Type) float64
 	IsWitnessAllowed() bool
 	IsPlacementRulesCacheEnabled() bool
@@ -0,0 +1,41 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleItemImpacts(t *testing.T) {
+	re := require.New(t)
+	typ := reflect.TypeOf(ScheduleConfig{})
+	items := make(map[string]struct{}, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		item := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if item == "" || item == "-" {
+			continue
+		}
+		items[item] = struct{}{}
+		_, ok := GetScheduleItemImpact(item)
+		re.True(ok, "the impact of %s is not defined", item)
+	}
+	for item := range scheduleItemImpacts {
+		re.Contains(items, item)
+	}
+}
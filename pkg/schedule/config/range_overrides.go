@@ -0,0 +1,54 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "github.com/tikv/pd/pkg/core/constant"
+
+// RangeOverrides is the schedule parameters overridden for a specific key range.
+// A nil field means the parameter is not overridden.
+type RangeOverrides struct {
+	MergeDisabled        bool
+	MaxPendingPeerCount  *uint64
+	LeaderSchedulePolicy *constant.SchedulePolicy
+}
+
+// GetLeaderSchedulePolicy returns the overridden leader schedule policy, or the given
+// policy if it's not overridden.
+func (o *RangeOverrides) GetLeaderSchedulePolicy(policy constant.SchedulePolicy) constant.SchedulePolicy {
+	if o != nil && o.LeaderSchedulePolicy != nil {
+		return *o.LeaderSchedulePolicy
+	}
+	return policy
+}
+
+// rangeOverriddenConfig is the shared config with the range overrides applied.
+type rangeOverriddenConfig struct {
+	SharedConfigProvider
+	maxPendingPeerCount uint64
+}
+
+// WithRangeOverrides returns the shared config with the range overrides applied.
+// The config is returned as it is if no config item is overridden.
+func WithRangeOverrides(conf SharedConfigProvider, overrides *RangeOverrides) SharedConfigProvider {
+	if overrides == nil || overrides.MaxPendingPeerCount == nil {
+		return conf
+	}
+	return &rangeOverriddenConfig{SharedConfigProvider: conf, maxPendingPeerCount: *overrides.MaxPendingPeerCount}
+}
+
+// GetMaxPendingPeerCount returns the number of the max pending peers.
+func (c *rangeOverriddenConfig) GetMaxPendingPeerCount() uint64 {
+	return c.maxPendingPeerCount
+}
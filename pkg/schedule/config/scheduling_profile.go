@@ -0,0 +1,141 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"slices"
+	"sort"
+
+	types "github.com/tikv/pd/pkg/schedule/type"
+)
+
+// The names of the built-in scheduling profiles.
+const (
+	SchedulingProfileBalanced            = "balanced"
+	SchedulingProfileAggressiveRebalance = "aggressive-rebalance"
+	SchedulingProfileImportFriendly      = "import-friendly"
+	SchedulingProfileMaintenance         = "maintenance"
+)
+
+// profileSchedulers are the schedulers managed by the scheduling profiles,
+// the other schedulers are left untouched when switching the profile.
+var profileSchedulers = []types.CheckerSchedulerType{
+	types.BalanceLeaderScheduler,
+	types.BalanceRegionScheduler,
+	types.HotRegionScheduler,
+}
+
+// SchedulingProfile is a preset bundling the scheduler enablement and the schedule limits.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type SchedulingProfile struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	LeaderScheduleLimit    uint64 `json:"leader-schedule-limit"`
+	RegionScheduleLimit    uint64 `json:"region-schedule-limit"`
+	ReplicaScheduleLimit   uint64 `json:"replica-schedule-limit"`
+	MergeScheduleLimit     uint64 `json:"merge-schedule-limit"`
+	HotRegionScheduleLimit uint64 `json:"hot-region-schedule-limit"`
+	// EnabledSchedulers are the managed schedulers running under the profile,
+	// the other managed schedulers are removed.
+	EnabledSchedulers []types.CheckerSchedulerType `json:"enabled-schedulers"`
+}
+
+var schedulingProfiles = map[string]*SchedulingProfile{
+	SchedulingProfileBalanced: {
+		Name:                   SchedulingProfileBalanced,
+		Description:            "the default scheduling behavior",
+		LeaderScheduleLimit:    defaultLeaderScheduleLimit,
+		RegionScheduleLimit:    defaultRegionScheduleLimit,
+		ReplicaScheduleLimit:   defaultReplicaScheduleLimit,
+		MergeScheduleLimit:     defaultMergeScheduleLimit,
+		HotRegionScheduleLimit: defaultHotRegionScheduleLimit,
+		EnabledSchedulers:      profileSchedulers,
+	},
+	SchedulingProfileAggressiveRebalance: {
+		Name:                   SchedulingProfileAggressiveRebalance,
+		Description:            "balance the leaders and regions as fast as possible, e.g. after scaling out",
+		LeaderScheduleLimit:    4 * defaultLeaderScheduleLimit,
+		RegionScheduleLimit:    2 * defaultRegionScheduleLimit,
+		ReplicaScheduleLimit:   defaultReplicaScheduleLimit,
+		MergeScheduleLimit:     2 * defaultMergeScheduleLimit,
+		HotRegionScheduleLimit: 2 * defaultHotRegionScheduleLimit,
+		EnabledSchedulers:      profileSchedulers,
+	},
+	SchedulingProfileImportFriendly: {
+		Name:                   SchedulingProfileImportFriendly,
+		Description:            "stop moving and merging the regions while bulk importing data",
+		LeaderScheduleLimit:    defaultLeaderScheduleLimit,
+		RegionScheduleLimit:    defaultRegionScheduleLimit,
+		ReplicaScheduleLimit:   defaultReplicaScheduleLimit,
+		MergeScheduleLimit:     0,
+		HotRegionScheduleLimit: defaultHotRegionScheduleLimit,
+		EnabledSchedulers:      []types.CheckerSchedulerType{types.BalanceLeaderScheduler},
+	},
+	SchedulingProfileMaintenance: {
+		Name:                   SchedulingProfileMaintenance,
+		Description:            "only repair the replicas, e.g. during the cluster maintenance",
+		LeaderScheduleLimit:    defaultLeaderScheduleLimit,
+		RegionScheduleLimit:    defaultRegionScheduleLimit,
+		ReplicaScheduleLimit:   defaultReplicaScheduleLimit,
+		MergeScheduleLimit:     0,
+		HotRegionScheduleLimit: 0,
+		EnabledSchedulers:      []types.CheckerSchedulerType{},
+	},
+}
+
+// GetSchedulingProfile returns the built-in scheduling profile with the given name.
+func GetSchedulingProfile(name string) (*SchedulingProfile, bool) {
+	profile, ok := schedulingProfiles[name]
+	return profile, ok
+}
+
+// GetSchedulingProfiles returns all the built-in scheduling profiles ordered by the name.
+func GetSchedulingProfiles() []*SchedulingProfile {
+	profiles := make([]*SchedulingProfile, 0, len(schedulingProfiles))
+	for _, profile := range schedulingProfiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles
+}
+
+// GetProfileSchedulers returns the schedulers managed by the scheduling profiles.
+func GetProfileSchedulers() []types.CheckerSchedulerType {
+	return profileSchedulers
+}
+
+// IsSchedulerEnabled returns whether the managed scheduler runs under the profile.
+func (p *SchedulingProfile) IsSchedulerEnabled(tp types.CheckerSchedulerType) bool {
+	return slices.Contains(p.EnabledSchedulers, tp)
+}
+
+// Apply sets the schedule limits of the profile to the config.
+func (p *SchedulingProfile) Apply(cfg *ScheduleConfig) {
+	cfg.LeaderScheduleLimit = p.LeaderScheduleLimit
+	cfg.RegionScheduleLimit = p.RegionScheduleLimit
+	cfg.ReplicaScheduleLimit = p.ReplicaScheduleLimit
+	cfg.MergeScheduleLimit = p.MergeScheduleLimit
+	cfg.HotRegionScheduleLimit = p.HotRegionScheduleLimit
+}
+
+// Matches returns whether the schedule limits of the config are still the ones of the profile.
+func (p *SchedulingProfile) Matches(cfg *ScheduleConfig) bool {
+	return cfg.LeaderScheduleLimit == p.LeaderScheduleLimit &&
+		cfg.RegionScheduleLimit == p.RegionScheduleLimit &&
+		cfg.ReplicaScheduleLimit == p.ReplicaScheduleLimit &&
+		cfg.MergeScheduleLimit == p.MergeScheduleLimit &&
+		cfg.HotRegionScheduleLimit == p.HotRegionScheduleLimit
+}
@@ -16,9 +16,11 @@ package config
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/slice"
 )
 
 const (
@@ -51,6 +53,32 @@ func ValidateLabels(labels []*metapb.StoreLabel) error {
 	return nil
 }
 
+// CheckLabelTaxonomy checks whether the store labels satisfy the taxonomy.
+func CheckLabelTaxonomy(taxonomy []StoreLabelConstraint, labels []*metapb.StoreLabel) error {
+	for _, constraint := range taxonomy {
+		var (
+			value string
+			found bool
+		)
+		for _, label := range labels {
+			if strings.EqualFold(label.GetKey(), constraint.Key) {
+				value, found = label.GetValue(), true
+				break
+			}
+		}
+		if !found {
+			if constraint.Required {
+				return errors.Errorf("the label %s is required", constraint.Key)
+			}
+			continue
+		}
+		if len(constraint.Values) > 0 && !slice.Contains(constraint.Values, value) {
+			return errors.Errorf("the value %s of the label %s is not allowed, the allowed values are %v", value, constraint.Key, constraint.Values)
+		}
+	}
+	return nil
+}
+
 // ValidateLabelKey checks the legality of the label key.
 func ValidateLabelKey(key string) error {
 	return validateFormat(key, keyFormat)
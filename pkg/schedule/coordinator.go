@@ -49,6 +49,8 @@ const (
 	maxLoadConfigRetries       = 10
 	// pushOperatorTickInterval is the interval try to push the operator.
 	pushOperatorTickInterval = 500 * time.Millisecond
+	// snapshotBudgetInterval is the interval to allocate the cluster snapshot bandwidth.
+	snapshotBudgetInterval = 10 * time.Second
 
 	// It takes about 1.3 minutes(1000000/128*10/60/1000) to iterate 1 million regions(with DefaultPatrolRegionInterval=10ms).
 	patrolScanRegionLimit = 128
@@ -62,6 +64,7 @@ var (
 	// WithLabelValues is a heavy operation, define variable to avoid call it every time.
 	pendingProcessedRegionsGauge = regionListGauge.WithLabelValues("pending_processed_regions")
 	priorityListGauge            = regionListGauge.WithLabelValues("priority_list")
+	repairListGauge              = regionListGauge.WithLabelValues("repair_list")
 )
 
 // Coordinator is used to manage all schedulers and checkers to decide if the region needs to be scheduled.
@@ -74,6 +77,10 @@ type Coordinator struct {
 
 	schedulersInitialized bool
 	patrolRegionsDuration time.Duration
+	patrol                *regionPatrol
+	// addOperatorMu serializes the schedule limit check and the operator
+	// addition of the checkers, since the partitions are patrolled concurrently.
+	addOperatorMu syncutil.Mutex
 
 	cluster           sche.ClusterInformer
 	prepareChecker    *prepareChecker
@@ -97,6 +104,7 @@ func NewCoordinator(parentCtx context.Context, cluster sche.ClusterInformer, hbS
 		ctx:                   ctx,
 		cancel:                cancel,
 		schedulersInitialized: false,
+		patrol:                &regionPatrol{},
 		cluster:               cluster,
 		prepareChecker:        newPrepareChecker(),
 		checkers:              checkers,
@@ -126,6 +134,11 @@ func (c *Coordinator) setPatrolRegionsDuration(dur time.Duration) {
 	c.patrolRegionsDuration = dur
 }
 
+// GetPatrolRegionsProgress returns the progress of the current patrol round.
+func (c *Coordinator) GetPatrolRegionsProgress() *PatrolRegionsProgress {
+	return c.patrol.progress(time.Now(), c.GetPatrolRegionsDuration())
+}
+
 // markSchedulersInitialized marks the scheduler initialization is finished.
 func (c *Coordinator) markSchedulersInitialized() {
 	c.Lock()
@@ -156,11 +169,6 @@ func (c *Coordinator) PatrolRegions() {
 	defer ticker.Stop()
 
 	log.Info("coordinator starts patrol regions")
-	start := time.Now()
-	var (
-		key     []byte
-		regions []*core.RegionInfo
-	)
 	for {
 		select {
 		case <-ticker.C:
@@ -168,6 +176,7 @@ func (c *Coordinator) PatrolRegions() {
 			ticker.Reset(c.cluster.GetCheckerConfig().GetPatrolRegionInterval())
 		case <-c.ctx.Done():
 			patrolCheckRegionsGauge.Set(0)
+			patrolRegionsLagGauge.Set(0)
 			c.setPatrolRegionsDuration(0)
 			log.Info("patrol regions has been stopped")
 			return
@@ -176,22 +185,26 @@ func (c *Coordinator) PatrolRegions() {
 			continue
 		}
 
+		// Repair the most urgent under-replicated regions first.
+		c.checkRepairRegions()
 		// Check priority regions first.
 		c.checkPriorityRegions()
 		// Check pending processed regions first.
 		c.checkPendingProcessedRegions()
 
-		key, regions = c.checkRegions(key)
-		if len(regions) == 0 {
-			continue
+		if c.patrol.isRoundDone() {
+			c.startPatrolRound()
 		}
-		// Updates the label level isolation statistics.
-		c.cluster.UpdateRegionsLabelLevelStats(regions)
-		if len(key) == 0 {
-			dur := time.Since(start)
+		regions := c.checkRegions()
+		patrolRegionsLagGauge.Set(c.patrol.lag(time.Now()).Seconds())
+		if len(regions) > 0 {
+			// Updates the label level isolation statistics.
+			c.cluster.UpdateRegionsLabelLevelStats(regions)
+		}
+		if c.patrol.isRoundDone() && c.patrol.checkedRegions() > 0 {
+			dur := c.patrol.finishRound(time.Now())
 			patrolCheckRegionsGauge.Set(dur.Seconds())
 			c.setPatrolRegionsDuration(dur)
-			start = time.Now()
 		}
 		failpoint.Inject("break-patrol", func() {
 			failpoint.Break()
@@ -199,19 +212,49 @@ func (c *Coordinator) PatrolRegions() {
 	}
 }
 
-func (c *Coordinator) checkRegions(startKey []byte) (key []byte, regions []*core.RegionInfo) {
-	regions = c.cluster.ScanRegions(startKey, nil, patrolScanRegionLimit)
-	if len(regions) == 0 {
-		// Resets the scan key.
-		key = nil
-		return
+// startPatrolRound splits the key space into the partitions holding about the
+// same number of regions according to the patrol concurrency.
+func (c *Coordinator) startPatrolRound() {
+	var keys [][]byte
+	if n := c.cluster.GetCheckerConfig().GetPatrolRegionConcurrency(); n > 1 {
+		keys = c.cluster.GetBasicCluster().GetPartitionKeys(n)
+	}
+	c.patrol.startRound(keys, time.Now())
+}
+
+// checkRegions checks a batch of regions of each pending partition, the
+// partitions are checked concurrently if there are more than one.
+func (c *Coordinator) checkRegions() []*core.RegionInfo {
+	partitions := c.patrol.pendingPartitions()
+	if len(partitions) == 1 {
+		return c.checkPartition(partitions[0])
 	}
+	results := make([][]*core.RegionInfo, len(partitions))
+	var wg sync.WaitGroup
+	for i, partition := range partitions {
+		wg.Add(1)
+		go func(i int, partition *patrolPartition) {
+			defer logutil.LogPanic()
+			defer wg.Done()
+			results[i] = c.checkPartition(partition)
+		}(i, partition)
+	}
+	wg.Wait()
+	var regions []*core.RegionInfo
+	for _, result := range results {
+		regions = append(regions, result...)
+	}
+	return regions
+}
 
+func (c *Coordinator) checkPartition(partition *patrolPartition) []*core.RegionInfo {
+	regions := c.cluster.ScanRegions(partition.nextKey, partition.endKey, patrolScanRegionLimit)
 	for _, region := range regions {
 		c.tryAddOperators(region)
-		key = region.GetEndKey()
 	}
-	return
+	// The partition is exhausted if the regions are less than the limit.
+	c.patrol.advance(partition, regions, len(regions) < patrolScanRegionLimit)
+	return regions
 }
 
 func (c *Coordinator) checkPendingProcessedRegions() {
@@ -223,6 +266,24 @@ func (c *Coordinator) checkPendingProcessedRegions() {
 	}
 }
 
+// checkRepairRegions repairs the under-replicated regions in the order of the
+// urgency planned by the repair planner, within the replica schedule limit.
+func (c *Coordinator) checkRepairRegions() {
+	planner := c.checkers.GetRepairPlanner()
+	budget := int(c.cluster.GetCheckerConfig().GetReplicaScheduleLimit()) - int(c.opController.OperatorCount(operator.OpReplica))
+	running := func(regionID uint64) bool { return c.opController.GetOperator(regionID) != nil }
+	for _, region := range planner.Plan(budget, running) {
+		ops := c.checkers.CheckRegion(region)
+		if len(ops) == 0 || ops[0].Kind()&operator.OpMerge != 0 {
+			continue
+		}
+		if !c.opController.ExceedStoreLimit(ops...) {
+			c.opController.AddWaitingOperator(ops...)
+		}
+	}
+	repairListGauge.Set(float64(planner.Len()))
+}
+
 // checkPriorityRegions checks priority regions
 func (c *Coordinator) checkPriorityRegions() {
 	items := c.checkers.GetPriorityRegions()
@@ -303,7 +364,9 @@ func (c *Coordinator) tryAddOperators(region *core.RegionInfo) {
 		return
 	}
 
-	if !c.opController.ExceedStoreLimit(ops...) {
+	c.addOperatorMu.Lock()
+	defer c.addOperatorMu.Unlock()
+	if !c.exceedScheduleLimit(ops[0]) && !c.opController.ExceedStoreLimit(ops...) {
 		c.opController.AddWaitingOperator(ops...)
 		c.checkers.RemovePendingProcessedRegion(id)
 	} else {
@@ -311,6 +374,20 @@ func (c *Coordinator) tryAddOperators(region *core.RegionInfo) {
 	}
 }
 
+// exceedScheduleLimit checks the schedule limit of the operator made by the
+// checkers again, since the limit may be reached by the other partitions after
+// the checkers check it. It should be called with the addOperatorMu held.
+func (c *Coordinator) exceedScheduleLimit(op *operator.Operator) bool {
+	conf := c.cluster.GetCheckerConfig()
+	switch kind := op.SchedulerKind(); kind {
+	case operator.OpMerge:
+		return c.opController.OperatorCount(kind) >= conf.GetMergeScheduleLimit()
+	case operator.OpReplica:
+		return c.opController.OperatorCount(kind) >= conf.GetReplicaScheduleLimit()
+	}
+	return false
+}
+
 // drivePushOperator is used to push the unfinished operator to the executor.
 func (c *Coordinator) drivePushOperator() {
 	defer logutil.LogPanic()
@@ -366,6 +443,24 @@ func (c *Coordinator) driveSlowNodeScheduler() {
 	}
 }
 
+// driveSnapshotBudget is used to allocate the cluster snapshot bandwidth across the stores periodically.
+func (c *Coordinator) driveSnapshotBudget() {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(snapshotBudgetInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			log.Info("drive snapshot budget has been stopped")
+			return
+		case <-ticker.C:
+			c.opController.UpdateSnapshotBudget()
+		}
+	}
+}
+
 // RunUntilStop runs the coordinator until receiving the stop signal.
 func (c *Coordinator) RunUntilStop(collectWaitTime ...time.Duration) {
 	c.Run(collectWaitTime...)
@@ -399,7 +494,7 @@ func (c *Coordinator) Run(collectWaitTime ...time.Duration) {
 	log.Info("coordinator starts to run schedulers")
 	c.InitSchedulers(true)
 
-	c.wg.Add(4)
+	c.wg.Add(5)
 	// Starts to patrol regions.
 	go c.PatrolRegions()
 	// Checks suspect key ranges
@@ -407,6 +502,7 @@ func (c *Coordinator) Run(collectWaitTime ...time.Duration) {
 	go c.drivePushOperator()
 	// Checks whether to create evict-slow-trend scheduler.
 	go c.driveSlowNodeScheduler()
+	go c.driveSnapshotBudget()
 }
 
 // InitSchedulers initializes schedulers.
@@ -29,7 +29,6 @@ type ClusterInformer interface {
 	SchedulerCluster
 	CheckerCluster
 
-	GetStorage() storage.Storage
 	UpdateRegionsLabelLevelStats(regions []*core.RegionInfo)
 }
 
@@ -43,6 +42,7 @@ type SchedulerCluster interface {
 	GetSchedulerConfig() sc.SchedulerConfigProvider
 	GetRegionLabeler() *labeler.RegionLabeler
 	GetStoreConfig() sc.StoreConfigProvider
+	GetStorage() storage.Storage
 	IsSchedulingHalted() bool
 }
 
@@ -0,0 +1,135 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/schedule/config"
+	"github.com/tikv/pd/pkg/schedule/plan"
+)
+
+// storeOnlyFilter is implemented by the filters whose verdict only depends on the store and the config.
+type storeOnlyFilter interface {
+	Filter
+	storeOnly()
+}
+
+func (*storageThresholdFilter) storeOnly() {}
+func (*StoreStateFilter) storeOnly()       {}
+func (*engineFilter) storeOnly()           {}
+func (*specialUseFilter) storeOnly()       {}
+
+type statusCacheKey struct {
+	filter Filter
+	// store is the snapshot of the store, any change of the store produces a new one.
+	store  *core.StoreInfo
+	target bool
+}
+
+// verdict is the cached result of a filter, the type is cached too since
+// StoreStateFilter reports the type of its last check.
+type verdict struct {
+	status *plan.Status
+	typ    filterType
+}
+
+// StatusCache caches the verdicts of the store filters within one scheduling round.
+// It should be dropped after the round, and it is not thread-safe.
+type StatusCache struct {
+	// conf is the config of the first check, the verdicts under other configs are not cached.
+	conf     config.SharedConfigProvider
+	verdicts map[statusCacheKey]verdict
+}
+
+// NewStatusCache creates a new StatusCache.
+func NewStatusCache() *StatusCache {
+	return &StatusCache{verdicts: make(map[statusCacheKey]verdict)}
+}
+
+// Wrap returns the filters whose verdicts are cached, the filters depending on the region are kept as they are.
+func (c *StatusCache) Wrap(filters []Filter) []Filter {
+	if c == nil {
+		return filters
+	}
+	wrapped := make([]Filter, 0, len(filters))
+	for _, f := range filters {
+		switch sf := f.(type) {
+		case *cachedFilter:
+			// The filter may have been wrapped by another cache.
+			wrapped = append(wrapped, &cachedFilter{storeOnlyFilter: sf.storeOnlyFilter, cache: c})
+		case storeOnlyFilter:
+			wrapped = append(wrapped, &cachedFilter{storeOnlyFilter: sf, cache: c})
+		default:
+			wrapped = append(wrapped, f)
+		}
+	}
+	return wrapped
+}
+
+// Len returns the number of the cached verdicts.
+func (c *StatusCache) Len() int {
+	return len(c.verdicts)
+}
+
+func (c *StatusCache) getOrCompute(conf config.SharedConfigProvider, key statusCacheKey, compute func() *plan.Status) verdict {
+	if c.conf == nil {
+		c.conf = conf
+	}
+	if key.store == nil || conf != c.conf {
+		status := compute()
+		return verdict{status: status, typ: key.filter.Type()}
+	}
+	if v, ok := c.verdicts[key]; ok {
+		filterCacheHitCounter.Inc()
+		return v
+	}
+	filterCacheMissCounter.Inc()
+	status := compute()
+	v := verdict{status: status, typ: key.filter.Type()}
+	c.verdicts[key] = v
+	return v
+}
+
+type cachedFilter struct {
+	storeOnlyFilter
+	cache *StatusCache
+	// typ is the type of the last returned verdict.
+	typ filterType
+}
+
+// Type implements the Filter interface.
+func (f *cachedFilter) Type() filterType {
+	return f.typ
+}
+
+// Source implements the Filter interface.
+func (f *cachedFilter) Source(conf config.SharedConfigProvider, store *core.StoreInfo) *plan.Status {
+	key := statusCacheKey{filter: f.storeOnlyFilter, store: store}
+	v := f.cache.getOrCompute(conf, key, func() *plan.Status {
+		return f.storeOnlyFilter.Source(conf, store)
+	})
+	f.typ = v.typ
+	return v.status
+}
+
+// Target implements the Filter interface.
+func (f *cachedFilter) Target(conf config.SharedConfigProvider, store *core.StoreInfo) *plan.Status {
+	key := statusCacheKey{filter: f.storeOnlyFilter, store: store, target: true}
+	v := f.cache.getOrCompute(conf, key, func() *plan.Status {
+		return f.storeOnlyFilter.Target(conf, store)
+	})
+	f.typ = v.typ
+	return v.status
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/mock/mockconfig"
+)
+
+func TestStatusCache(t *testing.T) {
+	re := require.New(t)
+	conf := mockconfig.NewTestOptions()
+	filters := []Filter{
+		NewSpecialUseFilter(""),
+		NewExcludedFilter("", nil, map[uint64]struct{}{2: {}}),
+	}
+	cache := NewStatusCache()
+	wrapped := cache.Wrap(filters)
+	re.Len(wrapped, 2)
+	re.IsType(&cachedFilter{}, wrapped[0])
+	// the filter which depends on the region should not be cached.
+	re.Equal(filters[1], wrapped[1])
+
+	stores := []*core.StoreInfo{
+		core.NewStoreInfoWithLabel(1, map[string]string{}),
+		core.NewStoreInfoWithLabel(2, map[string]string{}),
+		core.NewStoreInfoWithLabel(3, map[string]string{SpecialUseKey: SpecialUseHotRegion}),
+	}
+	for i := 0; i < 3; i++ {
+		targets := SelectTargetStores(stores, wrapped, conf, nil, nil)
+		re.Len(targets, 1)
+		re.Equal(uint64(1), targets[0].GetID())
+		re.Equal(3, cache.Len())
+	}
+
+	// a new store snapshot should be evaluated again.
+	stores[2] = stores[2].Clone(core.SetStoreLabels([]*metapb.StoreLabel{}))
+	targets := SelectTargetStores(stores, wrapped, conf, nil, nil)
+	re.Len(targets, 2)
+	re.Equal(4, cache.Len())
+
+	// the verdicts under another config are not cached.
+	SelectTargetStores(stores, wrapped, mockconfig.NewTestOptions(), nil, nil)
+	re.Equal(4, cache.Len())
+
+	// the filters wrapped by another cache are rewrapped rather than nested.
+	other := NewStatusCache()
+	rewrapped := other.Wrap(wrapped)
+	re.Equal(filters[0], rewrapped[0].(*cachedFilter).storeOnlyFilter)
+	SelectTargetStores(stores, rewrapped, conf, nil, nil)
+	re.Equal(3, other.Len())
+	re.Equal(4, cache.Len())
+
+	// nil cache keeps the filters as they are.
+	var empty *StatusCache
+	re.Equal(filters, empty.Wrap(filters))
+}
+
+func TestStatusCacheType(t *testing.T) {
+	re := require.New(t)
+	conf := mockconfig.NewTestOptions()
+	stateFilter := &StoreStateFilter{MoveRegion: true}
+	wrapped := NewStatusCache().Wrap([]Filter{stateFilter})[0]
+	tombstone := core.NewStoreInfoWithLabel(1, map[string]string{}).Clone(core.SetStoreState(metapb.StoreState_Tombstone))
+	offline := core.NewStoreInfoWithLabel(2, map[string]string{}).Clone(core.SetStoreState(metapb.StoreState_Offline, false))
+
+	re.False(wrapped.Target(conf, tombstone).IsOK())
+	re.Equal(storeStateTombstone, wrapped.Type())
+	re.False(wrapped.Target(conf, offline).IsOK())
+	re.Equal(storeStateOffline, wrapped.Type())
+	// the cached verdict reports its own type rather than the last computed one.
+	re.False(wrapped.Target(conf, tombstone).IsOK())
+	re.Equal(storeStateTombstone, wrapped.Type())
+	re.Equal(storeStateOffline, stateFilter.Type())
+}
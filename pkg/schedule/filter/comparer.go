@@ -41,10 +41,10 @@ func RegionScoreComparer(conf config.SharedConfigProvider) StoreComparer {
 }
 
 // IsolationComparer creates a StoreComparer to sort store by isolation score.
-func IsolationComparer(locationLabels []string, regionStores []*core.StoreInfo) StoreComparer {
+func IsolationComparer(locationLabels []string, weights []float64, regionStores []*core.StoreInfo) StoreComparer {
 	return func(a, b *core.StoreInfo) int {
-		sa := core.DistinctScore(locationLabels, regionStores, a)
-		sb := core.DistinctScore(locationLabels, regionStores, b)
+		sa := core.WeightedDistinctScore(locationLabels, weights, regionStores, a)
+		sb := core.WeightedDistinctScore(locationLabels, weights, regionStores, b)
 		switch {
 		case sa > sb:
 			return 1
@@ -52,6 +52,7 @@ const (
 	engine
 	specialUse
 	isolation
+	leaderTransferExcluded
 
 	storeStateOK
 	storeStateTombstone
@@ -67,6 +68,8 @@ const (
 	storeStateTooManyPendingPeer
 	storeStateRejectLeader
 	storeStateSlowTrend
+	storeStateCompactionSaturated
+	storeStateQuarantined
 
 	filtersLen
 )
@@ -81,6 +84,7 @@ var filters = [filtersLen]string{
 	"engine-filter",
 	"special-use-filter",
 	"isolation-filter",
+	"leader-transfer-excluded-filter",
 
 	"store-state-ok-filter",
 	"store-state-tombstone-filter",
@@ -96,6 +100,8 @@ var filters = [filtersLen]string{
 	"store-state-too-many-pending-peers-filter",
 	"store-state-reject-leader-filter",
 	"store-state-slow-trend-filter",
+	"store-state-compaction-saturated-filter",
+	"store-state-quarantined-filter",
 }
 
 // String implements fmt.Stringer interface.
@@ -203,6 +203,36 @@ func (f *excludedFilter) Target(_ config.SharedConfigProvider, store *core.Store
 	return statusOK
 }
 
+type leaderTransferExcludedFilter struct {
+	scope  string
+	stores map[uint64]struct{}
+}
+
+// NewLeaderTransferExcludedFilter creates a Filter that filters the stores which
+// are excluded as the leader target after the failed leader transfers.
+func NewLeaderTransferExcludedFilter(scope string, stores map[uint64]struct{}) Filter {
+	return &leaderTransferExcludedFilter{scope: scope, stores: stores}
+}
+
+func (f *leaderTransferExcludedFilter) Scope() string {
+	return f.scope
+}
+
+func (*leaderTransferExcludedFilter) Type() filterType {
+	return leaderTransferExcluded
+}
+
+func (*leaderTransferExcludedFilter) Source(config.SharedConfigProvider, *core.StoreInfo) *plan.Status {
+	return statusOK
+}
+
+func (f *leaderTransferExcludedFilter) Target(_ config.SharedConfigProvider, store *core.StoreInfo) *plan.Status {
+	if _, ok := f.stores[store.GetID()]; ok {
+		return statusStoreRejectLeader
+	}
+	return statusOK
+}
+
 type storageThresholdFilter struct{ scope string }
 
 // NewStorageThresholdFilter creates a Filter that filters all stores that are
@@ -234,6 +264,7 @@ func (*storageThresholdFilter) Target(conf config.SharedConfigProvider, store *c
 type distinctScoreFilter struct {
 	scope     string
 	labels    []string
+	weights   []float64
 	stores    []*core.StoreInfo
 	policy    string
 	safeScore float64
@@ -250,17 +281,17 @@ const (
 
 // NewLocationSafeguard creates a filter that filters all stores that have
 // lower distinct score than specified store.
-func NewLocationSafeguard(scope string, labels []string, stores []*core.StoreInfo, source *core.StoreInfo) Filter {
-	return newDistinctScoreFilter(scope, labels, stores, source, locationSafeguard)
+func NewLocationSafeguard(scope string, labels []string, weights []float64, stores []*core.StoreInfo, source *core.StoreInfo) Filter {
+	return newDistinctScoreFilter(scope, labels, weights, stores, source, locationSafeguard)
 }
 
 // NewLocationImprover creates a filter that filters all stores that have
 // lower or equal distinct score than specified store.
-func NewLocationImprover(scope string, labels []string, stores []*core.StoreInfo, source *core.StoreInfo) Filter {
-	return newDistinctScoreFilter(scope, labels, stores, source, locationImprove)
+func NewLocationImprover(scope string, labels []string, weights []float64, stores []*core.StoreInfo, source *core.StoreInfo) Filter {
+	return newDistinctScoreFilter(scope, labels, weights, stores, source, locationImprove)
 }
 
-func newDistinctScoreFilter(scope string, labels []string, stores []*core.StoreInfo, source *core.StoreInfo, policy string) Filter {
+func newDistinctScoreFilter(scope string, labels []string, weights []float64, stores []*core.StoreInfo, source *core.StoreInfo, policy string) Filter {
 	newStores := make([]*core.StoreInfo, 0, len(stores)-1)
 	for _, s := range stores {
 		if s.GetID() == source.GetID() {
@@ -272,8 +303,9 @@ func newDistinctScoreFilter(scope string, labels []string, stores []*core.StoreI
 	return &distinctScoreFilter{
 		scope:     scope,
 		labels:    labels,
+		weights:   weights,
 		stores:    newStores,
-		safeScore: core.DistinctScore(labels, newStores, source),
+		safeScore: core.WeightedDistinctScore(labels, weights, newStores, source),
 		policy:    policy,
 		srcStore:  source.GetID(),
 	}
@@ -292,7 +324,7 @@ func (*distinctScoreFilter) Source(config.SharedConfigProvider, *core.StoreInfo)
 }
 
 func (f *distinctScoreFilter) Target(_ config.SharedConfigProvider, store *core.StoreInfo) *plan.Status {
-	score := core.DistinctScore(f.labels, f.stores, store)
+	score := core.WeightedDistinctScore(f.labels, f.weights, f.stores, store)
 	switch f.policy {
 	case locationSafeguard:
 		if score >= f.safeScore {
@@ -423,6 +455,16 @@ func (f *StoreStateFilter) isBusy(_ config.SharedConfigProvider, store *core.Sto
 	return statusOK
 }
 
+func (f *StoreStateFilter) isCompactionSaturated(conf config.SharedConfigProvider, store *core.StoreInfo) *plan.Status {
+	if !f.AllowTemporaryStates &&
+		store.IsCompactionSaturated(conf.GetMaxStorePendingCompactionBytes(), conf.GetMaxStoreLevel0FileCount()) {
+		f.Reason = storeStateCompactionSaturated
+		return statusStoreBusy
+	}
+	f.Reason = storeStateOK
+	return statusOK
+}
+
 func (f *StoreStateFilter) exceedRemoveLimit(_ config.SharedConfigProvider, store *core.StoreInfo) *plan.Status {
 	if !f.AllowTemporaryStates && !store.IsAvailable(storelimit.RemovePeer, f.OperatorLevel) {
 		f.Reason = storeStateExceedRemoveLimit
@@ -471,18 +513,28 @@ func (f *StoreStateFilter) hasRejectLeaderProperty(conf config.SharedConfigProvi
 	return statusOK
 }
 
+func (f *StoreStateFilter) isQuarantined(conf config.SharedConfigProvider, store *core.StoreInfo) *plan.Status {
+	if conf.GetLabelTaxonomyAction() == config.LabelTaxonomyQuarantine &&
+		config.CheckLabelTaxonomy(conf.GetLabelTaxonomy(), store.GetLabels()) != nil {
+		f.Reason = storeStateQuarantined
+		return statusStoreQuarantined
+	}
+	f.Reason = storeStateOK
+	return statusOK
+}
+
 // The condition table.
 // Y: the condition is temporary (expected to become false soon).
 // N: the condition is expected to be true for a long time.
 // X means when the condition is true, the store CANNOT be selected.
 //
-// Condition    Down Offline Tomb Pause Disconn Busy RmLimit AddLimit Snap Pending Reject
-// IsTemporary  N    N       N    N     Y       Y    Y       Y        Y    Y       N
+// Condition    Down Offline Tomb Pause Disconn Busy RmLimit AddLimit Snap Pending Reject Compaction Quarantine
+// IsTemporary  N    N       N    N     Y       Y    Y       Y        Y    Y       N      Y          N
 //
 // LeaderSource X            X    X     X
 // RegionSource                                 X    X                X
-// LeaderTarget X    X       X    X     X       X                                  X
-// RegionTarget X    X       X          X       X            X        X    X
+// LeaderTarget X    X       X    X     X       X                                  X      X          X
+// RegionTarget X    X       X          X       X            X        X    X              X          X
 
 const (
 	leaderSource = iota
@@ -506,10 +558,11 @@ func (f *StoreStateFilter) anyConditionMatch(typ int, conf config.SharedConfigPr
 		funcs = []conditionFunc{f.isBusy}
 	case leaderTarget:
 		funcs = []conditionFunc{f.isRemoved, f.isRemoving, f.isDown, f.pauseLeaderTransfer,
-			f.slowStoreEvicted, f.slowTrendEvicted, f.isDisconnected, f.isBusy, f.hasRejectLeaderProperty}
+			f.slowStoreEvicted, f.slowTrendEvicted, f.isDisconnected, f.isBusy, f.hasRejectLeaderProperty,
+			f.isCompactionSaturated, f.isQuarantined}
 	case regionTarget:
 		funcs = []conditionFunc{f.isRemoved, f.isRemoving, f.isDown, f.isDisconnected, f.isBusy,
-			f.exceedAddLimit, f.tooManySnapshots, f.tooManyPendingPeers}
+			f.exceedAddLimit, f.tooManySnapshots, f.tooManyPendingPeers, f.isCompactionSaturated, f.isQuarantined}
 	case witnessTarget:
 		funcs = []conditionFunc{f.isRemoved, f.isRemoving, f.isDown, f.isDisconnected, f.isBusy}
 	case scatterRegionTarget:
@@ -774,7 +827,7 @@ func NewPlacementSafeguard(scope string, conf config.SharedConfigProvider, clust
 	if conf.IsPlacementRulesEnabled() {
 		return newRuleFitFilter(scope, cluster, ruleManager, region, oldFit, sourceStore.GetID())
 	}
-	return NewLocationSafeguard(scope, conf.GetLocationLabels(), cluster.GetRegionStores(region), sourceStore)
+	return NewLocationSafeguard(scope, conf.GetLocationLabels(), conf.GetLocationLabelWeights(), cluster.GetRegionStores(region), sourceStore)
 }
 
 // NewPlacementLeaderSafeguard creates a filter that ensures after transfer a leader with
@@ -27,6 +27,7 @@ import (
 	"github.com/tikv/pd/pkg/core/storelimit"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/mock/mockconfig"
+	sc "github.com/tikv/pd/pkg/schedule/config"
 	"github.com/tikv/pd/pkg/schedule/placement"
 	"github.com/tikv/pd/pkg/schedule/plan"
 )
@@ -59,8 +60,8 @@ func TestDistinctScoreFilter(t *testing.T) {
 		for _, id := range testCase.stores {
 			stores = append(stores, allStores[id-1])
 		}
-		ls := NewLocationSafeguard("", labels, stores, allStores[testCase.source-1])
-		li := NewLocationImprover("", labels, stores, allStores[testCase.source-1])
+		ls := NewLocationSafeguard("", labels, nil, stores, allStores[testCase.source-1])
+		li := NewLocationImprover("", labels, nil, stores, allStores[testCase.source-1])
 		re.Equal(testCase.safeGuardRes, ls.Target(mockconfig.NewTestOptions(), allStores[testCase.target-1]).StatusCode)
 		re.Equal(testCase.improverRes, li.Target(mockconfig.NewTestOptions(), allStores[testCase.target-1]).StatusCode)
 	}
@@ -285,6 +286,30 @@ func TestStoreStateFilter(t *testing.T) {
 		{3, plan.StatusOK, plan.StatusOK},
 	}
 	check(store, testCases)
+
+	// Quarantined for violating the label taxonomy
+	cfg := opt.GetReplicationConfig().Clone()
+	cfg.LabelTaxonomy = []sc.StoreLabelConstraint{{Key: "zone", Required: true}}
+	opt.SetReplicationConfig(cfg)
+	store = store.Clone(core.SetStoreStats(&pdpb.StoreStats{}))
+	// the stores are only rejected when the action is quarantine.
+	testCases = []testCase{
+		{2, plan.StatusOK, plan.StatusOK},
+	}
+	check(store, testCases)
+	cfg.LabelTaxonomyAction = sc.LabelTaxonomyQuarantine
+	opt.SetReplicationConfig(cfg)
+	testCases = []testCase{
+		{0, plan.StatusOK, plan.StatusStoreQuarantined},
+		{1, plan.StatusOK, plan.StatusStoreQuarantined},
+		{3, plan.StatusOK, plan.StatusStoreQuarantined},
+	}
+	check(store, testCases)
+	store = store.Clone(core.SetStoreLabels([]*metapb.StoreLabel{{Key: "zone", Value: "z1"}}))
+	testCases = []testCase{
+		{2, plan.StatusOK, plan.StatusOK},
+	}
+	check(store, testCases)
 }
 
 func TestStoreStateFilterReason(t *testing.T) {
@@ -434,7 +459,7 @@ func TestPlacementGuard(t *testing.T) {
 	}}, &metapb.Peer{StoreId: 1, Id: 1})
 	store := testCluster.GetStore(1)
 
-	re.IsType(NewLocationSafeguard("", []string{"zone"}, testCluster.GetRegionStores(region), store),
+	re.IsType(NewLocationSafeguard("", []string{"zone"}, nil, testCluster.GetRegionStores(region), store),
 		NewPlacementSafeguard("", testCluster.GetSharedConfig(), testCluster.GetBasicCluster(), testCluster.GetRuleManager(), region, store, nil))
 	testCluster.SetEnablePlacementRules(true)
 	re.IsType(newRuleFitFilter("", testCluster.GetBasicCluster(), testCluster.GetRuleManager(), region, nil, 1),
@@ -471,6 +496,21 @@ func TestSpecialUseFilter(t *testing.T) {
 	}
 }
 
+func TestLeaderTransferExcludedFilter(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opt := mockconfig.NewTestOptions()
+	testCluster := mockcluster.NewCluster(ctx, opt)
+	filter := NewLeaderTransferExcludedFilter("", map[uint64]struct{}{1: {}})
+	for storeID, targetRes := range map[uint64]plan.StatusCode{1: plan.StatusStoreRejectLeader, 2: plan.StatusOK} {
+		store := core.NewStoreInfoWithLabel(storeID, nil)
+		re.Equal(plan.StatusOK, filter.Source(testCluster.GetSharedConfig(), store).StatusCode)
+		re.Equal(targetRes, filter.Target(testCluster.GetSharedConfig(), store).StatusCode)
+	}
+}
+
 func BenchmarkCloneRegionTest(b *testing.B) {
 	epoch := &metapb.RegionEpoch{
 		ConfVer: 1,
@@ -24,8 +24,20 @@ var (
 			Name:      "filter",
 			Help:      "Counter of the filter",
 		}, []string{"action", "scope", "type", "source", "target"})
+
+	filterCacheCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "filter_cache",
+			Help:      "Counter of the filter verdict cache",
+		}, []string{"result"})
+
+	filterCacheHitCounter  = filterCacheCounter.WithLabelValues("hit")
+	filterCacheMissCounter = filterCacheCounter.WithLabelValues("miss")
 )
 
 func init() {
 	prometheus.MustRegister(filterCounter)
+	prometheus.MustRegister(filterCacheCounter)
 }
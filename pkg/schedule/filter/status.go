@@ -38,6 +38,7 @@ var (
 
 	// store config limitation
 	statusStoreRejectLeader = plan.NewStatus(plan.StatusStoreRejectLeader)
+	statusStoreQuarantined  = plan.NewStatus(plan.StatusStoreQuarantined)
 
 	statusStoreNotMatchRule      = plan.NewStatus(plan.StatusStoreNotMatchRule)
 	statusStoreNotMatchIsolation = plan.NewStatus(plan.StatusStoreNotMatchIsolation)
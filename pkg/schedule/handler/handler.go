@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/hex"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -31,6 +32,7 @@ import (
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/schedule"
+	"github.com/tikv/pd/pkg/schedule/checker"
 	sche "github.com/tikv/pd/pkg/schedule/core"
 	"github.com/tikv/pd/pkg/schedule/filter"
 	"github.com/tikv/pd/pkg/schedule/labeler"
@@ -41,6 +43,7 @@ import (
 	"github.com/tikv/pd/pkg/statistics"
 	"github.com/tikv/pd/pkg/statistics/buckets"
 	"github.com/tikv/pd/pkg/statistics/utils"
+	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/pkg/utils/typeutil"
 	"go.uber.org/zap"
 )
@@ -48,6 +51,8 @@ import (
 const (
 	defaultRegionLimit = 16
 	maxRegionLimit     = 10240
+	// operatorProgressInterval is the interval to observe the progress of the watched operators.
+	operatorProgressInterval = 200 * time.Millisecond
 )
 
 // Server is the interface for handler about schedule.
@@ -117,6 +122,40 @@ func (h *Handler) GetOperatorStatus(regionID uint64) (*operator.OpWithStatus, er
 	return op, nil
 }
 
+// WatchOperatorProgress sends the progress events of the operators on the given
+// regions until all of them end or the context is done. It returns the error of
+// send if it fails.
+func (h *Handler) WatchOperatorProgress(ctx context.Context, regionIDs []uint64, send func([]*operator.ProgressEvent) error) error {
+	c, err := h.GetOperatorController()
+	if err != nil {
+		return err
+	}
+	getOp := func(regionID uint64) *operator.Operator {
+		if op := c.GetOperatorStatus(regionID); op != nil {
+			return op.Operator
+		}
+		return nil
+	}
+	watcher := operator.NewProgressWatcher(regionIDs)
+	ticker := time.NewTicker(operatorProgressInterval)
+	defer ticker.Stop()
+	for {
+		if events := watcher.Observe(getOp); len(events) > 0 {
+			if err := send(events); err != nil {
+				return err
+			}
+		}
+		if watcher.Done() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
 // RemoveOperator removes the region operator.
 func (h *Handler) RemoveOperator(regionID uint64) error {
 	c, err := h.GetOperatorController()
@@ -237,6 +276,24 @@ func (h *Handler) GetRecords(from time.Time) ([]*operator.OpRecord, error) {
 	return records, nil
 }
 
+// GetLeaderTransferBlacklist returns the stores which are excluded as the leader target.
+func (h *Handler) GetLeaderTransferBlacklist() ([]operator.LeaderTransferExclusion, error) {
+	c, err := h.GetOperatorController()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetLeaderTransferBlacklist(), nil
+}
+
+// GetOperatorControllerState returns the state of the operator controller.
+func (h *Handler) GetOperatorControllerState() (*operator.ControllerState, error) {
+	c, err := h.GetOperatorController()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetState(), nil
+}
+
 // HandleOperatorCreation processes the request and creates an operator based on the provided input.
 // It supports various types of operators such as transfer-leader, transfer-region, add-peer, remove-peer, merge-region, split-region, scatter-region, and scatter-regions.
 // The function validates the input, performs the corresponding operation, and returns the HTTP status code, response body, and any error encountered during the process.
@@ -785,6 +842,15 @@ func (h *Handler) GetCheckerStatus(name string) (map[string]bool, error) {
 	}, nil
 }
 
+// GetPatrolRegionsProgress returns the progress of the current patrol round.
+func (h *Handler) GetPatrolRegionsProgress() (*schedule.PatrolRegionsProgress, error) {
+	co := h.GetCoordinator()
+	if co == nil {
+		return nil, errs.ErrNotBootstrapped.GenWithStackByArgs()
+	}
+	return co.GetPatrolRegionsProgress(), nil
+}
+
 // GetSchedulersController returns controller of schedulers.
 func (h *Handler) GetSchedulersController() (*schedulers.Controller, error) {
 	co := h.GetCoordinator()
@@ -882,6 +948,62 @@ func (h *Handler) GetSchedulerByStatus(status string, needTS bool) (any, error)
 	}
 }
 
+// SchedulerDryRunResult is the result of a scheduling round which is run without adding the operators.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type SchedulerDryRunResult struct {
+	Name string `json:"name"`
+	// Allowed shows whether the scheduler is allowed to schedule currently, the
+	// operators won't be created by the scheduler if it's not allowed, e.g. paused.
+	Allowed   bool                       `json:"allowed"`
+	Operators []*SchedulerDryRunOperator `json:"operators"`
+}
+
+// SchedulerDryRunOperator is an operator created by the dry run of a scheduler.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type SchedulerDryRunOperator struct {
+	RegionID uint64   `json:"region_id"`
+	Desc     string   `json:"desc"`
+	Brief    string   `json:"brief"`
+	Kind     string   `json:"kind"`
+	Steps    []string `json:"steps"`
+}
+
+// DryRunScheduler runs one scheduling round of the specified scheduler against the
+// current cluster and returns the operators without adding them to the operator controller.
+func (h *Handler) DryRunScheduler(name string) (*SchedulerDryRunResult, error) {
+	sc, err := h.GetSchedulersController()
+	if err != nil {
+		return nil, err
+	}
+	s := sc.GetScheduler(name)
+	if s == nil {
+		return nil, errs.ErrSchedulerNotFound.FastGenByArgs()
+	}
+	if !schedulers.IsDryRunnable(name) {
+		return nil, errs.ErrSchedulerNotDryRunnable.FastGenByArgs(name)
+	}
+	ops, _ := s.DiagnoseDryRun()
+	result := &SchedulerDryRunResult{
+		Name:      name,
+		Allowed:   s.AllowSchedule(false),
+		Operators: make([]*SchedulerDryRunOperator, 0, len(ops)),
+	}
+	for _, op := range ops {
+		steps := make([]string, 0, op.Len())
+		for i := 0; i < op.Len(); i++ {
+			steps = append(steps, op.Step(i).String())
+		}
+		result.Operators = append(result.Operators, &SchedulerDryRunOperator{
+			RegionID: op.RegionID(),
+			Desc:     op.Desc(),
+			Brief:    op.Brief(),
+			Kind:     op.Kind().String(),
+			Steps:    steps,
+		})
+	}
+	return result, nil
+}
+
 // GetDiagnosticResult returns the diagnostic results of the specified scheduler.
 func (h *Handler) GetDiagnosticResult(name string) (*schedulers.DiagnosticResult, error) {
 	if _, ok := schedulers.DiagnosableSummaryFunc[name]; !ok {
@@ -1156,6 +1278,55 @@ func (h *Handler) AccelerateRegionsScheduleInRanges(startKeys [][]byte, endKeys
 	return nil
 }
 
+// MergeRegionsInRange starts a job to merge the undersized regions in the given range aggressively.
+func (h *Handler) MergeRegionsInRange(rawStartKey, rawEndKey string, targetCount int) (*checker.RangeMergeProgress, error) {
+	startKey, err := hex.DecodeString(rawStartKey)
+	if err != nil {
+		return nil, errs.ErrRangeMergeInvalidRange.FastGenByArgs(err.Error())
+	}
+	endKey, err := hex.DecodeString(rawEndKey)
+	if err != nil {
+		return nil, errs.ErrRangeMergeInvalidRange.FastGenByArgs(err.Error())
+	}
+	co := h.GetCoordinator()
+	if co == nil {
+		return nil, errs.ErrNotBootstrapped.GenWithStackByArgs()
+	}
+	return co.GetCheckerController().GetRangeMerger().MergeRange(startKey, endKey, targetCount)
+}
+
+// GetRangeMergeJobs returns the progress of the recent range merge jobs.
+func (h *Handler) GetRangeMergeJobs() ([]*checker.RangeMergeProgress, error) {
+	co := h.GetCoordinator()
+	if co == nil {
+		return nil, errs.ErrNotBootstrapped.GenWithStackByArgs()
+	}
+	return co.GetCheckerController().GetRangeMerger().GetJobs(), nil
+}
+
+// DiagnoseMergeTargets returns the diagnosis of the adjacent regions of the
+// region as the merge target.
+func (h *Handler) DiagnoseMergeTargets(regionID uint64) ([]*checker.MergeTargetDiagnosis, error) {
+	co := h.GetCoordinator()
+	if co == nil {
+		return nil, errs.ErrNotBootstrapped.GenWithStackByArgs()
+	}
+	region := co.GetCluster().GetRegion(regionID)
+	if region == nil {
+		return nil, errs.ErrRegionNotFound.FastGenByArgs(regionID)
+	}
+	return co.GetCheckerController().GetMergeChecker().DiagnoseTargets(region), nil
+}
+
+// CancelRangeMergeJob cancels the running range merge job.
+func (h *Handler) CancelRangeMergeJob(id uint64) error {
+	co := h.GetCoordinator()
+	if co == nil {
+		return errs.ErrNotBootstrapped.GenWithStackByArgs()
+	}
+	return co.GetCheckerController().GetRangeMerger().CancelJob(id)
+}
+
 // AdjustLimit adjusts the limit of regions to schedule.
 func (*Handler) AdjustLimit(limitStr string, defaultLimits ...int) (int, error) {
 	limit := defaultRegionLimit
@@ -1200,7 +1371,7 @@ func (*Handler) BuildScatterRegionsResp(opsCount int, failures map[uint64]error)
 }
 
 // ScatterRegionsByRange scatters regions by range.
-func (h *Handler) ScatterRegionsByRange(rawStartKey, rawEndKey string, group string, retryLimit int) (int, map[uint64]error, error) {
+func (h *Handler) ScatterRegionsByRange(rawStartKey, rawEndKey string, group string, retryLimit int, storeGroup string) (int, map[uint64]error, error) {
 	startKey, err := hex.DecodeString(rawStartKey)
 	if err != nil {
 		return 0, nil, err
@@ -1213,16 +1384,39 @@ func (h *Handler) ScatterRegionsByRange(rawStartKey, rawEndKey string, group str
 	if co == nil {
 		return 0, nil, errs.ErrNotBootstrapped.GenWithStackByArgs()
 	}
-	return co.GetRegionScatterer().ScatterRegionsByRange(startKey, endKey, group, retryLimit)
+	opts, err := h.getScatterOptions(storeGroup)
+	if err != nil {
+		return 0, nil, err
+	}
+	return co.GetRegionScatterer().ScatterRegionsByRange(startKey, endKey, group, retryLimit, opts...)
 }
 
 // ScatterRegionsByID scatters regions by id.
-func (h *Handler) ScatterRegionsByID(ids []uint64, group string, retryLimit int) (int, map[uint64]error, error) {
+func (h *Handler) ScatterRegionsByID(ids []uint64, group string, retryLimit int, storeGroup string) (int, map[uint64]error, error) {
 	co := h.GetCoordinator()
 	if co == nil {
 		return 0, nil, errs.ErrNotBootstrapped.GenWithStackByArgs()
 	}
-	return co.GetRegionScatterer().ScatterRegionsByID(ids, group, retryLimit, false)
+	opts, err := h.getScatterOptions(storeGroup)
+	if err != nil {
+		return 0, nil, err
+	}
+	return co.GetRegionScatterer().ScatterRegionsByID(ids, group, retryLimit, false, opts...)
+}
+
+func (h *Handler) getScatterOptions(storeGroup string) ([]scatter.ScatterOption, error) {
+	if storeGroup == "" {
+		return nil, nil
+	}
+	c := h.GetCluster()
+	if c == nil {
+		return nil, errs.ErrNotBootstrapped.GenWithStackByArgs()
+	}
+	g := c.GetRuleManager().GetStoreGroup(storeGroup)
+	if g == nil {
+		return nil, errs.ErrStoreGroupNotFound.FastGenByArgs(storeGroup)
+	}
+	return []scatter.ScatterOption{scatter.WithStoreGroup(g)}, nil
 }
 
 // SplitRegionsResponse is the response for split regions.
@@ -1299,6 +1493,106 @@ func (h *Handler) CheckRegionsReplicated(rawStartKey, rawEndKey string) (string,
 	return state, nil
 }
 
+// GetZoneDistribution returns how the replicas are distributed across the zones versus what the
+// rules prescribe. The regions are the ones in the given hex-encoded key range, or the ones
+// covered by the rules of the group if the key range is not given. Only a page of at most
+// maxRegionLimit regions is taken into account, the rest can be got by the next page token.
+func (h *Handler) GetZoneDistribution(rawStartKey, rawEndKey, group, zoneLabel string, page *apiutil.PageQuery) (*placement.ZoneDistribution, error) {
+	startKey, err := hex.DecodeString(rawStartKey)
+	if err != nil {
+		return nil, errs.ErrKeyFormat.FastGenByArgs(err)
+	}
+	endKey, err := hex.DecodeString(rawEndKey)
+	if err != nil {
+		return nil, errs.ErrKeyFormat.FastGenByArgs(err)
+	}
+	manager, err := h.GetRuleManager()
+	if err != nil {
+		return nil, err
+	}
+	c := h.GetCluster()
+	scan := c.ScanRegions
+	if len(group) > 0 && len(startKey) == 0 && len(endKey) == 0 {
+		scan = scanKeyRanges(c.ScanRegions, getRuleRanges(manager.GetRulesByGroup(group)))
+	}
+	if page.Token != "" {
+		if startKey, err = page.KeyToken(); err != nil {
+			return nil, errs.ErrKeyFormat.FastGenByArgs(err)
+		}
+	}
+	if page.Limit <= 0 || page.Limit > maxRegionLimit {
+		page.Limit = maxRegionLimit
+	}
+	regions, next := apiutil.TrimPage(scan(startKey, endKey, page.ScanLimit()), page, func(r *core.RegionInfo) string {
+		return apiutil.KeyPageToken(r.GetStartKey())
+	})
+	distribution := manager.GetZoneDistribution(c, regions, zoneLabel, group)
+	distribution.NextPageToken = next
+	return distribution, nil
+}
+
+// getRuleRanges returns the key ranges covered by the rules, which are sorted
+// and merged if they overlap or are adjacent.
+func getRuleRanges(rules []*placement.Rule) []*core.KeyRange {
+	ranges := make([]*core.KeyRange, 0, len(rules))
+	for _, rule := range rules {
+		ranges = append(ranges, &core.KeyRange{StartKey: rule.StartKey, EndKey: rule.EndKey})
+	}
+	sort.Slice(ranges, func(i, j int) bool { return bytes.Compare(ranges[i].StartKey, ranges[j].StartKey) < 0 })
+	merged := make([]*core.KeyRange, 0, len(ranges))
+	for _, kr := range ranges {
+		if len(merged) > 0 {
+			last := merged[len(merged)-1]
+			if len(last.EndKey) == 0 {
+				break
+			}
+			if bytes.Compare(kr.StartKey, last.EndKey) <= 0 {
+				if len(kr.EndKey) == 0 || bytes.Compare(kr.EndKey, last.EndKey) > 0 {
+					last.EndKey = kr.EndKey
+				}
+				continue
+			}
+		}
+		merged = append(merged, &core.KeyRange{StartKey: kr.StartKey, EndKey: kr.EndKey})
+	}
+	return merged
+}
+
+// scanKeyRanges returns the function to scan the regions in the sorted and disjoint
+// key ranges from the start key, the end key is ignored since the ranges are bounded.
+func scanKeyRanges(
+	scan func(startKey, endKey []byte, limit int) []*core.RegionInfo, ranges []*core.KeyRange,
+) func(startKey, endKey []byte, limit int) []*core.RegionInfo {
+	return func(startKey, _ []byte, limit int) []*core.RegionInfo {
+		var regions []*core.RegionInfo
+		for _, kr := range ranges {
+			if len(kr.EndKey) > 0 && bytes.Compare(kr.EndKey, startKey) <= 0 {
+				continue
+			}
+			from := kr.StartKey
+			if bytes.Compare(startKey, from) > 0 {
+				from = startKey
+			}
+			// The region across the ranges is scanned twice, so one more is scanned
+			// to make up for it.
+			remaining := 0
+			if limit > 0 {
+				remaining = limit - len(regions) + 1
+			}
+			for _, region := range scan(from, kr.EndKey, remaining) {
+				if len(regions) > 0 && regions[len(regions)-1].GetID() == region.GetID() {
+					continue
+				}
+				regions = append(regions, region)
+			}
+			if limit > 0 && len(regions) >= limit {
+				return regions[:limit]
+			}
+		}
+		return regions
+	}
+}
+
 // GetRuleManager returns the rule manager.
 func (h *Handler) GetRuleManager() (*placement.RuleManager, error) {
 	c := h.GetCluster()
@@ -0,0 +1,290 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/tikv/pd/pkg/schedule/schedulers"
+	"github.com/tikv/pd/pkg/statistics"
+	"github.com/tikv/pd/pkg/statistics/utils"
+)
+
+// The actions recommended by the hot region analysis.
+const (
+	HotActionSplit     = "split"
+	HotActionScatter   = "scatter"
+	HotActionScheduler = "scheduler"
+)
+
+const (
+	// hotSkewRatio is the ratio of the store load to the average load above
+	// which the store is considered skewed.
+	hotSkewRatio = 1.5
+	// hotDominantShare is the share of the store load above which a single
+	// region dominates the store, it cannot be balanced without splitting.
+	hotDominantShare = 0.5
+	// maxAnalyzedHotRegions is the max number of the hot regions in the analysis.
+	maxAnalyzedHotRegions = 10
+	// maxScatterPerStore is the max number of the regions recommended to scatter for a skewed store.
+	maxScatterPerStore = 3
+)
+
+// HotAnalysis is the result of the hot region analysis.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type HotAnalysis struct {
+	Type            string               `json:"type"`
+	AverageByteRate float64              `json:"average_byte_rate"`
+	SkewedStores    []*HotSkewedStore    `json:"skewed_stores"`
+	HotRegions      []*HotRegionAnalysis `json:"hot_regions"`
+	Recommendations []*HotRecommendation `json:"recommendations"`
+}
+
+// HotSkewedStore is a store whose load is much higher than the average.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type HotSkewedStore struct {
+	StoreID  uint64  `json:"store_id"`
+	ByteRate float64 `json:"byte_rate"`
+	// Ratio is the ratio of the store load to the average load.
+	Ratio          float64 `json:"ratio"`
+	HotRegionCount int     `json:"hot_region_count"`
+}
+
+// HotRegionAnalysis is the analysis of a hot region.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type HotRegionAnalysis struct {
+	RegionID  uint64  `json:"region_id"`
+	StoreID   uint64  `json:"store_id"`
+	ByteRate  float64 `json:"byte_rate"`
+	KeyRate   float64 `json:"key_rate"`
+	QueryRate float64 `json:"query_rate"`
+	HotDegree int     `json:"hot_degree"`
+	// Share is the share of the region in the load of the store.
+	Share float64 `json:"share"`
+	// SplitKey is the hex encoded key which splits the load of the region
+	// evenly according to the buckets, it's empty if there are no buckets.
+	SplitKey string `json:"split_key,omitempty"`
+}
+
+// HotRecommendation is an action recommended to mitigate the hotspot.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type HotRecommendation struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+	// Command is the pd-ctl command to apply the recommendation.
+	Command string `json:"command"`
+}
+
+// AnalyzeHotRegions analyzes the hot statistics and the hot buckets of the
+// given type, and recommends the actions to mitigate the hotspot.
+func (h *Handler) AnalyzeHotRegions(typ utils.RWType) (*HotAnalysis, error) {
+	stores, err := h.GetHotStores()
+	if err != nil {
+		return nil, err
+	}
+	hotRegions, err := h.GetHotRegions(typ)
+	if err != nil {
+		return nil, err
+	}
+	buckets, err := h.GetHotBuckets()
+	if err != nil {
+		return nil, err
+	}
+	sc, err := h.GetSchedulersController()
+	if err != nil {
+		return nil, err
+	}
+	storeLoads, hotPeers := stores.BytesWriteStats, hotRegions.AsPeer
+	if typ == utils.Read {
+		storeLoads, hotPeers = stores.BytesReadStats, hotRegions.AsLeader
+	}
+	var schedulerStatus string
+	if sc.GetScheduler(schedulers.HotRegionName) == nil {
+		schedulerStatus = "disabled"
+	} else if paused, _ := sc.IsSchedulerPaused(schedulers.HotRegionName); paused {
+		schedulerStatus = "paused"
+	}
+	return analyzeHotRegions(typ, storeLoads, hotPeers, buckets, schedulerStatus), nil
+}
+
+func analyzeHotRegions(typ utils.RWType, storeLoads map[uint64]float64, hotPeers statistics.StoreHotPeersStat,
+	buckets HotBucketsResponse, schedulerStatus string) *HotAnalysis {
+	analysis := &HotAnalysis{
+		Type:            typ.String(),
+		SkewedStores:    []*HotSkewedStore{},
+		HotRegions:      []*HotRegionAnalysis{},
+		Recommendations: []*HotRecommendation{},
+	}
+	var total float64
+	for _, load := range storeLoads {
+		total += load
+	}
+	if len(storeLoads) > 0 {
+		analysis.AverageByteRate = total / float64(len(storeLoads))
+	}
+
+	// collect the hot regions, a region may be hot in multiple stores.
+	regions := make(map[uint64]*HotRegionAnalysis)
+	regionsByStore := make(map[uint64][]*HotRegionAnalysis)
+	for storeID, stat := range hotPeers {
+		if stat == nil {
+			continue
+		}
+		storeLoad := storeLoads[storeID]
+		for _, peer := range stat.Stats {
+			region := &HotRegionAnalysis{
+				RegionID:  peer.RegionID,
+				StoreID:   storeID,
+				ByteRate:  peer.ByteRate,
+				KeyRate:   peer.KeyRate,
+				QueryRate: peer.QueryRate,
+				HotDegree: peer.HotDegree,
+			}
+			if storeLoad > 0 {
+				region.Share = peer.ByteRate / storeLoad
+			}
+			regionsByStore[storeID] = append(regionsByStore[storeID], region)
+			if old, ok := regions[peer.RegionID]; !ok || old.Share < region.Share {
+				regions[peer.RegionID] = region
+			}
+		}
+	}
+	for _, region := range regions {
+		region.SplitKey = evenSplitKey(typ, buckets[region.RegionID])
+		analysis.HotRegions = append(analysis.HotRegions, region)
+	}
+	sort.Slice(analysis.HotRegions, func(i, j int) bool {
+		if analysis.HotRegions[i].ByteRate != analysis.HotRegions[j].ByteRate {
+			return analysis.HotRegions[i].ByteRate > analysis.HotRegions[j].ByteRate
+		}
+		return analysis.HotRegions[i].RegionID < analysis.HotRegions[j].RegionID
+	})
+	if len(analysis.HotRegions) > maxAnalyzedHotRegions {
+		analysis.HotRegions = analysis.HotRegions[:maxAnalyzedHotRegions]
+	}
+
+	// the regions dominating the stores should be split.
+	dominant := make(map[uint64]struct{})
+	for _, region := range analysis.HotRegions {
+		if region.Share < hotDominantShare {
+			continue
+		}
+		dominant[region.RegionID] = struct{}{}
+		recommendation := &HotRecommendation{
+			Action: HotActionSplit,
+			Reason: fmt.Sprintf("region %d takes %.0f%% of the %s load of store %d", region.RegionID, region.Share*100, typ, region.StoreID),
+		}
+		if region.SplitKey != "" {
+			recommendation.Command = fmt.Sprintf("operator add split-region %d --policy=usekey --keys=%s", region.RegionID, region.SplitKey)
+		} else {
+			recommendation.Command = fmt.Sprintf("operator add split-region %d --policy=approximate", region.RegionID)
+		}
+		analysis.Recommendations = append(analysis.Recommendations, recommendation)
+	}
+
+	// the other hot regions in the skewed stores should be scattered.
+	if analysis.AverageByteRate > 0 {
+		for storeID, load := range storeLoads {
+			ratio := load / analysis.AverageByteRate
+			if ratio < hotSkewRatio {
+				continue
+			}
+			analysis.SkewedStores = append(analysis.SkewedStores, &HotSkewedStore{
+				StoreID:        storeID,
+				ByteRate:       load,
+				Ratio:          ratio,
+				HotRegionCount: len(regionsByStore[storeID]),
+			})
+		}
+	}
+	sort.Slice(analysis.SkewedStores, func(i, j int) bool {
+		return analysis.SkewedStores[i].Ratio > analysis.SkewedStores[j].Ratio
+	})
+	for _, store := range analysis.SkewedStores {
+		candidates := regionsByStore[store.StoreID]
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].ByteRate > candidates[j].ByteRate })
+		scattered := 0
+		for _, region := range candidates {
+			if scattered >= maxScatterPerStore {
+				break
+			}
+			if _, ok := dominant[region.RegionID]; ok {
+				continue
+			}
+			scattered++
+			analysis.Recommendations = append(analysis.Recommendations, &HotRecommendation{
+				Action:  HotActionScatter,
+				Reason:  fmt.Sprintf("store %d is skewed with %.1fx of the average %s load", store.StoreID, store.Ratio, typ),
+				Command: fmt.Sprintf("operator add scatter-region %d", region.RegionID),
+			})
+		}
+	}
+
+	// the hot region scheduler should work if there are skewed stores.
+	if len(analysis.SkewedStores) > 0 {
+		switch schedulerStatus {
+		case "disabled":
+			analysis.Recommendations = append(analysis.Recommendations, &HotRecommendation{
+				Action:  HotActionScheduler,
+				Reason:  "the hot region scheduler is disabled",
+				Command: "scheduler add " + schedulers.HotRegionName,
+			})
+		case "paused":
+			analysis.Recommendations = append(analysis.Recommendations, &HotRecommendation{
+				Action:  HotActionScheduler,
+				Reason:  "the hot region scheduler is paused",
+				Command: "scheduler resume " + schedulers.HotRegionName,
+			})
+		}
+	}
+	return analysis
+}
+
+// evenSplitKey returns the boundary of the buckets which splits the load most evenly.
+func evenSplitKey(typ utils.RWType, buckets []*HotBucketsItem) string {
+	if len(buckets) < 2 {
+		return ""
+	}
+	load := func(b *HotBucketsItem) float64 {
+		if typ == utils.Read {
+			return float64(b.ReadBytes)
+		}
+		return float64(b.WriteBytes)
+	}
+	var total float64
+	for _, b := range buckets {
+		total += load(b)
+	}
+	if total == 0 {
+		return ""
+	}
+	var (
+		sum     float64
+		key     string
+		minDiff = total
+	)
+	for _, b := range buckets[:len(buckets)-1] {
+		sum += load(b)
+		diff := sum - (total - sum)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff < minDiff {
+			minDiff, key = diff, b.EndKey
+		}
+	}
+	return key
+}
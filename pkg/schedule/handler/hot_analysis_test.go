@@ -0,0 +1,75 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/statistics"
+	"github.com/tikv/pd/pkg/statistics/utils"
+)
+
+func TestAnalyzeHotRegions(t *testing.T) {
+	re := require.New(t)
+	storeLoads := map[uint64]float64{1: 1000, 2: 100, 3: 100}
+	hotPeers := statistics.StoreHotPeersStat{
+		1: {Stats: []statistics.HotPeerStatShow{
+			{RegionID: 10, ByteRate: 600},
+			{RegionID: 11, ByteRate: 200},
+			{RegionID: 12, ByteRate: 100},
+		}},
+		2: {Stats: []statistics.HotPeerStatShow{{RegionID: 13, ByteRate: 40}}},
+	}
+	buckets := HotBucketsResponse{
+		10: {
+			{StartKey: "61", EndKey: "62", WriteBytes: 100},
+			{StartKey: "62", EndKey: "63", WriteBytes: 300},
+			{StartKey: "63", EndKey: "64", WriteBytes: 200},
+		},
+	}
+	analysis := analyzeHotRegions(utils.Write, storeLoads, hotPeers, buckets, "disabled")
+	re.Equal("write", analysis.Type)
+	re.Equal(400.0, analysis.AverageByteRate)
+	re.Len(analysis.SkewedStores, 1)
+	re.Equal(uint64(1), analysis.SkewedStores[0].StoreID)
+	re.Equal(3, analysis.SkewedStores[0].HotRegionCount)
+	re.Len(analysis.HotRegions, 4)
+	re.Equal(uint64(10), analysis.HotRegions[0].RegionID)
+	re.Equal(0.6, analysis.HotRegions[0].Share)
+	re.Equal("63", analysis.HotRegions[0].SplitKey)
+
+	var actions, commands []string
+	for _, r := range analysis.Recommendations {
+		actions = append(actions, r.Action)
+		commands = append(commands, r.Command)
+	}
+	re.Equal([]string{HotActionSplit, HotActionScatter, HotActionScatter, HotActionScheduler}, actions)
+	re.Equal([]string{
+		"operator add split-region 10 --policy=usekey --keys=63",
+		"operator add scatter-region 11",
+		"operator add scatter-region 12",
+		"scheduler add balance-hot-region-scheduler",
+	}, commands)
+
+	// no recommendations if the load is balanced.
+	storeLoads = map[uint64]float64{1: 100, 2: 100, 3: 100}
+	hotPeers = statistics.StoreHotPeersStat{1: {Stats: []statistics.HotPeerStatShow{{RegionID: 10, ByteRate: 40}}}}
+	analysis = analyzeHotRegions(utils.Read, storeLoads, hotPeers, nil, "")
+	re.Empty(analysis.SkewedStores)
+	re.Len(analysis.HotRegions, 1)
+	re.Empty(analysis.HotRegions[0].SplitKey)
+	re.Empty(analysis.Recommendations)
+}
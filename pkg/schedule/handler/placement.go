@@ -0,0 +1,187 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
+	sche "github.com/tikv/pd/pkg/schedule/core"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/schedule/placement"
+)
+
+// RegionPlacement is the desired final placement of a region.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RegionPlacement struct {
+	Peers []*PlacementPeer `json:"peers"`
+	// DryRun only plans the steps without creating the operator.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// PlacementPeer is a peer of the desired placement.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type PlacementPeer struct {
+	StoreID uint64 `json:"store_id"`
+	// Role is one of voter, leader, follower and learner, it's voter by default.
+	Role placement.PeerRoleType `json:"role,omitempty"`
+}
+
+// PlacementPlan is the steps planned to reach the desired placement.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type PlacementPlan struct {
+	RegionID uint64   `json:"region_id"`
+	Steps    []string `json:"steps"`
+	// Created is false if it's a dry run or the region is already placed.
+	Created bool `json:"created"`
+}
+
+// Validate checks the desired placement.
+func (p *RegionPlacement) Validate() error {
+	if len(p.Peers) == 0 {
+		return errors.New("missing peers of the placement")
+	}
+	stores := make(map[uint64]struct{}, len(p.Peers))
+	var leaders, voters int
+	for _, peer := range p.Peers {
+		if peer == nil || peer.StoreID == 0 {
+			return errors.New("missing store id of the peer")
+		}
+		if _, ok := stores[peer.StoreID]; ok {
+			return errors.Errorf("duplicated peers in store %d", peer.StoreID)
+		}
+		stores[peer.StoreID] = struct{}{}
+		switch peer.Role {
+		case placement.Leader:
+			leaders++
+			voters++
+		case "", placement.Voter, placement.Follower:
+			voters++
+		case placement.Learner:
+		default:
+			return errors.Errorf("invalid role %q of the peer in store %d", peer.Role, peer.StoreID)
+		}
+	}
+	if leaders > 1 {
+		return errors.New("region cannot have multiple leaders")
+	}
+	if voters == 0 {
+		return errors.New("region must have at least one voter")
+	}
+	return nil
+}
+
+// AddRegionPlacementOperator plans the minimal steps to move the region to the
+// desired placement, and creates the operator unless it's a dry run.
+func (h *Handler) AddRegionPlacementOperator(regionID uint64, p *RegionPlacement) (*PlacementPlan, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	c := h.GetCluster()
+	if c == nil {
+		return nil, errs.ErrNotBootstrapped.GenWithStackByArgs()
+	}
+	region := c.GetRegion(regionID)
+	if region == nil {
+		return nil, errs.ErrRegionNotFound.FastGenByArgs(regionID)
+	}
+	for _, peer := range p.Peers {
+		if region.GetStorePeer(peer.StoreID) != nil {
+			continue
+		}
+		if err := checkStoreState(c, peer.StoreID); err != nil {
+			return nil, err
+		}
+	}
+
+	plan := &PlacementPlan{RegionID: regionID, Steps: []string{}}
+	if isRegionPlaced(region, p) {
+		return plan, nil
+	}
+	op, err := createPlacementOperator(c, region, p)
+	if err != nil {
+		log.Debug("fail to create placement operator", errs.ZapError(err))
+		return nil, err
+	}
+	for i := 0; i < op.Len(); i++ {
+		plan.Steps = append(plan.Steps, op.Step(i).String())
+	}
+	if p.DryRun {
+		return plan, nil
+	}
+	if err := h.addOperator(op); err != nil {
+		return nil, err
+	}
+	plan.Created = true
+	return plan, nil
+}
+
+// createPlacementOperator builds the operator with the same builder as the
+// rule checker, which picks the minimal steps to reach the target peers.
+func createPlacementOperator(c sche.SharedCluster, region *core.RegionInfo, p *RegionPlacement) (*operator.Operator, error) {
+	peers := make(map[uint64]*metapb.Peer, len(p.Peers))
+	roles := make(map[uint64]placement.PeerRoleType, len(p.Peers))
+	for _, peer := range p.Peers {
+		role := peer.Role
+		if role == "" {
+			role = placement.Voter
+		}
+		roles[peer.StoreID] = role
+		peers[peer.StoreID] = &metapb.Peer{
+			StoreId:   peer.StoreID,
+			Role:      role.MetaPeerRole(),
+			IsWitness: region.GetStorePeer(peer.StoreID).GetIsWitness(),
+		}
+	}
+	return operator.NewBuilder("admin-set-placement", c, region).
+		SetPeers(peers).
+		SetExpectedRoles(roles).
+		Build(operator.OpAdmin)
+}
+
+// isRegionPlaced checks whether the region already matches the placement, the
+// leader only matters if it's specified.
+func isRegionPlaced(region *core.RegionInfo, p *RegionPlacement) bool {
+	if len(region.GetPeers()) != len(p.Peers) {
+		return false
+	}
+	for _, peer := range p.Peers {
+		current := region.GetStorePeer(peer.StoreID)
+		if current == nil || core.IsInJointState(current) {
+			return false
+		}
+		switch peer.Role {
+		case placement.Learner:
+			if !core.IsLearner(current) {
+				return false
+			}
+		case placement.Leader:
+			if region.GetLeader().GetStoreId() != peer.StoreID {
+				return false
+			}
+		case placement.Follower:
+			if core.IsLearner(current) || region.GetLeader().GetStoreId() == peer.StoreID {
+				return false
+			}
+		default:
+			if core.IsLearner(current) {
+				return false
+			}
+		}
+	}
+	return true
+}
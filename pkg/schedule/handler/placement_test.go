@@ -0,0 +1,116 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/pkg/mock/mockconfig"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/schedule/placement"
+)
+
+func TestRegionPlacement(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := mockcluster.NewCluster(ctx, mockconfig.NewTestOptions())
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddRegionStore(i, 10)
+	}
+	region := tc.AddLeaderRegion(1, 1, 2, 3)
+
+	peers := func(roles ...placement.PeerRoleType) *RegionPlacement {
+		p := &RegionPlacement{}
+		for i, role := range roles {
+			p.Peers = append(p.Peers, &PlacementPeer{StoreID: uint64(i + 1), Role: role})
+		}
+		return p
+	}
+	re.Error((&RegionPlacement{}).Validate())
+	re.Error(peers(placement.Leader, placement.Leader).Validate())
+	re.Error(peers(placement.Learner).Validate())
+	re.Error(peers("witness").Validate())
+	re.NoError(peers("", placement.Follower, placement.Learner).Validate())
+
+	re.True(isRegionPlaced(region, peers("", "", "")))
+	re.True(isRegionPlaced(region, peers(placement.Leader, placement.Follower, "")))
+	re.False(isRegionPlaced(region, peers("", placement.Leader, "")))
+	re.False(isRegionPlaced(region, peers("", "", placement.Learner)))
+	re.False(isRegionPlaced(region, peers("", "", "", "")))
+
+	// only the leader is transferred.
+	op, err := createPlacementOperator(tc, region, peers(placement.Follower, placement.Leader, ""))
+	re.NoError(err)
+	re.Equal(1, op.Len())
+	re.Equal(operator.TransferLeader{FromStore: 1, ToStore: 2}, op.Step(0))
+
+	// the peer is moved from store 3 to store 4 and the leader is kept.
+	p := &RegionPlacement{Peers: []*PlacementPeer{{StoreID: 1, Role: placement.Leader}, {StoreID: 2}, {StoreID: 4}}}
+	op, err = createPlacementOperator(tc, region, p)
+	re.NoError(err)
+	re.Equal(operator.OpAdmin|operator.OpRegion, op.Kind())
+	re.Equal(4, op.Len())
+	re.Equal(operator.AddLearner{ToStore: 4, PeerID: 4, SendStore: 1}, op.Step(0))
+	re.Equal(operator.RemovePeer{FromStore: 3, PeerID: 3}, op.Step(3))
+}
+
+func TestScanRuleRanges(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tc := mockcluster.NewCluster(ctx, mockconfig.NewTestOptions())
+	tc.AddRegionStore(1, 10)
+	tc.AddLeaderRegionWithRange(1, "", "b", 1)
+	tc.AddLeaderRegionWithRange(2, "b", "d", 1)
+	tc.AddLeaderRegionWithRange(3, "d", "f", 1)
+	tc.AddLeaderRegionWithRange(4, "f", "", 1)
+
+	rules := []*placement.Rule{
+		{StartKey: []byte("b"), EndKey: []byte("c")},
+		{StartKey: []byte("a"), EndKey: []byte("bb")},
+		{StartKey: []byte("cc"), EndKey: []byte("e")},
+	}
+	ranges := getRuleRanges(rules)
+	re.Len(ranges, 2)
+	re.Equal([]byte("a"), ranges[0].StartKey)
+	re.Equal([]byte("c"), ranges[0].EndKey)
+	re.Equal([]byte("cc"), ranges[1].StartKey)
+	re.Equal([]byte("e"), ranges[1].EndKey)
+	// the range without the end key covers all the ranges after it.
+	ranges = getRuleRanges(append(rules, &placement.Rule{StartKey: []byte("bb")}))
+	re.Len(ranges, 1)
+	re.Empty(ranges[0].EndKey)
+
+	ids := func(regions []*core.RegionInfo) []uint64 {
+		var ids []uint64
+		for _, region := range regions {
+			ids = append(ids, region.GetID())
+		}
+		return ids
+	}
+	scan := scanKeyRanges(tc.ScanRegions, getRuleRanges(rules))
+	// region 2 is across the two ranges, it's only scanned once.
+	re.Equal([]uint64{1, 2, 3}, ids(scan(nil, nil, 0)))
+	re.Equal([]uint64{1, 2}, ids(scan(nil, nil, 2)))
+	re.Equal([]uint64{1, 2, 3}, ids(scan(nil, nil, 3)))
+	re.Equal([]uint64{2, 3}, ids(scan([]byte("b"), nil, 0)))
+	re.Equal([]uint64{3}, ids(scan([]byte("d"), nil, 0)))
+	re.Empty(scan([]byte("e"), nil, 0))
+}
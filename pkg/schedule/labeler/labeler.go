@@ -15,7 +15,11 @@
 package labeler
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,6 +29,7 @@ import (
 	"github.com/tikv/pd/pkg/schedule/rangelist"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/storage/kv"
+	"github.com/tikv/pd/pkg/utils/etcdutil"
 	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/tikv/pd/pkg/utils/syncutil"
 	"go.uber.org/zap"
@@ -106,6 +111,9 @@ func (l *RegionLabeler) checkAndClearExpiredLabels() {
 }
 
 func (l *RegionLabeler) loadRules() error {
+	if err := l.loadImportJournal(); err != nil {
+		return err
+	}
 	var toDelete []string
 	err := l.storage.LoadRegionRules(func(k, v string) {
 		r, err := NewLabelRuleFromJSON([]byte(v))
@@ -161,15 +169,20 @@ func (l *RegionLabeler) GetSplitKeys(start, end []byte) [][]byte {
 	return l.rangeList.GetSplitKeys(start, end)
 }
 
-// GetAllLabelRules returns all the rules.
+// GetAllLabelRules returns all the rules sorted by the ID.
 func (l *RegionLabeler) GetAllLabelRules() []*LabelRule {
 	l.checkAndClearExpiredLabels()
 	l.RLock()
 	defer l.RUnlock()
+	return l.getAllLabelRulesLocked()
+}
+
+func (l *RegionLabeler) getAllLabelRulesLocked() []*LabelRule {
 	rules := make([]*LabelRule, 0, len(l.labelRules))
 	for _, rule := range l.labelRules {
 		rules = append(rules, rule)
 	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
 	return rules
 }
 
@@ -268,6 +281,13 @@ func (l *RegionLabeler) DeleteLabelRuleLocked(id string) error {
 
 // Patch updates multiple region rules in a batch.
 func (l *RegionLabeler) Patch(patch LabelRulePatch) error {
+	return l.PatchIfMatch(patch, nil)
+}
+
+// PatchIfMatch updates multiple region rules in a batch if the check passes. The
+// check is called with all the current rules under the same lock as the update,
+// and the patch is aborted if it returns an error.
+func (l *RegionLabeler) PatchIfMatch(patch LabelRulePatch, check func(rules []*LabelRule) error) error {
 	// setRulesMap is used to solve duplicate entries in DeleteRules and SetRules.
 	// Note: We maintain compatibility with the previous behavior, which is to process DeleteRules before SetRules
 	// If there are duplicate rules, we will prioritize SetRules and select the last one from SetRules.
@@ -280,6 +300,14 @@ func (l *RegionLabeler) Patch(patch LabelRulePatch) error {
 		setRulesMap[rule.ID] = rule
 	}
 
+	l.Lock()
+	defer l.Unlock()
+	if check != nil {
+		if err := check(l.getAllLabelRulesLocked()); err != nil {
+			return err
+		}
+	}
+
 	// save to storage
 	var batch []func(kv.Txn) error
 	for _, key := range patch.DeleteRules {
@@ -302,9 +330,6 @@ func (l *RegionLabeler) Patch(patch LabelRulePatch) error {
 	}
 
 	// update in-memory states.
-	l.Lock()
-	defer l.Unlock()
-
 	for _, key := range patch.DeleteRules {
 		delete(l.labelRules, key)
 	}
@@ -315,6 +340,150 @@ func (l *RegionLabeler) Patch(patch LabelRulePatch) error {
 	return nil
 }
 
+// ImportLabelRules replaces all the label rules with the given rules. All the
+// rules are validated before anything is changed, and the difference is only
+// returned without being applied if dryRun is true. The changes are applied in
+// one transaction if they fit. Otherwise a journal to roll them back is saved
+// before they are applied in batches, so that an import failing halfway is
+// rolled back, either at once or on the next load.
+func (l *RegionLabeler) ImportLabelRules(rules []*LabelRule, dryRun bool) (*LabelRuleDiff, error) {
+	imported := make(map[string]*LabelRule, len(rules))
+	for _, rule := range rules {
+		if err := rule.checkAndAdjust(); err != nil {
+			return nil, err
+		}
+		if _, ok := imported[rule.ID]; ok {
+			return nil, errs.ErrRegionRuleContent.FastGenByArgs(fmt.Sprintf("duplicated rule id %s", rule.ID))
+		}
+		imported[rule.ID] = rule
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	diff := &LabelRuleDiff{Added: []string{}, Updated: []string{}, Deleted: []string{}, Applied: !dryRun}
+	journal := &labelRuleImportJournal{}
+	var saves []func(kv.Txn) error
+	for id, rule := range imported {
+		old, ok := l.labelRules[id]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, id)
+			journal.Added = append(journal.Added, id)
+		case !labelRuleEquals(old, rule):
+			diff.Updated = append(diff.Updated, id)
+			journal.Replaced = append(journal.Replaced, old)
+		default:
+			diff.Unchanged++
+			continue
+		}
+		saves = append(saves, l.saveRuleOp(rule))
+	}
+	for id, old := range l.labelRules {
+		if _, ok := imported[id]; !ok {
+			diff.Deleted = append(diff.Deleted, id)
+			saves = append(saves, l.deleteRuleOp(id))
+			journal.Replaced = append(journal.Replaced, old)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Updated)
+	sort.Strings(diff.Deleted)
+	if dryRun || len(saves) == 0 {
+		return diff, nil
+	}
+
+	if err := l.applyImport(saves, journal); err != nil {
+		return nil, err
+	}
+	l.labelRules = imported
+	l.BuildRangeListLocked()
+	log.Info("label rules are imported",
+		zap.Int("added", len(diff.Added)),
+		zap.Int("updated", len(diff.Updated)),
+		zap.Int("deleted", len(diff.Deleted)),
+		zap.Int("unchanged", diff.Unchanged))
+	return diff, nil
+}
+
+// labelRuleImportJournal records how to roll back an import which is too large
+// for one transaction.
+type labelRuleImportJournal struct {
+	// Replaced are the updated and deleted rules before the import.
+	Replaced []*LabelRule `json:"replaced"`
+	// Added are the IDs of the rules which do not exist before the import.
+	Added []string `json:"added"`
+}
+
+func (l *RegionLabeler) applyImport(saves []func(kv.Txn) error, journal *labelRuleImportJournal) error {
+	if len(saves) <= etcdutil.MaxEtcdTxnOps {
+		return endpoint.RunBatchOpInTxn(l.ctx, l.storage, saves)
+	}
+	if err := l.storage.RunInTxn(l.ctx, func(txn kv.Txn) error {
+		return l.storage.SaveRegionRuleImport(txn, journal)
+	}); err != nil {
+		return err
+	}
+	err := endpoint.RunBatchOpInTxn(l.ctx, l.storage, saves)
+	if err == nil {
+		err = l.storage.RunInTxn(l.ctx, l.storage.DeleteRegionRuleImport)
+	}
+	if err != nil {
+		if rerr := l.rollbackImport(journal); rerr != nil {
+			log.Error("failed to roll back the label rule import, it will be rolled back on the next load", errs.ZapError(rerr))
+		}
+		return err
+	}
+	return nil
+}
+
+// rollbackImport restores the rules recorded in the journal and removes it.
+func (l *RegionLabeler) rollbackImport(journal *labelRuleImportJournal) error {
+	restores := make([]func(kv.Txn) error, 0, len(journal.Replaced)+len(journal.Added))
+	for _, rule := range journal.Replaced {
+		restores = append(restores, l.saveRuleOp(rule))
+	}
+	for _, id := range journal.Added {
+		restores = append(restores, l.deleteRuleOp(id))
+	}
+	if err := endpoint.RunBatchOpInTxn(l.ctx, l.storage, restores); err != nil {
+		return err
+	}
+	return l.storage.RunInTxn(l.ctx, l.storage.DeleteRegionRuleImport)
+}
+
+// loadImportJournal rolls back the import which is interrupted halfway.
+func (l *RegionLabeler) loadImportJournal() error {
+	value, err := l.storage.LoadRegionRuleImport()
+	if err != nil || value == "" {
+		return err
+	}
+	journal := &labelRuleImportJournal{}
+	if err := json.Unmarshal([]byte(value), journal); err != nil {
+		return errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByCause()
+	}
+	log.Warn("roll back the interrupted label rule import",
+		zap.Int("replaced", len(journal.Replaced)), zap.Int("added", len(journal.Added)))
+	return l.rollbackImport(journal)
+}
+
+func (l *RegionLabeler) saveRuleOp(rule *LabelRule) func(kv.Txn) error {
+	return func(txn kv.Txn) error {
+		return l.storage.SaveRegionRule(txn, rule.ID, rule)
+	}
+}
+
+func (l *RegionLabeler) deleteRuleOp(id string) func(kv.Txn) error {
+	return func(txn kv.Txn) error {
+		return l.storage.DeleteRegionRule(txn, id)
+	}
+}
+
+func labelRuleEquals(a, b *LabelRule) bool {
+	aa, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+	return bytes.Equal(aa, bb)
+}
+
 // GetRegionLabel returns the label of the region for a key.
 // If there are multiple rules that match the key, the one with max rule index will be returned.
 func (l *RegionLabeler) GetRegionLabel(region *core.RegionInfo, key string) string {
@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strconv"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/storage"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/storage/kv"
@@ -107,9 +109,8 @@ func TestGetSetRule(t *testing.T) {
 		re.NoError(err)
 	}
 
-	allRules := labeler.GetAllLabelRules()
-	sort.Slice(allRules, func(i, j int) bool { return allRules[i].ID < allRules[j].ID })
-	re.Equal(rules, allRules)
+	// the rules are sorted by the ID.
+	re.Equal(rules, labeler.GetAllLabelRules())
 
 	byIDs, err := labeler.GetLabelRules([]string{"rule3", "rule1"})
 	re.NoError(err)
@@ -131,18 +132,117 @@ func TestGetSetRule(t *testing.T) {
 	}
 	err = labeler.Patch(patch)
 	re.NoError(err)
-	allRules = labeler.GetAllLabelRules()
-	sort.Slice(allRules, func(i, j int) bool { return allRules[i].ID < allRules[j].ID })
-	for id, rule := range allRules {
+	for id, rule := range labeler.GetAllLabelRules() {
 		expectSameRules(re, rule, rules[id+1])
 	}
 
+	// the conditional patch is aborted if the check fails.
+	checkErr := errors.New("modified")
+	patch = LabelRulePatch{DeleteRules: []string{"rule2"}}
+	err = labeler.PatchIfMatch(patch, func(current []*LabelRule) error {
+		re.Len(current, 2)
+		return checkErr
+	})
+	re.ErrorIs(err, checkErr)
+	re.NotNil(labeler.GetLabelRule("rule2"))
+	re.NoError(labeler.PatchIfMatch(patch, func([]*LabelRule) error { return nil }))
+	re.Nil(labeler.GetLabelRule("rule2"))
+
 	for _, r := range rules {
 		labeler.DeleteLabelRule(r.ID)
 	}
 	re.Empty(labeler.GetAllLabelRules())
 }
 
+func TestImportLabelRules(t *testing.T) {
+	re := require.New(t)
+	store := endpoint.NewStorageEndpoint(kv.NewMemoryKV(), nil)
+	labeler, err := NewRegionLabeler(context.Background(), store, time.Millisecond*10)
+	re.NoError(err)
+	for _, r := range []*LabelRule{
+		{ID: "rule1", Labels: []RegionLabel{{Key: "k1", Value: "v1"}}, RuleType: "key-range", Data: MakeKeyRanges("1234", "5678")},
+		{ID: "rule2", Labels: []RegionLabel{{Key: "k2", Value: "v2"}}, RuleType: "key-range", Data: MakeKeyRanges("ab12", "cd12")},
+		{ID: "rule3", Labels: []RegionLabel{{Key: "k3", Value: "v3"}}, RuleType: "key-range", Data: MakeKeyRanges("abcd", "efef")},
+	} {
+		re.NoError(labeler.SetLabelRule(r))
+	}
+	newRules := func() []*LabelRule {
+		return []*LabelRule{
+			{ID: "rule1", Labels: []RegionLabel{{Key: "k1", Value: "v1"}}, RuleType: "key-range", Data: MakeKeyRanges("1234", "5678")},
+			{ID: "rule2", Labels: []RegionLabel{{Key: "k2", Value: "v3"}}, RuleType: "key-range", Data: MakeKeyRanges("ab12", "cd12")},
+			{ID: "rule4", Labels: []RegionLabel{{Key: "k4", Value: "v4"}}, RuleType: "key-range", Data: MakeKeyRanges("f000", "f100")},
+		}
+	}
+
+	// the invalid rules are rejected as a whole.
+	invalid := append(newRules(), &LabelRule{ID: "rule5", RuleType: "key-range", Data: MakeKeyRanges("f200", "f300")})
+	_, err = labeler.ImportLabelRules(invalid, false)
+	re.Error(err)
+	duplicated := append(newRules(), newRules()[0])
+	_, err = labeler.ImportLabelRules(duplicated, false)
+	re.Error(err)
+	re.Len(labeler.GetAllLabelRules(), 3)
+
+	// the dry run only returns the difference.
+	expected := &LabelRuleDiff{Added: []string{"rule4"}, Updated: []string{"rule2"}, Deleted: []string{"rule3"}, Unchanged: 1}
+	diff, err := labeler.ImportLabelRules(newRules(), true)
+	re.NoError(err)
+	re.Equal(expected, diff)
+	re.NotNil(labeler.GetLabelRule("rule3"))
+
+	expected.Applied = true
+	diff, err = labeler.ImportLabelRules(newRules(), false)
+	re.NoError(err)
+	re.Equal(expected, diff)
+	re.Nil(labeler.GetLabelRule("rule3"))
+	re.Equal("v3", labeler.GetLabelRule("rule2").Labels[0].Value)
+	re.Equal("v4", labeler.GetRegionLabel(core.NewTestRegionInfo(1, 1, []byte{0xf0, 0x10}, []byte{0xf0, 0x20}), "k4"))
+
+	// the imported rules are persisted.
+	labeler2, err := NewRegionLabeler(context.Background(), store, time.Millisecond*10)
+	re.NoError(err)
+	re.Len(labeler2.GetAllLabelRules(), 3)
+	re.Nil(labeler2.GetLabelRule("rule3"))
+	re.NotNil(labeler2.GetLabelRule("rule4"))
+
+	// the import interrupted halfway is rolled back on the next load.
+	rule4 := labeler2.GetLabelRule("rule4")
+	rule5 := &LabelRule{ID: "rule5", Labels: []RegionLabel{{Key: "k5", Value: "v5"}}, RuleType: "key-range", Data: MakeKeyRanges("f200", "f300")}
+	re.NoError(store.RunInTxn(context.Background(), func(txn kv.Txn) error {
+		if err := store.SaveRegionRuleImport(txn, &labelRuleImportJournal{Replaced: []*LabelRule{rule4}, Added: []string{"rule5"}}); err != nil {
+			return err
+		}
+		if err := store.DeleteRegionRule(txn, "rule4"); err != nil {
+			return err
+		}
+		return store.SaveRegionRule(txn, "rule5", rule5)
+	}))
+	labeler3, err := NewRegionLabeler(context.Background(), store, time.Millisecond*10)
+	re.NoError(err)
+	re.Len(labeler3.GetAllLabelRules(), 3)
+	re.NotNil(labeler3.GetLabelRule("rule4"))
+	re.Nil(labeler3.GetLabelRule("rule5"))
+	journal, err := store.LoadRegionRuleImport()
+	re.NoError(err)
+	re.Empty(journal)
+
+	// the import too large for one transaction is applied in batches.
+	many := make([]*LabelRule, 0, 2*etcdutil.MaxEtcdTxnOps)
+	for i := 0; i < 2*etcdutil.MaxEtcdTxnOps; i++ {
+		many = append(many, &LabelRule{ID: fmt.Sprintf("rule-%03d", i), Labels: []RegionLabel{{Key: "k", Value: "v"}}, RuleType: "key-range", Data: MakeKeyRanges(fmt.Sprintf("a%03d", i), fmt.Sprintf("b%03d", i))})
+	}
+	diff, err = labeler3.ImportLabelRules(many, false)
+	re.NoError(err)
+	re.Len(diff.Added, 2*etcdutil.MaxEtcdTxnOps)
+	re.Len(diff.Deleted, 3)
+	journal, err = store.LoadRegionRuleImport()
+	re.NoError(err)
+	re.Empty(journal)
+	labeler4, err := NewRegionLabeler(context.Background(), store, time.Millisecond*10)
+	re.NoError(err)
+	re.Len(labeler4.GetAllLabelRules(), 2*etcdutil.MaxEtcdTxnOps)
+}
+
 func TestTxnWithEtcd(t *testing.T) {
 	re := require.New(t)
 	_, client, clean := etcdutil.NewTestEtcdCluster(t, 1)
@@ -338,6 +438,38 @@ func TestKeyRange(t *testing.T) {
 	}
 }
 
+func TestRangeOverrides(t *testing.T) {
+	re := require.New(t)
+	store := endpoint.NewStorageEndpoint(kv.NewMemoryKV(), nil)
+	labeler, err := NewRegionLabeler(context.Background(), store, time.Millisecond*10)
+	re.NoError(err)
+	rules := []*LabelRule{
+		{ID: "rule1", Labels: []RegionLabel{{Key: MergeOptionLabel, Value: MergeOptionValueDeny}, {Key: MaxPendingPeerCountLabel, Value: "8"}},
+			RuleType: "key-range", Data: MakeKeyRanges("1234", "5678")},
+		{ID: "rule2", Labels: []RegionLabel{{Key: LeaderSchedulePolicyLabel, Value: "size"}, {Key: MaxPendingPeerCountLabel, Value: "x"}},
+			RuleType: "key-range", Data: MakeKeyRanges("ab12", "cd12")},
+	}
+	for _, r := range rules {
+		re.NoError(labeler.SetLabelRule(r))
+	}
+
+	start, _ := hex.DecodeString("1234")
+	end, _ := hex.DecodeString("5678")
+	overrides := labeler.GetRangeOverrides(core.NewTestRegionInfo(1, 1, start, end))
+	re.True(overrides.MergeDisabled)
+	re.Equal(uint64(8), *overrides.MaxPendingPeerCount)
+	re.Nil(overrides.LeaderSchedulePolicy)
+	re.Equal(constant.ByCount, overrides.GetLeaderSchedulePolicy(constant.ByCount))
+
+	// the invalid value is ignored.
+	start, _ = hex.DecodeString("ab12")
+	end, _ = hex.DecodeString("cd12")
+	overrides = labeler.GetRangeOverrides(core.NewTestRegionInfo(2, 1, start, end))
+	re.False(overrides.MergeDisabled)
+	re.Nil(overrides.MaxPendingPeerCount)
+	re.Equal(constant.BySize, overrides.GetLeaderSchedulePolicy(constant.ByCount))
+}
+
 func TestLabelerRuleTTL(t *testing.T) {
 	re := require.New(t)
 	store := endpoint.NewStorageEndpoint(kv.NewMemoryKV(), nil)
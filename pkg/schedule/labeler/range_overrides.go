@@ -0,0 +1,71 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package labeler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/core/constant"
+	"github.com/tikv/pd/pkg/schedule/config"
+	"go.uber.org/zap"
+)
+
+// The labels used to override the schedule parameters for the key ranges.
+const (
+	// MergeOptionLabel is the label to disable merging the regions with `merge_option=deny`.
+	// If label value is `allow` or other value, it will be treated as `allow`.
+	MergeOptionLabel = "merge_option"
+	// MergeOptionValueDeny is the value of MergeOptionLabel to disable merging.
+	MergeOptionValueDeny = "deny"
+	// MaxPendingPeerCountLabel is the label to override `max-pending-peer-count`.
+	MaxPendingPeerCountLabel = "max_pending_peer_count"
+	// LeaderSchedulePolicyLabel is the label to override `leader-schedule-policy`.
+	LeaderSchedulePolicyLabel = "leader_schedule_policy"
+)
+
+// GetRangeOverrides returns the schedule parameters overridden for the key range
+// of the region. The labels with invalid values are ignored.
+func (l *RegionLabeler) GetRangeOverrides(region *core.RegionInfo) *config.RangeOverrides {
+	overrides := &config.RangeOverrides{}
+	for _, label := range l.GetRegionLabels(region) {
+		switch label.Key {
+		case MergeOptionLabel:
+			overrides.MergeDisabled = label.Value == MergeOptionValueDeny
+		case MaxPendingPeerCountLabel:
+			count, err := strconv.ParseUint(label.Value, 10, 64)
+			if err != nil {
+				log.Debug("invalid max pending peer count label", zap.Uint64("region-id", region.GetID()), zap.String("value", label.Value))
+				continue
+			}
+			overrides.MaxPendingPeerCount = &count
+		case LeaderSchedulePolicyLabel:
+			var policy constant.SchedulePolicy
+			switch strings.ToLower(label.Value) {
+			case constant.ByCount.String():
+				policy = constant.ByCount
+			case constant.BySize.String():
+				policy = constant.BySize
+			default:
+				log.Debug("invalid leader schedule policy label", zap.Uint64("region-id", region.GetID()), zap.String("value", label.Value))
+				continue
+			}
+			overrides.LeaderSchedulePolicy = &policy
+		}
+	}
+	return overrides
+}
@@ -121,6 +121,17 @@ type LabelRulePatch struct {
 	DeleteRules []string     `json:"deletes"`
 }
 
+// LabelRuleDiff is the difference between the imported label rules and the current ones.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type LabelRuleDiff struct {
+	Added     []string `json:"added"`
+	Updated   []string `json:"updated"`
+	Deleted   []string `json:"deleted"`
+	Unchanged int      `json:"unchanged"`
+	// Applied indicates whether the imported rules have taken effect, it's false for the dry run.
+	Applied bool `json:"applied"`
+}
+
 func (l *RegionLabel) expireBefore(t time.Time) bool {
 	failpoint.Inject("regionLabelExpireSub1Minute", func() {
 		if l.expire != nil {
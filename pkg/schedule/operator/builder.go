@@ -922,7 +922,7 @@ func (b *Builder) allowLeader(peer *metapb.Peer, ignoreClusterLimit bool) bool {
 	}
 	for _, r := range b.rules {
 		if (r.Role == placement.Leader || r.Role == placement.Voter) &&
-			placement.MatchLabelConstraints(store, r.LabelConstraints) {
+			r.MatchStore(store) {
 			return true
 		}
 	}
@@ -0,0 +1,106 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"sort"
+
+	"github.com/tikv/pd/pkg/core/constant"
+	"github.com/tikv/pd/pkg/core/storelimit"
+)
+
+// WaitingQueueState is the state of the waiting operators of a priority level.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type WaitingQueueState struct {
+	PriorityLevel string      `json:"priority_level"`
+	Operators     []*Operator `json:"operators"`
+}
+
+// StoreLimitState is the token state of a store limit.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreLimitState struct {
+	StoreID uint64 `json:"store_id"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+	// RatePerSec and Tokens are for the rate limit, the zero rate means no limit.
+	RatePerSec float64 `json:"rate_per_sec,omitempty"`
+	Tokens     float64 `json:"tokens,omitempty"`
+	// Capacity and Used are for the sliding windows, Used is the used size of
+	// the windows from the lowest priority level to the highest.
+	Capacity int64   `json:"capacity,omitempty"`
+	Used     []int64 `json:"used,omitempty"`
+}
+
+// ControllerState is a snapshot of the operator controller for introspection.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ControllerState struct {
+	Running []*Operator          `json:"running"`
+	Waiting []*WaitingQueueState `json:"waiting"`
+	// WaitingCounts is the number of the waiting operators of each description,
+	// which is limited by the scheduler-max-waiting-operator.
+	WaitingCounts map[string]uint64  `json:"waiting_counts"`
+	StoreLimits   []*StoreLimitState `json:"store_limits"`
+}
+
+// GetState returns the running operators, the waiting queues and the token
+// states of the store limits.
+func (oc *Controller) GetState() *ControllerState {
+	state := &ControllerState{
+		Running:       oc.GetOperators(),
+		WaitingCounts: oc.wopStatus.getCounts(),
+	}
+	sort.Slice(state.Running, func(i, j int) bool {
+		return state.Running[i].RegionID() < state.Running[j].RegionID()
+	})
+	queues := make([]*WaitingQueueState, constant.PriorityLevelLen)
+	for level := constant.Low; level < constant.PriorityLevelLen; level++ {
+		queues[level] = &WaitingQueueState{PriorityLevel: level.String(), Operators: make([]*Operator, 0)}
+	}
+	for _, op := range oc.GetWaitingOperators() {
+		if level := op.GetPriorityLevel(); level < constant.PriorityLevelLen {
+			queues[level].Operators = append(queues[level].Operators, op)
+		}
+	}
+	state.Waiting = queues
+	for _, store := range oc.cluster.GetStores() {
+		if store.IsRemoved() {
+			continue
+		}
+		switch limit := store.GetStoreLimit().(type) {
+		case *storelimit.StoreRateLimit:
+			for _, typ := range []storelimit.Type{storelimit.AddPeer, storelimit.RemovePeer} {
+				state.StoreLimits = append(state.StoreLimits, &StoreLimitState{
+					StoreID:    store.GetID(),
+					Type:       typ.String(),
+					Version:    limit.Version(),
+					RatePerSec: limit.Rate(typ),
+					Tokens:     limit.Tokens(typ),
+				})
+			}
+		case *storelimit.SlidingWindows:
+			state.StoreLimits = append(state.StoreLimits, &StoreLimitState{
+				StoreID:  store.GetID(),
+				Type:     storelimit.SendSnapshot.String(),
+				Version:  limit.Version(),
+				Capacity: limit.GetCap(),
+				Used:     limit.GetUsed(),
+			})
+		}
+	}
+	sort.SliceStable(state.StoreLimits, func(i, j int) bool {
+		return state.StoreLimits[i].StoreID < state.StoreLimits[j].StoreID
+	})
+	return state
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+const (
+	// maxKeyspaceMetricsLabels caps the number of the keyspaces which have their
+	// own label in the metrics to avoid the high cardinality.
+	maxKeyspaceMetricsLabels = 64
+	// otherKeyspaceLabel is the label of the keyspaces beyond the cap.
+	otherKeyspaceLabel = "other"
+)
+
+var keyspaceLabels = newKeyspaceLabeler(maxKeyspaceMetricsLabels)
+
+type keyspaceLabeler struct {
+	syncutil.RWMutex
+	capacity int
+	labels   map[uint32]string
+}
+
+func newKeyspaceLabeler(capacity int) *keyspaceLabeler {
+	return &keyspaceLabeler{capacity: capacity, labels: make(map[uint32]string)}
+}
+
+func (l *keyspaceLabeler) label(id uint32) string {
+	l.RLock()
+	label, ok := l.labels[id]
+	full := len(l.labels) >= l.capacity
+	l.RUnlock()
+	if ok {
+		return label
+	}
+	if full {
+		return otherKeyspaceLabel
+	}
+	l.Lock()
+	defer l.Unlock()
+	if label, ok := l.labels[id]; ok {
+		return label
+	}
+	if len(l.labels) >= l.capacity {
+		return otherKeyspaceLabel
+	}
+	label = strconv.FormatUint(uint64(id), 10)
+	l.labels[id] = label
+	return label
+}
+
+// KeyspaceMetricsLabel returns the keyspace label of the region used by the metrics.
+// The second return value is false if the region doesn't belong to any keyspace,
+// so the per keyspace metrics are only recorded when the keyspaces are in use.
+func KeyspaceMetricsLabel(region *core.RegionInfo) (string, bool) {
+	if region == nil {
+		return "", false
+	}
+	id, ok := codec.Key(region.GetStartKey()).KeyspaceID()
+	if !ok {
+		return "", false
+	}
+	return keyspaceLabels.label(id), true
+}
+
+// recordKeyspaceOperator records the operator event of the keyspace which the region belongs to.
+func (oc *Controller) recordKeyspaceOperator(op *Operator, event string) {
+	if label, ok := KeyspaceMetricsLabel(oc.cluster.GetRegion(op.RegionID())); ok {
+		keyspaceOperatorCounter.WithLabelValues(label, op.Desc(), strings.ToLower(event)).Inc()
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/pkg/core"
+)
+
+func TestKeyspaceMetricsLabel(t *testing.T) {
+	re := require.New(t)
+	newRegion := func(startKey []byte) *core.RegionInfo {
+		return core.NewRegionInfo(&metapb.Region{Id: 1, StartKey: startKey}, nil)
+	}
+	_, ok := KeyspaceMetricsLabel(newRegion([]byte("t\x80")))
+	re.False(ok)
+	_, ok = KeyspaceMetricsLabel(nil)
+	re.False(ok)
+	label, ok := KeyspaceMetricsLabel(newRegion(codec.EncodeBytes([]byte("x\x00\x00\x05"))))
+	re.True(ok)
+	re.Equal("5", label)
+
+	labeler := newKeyspaceLabeler(2)
+	re.Equal("1", labeler.label(1))
+	re.Equal("2", labeler.label(2))
+	re.Equal(otherKeyspaceLabel, labeler.label(3))
+	re.Equal("1", labeler.label(1))
+}
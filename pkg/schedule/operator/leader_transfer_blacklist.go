@@ -0,0 +1,140 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// leaderTransferFailureThreshold is the number of the consecutive failures
+	// after which the store is excluded as the leader target.
+	leaderTransferFailureThreshold = 2
+	// maxLeaderTransferBackoffShift limits the exclusion window to 32 times of the base window.
+	maxLeaderTransferBackoffShift = 5
+)
+
+// LeaderTransferExclusion is a store which is temporarily excluded as the leader target.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type LeaderTransferExclusion struct {
+	StoreID       uint64    `json:"store_id"`
+	Failures      int       `json:"failures"`
+	ExcludedUntil time.Time `json:"excluded_until"`
+}
+
+type leaderTransferFailure struct {
+	failures      int
+	excludedUntil time.Time
+}
+
+// leaderTransferBlacklist records the stores which fail to accept the leader transfers.
+type leaderTransferBlacklist struct {
+	syncutil.RWMutex
+	stores map[uint64]*leaderTransferFailure
+}
+
+func newLeaderTransferBlacklist() *leaderTransferBlacklist {
+	return &leaderTransferBlacklist{stores: make(map[uint64]*leaderTransferFailure)}
+}
+
+// recordFailure records a failed leader transfer to the store, the store will be
+// excluded with exponential backoff once the failures reach the threshold.
+func (b *leaderTransferBlacklist) recordFailure(storeID uint64, window time.Duration, now time.Time) {
+	if window <= 0 {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	f, ok := b.stores[storeID]
+	if !ok {
+		f = &leaderTransferFailure{}
+		b.stores[storeID] = f
+	}
+	f.failures++
+	if f.failures < leaderTransferFailureThreshold {
+		return
+	}
+	shift := f.failures - leaderTransferFailureThreshold
+	if shift > maxLeaderTransferBackoffShift {
+		shift = maxLeaderTransferBackoffShift
+	}
+	f.excludedUntil = now.Add(window << shift)
+	leaderTransferBlacklistCounter.Inc()
+	log.Info("exclude the store as the leader target",
+		zap.Uint64("store-id", storeID),
+		zap.Int("failures", f.failures),
+		zap.Time("excluded-until", f.excludedUntil))
+}
+
+// recordSuccess resets the failures of the store.
+func (b *leaderTransferBlacklist) recordSuccess(storeID uint64) {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.stores, storeID)
+}
+
+// isExcluded returns whether the store is excluded as the leader target now.
+func (b *leaderTransferBlacklist) isExcluded(storeID uint64, now time.Time) bool {
+	b.RLock()
+	defer b.RUnlock()
+	f, ok := b.stores[storeID]
+	return ok && now.Before(f.excludedUntil)
+}
+
+// excludedStores returns the IDs of the stores which are excluded now.
+func (b *leaderTransferBlacklist) excludedStores(now time.Time) map[uint64]struct{} {
+	b.RLock()
+	defer b.RUnlock()
+	stores := make(map[uint64]struct{})
+	for storeID, f := range b.stores {
+		if now.Before(f.excludedUntil) {
+			stores[storeID] = struct{}{}
+		}
+	}
+	return stores
+}
+
+// getExclusions returns the stores which are excluded now sorted by the store ID.
+func (b *leaderTransferBlacklist) getExclusions(now time.Time) []LeaderTransferExclusion {
+	b.RLock()
+	defer b.RUnlock()
+	exclusions := make([]LeaderTransferExclusion, 0, len(b.stores))
+	for storeID, f := range b.stores {
+		if now.Before(f.excludedUntil) {
+			exclusions = append(exclusions, LeaderTransferExclusion{
+				StoreID:       storeID,
+				Failures:      f.failures,
+				ExcludedUntil: f.excludedUntil,
+			})
+		}
+	}
+	sort.Slice(exclusions, func(i, j int) bool {
+		return exclusions[i].StoreID < exclusions[j].StoreID
+	})
+	return exclusions
+}
+
+// leaderTargets returns the target stores of the transfer leader step.
+func leaderTargets(step TransferLeader) []uint64 {
+	if len(step.ToStores) > 0 {
+		return step.ToStores
+	}
+	return []uint64{step.ToStore}
+}
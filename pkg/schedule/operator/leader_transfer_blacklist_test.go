@@ -0,0 +1,62 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderTransferBlacklist(t *testing.T) {
+	re := require.New(t)
+	b := newLeaderTransferBlacklist()
+	now := time.Now()
+	window := time.Minute
+
+	// the first failure doesn't exclude the store.
+	b.recordFailure(1, window, now)
+	re.False(b.isExcluded(1, now))
+	re.Empty(b.getExclusions(now))
+
+	b.recordFailure(1, window, now)
+	re.True(b.isExcluded(1, now))
+	re.False(b.isExcluded(1, now.Add(window)))
+
+	// the window grows exponentially.
+	b.recordFailure(1, window, now)
+	re.True(b.isExcluded(1, now.Add(window)))
+	re.False(b.isExcluded(1, now.Add(2*window)))
+	for i := 0; i < 10; i++ {
+		b.recordFailure(1, window, now)
+	}
+	re.True(b.isExcluded(1, now.Add(31*window)))
+	re.False(b.isExcluded(1, now.Add(32*window)))
+
+	exclusions := b.getExclusions(now)
+	re.Len(exclusions, 1)
+	re.Equal(uint64(1), exclusions[0].StoreID)
+	re.Equal(13, exclusions[0].Failures)
+
+	// disabled if the window is zero.
+	b.recordFailure(2, 0, now)
+	b.recordFailure(2, 0, now)
+	re.False(b.isExcluded(2, now))
+
+	b.recordSuccess(1)
+	re.False(b.isExcluded(1, now))
+	re.Empty(b.getExclusions(now))
+}
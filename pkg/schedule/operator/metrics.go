@@ -52,6 +52,15 @@ var (
 			Help:      "Counter of operator meeting store limit",
 		}, []string{"desc"})
 
+	// OperatorExceededLabelDomainLimitCounter exposes the counter when operator meet exceeded label domain limit.
+	OperatorExceededLabelDomainLimitCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "operator_exceeded_label_domain_limit",
+			Help:      "Counter of operator meeting label domain limit",
+		}, []string{"desc", "domain"})
+
 	// TODO: pre-allocate gauge metrics
 	operatorCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -61,6 +70,14 @@ var (
 			Help:      "Counter of schedule operators.",
 		}, []string{"type", "event"})
 
+	keyspaceOperatorCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "keyspace_operators_count",
+			Help:      "Counter of schedule operators per keyspace.",
+		}, []string{"keyspace", "type", "event"})
+
 	operatorDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "pd",
@@ -78,14 +95,34 @@ var (
 			Help:      "Bucketed histogram of the operator region size.",
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 20), // 1MB~1TB
 		}, []string{"type"})
+
+	leaderTransferBlacklistCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "leader_transfer_blacklist_total",
+			Help:      "Counter of the stores excluded as the leader target after failed leader transfers.",
+		})
+
+	snapshotBudgetGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "snapshot_budget",
+			Help:      "The add peer rate per minute of the store allocated by the cluster snapshot bandwidth.",
+		}, []string{"store"})
 )
 
 func init() {
 	prometheus.MustRegister(operatorStepDuration)
 	prometheus.MustRegister(OperatorLimitCounter)
 	prometheus.MustRegister(OperatorExceededStoreLimitCounter)
+	prometheus.MustRegister(OperatorExceededLabelDomainLimitCounter)
 	prometheus.MustRegister(operatorCounter)
+	prometheus.MustRegister(keyspaceOperatorCounter)
 	prometheus.MustRegister(operatorDuration)
 	prometheus.MustRegister(operatorSizeHist)
 	prometheus.MustRegister(storeLimitCostCounter)
+	prometheus.MustRegister(leaderTransferBlacklistCounter)
+	prometheus.MustRegister(snapshotBudgetGauge)
 }
@@ -33,6 +33,9 @@ const (
 	// after it, the operator will be considered expired.
 	OperatorExpireTime = 3 * time.Second
 	cancelReason       = "cancel-reason"
+	// learnerCatchUpTime is the additional info of the duration for the learner
+	// to receive the snapshot and catch up with the leader.
+	learnerCatchUpTime = "learner-catch-up-time"
 )
 
 // CancelReasonType is the type of cancel reason.
@@ -59,8 +62,12 @@ var (
 	StaleStatus CancelReasonType = "stale status"
 	// ExceedStoreLimit is the cancel reason when the operator exceeds the store limit.
 	ExceedStoreLimit CancelReasonType = "exceed store limit"
+	// ExceedLabelDomainLimit is the cancel reason when the operator exceeds the operator limit of a label domain.
+	ExceedLabelDomainLimit CancelReasonType = "exceed label domain limit"
 	// ExceedWaitLimit is the cancel reason when the operator exceeds the waiting queue limit.
 	ExceedWaitLimit CancelReasonType = "exceed wait limit"
+	// LearnerCatchUpTimeout is the cancel reason when the learner fails to catch up in time.
+	LearnerCatchUpTimeout CancelReasonType = "learner catch-up timeout"
 	// RelatedMergeRegion is the cancel reason when the operator is cancelled by related merge region.
 	RelatedMergeRegion CancelReasonType = "related merge region"
 	// Unknown is the cancel reason when the operator is cancelled by an unknown reason.
@@ -365,6 +372,21 @@ func (o *Operator) getCurrentTimeAndStep() (startTime time.Time, opStep OpStep)
 	return
 }
 
+// GetLearnerCatchUpTime returns the store of the learner being added by the
+// current step and how long it has been catching up. ok is false if the current
+// step doesn't add a learner which needs the snapshot.
+func (o *Operator) GetLearnerCatchUpTime(now time.Time) (storeID uint64, elapsed time.Duration, ok bool) {
+	if !o.HasStarted() {
+		return 0, 0, false
+	}
+	startTime, step := o.getCurrentTimeAndStep()
+	al, ok := step.(AddLearner)
+	if !ok || al.IsWitness {
+		return 0, 0, false
+	}
+	return al.ToStore, now.Sub(startTime), true
+}
+
 // Check checks if current step is finished, returns next step to take action.
 // If operator is at an end status, check returns nil.
 // It's safe to be called by multiple goroutine concurrently.
@@ -381,6 +403,9 @@ func (o *Operator) Check(region *core.RegionInfo) OpStep {
 				startTime, _ := o.getCurrentTimeAndStep()
 				operatorStepDuration.WithLabelValues(reflect.TypeOf(o.steps[int(step)]).Name()).
 					Observe(current.Sub(startTime).Seconds())
+				if al, ok := o.steps[int(step)].(AddLearner); ok && !al.IsWitness {
+					o.SetAdditionalInfo(learnerCatchUpTime, current.Sub(startTime).String())
+				}
 			}
 			atomic.StoreInt32(&o.currentStep, step+1)
 		} else {
@@ -50,6 +50,9 @@ var (
 	StoreBalanceBaseTime float64 = 60
 	// FastOperatorFinishTime min finish time, if finish duration less than it, op will be pushed to fast operator queue
 	FastOperatorFinishTime = 10 * time.Second
+	// LearnerCatchUpRetryWindow is the duration to avoid adding the learner to the
+	// same store again after it fails to catch up in time.
+	LearnerCatchUpRetryWindow = 10 * time.Minute
 )
 
 type opCounter struct {
@@ -97,6 +100,13 @@ type Controller struct {
 	wop       WaitingOperator
 	wopStatus *waitingOperatorStatus
 	counts    *opCounter
+	// leaderBlacklist records the stores failing to accept the leader transfers.
+	leaderBlacklist *leaderTransferBlacklist
+	// learnerCatchUpTimeouts records the store of the learner which failed to
+	// catch up in time for each region, so the checkers can retry elsewhere.
+	learnerCatchUpTimeouts *cache.TTLUint64
+	// snapshotBudget records the add peer rates allocated by the cluster snapshot bandwidth.
+	snapshotBudget snapshotBudget
 }
 
 // NewController creates a Controller.
@@ -113,6 +123,10 @@ func NewController(ctx context.Context, cluster *core.BasicCluster, config confi
 		wop:       newRandBuckets(),
 		wopStatus: newWaitingOperatorStatus(),
 		counts:    &opCounter{count: make(map[OpKind]uint64)},
+		// leader transfer failures
+		leaderBlacklist: newLeaderTransferBlacklist(),
+		// learner catch-up failures
+		learnerCatchUpTimeouts: cache.NewIDTTL(ctx, time.Minute, LearnerCatchUpRetryWindow),
 	}
 }
 
@@ -127,6 +141,12 @@ func (oc *Controller) GetCluster() *core.BasicCluster {
 	return oc.cluster
 }
 
+// GetSharedConfig exports the shared config to evict-scheduler for check the
+// evicting stores.
+func (oc *Controller) GetSharedConfig() config.SharedConfigProvider {
+	return oc.config
+}
+
 // GetHBStreams returns the heartbeat steams.
 func (oc *Controller) GetHBStreams() *hbstream.HeartbeatStreams {
 	return oc.hbStreams
@@ -149,6 +169,9 @@ func (oc *Controller) Dispatch(region *core.RegionInfo, source string, recordOpS
 			if source == DispatchFromHeartBeat && oc.checkStaleOperator(op, step, region) {
 				return
 			}
+			if oc.checkLearnerCatchUpTimeout(op) {
+				return
+			}
 			oc.SendScheduleCommand(region, step, source)
 		case SUCCESS:
 			if op.ContainNonWitnessStep() {
@@ -222,6 +245,41 @@ func (oc *Controller) checkStaleOperator(op *Operator, step OpStep, region *core
 	return false
 }
 
+// checkLearnerCatchUpTimeout cancels the operator if the learner it adds can't
+// catch up within the max learner catch-up time.
+func (oc *Controller) checkLearnerCatchUpTimeout(op *Operator) bool {
+	maxCatchUpTime := oc.config.GetMaxLearnerCatchUpTime()
+	if maxCatchUpTime <= 0 {
+		return false
+	}
+	storeID, elapsed, ok := op.GetLearnerCatchUpTime(time.Now())
+	if !ok || elapsed <= maxCatchUpTime {
+		return false
+	}
+	op.SetAdditionalInfo(learnerCatchUpTime, elapsed.String())
+	if oc.RemoveOperator(op, LearnerCatchUpTimeout) {
+		log.Info("learner fails to catch up in time",
+			zap.Uint64("region-id", op.RegionID()),
+			zap.Uint64("store-id", storeID),
+			zap.Duration("elapsed", elapsed))
+		oc.learnerCatchUpTimeouts.Put(op.RegionID(), storeID)
+		operatorCounter.WithLabelValues(op.Desc(), "promote-learner-timeout").Inc()
+		oc.PromoteWaitingOperator()
+	}
+	return true
+}
+
+// GetLearnerCatchUpTimeoutStore returns the store of the learner which failed
+// to catch up in time recently for the region.
+func (oc *Controller) GetLearnerCatchUpTimeoutStore(regionID uint64) (uint64, bool) {
+	v, ok := oc.learnerCatchUpTimeouts.Get(regionID)
+	if !ok {
+		return 0, false
+	}
+	storeID, ok := v.(uint64)
+	return storeID, ok
+}
+
 func getNextPushOperatorTime(step OpStep, now time.Time) time.Time {
 	nextTime := slowNotifyInterval
 	switch step.(type) {
@@ -336,6 +394,7 @@ func (oc *Controller) AddWaitingOperator(ops ...*Operator) int {
 			oc.wop.PutOperator(op)
 		}
 		operatorCounter.WithLabelValues(desc, "put").Inc()
+		oc.recordKeyspaceOperator(op, "put")
 		oc.wopStatus.incCount(desc)
 		added++
 		needPromoted++
@@ -360,6 +419,14 @@ func (oc *Controller) AddOperator(ops ...*Operator) bool {
 		}
 		return false
 	}
+	if oc.ExceedLabelDomainLimit(ops...) {
+		for _, op := range ops {
+			operatorCounter.WithLabelValues(op.Desc(), "exceed-domain-limit").Inc()
+			_ = op.Cancel(ExceedLabelDomainLimit)
+			oc.buryOperator(op)
+		}
+		return false
+	}
 	if pass, reason := oc.checkAddOperator(false, ops...); !pass {
 		for _, op := range ops {
 			_ = op.Cancel(reason)
@@ -395,6 +462,15 @@ func (oc *Controller) PromoteWaitingOperator() {
 			oc.wopStatus.decCount(ops[0].Desc())
 			continue
 		}
+		if oc.ExceedLabelDomainLimit(ops...) {
+			for _, op := range ops {
+				operatorCounter.WithLabelValues(op.Desc(), "exceed-domain-limit").Inc()
+				_ = op.Cancel(ExceedLabelDomainLimit)
+				oc.buryOperator(op)
+			}
+			oc.wopStatus.decCount(ops[0].Desc())
+			continue
+		}
 
 		if pass, reason := oc.checkAddOperator(true, ops...); !pass {
 			for _, op := range ops {
@@ -731,9 +807,52 @@ func (oc *Controller) buryOperator(op *Operator) {
 		operatorCounter.WithLabelValues(op.Desc(), "cancel").Inc()
 	}
 
+	oc.recordLeaderTransferResult(op)
+	oc.recordKeyspaceOperator(op, OpStatusToString(op.Status()))
 	oc.records.Put(op)
 }
 
+// recordLeaderTransferResult records the result of the leader transfer when the
+// operator is ended. The operator fails to transfer leader if it's timeout or stale
+// at the transfer leader step.
+func (oc *Controller) recordLeaderTransferResult(op *Operator) {
+	switch op.Status() {
+	case SUCCESS:
+		region := oc.cluster.GetRegion(op.RegionID())
+		if region == nil {
+			return
+		}
+		for i := 0; i < op.Len(); i++ {
+			if _, ok := op.Step(i).(TransferLeader); ok {
+				oc.leaderBlacklist.recordSuccess(region.GetLeader().GetStoreId())
+				return
+			}
+		}
+	case TIMEOUT, CANCELED:
+		if op.Status() == CANCELED && op.GetAdditionalInfo(cancelReason) != string(StaleStatus) {
+			return
+		}
+		_, current := op.getCurrentTimeAndStep()
+		if step, ok := current.(TransferLeader); ok {
+			window := oc.config.GetLeaderTransferBlacklistWindow()
+			for _, storeID := range leaderTargets(step) {
+				oc.leaderBlacklist.recordFailure(storeID, window, time.Now())
+			}
+		}
+	}
+}
+
+// GetLeaderTransferBlacklist returns the stores which are excluded as the leader target now.
+func (oc *Controller) GetLeaderTransferBlacklist() []LeaderTransferExclusion {
+	return oc.leaderBlacklist.getExclusions(time.Now())
+}
+
+// GetLeaderTransferExcludedStores returns the IDs of the stores which are excluded
+// as the leader target now, the schedulers use it to filter the leader targets.
+func (oc *Controller) GetLeaderTransferExcludedStores() map[uint64]struct{} {
+	return oc.leaderBlacklist.excludedStores(time.Now())
+}
+
 // GetOperatorStatus gets the operator and its status with the specify id.
 func (oc *Controller) GetOperatorStatus(id uint64) *OpWithStatus {
 	if opi, ok := oc.operators.Load(id); ok && opi.(*Operator) != nil {
@@ -976,9 +1095,88 @@ func (oc *Controller) ExceedStoreLimit(ops ...*Operator) bool {
 	return false
 }
 
+// labelDomain is a set of the stores sharing the same value of a label key, e.g. a zone.
+type labelDomain struct {
+	key, value string
+}
+
+// ExceedLabelDomainLimit returns true if the number of the running operators
+// moving regions into any label domain exceeds the limit after adding the
+// operators. Otherwise, returns false.
+func (oc *Controller) ExceedLabelDomainLimit(ops ...*Operator) bool {
+	limits := oc.config.GetLabelDomainOperatorLimits()
+	if len(limits) == 0 || len(ops) == 0 || ops[0].GetPriorityLevel() == constant.Urgent {
+		return false
+	}
+	adding := make(map[labelDomain]uint64)
+	for _, op := range ops {
+		for domain := range oc.getTargetDomains(op, limits) {
+			adding[domain]++
+		}
+	}
+	if len(adding) == 0 {
+		return false
+	}
+	running := make(map[labelDomain]uint64)
+	oc.operators.Range(func(_, value any) bool {
+		for domain := range oc.getTargetDomains(value.(*Operator), limits) {
+			if _, ok := adding[domain]; ok {
+				running[domain]++
+			}
+		}
+		return true
+	})
+	for _, limit := range limits {
+		for domain, count := range adding {
+			if domain.key == limit.Key && running[domain]+count > limit.Limit {
+				OperatorExceededLabelDomainLimitCounter.WithLabelValues(ops[0].Desc(), domain.key+"="+domain.value).Inc()
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getTargetDomains returns the label domains of the stores which the operator adds peers to.
+func (oc *Controller) getTargetDomains(op *Operator, limits []config.LabelDomainOperatorLimit) map[labelDomain]struct{} {
+	var domains map[labelDomain]struct{}
+	for i := 0; i < op.Len(); i++ {
+		var storeID uint64
+		switch step := op.Step(i).(type) {
+		case AddPeer:
+			storeID = step.ToStore
+		case AddLearner:
+			storeID = step.ToStore
+		default:
+			continue
+		}
+		store := oc.cluster.GetStore(storeID)
+		if store == nil {
+			continue
+		}
+		for _, limit := range limits {
+			if value := store.GetLabelValue(limit.Key); value != "" {
+				if domains == nil {
+					domains = make(map[labelDomain]struct{})
+				}
+				domains[labelDomain{key: limit.Key, value: value}] = struct{}{}
+			}
+		}
+	}
+	return domains
+}
+
 // getOrCreateStoreLimit is used to get or create the limit of a store.
 func (oc *Controller) getOrCreateStoreLimit(storeID uint64, limitType storelimit.Type) storelimit.StoreLimit {
-	ratePerSec := oc.config.GetStoreLimitByType(storeID, limitType) / StoreBalanceBaseTime
+	ratePerMin := oc.config.GetStoreLimitByType(storeID, limitType)
+	budgeted := false
+	if limitType == storelimit.AddPeer {
+		var rate float64
+		if rate, budgeted = oc.snapshotBudget.get(storeID); budgeted && (ratePerMin == 0 || rate < ratePerMin) {
+			ratePerMin = rate
+		}
+	}
+	ratePerSec := ratePerMin / StoreBalanceBaseTime
 	s := oc.cluster.GetStore(storeID)
 	if s == nil {
 		log.Error("invalid store ID", zap.Uint64("store-id", storeID))
@@ -986,7 +1184,12 @@ func (oc *Controller) getOrCreateStoreLimit(storeID uint64, limitType storelimit
 	}
 	// The other limits do not need to update by config exclude StoreRateLimit.
 	if limit, ok := s.GetStoreLimit().(*storelimit.StoreRateLimit); ok && limit.Rate(limitType) != ratePerSec {
-		oc.cluster.ResetStoreLimit(storeID, limitType, ratePerSec)
+		// The budget adjusts the rate frequently, keep the tokens to not refill the bucket.
+		if budgeted {
+			limit.SetRate(ratePerSec, limitType)
+		} else {
+			oc.cluster.ResetStoreLimit(storeID, limitType, ratePerSec)
+		}
 	}
 	return s.GetStoreLimit()
 }
@@ -22,15 +22,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/core/storelimit"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/mock/mockconfig"
+	"github.com/tikv/pd/pkg/schedule/config"
 	"github.com/tikv/pd/pkg/schedule/hbstream"
 	"github.com/tikv/pd/pkg/schedule/labeler"
 )
@@ -599,6 +602,181 @@ func (suite *operatorControllerTestSuite) TestStoreLimit() {
 	re.False(oc.RemoveOperator(op))
 }
 
+func (suite *operatorControllerTestSuite) TestGetState() {
+	re := suite.Require()
+	opt := mockconfig.NewTestOptions()
+	tc := mockcluster.NewCluster(suite.ctx, opt)
+	stream := hbstream.NewTestHeartbeatStreams(suite.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewController(suite.ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1)
+	tc.AddLeaderRegion(2, 1)
+	tc.SetStoreLimit(2, storelimit.AddPeer, 60)
+
+	running := NewTestOperator(1, &metapb.RegionEpoch{}, OpRegion, AddPeer{ToStore: 2, PeerID: 3})
+	re.True(oc.AddOperator(running))
+	waiting := NewTestOperator(2, &metapb.RegionEpoch{}, OpRegion, AddPeer{ToStore: 2, PeerID: 4})
+	waiting.SetPriorityLevel(constant.High)
+	oc.wop.PutOperator(waiting)
+	oc.wopStatus.incCount(waiting.Desc())
+
+	state := oc.GetState()
+	re.Equal([]*Operator{running}, state.Running)
+	re.Len(state.Waiting, int(constant.PriorityLevelLen))
+	re.Empty(state.Waiting[constant.Low].Operators)
+	re.Equal("high", state.Waiting[constant.High].PriorityLevel)
+	re.Equal([]*Operator{waiting}, state.Waiting[constant.High].Operators)
+	re.Equal(map[string]uint64{waiting.Desc(): 1}, state.WaitingCounts)
+	var limit *StoreLimitState
+	for _, l := range state.StoreLimits {
+		if l.StoreID == 2 && l.Type == storelimit.AddPeer.String() {
+			limit = l
+		}
+	}
+	re.NotNil(limit)
+	re.Equal(storelimit.VersionV1, limit.Version)
+	re.Equal(1.0, limit.RatePerSec)
+	// the running operator has taken the tokens.
+	re.Less(limit.Tokens, 1000.0)
+}
+
+func (suite *operatorControllerTestSuite) TestLabelDomainLimit() {
+	re := suite.Require()
+	opt := mockconfig.NewTestOptions()
+	tc := mockcluster.NewCluster(suite.ctx, opt)
+	stream := hbstream.NewTestHeartbeatStreams(suite.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewController(suite.ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	tc.AddLabelsStore(1, 0, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(2, 0, map[string]string{"zone": "z2"})
+	tc.AddLabelsStore(3, 0, map[string]string{"zone": "z2"})
+	tc.AddLabelsStore(4, 0, map[string]string{"zone": "z3"})
+	for i := uint64(1); i <= 5; i++ {
+		tc.AddLeaderRegion(i, 1)
+		tc.PutRegion(tc.GetRegion(i).Clone(core.SetApproximateSize(10)))
+	}
+	// make sure the store limit is not reached.
+	tc.SetAllStoresLimit(storelimit.AddPeer, 600)
+
+	tc.SetLabelDomainOperatorLimits([]config.LabelDomainOperatorLimit{{Key: "zone", Limit: 2}})
+	op1 := NewTestOperator(1, &metapb.RegionEpoch{}, OpRegion, AddPeer{ToStore: 2, PeerID: 11})
+	re.True(oc.AddOperator(op1))
+	op2 := NewTestOperator(2, &metapb.RegionEpoch{}, OpRegion, AddLearner{ToStore: 3, PeerID: 12})
+	re.True(oc.AddOperator(op2))
+	// zone z2 has reached the limit.
+	op3 := NewTestOperator(3, &metapb.RegionEpoch{}, OpRegion, AddPeer{ToStore: 3, PeerID: 13})
+	re.False(oc.AddOperator(op3))
+	re.Equal(string(ExceedLabelDomainLimit), op3.GetAdditionalInfo(cancelReason))
+	// the other zones are not affected.
+	op4 := NewTestOperator(4, &metapb.RegionEpoch{}, OpRegion, AddPeer{ToStore: 4, PeerID: 14})
+	re.True(oc.AddOperator(op4))
+	// the admin operators ignore the limit.
+	op5 := NewTestOperator(5, &metapb.RegionEpoch{}, OpRegion|OpAdmin, AddPeer{ToStore: 2, PeerID: 15})
+	re.True(oc.AddOperator(op5))
+
+	// the limit is released after the operator finishes.
+	checkRemoveOperatorSuccess(re, oc, op1)
+	op3 = NewTestOperator(3, &metapb.RegionEpoch{}, OpRegion, AddPeer{ToStore: 3, PeerID: 13})
+	re.False(oc.AddOperator(op3))
+	checkRemoveOperatorSuccess(re, oc, op5)
+	op3 = NewTestOperator(3, &metapb.RegionEpoch{}, OpRegion, AddPeer{ToStore: 3, PeerID: 13})
+	re.True(oc.AddOperator(op3))
+}
+
+func (suite *operatorControllerTestSuite) TestSnapshotBudget() {
+	re := suite.Require()
+	opt := mockconfig.NewTestOptions()
+	tc := mockcluster.NewCluster(suite.ctx, opt)
+	stream := hbstream.NewTestHeartbeatStreams(suite.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewController(suite.ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddLeaderStore(i, 0)
+		tc.AddLeaderRegion(i, 1)
+	}
+	tc.SetAllStoresLimit(storelimit.AddPeer, 15)
+	addPeerRate := func(storeID uint64) float64 {
+		limit := oc.getOrCreateStoreLimit(storeID, storelimit.AddPeer).(*storelimit.StoreRateLimit)
+		return limit.Rate(storelimit.AddPeer) * StoreBalanceBaseTime
+	}
+	oc.UpdateSnapshotBudget()
+	re.Empty(oc.GetSnapshotBudget())
+	re.Equal(15., addPeerRate(1))
+
+	// 48MiB/s is 30 snapshots of the 96MiB regions per minute, shared by the stores evenly.
+	tc.SetClusterSnapshotBandwidth(48 * units.MiB)
+	oc.UpdateSnapshotBudget()
+	re.Equal(map[uint64]float64{1: 10, 2: 10, 3: 10}, oc.GetSnapshotBudget())
+	re.Equal(10., addPeerRate(1))
+
+	// the store with more pending snapshots gets more, but no more than its own limit.
+	op := NewTestOperator(1, &metapb.RegionEpoch{}, OpRegion, AddPeer{ToStore: 3, PeerID: 11})
+	re.True(oc.AddOperator(op))
+	oc.UpdateSnapshotBudget()
+	re.Equal(map[uint64]float64{1: 7.5, 2: 7.5, 3: 15}, oc.GetSnapshotBudget())
+	tc.SetStoreLimit(1, storelimit.AddPeer, 5)
+	oc.UpdateSnapshotBudget()
+	re.Equal(map[uint64]float64{1: 5, 2: 10, 3: 15}, oc.GetSnapshotBudget())
+	re.Equal(10., addPeerRate(2))
+	// the idle stores only get the min rate if the others can use up the bandwidth.
+	op = NewTestOperator(2, &metapb.RegionEpoch{}, OpRegion, AddPeer{ToStore: 2, PeerID: 12})
+	re.True(oc.AddOperator(op))
+	oc.UpdateSnapshotBudget()
+	re.Equal(map[uint64]float64{1: minSnapshotBudgetRate, 2: 15, 3: 15}, oc.GetSnapshotBudget())
+	// the tokens taken by the operator are not refilled when the rate is adjusted.
+	tc.SetStoreLimit(1, storelimit.AddPeer, 15)
+	checkRemoveOperatorSuccess(re, oc, op)
+	oc.UpdateSnapshotBudget()
+	re.Equal(7.5, addPeerRate(2))
+	tokens := oc.getOrCreateStoreLimit(2, storelimit.AddPeer).(*storelimit.StoreRateLimit).Tokens(storelimit.AddPeer)
+	re.Less(tokens, float64(storelimit.RegionInfluence[storelimit.AddPeer]))
+
+	// the stores use their own limits again after disabling the budget.
+	tc.SetClusterSnapshotBandwidth(0)
+	oc.UpdateSnapshotBudget()
+	re.Empty(oc.GetSnapshotBudget())
+	re.Equal(15., addPeerRate(2))
+}
+
+func (suite *operatorControllerTestSuite) TestLearnerCatchUpTimeout() {
+	re := suite.Require()
+	tc := mockcluster.NewCluster(suite.ctx, mockconfig.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(suite.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewController(suite.ctx, tc.GetBasicCluster(), tc.GetSharedConfig(), stream)
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddLeaderStore(i, 0)
+	}
+	epoch := &metapb.RegionEpoch{}
+	region := tc.MockRegionInfo(1, 1, []uint64{2}, []uint64{3}, epoch)
+	learner := region.GetStoreLearner(3)
+	region = region.Clone(core.WithPendingPeers([]*metapb.Peer{learner}))
+	tc.PutRegion(region)
+	op := NewTestOperator(1, epoch, OpRegion,
+		AddLearner{ToStore: 3, PeerID: learner.GetId()},
+		PromoteLearner{ToStore: 3, PeerID: learner.GetId()})
+	re.True(oc.AddOperator(op))
+	op.SetStatusReachTime(STARTED, time.Now().Add(-2*time.Minute))
+
+	// no limit by default.
+	oc.Dispatch(region, DispatchFromHeartBeat, nil)
+	re.NotNil(oc.GetOperator(1))
+	tc.SetMaxLearnerCatchUpTime(5 * time.Minute)
+	oc.Dispatch(region, DispatchFromHeartBeat, nil)
+	re.NotNil(oc.GetOperator(1))
+
+	// the operator is canceled once the learner can't catch up in time.
+	tc.SetMaxLearnerCatchUpTime(time.Minute)
+	oc.Dispatch(region, DispatchFromHeartBeat, nil)
+	re.Nil(oc.GetOperator(1))
+	re.Equal(CANCELED, op.Status())
+	re.Equal(string(LearnerCatchUpTimeout), op.GetAdditionalInfo(cancelReason))
+	re.NotEmpty(op.GetAdditionalInfo(learnerCatchUpTime))
+	storeID, ok := oc.GetLearnerCatchUpTimeoutStore(1)
+	re.True(ok)
+	re.Equal(uint64(3), storeID)
+	_, ok = oc.GetLearnerCatchUpTimeoutStore(2)
+	re.False(ok)
+}
+
 // #1652
 func (suite *operatorControllerTestSuite) TestDispatchOutdatedRegion() {
 	re := suite.Require()
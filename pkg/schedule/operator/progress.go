@@ -0,0 +1,170 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/tikv/pd/pkg/utils/typeutil"
+)
+
+// The types of the operator progress events.
+const (
+	ProgressStepStarted  = "step-started"
+	ProgressStepFinished = "step-finished"
+	ProgressFinished     = "finished"
+)
+
+// ProgressEvent is a progress change of an operator.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ProgressEvent struct {
+	RegionID uint64 `json:"region_id"`
+	Desc     string `json:"desc"`
+	Type     string `json:"type"`
+	// StepIndex and Step are the step which is started or finished, they are
+	// the last reached step for the finished events.
+	StepIndex int    `json:"step_index"`
+	Steps     int    `json:"steps"`
+	Step      string `json:"step,omitempty"`
+	Status    string `json:"status"`
+	// Lag is how long the step has been running, or how long it took if it's
+	// finished. It's the running time of the operator for the finished events.
+	Lag  typeutil.Duration `json:"lag"`
+	Time time.Time         `json:"time"`
+}
+
+type progressState struct {
+	op *Operator
+	// next is the first step whose finished event is not generated yet.
+	next    int
+	started bool
+	ended   bool
+}
+
+// ProgressWatcher generates the progress events of the operators on the given
+// regions by comparing their progress with the last observed one. The watched
+// operator of a region is replaced if a new operator is created on it before
+// the old one is observed to end, and the region without any operator is
+// regarded as ended.
+type ProgressWatcher struct {
+	regionIDs []uint64
+	states    map[uint64]*progressState
+}
+
+// NewProgressWatcher creates a ProgressWatcher on the given regions.
+func NewProgressWatcher(regionIDs []uint64) *ProgressWatcher {
+	return &ProgressWatcher{
+		regionIDs: regionIDs,
+		states:    make(map[uint64]*progressState, len(regionIDs)),
+	}
+}
+
+// Observe returns the progress events since the last observation. getOp returns
+// the latest operator of the region including the ended one, or nil if there is
+// no operator.
+func (w *ProgressWatcher) Observe(getOp func(regionID uint64) *Operator) []*ProgressEvent {
+	var events []*ProgressEvent
+	now := time.Now()
+	for _, regionID := range w.regionIDs {
+		state, ok := w.states[regionID]
+		if ok && state.ended {
+			continue
+		}
+		op := getOp(regionID)
+		if op == nil {
+			// there is nothing to wait for, or the ended operator is no longer recorded.
+			w.states[regionID] = &progressState{ended: true}
+			continue
+		}
+		if !ok || state.op != op {
+			state = &progressState{op: op}
+			w.states[regionID] = state
+		}
+		events = append(events, state.observe(now)...)
+	}
+	return events
+}
+
+func (s *progressState) observe(now time.Time) []*ProgressEvent {
+	op := s.op
+	if s.ended || !op.HasStarted() && !op.IsEnd() {
+		return nil
+	}
+	var events []*ProgressEvent
+	newEvent := func(typ string, index int, lag time.Duration, t time.Time) *ProgressEvent {
+		event := &ProgressEvent{
+			RegionID:  op.RegionID(),
+			Desc:      op.Desc(),
+			Type:      typ,
+			StepIndex: index,
+			Steps:     op.Len(),
+			Status:    OpStatusToString(op.Status()),
+			Lag:       typeutil.NewDuration(lag),
+			Time:      t,
+		}
+		if step := op.Step(index); step != nil {
+			event.Step = step.String()
+		}
+		return event
+	}
+	// The status is loaded before the steps, so that the steps are complete if
+	// the operator is seen to be ended.
+	ended := op.IsEnd()
+	current := int(atomic.LoadInt32(&op.currentStep))
+	if op.HasStarted() {
+		for ; s.next < current; s.next++ {
+			startTime := s.stepStartTime()
+			if !s.started {
+				events = append(events, newEvent(ProgressStepStarted, s.next, 0, startTime))
+			}
+			finishTime := time.Unix(0, atomic.LoadInt64(&op.stepsTime[s.next]))
+			events = append(events, newEvent(ProgressStepFinished, s.next, finishTime.Sub(startTime), finishTime))
+			s.started = false
+		}
+		if !ended && !s.started && s.next < op.Len() {
+			startTime := s.stepStartTime()
+			events = append(events, newEvent(ProgressStepStarted, s.next, now.Sub(startTime), startTime))
+			s.started = true
+		}
+	}
+	if ended {
+		s.ended = true
+		endTime := op.GetReachTimeOf(op.Status())
+		var lag time.Duration
+		if op.HasStarted() {
+			lag = endTime.Sub(op.GetStartTime())
+		}
+		events = append(events, newEvent(ProgressFinished, s.next, lag, endTime))
+	}
+	return events
+}
+
+func (s *progressState) stepStartTime() time.Time {
+	if s.next == 0 {
+		return s.op.GetStartTime()
+	}
+	return time.Unix(0, atomic.LoadInt64(&s.op.stepsTime[s.next-1]))
+}
+
+// Done returns true if the operators of all the regions are observed to end.
+func (w *ProgressWatcher) Done() bool {
+	for _, regionID := range w.regionIDs {
+		if state, ok := w.states[regionID]; !ok || !state.ended {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+)
+
+func TestProgressWatcher(t *testing.T) {
+	re := require.New(t)
+	peers := []*metapb.Peer{{Id: 1, StoreId: 1}, {Id: 2, StoreId: 2}}
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: peers}, peers[0])
+	op := NewTestOperator(1, &metapb.RegionEpoch{}, OpRegion,
+		TransferLeader{FromStore: 1, ToStore: 2},
+		RemovePeer{FromStore: 1},
+	)
+	ops := map[uint64]*Operator{1: op}
+	getOp := func(regionID uint64) *Operator { return ops[regionID] }
+	types := func(events []*ProgressEvent) []string {
+		var types []string
+		for _, event := range events {
+			types = append(types, event.Type)
+		}
+		return types
+	}
+
+	ops[2] = NewTestOperator(2, &metapb.RegionEpoch{}, OpRegion, TransferLeader{FromStore: 1, ToStore: 2})
+	watcher := NewProgressWatcher([]uint64{1, 2})
+	// the operator which is not started has no progress.
+	re.Empty(watcher.Observe(getOp))
+	re.True(op.Start())
+	events := watcher.Observe(getOp)
+	re.Equal([]string{ProgressStepStarted}, types(events))
+	re.Equal(0, events[0].StepIndex)
+	re.Equal(2, events[0].Steps)
+	re.Empty(watcher.Observe(getOp))
+
+	// the first step is finished.
+	region = region.Clone(core.WithLeader(peers[1]))
+	re.NotNil(op.Check(region))
+	events = watcher.Observe(getOp)
+	re.Equal([]string{ProgressStepFinished, ProgressStepStarted}, types(events))
+	re.Equal(0, events[0].StepIndex)
+	re.Equal(1, events[1].StepIndex)
+	re.False(watcher.Done())
+
+	// the second step is finished and the operator succeeds.
+	region = region.Clone(core.WithRemoveStorePeer(1))
+	re.Nil(op.Check(region))
+	re.True(op.CheckSuccess())
+	events = watcher.Observe(getOp)
+	re.Equal([]string{ProgressStepFinished, ProgressFinished}, types(events))
+	re.Equal(OpStatusToString(SUCCESS), events[1].Status)
+	re.False(watcher.Done())
+
+	// the operator which is canceled before starting is finished directly.
+	re.True(ops[2].Cancel(AdminStop))
+	events = watcher.Observe(getOp)
+	re.Equal([]string{ProgressFinished}, types(events))
+	re.Equal(uint64(2), events[0].RegionID)
+	re.True(watcher.Done())
+	re.Empty(watcher.Observe(getOp))
+
+	// the region without any operator is regarded as ended.
+	watcher = NewProgressWatcher([]uint64{3})
+	re.Empty(watcher.Observe(getOp))
+	re.True(watcher.Done())
+}
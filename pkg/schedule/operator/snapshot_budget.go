@@ -0,0 +1,180 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"sort"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/docker/go-units"
+	"github.com/tikv/pd/pkg/core/storelimit"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+// minSnapshotBudgetRate is the min add peer rate per minute allocated to a store,
+// the rate 0 means unlimited for the store limit.
+const minSnapshotBudgetRate = 0.1
+
+// snapshotBudget records the add peer rates of the stores allocated by the
+// cluster snapshot bandwidth. The stores without the allocated rate use their
+// own limits.
+type snapshotBudget struct {
+	syncutil.RWMutex
+	rates map[uint64]float64
+}
+
+func (b *snapshotBudget) get(storeID uint64) (float64, bool) {
+	b.RLock()
+	defer b.RUnlock()
+	rate, ok := b.rates[storeID]
+	return rate, ok
+}
+
+func (b *snapshotBudget) set(rates map[uint64]float64) {
+	b.Lock()
+	defer b.Unlock()
+	b.rates = rates
+}
+
+// UpdateSnapshotBudget allocates the cluster snapshot bandwidth across the stores.
+// Each added peer costs a snapshot of about the average region size, so the
+// bandwidth is turned into the total add peer rate, which is shared by the
+// stores in proportion to their pending snapshots, the stores without pending
+// snapshots only get the min rate unless no store has any. No store gets more
+// than its own add peer limit, the spare rate is shared by the other stores instead.
+func (oc *Controller) UpdateSnapshotBudget() {
+	bandwidth := oc.config.GetClusterSnapshotBandwidth()
+	if bandwidth == 0 {
+		if len(oc.GetSnapshotBudget()) > 0 {
+			oc.snapshotBudget.set(nil)
+			snapshotBudgetGauge.Reset()
+		}
+		return
+	}
+	regionSize := oc.cluster.GetAverageRegionSize()
+	if regionSize < 1 {
+		regionSize = 1
+	}
+	total := float64(bandwidth) / float64(regionSize*units.MiB) * StoreBalanceBaseTime
+
+	demands := oc.getAddPeerDemands()
+	weights := make(map[uint64]float64)
+	limits := make(map[uint64]float64)
+	var totalDemand int
+	for _, store := range oc.cluster.GetStores() {
+		if store.IsRemoving() || store.IsRemoved() || store.IsDisconnected() {
+			continue
+		}
+		id := store.GetID()
+		weights[id] = float64(demands[id])
+		limits[id] = oc.config.GetStoreLimitByType(id, storelimit.AddPeer)
+		totalDemand += demands[id]
+	}
+	// Share the rate evenly if no store is waiting for the snapshots.
+	if totalDemand == 0 {
+		for id := range weights {
+			weights[id] = 1
+		}
+	}
+	rates := allocateSnapshotBudget(total, weights, limits)
+	oc.snapshotBudget.set(rates)
+	snapshotBudgetGauge.Reset()
+	for id, rate := range rates {
+		snapshotBudgetGauge.WithLabelValues(strconv.FormatUint(id, 10)).Set(rate)
+	}
+}
+
+// GetSnapshotBudget returns the add peer rates per minute of the stores
+// allocated by the cluster snapshot bandwidth.
+func (oc *Controller) GetSnapshotBudget() map[uint64]float64 {
+	oc.snapshotBudget.RLock()
+	defer oc.snapshotBudget.RUnlock()
+	rates := make(map[uint64]float64, len(oc.snapshotBudget.rates))
+	for id, rate := range oc.snapshotBudget.rates {
+		rates[id] = rate
+	}
+	return rates
+}
+
+// getAddPeerDemands returns the number of the unfinished steps of the running
+// operators adding peers to each store.
+func (oc *Controller) getAddPeerDemands() map[uint64]int {
+	demands := make(map[uint64]int)
+	oc.operators.Range(func(_, value any) bool {
+		op := value.(*Operator)
+		for i := int(atomic.LoadInt32(&op.currentStep)); i < op.Len(); i++ {
+			switch step := op.Step(i).(type) {
+			case AddPeer:
+				demands[step.ToStore]++
+			case AddLearner:
+				demands[step.ToStore]++
+			}
+		}
+		return true
+	})
+	return demands
+}
+
+// allocateSnapshotBudget shares the total rate by the weights, and the rate of
+// each store doesn't exceed its limit, the limit 0 means unlimited.
+func allocateSnapshotBudget(total float64, weights, limits map[uint64]float64) map[uint64]float64 {
+	pending := make([]uint64, 0, len(weights))
+	for id := range weights {
+		pending = append(pending, id)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i] < pending[j] })
+
+	rates := make(map[uint64]float64, len(weights))
+	remaining := total
+	for len(pending) > 0 && remaining > 0 {
+		var sum float64
+		for _, id := range pending {
+			sum += weights[id]
+		}
+		// The spare rate is shared evenly if none of the rest has any weight.
+		shareOf := func(id uint64) float64 {
+			if sum == 0 {
+				return remaining / float64(len(pending))
+			}
+			return remaining * weights[id] / sum
+		}
+		next := pending[:0:0]
+		var used float64
+		for _, id := range pending {
+			share := shareOf(id)
+			if limit := limits[id]; limit > 0 && share >= limit {
+				rates[id] = limit
+				used += limit
+			} else {
+				next = append(next, id)
+			}
+		}
+		if used == 0 {
+			for _, id := range next {
+				rates[id] = shareOf(id)
+			}
+			break
+		}
+		remaining -= used
+		pending = next
+	}
+	for id := range weights {
+		if rates[id] < minSnapshotBudgetRate {
+			rates[id] = minSnapshotBudgetRate
+		}
+	}
+	return rates
+}
@@ -162,3 +162,16 @@ func (s *waitingOperatorStatus) getCount(kind string) uint64 {
 	defer s.mu.Unlock()
 	return s.ops[kind]
 }
+
+// getCounts returns the non-zero counts of all operator kinds.
+func (s *waitingOperatorStatus) getCounts() map[string]uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make(map[string]uint64, len(s.ops))
+	for kind, count := range s.ops {
+		if count > 0 {
+			counts[kind] = count
+		}
+	}
+	return counts
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"github.com/tikv/pd/pkg/utils/typeutil"
+)
+
+// PatrolRegionsProgress is the progress of the current patrol round.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type PatrolRegionsProgress struct {
+	RoundStartTime time.Time `json:"round_start_time"`
+	// LastRoundDuration is the duration of the last finished patrol round.
+	LastRoundDuration typeutil.Duration `json:"last_round_duration"`
+	// Lag is the upper bound of the time since a region was patrolled last time.
+	Lag        typeutil.Duration          `json:"lag"`
+	Partitions []*PatrolPartitionProgress `json:"partitions"`
+}
+
+// PatrolPartitionProgress is the progress of a key space partition in the current patrol round.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type PatrolPartitionProgress struct {
+	StartKey       string `json:"start_key"`
+	EndKey         string `json:"end_key"`
+	CheckedRegions int    `json:"checked_regions"`
+	Done           bool   `json:"done"`
+}
+
+// patrolPartition is a key range of the key space which is patrolled by a worker.
+type patrolPartition struct {
+	startKey, endKey []byte
+	// nextKey is the key to continue the patrol, it's only updated by the worker of the partition.
+	nextKey []byte
+	checked int
+	done    bool
+}
+
+// regionPatrol records the progress of the patrol rounds. In a round, the key
+// space is split into the partitions holding about the same number of regions,
+// and the partitions are patrolled concurrently.
+type regionPatrol struct {
+	syncutil.RWMutex
+	partitions     []*patrolPartition
+	roundStart     time.Time
+	lastRoundStart time.Time
+}
+
+// startRound starts a new patrol round with the partitions split by the keys.
+func (p *regionPatrol) startRound(keys [][]byte, now time.Time) {
+	partitions := make([]*patrolPartition, 0, len(keys)+1)
+	var startKey []byte
+	for _, key := range keys {
+		partitions = append(partitions, &patrolPartition{startKey: startKey, endKey: key, nextKey: startKey})
+		startKey = key
+	}
+	partitions = append(partitions, &patrolPartition{startKey: startKey, nextKey: startKey})
+	p.Lock()
+	defer p.Unlock()
+	p.partitions = partitions
+	p.roundStart = now
+}
+
+// finishRound marks the current round as finished and returns its duration.
+func (p *regionPatrol) finishRound(now time.Time) time.Duration {
+	p.Lock()
+	defer p.Unlock()
+	p.lastRoundStart = p.roundStart
+	return now.Sub(p.roundStart)
+}
+
+// pendingPartitions returns the partitions which have not been patrolled in the current round.
+func (p *regionPatrol) pendingPartitions() []*patrolPartition {
+	p.RLock()
+	defer p.RUnlock()
+	partitions := make([]*patrolPartition, 0, len(p.partitions))
+	for _, partition := range p.partitions {
+		if !partition.done {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions
+}
+
+// advance records the regions patrolled in the partition, the partition is done
+// if it's exhausted or the patrol reaches its end key.
+func (p *regionPatrol) advance(partition *patrolPartition, regions []*core.RegionInfo, exhausted bool) {
+	p.Lock()
+	defer p.Unlock()
+	if len(regions) == 0 {
+		partition.done = true
+		return
+	}
+	partition.checked += len(regions)
+	partition.nextKey = regions[len(regions)-1].GetEndKey()
+	if exhausted || len(partition.nextKey) == 0 ||
+		(len(partition.endKey) > 0 && bytes.Compare(partition.nextKey, partition.endKey) >= 0) {
+		partition.done = true
+	}
+}
+
+// isRoundDone returns whether all the partitions of the current round have been patrolled.
+func (p *regionPatrol) isRoundDone() bool {
+	p.RLock()
+	defer p.RUnlock()
+	for _, partition := range p.partitions {
+		if !partition.done {
+			return false
+		}
+	}
+	return true
+}
+
+// checkedRegions returns the number of the regions patrolled in the current round.
+func (p *regionPatrol) checkedRegions() int {
+	p.RLock()
+	defer p.RUnlock()
+	checked := 0
+	for _, partition := range p.partitions {
+		checked += partition.checked
+	}
+	return checked
+}
+
+// lag returns the upper bound of the time since a region was patrolled last time,
+// which is the time since the last finished round started.
+func (p *regionPatrol) lag(now time.Time) time.Duration {
+	p.RLock()
+	defer p.RUnlock()
+	return p.lagLocked(now)
+}
+
+func (p *regionPatrol) lagLocked(now time.Time) time.Duration {
+	if !p.lastRoundStart.IsZero() {
+		return now.Sub(p.lastRoundStart)
+	}
+	if !p.roundStart.IsZero() {
+		return now.Sub(p.roundStart)
+	}
+	return 0
+}
+
+func (p *regionPatrol) progress(now time.Time, lastRoundDuration time.Duration) *PatrolRegionsProgress {
+	p.RLock()
+	defer p.RUnlock()
+	progress := &PatrolRegionsProgress{
+		RoundStartTime:    p.roundStart,
+		LastRoundDuration: typeutil.NewDuration(lastRoundDuration),
+		Lag:               typeutil.NewDuration(p.lagLocked(now)),
+		Partitions:        make([]*PatrolPartitionProgress, 0, len(p.partitions)),
+	}
+	for _, partition := range p.partitions {
+		progress.Partitions = append(progress.Partitions, &PatrolPartitionProgress{
+			StartKey:       core.HexRegionKeyStr(partition.startKey),
+			EndKey:         core.HexRegionKeyStr(partition.endKey),
+			CheckedRegions: partition.checked,
+			Done:           partition.done,
+		})
+	}
+	return progress
+}
@@ -17,19 +17,24 @@ package placement
 import (
 	"bytes"
 	"encoding/json"
+	"sort"
 	"time"
+
+	"github.com/tikv/pd/pkg/errs"
 )
 
 // ruleConfig contains rule and rule group configurations.
 type ruleConfig struct {
-	rules  map[[2]string]*Rule   // {group, id} => Rule
-	groups map[string]*RuleGroup // id => RuleGroup
+	rules       map[[2]string]*Rule    // {group, id} => Rule
+	groups      map[string]*RuleGroup  // id => RuleGroup
+	storeGroups map[string]*StoreGroup // id => StoreGroup
 }
 
 func newRuleConfig() *ruleConfig {
 	return &ruleConfig{
-		rules:  make(map[[2]string]*Rule),
-		groups: make(map[string]*RuleGroup),
+		rules:       make(map[[2]string]*Rule),
+		groups:      make(map[string]*RuleGroup),
+		storeGroups: make(map[string]*StoreGroup),
 	}
 }
 
@@ -51,6 +56,7 @@ func (c *ruleConfig) adjust() {
 		}
 		// setup group for `buildRuleList`
 		r.group = g
+		r.storeGroup = c.storeGroups[r.StoreGroup]
 	}
 }
 
@@ -79,6 +85,10 @@ func (c *ruleConfig) getGroup(id string) *RuleGroup {
 	return &RuleGroup{ID: id}
 }
 
+func (c *ruleConfig) getStoreGroup(id string) *StoreGroup {
+	return c.storeGroups[id]
+}
+
 func (c *ruleConfig) beginPatch() *RuleConfigPatch {
 	return &RuleConfigPatch{
 		c:   c,
@@ -122,6 +132,23 @@ func (p *RuleConfigPatch) DeleteGroup(id string) {
 	p.SetGroup(&RuleGroup{ID: id})
 }
 
+func (p *RuleConfigPatch) getStoreGroup(id string) *StoreGroup {
+	if g, ok := p.mut.storeGroups[id]; ok {
+		return g
+	}
+	return p.c.getStoreGroup(id)
+}
+
+// SetStoreGroup sets a store group to the patch.
+func (p *RuleConfigPatch) SetStoreGroup(g *StoreGroup) {
+	p.mut.storeGroups[g.ID] = g
+}
+
+// DeleteStoreGroup deletes a store group from the patch.
+func (p *RuleConfigPatch) DeleteStoreGroup(id string) {
+	p.mut.storeGroups[id] = nil
+}
+
 func (p *RuleConfigPatch) iterateRules(f func(*Rule)) {
 	for _, r := range p.mut.rules {
 		if r != nil { // nil means delete.
@@ -136,8 +163,26 @@ func (p *RuleConfigPatch) iterateRules(f func(*Rule)) {
 }
 
 func (p *RuleConfigPatch) adjust() {
-	// setup rule.group for `buildRuleList` use.
-	p.iterateRules(func(r *Rule) { r.group = p.getGroup(r.GroupID) })
+	// setup rule.group and rule.storeGroup for `buildRuleList` use.
+	p.iterateRules(func(r *Rule) {
+		r.group = p.getGroup(r.GroupID)
+		r.storeGroup = p.getStoreGroup(r.StoreGroup)
+	})
+}
+
+// checkStoreGroups checks all the store groups referenced by the rules exist.
+func (p *RuleConfigPatch) checkStoreGroups() error {
+	var err error
+	p.iterateRules(func(r *Rule) {
+		if err == nil && r.StoreGroup != "" && r.storeGroup == nil {
+			if _, deleted := p.mut.storeGroups[r.StoreGroup]; deleted {
+				err = errs.ErrStoreGroupInUse.FastGenByArgs(r.StoreGroup, r.ID)
+			} else {
+				err = errs.ErrStoreGroupNotFound.FastGenByArgs(r.StoreGroup)
+			}
+		}
+	})
+	return err
 }
 
 // trim unnecessary updates. For example, remove a rule then insert the same rule.
@@ -152,6 +197,56 @@ func (p *RuleConfigPatch) trim() {
 			delete(p.mut.groups, id)
 		}
 	}
+	for id, group := range p.mut.storeGroups {
+		if jsonEquals(group, p.c.getStoreGroup(id)) {
+			delete(p.mut.storeGroups, id)
+		}
+	}
+}
+
+// changed returns whether the patch changes anything.
+func (p *RuleConfigPatch) changed() bool {
+	return len(p.mut.rules) > 0 || len(p.mut.groups) > 0 || len(p.mut.storeGroups) > 0
+}
+
+// RulesChange is a part of the rules, rule groups and store groups.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RulesChange struct {
+	Rules       []*Rule       `json:"rules,omitempty"`
+	RuleGroups  []*RuleGroup  `json:"rule_groups,omitempty"`
+	StoreGroups []*StoreGroup `json:"store_groups,omitempty"`
+}
+
+// change returns the states of the changed items before and after the patch,
+// a deleted item is only in the old one and a created item is only in the new one.
+func (p *RuleConfigPatch) change() (oldChange, newChange *RulesChange) {
+	oldChange, newChange = &RulesChange{}, &RulesChange{}
+	for key, rule := range p.mut.rules {
+		if oldRule := p.c.getRule(key); oldRule != nil {
+			oldChange.Rules = append(oldChange.Rules, oldRule)
+		}
+		if rule != nil {
+			newChange.Rules = append(newChange.Rules, rule)
+		}
+	}
+	for id, group := range p.mut.groups {
+		oldChange.RuleGroups = append(oldChange.RuleGroups, p.c.getGroup(id))
+		newChange.RuleGroups = append(newChange.RuleGroups, group)
+	}
+	for id, group := range p.mut.storeGroups {
+		if oldGroup := p.c.getStoreGroup(id); oldGroup != nil {
+			oldChange.StoreGroups = append(oldChange.StoreGroups, oldGroup)
+		}
+		if group != nil {
+			newChange.StoreGroups = append(newChange.StoreGroups, group)
+		}
+	}
+	for _, c := range []*RulesChange{oldChange, newChange} {
+		sortRules(c.Rules)
+		sort.Slice(c.RuleGroups, func(i, j int) bool { return c.RuleGroups[i].ID < c.RuleGroups[j].ID })
+		sort.Slice(c.StoreGroups, func(i, j int) bool { return c.StoreGroups[i].ID < c.StoreGroups[j].ID })
+	}
+	return oldChange, newChange
 }
 
 // merge all mutations to ruleConfig.
@@ -177,6 +272,13 @@ func (p *RuleConfigPatch) commit() {
 	for id, group := range p.mut.groups {
 		p.c.groups[id] = group
 	}
+	for id, group := range p.mut.storeGroups {
+		if group == nil {
+			delete(p.c.storeGroups, id)
+		} else {
+			p.c.storeGroups[id] = group
+		}
+	}
 	p.c.adjust()
 }
 
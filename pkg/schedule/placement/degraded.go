@@ -0,0 +1,57 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"github.com/tikv/pd/pkg/slice"
+)
+
+// RelaxRulesForDownZones returns the relaxed copies of the rules which cannot
+// be satisfied when the given zones are entirely down, the rules not affected
+// are not returned. A rule is relaxed by:
+//   - removing the zone isolation level, so that the replicas of the down zones
+//     can be placed in the remaining zones.
+//   - removing the zone constraint whose zones are all down, so that the
+//     replicas pinned to the down zones can be placed in the remaining zones.
+func RelaxRulesForDownZones(rules []*Rule, zoneLabel string, downZones []string) []*Rule {
+	if zoneLabel == "" || len(downZones) == 0 {
+		return nil
+	}
+	var relaxed []*Rule
+	for _, rule := range rules {
+		var (
+			changed     bool
+			constraints []LabelConstraint
+		)
+		for _, c := range rule.LabelConstraints {
+			if c.Key == zoneLabel && c.Op == In && len(c.Values) > 0 &&
+				slice.AllOf(c.Values, func(i int) bool { return slice.Contains(downZones, c.Values[i]) }) {
+				changed = true
+				continue
+			}
+			constraints = append(constraints, c)
+		}
+		if !changed && rule.IsolationLevel != zoneLabel {
+			continue
+		}
+		clone := rule.Clone()
+		clone.LabelConstraints = constraints
+		if clone.IsolationLevel == zoneLabel {
+			clone.IsolationLevel = ""
+		}
+		relaxed = append(relaxed, clone)
+	}
+	return relaxed
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelaxRulesForDownZones(t *testing.T) {
+	re := require.New(t)
+	rules := []*Rule{
+		{GroupID: "pd", ID: "default", Role: Voter, Count: 3, LocationLabels: []string{"zone", "host"}, IsolationLevel: "zone"},
+		{GroupID: "pd", ID: "z1", Role: Voter, Count: 1, LabelConstraints: []LabelConstraint{{Key: "zone", Op: In, Values: []string{"z1"}}}},
+		{GroupID: "pd", ID: "z2", Role: Voter, Count: 1, LabelConstraints: []LabelConstraint{{Key: "zone", Op: In, Values: []string{"z2"}}}},
+		{GroupID: "pd", ID: "z12", Role: Learner, Count: 1, LabelConstraints: []LabelConstraint{
+			{Key: "zone", Op: In, Values: []string{"z1", "z2"}},
+			{Key: "engine", Op: In, Values: []string{"tiflash"}},
+		}},
+	}
+	re.Empty(RelaxRulesForDownZones(rules, "zone", nil))
+
+	relaxed := RelaxRulesForDownZones(rules, "zone", []string{"z1"})
+	re.Len(relaxed, 2)
+	re.Equal("default", relaxed[0].ID)
+	re.Empty(relaxed[0].IsolationLevel)
+	re.Equal([]string{"zone", "host"}, relaxed[0].LocationLabels)
+	re.Equal("z1", relaxed[1].ID)
+	re.Empty(relaxed[1].LabelConstraints)
+	// the original rules are not changed.
+	re.Equal("zone", rules[0].IsolationLevel)
+	re.Len(rules[1].LabelConstraints, 1)
+
+	relaxed = RelaxRulesForDownZones(rules, "zone", []string{"z1", "z2"})
+	re.Len(relaxed, 4)
+	re.Equal([]LabelConstraint{{Key: "engine", Op: In, Values: []string{"tiflash"}}}, relaxed[3].LabelConstraints)
+}
@@ -15,7 +15,6 @@
 package placement
 
 import (
-	"math"
 	"math/bits"
 	"sort"
 
@@ -23,8 +22,6 @@ import (
 	"github.com/tikv/pd/pkg/core"
 )
 
-const replicaBaseScore = 100
-
 // RegionFit is the result of fitting a region's peers to rule list.
 // All peers are divided into corresponding rules according to the matching
 // rules, and the remaining Peers are placed in the OrphanPeers list.
@@ -59,11 +56,11 @@ func (f *RegionFit) Replace(srcStoreID uint64, dstStore *core.StoreInfo) bool {
 	}
 
 	// the target store should be fit all constraints.
-	if !MatchLabelConstraints(dstStore, fit.Rule.LabelConstraints) {
+	if !fit.Rule.MatchStore(dstStore) {
 		return false
 	}
 
-	score := isolationStoreScore(srcStoreID, dstStore, fit.stores, fit.Rule.LocationLabels)
+	score := isolationStoreScore(srcStoreID, dstStore, fit.stores, fit.Rule.LocationLabels, fit.locationWeights)
 	// restore the source store.
 	return fit.IsolationScore <= score
 }
@@ -134,6 +131,9 @@ type RuleFit struct {
 	WitnessScore   int     `json:"witness-score"`
 	// stores is the stores that the peers are placed in.
 	stores []*core.StoreInfo
+	// locationWeights is the weights of the location labels used to calculate
+	// IsolationScore, nil means the default weights.
+	locationWeights []float64
 }
 
 // IsSatisfied returns if the rule is properly satisfied.
@@ -179,9 +179,13 @@ type StoreSet interface {
 	GetStore(id uint64) *core.StoreInfo
 }
 
+// locationWeightsFunc returns the weights of the location labels of a rule, nil
+// means the default weights.
+type locationWeightsFunc func(labels []string) []float64
+
 // fitRegion tries to fit peers of a region to the rules.
-func fitRegion(stores []*core.StoreInfo, region *core.RegionInfo, rules []*Rule, supportWitness bool) *RegionFit {
-	w := newFitWorker(stores, region, rules, supportWitness)
+func fitRegion(stores []*core.StoreInfo, region *core.RegionInfo, rules []*Rule, supportWitness bool, locationWeights locationWeightsFunc) *RegionFit {
+	w := newFitWorker(stores, region, rules, supportWitness, locationWeights)
 	w.run()
 	return &w.bestFit
 }
@@ -194,6 +198,8 @@ type fitWorker struct {
 	supportWitness bool
 	needIsolation  bool
 	exit           bool
+	// locationWeights is the weights of the location labels of each rule.
+	locationWeights [][]float64
 }
 
 func newFitPeer(stores []*core.StoreInfo, region *core.RegionInfo, fitPeers []*metapb.Peer) []*fitPeer {
@@ -209,7 +215,7 @@ func newFitPeer(stores []*core.StoreInfo, region *core.RegionInfo, fitPeers []*m
 	return peers
 }
 
-func newFitWorker(stores []*core.StoreInfo, region *core.RegionInfo, rules []*Rule, supportWitness bool) *fitWorker {
+func newFitWorker(stores []*core.StoreInfo, region *core.RegionInfo, rules []*Rule, supportWitness bool, locationWeights locationWeightsFunc) *fitWorker {
 	peers := newFitPeer(stores, region, region.GetPeers())
 	// Sort peers to keep the match result deterministic.
 	sort.Slice(peers, func(i, j int) bool {
@@ -217,13 +223,20 @@ func newFitWorker(stores []*core.StoreInfo, region *core.RegionInfo, rules []*Ru
 		si, sj := stateScore(region, peers[i].GetId()), stateScore(region, peers[j].GetId())
 		return si > sj || (si == sj && peers[i].GetId() < peers[j].GetId())
 	})
+	weights := make([][]float64, len(rules))
+	if locationWeights != nil {
+		for i, rule := range rules {
+			weights[i] = locationWeights(rule.LocationLabels)
+		}
+	}
 	return &fitWorker{
-		stores:         stores,
-		bestFit:        RegionFit{RuleFits: make([]*RuleFit, len(rules))},
-		peers:          peers,
-		needIsolation:  needIsolation(rules),
-		rules:          rules,
-		supportWitness: supportWitness,
+		stores:          stores,
+		bestFit:         RegionFit{RuleFits: make([]*RuleFit, len(rules))},
+		peers:           peers,
+		needIsolation:   needIsolation(rules),
+		rules:           rules,
+		supportWitness:  supportWitness,
+		locationWeights: weights,
 	}
 }
 
@@ -256,7 +269,7 @@ func (w *fitWorker) fitRule(index int) bool {
 		// 3. Don't select leader as witness.
 		// 4. Not selected by other rules.
 		for _, p := range w.peers {
-			if !p.selected && MatchLabelConstraints(p.store, w.rules[index].LabelConstraints) && !(p.isLeader && w.supportWitness && w.rules[index].IsWitness) {
+			if !p.selected && w.rules[index].MatchStore(p.store) && !(p.isLeader && w.supportWitness && w.rules[index].IsWitness) {
 				candidates = append(candidates, p)
 			}
 		}
@@ -323,7 +336,7 @@ func unSelectPeers(selected []*fitPeer) {
 // compareBest checks if the selected peers is better then previous best.
 // Returns true if it replaces `bestFit` with a better alternative.
 func (w *fitWorker) compareBest(selected []*fitPeer, index int) bool {
-	rf := newRuleFit(w.rules[index], selected, w.supportWitness)
+	rf := newRuleFit(w.rules[index], selected, w.supportWitness, w.locationWeights[index])
 	cmp := 1
 	if best := w.bestFit.RuleFits[index]; best != nil {
 		cmp = compareRuleFit(rf, best)
@@ -361,8 +374,13 @@ func (w *fitWorker) updateOrphanPeers(index int) {
 	}
 }
 
-func newRuleFit(rule *Rule, peers []*fitPeer, supportWitness bool) *RuleFit {
-	rf := &RuleFit{Rule: rule, IsolationScore: isolationScore(peers, rule.LocationLabels), WitnessScore: witnessScore(peers, supportWitness && rule.IsWitness)}
+func newRuleFit(rule *Rule, peers []*fitPeer, supportWitness bool, locationWeights []float64) *RuleFit {
+	rf := &RuleFit{
+		Rule:            rule,
+		IsolationScore:  isolationScore(peers, rule.LocationLabels, locationWeights),
+		WitnessScore:    witnessScore(peers, supportWitness && rule.IsWitness),
+		locationWeights: locationWeights,
+	}
 	for _, p := range peers {
 		rf.Peers = append(rf.Peers, p.Peer)
 		rf.stores = append(rf.stores, p.store)
@@ -396,7 +414,7 @@ func (p *fitPeer) matchRoleStrict(role PeerRoleType) bool {
 	return false
 }
 
-func isolationStoreScore(srcStoreID uint64, dstStore *core.StoreInfo, stores []*core.StoreInfo, labels []string) float64 {
+func isolationStoreScore(srcStoreID uint64, dstStore *core.StoreInfo, stores []*core.StoreInfo, labels []string, weights []float64) float64 {
 	var score float64
 	if len(labels) == 0 || len(stores) <= 1 {
 		return 0
@@ -411,14 +429,14 @@ func isolationStoreScore(srcStoreID uint64, dstStore *core.StoreInfo, stores []*
 				store2 = dstStore
 			}
 			if index := store1.CompareLocation(store2, labels); index != -1 {
-				score += math.Pow(replicaBaseScore, float64(len(labels)-index-1))
+				score += core.LocationDistinctScore(labels, weights, index)
 			}
 		}
 	}
 	return score
 }
 
-func isolationScore(peers []*fitPeer, labels []string) float64 {
+func isolationScore(peers []*fitPeer, labels []string, weights []float64) float64 {
 	var score float64
 	if len(labels) == 0 || len(peers) <= 1 {
 		return 0
@@ -432,7 +450,7 @@ func isolationScore(peers []*fitPeer, labels []string) float64 {
 	for i, p1 := range peers {
 		for _, p2 := range peers[i+1:] {
 			if index := p1.store.CompareLocation(p2.store, labels); index != -1 {
-				score += math.Pow(replicaBaseScore, float64(len(labels)-index-1))
+				score += core.LocationDistinctScore(labels, weights, index)
 			}
 		}
 	}
@@ -155,7 +155,7 @@ func TestReplace(t *testing.T) {
 		for _, r := range tc.rules {
 			rules = append(rules, makeRule(r))
 		}
-		rf := fitRegion(stores.GetStores(), region, rules, false)
+		rf := fitRegion(stores.GetStores(), region, rules, false, nil)
 		re.True(rf.IsSatisfied())
 		rf.regionStores = stores.GetStores()
 		re.Equal(rf.Replace(tc.srcStoreID, stores.GetStore(tc.dstStoreID)), tc.ok)
@@ -200,7 +200,7 @@ func TestFitRegion(t *testing.T) {
 		for _, r := range testCase.rules {
 			rules = append(rules, makeRule(r))
 		}
-		rf := fitRegion(stores.GetStores(), region, rules, false)
+		rf := fitRegion(stores.GetStores(), region, rules, false, nil)
 		expects := strings.Split(testCase.fitPeers, "/")
 		for i, f := range rf.RuleFits {
 			re.True(checkPeerMatch(f.Peers, expects[i]))
@@ -239,12 +239,47 @@ func TestIsolationScore(t *testing.T) {
 
 	for _, testCase := range testCases {
 		peers1, peers2 := makePeers(testCase.peers1), makePeers(testCase.peers2)
-		score1 := isolationScore(peers1, []string{"zone", "rack", "host"})
-		score2 := isolationScore(peers2, []string{"zone", "rack", "host"})
+		score1 := isolationScore(peers1, []string{"zone", "rack", "host"}, nil)
+		score2 := isolationScore(peers2, []string{"zone", "rack", "host"}, nil)
 		testCase.checker(score1, score2)
 	}
 }
 
+func TestWeightedIsolationScore(t *testing.T) {
+	re := require.New(t)
+	stores := makeStores()
+	labels := []string{"zone", "rack"}
+	// the rack diversity is weighted higher than the zone diversity.
+	weights := []float64{1, 10}
+	makePeers := func(ids ...uint64) []*fitPeer {
+		var peers []*fitPeer
+		for _, id := range ids {
+			peers = append(peers, &fitPeer{
+				Peer:  &metapb.Peer{Id: id, StoreId: id},
+				store: stores.GetStore(id),
+			})
+		}
+		return peers
+	}
+	// a different zone is also a different rack, so it never ranks lower.
+	re.Equal(float64(11), isolationScore(makePeers(1111, 2111), labels, weights))
+	re.Equal(float64(10), isolationScore(makePeers(1111, 1211), labels, weights))
+	re.Equal(float64(0), isolationScore(makePeers(1111, 1112), labels, weights))
+
+	// the rule fit and the replacement check use the weights of the rule.
+	region := core.NewRegionInfo(&metapb.Region{Peers: []*metapb.Peer{
+		{Id: 1111, StoreId: 1111}, {Id: 1211, StoreId: 1211}, {Id: 2111, StoreId: 2111},
+	}}, &metapb.Peer{Id: 1111, StoreId: 1111})
+	rules := []*Rule{{GroupID: DefaultGroupID, ID: DefaultRuleID, Role: Voter, Count: 3, LocationLabels: labels}}
+	fit := fitRegion(getStoresByRegion(stores, region), region, rules, false, func(l []string) []float64 {
+		re.Equal(labels, l)
+		return weights
+	})
+	re.Equal(float64(11+11+10), fit.RuleFits[0].IsolationScore)
+	re.True(fit.Replace(1211, stores.GetStore(3111)))
+	re.False(fit.Replace(2111, stores.GetStore(1311)))
+}
+
 func TestPickPeersFromBinaryInt(t *testing.T) {
 	re := require.New(t)
 	var candidates []*fitPeer
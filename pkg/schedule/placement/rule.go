@@ -20,6 +20,7 @@ import (
 	"sort"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/core"
 )
 
 // PeerRoleType is the expected peer type of the placement rule.
@@ -66,11 +67,13 @@ type Rule struct {
 	IsWitness        bool              `json:"is_witness"`                  // when it is true, it means the role is also a witness
 	Count            int               `json:"count"`                       // expected count of the peers
 	LabelConstraints []LabelConstraint `json:"label_constraints,omitempty"` // used to select stores to place peers
+	StoreGroup       string            `json:"store_group,omitempty"`       // used to select stores in the store group to place peers
 	LocationLabels   []string          `json:"location_labels,omitempty"`   // used to make peers isolated physically
 	IsolationLevel   string            `json:"isolation_level,omitempty"`   // used to isolate replicas explicitly and forcibly
 	Version          uint64            `json:"version,omitempty"`           // only set at runtime, add 1 each time rules updated, begin from 0.
 	CreateTimestamp  uint64            `json:"create_timestamp,omitempty"`  // only set at runtime, recorded rule create timestamp
 	group            *RuleGroup        // only set at runtime, no need to {,un}marshal or persist.
+	storeGroup       *StoreGroup       // only set at runtime, no need to {,un}marshal or persist.
 }
 
 // NewRuleFromJSON creates a rule from the JSON data.
@@ -93,9 +96,30 @@ func (r *Rule) Clone() *Rule {
 	_ = json.Unmarshal([]byte(r.String()), &clone)
 	clone.StartKey = append(r.StartKey[:0:0], r.StartKey...)
 	clone.EndKey = append(r.EndKey[:0:0], r.EndKey...)
+	clone.storeGroup = r.storeGroup
 	return &clone
 }
 
+// StoreConstraints returns the label constraints to select the stores of the
+// rule, which include the selectors of the store group if it's specified.
+func (r *Rule) StoreConstraints() []LabelConstraint {
+	if r.storeGroup == nil {
+		return r.LabelConstraints
+	}
+	constraints := make([]LabelConstraint, 0, len(r.LabelConstraints)+len(r.storeGroup.Selectors))
+	constraints = append(constraints, r.LabelConstraints...)
+	return append(constraints, r.storeGroup.Selectors...)
+}
+
+// MatchStore checks if a store can be selected to place the peers of the rule.
+func (r *Rule) MatchStore(store *core.StoreInfo) bool {
+	if r.StoreGroup != "" && r.storeGroup == nil {
+		// The store group is not loaded yet, no store can be selected.
+		return false
+	}
+	return MatchLabelConstraints(store, r.StoreConstraints())
+}
+
 // Key returns (groupID, ID) as the global unique key of a rule.
 func (r *Rule) Key() [2]string {
 	return [2]string{r.GroupID, r.ID}
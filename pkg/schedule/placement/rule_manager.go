@@ -63,6 +63,14 @@ type RuleManager struct {
 	storeSetInformer core.StoreSetInformer
 	cache            *RegionRuleFitCacheManager
 	conf             config.SharedConfigProvider
+
+	// onChange is called after the rules are changed.
+	onChange func()
+	// validate is called before the rules are changed by users, an error rejects the change.
+	validate func(oldChange, newChange *RulesChange) error
+	// version is increased once the rules are changed, it's used to detect the
+	// changes made during the validation.
+	version uint64
 }
 
 // NewRuleManager creates a RuleManager instance.
@@ -92,6 +100,9 @@ func (m *RuleManager) Initialize(maxReplica int, locationLabels []string, isolat
 	if err := m.loadGroups(); err != nil {
 		return err
 	}
+	if err := m.loadStoreGroups(); err != nil {
+		return err
+	}
 	if len(m.ruleConfig.rules) == 0 {
 		// migrate from old config.
 		var defaultRules []*Rule
@@ -215,6 +226,17 @@ func (m *RuleManager) loadGroups() error {
 	})
 }
 
+func (m *RuleManager) loadStoreGroups() error {
+	return m.storage.LoadStoreGroups(func(k, v string) {
+		g, err := NewStoreGroupFromJSON([]byte(v))
+		if err != nil {
+			log.Error("failed to unmarshal store group", zap.String("group-id", k), errs.ZapError(errs.ErrLoadStoreGroup, err))
+			return
+		}
+		m.ruleConfig.storeGroups[g.ID] = g
+	})
+}
+
 // AdjustRule check and adjust rule from client or storage.
 func (m *RuleManager) AdjustRule(r *Rule, groupID string) (err error) {
 	r.StartKey, err = hex.DecodeString(r.StartKeyHex)
@@ -306,11 +328,10 @@ func (m *RuleManager) SetRule(rule *Rule) error {
 	if err := m.AdjustRule(rule, ""); err != nil {
 		return err
 	}
-	m.Lock()
-	defer m.Unlock()
-	p := m.BeginPatch()
-	p.SetRule(rule)
-	if err := m.TryCommitPatchLocked(p); err != nil {
+	if err := m.commitPatch(true, func(p *RuleConfigPatch) error {
+		p.SetRule(rule)
+		return nil
+	}); err != nil {
 		return err
 	}
 	log.Info("placement rule updated", zap.String("rule", fmt.Sprint(rule)))
@@ -319,11 +340,10 @@ func (m *RuleManager) SetRule(rule *Rule) error {
 
 // DeleteRule removes a Rule.
 func (m *RuleManager) DeleteRule(group, id string) error {
-	m.Lock()
-	defer m.Unlock()
-	p := m.BeginPatch()
-	p.DeleteRule(group, id)
-	if err := m.TryCommitPatchLocked(p); err != nil {
+	if err := m.commitPatch(true, func(p *RuleConfigPatch) error {
+		p.DeleteRule(group, id)
+		return nil
+	}); err != nil {
 		return err
 	}
 	log.Info("placement rule is removed", zap.String("group", group), zap.String("id", id))
@@ -341,6 +361,10 @@ func (m *RuleManager) GetSplitKeys(start, end []byte) [][]byte {
 func (m *RuleManager) GetAllRules() []*Rule {
 	m.RLock()
 	defer m.RUnlock()
+	return m.getAllRulesLocked()
+}
+
+func (m *RuleManager) getAllRulesLocked() []*Rule {
 	rules := make([]*Rule, 0, len(m.ruleConfig.rules))
 	for _, r := range m.ruleConfig.rules {
 		rules = append(rules, r.Clone())
@@ -416,6 +440,10 @@ func (m *RuleManager) IsRegionFitCached(storeSet StoreSet, region *core.RegionIn
 	return isCached
 }
 
+func (m *RuleManager) locationWeightsOf(labels []string) []float64 {
+	return config.LocationWeightsOf(m.conf, labels)
+}
+
 // FitRegion fits a region to the rules it matches.
 func (m *RuleManager) FitRegion(storeSet StoreSet, region *core.RegionInfo) (fit *RegionFit) {
 	regionStores := getStoresByRegion(storeSet, region)
@@ -426,7 +454,7 @@ func (m *RuleManager) FitRegion(storeSet StoreSet, region *core.RegionInfo) (fit
 			return fit
 		}
 	}
-	fit = fitRegion(regionStores, region, rules, m.conf.IsWitnessAllowed())
+	fit = fitRegion(regionStores, region, rules, m.conf.IsWitnessAllowed(), m.locationWeightsOf)
 	fit.regionStores = regionStores
 	fit.rules = rules
 	if isCached {
@@ -463,6 +491,20 @@ func (m *RuleManager) CheckIsCachedDirectly(regionID uint64) bool {
 	return ok
 }
 
+// SetChangeCallback sets the callback which is called after the rules are
+// changed. It should be called before the rule manager is used.
+func (m *RuleManager) SetChangeCallback(f func()) {
+	m.onChange = f
+}
+
+// SetValidateCallback sets the callback which is called with the changed part
+// of the rules before they are changed by users, the change is rejected if it
+// returns an error. It's called without the lock held. It should be called
+// before the rule manager is used.
+func (m *RuleManager) SetValidateCallback(f func(oldChange, newChange *RulesChange) error) {
+	m.validate = f
+}
+
 // BeginPatch returns a patch for multiple changes.
 func (m *RuleManager) BeginPatch() *RuleConfigPatch {
 	return m.ruleConfig.beginPatch()
@@ -471,6 +513,9 @@ func (m *RuleManager) BeginPatch() *RuleConfigPatch {
 // TryCommitPatchLocked tries to commit a patch.
 func (m *RuleManager) TryCommitPatchLocked(patch *RuleConfigPatch) error {
 	patch.adjust()
+	if err := patch.checkStoreGroups(); err != nil {
+		return err
+	}
 
 	ruleList, err := buildRuleList(patch)
 	if err != nil {
@@ -486,11 +531,73 @@ func (m *RuleManager) TryCommitPatchLocked(patch *RuleConfigPatch) error {
 	}
 
 	// update in-memory state
+	changed := patch.changed()
 	patch.commit()
 	m.ruleList = ruleList
+	if changed {
+		m.version++
+		if m.onChange != nil {
+			m.onChange()
+		}
+	}
 	return nil
 }
 
+// maxValidateRetries is the max times to validate a change again when the rules
+// are changed by others during the validation.
+const maxValidateRetries = 3
+
+// commitPatch builds a patch by the build func and commits it. If validate is
+// set, the change is validated without holding the lock first, since the
+// validators may be slow, e.g. the webhooks, and holding the lock would block
+// the rule fitting. Then the patch is rebuilt and committed under the lock if
+// the rules aren't changed since the validation.
+func (m *RuleManager) commitPatch(validate bool, build func(p *RuleConfigPatch) error) error {
+	for i := 0; ; i++ {
+		var version uint64
+		if validate && m.validate != nil {
+			var err error
+			if version, err = m.validatePatch(build); err != nil {
+				return err
+			}
+		}
+		m.Lock()
+		if validate && m.validate != nil && m.version != version {
+			m.Unlock()
+			if i >= maxValidateRetries {
+				return errs.ErrRulesChanged.FastGenByArgs()
+			}
+			continue
+		}
+		p := m.BeginPatch()
+		err := build(p)
+		if err == nil {
+			err = m.TryCommitPatchLocked(p)
+		}
+		m.Unlock()
+		return err
+	}
+}
+
+// validatePatch builds a patch by the build func and validates the change of it.
+// It returns the version of the rules which the change is based on.
+func (m *RuleManager) validatePatch(build func(p *RuleConfigPatch) error) (uint64, error) {
+	m.RLock()
+	p := m.BeginPatch()
+	if err := build(p); err != nil {
+		m.RUnlock()
+		return 0, err
+	}
+	p.trim()
+	version, changed := m.version, p.changed()
+	oldChange, newChange := p.change()
+	m.RUnlock()
+	if !changed {
+		return version, nil
+	}
+	return version, m.validate(oldChange, newChange)
+}
+
 func (m *RuleManager) savePatch(p *ruleConfig) error {
 	var batch []func(kv.Txn) error
 	// add rules to batch
@@ -520,21 +627,58 @@ func (m *RuleManager) savePatch(p *ruleConfig) error {
 			})
 		}
 	}
+	// add store groups to batch
+	for id, g := range p.storeGroups {
+		localID, localGroup := id, g
+		if g == nil {
+			batch = append(batch, func(txn kv.Txn) error {
+				return m.storage.DeleteStoreGroup(txn, localID)
+			})
+		} else {
+			batch = append(batch, func(txn kv.Txn) error {
+				return m.storage.SaveStoreGroup(txn, localID, localGroup)
+			})
+		}
+	}
 	return endpoint.RunBatchOpInTxn(m.ctx, m.storage, batch)
 }
 
 // SetRules inserts or updates lots of Rules at once.
 func (m *RuleManager) SetRules(rules []*Rule) error {
-	m.Lock()
-	defer m.Unlock()
-	p := m.BeginPatch()
+	return m.setRules(rules, true, nil)
+}
+
+// SetRulesIfMatch inserts or updates lots of Rules at once if the check passes.
+// The check is called with all the current rules under the same lock as the
+// update, and the update is aborted if it returns an error.
+func (m *RuleManager) SetRulesIfMatch(rules []*Rule, check func(rules []*Rule) error) error {
+	return m.setRules(rules, true, check)
+}
+
+// SetRulesWithoutValidation inserts or updates lots of Rules at once without
+// validating the change. It's used by the changes made by PD itself rather than
+// users, e.g. restoring the rules, which shouldn't be rejected.
+func (m *RuleManager) SetRulesWithoutValidation(rules []*Rule) error {
+	return m.setRules(rules, false, nil)
+}
+
+func (m *RuleManager) setRules(rules []*Rule, validate bool, check func(rules []*Rule) error) error {
 	for _, r := range rules {
 		if err := m.AdjustRule(r, ""); err != nil {
 			return err
 		}
-		p.SetRule(r)
 	}
-	if err := m.TryCommitPatchLocked(p); err != nil {
+	if err := m.commitPatch(validate, func(p *RuleConfigPatch) error {
+		if check != nil {
+			if err := check(m.getAllRulesLocked()); err != nil {
+				return err
+			}
+		}
+		for _, r := range rules {
+			p.SetRule(r)
+		}
+		return nil
+	}); err != nil {
 		return err
 	}
 
@@ -576,28 +720,25 @@ func (m *RuleManager) Batch(todo []RuleOp) error {
 		}
 	}
 
-	m.Lock()
-	defer m.Unlock()
-
-	patch := m.BeginPatch()
-	for _, t := range todo {
-		switch t.Action {
-		case RuleOpAdd:
-			patch.SetRule(t.Rule)
-		case RuleOpDel:
-			if !t.DeleteByIDPrefix {
-				patch.DeleteRule(t.GroupID, t.ID)
-			} else {
-				m.ruleConfig.iterateRules(func(r *Rule) {
-					if r.GroupID == t.GroupID && strings.HasPrefix(r.ID, t.ID) {
-						patch.DeleteRule(r.GroupID, r.ID)
-					}
-				})
+	if err := m.commitPatch(true, func(patch *RuleConfigPatch) error {
+		for _, t := range todo {
+			switch t.Action {
+			case RuleOpAdd:
+				patch.SetRule(t.Rule)
+			case RuleOpDel:
+				if !t.DeleteByIDPrefix {
+					patch.DeleteRule(t.GroupID, t.ID)
+				} else {
+					m.ruleConfig.iterateRules(func(r *Rule) {
+						if r.GroupID == t.GroupID && strings.HasPrefix(r.ID, t.ID) {
+							patch.DeleteRule(r.GroupID, r.ID)
+						}
+					})
+				}
 			}
 		}
-	}
-
-	if err := m.TryCommitPatchLocked(patch); err != nil {
+		return nil
+	}); err != nil {
 		return err
 	}
 
@@ -629,11 +770,10 @@ func (m *RuleManager) GetRuleGroups() []*RuleGroup {
 
 // SetRuleGroup updates a RuleGroup.
 func (m *RuleManager) SetRuleGroup(group *RuleGroup) error {
-	m.Lock()
-	defer m.Unlock()
-	p := m.BeginPatch()
-	p.SetGroup(group)
-	if err := m.TryCommitPatchLocked(p); err != nil {
+	if err := m.commitPatch(true, func(p *RuleConfigPatch) error {
+		p.SetGroup(group)
+		return nil
+	}); err != nil {
 		return err
 	}
 	log.Info("group config updated", zap.String("group", fmt.Sprint(group)))
@@ -642,22 +782,94 @@ func (m *RuleManager) SetRuleGroup(group *RuleGroup) error {
 
 // DeleteRuleGroup removes a RuleGroup.
 func (m *RuleManager) DeleteRuleGroup(id string) error {
-	m.Lock()
-	defer m.Unlock()
-	p := m.BeginPatch()
-	p.DeleteGroup(id)
-	if err := m.TryCommitPatchLocked(p); err != nil {
+	if err := m.commitPatch(true, func(p *RuleConfigPatch) error {
+		p.DeleteGroup(id)
+		return nil
+	}); err != nil {
 		return err
 	}
 	log.Info("group config reset", zap.String("group", id))
 	return nil
 }
 
+// GetStoreGroup returns the StoreGroup with the same ID.
+func (m *RuleManager) GetStoreGroup(id string) *StoreGroup {
+	m.RLock()
+	defer m.RUnlock()
+	return m.ruleConfig.getStoreGroup(id)
+}
+
+// GetStoreGroups returns all StoreGroup configuration.
+func (m *RuleManager) GetStoreGroups() []*StoreGroup {
+	m.RLock()
+	defer m.RUnlock()
+	groups := make([]*StoreGroup, 0, len(m.ruleConfig.storeGroups))
+	for _, g := range m.ruleConfig.storeGroups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups
+}
+
+// GetRulesByStoreGroup returns sorted rules which target the store group.
+func (m *RuleManager) GetRulesByStoreGroup(id string) []*Rule {
+	m.RLock()
+	defer m.RUnlock()
+	return m.GetRulesByStoreGroupLocked(id)
+}
+
+// GetRulesByStoreGroupLocked returns sorted rules which target the store group.
+func (m *RuleManager) GetRulesByStoreGroupLocked(id string) []*Rule {
+	var rules []*Rule
+	for _, r := range m.ruleConfig.rules {
+		if r.StoreGroup == id {
+			rules = append(rules, r.Clone())
+		}
+	}
+	sortRules(rules)
+	return rules
+}
+
+// SetStoreGroup inserts or updates a StoreGroup, the rules targeting it will
+// select the stores by the new selectors.
+func (m *RuleManager) SetStoreGroup(group *StoreGroup) error {
+	if err := group.validate(); err != nil {
+		return err
+	}
+	if err := m.commitPatch(true, func(p *RuleConfigPatch) error {
+		p.SetStoreGroup(group)
+		return nil
+	}); err != nil {
+		return err
+	}
+	log.Info("store group updated", zap.String("store-group", fmt.Sprint(group)))
+	return nil
+}
+
+// DeleteStoreGroup removes a StoreGroup, it fails if any rule still targets it.
+func (m *RuleManager) DeleteStoreGroup(id string) error {
+	if err := m.commitPatch(true, func(p *RuleConfigPatch) error {
+		if m.ruleConfig.getStoreGroup(id) == nil {
+			return errs.ErrStoreGroupNotFound.FastGenByArgs(id)
+		}
+		p.DeleteStoreGroup(id)
+		return nil
+	}); err != nil {
+		return err
+	}
+	log.Info("store group removed", zap.String("store-group", id))
+	return nil
+}
+
 // GetAllGroupBundles returns all rules and groups configuration. Rules are
 // grouped by groups.
 func (m *RuleManager) GetAllGroupBundles() []GroupBundle {
 	m.RLock()
 	defer m.RUnlock()
+	return m.getAllGroupBundlesLocked()
+}
+
+func (m *RuleManager) getAllGroupBundlesLocked() []GroupBundle {
 	bundles := make([]GroupBundle, 0, len(m.ruleConfig.groups))
 	for _, g := range m.ruleConfig.groups {
 		bundles = append(bundles, GroupBundle{
@@ -702,9 +914,20 @@ func (m *RuleManager) GetGroupBundle(id string) (b GroupBundle) {
 
 // SetAllGroupBundles resets configuration. If override is true, all old configurations are dropped.
 func (m *RuleManager) SetAllGroupBundles(groups []GroupBundle, override bool) error {
-	m.Lock()
-	defer m.Unlock()
-	p := m.BeginPatch()
+	return m.SetAllGroupBundlesIfMatch(groups, override, nil)
+}
+
+// SetAllGroupBundlesIfMatch resets configuration like SetAllGroupBundles if the
+// check passes. The check is called with all the current group bundles under
+// the same lock as the update, and the update is aborted if it returns an error.
+func (m *RuleManager) SetAllGroupBundlesIfMatch(groups []GroupBundle, override bool, check func(bundles []GroupBundle) error) error {
+	for _, g := range groups {
+		for _, r := range g.Rules {
+			if err := m.AdjustRule(r, g.ID); err != nil {
+				return err
+			}
+		}
+	}
 	matchID := func(a string) bool {
 		for _, g := range groups {
 			if g.ID == a {
@@ -713,30 +936,34 @@ func (m *RuleManager) SetAllGroupBundles(groups []GroupBundle, override bool) er
 		}
 		return false
 	}
-	for k := range m.ruleConfig.rules {
-		if override || matchID(k[0]) {
-			p.DeleteRule(k[0], k[1])
+	if err := m.commitPatch(true, func(p *RuleConfigPatch) error {
+		if check != nil {
+			if err := check(m.getAllGroupBundlesLocked()); err != nil {
+				return err
+			}
 		}
-	}
-	for id := range m.ruleConfig.groups {
-		if override || matchID(id) {
-			p.DeleteGroup(id)
+		for k := range m.ruleConfig.rules {
+			if override || matchID(k[0]) {
+				p.DeleteRule(k[0], k[1])
+			}
 		}
-	}
-	for _, g := range groups {
-		p.SetGroup(&RuleGroup{
-			ID:       g.ID,
-			Index:    g.Index,
-			Override: g.Override,
-		})
-		for _, r := range g.Rules {
-			if err := m.AdjustRule(r, g.ID); err != nil {
-				return err
+		for id := range m.ruleConfig.groups {
+			if override || matchID(id) {
+				p.DeleteGroup(id)
 			}
-			p.SetRule(r)
 		}
-	}
-	if err := m.TryCommitPatchLocked(p); err != nil {
+		for _, g := range groups {
+			p.SetGroup(&RuleGroup{
+				ID:       g.ID,
+				Index:    g.Index,
+				Override: g.Override,
+			})
+			for _, r := range g.Rules {
+				p.SetRule(r)
+			}
+		}
+		return nil
+	}); err != nil {
 		return err
 	}
 	log.Info("full config reset", zap.String("config", fmt.Sprint(groups)))
@@ -746,28 +973,29 @@ func (m *RuleManager) SetAllGroupBundles(groups []GroupBundle, override bool) er
 // SetGroupBundle resets a Group and all rules belong to it. All old rules
 // belong to the Group are dropped.
 func (m *RuleManager) SetGroupBundle(group GroupBundle) error {
-	m.Lock()
-	defer m.Unlock()
-	p := m.BeginPatch()
-	if _, ok := m.ruleConfig.groups[group.ID]; ok {
-		for k := range m.ruleConfig.rules {
-			if k[0] == group.ID {
-				p.DeleteRule(k[0], k[1])
-			}
-		}
-	}
-	p.SetGroup(&RuleGroup{
-		ID:       group.ID,
-		Index:    group.Index,
-		Override: group.Override,
-	})
 	for _, r := range group.Rules {
 		if err := m.AdjustRule(r, group.ID); err != nil {
 			return err
 		}
-		p.SetRule(r)
 	}
-	if err := m.TryCommitPatchLocked(p); err != nil {
+	if err := m.commitPatch(true, func(p *RuleConfigPatch) error {
+		if _, ok := m.ruleConfig.groups[group.ID]; ok {
+			for k := range m.ruleConfig.rules {
+				if k[0] == group.ID {
+					p.DeleteRule(k[0], k[1])
+				}
+			}
+		}
+		p.SetGroup(&RuleGroup{
+			ID:       group.ID,
+			Index:    group.Index,
+			Override: group.Override,
+		})
+		for _, r := range group.Rules {
+			p.SetRule(r)
+		}
+		return nil
+	}); err != nil {
 		return err
 	}
 	log.Info("group is reset", zap.String("group", fmt.Sprint(group)))
@@ -777,8 +1005,6 @@ func (m *RuleManager) SetGroupBundle(group GroupBundle) error {
 // DeleteGroupBundle removes a Group and all rules belong to it. If `regex` is
 // true, `id` is a regexp expression.
 func (m *RuleManager) DeleteGroupBundle(id string, regex bool) error {
-	m.Lock()
-	defer m.Unlock()
 	matchID := func(a string) bool { return a == id }
 	if regex {
 		r, err := regexp.Compile(id)
@@ -788,18 +1014,19 @@ func (m *RuleManager) DeleteGroupBundle(id string, regex bool) error {
 		matchID = r.MatchString
 	}
 
-	p := m.BeginPatch()
-	for k := range m.ruleConfig.rules {
-		if matchID(k[0]) {
-			p.DeleteRule(k[0], k[1])
+	if err := m.commitPatch(true, func(p *RuleConfigPatch) error {
+		for k := range m.ruleConfig.rules {
+			if matchID(k[0]) {
+				p.DeleteRule(k[0], k[1])
+			}
 		}
-	}
-	for _, g := range m.ruleConfig.groups {
-		if matchID(g.ID) {
-			p.DeleteGroup(g.ID)
+		for _, g := range m.ruleConfig.groups {
+			if matchID(g.ID) {
+				p.DeleteGroup(g.ID)
+			}
 		}
-	}
-	if err := m.TryCommitPatchLocked(p); err != nil {
+		return nil
+	}); err != nil {
 		return err
 	}
 	log.Info("groups are removed", zap.String("id", id), zap.Bool("regexp", regex))
@@ -19,11 +19,13 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/codec"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/constant"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/mock/mockconfig"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/storage/kv"
@@ -358,6 +360,42 @@ func TestGroupConfig(t *testing.T) {
 	re.Equal([]*RuleGroup{g2}, manager.GetRuleGroups())
 }
 
+func TestStoreGroup(t *testing.T) {
+	re := require.New(t)
+	store, manager := newTestManager(t, false)
+	re.Error(manager.SetStoreGroup(&StoreGroup{ID: "dedicated"}))
+	re.True(errs.ErrStoreGroupNotFound.Equal(manager.SetRule(&Rule{GroupID: "foo", ID: "bar", Role: Voter, Count: 1, StoreGroup: "dedicated"})))
+
+	group := &StoreGroup{ID: "dedicated", Selectors: []LabelConstraint{{Key: "disk", Op: In, Values: []string{"nvme"}}}}
+	re.NoError(manager.SetStoreGroup(group))
+	re.NoError(manager.SetRule(&Rule{GroupID: "foo", ID: "bar", Role: Voter, Count: 1, StoreGroup: "dedicated",
+		LabelConstraints: []LabelConstraint{{Key: "zone", Op: In, Values: []string{"z1"}}}}))
+	rule := manager.GetRule("foo", "bar")
+	re.True(rule.MatchStore(core.NewStoreInfoWithLabel(1, map[string]string{"zone": "z1", "disk": "nvme"})))
+	re.False(rule.MatchStore(core.NewStoreInfoWithLabel(2, map[string]string{"zone": "z1", "disk": "ssd"})))
+	re.False(rule.MatchStore(core.NewStoreInfoWithLabel(3, map[string]string{"zone": "z2", "disk": "nvme"})))
+
+	// the rule selects the stores by the updated selectors.
+	group = &StoreGroup{ID: "dedicated", Selectors: []LabelConstraint{{Key: "disk", Op: In, Values: []string{"ssd"}}}}
+	re.NoError(manager.SetStoreGroup(group))
+	rule = manager.GetRule("foo", "bar")
+	re.True(rule.MatchStore(core.NewStoreInfoWithLabel(2, map[string]string{"zone": "z1", "disk": "ssd"})))
+	re.Len(manager.GetRulesByStoreGroup("dedicated"), 1)
+
+	// the store group is persisted along with the rules.
+	m2 := NewRuleManager(context.Background(), store, nil, nil)
+	re.NoError(m2.Initialize(3, []string{"zone", "rack", "host"}, ""))
+	re.Equal([]*StoreGroup{group}, m2.GetStoreGroups())
+	re.True(m2.GetRule("foo", "bar").MatchStore(core.NewStoreInfoWithLabel(2, map[string]string{"zone": "z1", "disk": "ssd"})))
+
+	// the store group can't be deleted until no rule targets it.
+	re.True(errs.ErrStoreGroupInUse.Equal(manager.DeleteStoreGroup("dedicated")))
+	re.NoError(manager.DeleteRule("foo", "bar"))
+	re.NoError(manager.DeleteStoreGroup("dedicated"))
+	re.Empty(manager.GetStoreGroups())
+	re.True(errs.ErrStoreGroupNotFound.Equal(manager.DeleteStoreGroup("dedicated")))
+}
+
 func TestRuleVersion(t *testing.T) {
 	re := require.New(t)
 	_, manager := newTestManager(t, false)
@@ -499,3 +537,63 @@ func dhex(hk string) []byte {
 	}
 	return k
 }
+
+func TestValidateCallback(t *testing.T) {
+	re := require.New(t)
+	_, manager := newTestManager(t, false)
+	var oldChange, newChange *RulesChange
+	manager.SetValidateCallback(func(o, n *RulesChange) error {
+		oldChange, newChange = o, n
+		if len(n.Rules) > 0 && n.Rules[0].Count > 5 {
+			return errors.New("too many replicas")
+		}
+		return nil
+	})
+
+	rule := &Rule{GroupID: DefaultGroupID, ID: DefaultRuleID, Role: Voter, Count: 7}
+	re.Error(manager.SetRule(rule))
+	re.Equal(3, manager.GetRule(DefaultGroupID, DefaultRuleID).Count)
+	re.Len(oldChange.Rules, 1)
+	re.Equal(3, oldChange.Rules[0].Count)
+	re.Equal(7, newChange.Rules[0].Count)
+
+	rule = &Rule{GroupID: "foo", ID: "bar", Role: Learner, Count: 1}
+	re.NoError(manager.SetRule(rule))
+	re.Empty(oldChange.Rules)
+	re.Len(newChange.Rules, 1)
+	re.NoError(manager.DeleteRule("foo", "bar"))
+	re.Len(oldChange.Rules, 1)
+	re.Empty(newChange.Rules)
+
+	// The internal changes aren't validated.
+	rule = &Rule{GroupID: DefaultGroupID, ID: DefaultRuleID, Role: Voter, Count: 7}
+	re.NoError(manager.SetRulesWithoutValidation([]*Rule{rule}))
+	re.Equal(7, manager.GetRule(DefaultGroupID, DefaultRuleID).Count)
+}
+
+func TestValidateWithoutLock(t *testing.T) {
+	re := require.New(t)
+	_, manager := newTestManager(t, false)
+	validating, release := make(chan struct{}), make(chan struct{})
+	manager.SetValidateCallback(func(_, _ *RulesChange) error {
+		select {
+		case validating <- struct{}{}:
+			<-release
+		default:
+		}
+		return nil
+	})
+
+	done := make(chan error)
+	go func() {
+		done <- manager.SetRule(&Rule{GroupID: "foo", ID: "bar", Role: Learner, Count: 1})
+	}()
+	<-validating
+	// The rules can be read and changed by PD itself during the validation.
+	re.Len(manager.GetAllRules(), 1)
+	re.NoError(manager.SetRulesWithoutValidation([]*Rule{{GroupID: "foo", ID: "baz", Role: Learner, Count: 1}}))
+	close(release)
+	// The change is validated again since the rules are changed during the validation.
+	re.NoError(<-done)
+	re.Len(manager.GetAllRules(), 3)
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// StoreGroup is a named set of stores selected by the label constraints. The
+// placement rules and the scatter operations can target a store group to place
+// the peers on the dedicated stores only.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreGroup struct {
+	ID        string            `json:"id"`
+	Selectors []LabelConstraint `json:"selectors"`
+}
+
+// NewStoreGroupFromJSON creates a store group from the JSON data.
+func NewStoreGroupFromJSON(data []byte) (*StoreGroup, error) {
+	g := &StoreGroup{}
+	if err := json.Unmarshal(data, g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *StoreGroup) String() string {
+	b, _ := json.Marshal(g)
+	return string(b)
+}
+
+// Clone returns a copy of StoreGroup.
+func (g *StoreGroup) Clone() *StoreGroup {
+	var clone StoreGroup
+	_ = json.Unmarshal([]byte(g.String()), &clone)
+	return &clone
+}
+
+// MatchStore checks if a store belongs to the store group.
+func (g *StoreGroup) MatchStore(store *core.StoreInfo) bool {
+	return MatchLabelConstraints(store, g.Selectors)
+}
+
+func (g *StoreGroup) validate() error {
+	if g.ID == "" {
+		return errs.ErrStoreGroupContent.FastGenByArgs("id should not be empty")
+	}
+	if len(g.Selectors) == 0 {
+		return errs.ErrStoreGroupContent.FastGenByArgs("selectors should not be empty")
+	}
+	for _, c := range g.Selectors {
+		if !validateOp(c.Op) {
+			return errs.ErrStoreGroupContent.FastGenByArgs(fmt.Sprintf("invalid op %s", c.Op))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/tikv/pd/pkg/core"
+)
+
+const (
+	// DefaultZoneLabel is the default store label key used to distinguish the zones.
+	DefaultZoneLabel = "zone"
+	// maxDiscrepancySampleRegions is the max number of the sample regions recorded for a discrepancy.
+	maxDiscrepancySampleRegions = 16
+
+	// DiscrepancyInsufficientReplicas means the zone has fewer replicas than the rule prescribes.
+	DiscrepancyInsufficientReplicas = "insufficient-replicas"
+	// DiscrepancyExcessReplicas means the zone has more replicas than the rule prescribes.
+	DiscrepancyExcessReplicas = "excess-replicas"
+	// DiscrepancyOrphanPeer means the peer in the zone doesn't match any rule.
+	DiscrepancyOrphanPeer = "orphan-peer"
+)
+
+// ZoneDistribution is the distribution of the replicas across the zones compared
+// with what the rules prescribe.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ZoneDistribution struct {
+	// ZoneLabel is the store label key used to distinguish the zones.
+	ZoneLabel     string                  `json:"zone-label"`
+	RegionCount   int                     `json:"region-count"`
+	Rules         []*RuleZoneDistribution `json:"rules"`
+	OrphanPeers   map[string]int          `json:"orphan-peers,omitempty"`
+	Discrepancies []*ZoneDiscrepancy      `json:"discrepancies,omitempty"`
+	// NextPageToken is the page token of the next page of the regions, it's empty
+	// if all the regions have been taken into account.
+	NextPageToken string `json:"next_page_token,omitempty"`
+}
+
+// RuleZoneDistribution is the distribution of the replicas of a rule across the zones.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RuleZoneDistribution struct {
+	GroupID string                       `json:"group-id"`
+	ID      string                       `json:"id"`
+	Zones   map[string]*ZoneReplicaCount `json:"zones"`
+}
+
+// ZoneReplicaCount is the number of the replicas in a zone summed over the regions.
+// The rule prescribes the number of the replicas in the range of [Min, Max].
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ZoneReplicaCount struct {
+	Actual int `json:"actual"`
+	Min    int `json:"min"`
+	Max    int `json:"max"`
+}
+
+// ZoneDiscrepancy is the mismatch between the actual replicas in a zone and what
+// the rule prescribes. The orphan peers are not attributed to any rule.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ZoneDiscrepancy struct {
+	GroupID       string   `json:"group-id,omitempty"`
+	RuleID        string   `json:"rule-id,omitempty"`
+	Zone          string   `json:"zone"`
+	Reason        string   `json:"reason"`
+	RegionCount   int      `json:"region-count"`
+	SampleRegions []uint64 `json:"sample-regions"`
+}
+
+type discrepancyKey struct {
+	rule   [2]string
+	zone   string
+	reason string
+}
+
+// zoneDistributionBuilder accumulates the zone distribution of the regions.
+type zoneDistributionBuilder struct {
+	storeSet      StoreSet
+	zoneLabel     string
+	group         string
+	result        *ZoneDistribution
+	rules         map[[2]string]*RuleZoneDistribution
+	ruleZones     map[[2]string][]string
+	discrepancies map[discrepancyKey]*ZoneDiscrepancy
+}
+
+// GetZoneDistribution returns how the replicas of the given regions are distributed across
+// the zones versus what the rules prescribe. If the group is not empty, only the rules of
+// the group are taken into account.
+func (m *RuleManager) GetZoneDistribution(storeSet StoreSet, regions []*core.RegionInfo, zoneLabel, group string) *ZoneDistribution {
+	if len(zoneLabel) == 0 {
+		zoneLabel = DefaultZoneLabel
+	}
+	b := &zoneDistributionBuilder{
+		storeSet:      storeSet,
+		zoneLabel:     zoneLabel,
+		group:         group,
+		result:        &ZoneDistribution{ZoneLabel: zoneLabel, Rules: []*RuleZoneDistribution{}},
+		rules:         make(map[[2]string]*RuleZoneDistribution),
+		ruleZones:     make(map[[2]string][]string),
+		discrepancies: make(map[discrepancyKey]*ZoneDiscrepancy),
+	}
+	for _, region := range regions {
+		b.addRegion(region, m.FitRegion(storeSet, region))
+	}
+	return b.build()
+}
+
+func (b *zoneDistributionBuilder) addRegion(region *core.RegionInfo, fit *RegionFit) {
+	b.result.RegionCount++
+	for _, rf := range fit.RuleFits {
+		if len(b.group) > 0 && rf.Rule.GroupID != b.group {
+			continue
+		}
+		b.addRuleFit(region, rf)
+	}
+	if len(b.group) > 0 {
+		return
+	}
+	for _, peer := range fit.OrphanPeers {
+		zone := b.getZone(peer.GetStoreId())
+		if b.result.OrphanPeers == nil {
+			b.result.OrphanPeers = make(map[string]int)
+		}
+		b.result.OrphanPeers[zone]++
+		b.addDiscrepancy(region, nil, zone, DiscrepancyOrphanPeer)
+	}
+}
+
+func (b *zoneDistributionBuilder) addRuleFit(region *core.RegionInfo, rf *RuleFit) {
+	rule := rf.Rule
+	dist, ok := b.rules[rule.Key()]
+	if !ok {
+		dist = &RuleZoneDistribution{GroupID: rule.GroupID, ID: rule.ID, Zones: make(map[string]*ZoneReplicaCount)}
+		b.rules[rule.Key()] = dist
+		b.result.Rules = append(b.result.Rules, dist)
+	}
+	actual := make(map[string]int)
+	for _, peer := range rf.Peers {
+		actual[b.getZone(peer.GetStoreId())]++
+	}
+	candidates := b.getRuleZones(rule)
+	zones := slices.Clone(candidates)
+	for zone := range actual {
+		if !slices.Contains(zones, zone) {
+			zones = append(zones, zone)
+		}
+	}
+	isolated := b.isIsolatedByZone(rule)
+	attributed := false
+	for _, zone := range zones {
+		// The zones which don't match the rule shouldn't have any replica. If the rule
+		// isolates the replicas by zone, they should be spread evenly across the zones.
+		minCount, maxCount := 0, 0
+		if slices.Contains(candidates, zone) {
+			maxCount = rule.Count
+			if isolated {
+				minCount = rule.Count / len(candidates)
+				maxCount = (rule.Count + len(candidates) - 1) / len(candidates)
+			}
+		}
+		count, ok := dist.Zones[zone]
+		if !ok {
+			count = &ZoneReplicaCount{}
+			dist.Zones[zone] = count
+		}
+		count.Actual += actual[zone]
+		count.Min += minCount
+		count.Max += maxCount
+		switch {
+		case actual[zone] < minCount:
+			attributed = true
+			b.addDiscrepancy(region, rule, zone, DiscrepancyInsufficientReplicas)
+		case actual[zone] > maxCount:
+			b.addDiscrepancy(region, rule, zone, DiscrepancyExcessReplicas)
+		}
+	}
+	// The missing replicas can't be attributed to a zone if every zone has got its minimum.
+	if len(rf.Peers) < rule.Count && !attributed {
+		b.addDiscrepancy(region, rule, "", DiscrepancyInsufficientReplicas)
+	}
+}
+
+// getRuleZones returns the zones of the stores which match the rule.
+func (b *zoneDistributionBuilder) getRuleZones(rule *Rule) []string {
+	if zones, ok := b.ruleZones[rule.Key()]; ok {
+		return zones
+	}
+	zones := make([]string, 0)
+	for _, store := range b.storeSet.GetStores() {
+		if store.IsRemoved() || !rule.MatchStore(store) {
+			continue
+		}
+		zone := store.GetLabelValue(b.zoneLabel)
+		if !slices.Contains(zones, zone) {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+	b.ruleZones[rule.Key()] = zones
+	return zones
+}
+
+func (b *zoneDistributionBuilder) isIsolatedByZone(rule *Rule) bool {
+	return rule.IsolationLevel == b.zoneLabel || slices.Contains(rule.LocationLabels, b.zoneLabel)
+}
+
+func (b *zoneDistributionBuilder) getZone(storeID uint64) string {
+	store := b.storeSet.GetStore(storeID)
+	if store == nil {
+		return ""
+	}
+	return store.GetLabelValue(b.zoneLabel)
+}
+
+func (b *zoneDistributionBuilder) addDiscrepancy(region *core.RegionInfo, rule *Rule, zone, reason string) {
+	key := discrepancyKey{zone: zone, reason: reason}
+	if rule != nil {
+		key.rule = rule.Key()
+	}
+	d, ok := b.discrepancies[key]
+	if !ok {
+		d = &ZoneDiscrepancy{GroupID: key.rule[0], RuleID: key.rule[1], Zone: zone, Reason: reason}
+		b.discrepancies[key] = d
+		b.result.Discrepancies = append(b.result.Discrepancies, d)
+	}
+	d.RegionCount++
+	if len(d.SampleRegions) < maxDiscrepancySampleRegions {
+		d.SampleRegions = append(d.SampleRegions, region.GetID())
+	}
+}
+
+func (b *zoneDistributionBuilder) build() *ZoneDistribution {
+	sort.Slice(b.result.Rules, func(i, j int) bool {
+		return compareRuleKey(b.result.Rules[i], b.result.Rules[j])
+	})
+	sort.SliceStable(b.result.Discrepancies, func(i, j int) bool {
+		return b.result.Discrepancies[i].RegionCount > b.result.Discrepancies[j].RegionCount
+	})
+	return b.result
+}
+
+func compareRuleKey(a, b *RuleZoneDistribution) bool {
+	if a.GroupID != b.GroupID {
+		return a.GroupID < b.GroupID
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+)
+
+func TestZoneDistribution(t *testing.T) {
+	re := require.New(t)
+	_, manager := newTestManager(t, false)
+	stores := makeStores()
+	regions := []*core.RegionInfo{
+		makeRegion("1111,2111,3111"),
+		// two replicas in zone1
+		makeRegion("1111,1211,2111"),
+		// missing a replica
+		makeRegion("1111,2111"),
+		// an extra replica
+		makeRegion("1111,2111,3111,4111"),
+	}
+	for i, region := range regions {
+		regions[i] = region.Clone(core.WithNewRegionID(uint64(i + 1)))
+	}
+
+	dist := manager.GetZoneDistribution(stores, regions, "", "")
+	re.Equal(DefaultZoneLabel, dist.ZoneLabel)
+	re.Equal(len(regions), dist.RegionCount)
+	re.Len(dist.Rules, 1)
+	rule := dist.Rules[0]
+	re.Equal(DefaultGroupID, rule.GroupID)
+	re.Equal(DefaultRuleID, rule.ID)
+	// The default rule isolates the replicas by zone, there should be at most one replica in each zone.
+	re.Len(rule.Zones, 5)
+	re.Equal(&ZoneReplicaCount{Actual: 5, Min: 0, Max: 4}, rule.Zones["zone1"])
+	re.Equal(&ZoneReplicaCount{Actual: 0, Min: 0, Max: 4}, rule.Zones["zone5"])
+	re.Equal(map[string]int{"zone4": 1}, dist.OrphanPeers)
+
+	re.Len(dist.Discrepancies, 3)
+	reasons := make(map[string]*ZoneDiscrepancy)
+	for _, d := range dist.Discrepancies {
+		reasons[d.Reason] = d
+	}
+	re.Equal(&ZoneDiscrepancy{GroupID: DefaultGroupID, RuleID: DefaultRuleID, Zone: "zone1",
+		Reason: DiscrepancyExcessReplicas, RegionCount: 1, SampleRegions: []uint64{regions[1].GetID()}},
+		reasons[DiscrepancyExcessReplicas])
+	re.Equal(&ZoneDiscrepancy{GroupID: DefaultGroupID, RuleID: DefaultRuleID, Zone: "",
+		Reason: DiscrepancyInsufficientReplicas, RegionCount: 1, SampleRegions: []uint64{regions[2].GetID()}},
+		reasons[DiscrepancyInsufficientReplicas])
+	re.Equal(&ZoneDiscrepancy{Zone: "zone4", Reason: DiscrepancyOrphanPeer, RegionCount: 1,
+		SampleRegions: []uint64{regions[3].GetID()}}, reasons[DiscrepancyOrphanPeer])
+
+	// Only the rules of the given group are taken into account.
+	dist = manager.GetZoneDistribution(stores, regions, "", "other")
+	re.Empty(dist.Rules)
+	re.Empty(dist.Discrepancies)
+}
@@ -76,14 +76,20 @@ func (p *BalanceSchedulerPlan) GetResource(step int) uint64 {
 	if p.Step < step {
 		return 0
 	}
-	// Please use with care. Add a nil check if need in the future
+	// The resource may be not picked yet if the plan fails at the step.
 	switch step {
 	case pickSource:
-		return p.Source.GetID()
+		if p.Source != nil {
+			return p.Source.GetID()
+		}
 	case pickRegion:
-		return p.Region.GetID()
+		if p.Region != nil {
+			return p.Region.GetID()
+		}
 	case pickTarget:
-		return p.Target.GetID()
+		if p.Target != nil {
+			return p.Target.GetID()
+		}
 	}
 	return 0
 }
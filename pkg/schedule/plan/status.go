@@ -51,6 +51,8 @@ const (
 	StatusStoreRejectLeader = iota + 300
 	// StatusStoreNotMatchIsolation represents the isolation cannot satisfy the requirement.
 	StatusStoreNotMatchIsolation
+	// StatusStoreQuarantined represents the store is quarantined for violating the label taxonomy.
+	StatusStoreQuarantined
 )
 
 // hard limitation
@@ -118,6 +120,7 @@ var statusText = map[StatusCode]string{
 	// store is limited by specified configuration
 	StatusStoreRejectLeader:      "StoreRejectLeader",
 	StatusStoreNotMatchIsolation: "StoreNotMatchIsolation",
+	StatusStoreQuarantined:       "StoreQuarantined",
 
 	// store is limited by hard constraint
 	StatusStoreLowSpace:     "StoreLowSpace",
@@ -162,8 +162,30 @@ func newEngineContext(ctx context.Context, filterFuncs ...filterFunc) engineCont
 	}
 }
 
+// ScatterOption is used to customize the scatter of the regions.
+type ScatterOption func(*scatterOptions)
+
+type scatterOptions struct {
+	storeGroup *placement.StoreGroup
+}
+
+// WithStoreGroup limits the target stores of the scatter to the store group.
+func WithStoreGroup(group *placement.StoreGroup) ScatterOption {
+	return func(opts *scatterOptions) {
+		opts.storeGroup = group
+	}
+}
+
+func newScatterOptions(opts ...ScatterOption) *scatterOptions {
+	options := &scatterOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
 // ScatterRegionsByRange directly scatter regions by ScatterRegions
-func (r *RegionScatterer) ScatterRegionsByRange(startKey, endKey []byte, group string, retryLimit int) (int, map[uint64]error, error) {
+func (r *RegionScatterer) ScatterRegionsByRange(startKey, endKey []byte, group string, retryLimit int, opts ...ScatterOption) (int, map[uint64]error, error) {
 	regions := r.cluster.ScanRegions(startKey, endKey, -1)
 	if len(regions) < 1 {
 		scatterSkipEmptyRegionCounter.Inc()
@@ -175,7 +197,7 @@ func (r *RegionScatterer) ScatterRegionsByRange(startKey, endKey []byte, group s
 		regionMap[region.GetID()] = region
 	}
 	// If there existed any region failed to relocated after retry, add it into unProcessedRegions
-	opsCount, err := r.scatterRegions(regionMap, failures, group, retryLimit, false)
+	opsCount, err := r.scatterRegions(regionMap, failures, group, retryLimit, false, opts...)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -183,7 +205,7 @@ func (r *RegionScatterer) ScatterRegionsByRange(startKey, endKey []byte, group s
 }
 
 // ScatterRegionsByID directly scatter regions by ScatterRegions
-func (r *RegionScatterer) ScatterRegionsByID(regionsID []uint64, group string, retryLimit int, skipStoreLimit bool) (int, map[uint64]error, error) {
+func (r *RegionScatterer) ScatterRegionsByID(regionsID []uint64, group string, retryLimit int, skipStoreLimit bool, opts ...ScatterOption) (int, map[uint64]error, error) {
 	if len(regionsID) < 1 {
 		scatterSkipEmptyRegionCounter.Inc()
 		return 0, nil, errEmptyRegion
@@ -212,7 +234,7 @@ func (r *RegionScatterer) ScatterRegionsByID(regionsID []uint64, group string, r
 		regionMap[region.GetID()] = region
 	}
 	// If there existed any region failed to relocated after retry, add it into unProcessedRegions
-	opsCount, err := r.scatterRegions(regionMap, failures, group, retryLimit, skipStoreLimit)
+	opsCount, err := r.scatterRegions(regionMap, failures, group, retryLimit, skipStoreLimit, opts...)
 	if err != nil {
 		return 0, nil, err
 	}
@@ -225,7 +247,7 @@ func (r *RegionScatterer) ScatterRegionsByID(regionsID []uint64, group string, r
 // time.Sleep between each retry.
 // Failures indicates the regions which are failed to be relocated, the key of the failures indicates the regionID
 // and the value of the failures indicates the failure error.
-func (r *RegionScatterer) scatterRegions(regions map[uint64]*core.RegionInfo, failures map[uint64]error, group string, retryLimit int, skipStoreLimit bool) (int, error) {
+func (r *RegionScatterer) scatterRegions(regions map[uint64]*core.RegionInfo, failures map[uint64]error, group string, retryLimit int, skipStoreLimit bool, opts ...ScatterOption) (int, error) {
 	if len(regions) < 1 {
 		scatterSkipEmptyRegionCounter.Inc()
 		return 0, errEmptyRegion
@@ -236,7 +258,7 @@ func (r *RegionScatterer) scatterRegions(regions map[uint64]*core.RegionInfo, fa
 	opsCount := 0
 	for currentRetry := 0; currentRetry <= retryLimit; currentRetry++ {
 		for _, region := range regions {
-			op, err := r.Scatter(region, group, skipStoreLimit)
+			op, err := r.Scatter(region, group, skipStoreLimit, opts...)
 			failpoint.Inject("scatterFail", func() {
 				if region.GetID() == 1 {
 					err = errors.New("mock error")
@@ -272,8 +294,9 @@ func (r *RegionScatterer) scatterRegions(regions map[uint64]*core.RegionInfo, fa
 }
 
 // Scatter relocates the region. If the group is defined, the regions' leader with the same group would be scattered
-// in a group level instead of cluster level.
-func (r *RegionScatterer) Scatter(region *core.RegionInfo, group string, skipStoreLimit bool) (*operator.Operator, error) {
+// in a group level instead of cluster level. If the store group is specified, the peers would be scattered to the stores
+// of the store group only.
+func (r *RegionScatterer) Scatter(region *core.RegionInfo, group string, skipStoreLimit bool, opts ...ScatterOption) (*operator.Operator, error) {
 	if !filter.IsRegionReplicated(r.cluster, region) {
 		r.addSuspectRegions(region.GetID())
 		scatterSkipNotReplicatedCounter.Inc()
@@ -293,10 +316,11 @@ func (r *RegionScatterer) Scatter(region *core.RegionInfo, group string, skipSto
 		return nil, errors.Errorf("region %d is hot", region.GetID())
 	}
 
-	return r.scatterRegion(region, group, skipStoreLimit)
+	return r.scatterRegion(region, group, skipStoreLimit, opts...)
 }
 
-func (r *RegionScatterer) scatterRegion(region *core.RegionInfo, group string, skipStoreLimit bool) (*operator.Operator, error) {
+func (r *RegionScatterer) scatterRegion(region *core.RegionInfo, group string, skipStoreLimit bool, opts ...ScatterOption) (*operator.Operator, error) {
+	options := newScatterOptions(opts...)
 	engineFilter := filter.NewEngineFilter(r.name, filter.NotSpecialEngines)
 	ordinaryPeers := make(map[uint64]*metapb.Peer, len(region.GetPeers()))
 	specialPeers := make(map[string]map[uint64]*metapb.Peer)
@@ -328,6 +352,9 @@ func (r *RegionScatterer) scatterRegion(region *core.RegionInfo, group string, s
 			filters[i] = filterFunc()
 		}
 		filters[filterLen-2] = filter.NewExcludedFilter(r.name, nil, selectedStores)
+		if options.storeGroup != nil {
+			filters = append(filters, filter.NewLabelConstraintFilter(r.name, options.storeGroup.Selectors))
+		}
 		for _, peer := range peers {
 			if _, ok := selectedStores[peer.GetStoreId()]; ok {
 				if allowLeader(oldFit, peer) {
@@ -62,6 +62,9 @@ type balanceLeaderSchedulerConfig struct {
 	Ranges  []core.KeyRange `json:"ranges"`
 	// Batch is used to generate multiple operators by one scheduling
 	Batch int `json:"batch"`
+	// Policy is used to balance the leaders by the request load when it's "load",
+	// otherwise the leader-schedule-policy is followed.
+	Policy string `json:"policy,omitempty"`
 }
 
 func (conf *balanceLeaderSchedulerConfig) Update(data []byte) (int, any) {
@@ -75,6 +78,12 @@ func (conf *balanceLeaderSchedulerConfig) Update(data []byte) (int, any) {
 	}
 	newConfig, _ := json.Marshal(conf)
 	if !bytes.Equal(oldConfig, newConfig) {
+		if len(conf.Policy) > 0 && conf.Policy != balanceLeaderPolicyLoad {
+			if err := json.Unmarshal(oldConfig, conf); err != nil {
+				return http.StatusInternalServerError, err.Error()
+			}
+			return http.StatusBadRequest, "invalid policy which should be empty or \"load\""
+		}
 		if !conf.validateLocked() {
 			if err := json.Unmarshal(oldConfig, conf); err != nil {
 				return http.StatusInternalServerError, err.Error()
@@ -110,6 +119,7 @@ func (conf *balanceLeaderSchedulerConfig) Clone() *balanceLeaderSchedulerConfig
 	return &balanceLeaderSchedulerConfig{
 		Ranges: ranges,
 		Batch:  conf.Batch,
+		Policy: conf.Policy,
 	}
 }
 
@@ -127,6 +137,12 @@ func (conf *balanceLeaderSchedulerConfig) getBatch() int {
 	return conf.Batch
 }
 
+func (conf *balanceLeaderSchedulerConfig) getPolicy() string {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.Policy
+}
+
 func (conf *balanceLeaderSchedulerConfig) getRanges() []core.KeyRange {
 	conf.RLock()
 	defer conf.RUnlock()
@@ -246,6 +262,7 @@ func (l *balanceLeaderScheduler) ReloadConfig() error {
 	}
 	l.conf.Ranges = newCfg.Ranges
 	l.conf.Batch = newCfg.Batch
+	l.conf.Policy = newCfg.Policy
 	return nil
 }
 
@@ -363,13 +380,19 @@ func (l *balanceLeaderScheduler) Schedule(cluster sche.SchedulerCluster, dryRun
 	opInfluence := l.OpController.GetOpInfluence(cluster.GetBasicCluster())
 	kind := constant.NewScheduleKind(constant.LeaderKind, leaderSchedulePolicy)
 	solver := newSolver(basePlan, kind, cluster, opInfluence)
+	filters := append(l.filters[:len(l.filters):len(l.filters)],
+		filter.NewLeaderTransferExcludedFilter(l.GetName(), l.OpController.GetLeaderTransferExcludedStores()))
+	solver.filters = filter.NewStatusCache().Wrap(filters)
 
 	stores := cluster.GetStores()
+	if l.conf.getPolicy() == balanceLeaderPolicyLoad {
+		solver.leaderLoads = newLeaderLoads(stores, cluster.RegionReadStats(), cluster.RegionWriteStats())
+	}
 	scoreFunc := func(store *core.StoreInfo) float64 {
-		return store.LeaderScore(solver.kind.Policy, solver.GetOpInfluence(store.GetID()))
+		return solver.leaderScore(store, solver.kind.Policy, solver.GetOpInfluence(store.GetID()))
 	}
-	sourceCandidate := newCandidateStores(filter.SelectSourceStores(stores, l.filters, cluster.GetSchedulerConfig(), collector, l.filterCounter), false, scoreFunc)
-	targetCandidate := newCandidateStores(filter.SelectTargetStores(stores, l.filters, cluster.GetSchedulerConfig(), nil, l.filterCounter), true, scoreFunc)
+	sourceCandidate := newCandidateStores(filter.SelectSourceStores(stores, solver.filters, cluster.GetSchedulerConfig(), collector, l.filterCounter), false, scoreFunc)
+	targetCandidate := newCandidateStores(filter.SelectTargetStores(stores, solver.filters, cluster.GetSchedulerConfig(), nil, l.filterCounter), true, scoreFunc)
 	usedRegions := make(map[uint64]struct{})
 
 	result := make([]*operator.Operator, 0, batch)
@@ -472,17 +495,17 @@ func (l *balanceLeaderScheduler) transferLeaderOut(solver *solver, collector *pl
 	solver.Step++
 	defer func() { solver.Step-- }()
 	targets := solver.GetFollowerStores(solver.Region)
-	finalFilters := l.filters
+	finalFilters := solver.filters
 	conf := solver.GetSchedulerConfig()
 	if leaderFilter := filter.NewPlacementLeaderSafeguard(l.GetName(), conf, solver.GetBasicCluster(), solver.GetRuleManager(), solver.Region, solver.Source, false /*allowMoveLeader*/); leaderFilter != nil {
-		finalFilters = append(l.filters, leaderFilter)
+		finalFilters = append(solver.filters, leaderFilter)
 	}
 	targets = filter.SelectTargetStores(targets, finalFilters, conf, collector, l.filterCounter)
-	leaderSchedulePolicy := conf.GetLeaderSchedulePolicy()
+	leaderSchedulePolicy := getRangeOverrides(solver, solver.Region).GetLeaderSchedulePolicy(conf.GetLeaderSchedulePolicy())
 	sort.Slice(targets, func(i, j int) bool {
 		iOp := solver.GetOpInfluence(targets[i].GetID())
 		jOp := solver.GetOpInfluence(targets[j].GetID())
-		return targets[i].LeaderScore(leaderSchedulePolicy, iOp) < targets[j].LeaderScore(leaderSchedulePolicy, jOp)
+		return solver.leaderScore(targets[i], leaderSchedulePolicy, iOp) < solver.leaderScore(targets[j], leaderSchedulePolicy, jOp)
 	})
 	for _, solver.Target = range targets {
 		if op := l.createOperator(solver, collector); op != nil {
@@ -521,10 +544,10 @@ func (l *balanceLeaderScheduler) transferLeaderIn(solver *solver, collector *pla
 		balanceLeaderNoLeaderRegionCounter.Inc()
 		return nil
 	}
-	finalFilters := l.filters
+	finalFilters := solver.filters
 	conf := solver.GetSchedulerConfig()
 	if leaderFilter := filter.NewPlacementLeaderSafeguard(l.GetName(), conf, solver.GetBasicCluster(), solver.GetRuleManager(), solver.Region, solver.Source, false /*allowMoveLeader*/); leaderFilter != nil {
-		finalFilters = append(l.filters, leaderFilter)
+		finalFilters = append(solver.filters, leaderFilter)
 	}
 	target := filter.NewCandidates([]*core.StoreInfo{solver.Target}).
 		FilterTarget(conf, nil, l.filterCounter, finalFilters...).
@@ -544,6 +567,10 @@ func (l *balanceLeaderScheduler) transferLeaderIn(solver *solver, collector *pla
 func (l *balanceLeaderScheduler) createOperator(solver *solver, collector *plan.Collector) *operator.Operator {
 	solver.Step++
 	defer func() { solver.Step-- }()
+	// the leader schedule policy may be overridden for the key range of the region.
+	policy := solver.kind.Policy
+	solver.kind.Policy = getRangeOverrides(solver, solver.Region).GetLeaderSchedulePolicy(policy)
+	defer func() { solver.kind.Policy = policy }()
 	solver.sourceScore, solver.targetScore = solver.sourceStoreScore(l.GetName()), solver.targetStoreScore(l.GetName())
 	if !solver.shouldBalance(l.GetName()) {
 		balanceLeaderSkipCounter.Inc()
@@ -565,6 +592,7 @@ func (l *balanceLeaderScheduler) createOperator(solver *solver, collector *plan.
 	op.Counters = append(op.Counters,
 		balanceLeaderNewOpCounter,
 	)
+	appendKeyspaceBalanceCounter(op, l.GetName(), solver.Region)
 	op.FinishedCounters = append(op.FinishedCounters,
 		balanceDirectionCounter.WithLabelValues(l.GetName(), solver.SourceMetricLabel(), solver.TargetMetricLabel()),
 	)
@@ -0,0 +1,89 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/core/constant"
+	"github.com/tikv/pd/pkg/statistics"
+	"github.com/tikv/pd/pkg/statistics/utils"
+)
+
+// balanceLeaderPolicyLoad makes balance-leader weight the leader score of each
+// store by the request load of its leaders rather than only the leader count.
+const balanceLeaderPolicyLoad = "load"
+
+// leaderLoadDims are the dimensions of the leader load.
+var leaderLoadDims = []int{utils.ByteDim, utils.QueryDim}
+
+// leaderLoads records the load of the leaders on each store relative to the
+// average of the stores, it's calculated from the hot peer statistics once
+// per scheduling round.
+type leaderLoads struct {
+	ratios map[uint64]float64
+}
+
+func newLeaderLoads(stores []*core.StoreInfo, statsList ...map[uint64][]*statistics.HotPeerStat) *leaderLoads {
+	loads := make(map[uint64][]float64, len(stores))
+	totals := make([]float64, len(leaderLoadDims))
+	for _, store := range stores {
+		storeLoads := make([]float64, len(leaderLoadDims))
+		for _, stats := range statsList {
+			for _, stat := range stats[store.GetID()] {
+				if !stat.IsLeader() {
+					continue
+				}
+				for i, dim := range leaderLoadDims {
+					storeLoads[i] += stat.GetLoad(dim)
+				}
+			}
+		}
+		for i := range leaderLoadDims {
+			totals[i] += storeLoads[i]
+		}
+		loads[store.GetID()] = storeLoads
+	}
+	ratios := make(map[uint64]float64, len(stores))
+	for storeID, storeLoads := range loads {
+		var sum float64
+		dims := 0
+		for i := range leaderLoadDims {
+			if totals[i] <= 0 {
+				continue
+			}
+			sum += storeLoads[i] / (totals[i] / float64(len(stores)))
+			dims++
+		}
+		if dims > 0 {
+			ratios[storeID] = sum / float64(dims)
+		}
+	}
+	return &leaderLoads{ratios: ratios}
+}
+
+// score returns the leader score amplified by the leader load of the store, so a
+// store serving a few heavy leaders is regarded as holding more leaders. It's
+// the same as the leader score if there is no hot leader in the cluster.
+func (l *leaderLoads) score(store *core.StoreInfo, policy constant.SchedulePolicy, delta int64) float64 {
+	return store.LeaderScore(policy, delta) * (1 + l.ratios[store.GetID()])
+}
+
+// leaderScore returns the leader score of the store according to the policy of the solver.
+func (p *solver) leaderScore(store *core.StoreInfo, policy constant.SchedulePolicy, delta int64) float64 {
+	if p.leaderLoads != nil {
+		return p.leaderLoads.score(store, policy, delta)
+	}
+	return store.LeaderScore(policy, delta)
+}
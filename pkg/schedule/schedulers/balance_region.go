@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/constant"
+	"github.com/tikv/pd/pkg/schedule/config"
 	sche "github.com/tikv/pd/pkg/schedule/core"
 	"github.com/tikv/pd/pkg/schedule/filter"
 	"github.com/tikv/pd/pkg/schedule/operator"
@@ -119,8 +120,9 @@ func (s *balanceRegionScheduler) Schedule(cluster sche.SchedulerCluster, dryRun
 	stores := cluster.GetStores()
 	conf := cluster.GetSchedulerConfig()
 	snapshotFilter := filter.NewSnapshotSendFilter(stores, constant.Medium)
-	faultTargets := filter.SelectUnavailableTargetStores(stores, s.filters, conf, collector, s.filterCounter)
-	sourceStores := filter.SelectSourceStores(stores, s.filters, conf, collector, s.filterCounter)
+	filters := filter.NewStatusCache().Wrap(s.filters)
+	faultTargets := filter.SelectUnavailableTargetStores(stores, filters, conf, collector, s.filterCounter)
+	sourceStores := filter.SelectSourceStores(stores, filters, conf, collector, s.filterCounter)
 	opInfluence := s.OpController.GetOpInfluence(cluster.GetBasicCluster())
 	s.OpController.GetFastOpInfluence(cluster.GetBasicCluster(), opInfluence)
 	kind := constant.NewScheduleKind(constant.RegionKind, constant.BySize)
@@ -208,6 +210,7 @@ func (s *balanceRegionScheduler) Schedule(cluster sche.SchedulerCluster, dryRun
 			if op := s.transferPeer(solver, collector, sourceStores[sourceIndex+1:], faultTargets); op != nil {
 				s.retryQuota.ResetLimit(solver.Source)
 				op.Counters = append(op.Counters, balanceRegionNewOpCounter)
+				appendKeyspaceBalanceCounter(op, s.GetName(), solver.Region)
 				return []*operator.Operator{op}, collector.GetPlans()
 			}
 			solver.Step--
@@ -232,7 +235,13 @@ func (s *balanceRegionScheduler) transferPeer(solver *solver, collector *plan.Co
 		filter.NewPlacementSafeguard(s.GetName(), conf, solver.GetBasicCluster(), solver.GetRuleManager(),
 			solver.Region, solver.Source, solver.fit),
 	}
-	candidates := filter.NewCandidates(dstStores).FilterTarget(conf, collector, s.filterCounter, filters...)
+	// The target stores are only filtered by the global config before, so check
+	// their states again if the config is overridden for the key range of the region.
+	overrides := getRangeOverrides(solver, solver.Region)
+	if overrides != nil && overrides.MaxPendingPeerCount != nil {
+		filters = append(filters, &filter.StoreStateFilter{ActionScope: s.GetName(), MoveRegion: true, OperatorLevel: constant.Medium})
+	}
+	candidates := filter.NewCandidates(dstStores).FilterTarget(config.WithRangeOverrides(conf, overrides), collector, s.filterCounter, filters...)
 	if len(candidates.Stores) != 0 {
 		solver.Step++
 	}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"sort"
 	"testing"
 
@@ -29,8 +30,10 @@ import (
 	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/schedule/config"
+	"github.com/tikv/pd/pkg/schedule/labeler"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/schedule/plan"
+	"github.com/tikv/pd/pkg/statistics/utils"
 	"github.com/tikv/pd/pkg/storage"
 	"github.com/tikv/pd/pkg/utils/operatorutil"
 	"github.com/tikv/pd/pkg/versioninfo"
@@ -320,6 +323,34 @@ func (suite *balanceLeaderSchedulerTestSuite) TestBalanceLeaderSchedulePolicy()
 	re.NotEmpty(suite.schedule())
 }
 
+func (suite *balanceLeaderSchedulerTestSuite) TestBalanceLeaderByLoad() {
+	re := suite.Require()
+	// Stores:          1       2       3       4
+	// Leader Count:    10      10      10      10
+	// Hot Leader:      10
+	suite.tc.AddLeaderStore(1, 10)
+	suite.tc.AddLeaderStore(2, 10)
+	suite.tc.AddLeaderStore(3, 10)
+	suite.tc.AddLeaderStore(4, 10)
+	for id := uint64(1); id <= 5; id++ {
+		suite.tc.AddLeaderRegion(id, 1, 2, 3, 4)
+	}
+	suite.tc.AddRegionWithReadInfo(10, 1, 512*units.KiB*utils.RegionHeartBeatReportInterval, 0, 0, utils.RegionHeartBeatReportInterval, []uint64{2, 3, 4})
+	// the leaders are balanced by count.
+	re.Empty(suite.schedule())
+
+	lb := suite.lb.(*balanceLeaderScheduler)
+	status, _ := lb.conf.Update([]byte(`{"policy":"invalid"}`))
+	re.Equal(http.StatusBadRequest, status)
+	status, _ = lb.conf.Update([]byte(`{"policy":"load"}`))
+	re.Equal(http.StatusOK, status)
+	ops := suite.schedule()
+	re.NotEmpty(ops)
+	// the hot leader is left to the hot region scheduler.
+	re.NotEqual(uint64(10), ops[0].RegionID())
+	operatorutil.CheckTransferLeaderFrom(re, ops[0], operator.OpKind(0), 1)
+}
+
 func (suite *balanceLeaderSchedulerTestSuite) TestBalanceLeaderTolerantRatio() {
 	re := suite.Require()
 	suite.tc.SetTolerantSizeRatio(2.5)
@@ -803,6 +834,37 @@ func checkBalanceRegionSchedule1(re *require.Assertions, enablePlacementRules bo
 	re.True(plans[0].GetStatus().IsOK())
 }
 
+func TestBalanceRegionRangeOverrides(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	tc.SetEnablePlacementRules(false)
+	tc.SetMaxReplicasWithLabel(false, 1)
+	sb, err := CreateScheduler(BalanceRegionType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(BalanceRegionType, []string{"", ""}))
+	re.NoError(err)
+	tc.AddRegionStore(1, 6)
+	tc.AddRegionStore(2, 8)
+	tc.AddRegionStore(3, 8)
+	tc.AddRegionStore(4, 16)
+	tc.AddLeaderRegion(1, 4)
+	tc.UpdatePendingPeerCount(1, 10)
+	ops, _ := sb.Schedule(tc, false)
+	re.NotEmpty(ops)
+	operatorutil.CheckTransferPeerWithLeaderTransfer(re, ops[0], operator.OpKind(0), 4, 1)
+
+	// store 1 has too many pending peers for the overridden max-pending-peer-count.
+	re.NoError(tc.GetRegionLabeler().SetLabelRule(&labeler.LabelRule{
+		ID:       "test",
+		Labels:   []labeler.RegionLabel{{Key: labeler.MaxPendingPeerCountLabel, Value: "5"}},
+		RuleType: labeler.KeyRange,
+		Data:     []any{map[string]any{"start_key": "", "end_key": ""}},
+	}))
+	ops, _ = sb.Schedule(tc, false)
+	re.NotEmpty(ops)
+	re.NotEqual(uint64(1), ops[0].Step(0).(operator.AddLearner).ToStore)
+}
+
 func TestBalanceRegionReplicas3(t *testing.T) {
 	re := require.New(t)
 	checkReplica3(re, false /* disable placement rules */)
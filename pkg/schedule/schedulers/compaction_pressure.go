@@ -0,0 +1,126 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/core/constant"
+	"github.com/tikv/pd/pkg/errs"
+	sche "github.com/tikv/pd/pkg/schedule/core"
+	"github.com/tikv/pd/pkg/schedule/filter"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/schedule/plan"
+	"go.uber.org/zap"
+)
+
+const (
+	// CompactionPressureName is compaction pressure scheduler name.
+	CompactionPressureName = "compaction-pressure-scheduler"
+	// CompactionPressureType is compaction pressure scheduler type.
+	CompactionPressureType = "compaction-pressure"
+	// compactionPressureBatchSize is the max number of the leaders moved off
+	// each saturated store by one scheduling.
+	compactionPressureBatchSize = 4
+)
+
+type compactionPressureScheduler struct {
+	*BaseScheduler
+	filters []filter.Filter
+}
+
+// newCompactionPressureScheduler creates a scheduler that sheds the leaders off
+// the stores whose compaction pressure exceeds the limits, until the pressure
+// is relieved. The saturated stores are also excluded as the targets of the
+// regions and the leaders by the store state filter.
+func newCompactionPressureScheduler(opController *operator.Controller) Scheduler {
+	return &compactionPressureScheduler{
+		BaseScheduler: NewBaseScheduler(opController),
+		filters: []filter.Filter{
+			&filter.StoreStateFilter{ActionScope: CompactionPressureName, TransferLeader: true, OperatorLevel: constant.Medium},
+			filter.NewSpecialUseFilter(CompactionPressureName),
+		},
+	}
+}
+
+func (*compactionPressureScheduler) GetName() string {
+	return CompactionPressureName
+}
+
+func (*compactionPressureScheduler) GetType() string {
+	return CompactionPressureType
+}
+
+func (s *compactionPressureScheduler) IsScheduleAllowed(cluster sche.SchedulerCluster) bool {
+	allowed := s.OpController.OperatorCount(operator.OpLeader) < cluster.GetSchedulerConfig().GetLeaderScheduleLimit()
+	if !allowed {
+		operator.OperatorLimitCounter.WithLabelValues(s.GetType(), operator.OpLeader.String()).Inc()
+	}
+	return allowed
+}
+
+func (s *compactionPressureScheduler) Schedule(cluster sche.SchedulerCluster, _ bool) ([]*operator.Operator, []plan.Plan) {
+	compactionPressureCounter.Inc()
+	conf := cluster.GetSchedulerConfig()
+	maxPendingBytes, maxLevel0Files := conf.GetMaxStorePendingCompactionBytes(), conf.GetMaxStoreLevel0FileCount()
+	if maxPendingBytes == 0 && maxLevel0Files == 0 {
+		return nil, nil
+	}
+	var ops []*operator.Operator
+	for _, store := range cluster.GetStores() {
+		if !store.IsCompactionSaturated(maxPendingBytes, maxLevel0Files) || store.GetLeaderCount() == 0 {
+			continue
+		}
+		compactionPressureSaturatedStoreCounter.Inc()
+		ops = append(ops, s.shedLeaders(cluster, store)...)
+	}
+	return ops, nil
+}
+
+func (s *compactionPressureScheduler) shedLeaders(cluster sche.SchedulerCluster, store *core.StoreInfo) []*operator.Operator {
+	var ops []*operator.Operator
+	pendingFilter := filter.NewRegionPendingFilter()
+	downFilter := filter.NewRegionDownFilter()
+	// the random regions may be duplicated.
+	picked := make(map[uint64]struct{})
+	for _, region := range cluster.RandLeaderRegions(store.GetID(), nil) {
+		if len(ops) >= compactionPressureBatchSize {
+			break
+		}
+		if _, ok := picked[region.GetID()]; ok {
+			continue
+		}
+		picked[region.GetID()] = struct{}{}
+		if filter.SelectOneRegion([]*core.RegionInfo{region}, nil, pendingFilter, downFilter) == nil {
+			continue
+		}
+		target := filter.NewCandidates(cluster.GetFollowerStores(region)).
+			FilterTarget(cluster.GetSchedulerConfig(), nil, nil, s.filters...).
+			RandomPick()
+		if target == nil {
+			compactionPressureNoTargetStoreCounter.Inc()
+			continue
+		}
+		op, err := operator.CreateTransferLeaderOperator(CompactionPressureType, cluster, region, target.GetID(), []uint64{}, operator.OpLeader)
+		if err != nil {
+			log.Debug("fail to create compaction pressure operator", zap.Uint64("region-id", region.GetID()), errs.ZapError(err))
+			continue
+		}
+		op.SetPriorityLevel(constant.Medium)
+		op.Counters = append(op.Counters, compactionPressureNewOperatorCounter)
+		ops = append(ops, op)
+	}
+	return ops
+}
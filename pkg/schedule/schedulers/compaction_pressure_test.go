@@ -0,0 +1,61 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/storage"
+	"github.com/tikv/pd/pkg/utils/operatorutil"
+)
+
+func TestCompactionPressure(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+
+	tc.AddLeaderStore(1, 1)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	sl, err := CreateScheduler(CompactionPressureType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(CompactionPressureType, nil))
+	re.NoError(err)
+	report := func(storeID, level0Files uint64, reportTime time.Time) {
+		re.NoError(tc.UpdateCompactionStats(storeID, &core.CompactionStats{Level0FileCount: level0Files, ReportTime: reportTime}))
+	}
+
+	// no limit by default.
+	report(1, 100, time.Now())
+	ops, _ := sl.Schedule(tc, false)
+	re.Empty(ops)
+
+	// the leaders are moved off the saturated store, but not to another saturated store.
+	tc.SetMaxStoreLevel0FileCount(20)
+	report(2, 30, time.Now())
+	ops, _ = sl.Schedule(tc, false)
+	re.Len(ops, 1)
+	operatorutil.CheckTransferLeader(re, ops[0], operator.OpLeader, 1, 3)
+
+	// the stale stats are ignored.
+	report(1, 100, time.Now().Add(-10*time.Minute))
+	ops, _ = sl.Schedule(tc, false)
+	re.Empty(ops)
+	re.False(tc.GetStore(1).IsCompactionSaturated(0, 20))
+	re.True(tc.GetStore(2).IsCompactionSaturated(0, 20))
+}
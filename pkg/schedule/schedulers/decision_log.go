@@ -0,0 +1,205 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	sc "github.com/tikv/pd/pkg/schedule/config"
+	sche "github.com/tikv/pd/pkg/schedule/core"
+	"github.com/tikv/pd/pkg/schedule/operator"
+	"github.com/tikv/pd/pkg/schedule/plan"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// maxDecisionRejections is the max number of the rejected alternatives
+	// recorded for one decision, to keep the lines of the log bounded.
+	maxDecisionRejections = 64
+	// rejectedByLabel is the reason of the operators dropped because the
+	// scheduling of the region is disabled by the region label.
+	rejectedByLabel = "schedule-disabled-by-label"
+)
+
+// DecisionRecord is a scheduling decision written to the decision log.
+type DecisionRecord struct {
+	Time      time.Time           `json:"time"`
+	Scheduler string              `json:"scheduler"`
+	Inputs    DecisionInputs      `json:"inputs"`
+	Operators []DecisionOperator  `json:"operators,omitempty"`
+	Rejected  []DecisionRejection `json:"rejected,omitempty"`
+}
+
+// DecisionInputs is the summary of the cluster seen by the scheduler when the
+// decision is made.
+type DecisionInputs struct {
+	StoreCount  int `json:"store-count"`
+	RegionCount int `json:"region-count"`
+}
+
+// DecisionOperator is an operator chosen by the scheduler.
+type DecisionOperator struct {
+	RegionID uint64   `json:"region-id"`
+	Desc     string   `json:"desc"`
+	Kind     string   `json:"kind"`
+	Steps    []string `json:"steps"`
+}
+
+// DecisionRejection is an alternative rejected by the scheduler. The resources
+// are the ones picked at each step before it's rejected, e.g. the source store,
+// the region and the target store for the balance schedulers.
+type DecisionRejection struct {
+	Step      int      `json:"step"`
+	Resources []uint64 `json:"resources,omitempty"`
+	RegionID  uint64   `json:"region-id,omitempty"`
+	Reason    string   `json:"reason"`
+}
+
+// DecisionLog writes the scheduling decisions of all the schedulers as JSONL,
+// the file is rotated by size. It's enabled by the decision-log-file config,
+// the file is fixed once it's created so that it can't be redirected online.
+type DecisionLog struct {
+	syncutil.Mutex
+	config sc.SchedulerConfigProvider
+	file   string
+	writer *lumberjack.Logger
+}
+
+// NewDecisionLog creates a new DecisionLog.
+func NewDecisionLog(config sc.SchedulerConfigProvider) *DecisionLog {
+	return &DecisionLog{config: config, file: config.GetDecisionLogFile()}
+}
+
+// IsEnabled returns whether the decisions should be recorded.
+func (l *DecisionLog) IsEnabled() bool {
+	return l != nil && l.file != ""
+}
+
+// Record writes the decision as a line of the log.
+func (l *DecisionLog) Record(record *DecisionRecord) {
+	if !l.IsEnabled() {
+		return
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Error("failed to marshal the scheduling decision", errs.ZapError(err))
+		return
+	}
+	data = append(data, '\n')
+	l.Lock()
+	defer l.Unlock()
+	writer := l.getWriterLocked()
+	if _, err := writer.Write(data); err != nil {
+		log.Error("failed to write the scheduling decision", zap.String("file", writer.Filename), errs.ZapError(err))
+	}
+}
+
+// getWriterLocked returns the writer of the current config, the file is
+// reopened if the rotation config is changed.
+func (l *DecisionLog) getWriterLocked() *lumberjack.Logger {
+	maxSize, maxBackups := l.config.GetDecisionLogMaxSize(), l.config.GetDecisionLogMaxBackups()
+	if l.writer != nil && l.writer.MaxSize == maxSize && l.writer.MaxBackups == maxBackups {
+		return l.writer
+	}
+	l.closeLocked()
+	l.writer = &lumberjack.Logger{
+		Filename:   l.file,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+		LocalTime:  true,
+	}
+	return l.writer
+}
+
+// Close closes the log file.
+func (l *DecisionLog) Close() {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.closeLocked()
+}
+
+func (l *DecisionLog) closeLocked() {
+	if l.writer == nil {
+		return
+	}
+	if err := l.writer.Close(); err != nil {
+		log.Warn("failed to close the decision log", zap.String("file", l.writer.Filename), errs.ZapError(err))
+	}
+	l.writer = nil
+}
+
+// newDecisionRecord builds the record of the decision made by the scheduler.
+// Only the plans which are not schedulable are recorded as the rejected ones.
+func newDecisionRecord(cluster sche.SchedulerCluster, name string, ops []*operator.Operator, plans []plan.Plan, denied []*operator.Operator) *DecisionRecord {
+	record := &DecisionRecord{
+		Time:      time.Now(),
+		Scheduler: name,
+		Inputs: DecisionInputs{
+			StoreCount:  cluster.GetBasicCluster().GetStoreCount(),
+			RegionCount: cluster.GetBasicCluster().GetTotalRegionCount(),
+		},
+	}
+	for _, op := range ops {
+		record.Operators = append(record.Operators, newDecisionOperator(op))
+	}
+	for _, op := range denied {
+		if len(record.Rejected) >= maxDecisionRejections {
+			break
+		}
+		record.Rejected = append(record.Rejected, DecisionRejection{
+			RegionID: op.RegionID(),
+			Reason:   rejectedByLabel,
+		})
+	}
+	for _, p := range plans {
+		if len(record.Rejected) >= maxDecisionRejections {
+			break
+		}
+		status := p.GetStatus()
+		if status == nil || status.IsOK() {
+			continue
+		}
+		rejection := DecisionRejection{
+			Step:   p.GetStep(),
+			Reason: status.String(),
+		}
+		for step := 0; step <= p.GetStep(); step++ {
+			rejection.Resources = append(rejection.Resources, p.GetResource(step))
+		}
+		record.Rejected = append(record.Rejected, rejection)
+	}
+	return record
+}
+
+func newDecisionOperator(op *operator.Operator) DecisionOperator {
+	steps := make([]string, 0, op.Len())
+	for i := 0; i < op.Len(); i++ {
+		steps = append(steps, op.Step(i).String())
+	}
+	return DecisionOperator{
+		RegionID: op.RegionID(),
+		Desc:     op.Desc(),
+		Kind:     op.Kind().String(),
+		Steps:    steps,
+	}
+}
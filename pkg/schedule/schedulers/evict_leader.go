@@ -25,6 +25,7 @@ import (
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/errs"
+	sc "github.com/tikv/pd/pkg/schedule/config"
 	sche "github.com/tikv/pd/pkg/schedule/core"
 	"github.com/tikv/pd/pkg/schedule/filter"
 	"github.com/tikv/pd/pkg/schedule/operator"
@@ -54,6 +55,7 @@ type evictLeaderSchedulerConfig struct {
 	// Batch is used to generate multiple operators by one scheduling
 	Batch             int `json:"batch"`
 	cluster           *core.BasicCluster
+	sharedConfig      sc.SharedConfigProvider
 	removeSchedulerCb func(string) error
 }
 
@@ -195,6 +197,11 @@ func (conf *evictLeaderSchedulerConfig) pauseLeaderTransferIfStoreNotExist(id ui
 	conf.RLock()
 	defer conf.RUnlock()
 	if _, exist := conf.StoreIDWithRanges[id]; !exist {
+		// Refuse the admin instead of letting the eviction guard hold the new
+		// store back silently.
+		if err := CheckEvictingStoreRatio(conf.cluster.GetStores(), conf.sharedConfig.GetMaxEvictingStoreRatio(), id); err != nil {
+			return exist, err
+		}
 		if err := conf.cluster.PauseLeaderTransfer(id); err != nil {
 			return exist, err
 		}
@@ -251,6 +258,7 @@ type evictLeaderScheduler struct {
 	*BaseScheduler
 	conf    *evictLeaderSchedulerConfig
 	handler http.Handler
+	guard   evictionGuard
 }
 
 // newEvictLeaderScheduler creates an admin scheduler that transfers all leaders
@@ -308,7 +316,18 @@ func (s *evictLeaderScheduler) IsScheduleAllowed(cluster sche.SchedulerCluster)
 
 func (s *evictLeaderScheduler) Schedule(cluster sche.SchedulerCluster, _ bool) ([]*operator.Operator, []plan.Plan) {
 	evictLeaderCounter.Inc()
-	return scheduleEvictLeaderBatch(s.GetName(), s.GetType(), cluster, s.conf), nil
+	stores := s.guard.admit(cluster, s.GetName(), s.conf.getStores())
+	return scheduleEvictLeaderBatch(s.GetName(), s.GetType(), cluster, &admittedStoresConf{s.conf, stores}), nil
+}
+
+// admittedStoresConf limits the stores to evict to the ones admitted by the eviction guard.
+type admittedStoresConf struct {
+	evictLeaderStoresConf
+	stores []uint64
+}
+
+func (conf *admittedStoresConf) getStores() []uint64 {
+	return conf.stores
 }
 
 func uniqueAppendOperator(dst []*operator.Operator, src ...*operator.Operator) []*operator.Operator {
@@ -428,6 +447,10 @@ func (handler *evictLeaderHandler) UpdateConfig(w http.ResponseWriter, r *http.R
 		id = (uint64)(idFloat)
 		exist, err = handler.config.pauseLeaderTransferIfStoreNotExist(id)
 		if err != nil {
+			if errors.ErrorEqual(err, errs.ErrSchedulerEvictionRefused.FastGenByArgs()) {
+				handler.rd.JSON(w, http.StatusBadRequest, err.Error())
+				return
+			}
 			handler.rd.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
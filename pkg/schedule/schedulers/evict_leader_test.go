@@ -16,6 +16,10 @@ package schedulers
 
 import (
 	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -129,3 +133,64 @@ func TestBatchEvict(t *testing.T) {
 	ops, _ = sl.Schedule(tc, false)
 	re.Len(ops, 5)
 }
+
+func TestEvictLeaderWithGuard(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddLeaderStore(i, 10)
+	}
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddLeaderRegion(i, i, 4)
+	}
+	sl, err := CreateScheduler(EvictLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	re.NoError(sl.PrepareConfig(tc))
+	es := sl.(*evictLeaderScheduler)
+	ranges, err := getKeyRanges(nil)
+	re.NoError(err)
+	es.conf.resetStore(2, ranges)
+	es.conf.resetStore(3, ranges)
+
+	// only 2 of 4 stores can be evicted.
+	tc.SetMaxEvictingStoreRatio(0.3)
+	ops, _ := sl.Schedule(tc, false)
+	admitted := es.guard.admit(tc, es.GetName(), es.conf.getStores())
+	re.Len(admitted, 2)
+	re.NotEmpty(ops)
+	for _, op := range ops {
+		re.Contains(admitted, op.Step(0).(operator.TransferLeader).FromStore)
+	}
+
+	// the refused store is admitted once the ratio allows.
+	tc.SetMaxEvictingStoreRatio(0.5)
+	re.Len(es.guard.admit(tc, es.GetName(), es.conf.getStores()), 3)
+}
+
+func TestEvictLeaderRefusedByAdmin(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddLeaderStore(i, 10)
+	}
+	sl, err := CreateScheduler(EvictLeaderType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(EvictLeaderType, []string{"1"}), func(string) error { return nil })
+	re.NoError(err)
+	re.NoError(sl.PrepareConfig(tc))
+	tc.SetMaxEvictingStoreRatio(0.3)
+
+	addStore := func(id uint64) int {
+		req := httptest.NewRequest(http.MethodPost, "/config", strings.NewReader(fmt.Sprintf(`{"store_id":%d}`, id)))
+		w := httptest.NewRecorder()
+		sl.ServeHTTP(w, req)
+		return w.Code
+	}
+	re.Equal(http.StatusOK, addStore(2))
+	// the third store exceeds the ratio, so the admin is refused.
+	re.Equal(http.StatusBadRequest, addStore(3))
+	re.Len(sl.(*evictLeaderScheduler).conf.getStores(), 2)
+	re.True(tc.GetStore(3).AllowLeaderTransfer())
+	// the store already evicting is still accepted.
+	re.Equal(http.StatusOK, addStore(2))
+}
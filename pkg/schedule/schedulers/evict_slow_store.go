@@ -15,6 +15,7 @@
 package schedulers
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
 	sche "github.com/tikv/pd/pkg/schedule/core"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/schedule/plan"
@@ -187,6 +189,7 @@ type evictSlowStoreScheduler struct {
 	*BaseScheduler
 	conf    *evictSlowStoreSchedulerConfig
 	handler http.Handler
+	guard   evictionGuard
 }
 
 func (s *evictSlowStoreScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -242,20 +245,28 @@ func (s *evictSlowStoreScheduler) PrepareConfig(cluster sche.SchedulerCluster) e
 }
 
 func (s *evictSlowStoreScheduler) CleanConfig(cluster sche.SchedulerCluster) {
-	s.cleanupEvictLeader(cluster)
+	s.cleanupEvictLeader(cluster, "the scheduler is removed")
 }
 
 func (s *evictSlowStoreScheduler) prepareEvictLeader(cluster sche.SchedulerCluster, storeID uint64) error {
+	if err := s.guard.check(cluster, s.GetName(), storeID); err != nil {
+		return err
+	}
 	err := s.conf.setStoreAndPersist(storeID)
 	if err != nil {
 		log.Info("evict-slow-store-scheduler persist config failed", zap.Uint64("store-id", storeID))
 		return err
 	}
 
-	return cluster.SlowStoreEvicted(storeID)
+	if err := cluster.SlowStoreEvicted(storeID); err != nil {
+		return err
+	}
+	recordSlowStoreEvent(cluster, storeID, endpoint.SlowStoreEventEvict,
+		fmt.Sprintf("the slow score reaches the evict threshold %d", slowStoreEvictThreshold))
+	return nil
 }
 
-func (s *evictSlowStoreScheduler) cleanupEvictLeader(cluster sche.SchedulerCluster) {
+func (s *evictSlowStoreScheduler) cleanupEvictLeader(cluster sche.SchedulerCluster, reason string) {
 	evictSlowStore, err := s.conf.clearAndPersist()
 	if err != nil {
 		log.Info("evict-slow-store-scheduler persist config failed", zap.Uint64("store-id", evictSlowStore))
@@ -264,6 +275,137 @@ func (s *evictSlowStoreScheduler) cleanupEvictLeader(cluster sche.SchedulerClust
 		return
 	}
 	cluster.SlowStoreRecovered(evictSlowStore)
+	recordSlowStoreEvent(cluster, evictSlowStore, endpoint.SlowStoreEventRecover, reason)
+}
+
+// recordSlowStoreEvent records the event to the slow store timeline, which is
+// used to reconstruct when and why a store degraded.
+func recordSlowStoreEvent(cluster sche.SchedulerCluster, storeID uint64, typ, reason string) {
+	event := &endpoint.SlowStoreEvent{
+		StoreID: storeID,
+		Time:    time.Now(),
+		Type:    typ,
+		Reason:  reason,
+	}
+	if store := cluster.GetStore(storeID); store != nil {
+		event.SlowScore = store.GetSlowScore()
+	}
+	if err := cluster.GetStorage().SaveSlowStoreEvent(event); err != nil {
+		log.Warn("failed to record the slow store event",
+			zap.Uint64("store-id", storeID), zap.String("type", typ), errs.ZapError(err))
+	}
+}
+
+// evictionGuard refuses to start a new eviction when more than the
+// max-evicting-store-ratio of the stores are already evicting or offline, since
+// evicting one more store may collapse the capacity of the cluster. A store is
+// evicting if it's evicted as the slow store, or its leader transfer is paused
+// by the evict-leader-scheduler. The refusal of a store is recorded as a slow
+// store event once until an eviction is allowed again.
+type evictionGuard struct {
+	syncutil.Mutex
+	refusedStores map[uint64]struct{}
+	// admittedStores are the stores of the evict-leader-scheduler whose
+	// evictions are allowed.
+	admittedStores map[uint64]struct{}
+}
+
+func (g *evictionGuard) check(cluster sche.SchedulerCluster, name string, storeID uint64) error {
+	g.Lock()
+	defer g.Unlock()
+	return g.checkLocked(cluster, name, storeID, nil)
+}
+
+// admit returns the stores allowed to be evicted by the evict-leader-scheduler.
+// The new stores are admitted one by one, and the ones not admitted yet are not
+// counted as evicting though their leader transfer has been paused.
+func (g *evictionGuard) admit(cluster sche.SchedulerCluster, name string, stores []uint64) []uint64 {
+	g.Lock()
+	defer g.Unlock()
+	current := make(map[uint64]struct{}, len(stores))
+	pending := make(map[uint64]struct{})
+	for _, id := range stores {
+		current[id] = struct{}{}
+		if _, ok := g.admittedStores[id]; !ok {
+			pending[id] = struct{}{}
+		}
+	}
+	for id := range g.admittedStores {
+		if _, ok := current[id]; !ok {
+			delete(g.admittedStores, id)
+		}
+	}
+	admitted := make([]uint64, 0, len(stores))
+	for _, id := range stores {
+		if _, ok := pending[id]; ok {
+			if err := g.checkLocked(cluster, name, id, pending); err != nil {
+				continue
+			}
+			delete(pending, id)
+			if g.admittedStores == nil {
+				g.admittedStores = make(map[uint64]struct{})
+			}
+			g.admittedStores[id] = struct{}{}
+		}
+		admitted = append(admitted, id)
+	}
+	return admitted
+}
+
+func (g *evictionGuard) checkLocked(cluster sche.SchedulerCluster, name string, storeID uint64, pending map[uint64]struct{}) error {
+	reason := evictionRefusedReason(cluster.GetStores(), cluster.GetSchedulerConfig().GetMaxEvictingStoreRatio(), func(id uint64) bool {
+		_, ok := pending[id]
+		return ok || id == storeID
+	})
+	if reason == "" {
+		g.refusedStores = nil
+		return nil
+	}
+	if _, ok := g.refusedStores[storeID]; !ok {
+		if g.refusedStores == nil {
+			g.refusedStores = make(map[uint64]struct{})
+		}
+		g.refusedStores[storeID] = struct{}{}
+		log.Warn("refuse to evict the store", zap.String("scheduler", name), zap.Uint64("store-id", storeID), zap.String("reason", reason))
+		recordSlowStoreEvent(cluster, storeID, endpoint.SlowStoreEventEvictRefused, reason)
+	}
+	schedulerCounter.WithLabelValues(name, "eviction-refused").Inc()
+	return errs.ErrSchedulerEvictionRefused.FastGenByArgs(storeID, reason)
+}
+
+// CheckEvictingStoreRatio returns an error if more than the
+// max-evicting-store-ratio of the stores are already evicting or offline. It's
+// used to refuse the evictions started by the admins, which are evicting the
+// leaders with the evict-leader-scheduler and moving the peers out by taking the
+// store offline.
+func CheckEvictingStoreRatio(stores []*core.StoreInfo, ratio float64, storeID uint64) error {
+	reason := evictionRefusedReason(stores, ratio, func(id uint64) bool { return id == storeID })
+	if reason == "" {
+		return nil
+	}
+	return errs.ErrSchedulerEvictionRefused.FastGenByArgs(storeID, reason)
+}
+
+// evictionRefusedReason returns why a new eviction is refused, or an empty
+// string if it's allowed. The skipped stores are not counted as evicting.
+func evictionRefusedReason(stores []*core.StoreInfo, ratio float64, skip func(uint64) bool) string {
+	var total, evicting int
+	for _, store := range stores {
+		if store.IsRemoved() {
+			continue
+		}
+		total++
+		if skip(store.GetID()) {
+			continue
+		}
+		if store.IsRemoving() || store.EvictedAsSlowStore() || store.IsEvictedAsSlowTrend() || !store.AllowLeaderTransfer() {
+			evicting++
+		}
+	}
+	if float64(evicting) <= float64(total)*ratio {
+		return ""
+	}
+	return fmt.Sprintf("%d of %d stores are evicting or offline, exceeding the max-evicting-store-ratio %v", evicting, total, ratio)
 }
 
 func (s *evictSlowStoreScheduler) schedulerEvictLeader(cluster sche.SchedulerCluster) []*operator.Operator {
@@ -286,18 +428,21 @@ func (s *evictSlowStoreScheduler) Schedule(cluster sche.SchedulerCluster, _ bool
 
 	if s.conf.evictStore() != 0 {
 		store := cluster.GetStore(s.conf.evictStore())
+		var reason string
 		if store == nil || store.IsRemoved() {
 			// Previous slow store had been removed, remove the scheduler and check
 			// slow node next time.
 			log.Info("slow store has been removed",
 				zap.Uint64("store-id", store.GetID()))
+			reason = "the store is removed"
 		} else if store.GetSlowScore() <= slowStoreRecoverThreshold && s.conf.readyForRecovery() {
 			log.Info("slow store has been recovered",
 				zap.Uint64("store-id", store.GetID()))
+			reason = fmt.Sprintf("the slow score falls to the recover threshold %d", slowStoreRecoverThreshold)
 		} else {
 			return s.schedulerEvictLeader(cluster), nil
 		}
-		s.cleanupEvictLeader(cluster)
+		s.cleanupEvictLeader(cluster, reason)
 		return nil, nil
 	}
 
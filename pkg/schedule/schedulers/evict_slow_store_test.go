@@ -19,13 +19,16 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pingcap/failpoint"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/storage"
+	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/utils/operatorutil"
 )
 
@@ -153,3 +156,33 @@ func (suite *evictSlowStoreTestSuite) TestEvictSlowStorePersistFail() {
 	ops, _ = suite.es.Schedule(suite.tc, false)
 	re.NotEmpty(ops)
 }
+
+func TestEvictionGuard(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, _ := prepareSchedulersTest()
+	defer cancel()
+	for i := uint64(1); i <= 4; i++ {
+		tc.AddLeaderStore(i, 10)
+	}
+	re.NoError(tc.SlowStoreEvicted(2))
+	tc.SetStoreOffline(3)
+
+	// 2 of 4 stores are evicting or offline, which doesn't exceed the ratio.
+	guard := &evictionGuard{}
+	tc.SetMaxEvictingStoreRatio(0.5)
+	re.NoError(guard.check(tc, EvictSlowStoreName, 1))
+
+	// the refusal is recorded once.
+	tc.SetMaxEvictingStoreRatio(0.3)
+	for i := 0; i < 2; i++ {
+		err := guard.check(tc, EvictSlowStoreName, 1)
+		re.ErrorContains(err, "2 of 4 stores are evicting or offline")
+	}
+	events, err := tc.GetStorage().LoadSlowStoreEvents(1, time.Time{}, time.Now().Add(time.Minute))
+	re.NoError(err)
+	re.Len(events, 1)
+	re.Equal(endpoint.SlowStoreEventEvictRefused, events[0].Type)
+
+	// the store itself is not counted.
+	re.NoError(guard.check(tc, EvictSlowStoreName, 2))
+}
@@ -276,6 +276,7 @@ type evictSlowTrendScheduler struct {
 	*BaseScheduler
 	conf    *evictSlowTrendSchedulerConfig
 	handler http.Handler
+	guard   evictionGuard
 }
 
 func (s *evictSlowTrendScheduler) GetNextInterval(time.Duration) time.Duration {
@@ -344,19 +345,26 @@ func (s *evictSlowTrendScheduler) PrepareConfig(cluster sche.SchedulerCluster) e
 }
 
 func (s *evictSlowTrendScheduler) CleanConfig(cluster sche.SchedulerCluster) {
-	s.cleanupEvictLeader(cluster)
+	s.cleanupEvictLeader(cluster, "the scheduler is removed")
 }
 
 func (s *evictSlowTrendScheduler) prepareEvictLeader(cluster sche.SchedulerCluster, storeID uint64) error {
+	if err := s.guard.check(cluster, s.GetName(), storeID); err != nil {
+		return err
+	}
 	err := s.conf.setStoreAndPersist(storeID)
 	if err != nil {
 		log.Info("evict-slow-trend-scheduler persist config failed", zap.Uint64("store-id", storeID))
 		return err
 	}
-	return cluster.SlowTrendEvicted(storeID)
+	if err := cluster.SlowTrendEvicted(storeID); err != nil {
+		return err
+	}
+	recordSlowStoreEvent(cluster, storeID, endpoint.SlowStoreEventEvictByTrend, "the store is slower than others by trend")
+	return nil
 }
 
-func (s *evictSlowTrendScheduler) cleanupEvictLeader(cluster sche.SchedulerCluster) {
+func (s *evictSlowTrendScheduler) cleanupEvictLeader(cluster sche.SchedulerCluster, reason string) {
 	evictedStoreID, err := s.conf.clearAndPersist(cluster)
 	if err != nil {
 		log.Info("evict-slow-trend-scheduler persist config failed", zap.Uint64("store-id", evictedStoreID))
@@ -365,6 +373,7 @@ func (s *evictSlowTrendScheduler) cleanupEvictLeader(cluster sche.SchedulerClust
 		// Assertion: evictStoreID == s.conf.LastEvictCandidate.storeID
 		s.conf.markCandidateRecovered()
 		cluster.SlowTrendRecovered(evictedStoreID)
+		recordSlowStoreEvent(cluster, evictedStoreID, endpoint.SlowStoreEventRecoverByTrend, reason)
 	}
 }
 
@@ -395,19 +404,22 @@ func (s *evictSlowTrendScheduler) Schedule(cluster sche.SchedulerCluster, _ bool
 
 	if s.conf.evictedStore() != 0 {
 		store := cluster.GetStore(s.conf.evictedStore())
+		var reason string
 		if store == nil || store.IsRemoved() {
 			// Previous slow store had been removed, remove the scheduler and check
 			// slow node next time.
 			log.Info("store evicted by slow trend has been removed", zap.Uint64("store-id", store.GetID()))
 			storeSlowTrendActionStatusGauge.WithLabelValues("evict", "stop_removed").Inc()
+			reason = "the store is removed"
 		} else if checkStoreCanRecover(cluster, store) && s.conf.readyForRecovery() {
 			log.Info("store evicted by slow trend has been recovered", zap.Uint64("store-id", store.GetID()))
 			storeSlowTrendActionStatusGauge.WithLabelValues("evict", "stop_recovered").Inc()
+			reason = "the store is recovered by trend"
 		} else {
 			storeSlowTrendActionStatusGauge.WithLabelValues("evict", "continue").Inc()
 			return s.scheduleEvictLeader(cluster), nil
 		}
-		s.cleanupEvictLeader(cluster)
+		s.cleanupEvictLeader(cluster, reason)
 		return ops, nil
 	}
 
@@ -984,6 +984,7 @@ func (bs *balanceSolver) filterDstStores() map[uint64]*statistics.StoreLoadDetai
 		filters = []filter.Filter{
 			&filter.StoreStateFilter{ActionScope: bs.sche.GetName(), TransferLeader: true, OperatorLevel: constant.High},
 			filter.NewSpecialUseFilter(bs.sche.GetName(), filter.SpecialUseHotRegion),
+			filter.NewLeaderTransferExcludedFilter(bs.sche.GetName(), bs.sche.OpController.GetLeaderTransferExcludedStores()),
 		}
 		if bs.rwTy == utils.Read {
 			peers := bs.cur.region.GetPeers()
@@ -1003,7 +1004,7 @@ func (bs *balanceSolver) filterDstStores() map[uint64]*statistics.StoreLoadDetai
 					continue
 				}
 				// move leader
-				if filter.Target(bs.GetSchedulerConfig(), detail.StoreInfo, moveLeaderFilters) {
+				if filter.Target(getRegionConfig(bs, bs.cur.region), detail.StoreInfo, moveLeaderFilters) {
 					candidates = append(candidates, detail)
 				}
 			}
@@ -1028,6 +1029,7 @@ func (bs *balanceSolver) pickDstStores(filters []filter.Filter, candidates []*st
 	ret := make(map[uint64]*statistics.StoreLoadDetail, len(candidates))
 	confDstToleranceRatio := bs.sche.conf.GetDstToleranceRatio()
 	confEnableForTiFlash := bs.sche.conf.GetEnableForTiFlash()
+	regionConf := getRegionConfig(bs, bs.cur.region)
 	for _, detail := range candidates {
 		store := detail.StoreInfo
 		dstToleranceRatio := confDstToleranceRatio
@@ -1040,7 +1042,7 @@ func (bs *balanceSolver) pickDstStores(filters []filter.Filter, candidates []*st
 			}
 			dstToleranceRatio += tiflashToleranceRatioCorrection
 		}
-		if filter.Target(bs.GetSchedulerConfig(), store, filters) {
+		if filter.Target(regionConf, store, filters) {
 			id := store.GetID()
 			if !bs.checkDstByPriorityAndTolerance(detail.LoadPred.Max(), &detail.LoadPred.Expect, dstToleranceRatio) {
 				hotSchedulerResultCounter.WithLabelValues("dst-store-failed-"+bs.resourceTy.String(), strconv.FormatUint(id, 10)).Inc()
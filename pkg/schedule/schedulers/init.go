@@ -148,6 +148,7 @@ func schedulersRegister() {
 			return nil, err
 		}
 		conf.cluster = opController.GetCluster()
+		conf.sharedConfig = opController.GetSharedConfig()
 		conf.removeSchedulerCb = removeSchedulerCb[0]
 		return newEvictLeaderScheduler(opController, conf), nil
 	})
@@ -461,6 +462,17 @@ func schedulersRegister() {
 		return newTransferWitnessLeaderScheduler(opController), nil
 	})
 
+	// compaction pressure
+	RegisterSliceDecoderBuilder(CompactionPressureType, func([]string) ConfigDecoder {
+		return func(any) error {
+			return nil
+		}
+	})
+
+	RegisterScheduler(CompactionPressureType, func(opController *operator.Controller, _ endpoint.ConfigStorage, _ ConfigDecoder, _ ...func(string) error) (Scheduler, error) {
+		return newCompactionPressureScheduler(opController), nil
+	})
+
 	// evict slow store by trend
 	RegisterSliceDecoderBuilder(EvictSlowTrendType, func([]string) ConfigDecoder {
 		return func(any) error {
@@ -100,9 +100,10 @@ func (s *labelScheduler) Schedule(cluster sche.SchedulerCluster, _ bool) ([]*ope
 				excludeStores[p.GetStoreId()] = struct{}{}
 			}
 			f := filter.NewExcludedFilter(s.GetName(), nil, excludeStores)
+			leaderFilter := filter.NewLeaderTransferExcludedFilter(s.GetName(), s.OpController.GetLeaderTransferExcludedStores())
 
 			target := filter.NewCandidates(cluster.GetFollowerStores(region)).
-				FilterTarget(cluster.GetSchedulerConfig(), nil, nil, &filter.StoreStateFilter{ActionScope: LabelName, TransferLeader: true, OperatorLevel: constant.Medium}, f).
+				FilterTarget(cluster.GetSchedulerConfig(), nil, nil, &filter.StoreStateFilter{ActionScope: LabelName, TransferLeader: true, OperatorLevel: constant.Medium}, f, leaderFilter).
 				RandomPick()
 			if target == nil {
 				log.Debug("label scheduler no target found for region", zap.Uint64("region-id", region.GetID()))
@@ -79,6 +79,14 @@ var (
 			Help:      "Counter of direction of balance related schedulers.",
 		}, []string{"type", "source", "target"})
 
+	keyspaceBalanceCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "scheduler",
+			Name:      "keyspace_balance",
+			Help:      "Counter of the operators created by balance related schedulers per keyspace.",
+		}, []string{"keyspace", "type"})
+
 	// TODO: pre-allocate gauge metrics
 	hotDirectionCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -155,6 +163,7 @@ func init() {
 	prometheus.MustRegister(hotSchedulerResultCounter)
 	prometheus.MustRegister(hotDirectionCounter)
 	prometheus.MustRegister(balanceDirectionCounter)
+	prometheus.MustRegister(keyspaceBalanceCounter)
 	prometheus.MustRegister(opInfluenceStatus)
 	prometheus.MustRegister(tolerantResourceStatus)
 	prometheus.MustRegister(hotPendingStatus)
@@ -217,6 +226,10 @@ func splitBucketCounterWithEvent(event string) prometheus.Counter {
 	return schedulerCounter.WithLabelValues(types.SplitBucketScheduler.String(), event)
 }
 
+func compactionPressureCounterWithEvent(event string) prometheus.Counter {
+	return schedulerCounter.WithLabelValues(types.CompactionPressureScheduler.String(), event)
+}
+
 func transferWitnessLeaderCounterWithEvent(event string) prometheus.Counter {
 	return schedulerCounter.WithLabelValues(types.TransferWitnessLeaderScheduler.String(), event)
 }
@@ -336,8 +349,14 @@ var (
 	splitBucketNoSplitKeysCounter        = splitBucketCounterWithEvent("no-split-keys")
 	splitBucketCreateOperatorFailCounter = splitBucketCounterWithEvent("create-operator-fail")
 	splitBucketNewOperatorCounter        = splitBucketCounterWithEvent("new-operator")
+	splitBucketBatchLimitCounter         = splitBucketCounterWithEvent("batch-limit")
 
 	transferWitnessLeaderCounter              = transferWitnessLeaderCounterWithEvent("schedule")
 	transferWitnessLeaderNewOperatorCounter   = transferWitnessLeaderCounterWithEvent("new-operator")
 	transferWitnessLeaderNoTargetStoreCounter = transferWitnessLeaderCounterWithEvent("no-target-store")
+
+	compactionPressureCounter               = compactionPressureCounterWithEvent("schedule")
+	compactionPressureSaturatedStoreCounter = compactionPressureCounterWithEvent("saturated-store")
+	compactionPressureNoTargetStoreCounter  = compactionPressureCounterWithEvent("no-target-store")
+	compactionPressureNewOperatorCounter    = compactionPressureCounterWithEvent("new-operator")
 )
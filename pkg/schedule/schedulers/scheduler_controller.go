@@ -55,6 +55,8 @@ type Controller struct {
 	// which will only be initialized and used in the API service mode now.
 	schedulerHandlers map[string]http.Handler
 	opController      *operator.Controller
+	// decisionLog is shared by all the schedulers.
+	decisionLog *DecisionLog
 }
 
 // NewController creates a scheduler controller.
@@ -66,6 +68,7 @@ func NewController(ctx context.Context, cluster sche.SchedulerCluster, storage e
 		schedulers:        make(map[string]*ScheduleController),
 		schedulerHandlers: make(map[string]http.Handler),
 		opController:      opController,
+		decisionLog:       NewDecisionLog(cluster.GetSchedulerConfig()),
 	}
 }
 
@@ -74,6 +77,7 @@ func (c *Controller) Wait() {
 	c.Lock()
 	defer c.Unlock()
 	c.wg.Wait()
+	c.decisionLog.Close()
 }
 
 // GetScheduler returns a schedule controller by name.
@@ -197,6 +201,7 @@ func (c *Controller) AddScheduler(scheduler Scheduler, args ...string) error {
 	}
 
 	s := NewScheduleController(c.ctx, c.cluster, c.opController, scheduler)
+	s.decisionLog = c.decisionLog
 	if err := s.Scheduler.PrepareConfig(c.cluster); err != nil {
 		return err
 	}
@@ -428,6 +433,7 @@ type ScheduleController struct {
 	delayAt            int64
 	delayUntil         int64
 	diagnosticRecorder *DiagnosticRecorder
+	decisionLog        *DecisionLog
 }
 
 // NewScheduleController creates a new ScheduleController.
@@ -457,6 +463,13 @@ func (s *ScheduleController) Stop() {
 // Schedule tries to create some operators.
 func (s *ScheduleController) Schedule(diagnosable bool) []*operator.Operator {
 	_, isEvictLeaderScheduler := s.Scheduler.(*evictLeaderScheduler)
+	// The plans are collected for the decision log too, which records the
+	// rejected alternatives.
+	recordDecision := s.decisionLog.IsEnabled()
+	var (
+		rejectedPlans []plan.Plan
+		deniedOps     []*operator.Operator
+	)
 retry:
 	for i := 0; i < maxScheduleRetries; i++ {
 		// no need to retry if schedule should stop to speed exit
@@ -468,10 +481,13 @@ retry:
 		cacheCluster := newCacheCluster(s.cluster)
 		// we need only process diagnostic once in the retry loop
 		diagnosable = diagnosable && i == 0
-		ops, plans := s.Scheduler.Schedule(cacheCluster, diagnosable)
+		ops, plans := s.Scheduler.Schedule(cacheCluster, diagnosable || (recordDecision && i == 0))
 		if diagnosable {
 			s.diagnosticRecorder.SetResultFromPlans(ops, plans)
 		}
+		if recordDecision && i == 0 {
+			rejectedPlans = plans
+		}
 		if len(ops) == 0 {
 			continue
 		}
@@ -492,6 +508,9 @@ retry:
 			// Refer: https://docs.pingcap.com/tidb-in-kubernetes/stable/restart-a-tidb-cluster#perform-a-graceful-restart-to-a-single-tikv-pod
 			if labelMgr.ScheduleDisabled(region) && !isEvictLeaderScheduler {
 				denySchedulersByLabelerCounter.Inc()
+				if recordDecision {
+					deniedOps = append(deniedOps, ops[i])
+				}
 				ops = append(ops[:i], ops[i+1:]...)
 				i--
 			}
@@ -499,13 +518,35 @@ retry:
 		if len(ops) == 0 {
 			continue
 		}
+		if recordDecision {
+			s.decisionLog.Record(newDecisionRecord(s.cluster, s.Scheduler.GetName(), ops, rejectedPlans, deniedOps))
+		}
 		return ops
 	}
 	s.nextInterval = s.Scheduler.GetNextInterval(s.nextInterval)
+	if recordDecision && (len(rejectedPlans) > 0 || len(deniedOps) > 0) {
+		s.decisionLog.Record(newDecisionRecord(s.cluster, s.Scheduler.GetName(), nil, rejectedPlans, deniedOps))
+	}
 	return nil
 }
 
+// dryRunnableSchedulers are the schedulers whose scheduling is free of side effects
+// in the dry run mode. The others may persist their config or record events when
+// scheduling, e.g. evict-slow-store, so they can't be dry run.
+var dryRunnableSchedulers = map[string]struct{}{
+	BalanceRegionName:  {},
+	BalanceLeaderName:  {},
+	BalanceWitnessName: {},
+}
+
+// IsDryRunnable returns whether the scheduler can be dry run.
+func IsDryRunnable(name string) bool {
+	_, ok := dryRunnableSchedulers[name]
+	return ok
+}
+
 // DiagnoseDryRun returns the operators and plans of a scheduler.
+// The operators are not added to the operator controller.
 func (s *ScheduleController) DiagnoseDryRun() ([]*operator.Operator, []plan.Plan) {
 	cacheCluster := newCacheCluster(s.cluster)
 	return s.Scheduler.Schedule(cacheCluster, true)
@@ -16,6 +16,10 @@ package schedulers
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/docker/go-units"
@@ -504,3 +508,66 @@ func TestBalanceLeaderWithConflictRule(t *testing.T) {
 		}
 	}
 }
+
+func TestDecisionLog(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest()
+	defer cancel()
+	file := filepath.Join(t.TempDir(), "decision.log")
+	tc.SetDecisionLogFile(file)
+	tc.SetClusterVersion(versioninfo.MinSupportedVersion(versioninfo.Version4_0))
+	tc.SetEnablePlacementRules(false)
+	tc.SetMaxReplicasWithLabel(false, 1)
+	sb, err := CreateScheduler(BalanceRegionType, oc, storage.NewStorageWithMemoryBackend(), ConfigSliceDecoder(BalanceRegionType, []string{"", ""}))
+	re.NoError(err)
+	s := NewScheduleController(context.Background(), tc, oc, sb)
+	s.decisionLog = NewDecisionLog(tc.GetSchedulerConfig())
+	defer s.decisionLog.Close()
+
+	tc.AddRegionStore(1, 6)
+	tc.AddRegionStore(2, 8)
+	tc.AddRegionStore(3, 8)
+	tc.AddRegionStore(4, 16)
+	tc.AddLeaderRegion(1, 4)
+	re.NotEmpty(s.Schedule(false))
+	// the balanced cluster has no operators but the rejected alternatives.
+	tc.UpdateRegionCount(1, 16)
+	tc.UpdateRegionCount(2, 16)
+	tc.UpdateRegionCount(3, 16)
+	re.Empty(s.Schedule(false))
+
+	data, err := os.ReadFile(file)
+	re.NoError(err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	re.Len(lines, 2)
+	var record DecisionRecord
+	re.NoError(json.Unmarshal([]byte(lines[0]), &record))
+	re.Equal(BalanceRegionName, record.Scheduler)
+	re.Equal(4, record.Inputs.StoreCount)
+	re.Equal(1, record.Inputs.RegionCount)
+	re.Len(record.Operators, 1)
+	re.Equal(uint64(1), record.Operators[0].RegionID)
+	re.NotEmpty(record.Operators[0].Steps)
+	record = DecisionRecord{}
+	re.NoError(json.Unmarshal([]byte(lines[1]), &record))
+	re.Empty(record.Operators)
+	re.NotEmpty(record.Rejected)
+	for _, rejection := range record.Rejected {
+		re.NotEmpty(rejection.Reason)
+		re.Len(rejection.Resources, rejection.Step+1)
+	}
+
+	// the file can't be changed online.
+	otherFile := filepath.Join(t.TempDir(), "other.log")
+	tc.SetDecisionLogFile(otherFile)
+	re.Empty(s.Schedule(false))
+	_, err = os.Stat(otherFile)
+	re.True(os.IsNotExist(err))
+	data2, err := os.ReadFile(file)
+	re.NoError(err)
+	re.Len(strings.Split(strings.TrimSpace(string(data2)), "\n"), 3)
+
+	// the decisions are not recorded if the decision log is disabled at startup.
+	tc.SetDecisionLogFile("")
+	re.False(NewDecisionLog(tc.GetSchedulerConfig()).IsEnabled())
+}
@@ -86,6 +86,8 @@ func (s *shuffleLeaderScheduler) Schedule(cluster sche.SchedulerCluster, _ bool)
 	shuffleLeaderCounter.Inc()
 	targetStore := filter.NewCandidates(cluster.GetStores()).
 		FilterTarget(cluster.GetSchedulerConfig(), nil, nil, s.filters...).
+		FilterTarget(cluster.GetSchedulerConfig(), nil, nil,
+			filter.NewLeaderTransferExcludedFilter(s.GetName(), s.OpController.GetLeaderTransferExcludedStores())).
 		RandomPick()
 	if targetStore == nil {
 		shuffleLeaderNoTargetStoreCounter.Inc()
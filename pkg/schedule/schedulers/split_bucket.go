@@ -19,11 +19,14 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/errs"
 	sche "github.com/tikv/pd/pkg/schedule/core"
 	"github.com/tikv/pd/pkg/schedule/operator"
@@ -43,27 +46,36 @@ const (
 	// defaultHotDegree is the default hot region threshold.
 	defaultHotDegree  = 3
 	defaultSplitLimit = 10
+	// defaultSplitBatchSize is the default max number of the split operators
+	// created by one scheduling.
+	defaultSplitBatchSize = 4
 )
 
 func initSplitBucketConfig() *splitBucketSchedulerConfig {
 	return &splitBucketSchedulerConfig{
 		Degree:     defaultHotDegree,
 		SplitLimit: defaultSplitLimit,
+		BatchSize:  defaultSplitBatchSize,
 	}
 }
 
 type splitBucketSchedulerConfig struct {
 	syncutil.RWMutex
-	storage    endpoint.ConfigStorage
-	Degree     int    `json:"degree"`
+	storage endpoint.ConfigStorage
+	Degree  int `json:"degree"`
+	// SplitLimit is the global cap of the running split operators.
 	SplitLimit uint64 `json:"split-limit"`
+	// BatchSize is the max number of the split operators created by one scheduling.
+	BatchSize uint64 `json:"batch-size"`
 }
 
 func (conf *splitBucketSchedulerConfig) Clone() *splitBucketSchedulerConfig {
 	conf.RLock()
 	defer conf.RUnlock()
 	return &splitBucketSchedulerConfig{
-		Degree: conf.Degree,
+		Degree:     conf.Degree,
+		SplitLimit: conf.SplitLimit,
+		BatchSize:  conf.BatchSize,
 	}
 }
 
@@ -87,6 +99,17 @@ func (conf *splitBucketSchedulerConfig) getSplitLimit() uint64 {
 	return conf.SplitLimit
 }
 
+// getBatchSize returns the batch size, the config persisted by the older
+// version doesn't have it, so it creates one operator at a time as before.
+func (conf *splitBucketSchedulerConfig) getBatchSize() uint64 {
+	conf.RLock()
+	defer conf.RUnlock()
+	if conf.BatchSize == 0 {
+		return 1
+	}
+	return conf.BatchSize
+}
+
 type splitBucketScheduler struct {
 	*BaseScheduler
 	conf    *splitBucketSchedulerConfig
@@ -189,6 +212,7 @@ func (s *splitBucketScheduler) ReloadConfig() error {
 		return err
 	}
 	s.conf.SplitLimit = newCfg.SplitLimit
+	s.conf.BatchSize = newCfg.BatchSize
 	s.conf.Degree = newCfg.Degree
 	return nil
 }
@@ -232,9 +256,42 @@ func (s *splitBucketScheduler) Schedule(cluster sche.SchedulerCluster, _ bool) (
 	return s.splitBucket(plan), nil
 }
 
+// splitCandidate is the hottest bucket of a region which can be split.
+type splitCandidate struct {
+	region  *core.RegionInfo
+	bucket  *buckets.BucketStat
+	tableID int64
+}
+
+// splitBucket creates the split operators for the hot buckets in batch. The
+// number of the operators is capped by both the batch size and the remaining
+// quota of the split limit, and the quota is shared across the tables in a
+// round-robin way, so that a table with many hot regions can't starve others.
 func (s *splitBucketScheduler) splitBucket(plan *splitBucketPlan) []*operator.Operator {
-	var splitBucket *buckets.BucketStat
-	for regionID, buckets := range plan.hotBuckets {
+	limit := plan.conf.getBatchSize()
+	running := s.OpController.OperatorCount(operator.OpSplit)
+	if splitLimit := plan.conf.getSplitLimit(); running+limit > splitLimit {
+		if running >= splitLimit {
+			return nil
+		}
+		limit = splitLimit - running
+	}
+	candidates := s.collectSplitCandidates(plan)
+	if uint64(len(candidates)) > limit {
+		splitBucketBatchLimitCounter.Inc()
+	}
+	var ops []*operator.Operator
+	for _, candidate := range pickSplitCandidates(candidates, int(limit)) {
+		if op := createSplitBucketOperator(candidate); op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+func (s *splitBucketScheduler) collectSplitCandidates(plan *splitBucketPlan) []*splitCandidate {
+	var candidates []*splitCandidate
+	for regionID, stats := range plan.hotBuckets {
 		region := plan.cluster.GetRegion(regionID)
 		// skip if the region doesn't exist
 		if region == nil {
@@ -250,7 +307,8 @@ func (s *splitBucketScheduler) splitBucket(plan *splitBucketPlan) []*operator.Op
 			splitBucketOperatorExistCounter.Inc()
 			continue
 		}
-		for _, bucket := range buckets {
+		var splitBucket *buckets.BucketStat
+		for _, bucket := range stats {
 			// the key range of the bucket must less than the region.
 			// like bucket: [001 100] and region: [001 100] will not pass.
 			// like bucket: [003 100] and region: [002 100] will pass.
@@ -267,28 +325,66 @@ func (s *splitBucketScheduler) splitBucket(plan *splitBucketPlan) []*operator.Op
 				splitBucket = bucket
 			}
 		}
-	}
-	if splitBucket != nil {
-		region := plan.cluster.GetRegion(splitBucket.RegionID)
-		if region == nil {
-			return nil
+		if splitBucket != nil {
+			candidates = append(candidates, &splitCandidate{
+				region:  region,
+				bucket:  splitBucket,
+				tableID: codec.Key(region.GetStartKey()).TableID(),
+			})
 		}
-		splitKey := make([][]byte, 0)
-		if bytes.Compare(region.GetStartKey(), splitBucket.StartKey) < 0 {
-			splitKey = append(splitKey, splitBucket.StartKey)
+	}
+	return candidates
+}
+
+// pickSplitCandidates picks at most limit candidates. The tables take turns,
+// the hotter table goes first, and each table offers its hottest candidate
+// which is not picked yet in each turn.
+func pickSplitCandidates(candidates []*splitCandidate, limit int) []*splitCandidate {
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].bucket.HotDegree != candidates[j].bucket.HotDegree {
+			return candidates[i].bucket.HotDegree > candidates[j].bucket.HotDegree
 		}
-		if bytes.Compare(region.GetEndKey(), splitBucket.EndKey) > 0 {
-			splitKey = append(splitKey, splitBucket.EndKey)
+		return candidates[i].region.GetID() < candidates[j].region.GetID()
+	})
+	// the tables are in the order of their hottest candidates.
+	var tables []int64
+	byTable := make(map[int64][]*splitCandidate)
+	for _, candidate := range candidates {
+		if _, ok := byTable[candidate.tableID]; !ok {
+			tables = append(tables, candidate.tableID)
 		}
-		op, err := operator.CreateSplitRegionOperator(SplitBucketType, region, operator.OpSplit,
-			pdpb.CheckPolicy_USEKEY, splitKey)
-		if err != nil {
-			splitBucketCreateOperatorFailCounter.Inc()
-			return nil
+		byTable[candidate.tableID] = append(byTable[candidate.tableID], candidate)
+	}
+	picked := make([]*splitCandidate, 0, limit)
+	for round := 0; len(picked) < limit && len(picked) < len(candidates); round++ {
+		for _, tableID := range tables {
+			if len(picked) >= limit {
+				break
+			}
+			if round < len(byTable[tableID]) {
+				picked = append(picked, byTable[tableID][round])
+			}
 		}
-		splitBucketNewOperatorCounter.Inc()
-		op.SetAdditionalInfo("hot-degree", strconv.FormatInt(int64(splitBucket.HotDegree), 10))
-		return []*operator.Operator{op}
 	}
-	return nil
+	return picked
+}
+
+func createSplitBucketOperator(candidate *splitCandidate) *operator.Operator {
+	region, splitBucket := candidate.region, candidate.bucket
+	splitKey := make([][]byte, 0)
+	if bytes.Compare(region.GetStartKey(), splitBucket.StartKey) < 0 {
+		splitKey = append(splitKey, splitBucket.StartKey)
+	}
+	if bytes.Compare(region.GetEndKey(), splitBucket.EndKey) > 0 {
+		splitKey = append(splitKey, splitBucket.EndKey)
+	}
+	op, err := operator.CreateSplitRegionOperator(SplitBucketType, region, operator.OpSplit,
+		pdpb.CheckPolicy_USEKEY, splitKey)
+	if err != nil {
+		splitBucketCreateOperatorFailCounter.Inc()
+		return nil
+	}
+	splitBucketNewOperatorCounter.Inc()
+	op.SetAdditionalInfo("hot-degree", strconv.FormatInt(int64(splitBucket.HotDegree), 10))
+	return op
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/codec"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/statistics/buckets"
@@ -59,7 +60,7 @@ func TestSplitBucket(t *testing.T) {
 		tc.PutRegion(region)
 	}
 
-	conf := &splitBucketSchedulerConfig{Degree: 10}
+	conf := &splitBucketSchedulerConfig{Degree: 10, SplitLimit: defaultSplitLimit}
 	scheduler := newSplitBucketScheduler(oc, nil)
 
 	// case1: the key range of the hot bucket stat is [1 2] and the region is [1 10],
@@ -103,3 +104,64 @@ func TestSplitBucket(t *testing.T) {
 	step = ops[0].Step(0).(operator.SplitRegion)
 	re.Len(step.SplitKeys, 2)
 }
+
+func TestSplitBucketBatch(t *testing.T) {
+	re := require.New(t)
+	cancel, _, tc, oc := prepareSchedulersTest(false)
+	defer cancel()
+	for i := uint64(1); i <= 3; i++ {
+		tc.AddRegionStore(i, 10)
+	}
+	key := func(tableID, rowID int64) []byte {
+		return codec.EncodeBytes(codec.GenerateRowKey(tableID, rowID))
+	}
+	// table 1 has 4 hot regions, and table 2 has 2 cooler hot regions.
+	hotBuckets := make(map[uint64][]*buckets.BucketStat)
+	addHotRegion := func(regionID uint64, tableID int64, degree int) {
+		peers := []*metapb.Peer{{Id: regionID*10 + 1, StoreId: 1}, {Id: regionID*10 + 2, StoreId: 2}, {Id: regionID*10 + 3, StoreId: 3}}
+		rowID := int64(regionID) * 100
+		tc.PutRegion(core.NewRegionInfo(&metapb.Region{
+			Id:       regionID,
+			Peers:    peers,
+			StartKey: key(tableID, rowID),
+			EndKey:   key(tableID, rowID+100),
+		}, peers[0], core.SetApproximateSize(600)))
+		hotBuckets[regionID] = []*buckets.BucketStat{{
+			RegionID:  regionID,
+			HotDegree: degree,
+			StartKey:  key(tableID, rowID),
+			EndKey:    key(tableID, rowID+10),
+		}}
+	}
+	for i := uint64(1); i <= 4; i++ {
+		addHotRegion(i, 1, 20+int(i))
+	}
+	addHotRegion(5, 2, 10)
+	addHotRegion(6, 2, 11)
+
+	conf := &splitBucketSchedulerConfig{Degree: 10, SplitLimit: defaultSplitLimit, BatchSize: 4}
+	scheduler := newSplitBucketScheduler(oc, conf)
+	plan := &splitBucketPlan{
+		cluster:            tc,
+		hotBuckets:         hotBuckets,
+		hotRegionSplitSize: 512,
+		conf:               conf,
+	}
+	regionIDs := func(ops []*operator.Operator) []uint64 {
+		var ids []uint64
+		for _, op := range ops {
+			ids = append(ids, op.RegionID())
+		}
+		return ids
+	}
+	// the tables take turns, and the hotter ones go first.
+	ops := scheduler.splitBucket(plan)
+	re.Equal([]uint64{4, 6, 3, 5}, regionIDs(ops))
+
+	// the global cap counts the running split operators.
+	re.True(oc.AddOperator(ops[:2]...))
+	conf.SplitLimit = 3
+	re.Equal([]uint64{3}, regionIDs(scheduler.splitBucket(plan)))
+	conf.SplitLimit = 2
+	re.Empty(scheduler.splitBucket(plan))
+}
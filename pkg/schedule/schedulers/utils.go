@@ -23,7 +23,9 @@ import (
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/constant"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/schedule/config"
 	sche "github.com/tikv/pd/pkg/schedule/core"
+	"github.com/tikv/pd/pkg/schedule/filter"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/schedule/placement"
 	"github.com/tikv/pd/pkg/schedule/plan"
@@ -50,6 +52,11 @@ type solver struct {
 	tolerantSizeRatio float64
 	tolerantSource    int64
 	fit               *placement.RegionFit
+	// filters are the store filters used in this round, their verdicts are cached.
+	filters []filter.Filter
+	// leaderLoads is used to weight the leader score by the leader load, it's
+	// nil if the leaders are balanced by the leader schedule policy only.
+	leaderLoads *leaderLoads
 
 	sourceScore float64
 	targetScore float64
@@ -103,7 +110,7 @@ func (p *solver) sourceStoreScore(scheduleName string) float64 {
 	switch p.kind.Resource {
 	case constant.LeaderKind:
 		sourceDelta := influence - tolerantResource
-		score = p.Source.LeaderScore(p.kind.Policy, sourceDelta)
+		score = p.leaderScore(p.Source, p.kind.Policy, sourceDelta)
 	case constant.RegionKind:
 		sourceDelta := influence*influenceAmp - tolerantResource
 		score = p.Source.RegionScore(p.GetSchedulerConfig().GetRegionScoreFormulaVersion(), p.GetSchedulerConfig().GetHighSpaceRatio(), p.GetSchedulerConfig().GetLowSpaceRatio(), sourceDelta)
@@ -133,7 +140,7 @@ func (p *solver) targetStoreScore(scheduleName string) float64 {
 	switch p.kind.Resource {
 	case constant.LeaderKind:
 		targetDelta := influence + tolerantResource
-		score = p.Target.LeaderScore(p.kind.Policy, targetDelta)
+		score = p.leaderScore(p.Target, p.kind.Policy, targetDelta)
 	case constant.RegionKind:
 		targetDelta := influence*influenceAmp + tolerantResource
 		score = p.Target.RegionScore(p.GetSchedulerConfig().GetRegionScoreFormulaVersion(), p.GetSchedulerConfig().GetHighSpaceRatio(), p.GetSchedulerConfig().GetLowSpaceRatio(), targetDelta)
@@ -180,6 +187,27 @@ func (p *solver) getTolerantResource() int64 {
 	return p.tolerantSource
 }
 
+// appendKeyspaceBalanceCounter appends the counter of the keyspace which the region belongs to.
+func appendKeyspaceBalanceCounter(op *operator.Operator, name string, region *core.RegionInfo) {
+	if label, ok := operator.KeyspaceMetricsLabel(region); ok {
+		op.Counters = append(op.Counters, keyspaceBalanceCounter.WithLabelValues(label, name))
+	}
+}
+
+// getRangeOverrides returns the schedule parameters overridden for the key range of the region.
+func getRangeOverrides(cluster sche.SchedulerCluster, region *core.RegionInfo) *config.RangeOverrides {
+	if l := cluster.GetRegionLabeler(); l != nil {
+		return l.GetRangeOverrides(region)
+	}
+	return nil
+}
+
+// getRegionConfig returns the scheduler config with the range overrides of the
+// region applied, it's used to filter the target stores to move the region to.
+func getRegionConfig(cluster sche.SchedulerCluster, region *core.RegionInfo) config.SharedConfigProvider {
+	return config.WithRangeOverrides(cluster.GetSchedulerConfig(), getRangeOverrides(cluster, region))
+}
+
 func adjustTolerantRatio(cluster sche.SchedulerCluster, kind constant.ScheduleKind) float64 {
 	var tolerantSizeRatio float64
 	switch c := cluster.(type) {
@@ -69,6 +69,8 @@ const (
 	TransferWitnessLeaderScheduler CheckerSchedulerType = "transfer-witness-leader-scheduler"
 	// LabelScheduler is label scheduler name.
 	LabelScheduler CheckerSchedulerType = "label-scheduler"
+	// CompactionPressureScheduler is compaction pressure scheduler name.
+	CompactionPressureScheduler CheckerSchedulerType = "compaction-pressure-scheduler"
 )
 
 // SchedulerTypeCompatibleMap temporarily exists for compatibility.
@@ -91,6 +93,7 @@ var SchedulerTypeCompatibleMap = map[CheckerSchedulerType]string{
 	SplitBucketScheduler:           "split-bucket",
 	TransferWitnessLeaderScheduler: "transfer-witness-leader",
 	LabelScheduler:                 "label",
+	CompactionPressureScheduler:    "compaction-pressure",
 }
 
 var SchedulerStr2Type = map[string]CheckerSchedulerType{
@@ -112,4 +115,5 @@ var SchedulerStr2Type = map[string]CheckerSchedulerType{
 	"split-bucket-scheduler":            SplitBucketScheduler,
 	"transfer-witness-leader-scheduler": TransferWitnessLeaderScheduler,
 	"label-scheduler":                   LabelScheduler,
+	"compaction-pressure-scheduler":     CompactionPressureScheduler,
 }
@@ -81,6 +81,22 @@ var (
 			Name:      "label_level",
 			Help:      "Number of regions in the different label level.",
 		}, []string{"type"})
+
+	zoneQuorumLossGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "regions",
+			Name:      "zone_quorum_loss",
+			Help:      "Number of regions which would lose quorum if the zone failed.",
+		}, []string{"zone"})
+
+	zoneQuorumLossEventCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "regions",
+			Name:      "zone_quorum_loss_event",
+			Help:      "Counter of the events that regions begin to depend on a single zone for quorum.",
+		}, []string{"zone"})
 	readByteHist = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Namespace: "pd",
@@ -196,4 +212,6 @@ func init() {
 	prometheus.MustRegister(regionAbnormalPeerDuration)
 	prometheus.MustRegister(hotCacheFlowQueueStatusGauge)
 	prometheus.MustRegister(hotPeerSummary)
+	prometheus.MustRegister(zoneQuorumLossGauge)
+	prometheus.MustRegister(zoneQuorumLossEventCounter)
 }
@@ -0,0 +1,176 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+// ZoneQuorumStatistics records, per zone, the regions which would lose quorum
+// if that zone failed. The zone is the value of the top level location label.
+type ZoneQuorumStatistics struct {
+	syncutil.RWMutex
+	// regionZones records the zones a region depends on for quorum.
+	regionZones map[uint64][]string
+	zoneCounter map[string]int
+	// lastCollected is the counter when the metrics are collected last time,
+	// it's used to detect the transition from zero to non-zero.
+	lastCollected map[string]int
+}
+
+// NewZoneQuorumStatistics creates a new ZoneQuorumStatistics.
+func NewZoneQuorumStatistics() *ZoneQuorumStatistics {
+	return &ZoneQuorumStatistics{
+		regionZones:   make(map[uint64][]string),
+		zoneCounter:   make(map[string]int),
+		lastCollected: make(map[string]int),
+	}
+}
+
+// Observe records the zones which the region depends on for quorum.
+func (z *ZoneQuorumStatistics) Observe(region *core.RegionInfo, stores []*core.StoreInfo, locationLabels []string) {
+	var zones []string
+	if len(locationLabels) > 0 {
+		zones = getQuorumLossZones(region, stores, locationLabels[0])
+	}
+	regionID := region.GetID()
+	z.Lock()
+	defer z.Unlock()
+	old, ok := z.regionZones[regionID]
+	if ok && equalZones(old, zones) {
+		return
+	}
+	for _, zone := range old {
+		z.zoneCounter[zone]--
+	}
+	if len(zones) == 0 {
+		delete(z.regionZones, regionID)
+		return
+	}
+	for _, zone := range zones {
+		z.zoneCounter[zone]++
+	}
+	z.regionZones[regionID] = zones
+}
+
+// ClearDefunctRegion is used to handle the overlap region.
+func (z *ZoneQuorumStatistics) ClearDefunctRegion(regionID uint64) {
+	z.Lock()
+	defer z.Unlock()
+	for _, zone := range z.regionZones[regionID] {
+		z.zoneCounter[zone]--
+	}
+	delete(z.regionZones, regionID)
+}
+
+// GetZoneQuorumLossCount returns the number of the regions which would lose
+// quorum if the zone failed, zones without such regions are omitted.
+func (z *ZoneQuorumStatistics) GetZoneQuorumLossCount() map[string]int {
+	z.RLock()
+	defer z.RUnlock()
+	counter := make(map[string]int, len(z.zoneCounter))
+	for zone, count := range z.zoneCounter {
+		if count > 0 {
+			counter[zone] = count
+		}
+	}
+	return counter
+}
+
+// Collect collects the metrics and raises an event when the number of the
+// regions depending on a single zone rises above zero.
+func (z *ZoneQuorumStatistics) Collect() {
+	z.Lock()
+	defer z.Unlock()
+	for zone, count := range z.zoneCounter {
+		if count > 0 && z.lastCollected[zone] == 0 {
+			zoneQuorumLossEventCounter.WithLabelValues(zone).Inc()
+			log.Warn("some regions would lose quorum if the zone failed",
+				zap.String("zone", zone), zap.Int("region-count", count))
+		}
+		zoneQuorumLossGauge.WithLabelValues(zone).Set(float64(count))
+		if count == 0 {
+			delete(z.zoneCounter, zone)
+		}
+	}
+	for zone := range z.lastCollected {
+		if _, ok := z.zoneCounter[zone]; !ok {
+			zoneQuorumLossGauge.DeleteLabelValues(zone)
+		}
+	}
+	z.lastCollected = make(map[string]int, len(z.zoneCounter))
+	for zone, count := range z.zoneCounter {
+		z.lastCollected[zone] = count
+	}
+}
+
+// ResetZoneQuorumStatsMetrics resets the metrics of the zone quorum status.
+func ResetZoneQuorumStatsMetrics() {
+	zoneQuorumLossGauge.Reset()
+}
+
+// getQuorumLossZones returns the zones that hold enough voters of the region
+// to make it lose quorum once the zone fails.
+func getQuorumLossZones(region *core.RegionInfo, stores []*core.StoreInfo, zoneLabel string) []string {
+	storeZones := make(map[uint64]string, len(stores))
+	for _, s := range stores {
+		storeZones[s.GetID()] = s.GetLabelValue(zoneLabel)
+	}
+	voters := 0
+	votersInZone := make(map[string]int)
+	for _, peer := range region.GetPeers() {
+		if peer.GetRole() == metapb.PeerRole_Learner {
+			continue
+		}
+		voters++
+		if zone := storeZones[peer.GetStoreId()]; zone != "" {
+			votersInZone[zone]++
+		}
+	}
+	if voters == 0 {
+		return nil
+	}
+	quorum := voters/2 + 1
+	var zones []string
+	for zone, count := range votersInZone {
+		if voters-count < quorum {
+			zones = append(zones, zone)
+		}
+	}
+	return zones
+}
+
+func equalZones(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, za := range a {
+		found := false
+		for _, zb := range b {
+			if za == zb {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
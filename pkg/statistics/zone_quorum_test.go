@@ -0,0 +1,78 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+)
+
+func TestZoneQuorumStatistics(t *testing.T) {
+	re := require.New(t)
+	labels := []string{"zone", "host"}
+	stores := []*core.StoreInfo{
+		core.NewStoreInfoWithLabel(1, map[string]string{"zone": "z1", "host": "h1"}),
+		core.NewStoreInfoWithLabel(2, map[string]string{"zone": "z1", "host": "h2"}),
+		core.NewStoreInfoWithLabel(3, map[string]string{"zone": "z2", "host": "h3"}),
+		core.NewStoreInfoWithLabel(4, map[string]string{"zone": "z3", "host": "h4"}),
+	}
+	newRegion := func(id uint64, storeIDs ...uint64) *core.RegionInfo {
+		peers := make([]*metapb.Peer, 0, len(storeIDs))
+		for i, storeID := range storeIDs {
+			peers = append(peers, &metapb.Peer{Id: id*10 + uint64(i), StoreId: storeID})
+		}
+		return core.NewRegionInfo(&metapb.Region{Id: id, Peers: peers}, peers[0])
+	}
+	regionStores := func(region *core.RegionInfo) []*core.StoreInfo {
+		var res []*core.StoreInfo
+		for _, peer := range region.GetPeers() {
+			res = append(res, stores[peer.GetStoreId()-1])
+		}
+		return res
+	}
+
+	stats := NewZoneQuorumStatistics()
+	// region 1 places 2 of 3 voters in z1.
+	r1 := newRegion(1, 1, 2, 3)
+	stats.Observe(r1, regionStores(r1), labels)
+	// region 2 is well distributed.
+	r2 := newRegion(2, 1, 3, 4)
+	stats.Observe(r2, regionStores(r2), labels)
+	re.Equal(map[string]int{"z1": 1}, stats.GetZoneQuorumLossCount())
+
+	// observe the same region again should not change the counter.
+	stats.Observe(r1, regionStores(r1), labels)
+	re.Equal(map[string]int{"z1": 1}, stats.GetZoneQuorumLossCount())
+
+	// region 1 is fixed.
+	r1 = newRegion(1, 1, 3, 4)
+	stats.Observe(r1, regionStores(r1), labels)
+	re.Empty(stats.GetZoneQuorumLossCount())
+
+	// single replica region always depends on one zone.
+	r3 := newRegion(3, 3)
+	stats.Observe(r3, regionStores(r3), labels)
+	re.Equal(map[string]int{"z2": 1}, stats.GetZoneQuorumLossCount())
+	stats.Collect()
+	stats.ClearDefunctRegion(3)
+	re.Empty(stats.GetZoneQuorumLossCount())
+
+	// no location labels means no zone.
+	stats.Observe(r3, regionStores(r3), nil)
+	re.Empty(stats.GetZoneQuorumLossCount())
+}
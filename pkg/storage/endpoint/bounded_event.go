@@ -0,0 +1,119 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.etcd.io/etcd/clientv3"
+)
+
+// boundedEventCounts caches the number of the events under each prefix, so
+// the prefix is only scanned on the first write instead of every write.
+type boundedEventCounts struct {
+	syncutil.Mutex
+	counts map[string]int
+}
+
+func newBoundedEventCounts() *boundedEventCounts {
+	return &boundedEventCounts{counts: make(map[string]int)}
+}
+
+// BoundedEventStorage defines the operations on the cached counts of the bounded events.
+type BoundedEventStorage interface {
+	ResetBoundedEventCounts()
+}
+
+var _ BoundedEventStorage = (*StorageEndpoint)(nil)
+
+// ResetBoundedEventCounts drops the cached event counts, so they are recounted
+// from the storage on the next write. It should be called once the server
+// becomes the leader, since the other leaders may write events in the meantime.
+func (se *StorageEndpoint) ResetBoundedEventCounts() {
+	c := se.boundedEventCounts
+	c.Lock()
+	defer c.Unlock()
+	c.counts = make(map[string]int)
+}
+
+// saveBoundedEvent saves the event with the key under the prefix, and removes
+// the oldest events under the prefix once there are more than limit events.
+// The keys under the prefix must be in time order.
+func (se *StorageEndpoint) saveBoundedEvent(prefix, key string, event any, limit int) error {
+	c := se.boundedEventCounts
+	c.Lock()
+	defer c.Unlock()
+	count, ok := c.counts[prefix]
+	// Drop the cached count until the save succeeds, so it's recounted after
+	// any failure.
+	delete(c.counts, prefix)
+	if !ok {
+		if err := se.loadRangeByPrefix(prefix, func(_, _ string) { count++ }); err != nil {
+			return err
+		}
+	}
+	existed, err := se.Load(key)
+	if err != nil {
+		return err
+	}
+	if err := se.saveJSON(key, event); err != nil {
+		return err
+	}
+	if existed == "" {
+		count++
+	}
+	if count > limit {
+		keys, _, err := se.LoadRange(prefix, clientv3.GetPrefixRangeEnd(prefix), count-limit)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err := se.Remove(k); err != nil {
+				return err
+			}
+		}
+		count -= len(keys)
+	}
+	c.counts[prefix] = count
+	return nil
+}
+
+// loadEvents loads the events under the prefix in time order. It skips the
+// events out of the time range [startTime, endTime), and doesn't limit the end
+// time if the endTime is zero.
+func loadEvents[T any](se *StorageEndpoint, prefix string, startTime, endTime time.Time, eventTime func(*T) time.Time) ([]*T, error) {
+	events := make([]*T, 0)
+	var err error
+	if rangeErr := se.loadRangeByPrefix(prefix, func(_, v string) {
+		if err != nil {
+			return
+		}
+		event := new(T)
+		if err = json.Unmarshal([]byte(v), event); err != nil {
+			err = errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+			return
+		}
+		if t := eventTime(event); t.Before(startTime) || (!endTime.IsZero() && !t.Before(endTime)) {
+			return
+		}
+		events = append(events, event)
+	}); rangeErr != nil {
+		return nil, rangeErr
+	}
+	return events, err
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"strconv"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// ClusterStateEpochStorage defines the storage operations on the cluster state epoch.
+type ClusterStateEpochStorage interface {
+	LoadClusterStateEpoch() (uint64, error)
+	SaveClusterStateEpoch(epoch uint64) error
+}
+
+var _ ClusterStateEpochStorage = (*StorageEndpoint)(nil)
+
+// LoadClusterStateEpoch loads the upper bound of the reserved cluster state epochs from storage.
+func (se *StorageEndpoint) LoadClusterStateEpoch() (uint64, error) {
+	value, err := se.Load(ClusterStateEpochPath())
+	if err != nil || value == "" {
+		return 0, err
+	}
+	epoch, err := strconv.ParseUint(value, 16, 64)
+	if err != nil {
+		return 0, errs.ErrStrconvParseUint.Wrap(err).GenWithStackByArgs()
+	}
+	return epoch, nil
+}
+
+// SaveClusterStateEpoch saves the upper bound of the reserved cluster state epochs.
+func (se *StorageEndpoint) SaveClusterStateEpoch(epoch uint64) error {
+	value := strconv.FormatUint(epoch, 16)
+	return se.Save(ClusterStateEpochPath(), value)
+}
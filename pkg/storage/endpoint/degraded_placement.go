@@ -0,0 +1,88 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// The actions of the degraded placement transitions.
+const (
+	// DegradedPlacementEnter means the placement rules are relaxed for the down zones.
+	DegradedPlacementEnter = "enter"
+	// DegradedPlacementUpdate means the down zones are changed and the rules are relaxed again.
+	DegradedPlacementUpdate = "update"
+	// DegradedPlacementExit means the original placement rules are restored.
+	DegradedPlacementExit = "exit"
+)
+
+// maxDegradedPlacementEvents is the max number of the degraded placement
+// events kept, the oldest events are removed once it's exceeded.
+const maxDegradedPlacementEvents = 256
+
+// DegradedPlacementEvent is an audit record of a degraded placement transition.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type DegradedPlacementEvent struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`
+	DownZones []string  `json:"down_zones,omitempty"`
+	// Rules are the keys of the rules relaxed or restored in the transition.
+	Rules  []string `json:"rules,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+}
+
+// DegradedPlacementStorage defines the storage operations on the degraded placement.
+type DegradedPlacementStorage interface {
+	LoadDegradedPlacementState(state any) (bool, error)
+	SaveDegradedPlacementState(state any) error
+	SaveDegradedPlacementEvent(event *DegradedPlacementEvent) error
+	LoadDegradedPlacementEvents() ([]*DegradedPlacementEvent, error)
+}
+
+var _ DegradedPlacementStorage = (*StorageEndpoint)(nil)
+
+// LoadDegradedPlacementState loads the degraded placement state, it returns
+// false if the state doesn't exist.
+func (se *StorageEndpoint) LoadDegradedPlacementState(state any) (bool, error) {
+	value, err := se.Load(DegradedPlacementStatePath())
+	if err != nil || value == "" {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(value), state); err != nil {
+		return false, errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return true, nil
+}
+
+// SaveDegradedPlacementState saves the degraded placement state.
+func (se *StorageEndpoint) SaveDegradedPlacementState(state any) error {
+	return se.saveJSON(DegradedPlacementStatePath(), state)
+}
+
+// SaveDegradedPlacementEvent saves the degraded placement event and removes
+// the oldest events if there are too many events.
+func (se *StorageEndpoint) SaveDegradedPlacementEvent(event *DegradedPlacementEvent) error {
+	return se.saveBoundedEvent(degradedPlacementEventPrefix(),
+		DegradedPlacementEventPath(event.Time.UnixNano()), event, maxDegradedPlacementEvents)
+}
+
+// LoadDegradedPlacementEvents loads the degraded placement events in time order.
+func (se *StorageEndpoint) LoadDegradedPlacementEvents() ([]*DegradedPlacementEvent, error) {
+	return loadEvents(se, degradedPlacementEventPrefix(), time.Time{}, time.Time{},
+		func(event *DegradedPlacementEvent) time.Time { return event.Time })
+}
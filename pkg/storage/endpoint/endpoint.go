@@ -25,6 +25,7 @@ import (
 type StorageEndpoint struct {
 	kv.Base
 	encryptionKeyManager *encryption.Manager
+	boundedEventCounts   *boundedEventCounts
 }
 
 // NewStorageEndpoint creates a new base storage endpoint with the given KV and encryption key manager.
@@ -36,5 +37,6 @@ func NewStorageEndpoint(
 	return &StorageEndpoint{
 		kvBase,
 		encryptionKeyManager,
+		newBoundedEventCounts(),
 	}
 }
@@ -34,26 +34,39 @@ const (
 	ruleCommonPath            = "rule"
 	rulesPath                 = "rules"
 	ruleGroupPath             = "rule_group"
+	storeGroupPath            = "rule_store_group"
 	regionLabelPath           = "region_label"
+	regionLabelImportPath     = "label_rule_import"
 	replicationPath           = "replication_mode"
 	customSchedulerConfigPath = "scheduler_config"
 	// GCWorkerServiceSafePointID is the service id of GC worker.
 	GCWorkerServiceSafePointID = "gc_worker"
 	minResolvedTS              = "min_resolved_ts"
 	externalTimeStamp          = "external_timestamp"
+	clusterStateEpoch          = "state_epoch"
+	storeEventRevision         = "store_event_revision"
+	slowStoreEventPath         = "slow_store_event"
+	storeAddressChangePath     = "store_address_change"
+	storeReplacementPath       = "store_replacement"
+	storeCompactionStats       = "store_compaction_stats"
+	degradedPlacementPath      = "degraded_placement"
+	schedulingProfilePath      = "scheduling_profile"
 	keyspaceSafePointPrefix    = "keyspaces/gc_safepoint"
 	keyspaceGCSafePointSuffix  = "gc"
 	keyspacePrefix             = "keyspaces"
 	keyspaceMetaInfix          = "meta"
 	keyspaceIDInfix            = "id"
 	keyspaceAllocID            = "alloc_id"
+	keyspaceMetadataSchema     = "metadata_schema"
 	gcSafePointInfix           = "gc_safe_point"
 	serviceSafePointInfix      = "service_safe_point"
 	regionPathPrefix           = "raft/r"
 	// resource group storage endpoint has prefix `resource_group`
-	resourceGroupSettingsPath = "settings"
-	resourceGroupStatesPath   = "states"
-	controllerConfigPath      = "controller"
+	resourceGroupSettingsPath  = "settings"
+	resourceGroupStatesPath    = "states"
+	resourceGroupHierarchyPath = "hierarchy"
+	resourceGroupBypassPath    = "bypass"
+	controllerConfigPath       = "controller"
 	// tso storage endpoint has prefix `tso`
 	tsoServiceKey                = utils.TSOServiceName
 	globalTSOAllocatorEtcdPrefix = "gta"
@@ -63,6 +76,8 @@ const (
 	tsoKeyspaceGroupPrefix      = tsoServiceKey + "/" + utils.KeyspaceGroupsKey
 	keyspaceGroupsMembershipKey = "membership"
 	keyspaceGroupsElectionKey   = "election"
+	keyspaceGroupsProfileKey    = "profiles"
+	keyspaceGroupsAutoSplitKey  = "auto_split_events"
 
 	// we use uint64 to represent ID, the max length of uint64 is 20.
 	keyLen = 20
@@ -113,6 +128,11 @@ func RuleGroupPathPrefix(clusterID uint64) string {
 	return path.Join(PDRootPath(clusterID), ruleGroupPath)
 }
 
+// StoreGroupPathPrefix returns the path prefix to save the store groups.
+func StoreGroupPathPrefix(clusterID uint64) string {
+	return path.Join(PDRootPath(clusterID), storeGroupPath)
+}
+
 // RegionLabelPathPrefix returns the path prefix to save the region label.
 func RegionLabelPathPrefix(clusterID uint64) string {
 	return path.Join(PDRootPath(clusterID), regionLabelPath)
@@ -138,6 +158,56 @@ func ExtractStoreIDFromPath(clusterID uint64, path string) (uint64, error) {
 	return strconv.ParseUint(idStr, 10, 64)
 }
 
+// slowStoreEventPrefix returns the prefix of the slow store events of the given store.
+func slowStoreEventPrefix(storeID uint64) string {
+	return path.Join(slowStoreEventPath, fmt.Sprintf("%020d", storeID)) + "/"
+}
+
+// SlowStoreEventPath returns the path of the slow store event with the given store ID and timestamp.
+func SlowStoreEventPath(storeID uint64, ts int64) string {
+	return slowStoreEventPrefix(storeID) + fmt.Sprintf("%020d", ts)
+}
+
+// storeAddressChangePrefix returns the prefix of the address changes of the given store.
+func storeAddressChangePrefix(storeID uint64) string {
+	return path.Join(storeAddressChangePath, fmt.Sprintf("%020d", storeID)) + "/"
+}
+
+// StoreAddressChangePath returns the path of the store address change with the given store ID and timestamp.
+func StoreAddressChangePath(storeID uint64, ts int64) string {
+	return storeAddressChangePrefix(storeID) + fmt.Sprintf("%020d", ts)
+}
+
+// StoreReplacementPath returns the path of the replacement of the given old store.
+func StoreReplacementPath(oldStoreID uint64) string {
+	return path.Join(storeReplacementPath, fmt.Sprintf("%020d", oldStoreID))
+}
+
+// storeCompactionStatsPath returns the path of the compaction stats of the given store.
+func storeCompactionStatsPath(storeID uint64) string {
+	return path.Join(clusterPath, storeCompactionStats, fmt.Sprintf("%020d", storeID))
+}
+
+// StoreCompactionStatsPathPrefix returns the path prefix of the compaction stats of the stores.
+func StoreCompactionStatsPathPrefix(clusterID uint64) string {
+	return path.Join(PDRootPath(clusterID), clusterPath, storeCompactionStats) + "/"
+}
+
+// DegradedPlacementStatePath returns the path of the degraded placement state.
+func DegradedPlacementStatePath() string {
+	return path.Join(degradedPlacementPath, "state")
+}
+
+// degradedPlacementEventPrefix returns the prefix of the degraded placement events.
+func degradedPlacementEventPrefix() string {
+	return path.Join(degradedPlacementPath, "event") + "/"
+}
+
+// DegradedPlacementEventPath returns the path of the degraded placement event with the given timestamp.
+func DegradedPlacementEventPath(ts int64) string {
+	return degradedPlacementEventPrefix() + fmt.Sprintf("%020d", ts)
+}
+
 func storeLeaderWeightPath(storeID uint64) string {
 	return path.Join(schedulePath, "store_weight", fmt.Sprintf("%020d", storeID), "leader")
 }
@@ -178,6 +248,14 @@ func resourceGroupStateKeyPath(groupName string) string {
 	return path.Join(resourceGroupStatesPath, groupName)
 }
 
+func resourceGroupHierarchyKeyPath(groupName string) string {
+	return path.Join(resourceGroupHierarchyPath, groupName)
+}
+
+func resourceGroupBypassKeyPath(groupName string) string {
+	return path.Join(resourceGroupBypassPath, groupName)
+}
+
 func ruleKeyPath(ruleKey string) string {
 	return path.Join(rulesPath, ruleKey)
 }
@@ -186,6 +264,10 @@ func ruleGroupIDPath(groupID string) string {
 	return path.Join(ruleGroupPath, groupID)
 }
 
+func storeGroupIDPath(groupID string) string {
+	return path.Join(storeGroupPath, groupID)
+}
+
 func regionLabelKeyPath(ruleKey string) string {
 	return path.Join(regionLabelPath, ruleKey)
 }
@@ -217,6 +299,21 @@ func ExternalTimestampPath() string {
 	return path.Join(clusterPath, externalTimeStamp)
 }
 
+// ClusterStateEpochPath returns the cluster state epoch path.
+func ClusterStateEpochPath() string {
+	return path.Join(clusterPath, clusterStateEpoch)
+}
+
+// StoreEventRevisionPath returns the path of the revision of the store events.
+func StoreEventRevisionPath() string {
+	return path.Join(clusterPath, storeEventRevision)
+}
+
+// SchedulingProfilePath returns the path of the active scheduling profile.
+func SchedulingProfilePath() string {
+	return path.Join(clusterPath, schedulingProfilePath)
+}
+
 // GCSafePointV2Path is the storage path of gc safe point v2.
 // Path: keyspaces/gc_safe_point/{keyspaceID}
 func GCSafePointV2Path(keyspaceID uint32) string {
@@ -267,6 +364,12 @@ func KeyspaceIDAlloc() string {
 	return path.Join(keyspacePrefix, keyspaceAllocID)
 }
 
+// KeyspaceMetadataSchemaPath returns the path of the keyspace metadata schema.
+// Path: keyspaces/metadata_schema
+func KeyspaceMetadataSchemaPath() string {
+	return path.Join(keyspacePrefix, keyspaceMetadataSchema)
+}
+
 // EncodeKeyspaceID from uint32 to string.
 // It adds extra padding to make encoded ID ordered.
 // Encoded ID can be decoded directly with strconv.ParseUint.
@@ -287,6 +390,24 @@ func KeyspaceGroupIDPath(id uint32) string {
 	return path.Join(tsoKeyspaceGroupPrefix, keyspaceGroupsMembershipKey, encodeKeyspaceGroupID(id))
 }
 
+// KeyspaceGroupProfilePath returns the path to the keyspace group profile of the user kind.
+// Path: tso/keyspace_groups/profiles/{user_kind}
+func KeyspaceGroupProfilePath(userKind string) string {
+	return path.Join(tsoKeyspaceGroupPrefix, keyspaceGroupsProfileKey, userKind)
+}
+
+// keyspaceGroupAutoSplitEventPrefix returns the prefix of the keyspace group auto-split events.
+// Path: tso/keyspace_groups/auto_split_events/
+func keyspaceGroupAutoSplitEventPrefix() string {
+	return path.Join(tsoKeyspaceGroupPrefix, keyspaceGroupsAutoSplitKey) + "/"
+}
+
+// KeyspaceGroupAutoSplitEventPath returns the path of the keyspace group auto-split event with the given timestamp.
+// Path: tso/keyspace_groups/auto_split_events/{timestamp}
+func KeyspaceGroupAutoSplitEventPath(ts int64) string {
+	return keyspaceGroupAutoSplitEventPrefix() + fmt.Sprintf("%020d", ts)
+}
+
 // GetCompiledKeyspaceGroupIDRegexp returns the compiled regular expression for matching keyspace group id.
 func GetCompiledKeyspaceGroupIDRegexp() *regexp.Regexp {
 	pattern := strings.Join([]string{KeyspaceGroupIDPrefix(), `(\d{5})$`}, "/")
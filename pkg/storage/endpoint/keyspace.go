@@ -42,6 +42,9 @@ type KeyspaceStorage interface {
 	LoadKeyspaceID(txn kv.Txn, name string) (bool, uint32, error)
 	// LoadRangeKeyspace loads no more than limit keyspaces starting at startID.
 	LoadRangeKeyspace(txn kv.Txn, startID uint32, limit int) ([]*keyspacepb.KeyspaceMeta, error)
+	// SaveKeyspaceMetadataSchema saves the JSON schema of the keyspace metadata, the empty schema removes it.
+	SaveKeyspaceMetadataSchema(txn kv.Txn, schema string) error
+	LoadKeyspaceMetadataSchema(txn kv.Txn) (string, error)
 	RunInTxn(ctx context.Context, f func(txn kv.Txn) error) error
 }
 
@@ -119,3 +122,17 @@ func (*StorageEndpoint) LoadRangeKeyspace(txn kv.Txn, startID uint32, limit int)
 	}
 	return keyspaces, nil
 }
+
+// SaveKeyspaceMetadataSchema saves the JSON schema of the keyspace metadata.
+func (*StorageEndpoint) SaveKeyspaceMetadataSchema(txn kv.Txn, schema string) error {
+	if schema == "" {
+		return txn.Remove(KeyspaceMetadataSchemaPath())
+	}
+	return txn.Save(KeyspaceMetadataSchemaPath(), schema)
+}
+
+// LoadKeyspaceMetadataSchema loads the JSON schema of the keyspace metadata,
+// it returns empty if there is no schema.
+func (*StorageEndpoint) LoadKeyspaceMetadataSchema(txn kv.Txn) (string, error) {
+	return txn.Load(KeyspaceMetadataSchemaPath())
+}
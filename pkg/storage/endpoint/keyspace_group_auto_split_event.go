@@ -0,0 +1,59 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import "time"
+
+// The reasons of the keyspace group auto-split.
+const (
+	// AutoSplitReasonTSOQPS means the TSO QPS of the keyspace group exceeds the threshold.
+	AutoSplitReasonTSOQPS = "tso-qps"
+	// AutoSplitReasonKeyspaceCount means the keyspace count of the keyspace group exceeds the threshold.
+	AutoSplitReasonKeyspaceCount = "keyspace-count"
+)
+
+// maxKeyspaceGroupAutoSplitEvents is the max number of the auto-split events kept,
+// the oldest events are removed once it's exceeded.
+const maxKeyspaceGroupAutoSplitEvents = 1024
+
+// KeyspaceGroupAutoSplitEvent is an event of splitting a keyspace group automatically.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type KeyspaceGroupAutoSplitEvent struct {
+	Time time.Time `json:"time"`
+	// SplitSource is the ID of the keyspace group being split.
+	SplitSource uint32 `json:"split-source"`
+	// SplitTarget is the ID of the new keyspace group.
+	SplitTarget uint32 `json:"split-target"`
+	// Keyspaces are the keyspace IDs moved to the split target.
+	Keyspaces []uint32 `json:"keyspaces"`
+	Reason    string   `json:"reason"`
+	// KeyspaceCount and TSOQPS are the ones of the split source before the split.
+	KeyspaceCount int     `json:"keyspace-count"`
+	TSOQPS        float64 `json:"tso-qps"`
+}
+
+// SaveKeyspaceGroupAutoSplitEvent saves the keyspace group auto-split event and
+// removes the oldest events if there are too many events.
+func (se *StorageEndpoint) SaveKeyspaceGroupAutoSplitEvent(event *KeyspaceGroupAutoSplitEvent) error {
+	return se.saveBoundedEvent(keyspaceGroupAutoSplitEventPrefix(),
+		KeyspaceGroupAutoSplitEventPath(event.Time.UnixNano()), event, maxKeyspaceGroupAutoSplitEvents)
+}
+
+// LoadKeyspaceGroupAutoSplitEvents loads the keyspace group auto-split events in the
+// time range [startTime, endTime). It doesn't limit the end time if the endTime is zero.
+func (se *StorageEndpoint) LoadKeyspaceGroupAutoSplitEvents(startTime, endTime time.Time) ([]*KeyspaceGroupAutoSplitEvent, error) {
+	return loadEvents(se, keyspaceGroupAutoSplitEventPrefix(), startTime, endTime,
+		func(event *KeyspaceGroupAutoSplitEvent) time.Time { return event.Time })
+}
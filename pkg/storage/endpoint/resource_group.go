@@ -26,6 +26,12 @@ type ResourceGroupStorage interface {
 	LoadResourceGroupStates(f func(k, v string)) error
 	SaveResourceGroupStates(name string, obj any) error
 	DeleteResourceGroupStates(name string) error
+	LoadResourceGroupHierarchies(f func(k, v string)) error
+	SaveResourceGroupHierarchy(name string, obj any) error
+	DeleteResourceGroupHierarchy(name string) error
+	LoadResourceGroupBypasses(f func(k, v string)) error
+	SaveResourceGroupBypass(name string, obj any) error
+	DeleteResourceGroupBypass(name string) error
 	SaveControllerConfig(config any) error
 	LoadControllerConfig() (string, error)
 }
@@ -62,6 +68,36 @@ func (se *StorageEndpoint) LoadResourceGroupStates(f func(k, v string)) error {
 	return se.loadRangeByPrefix(resourceGroupStatesPath+"/", f)
 }
 
+// SaveResourceGroupHierarchy stores the parent of a resource group to storage.
+func (se *StorageEndpoint) SaveResourceGroupHierarchy(name string, obj any) error {
+	return se.saveJSON(resourceGroupHierarchyKeyPath(name), obj)
+}
+
+// DeleteResourceGroupHierarchy removes the parent of a resource group from storage.
+func (se *StorageEndpoint) DeleteResourceGroupHierarchy(name string) error {
+	return se.Remove(resourceGroupHierarchyKeyPath(name))
+}
+
+// LoadResourceGroupHierarchies loads the parents of all resource groups from storage.
+func (se *StorageEndpoint) LoadResourceGroupHierarchies(f func(k, v string)) error {
+	return se.loadRangeByPrefix(resourceGroupHierarchyPath+"/", f)
+}
+
+// SaveResourceGroupBypass stores the bypass setting of a resource group to storage.
+func (se *StorageEndpoint) SaveResourceGroupBypass(name string, obj any) error {
+	return se.saveJSON(resourceGroupBypassKeyPath(name), obj)
+}
+
+// DeleteResourceGroupBypass removes the bypass setting of a resource group from storage.
+func (se *StorageEndpoint) DeleteResourceGroupBypass(name string) error {
+	return se.Remove(resourceGroupBypassKeyPath(name))
+}
+
+// LoadResourceGroupBypasses loads the bypass settings of all resource groups from storage.
+func (se *StorageEndpoint) LoadResourceGroupBypasses(f func(k, v string)) error {
+	return se.loadRangeByPrefix(resourceGroupBypassPath+"/", f)
+}
+
 // SaveControllerConfig stores the resource controller config to storage.
 func (se *StorageEndpoint) SaveControllerConfig(config any) error {
 	return se.saveJSON(controllerConfigPath, config)
@@ -27,7 +27,9 @@ type RuleStorage interface {
 	LoadRule(ruleKey string) (string, error)
 	LoadRules(f func(k, v string)) error
 	LoadRuleGroups(f func(k, v string)) error
+	LoadStoreGroups(f func(k, v string)) error
 	LoadRegionRules(f func(k, v string)) error
+	LoadRegionRuleImport() (string, error)
 
 	// We need to use txn to avoid concurrent modification.
 	// And it is helpful for the scheduling server to watch the rule.
@@ -35,8 +37,12 @@ type RuleStorage interface {
 	DeleteRule(txn kv.Txn, ruleKey string) error
 	SaveRuleGroup(txn kv.Txn, groupID string, group any) error
 	DeleteRuleGroup(txn kv.Txn, groupID string) error
+	SaveStoreGroup(txn kv.Txn, groupID string, group any) error
+	DeleteStoreGroup(txn kv.Txn, groupID string) error
 	SaveRegionRule(txn kv.Txn, ruleKey string, rule any) error
 	DeleteRegionRule(txn kv.Txn, ruleKey string) error
+	SaveRegionRuleImport(txn kv.Txn, journal any) error
+	DeleteRegionRuleImport(txn kv.Txn) error
 
 	RunInTxn(ctx context.Context, f func(txn kv.Txn) error) error
 }
@@ -68,6 +74,21 @@ func (*StorageEndpoint) DeleteRuleGroup(txn kv.Txn, groupID string) error {
 	return txn.Remove(ruleGroupIDPath(groupID))
 }
 
+// LoadStoreGroups loads all store groups from storage.
+func (se *StorageEndpoint) LoadStoreGroups(f func(k, v string)) error {
+	return se.loadRangeByPrefix(storeGroupPath+"/", f)
+}
+
+// SaveStoreGroup stores a store group to storage.
+func (*StorageEndpoint) SaveStoreGroup(txn kv.Txn, groupID string, group any) error {
+	return saveJSONInTxn(txn, storeGroupIDPath(groupID), group)
+}
+
+// DeleteStoreGroup removes a store group from storage.
+func (*StorageEndpoint) DeleteStoreGroup(txn kv.Txn, groupID string) error {
+	return txn.Remove(storeGroupIDPath(groupID))
+}
+
 // LoadRegionRules loads region rules from storage.
 func (se *StorageEndpoint) LoadRegionRules(f func(k, v string)) error {
 	return se.loadRangeByPrefix(regionLabelPath+"/", f)
@@ -83,6 +104,22 @@ func (*StorageEndpoint) DeleteRegionRule(txn kv.Txn, ruleKey string) error {
 	return txn.Remove(regionLabelKeyPath(ruleKey))
 }
 
+// LoadRegionRuleImport loads the journal of the unfinished region rule import.
+// It is kept out of the region label path so that the watchers ignore it.
+func (se *StorageEndpoint) LoadRegionRuleImport() (string, error) {
+	return se.Load(regionLabelImportPath)
+}
+
+// SaveRegionRuleImport saves the journal of the region rule import.
+func (*StorageEndpoint) SaveRegionRuleImport(txn kv.Txn, journal any) error {
+	return saveJSONInTxn(txn, regionLabelImportPath, journal)
+}
+
+// DeleteRegionRuleImport removes the journal of the region rule import.
+func (*StorageEndpoint) DeleteRegionRuleImport(txn kv.Txn) error {
+	return txn.Remove(regionLabelImportPath)
+}
+
 // LoadRule load a placement rule from storage.
 func (se *StorageEndpoint) LoadRule(ruleKey string) (string, error) {
 	return se.Load(ruleKeyPath(ruleKey))
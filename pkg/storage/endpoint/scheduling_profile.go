@@ -0,0 +1,34 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+// SchedulingProfileStorage defines the storage operations on the scheduling profile.
+type SchedulingProfileStorage interface {
+	LoadSchedulingProfile() (string, error)
+	SaveSchedulingProfile(name string) error
+}
+
+var _ SchedulingProfileStorage = (*StorageEndpoint)(nil)
+
+// LoadSchedulingProfile loads the name of the active scheduling profile,
+// it returns an empty string if no profile has been applied.
+func (se *StorageEndpoint) LoadSchedulingProfile() (string, error) {
+	return se.Load(SchedulingProfilePath())
+}
+
+// SaveSchedulingProfile saves the name of the active scheduling profile.
+func (se *StorageEndpoint) SaveSchedulingProfile(name string) error {
+	return se.Save(SchedulingProfilePath(), name)
+}
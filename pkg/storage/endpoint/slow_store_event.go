@@ -0,0 +1,76 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import "time"
+
+// The types of the slow store events.
+const (
+	// SlowStoreEventBecomeSlow means the slow score of the store reaches the slow threshold.
+	SlowStoreEventBecomeSlow = "become-slow"
+	// SlowStoreEventBecomeNormal means the slow score of the store falls below the slow threshold.
+	SlowStoreEventBecomeNormal = "become-normal"
+	// SlowStoreEventEvict means the leaders of the store are evicted by the evict-slow-store scheduler.
+	SlowStoreEventEvict = "evict-slow-store"
+	// SlowStoreEventRecover means the store is recovered from the evict-slow-store scheduler.
+	SlowStoreEventRecover = "recover-slow-store"
+	// SlowStoreEventEvictByTrend means the leaders of the store are evicted by the evict-slow-trend scheduler.
+	SlowStoreEventEvictByTrend = "evict-slow-trend"
+	// SlowStoreEventRecoverByTrend means the store is recovered from the evict-slow-trend scheduler.
+	SlowStoreEventRecoverByTrend = "recover-slow-trend"
+	// SlowStoreEventEvictRefused means the eviction of the store is refused because
+	// too many stores are evicting or offline.
+	SlowStoreEventEvictRefused = "evict-refused"
+)
+
+// maxSlowStoreEventsPerStore is the max number of the events kept for a store,
+// the oldest events are removed once it's exceeded.
+const maxSlowStoreEventsPerStore = 256
+
+// SlowStoreEvent is an event in the slow store timeline.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type SlowStoreEvent struct {
+	StoreID   uint64    `json:"store_id"`
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	SlowScore uint64    `json:"slow_score"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// SlowStoreEventStorage defines the storage operations on the slow store events.
+type SlowStoreEventStorage interface {
+	SaveSlowStoreEvent(event *SlowStoreEvent) error
+	LoadSlowStoreEvents(storeID uint64, startTime, endTime time.Time) ([]*SlowStoreEvent, error)
+}
+
+var _ SlowStoreEventStorage = (*StorageEndpoint)(nil)
+
+// SaveSlowStoreEvent saves the slow store event and removes the oldest events
+// of the store if there are too many events.
+func (se *StorageEndpoint) SaveSlowStoreEvent(event *SlowStoreEvent) error {
+	return se.saveBoundedEvent(slowStoreEventPrefix(event.StoreID),
+		SlowStoreEventPath(event.StoreID, event.Time.UnixNano()), event, maxSlowStoreEventsPerStore)
+}
+
+// LoadSlowStoreEvents loads the slow store events in the time range [startTime, endTime).
+// It loads the events of all stores if the storeID is 0, and doesn't limit the
+// end time if the endTime is zero.
+func (se *StorageEndpoint) LoadSlowStoreEvents(storeID uint64, startTime, endTime time.Time) ([]*SlowStoreEvent, error) {
+	prefix := slowStoreEventPath + "/"
+	if storeID != 0 {
+		prefix = slowStoreEventPrefix(storeID)
+	}
+	return loadEvents(se, prefix, startTime, endTime, func(event *SlowStoreEvent) time.Time { return event.Time })
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// maxStoreAddressChangesPerStore is the max number of the address changes kept
+// for a store, the oldest changes are removed once it's exceeded.
+const maxStoreAddressChangesPerStore = 64
+
+// StoreAddressChange records that a store re-registers with a changed address.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreAddressChange struct {
+	StoreID    uint64    `json:"store_id"`
+	Time       time.Time `json:"time"`
+	OldAddress string    `json:"old_address"`
+	NewAddress string    `json:"new_address"`
+	// StartTimestamp is the start timestamp of the store process which changes the address.
+	StartTimestamp int64 `json:"start_timestamp"`
+}
+
+// StoreAddressChangeStorage defines the storage operations on the store address changes.
+type StoreAddressChangeStorage interface {
+	SaveStoreAddressChange(change *StoreAddressChange) error
+	LoadStoreAddressChanges(storeID uint64) ([]*StoreAddressChange, error)
+}
+
+var _ StoreAddressChangeStorage = (*StorageEndpoint)(nil)
+
+// SaveStoreAddressChange saves the store address change and removes the oldest
+// changes of the store if there are too many changes.
+func (se *StorageEndpoint) SaveStoreAddressChange(change *StoreAddressChange) error {
+	if err := se.saveJSON(StoreAddressChangePath(change.StoreID, change.Time.UnixNano()), change); err != nil {
+		return err
+	}
+	prefix := storeAddressChangePrefix(change.StoreID)
+	var keys []string
+	if err := se.loadRangeByPrefix(prefix, func(k, _ string) {
+		keys = append(keys, prefix+k)
+	}); err != nil {
+		return err
+	}
+	for i := 0; i < len(keys)-maxStoreAddressChangesPerStore; i++ {
+		if err := se.Remove(keys[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadStoreAddressChanges loads the address changes of the store in time order.
+// It loads the changes of all stores if the storeID is 0.
+func (se *StorageEndpoint) LoadStoreAddressChanges(storeID uint64) ([]*StoreAddressChange, error) {
+	prefix := storeAddressChangePath + "/"
+	if storeID != 0 {
+		prefix = storeAddressChangePrefix(storeID)
+	}
+	changes := make([]*StoreAddressChange, 0)
+	var err error
+	if rangeErr := se.loadRangeByPrefix(prefix, func(_, v string) {
+		if err != nil {
+			return
+		}
+		change := &StoreAddressChange{}
+		if err = json.Unmarshal([]byte(v), change); err != nil {
+			err = errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+			return
+		}
+		changes = append(changes, change)
+	}); rangeErr != nil {
+		return nil, rangeErr
+	}
+	return changes, err
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+// StoreCompactionStatsStorage defines the storage operations on the compaction stats of the stores.
+// The stats are only saved to be watched by the scheduling service.
+type StoreCompactionStatsStorage interface {
+	SaveStoreCompactionStats(storeID uint64, stats any) error
+	DeleteStoreCompactionStats(storeID uint64) error
+}
+
+var _ StoreCompactionStatsStorage = (*StorageEndpoint)(nil)
+
+// SaveStoreCompactionStats saves the compaction stats of the store.
+func (se *StorageEndpoint) SaveStoreCompactionStats(storeID uint64, stats any) error {
+	return se.saveJSON(storeCompactionStatsPath(storeID), stats)
+}
+
+// DeleteStoreCompactionStats removes the compaction stats of the store.
+func (se *StorageEndpoint) DeleteStoreCompactionStats(storeID uint64) error {
+	return se.Remove(storeCompactionStatsPath(storeID))
+}
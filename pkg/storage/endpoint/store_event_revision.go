@@ -0,0 +1,47 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"strconv"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// StoreEventRevisionStorage defines the storage operations on the revision of the store events.
+type StoreEventRevisionStorage interface {
+	LoadStoreEventRevision() (uint64, error)
+	SaveStoreEventRevision(revision uint64) error
+}
+
+var _ StoreEventRevisionStorage = (*StorageEndpoint)(nil)
+
+// LoadStoreEventRevision loads the revision of the store events from storage.
+func (se *StorageEndpoint) LoadStoreEventRevision() (uint64, error) {
+	value, err := se.Load(StoreEventRevisionPath())
+	if err != nil || value == "" {
+		return 0, err
+	}
+	revision, err := strconv.ParseUint(value, 16, 64)
+	if err != nil {
+		return 0, errs.ErrStrconvParseUint.Wrap(err).GenWithStackByArgs()
+	}
+	return revision, nil
+}
+
+// SaveStoreEventRevision saves the revision of the store events.
+func (se *StorageEndpoint) SaveStoreEventRevision(revision uint64) error {
+	return se.Save(StoreEventRevisionPath(), strconv.FormatUint(revision, 16))
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// StoreReplacement pairs an old store with the new one replacing it.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreReplacement struct {
+	OldStoreID uint64    `json:"old_store_id"`
+	NewStoreID uint64    `json:"new_store_id"`
+	StartTime  time.Time `json:"start_time"`
+}
+
+// StoreReplacementStorage defines the storage operations on the store replacements.
+type StoreReplacementStorage interface {
+	SaveStoreReplacement(replacement *StoreReplacement) error
+	DeleteStoreReplacement(oldStoreID uint64) error
+	LoadStoreReplacements() ([]*StoreReplacement, error)
+}
+
+var _ StoreReplacementStorage = (*StorageEndpoint)(nil)
+
+// SaveStoreReplacement saves the replacement of the old store.
+func (se *StorageEndpoint) SaveStoreReplacement(replacement *StoreReplacement) error {
+	return se.saveJSON(StoreReplacementPath(replacement.OldStoreID), replacement)
+}
+
+// DeleteStoreReplacement deletes the replacement of the old store.
+func (se *StorageEndpoint) DeleteStoreReplacement(oldStoreID uint64) error {
+	return se.Remove(StoreReplacementPath(oldStoreID))
+}
+
+// LoadStoreReplacements loads all the store replacements.
+func (se *StorageEndpoint) LoadStoreReplacements() ([]*StoreReplacement, error) {
+	replacements := make([]*StoreReplacement, 0)
+	var err error
+	if rangeErr := se.loadRangeByPrefix(storeReplacementPath+"/", func(_, v string) {
+		if err != nil {
+			return
+		}
+		replacement := &StoreReplacement{}
+		if err = json.Unmarshal([]byte(v), replacement); err != nil {
+			err = errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+			return
+		}
+		replacements = append(replacements, replacement)
+	}); rangeErr != nil {
+		return nil, rangeErr
+	}
+	return replacements, err
+}
@@ -17,7 +17,12 @@ package endpoint
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/pkg/storage/kv"
 	"github.com/tikv/pd/pkg/utils/typeutil"
@@ -27,6 +32,19 @@ import (
 // UserKind represents the user kind.
 type UserKind int
 
+// The TSO settings of the keyspace group, which override the ones of the TSO servers.
+const (
+	// TSOUpdatePhysicalIntervalKey is the interval to update the physical part of the TSO.
+	TSOUpdatePhysicalIntervalKey = "tso-update-physical-interval"
+	// TSOSaveIntervalKey is the interval to save the timestamp window to the storage.
+	TSOSaveIntervalKey = "tso-save-interval"
+	// TSOMaxBatchWaitKey caps the time to wait for more requests to merge when the TSO
+	// requests forwarded to the keyspace group are batched adaptively.
+	TSOMaxBatchWaitKey = "tso-max-batch-wait"
+	// TSOMaxBatchSizeKey caps the logical count of a merged TSO request forwarded to the keyspace group.
+	TSOMaxBatchSizeKey = "tso-max-batch-size"
+)
+
 // Different user kinds.
 const (
 	Basic UserKind = iota
@@ -115,6 +133,9 @@ type KeyspaceGroup struct {
 	Members []KeyspaceGroupMember `json:"members"`
 	// Keyspaces are the keyspace IDs which belong to the keyspace group.
 	Keyspaces []uint32 `json:"keyspaces"`
+	// TSOConfig is the TSO settings of the keyspace group, which is inherited
+	// from the profile of its user kind when the keyspace group is created.
+	TSOConfig map[string]string `json:"tso-config,omitempty"`
 	// KeyspaceLookupTable is for fast lookup if a given keyspace belongs to this keyspace group.
 	// It's not persisted and will be built when loading from storage.
 	KeyspaceLookupTable map[uint32]struct{} `json:"-"`
@@ -159,12 +180,108 @@ func (kg *KeyspaceGroup) IsMergeSource() bool {
 	return kg.IsMerging() && slice.Contains(kg.MergeState.MergeList, kg.ID)
 }
 
+// GetTSODuration returns the duration of the TSO setting of the keyspace group.
+// It returns 0 if the setting is absent.
+func (kg *KeyspaceGroup) GetTSODuration(key string) (time.Duration, error) {
+	if kg == nil {
+		return 0, nil
+	}
+	return getTSODuration(kg.TSOConfig, key)
+}
+
+func getTSODuration(tsoConfig map[string]string, key string) (time.Duration, error) {
+	value, ok := tsoConfig[key]
+	if !ok {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errs.ErrInvalidTSOConfig.FastGenByArgs(key, err.Error())
+	}
+	if d <= 0 {
+		return 0, errs.ErrInvalidTSOConfig.FastGenByArgs(key, "the duration should be positive")
+	}
+	return d, nil
+}
+
+// GetTSOBatchCaps returns the caps of the adaptive batching of the TSO requests forwarded
+// to the keyspace group. The zero values mean no cap, as well as the invalid settings.
+func (kg *KeyspaceGroup) GetTSOBatchCaps() (maxBatchWait time.Duration, maxBatchSize uint32) {
+	if kg == nil {
+		return 0, 0
+	}
+	maxBatchWait, _ = getTSODuration(kg.TSOConfig, TSOMaxBatchWaitKey)
+	maxBatchSize, _ = getTSOCount(kg.TSOConfig, TSOMaxBatchSizeKey)
+	return
+}
+
+func getTSOCount(tsoConfig map[string]string, key string) (uint32, error) {
+	value, ok := tsoConfig[key]
+	if !ok {
+		return 0, nil
+	}
+	count, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, errs.ErrInvalidTSOConfig.FastGenByArgs(key, err.Error())
+	}
+	if count == 0 {
+		return 0, errs.ErrInvalidTSOConfig.FastGenByArgs(key, "the count should be positive")
+	}
+	return uint32(count), nil
+}
+
+// ValidateTSOConfig checks the TSO settings which can be overridden by the keyspace group.
+func ValidateTSOConfig(tsoConfig map[string]string) error {
+	if err := checkTSODurationRange(tsoConfig, TSOUpdatePhysicalIntervalKey,
+		utils.MinTSOUpdatePhysicalInterval, utils.MaxTSOUpdatePhysicalInterval); err != nil {
+		return err
+	}
+	if err := checkTSODurationRange(tsoConfig, TSOSaveIntervalKey,
+		utils.MinTSOSaveInterval, utils.MaxTSOSaveInterval); err != nil {
+		return err
+	}
+	if _, err := getTSODuration(tsoConfig, TSOMaxBatchWaitKey); err != nil {
+		return err
+	}
+	_, err := getTSOCount(tsoConfig, TSOMaxBatchSizeKey)
+	return err
+}
+
+func checkTSODurationRange(tsoConfig map[string]string, key string, minDuration, maxDuration time.Duration) error {
+	duration, err := getTSODuration(tsoConfig, key)
+	if err != nil {
+		return err
+	}
+	if duration != 0 && (duration < minDuration || duration > maxDuration) {
+		return errs.ErrInvalidTSOConfig.FastGenByArgs(key,
+			fmt.Sprintf("the duration should be in [%v, %v]", minDuration, maxDuration))
+	}
+	return nil
+}
+
+// KeyspaceGroupProfile is the default configuration of the keyspace groups of a user kind.
+// The new keyspace groups of the user kind inherit the configuration from the profile.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type KeyspaceGroupProfile struct {
+	UserKind string `json:"user-kind"`
+	// ReplicaCount is the desired number of the members of the keyspace group.
+	ReplicaCount int `json:"replica-count"`
+	// Priority is the priority of the members allocated to the keyspace group.
+	Priority int `json:"priority"`
+	// TSOConfig is the TSO settings inherited by the keyspace group.
+	TSOConfig map[string]string `json:"tso-config,omitempty"`
+}
+
 // KeyspaceGroupStorage is the interface for keyspace group storage.
 type KeyspaceGroupStorage interface {
 	LoadKeyspaceGroups(startID uint32, limit int) ([]*KeyspaceGroup, error)
 	LoadKeyspaceGroup(txn kv.Txn, id uint32) (*KeyspaceGroup, error)
 	SaveKeyspaceGroup(txn kv.Txn, kg *KeyspaceGroup) error
 	DeleteKeyspaceGroup(txn kv.Txn, id uint32) error
+	LoadKeyspaceGroupProfile(txn kv.Txn, userKind string) (*KeyspaceGroupProfile, error)
+	SaveKeyspaceGroupProfile(txn kv.Txn, profile *KeyspaceGroupProfile) error
+	SaveKeyspaceGroupAutoSplitEvent(event *KeyspaceGroupAutoSplitEvent) error
+	LoadKeyspaceGroupAutoSplitEvents(startTime, endTime time.Time) ([]*KeyspaceGroupAutoSplitEvent, error)
 	// TODO: add more interfaces.
 	RunInTxn(ctx context.Context, f func(txn kv.Txn) error) error
 }
@@ -216,3 +333,21 @@ func (se *StorageEndpoint) LoadKeyspaceGroups(startID uint32, limit int) ([]*Key
 	}
 	return kgs, nil
 }
+
+// LoadKeyspaceGroupProfile loads the keyspace group profile of the user kind.
+func (*StorageEndpoint) LoadKeyspaceGroupProfile(txn kv.Txn, userKind string) (*KeyspaceGroupProfile, error) {
+	value, err := txn.Load(KeyspaceGroupProfilePath(userKind))
+	if err != nil || value == "" {
+		return nil, err
+	}
+	profile := &KeyspaceGroupProfile{}
+	if err := json.Unmarshal([]byte(value), profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// SaveKeyspaceGroupProfile saves the keyspace group profile.
+func (*StorageEndpoint) SaveKeyspaceGroupProfile(txn kv.Txn, profile *KeyspaceGroupProfile) error {
+	return saveJSONInTxn(txn, KeyspaceGroupProfilePath(profile.UserKind), profile)
+}
@@ -29,10 +29,7 @@ import (
 	"go.uber.org/zap"
 )
 
-const (
-	requestTimeout  = 10 * time.Second
-	slowRequestTime = time.Second
-)
+const requestTimeout = 10 * time.Second
 
 var (
 	// WithLabelValues is a heavy operation, define variable to avoid call it every time.
@@ -137,6 +134,9 @@ func (kv *etcdKVBase) Remove(key string) error {
 type SlowLogTxn struct {
 	clientv3.Txn
 	cancel context.CancelFunc
+	// key and size are used to attribute the slow transaction.
+	key  string
+	size int
 }
 
 // NewSlowLogTxn create a SlowLogTxn.
@@ -159,6 +159,12 @@ func (t *SlowLogTxn) If(cs ...clientv3.Cmp) clientv3.Txn {
 // Then takes a list of operations. The Ops list will be executed, if the
 // comparisons passed in If() succeed.
 func (t *SlowLogTxn) Then(ops ...clientv3.Op) clientv3.Txn {
+	for _, op := range ops {
+		if len(t.key) == 0 {
+			t.key = string(op.KeyBytes())
+		}
+		t.size += len(op.KeyBytes()) + len(op.ValueBytes())
+	}
 	t.Txn = t.Txn.Then(ops...)
 	return t
 }
@@ -170,8 +176,9 @@ func (t *SlowLogTxn) Commit() (*clientv3.TxnResponse, error) {
 	t.cancel()
 
 	cost := time.Since(start)
-	if cost > slowRequestTime {
+	if etcdutil.RecordSlowTxn(t.key, t.size, cost, err) {
 		log.Warn("txn runs too slow",
+			zap.String("key", t.key),
 			zap.Reflect("response", resp),
 			zap.Duration("cost", cost),
 			errs.ZapError(err))
@@ -40,6 +40,15 @@ type Storage interface {
 	endpoint.GCSafePointStorage
 	endpoint.MinResolvedTSStorage
 	endpoint.ExternalTSStorage
+	endpoint.ClusterStateEpochStorage
+	endpoint.StoreEventRevisionStorage
+	endpoint.StoreCompactionStatsStorage
+	endpoint.BoundedEventStorage
+	endpoint.SlowStoreEventStorage
+	endpoint.StoreAddressChangeStorage
+	endpoint.StoreReplacementStorage
+	endpoint.DegradedPlacementStorage
+	endpoint.SchedulingProfileStorage
 	endpoint.SafePointV2Storage
 	endpoint.KeyspaceStorage
 	endpoint.ResourceGroupStorage
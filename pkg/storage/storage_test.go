@@ -30,6 +30,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/storage/kv"
 	"go.etcd.io/etcd/clientv3"
 )
 
@@ -126,6 +127,87 @@ func TestStoreWeight(t *testing.T) {
 	}
 }
 
+func TestSlowStoreEvents(t *testing.T) {
+	re := require.New(t)
+	storage := NewStorageWithMemoryBackend()
+	start := time.Unix(1000, 0)
+	for i := 0; i < 300; i++ {
+		re.NoError(storage.SaveSlowStoreEvent(&endpoint.SlowStoreEvent{
+			StoreID: 1,
+			Time:    start.Add(time.Duration(i) * time.Second),
+			Type:    endpoint.SlowStoreEventBecomeSlow,
+		}))
+	}
+	re.NoError(storage.SaveSlowStoreEvent(&endpoint.SlowStoreEvent{
+		StoreID: 2,
+		Time:    start,
+		Type:    endpoint.SlowStoreEventEvict,
+	}))
+
+	// the oldest events are removed.
+	events, err := storage.LoadSlowStoreEvents(1, time.Time{}, time.Time{})
+	re.NoError(err)
+	re.Len(events, 256)
+	re.Equal(start.Add(44*time.Second).UnixNano(), events[0].Time.UnixNano())
+	events, err = storage.LoadSlowStoreEvents(0, time.Time{}, time.Time{})
+	re.NoError(err)
+	re.Len(events, 257)
+	re.Equal(uint64(2), events[256].StoreID)
+	events, err = storage.LoadSlowStoreEvents(1, start.Add(100*time.Second), start.Add(110*time.Second))
+	re.NoError(err)
+	re.Len(events, 10)
+}
+
+func TestBoundedEvents(t *testing.T) {
+	re := require.New(t)
+	base := kv.NewMemoryKV()
+	storage := endpoint.NewStorageEndpoint(base, nil)
+	start := time.Unix(1000, 0)
+	for i := 0; i < 300; i++ {
+		re.NoError(storage.SaveDegradedPlacementEvent(&endpoint.DegradedPlacementEvent{
+			Time:   start.Add(time.Duration(i) * time.Second),
+			Action: endpoint.DegradedPlacementEnter,
+		}))
+	}
+	// overwriting an existing event doesn't remove another one.
+	re.NoError(storage.SaveDegradedPlacementEvent(&endpoint.DegradedPlacementEvent{
+		Time:   start.Add(299 * time.Second),
+		Action: endpoint.DegradedPlacementExit,
+	}))
+	events, err := storage.LoadDegradedPlacementEvents()
+	re.NoError(err)
+	re.Len(events, 256)
+	re.Equal(start.Add(44*time.Second).UnixNano(), events[0].Time.UnixNano())
+	re.Equal(endpoint.DegradedPlacementExit, events[255].Action)
+
+	// a new endpoint counts the existing events on its first write.
+	storage = endpoint.NewStorageEndpoint(base, nil)
+	re.NoError(storage.SaveDegradedPlacementEvent(&endpoint.DegradedPlacementEvent{
+		Time:   start.Add(300 * time.Second),
+		Action: endpoint.DegradedPlacementEnter,
+	}))
+	events, err = storage.LoadDegradedPlacementEvents()
+	re.NoError(err)
+	re.Len(events, 256)
+	re.Equal(start.Add(45*time.Second).UnixNano(), events[0].Time.UnixNano())
+
+	// the cached count is stale once another endpoint writes events, until it's reset.
+	other := endpoint.NewStorageEndpoint(base, nil)
+	re.NoError(other.SaveDegradedPlacementEvent(&endpoint.DegradedPlacementEvent{
+		Time:   start.Add(301 * time.Second),
+		Action: endpoint.DegradedPlacementEnter,
+	}))
+	storage.ResetBoundedEventCounts()
+	re.NoError(storage.SaveDegradedPlacementEvent(&endpoint.DegradedPlacementEvent{
+		Time:   start.Add(302 * time.Second),
+		Action: endpoint.DegradedPlacementEnter,
+	}))
+	events, err = storage.LoadDegradedPlacementEvents()
+	re.NoError(err)
+	re.Len(events, 256)
+	re.Equal(start.Add(47*time.Second).UnixNano(), events[0].Time.UnixNano())
+}
+
 func TestLoadGCSafePoint(t *testing.T) {
 	re := require.New(t)
 	storage := NewStorageWithMemoryBackend()
@@ -16,6 +16,7 @@ package syncer
 
 import (
 	"context"
+	"math"
 	"time"
 
 	"github.com/docker/go-units"
@@ -85,6 +86,17 @@ func (s *RegionSyncer) IsRunning() bool {
 	return s.streamingRunning.Load()
 }
 
+// GetStaleness returns how long the region cache hasn't been synced with the
+// leader. The leader sends a keepalive every syncerKeepAliveInterval if there
+// is no region change, so the staleness of a healthy follower is less than it.
+func (s *RegionSyncer) GetStaleness() time.Duration {
+	lastSyncTime := s.lastSyncTime.Load()
+	if !s.IsRunning() || lastSyncTime == 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Since(time.Unix(0, lastSyncTime))
+}
+
 // StartSyncWithLeader starts to sync with leader.
 func (s *RegionSyncer) StartSyncWithLeader(addr string) {
 	s.wg.Add(1)
@@ -232,6 +244,7 @@ func (s *RegionSyncer) StartSyncWithLeader(addr string) {
 				}
 				// mark the client as running status when it finished the first history region sync.
 				s.streamingRunning.Store(true)
+				s.lastSyncTime.Store(time.Now().UnixNano())
 			}
 		}
 	}()
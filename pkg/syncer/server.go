@@ -86,6 +86,9 @@ type RegionSyncer struct {
 	tlsConfig *grpcutil.TLSConfig
 	// status when as client
 	streamingRunning atomic.Bool
+	// lastSyncTime is the unix nano time when the client receives the last
+	// response from the leader.
+	lastSyncTime atomic.Int64
 }
 
 // NewRegionSyncer returns a region syncer that ensures final consistency through the heartbeat,
@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -176,17 +177,26 @@ type AllocatorManager struct {
 	// member is for election use
 	member ElectionMember
 	// TSO config
-	rootPath               string
-	storage                endpoint.TSOStorage
-	enableLocalTSO         bool
-	saveInterval           time.Duration
-	updatePhysicalInterval time.Duration
+	rootPath       string
+	storage        endpoint.TSOStorage
+	enableLocalTSO bool
+	// saveInterval and updatePhysicalInterval are in nanoseconds, they may be
+	// overridden by the TSO settings of the keyspace group at runtime.
+	saveInterval           atomic.Int64
+	updatePhysicalInterval atomic.Int64
 	// leaderLease defines the time within which a TSO primary/leader must update its TTL
 	// in etcd, otherwise etcd will expire the leader key and other servers can campaign
 	// the primary/leader again. Etcd only supports seconds TTL, so here is second too.
 	leaderLease    int64
 	maxResetTSGap  func() time.Duration
+	maxClockDrift  func() time.Duration
 	securityConfig *grpcutil.TLSConfig
+	// clockOffset is the offset in nanoseconds added to the system time of
+	// the TSO allocators, it's only used to simulate the clock skew in tests.
+	clockOffset atomic.Int64
+	// clockDriftDetector estimates the drift of the local clock, it's shared
+	// by the allocator managers of the same server.
+	clockDriftDetector atomic.Pointer[ClockDriftDetector]
 	// for gRPC use
 	localAllocatorConn struct {
 		syncutil.RWMutex
@@ -206,19 +216,19 @@ func NewAllocatorManager(
 ) *AllocatorManager {
 	ctx, cancel := context.WithCancel(ctx)
 	am := &AllocatorManager{
-		ctx:                    ctx,
-		cancel:                 cancel,
-		kgID:                   keyspaceGroupID,
-		member:                 member,
-		rootPath:               rootPath,
-		storage:                storage,
-		enableLocalTSO:         cfg.IsLocalTSOEnabled(),
-		saveInterval:           cfg.GetTSOSaveInterval(),
-		updatePhysicalInterval: cfg.GetTSOUpdatePhysicalInterval(),
-		leaderLease:            cfg.GetLeaderLease(),
-		maxResetTSGap:          cfg.GetMaxResetTSGap,
-		securityConfig:         cfg.GetTLSConfig(),
-	}
+		ctx:            ctx,
+		cancel:         cancel,
+		kgID:           keyspaceGroupID,
+		member:         member,
+		rootPath:       rootPath,
+		storage:        storage,
+		enableLocalTSO: cfg.IsLocalTSOEnabled(),
+		leaderLease:    cfg.GetLeaderLease(),
+		maxResetTSGap:  cfg.GetMaxResetTSGap,
+		maxClockDrift:  cfg.GetMaxClockDrift,
+		securityConfig: cfg.GetTLSConfig(),
+	}
+	am.setTSOIntervals(cfg.GetTSOUpdatePhysicalInterval(), cfg.GetTSOSaveInterval())
 	am.mu.allocatorGroups = make(map[string]*allocatorGroup)
 	am.mu.clusterDCLocations = make(map[string]*DCLocationInfo)
 	am.localAllocatorConn.clientConns = make(map[string]*grpc.ClientConn)
@@ -274,6 +284,21 @@ func (am *AllocatorManager) setUpLocalAllocator(parentCtx context.Context, dcLoc
 	go am.allocatorLeaderLoop(parentCtx, localTSOAllocator)
 }
 
+// setTSOIntervals sets the intervals of the TSO allocators, which take effect
+// from the next update of the timestamp.
+func (am *AllocatorManager) setTSOIntervals(updatePhysicalInterval, saveInterval time.Duration) {
+	am.updatePhysicalInterval.Store(int64(updatePhysicalInterval))
+	am.saveInterval.Store(int64(saveInterval))
+}
+
+func (am *AllocatorManager) getUpdatePhysicalInterval() time.Duration {
+	return time.Duration(am.updatePhysicalInterval.Load())
+}
+
+func (am *AllocatorManager) getSaveInterval() time.Duration {
+	return time.Duration(am.saveInterval.Load())
+}
+
 // getGroupID returns the keyspace group ID of the allocator manager.
 func (am *AllocatorManager) getGroupID() uint32 {
 	if am == nil {
@@ -713,7 +738,8 @@ func (am *AllocatorManager) AllocatorDaemon(ctx context.Context) {
 		patrolTicker = time.NewTicker(patrolStep)
 		defer patrolTicker.Stop()
 	}
-	tsTicker := time.NewTicker(am.updatePhysicalInterval)
+	updatePhysicalInterval := am.getUpdatePhysicalInterval()
+	tsTicker := time.NewTicker(updatePhysicalInterval)
 	failpoint.Inject("fastUpdatePhysicalInterval", func() {
 		tsTicker.Stop()
 		tsTicker = time.NewTicker(time.Millisecond)
@@ -730,6 +756,11 @@ func (am *AllocatorManager) AllocatorDaemon(ctx context.Context) {
 		case <-tsTicker.C:
 			// Update the initialized TSO Allocator to advance TSO.
 			am.allocatorUpdater()
+			// Follow the interval changed by the TSO settings of the keyspace group.
+			if interval := am.getUpdatePhysicalInterval(); interval != updatePhysicalInterval {
+				updatePhysicalInterval = interval
+				tsTicker.Reset(interval)
+			}
 		case <-checkerTicker.C:
 			// Check and maintain the cluster's meta info about dc-location distribution.
 			go am.ClusterDCLocationChecker()
@@ -1366,6 +1397,32 @@ func (am *AllocatorManager) nextLeaderKey(dcLocation string) string {
 	return path.Join(am.getAllocatorPath(dcLocation), "next-leader")
 }
 
+// SetClockOffset sets the offset added to the system time of the TSO allocators,
+// it's used to simulate the clock skew in tests.
+func (am *AllocatorManager) SetClockOffset(offset time.Duration) {
+	am.clockOffset.Store(int64(offset))
+}
+
+func (am *AllocatorManager) getClockOffset() time.Duration {
+	return time.Duration(am.clockOffset.Load())
+}
+
+// SetClockDriftDetector sets the detector used to check the drift of the local
+// clock before the TSO allocators follow it.
+func (am *AllocatorManager) SetClockDriftDetector(detector *ClockDriftDetector) {
+	am.clockDriftDetector.Store(detector)
+}
+
+// getClockDrift returns the drift of the local clock with the clock offset, it
+// returns 0 if the drift is unknown.
+func (am *AllocatorManager) getClockDrift() time.Duration {
+	drift, ok := am.clockDriftDetector.Load().GetDrift()
+	if !ok {
+		return 0
+	}
+	return drift + am.getClockOffset()
+}
+
 // EnableLocalTSO returns the value of AllocatorManager.enableLocalTSO.
 func (am *AllocatorManager) EnableLocalTSO() bool {
 	return am.enableLocalTSO
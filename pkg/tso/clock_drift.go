@@ -0,0 +1,192 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tso
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/utils/logutil"
+	"go.etcd.io/etcd/clientv3"
+	"go.uber.org/zap"
+)
+
+const (
+	clockDriftCheckInterval = 10 * time.Second
+	clockDriftProbeTimeout  = 3 * time.Second
+	// clockDriftExpiration is how long an estimate is trusted if none of the peers
+	// can be probed afterwards.
+	clockDriftExpiration = 3 * clockDriftCheckInterval
+	// dateHeaderPrecision is the precision of the Date header of the HTTP responses.
+	dateHeaderPrecision = time.Second
+)
+
+// ClockDriftDetector estimates the drift of the local clock against the etcd peers
+// by the Date headers of their HTTP responses. The Date header is in seconds, so the
+// drift within about one second can't be detected.
+type ClockDriftDetector struct {
+	client     *clientv3.Client
+	httpClient *http.Client
+	// localEndpoints are the client URLs of the local member, which are skipped
+	// since probing them measures nothing.
+	localEndpoints []string
+	// drift is the estimated drift in nanoseconds, it's positive if the local clock is ahead.
+	drift atomic.Int64
+	// checkedAt is the unix time in nanoseconds of the last estimate, 0 if never estimated.
+	checkedAt atomic.Int64
+}
+
+// NewClockDriftDetector creates a new ClockDriftDetector, the check loop is
+// started by StartCheckLoop.
+func NewClockDriftDetector(client *clientv3.Client, httpClient *http.Client, localEndpoints []string) *ClockDriftDetector {
+	return &ClockDriftDetector{
+		client:         client,
+		httpClient:     httpClient,
+		localEndpoints: localEndpoints,
+	}
+}
+
+// StartCheckLoop starts to check the drift periodically until the context is done.
+func (d *ClockDriftDetector) StartCheckLoop(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	go d.checkLoop(ctx, wg)
+}
+
+func (d *ClockDriftDetector) checkLoop(ctx context.Context, wg *sync.WaitGroup) {
+	defer logutil.LogPanic()
+	defer wg.Done()
+
+	ticker := time.NewTicker(clockDriftCheckInterval)
+	defer ticker.Stop()
+	for {
+		d.check(ctx)
+		select {
+		case <-ctx.Done():
+			log.Info("exit the clock drift check loop")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *ClockDriftDetector) check(ctx context.Context) {
+	endpoints := excludeEndpoints(d.client.Endpoints(), d.localEndpoints)
+	drifts := make([]time.Duration, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		drift, err := d.probe(ctx, endpoint)
+		if err != nil {
+			log.Debug("failed to probe the clock of the peer", zap.String("endpoint", endpoint), zap.Error(err))
+			continue
+		}
+		drifts = append(drifts, drift)
+	}
+	// Keep the last estimate until it expires if none of the peers is reachable.
+	if len(drifts) == 0 {
+		return
+	}
+	drift := medianDrift(drifts)
+	d.drift.Store(int64(drift))
+	d.checkedAt.Store(time.Now().UnixNano())
+	tsoClockDriftGauge.Set(drift.Seconds())
+}
+
+// excludeEndpoints returns the endpoints not in the excluded ones, ignoring the trailing slashes.
+func excludeEndpoints(endpoints, excluded []string) []string {
+	peers := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !slices.ContainsFunc(excluded, func(e string) bool {
+			return strings.TrimSuffix(e, "/") == strings.TrimSuffix(endpoint, "/")
+		}) {
+			peers = append(peers, endpoint)
+		}
+	}
+	return peers
+}
+
+func (d *ClockDriftDetector) probe(ctx context.Context, endpoint string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, clockDriftProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/health", http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	resp, err := d.httpClient.Do(req)
+	end := time.Now()
+	if err != nil {
+		return 0, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	peer, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, err
+	}
+	return estimateClockDrift(start, end, peer), nil
+}
+
+// estimateClockDrift returns the lower bound of the drift of the local clock,
+// given the peer time is sampled between start and end of the local clock and
+// truncated to the precision of the Date header.
+func estimateClockDrift(start, end, peer time.Time) time.Duration {
+	if ahead := start.Sub(peer.Add(dateHeaderPrecision)); ahead > 0 {
+		return ahead
+	}
+	if behind := end.Sub(peer); behind < 0 {
+		return behind
+	}
+	return 0
+}
+
+// medianDrift returns the median of the drifts, so that the local clock is
+// only considered drifting when it disagrees with the majority of the peers.
+// With an even number of drifts, the one closer to zero of the middle two is used.
+func medianDrift(drifts []time.Duration) time.Duration {
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i] < drifts[j] })
+	mid := len(drifts) / 2
+	if len(drifts)%2 == 1 {
+		return drifts[mid]
+	}
+	lower, upper := drifts[mid-1], drifts[mid]
+	if upper <= 0 {
+		return upper
+	}
+	if lower >= 0 {
+		return lower
+	}
+	return 0
+}
+
+// GetDrift returns the estimated drift of the local clock, which is positive if
+// the local clock is ahead. It returns false if the drift hasn't been estimated
+// or the estimate has expired.
+func (d *ClockDriftDetector) GetDrift() (time.Duration, bool) {
+	if d == nil {
+		return 0, false
+	}
+	checkedAt := d.checkedAt.Load()
+	if checkedAt == 0 || time.Since(time.Unix(0, checkedAt)) > clockDriftExpiration {
+		return 0, false
+	}
+	return time.Duration(d.drift.Load()), true
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tso
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/storage/kv"
+)
+
+func TestEstimateClockDrift(t *testing.T) {
+	re := require.New(t)
+	start := time.Unix(100, 0)
+	end := start.Add(200 * time.Millisecond)
+	testCases := []struct {
+		peer     time.Time
+		expected time.Duration
+	}{
+		// the peer time is within the precision of the Date header.
+		{time.Unix(100, 0), 0},
+		{time.Unix(99, 0), 0},
+		// the local clock is ahead.
+		{time.Unix(97, 0), 2 * time.Second},
+		// the local clock is behind.
+		{time.Unix(103, 0), -3*time.Second + 200*time.Millisecond},
+	}
+	for _, tc := range testCases {
+		re.Equal(tc.expected, estimateClockDrift(start, end, tc.peer), tc.peer)
+	}
+}
+
+func TestMedianDrift(t *testing.T) {
+	re := require.New(t)
+	re.Equal(time.Second, medianDrift([]time.Duration{5 * time.Second, 0, time.Second}))
+	re.Equal(time.Duration(0), medianDrift([]time.Duration{5 * time.Second, 0, 0}))
+	// the local clock is only considered drifting when all the peers agree.
+	re.Equal(time.Duration(0), medianDrift([]time.Duration{5 * time.Second, 0}))
+	re.Equal(3*time.Second, medianDrift([]time.Duration{5 * time.Second, 3 * time.Second}))
+	re.Equal(-3*time.Second, medianDrift([]time.Duration{-5 * time.Second, -3 * time.Second}))
+	re.Equal(time.Duration(0), medianDrift([]time.Duration{-5 * time.Second, 3 * time.Second}))
+}
+
+func TestClockDriftFailsUpdatingTimestamp(t *testing.T) {
+	re := require.New(t)
+	var drift time.Duration
+	oracle := &timestampOracle{
+		storage:                endpoint.NewStorageEndpoint(kv.NewMemoryKV(), nil),
+		saveInterval:           func() time.Duration { return 3 * time.Second },
+		updatePhysicalInterval: func() time.Duration { return 50 * time.Millisecond },
+		maxResetTSGap:          func() time.Duration { return time.Hour },
+		clockDrift:             func() time.Duration { return drift },
+		maxClockDrift:          func() time.Duration { return 5 * time.Second },
+		tsoMux:                 &tsoObject{},
+		metrics:                newTSOMetrics("0", GlobalDCLocation),
+	}
+	re.NoError(oracle.SyncTimestamp())
+
+	drift = 10 * time.Second
+	physical, _ := oracle.getTSO()
+	time.Sleep(10 * time.Millisecond)
+	// the error makes the primary resign.
+	re.ErrorIs(oracle.UpdateTimestamp(), errs.ErrClockDrift)
+	next, _ := oracle.getTSO()
+	re.Equal(physical, next)
+	// the member with the drifting clock can't sync the timestamp to be the primary.
+	oracle.tsoMux.physical = time.Time{}
+	err := oracle.SyncTimestamp()
+	re.ErrorIs(err, errs.ErrClockDrift)
+
+	drift = -time.Second
+	re.NoError(oracle.SyncTimestamp())
+	physical, _ = oracle.getTSO()
+	time.Sleep(10 * time.Millisecond)
+	re.NoError(oracle.UpdateTimestamp())
+	next, _ = oracle.getTSO()
+	re.True(next.After(physical))
+}
+
+func TestExcludeEndpoints(t *testing.T) {
+	re := require.New(t)
+	endpoints := []string{"http://127.0.0.1:2379", "http://127.0.0.1:2479/", "http://127.0.0.1:2579"}
+	re.Equal([]string{"http://127.0.0.1:2479/", "http://127.0.0.1:2579"},
+		excludeEndpoints(endpoints, []string{"http://127.0.0.1:2379/"}))
+	re.Equal([]string{"http://127.0.0.1:2379", "http://127.0.0.1:2579"},
+		excludeEndpoints(endpoints, []string{"http://127.0.0.1:2479"}))
+	re.Empty(excludeEndpoints(endpoints[:1], endpoints[:1]))
+}
+
+func TestClockDriftExpiration(t *testing.T) {
+	re := require.New(t)
+	d := NewClockDriftDetector(nil, nil, nil)
+	_, ok := d.GetDrift()
+	re.False(ok)
+
+	d.drift.Store(int64(time.Second))
+	d.checkedAt.Store(time.Now().UnixNano())
+	drift, ok := d.GetDrift()
+	re.True(ok)
+	re.Equal(time.Second, drift)
+
+	d.checkedAt.Store(time.Now().Add(-clockDriftExpiration - time.Second).UnixNano())
+	_, ok = d.GetDrift()
+	re.False(ok)
+}
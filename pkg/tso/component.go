@@ -0,0 +1,123 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tso
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+const (
+	// UnknownComponent is the component of the TSO requests without the component metadata.
+	UnknownComponent = "unknown"
+	// OtherComponent is the component of the TSO requests whose components are
+	// beyond the limit, it prevents the untrusted metadata from bloating the counters.
+	OtherComponent = "other"
+	// maxComponentCount is the max number of the components to be counted separately.
+	maxComponentCount = 64
+)
+
+// ComponentAllocation is the TSO allocation count of a calling component.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ComponentAllocation struct {
+	Component string `json:"component"`
+	// Requests is the number of the TSO requests, a client batches the TSO
+	// requests of the callers into one.
+	Requests uint64 `json:"requests"`
+	// Timestamps is the number of the allocated timestamps.
+	Timestamps uint64 `json:"timestamps"`
+}
+
+type componentCounter struct {
+	requests   atomic.Uint64
+	timestamps atomic.Uint64
+
+	requestCounter   prometheus.Counter
+	timestampCounter prometheus.Counter
+}
+
+// ComponentAllocations counts the TSO allocations by the calling components,
+// which are reported by the clients in the gRPC metadata, e.g. tidb, cdc or br.
+type ComponentAllocations struct {
+	mu       syncutil.RWMutex
+	counters map[string]*componentCounter
+}
+
+// NewComponentAllocations creates a new ComponentAllocations.
+func NewComponentAllocations() *ComponentAllocations {
+	return &ComponentAllocations{counters: make(map[string]*componentCounter)}
+}
+
+// Observe records a TSO request of the component which allocates the count of timestamps.
+func (c *ComponentAllocations) Observe(component string, count uint32) {
+	counter := c.getCounter(component)
+	counter.requests.Add(1)
+	counter.timestamps.Add(uint64(count))
+	counter.requestCounter.Inc()
+	counter.timestampCounter.Add(float64(count))
+}
+
+func (c *ComponentAllocations) getCounter(component string) *componentCounter {
+	if component == "" {
+		component = UnknownComponent
+	}
+	c.mu.RLock()
+	counter, ok := c.counters[component]
+	c.mu.RUnlock()
+	if ok {
+		return counter
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if counter, ok := c.counters[component]; ok {
+		return counter
+	}
+	// Keep one slot for the other component.
+	if len(c.counters) >= maxComponentCount-1 && component != OtherComponent {
+		if counter, ok := c.counters[OtherComponent]; ok {
+			return counter
+		}
+		component = OtherComponent
+	}
+	counter = &componentCounter{
+		requestCounter:   tsoComponentAllocationCounter.WithLabelValues(component, "requests"),
+		timestampCounter: tsoComponentAllocationCounter.WithLabelValues(component, "timestamps"),
+	}
+	c.counters[component] = counter
+	return counter
+}
+
+// GetAll returns the TSO allocation counts of all the components since the
+// server started, sorted by the component.
+func (c *ComponentAllocations) GetAll() []*ComponentAllocation {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	allocations := make([]*ComponentAllocation, 0, len(c.counters))
+	for component, counter := range c.counters {
+		allocations = append(allocations, &ComponentAllocation{
+			Component:  component,
+			Requests:   counter.requests.Load(),
+			Timestamps: counter.timestamps.Load(),
+		})
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].Component < allocations[j].Component
+	})
+	return allocations
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tso
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentAllocations(t *testing.T) {
+	re := require.New(t)
+	allocations := NewComponentAllocations()
+	allocations.Observe("tidb", 10)
+	allocations.Observe("tidb", 5)
+	allocations.Observe("cdc", 1)
+	allocations.Observe("", 2)
+	re.Equal([]*ComponentAllocation{
+		{Component: "cdc", Requests: 1, Timestamps: 1},
+		{Component: "tidb", Requests: 2, Timestamps: 15},
+		{Component: UnknownComponent, Requests: 1, Timestamps: 2},
+	}, allocations.GetAll())
+
+	// the components beyond the limit are counted as the other one.
+	for i := 0; i < maxComponentCount; i++ {
+		allocations.Observe(fmt.Sprintf("component-%d", i), 1)
+	}
+	all := allocations.GetAll()
+	re.Len(all, maxComponentCount)
+	for _, allocation := range all {
+		if allocation.Component == OtherComponent {
+			re.Equal(uint64(4), allocation.Requests)
+		}
+	}
+	allocations.Observe("tidb", 1)
+	re.Len(allocations.GetAll(), maxComponentCount)
+}
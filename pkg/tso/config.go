@@ -46,6 +46,8 @@ type Config interface {
 	GetTSOSaveInterval() time.Duration
 	// GetMaxResetTSGap returns the MaxResetTSGap.
 	GetMaxResetTSGap() time.Duration
+	// GetMaxClockDrift returns the max drift of the local clock against the peers.
+	GetMaxClockDrift() time.Duration
 	// GetTLSConfig returns the TLS config.
 	GetTLSConfig() *grpcutil.TLSConfig
 }
@@ -118,9 +118,12 @@ func newGlobalTimestampOracle(am *AllocatorManager) *timestampOracle {
 		keyspaceGroupID:        am.kgID,
 		tsPath:                 endpoint.KeyspaceGroupGlobalTSPath(am.kgID),
 		storage:                am.storage,
-		saveInterval:           am.saveInterval,
-		updatePhysicalInterval: am.updatePhysicalInterval,
+		saveInterval:           am.getSaveInterval,
+		updatePhysicalInterval: am.getUpdatePhysicalInterval,
 		maxResetTSGap:          am.maxResetTSGap,
+		clockOffset:            am.getClockOffset,
+		clockDrift:             am.getClockDrift,
+		maxClockDrift:          am.maxClockDrift,
 		dcLocation:             GlobalDCLocation,
 		tsoMux:                 &tsoObject{},
 		metrics:                newTSOMetrics(am.getGroupIDStr(), GlobalDCLocation),
@@ -164,6 +167,11 @@ func (gta *GlobalTSOAllocator) GetTimestampPath() string {
 	return gta.timestampOracle.GetTimestampPath()
 }
 
+// GetLastSavedTime returns the time window saved in etcd by the allocator.
+func (gta *GlobalTSOAllocator) GetLastSavedTime() time.Time {
+	return gta.timestampOracle.getLastSavedTime()
+}
+
 func (gta *GlobalTSOAllocator) estimateMaxTS(ctx context.Context, count uint32, suffixBits int) (*pdpb.Timestamp, bool, error) {
 	physical, logical, lastUpdateTime := gta.timestampOracle.generateTSO(ctx, int64(count), 0)
 	if physical == 0 {
@@ -252,7 +260,7 @@ func (gta *GlobalTSOAllocator) GenerateTSO(ctx context.Context, count uint32) (p
 			continue
 		}
 		if shouldRetry {
-			time.Sleep(gta.timestampOracle.updatePhysicalInterval)
+			time.Sleep(gta.timestampOracle.updatePhysicalInterval())
 			continue
 		}
 	SETTING_PHASE:
@@ -347,6 +355,8 @@ const (
 	rpcTimeout  = 3 * time.Second
 	// TODO: maybe make syncMaxRetryCount configurable
 	syncMaxRetryCount = 2
+	// campaignCheckRetryInterval is the interval to retry the campaign after the pre-check fails.
+	campaignCheckRetryInterval = 100 * time.Millisecond
 )
 
 type syncResp struct {
@@ -574,9 +584,15 @@ func (gta *GlobalTSOAllocator) campaignLeader() {
 				logutil.CondUint32("keyspace-group-id", gta.getGroupID(), gta.getGroupID() > 0),
 				zap.String("campaign-tso-primary-name", gta.member.Name()))
 		} else if errors.Is(err, errs.ErrCheckCampaign) {
-			log.Info("campaign tso primary meets error due to pre-check campaign failed, the tso keyspace group may be in split",
+			log.Info("campaign tso primary meets error due to pre-check campaign failed, the tso keyspace group may be in split or "+
+				"there is a member with higher priority",
 				logutil.CondUint32("keyspace-group-id", gta.getGroupID(), gta.getGroupID() > 0),
 				zap.String("campaign-tso-primary-name", gta.member.Name()))
+			// Wait a while to avoid checking too frequently.
+			select {
+			case <-gta.ctx.Done():
+			case <-time.After(campaignCheckRetryInterval):
+			}
 		} else {
 			log.Error("campaign tso primary meets error due to etcd error",
 				logutil.CondUint32("keyspace-group-id", gta.getGroupID(), gta.getGroupID() > 0),
@@ -23,6 +23,7 @@ import (
 	"regexp"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	perrors "github.com/pingcap/errors"
@@ -58,7 +59,13 @@ const (
 	// of the primaries on this TSO server/pod have changed. A goroutine will periodically check
 	// do this check and re-distribute the primaries if necessary.
 	defaultPrimaryPriorityCheckInterval = 10 * time.Second
-	groupPatrolInterval                 = time.Minute
+	// defaultMaxPriorityCampaignDeferral is the default max duration for which a member defers
+	// the primary campaign because of the alive members with higher priority. It's bounded in
+	// case the members with higher priority fail to campaign.
+	defaultMaxPriorityCampaignDeferral = 3 * time.Second
+	groupPatrolInterval                = time.Minute
+	// tsoNodesResyncInterval is the interval to relist the registered tso servers from etcd.
+	tsoNodesResyncInterval = time.Minute
 )
 
 type state struct {
@@ -283,7 +290,9 @@ func (s *state) getNextPrimaryToReset(
 	for j := 0; j < groupSize; groupID, j = (groupID+1)%groupSize, j+1 {
 		am := s.ams[groupID]
 		kg := s.kgs[groupID]
-		if am != nil && kg != nil && am.GetMember().IsLeader() {
+		// Don't transfer the primary of the split target until the split is finished,
+		// i.e. its TSO has been fenced above the split source by the current primary.
+		if am != nil && kg != nil && !kg.IsSplitTarget() && am.GetMember().IsLeader() {
 			maxPriority := math.MinInt32
 			localPriority := math.MaxInt32
 			for _, member := range kg.Members {
@@ -378,14 +387,31 @@ type KeyspaceGroupManager struct {
 	mergeCheckerCancelMap sync.Map // GroupID -> context.CancelFunc
 
 	primaryPriorityCheckInterval time.Duration
+	maxPriorityCampaignDeferral  time.Duration
+	// groupMembers stores the members of the keyspace groups, it's used by the campaign
+	// checkers to avoid acquiring the state lock, which may be held while waiting for the
+	// primary election loops to exit.
+	groupMembers sync.Map // GroupID -> []endpoint.KeyspaceGroupMember
+	// handingOff indicates whether this server is handing off its primaries, the members
+	// won't campaign the primaries of the keyspace groups while it's set.
+	handingOff atomic.Bool
+	// draining indicates whether this server is drained, it's set after the primaries are
+	// handed off and the server stops accepting the TSO requests then.
+	draining atomic.Bool
+	// keyspaceRequests counts the TSO requests handled by this server for each keyspace,
+	// which is used by PD to estimate the TSO QPS of the keyspace groups.
+	keyspaceRequests sync.Map // KeyspaceID -> *atomic.Uint64
 
 	// tsoNodes is the registered tso servers.
 	tsoNodes sync.Map // store as map[string]struct{}
-	// serviceRegistryMap stores the mapping from the service registry key to the service address.
-	// Note: it is only used in tsoNodesWatcher.
-	serviceRegistryMap map[string]string
-	// tsoNodesWatcher is the watcher for the registered tso servers.
-	tsoNodesWatcher *etcdutil.LoopWatcher
+	// tsoNodesInformer is the informer for the registered tso servers.
+	tsoNodesInformer *etcdutil.Informer[*discovery.ServiceRegistryEntry]
+
+	// clockOffset is the offset added to the system time of the allocator managers,
+	// it's only used to simulate the clock skew in tests. It's protected by the state lock.
+	clockOffset time.Duration
+	// clockDriftDetector estimates the drift of the local clock for all the allocator managers.
+	clockDriftDetector *ClockDriftDetector
 
 	// pre-initialized metrics
 	metrics *keyspaceGroupMetrics
@@ -421,9 +447,9 @@ func NewKeyspaceGroupManager(
 		legacySvcRootPath:            legacySvcRootPath,
 		tsoSvcRootPath:               tsoSvcRootPath,
 		primaryPriorityCheckInterval: defaultPrimaryPriorityCheckInterval,
+		maxPriorityCampaignDeferral:  defaultMaxPriorityCampaignDeferral,
 		cfg:                          cfg,
 		groupUpdateRetryList:         make(map[uint32]*endpoint.KeyspaceGroup),
-		serviceRegistryMap:           make(map[string]string),
 		metrics:                      newKeyspaceGroupMetrics(),
 	}
 	kgm.legacySvcStorage = endpoint.NewStorageEndpoint(
@@ -431,6 +457,10 @@ func NewKeyspaceGroupManager(
 	kgm.tsoSvcStorage = endpoint.NewStorageEndpoint(
 		kv.NewEtcdKVBase(kgm.etcdClient, kgm.tsoSvcRootPath), nil)
 	kgm.compiledKGMembershipIDRegexp = endpoint.GetCompiledKeyspaceGroupIDRegexp()
+	if httpClient != nil {
+		// The TSO server is not an etcd member, so all the endpoints are peers.
+		kgm.clockDriftDetector = NewClockDriftDetector(etcdClient, httpClient, nil)
+	}
 	kgm.state.initialize()
 	return kgm
 }
@@ -452,6 +482,9 @@ func (kgm *KeyspaceGroupManager) Initialize() error {
 	go kgm.primaryPriorityCheckLoop()
 	go kgm.groupSplitPatroller()
 	go kgm.deletedGroupCleaner()
+	if kgm.clockDriftDetector != nil {
+		kgm.clockDriftDetector.StartCheckLoop(kgm.ctx, &kgm.wg)
+	}
 
 	return nil
 }
@@ -482,41 +515,34 @@ func (kgm *KeyspaceGroupManager) GetServiceConfig() ServiceConfig {
 // Key: /ms/{cluster_id}/tso/registry/{tsoServerAddress}
 // Value: discover.ServiceRegistryEntry
 func (kgm *KeyspaceGroupManager) InitializeTSOServerWatchLoop() error {
-	putFn := func(kv *mvccpb.KeyValue) error {
-		s := &discovery.ServiceRegistryEntry{}
-		if err := json.Unmarshal(kv.Value, s); err != nil {
-			log.Warn("failed to unmarshal service registry entry",
-				zap.String("event-kv-key", string(kv.Key)), zap.Error(err))
-			return err
-		}
-		kgm.tsoNodes.Store(s.ServiceAddr, struct{}{})
-		kgm.serviceRegistryMap[string(kv.Key)] = s.ServiceAddr
-		return nil
-	}
-	deleteFn := func(kv *mvccpb.KeyValue) error {
-		key := string(kv.Key)
-		if serviceAddr, ok := kgm.serviceRegistryMap[key]; ok {
-			delete(kgm.serviceRegistryMap, key)
-			kgm.tsoNodes.Delete(serviceAddr)
-			return nil
-		}
-		return perrors.Errorf("failed to find the service address for key %s", key)
-	}
-
-	kgm.tsoNodesWatcher = etcdutil.NewLoopWatcher(
+	kgm.tsoNodesInformer = etcdutil.NewInformer(
 		kgm.ctx,
 		&kgm.wg,
 		kgm.etcdClient,
 		"tso-nodes-watcher",
 		kgm.tsoServiceKey,
-		func([]*clientv3.Event) error { return nil },
-		putFn,
-		deleteFn,
-		func([]*clientv3.Event) error { return nil },
-		true, /* withPrefix */
+		discovery.DecodeServiceRegistryEntry,
+		etcdutil.InformerHandler[*discovery.ServiceRegistryEntry]{
+			OnAdd: func(_ string, s *discovery.ServiceRegistryEntry) error {
+				kgm.tsoNodes.Store(s.ServiceAddr, struct{}{})
+				return nil
+			},
+			OnUpdate: func(_ string, old, s *discovery.ServiceRegistryEntry) error {
+				if old.ServiceAddr != s.ServiceAddr {
+					kgm.tsoNodes.Delete(old.ServiceAddr)
+				}
+				kgm.tsoNodes.Store(s.ServiceAddr, struct{}{})
+				return nil
+			},
+			OnDelete: func(_ string, s *discovery.ServiceRegistryEntry) error {
+				kgm.tsoNodes.Delete(s.ServiceAddr)
+				return nil
+			},
+		},
+		tsoNodesResyncInterval,
 	)
-	kgm.tsoNodesWatcher.StartWatchLoop()
-	if err := kgm.tsoNodesWatcher.WaitLoad(); err != nil {
+	kgm.tsoNodesInformer.StartWatchLoop()
+	if err := kgm.tsoNodesInformer.WaitLoad(); err != nil {
 		log.Error("failed to load the registered tso servers", errs.ZapError(err))
 		return err
 	}
@@ -623,11 +649,7 @@ func (kgm *KeyspaceGroupManager) primaryPriorityCheckLoop() {
 			// Every primaryPriorityCheckInterval, we only reset the primary of one keyspace group
 			member, kg, localPriority, nextGroupID := kgm.getNextPrimaryToReset(groupID, kgm.tsoServiceID.ServiceAddr)
 			if member != nil {
-				aliveTSONodes := make(map[string]struct{})
-				kgm.tsoNodes.Range(func(key, _ any) bool {
-					aliveTSONodes[typeutil.TrimScheme(key.(string))] = struct{}{}
-					return true
-				})
+				aliveTSONodes := kgm.getAliveTSONodes()
 				if len(aliveTSONodes) == 0 {
 					log.Warn("no alive tso node", zap.String("local-address", kgm.tsoServiceID.ServiceAddr))
 					continue
@@ -665,6 +687,184 @@ func (kgm *KeyspaceGroupManager) primaryPriorityCheckLoop() {
 	}
 }
 
+// Drain resigns all the primaries held by this server, waits for the other members to
+// take them over, and then marks the server as draining so that it stops accepting the
+// TSO requests. The members on this server won't campaign the primaries since then. The
+// keyspace groups without any other alive member are left to be served by nobody.
+// If the primaries are not handed off before the context is done, ErrTSODrainTimeout is
+// returned and the server is undrained, the members campaign the primaries again.
+func (kgm *KeyspaceGroupManager) Drain(ctx context.Context) error {
+	kgm.handingOff.Store(true)
+	localAddress := kgm.tsoServiceID.ServiceAddr
+	aliveTSONodes := kgm.getAliveTSONodes()
+	delete(aliveTSONodes, typeutil.TrimScheme(localAddress))
+
+	// Collect the primaries first and resign them outside of the critical section
+	// as resetting the primary may take some time.
+	primaries := make(map[uint32]ElectionMember)
+	handoffs := make(map[uint32]bool)
+	kgm.RLock()
+	for i, am := range kgm.ams {
+		kg := kgm.kgs[i]
+		if am == nil || kg == nil || !am.GetMember().IsLeader() {
+			continue
+		}
+		primaries[kg.ID] = am.GetMember()
+		handoffs[kg.ID] = slice.AnyOf(kg.Members, func(i int) bool {
+			_, ok := aliveTSONodes[typeutil.TrimScheme(kg.Members[i].Address)]
+			return ok
+		})
+	}
+	kgm.RUnlock()
+
+	resigned := make(map[uint32]ElectionMember)
+	for groupID, member := range primaries {
+		member.ResetLeader()
+		log.Info("resign the primary for draining",
+			zap.String("local-address", localAddress),
+			zap.Uint32("keyspace-group-id", groupID),
+			zap.Bool("handoff", handoffs[groupID]))
+		if handoffs[groupID] {
+			resigned[groupID] = member
+		}
+	}
+
+	ticker := time.NewTicker(campaignCheckRetryInterval)
+	defer ticker.Stop()
+	for {
+		for groupID, member := range resigned {
+			if member.IsLeaderElected() && member.GetLeaderID() != member.ID() {
+				delete(resigned, groupID)
+			}
+		}
+		if len(resigned) == 0 {
+			log.Info("all the primaries are handed off", zap.String("local-address", localAddress))
+			kgm.draining.Store(true)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			kgm.Undrain()
+			groupIDs := make([]uint32, 0, len(resigned))
+			for groupID := range resigned {
+				groupIDs = append(groupIDs, groupID)
+			}
+			sort.Slice(groupIDs, func(i, j int) bool { return groupIDs[i] < groupIDs[j] })
+			return errs.ErrTSODrainTimeout.FastGenByArgs(groupIDs)
+		case <-ticker.C:
+		}
+	}
+}
+
+// ResignPrimaries resigns all the primaries held by this server without draining it,
+// so the members of each keyspace group, including this server, campaign again. It
+// returns the IDs of the resigned keyspace groups.
+func (kgm *KeyspaceGroupManager) ResignPrimaries() []uint32 {
+	primaries := make(map[uint32]ElectionMember)
+	kgm.RLock()
+	for i, am := range kgm.ams {
+		kg := kgm.kgs[i]
+		if am == nil || kg == nil || !am.GetMember().IsLeader() {
+			continue
+		}
+		primaries[kg.ID] = am.GetMember()
+	}
+	kgm.RUnlock()
+
+	groupIDs := make([]uint32, 0, len(primaries))
+	for groupID, member := range primaries {
+		member.ResetLeader()
+		log.Info("resign the primary",
+			zap.String("local-address", kgm.tsoServiceID.ServiceAddr),
+			zap.Uint32("keyspace-group-id", groupID))
+		groupIDs = append(groupIDs, groupID)
+	}
+	sort.Slice(groupIDs, func(i, j int) bool { return groupIDs[i] < groupIDs[j] })
+	return groupIDs
+}
+
+// Undrain makes the server accept the TSO requests again and the members on it campaign
+// the primaries again.
+func (kgm *KeyspaceGroupManager) Undrain() {
+	kgm.draining.Store(false)
+	kgm.handingOff.Store(false)
+	log.Info("tso server is undrained", zap.String("local-address", kgm.tsoServiceID.ServiceAddr))
+}
+
+// IsDraining returns whether this server is drained and stops accepting the TSO requests.
+func (kgm *KeyspaceGroupManager) IsDraining() bool {
+	return kgm.draining.Load()
+}
+
+func (kgm *KeyspaceGroupManager) getAliveTSONodes() map[string]struct{} {
+	aliveTSONodes := make(map[string]struct{})
+	kgm.tsoNodes.Range(func(key, _ any) bool {
+		aliveTSONodes[typeutil.TrimScheme(key.(string))] = struct{}{}
+		return true
+	})
+	return aliveTSONodes
+}
+
+// newPriorityCampaignChecker returns the campaign checker which makes the member defer the
+// primary campaign if there is an alive member with higher priority in the keyspace group,
+// so that the election prefers the members with higher priority. The checker is called by the
+// primary election loop and every leadership check of the member, so it could run concurrently.
+func (kgm *KeyspaceGroupManager) newPriorityCampaignChecker(groupID uint32) func(*election.Leadership) bool {
+	var (
+		mu            syncutil.Mutex
+		deferredSince time.Time
+	)
+	return func(leadership *election.Leadership) bool {
+		if kgm.handingOff.Load() {
+			// Don't campaign while handing off, but keep the valid leadership of the
+			// primaries which are not resigned yet, so they keep serving until then.
+			return leadership.Check()
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !kgm.hasAliveHigherPriorityMember(groupID) {
+			deferredSince = time.Time{}
+			return true
+		}
+		if deferredSince.IsZero() {
+			deferredSince = time.Now()
+		}
+		if time.Since(deferredSince) >= kgm.maxPriorityCampaignDeferral {
+			log.Warn("campaign the primary as the members with higher priority don't campaign in time",
+				zap.String("local-address", kgm.tsoServiceID.ServiceAddr),
+				zap.Uint32("keyspace-group-id", groupID),
+				zap.Duration("deferral", time.Since(deferredSince)))
+			deferredSince = time.Time{}
+			return true
+		}
+		return false
+	}
+}
+
+func (kgm *KeyspaceGroupManager) hasAliveHigherPriorityMember(groupID uint32) bool {
+	value, ok := kgm.groupMembers.Load(groupID)
+	if !ok {
+		return false
+	}
+	members := value.([]endpoint.KeyspaceGroupMember)
+	localPriority := math.MaxInt32
+	for _, member := range members {
+		if member.IsAddressEquivalent(kgm.tsoServiceID.ServiceAddr) {
+			localPriority = member.Priority
+		}
+	}
+	aliveTSONodes := kgm.getAliveTSONodes()
+	for _, member := range members {
+		if member.Priority <= localPriority {
+			continue
+		}
+		if _, ok := aliveTSONodes[typeutil.TrimScheme(member.Address)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (kgm *KeyspaceGroupManager) isAssignedToMe(group *endpoint.KeyspaceGroup) bool {
 	return slice.AnyOf(group.Members, func(i int) bool {
 		return group.Members[i].IsAddressEquivalent(kgm.tsoServiceID.ServiceAddr)
@@ -717,6 +917,7 @@ func (kgm *KeyspaceGroupManager) updateKeyspaceGroup(group *endpoint.KeyspaceGro
 	// If this host is already assigned a replica of this keyspace group, i.e., the election member
 	// is already initialized, just update the meta.
 	if oldAM != nil {
+		oldAM.setTSOIntervals(kgm.getTSOIntervals(group))
 		kgm.updateKeyspaceGroupMembership(oldGroup, group, true)
 		return
 	}
@@ -754,6 +955,8 @@ func (kgm *KeyspaceGroupManager) updateKeyspaceGroup(group *endpoint.KeyspaceGro
 		participant.SetCampaignChecker(func(*election.Leadership) bool {
 			return splitSourceAM.GetMember().IsLeader()
 		})
+	} else {
+		participant.SetCampaignChecker(kgm.newPriorityCampaignChecker(group.ID))
 	}
 	// Only the default keyspace group uses the legacy service root path for LoadTimestamp/SyncTimestamp.
 	var (
@@ -769,6 +972,7 @@ func (kgm *KeyspaceGroupManager) updateKeyspaceGroup(group *endpoint.KeyspaceGro
 	}
 	// Initialize all kinds of maps.
 	am := NewAllocatorManager(kgm.ctx, group.ID, participant, tsRootPath, storage, kgm.cfg, true)
+	am.setTSOIntervals(kgm.getTSOIntervals(group))
 	log.Info("created allocator manager",
 		zap.Uint32("keyspace-group-id", group.ID),
 		zap.String("timestamp-path", am.GetTimestampPath("")))
@@ -779,6 +983,9 @@ func (kgm *KeyspaceGroupManager) updateKeyspaceGroup(group *endpoint.KeyspaceGro
 		kgm.keyspaceLookupTable[kid] = group.ID
 	}
 	kgm.kgs[group.ID] = group
+	kgm.groupMembers.Store(group.ID, group.Members)
+	am.SetClockOffset(kgm.clockOffset)
+	am.SetClockDriftDetector(kgm.clockDriftDetector)
 	kgm.ams[group.ID] = am
 	// If the group is the split target, add it to the splitting group map.
 	if group.IsSplitTarget() {
@@ -788,6 +995,26 @@ func (kgm *KeyspaceGroupManager) updateKeyspaceGroup(group *endpoint.KeyspaceGro
 	kgm.Unlock()
 }
 
+// getTSOIntervals returns the TSO intervals of the keyspace group, the ones of
+// the server are used if the keyspace group doesn't override them.
+func (kgm *KeyspaceGroupManager) getTSOIntervals(
+	group *endpoint.KeyspaceGroup,
+) (updatePhysicalInterval, saveInterval time.Duration) {
+	updatePhysicalInterval, saveInterval = kgm.cfg.GetTSOUpdatePhysicalInterval(), kgm.cfg.GetTSOSaveInterval()
+	if err := endpoint.ValidateTSOConfig(group.TSOConfig); err != nil {
+		log.Warn("the tso config of the keyspace group is invalid, use the server config instead",
+			zap.Uint32("keyspace-group-id", group.ID), errs.ZapError(err))
+		return
+	}
+	if interval, _ := group.GetTSODuration(endpoint.TSOUpdatePhysicalIntervalKey); interval > 0 {
+		updatePhysicalInterval = interval
+	}
+	if interval, _ := group.GetTSODuration(endpoint.TSOSaveIntervalKey); interval > 0 {
+		saveInterval = interval
+	}
+	return
+}
+
 // validateSplit checks whether the meta info of split keyspace group
 // to ensure that the split process could be continued.
 func validateSplit(
@@ -913,7 +1140,7 @@ func (kgm *KeyspaceGroupManager) updateKeyspaceGroupMembership(
 	if oldGroup != nil {
 		// SplitTarget -> !Splitting
 		if oldGroup.IsSplitTarget() && !newGroup.IsSplitting() {
-			kgm.ams[groupID].GetMember().(*member.Participant).SetCampaignChecker(nil)
+			kgm.ams[groupID].GetMember().(*member.Participant).SetCampaignChecker(kgm.newPriorityCampaignChecker(groupID))
 			splitTime := kgm.splittingGroups[groupID]
 			delete(kgm.splittingGroups, groupID)
 			kgm.metrics.splitTargetGauge.Dec()
@@ -929,6 +1156,7 @@ func (kgm *KeyspaceGroupManager) updateKeyspaceGroupMembership(
 		}
 	}
 	kgm.kgs[groupID] = newGroup
+	kgm.groupMembers.Store(groupID, newGroup.Members)
 }
 
 // deleteKeyspaceGroup deletes the given keyspace group.
@@ -966,6 +1194,7 @@ func (kgm *KeyspaceGroupManager) deleteKeyspaceGroup(groupID uint32) {
 			}
 		}
 		kgm.kgs[groupID] = nil
+		kgm.groupMembers.Delete(groupID)
 	}
 
 	am := kgm.ams[groupID]
@@ -1032,6 +1261,28 @@ func (kgm *KeyspaceGroupManager) GetElectionMember(
 	return am.GetMember(), nil
 }
 
+// GetPrimaryToForward returns the address of the primary of the keyspace group serving
+// the given keyspace along with the keyspace group ID, so that the TSO requests received
+// by this non-primary member can be forwarded to the primary. An empty address is returned
+// if this member is the primary or the primary is unknown, the requests should be handled
+// locally then.
+func (kgm *KeyspaceGroupManager) GetPrimaryToForward(
+	keyspaceID, keyspaceGroupID uint32,
+) (string, uint32) {
+	if err := checkKeySpaceGroupID(keyspaceGroupID); err != nil {
+		return "", keyspaceGroupID
+	}
+	am, _, curKeyspaceGroupID, err := kgm.getKeyspaceGroupMetaWithCheck(keyspaceID, keyspaceGroupID)
+	if err != nil || am.GetMember().IsLeader() {
+		return "", curKeyspaceGroupID
+	}
+	primaryAddr := am.GetLeaderAddr()
+	if typeutil.TrimScheme(primaryAddr) == typeutil.TrimScheme(kgm.tsoServiceID.ServiceAddr) {
+		return "", curKeyspaceGroupID
+	}
+	return primaryAddr, curKeyspaceGroupID
+}
+
 // GetKeyspaceGroups returns all keyspace groups managed by the current keyspace group manager.
 func (kgm *KeyspaceGroupManager) GetKeyspaceGroups() map[uint32]*endpoint.KeyspaceGroup {
 	kgm.RLock()
@@ -1046,6 +1297,30 @@ func (kgm *KeyspaceGroupManager) GetKeyspaceGroups() map[uint32]*endpoint.Keyspa
 	return keyspaceGroups
 }
 
+// GetKeyspaceGroup returns the keyspace group with the given ID, or nil if it's
+// invalid or not loaded by the current keyspace group manager.
+func (kgm *KeyspaceGroupManager) GetKeyspaceGroup(keyspaceGroupID uint32) *endpoint.KeyspaceGroup {
+	if checkKeySpaceGroupID(keyspaceGroupID) != nil {
+		return nil
+	}
+	_, group := kgm.getKeyspaceGroupMeta(keyspaceGroupID)
+	return group
+}
+
+// SetClockOffset sets the offset added to the system time of all the allocator
+// managers, including the ones created later. It's used to simulate the clock
+// skew in tests.
+func (kgm *KeyspaceGroupManager) SetClockOffset(offset time.Duration) {
+	kgm.Lock()
+	defer kgm.Unlock()
+	kgm.clockOffset = offset
+	for _, am := range kgm.ams {
+		if am != nil {
+			am.SetClockOffset(offset)
+		}
+	}
+}
+
 // HandleTSORequest forwards TSO allocation requests to correct TSO Allocators of the given keyspace group.
 func (kgm *KeyspaceGroupManager) HandleTSORequest(
 	ctx context.Context,
@@ -1083,9 +1358,73 @@ func (kgm *KeyspaceGroupManager) HandleTSORequest(
 		return pdpb.Timestamp{}, curKeyspaceGroupID, err
 	}
 	ts, err = am.HandleRequest(ctx, dcLocation, count)
+	if err == nil {
+		kgm.observeKeyspaceRequest(keyspaceID)
+	}
 	return ts, curKeyspaceGroupID, err
 }
 
+func (kgm *KeyspaceGroupManager) observeKeyspaceRequest(keyspaceID uint32) {
+	counter, ok := kgm.keyspaceRequests.Load(keyspaceID)
+	if !ok {
+		counter, _ = kgm.keyspaceRequests.LoadOrStore(keyspaceID, &atomic.Uint64{})
+	}
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// GetKeyspaceRequests returns the number of the TSO requests handled by this server
+// for each keyspace since the server started.
+func (kgm *KeyspaceGroupManager) GetKeyspaceRequests() map[uint32]uint64 {
+	requests := make(map[uint32]uint64)
+	kgm.keyspaceRequests.Range(func(key, value any) bool {
+		requests[key.(uint32)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+	return requests
+}
+
+// KeyspaceGroupHealth is the health status of a keyspace group served by the TSO server.
+type KeyspaceGroupHealth struct {
+	// IsPrimary is true if the server is the primary of the keyspace group.
+	IsPrimary bool `json:"is_primary"`
+	// PrimaryElected is true if the keyspace group has a primary, no matter which server it is.
+	PrimaryElected bool `json:"primary_elected"`
+	// Serving is true if the server is the primary and ready to allocate the TSO.
+	Serving bool `json:"serving"`
+	// LastSavedTime is the time window saved in etcd by the primary, it's zero on the secondaries.
+	LastSavedTime time.Time `json:"last_saved_time"`
+}
+
+// GetKeyspaceGroupHealth returns the health status of the keyspace groups served by this server.
+func (kgm *KeyspaceGroupManager) GetKeyspaceGroupHealth() map[uint32]*KeyspaceGroupHealth {
+	ams := make(map[uint32]*AllocatorManager)
+	kgm.RLock()
+	for i, am := range kgm.ams {
+		if am != nil && kgm.kgs[i] != nil {
+			ams[uint32(i)] = am
+		}
+	}
+	kgm.RUnlock()
+
+	healths := make(map[uint32]*KeyspaceGroupHealth, len(ams))
+	for id, am := range ams {
+		member := am.GetMember()
+		health := &KeyspaceGroupHealth{
+			IsPrimary:      member.IsLeader(),
+			PrimaryElected: member.IsLeaderElected(),
+		}
+		if health.IsPrimary {
+			if allocator, err := am.GetAllocator(GlobalDCLocation); err == nil {
+				gta := allocator.(*GlobalTSOAllocator)
+				health.Serving = gta.IsInitialize()
+				health.LastSavedTime = gta.GetLastSavedTime()
+			}
+		}
+		healths[id] = health
+	}
+	return healths
+}
+
 func checkKeySpaceGroupID(id uint32) error {
 	if id < mcsutils.MaxKeyspaceGroupCountInUse {
 		return nil
@@ -1478,6 +1817,21 @@ func (kgm *KeyspaceGroupManager) groupSplitPatroller() {
 	}
 }
 
+// deleteElectionPath deletes all the keys under the election path of the given keyspace group.
+// The primary of the deleted keyspace group must have been gone, e.g. the merge checker has
+// waited for the primaries of the merged keyspace groups to step down.
+func (kgm *KeyspaceGroupManager) deleteElectionPath(groupID uint32) error {
+	// Append the slash to avoid deleting the election path of other keyspace groups with the same prefix.
+	electionPath := endpoint.KeyspaceGroupsElectionPath(kgm.tsoSvcRootPath, groupID) + "/"
+	_, err := kv.NewSlowLogTxn(kgm.etcdClient).
+		Then(clientv3.OpDelete(electionPath, clientv3.WithPrefix())).
+		Commit()
+	if err != nil {
+		return errs.ErrEtcdKVDelete.Wrap(err).GenWithStackByCause()
+	}
+	return nil
+}
+
 // deletedGroupCleaner is used to clean the deleted keyspace groups related data.
 // For example, the TSO keys of the merged keyspace groups remain in the storage.
 func (kgm *KeyspaceGroupManager) deletedGroupCleaner() {
@@ -1531,6 +1885,13 @@ func (kgm *KeyspaceGroupManager) deletedGroupCleaner() {
 					zap.Error(err))
 				continue
 			}
+			// Clean up the remaining election keys, e.g. the priorities of the participants.
+			if err := kgm.deleteElectionPath(groupID); err != nil {
+				log.Warn("failed to delete the keyspace group election path",
+					zap.Uint32("keyspace-group-id", groupID),
+					zap.Error(err))
+				continue
+			}
 			kgm.cleanKeyspaceGroup(groupID)
 			lastDeletedGroupID = groupID
 			lastDeletedGroupNum += 1
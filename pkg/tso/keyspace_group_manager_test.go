@@ -116,6 +116,10 @@ func (suite *keyspaceGroupManagerTestSuite) TestDeletedGroupCleanup() {
 		re.NoError(err)
 		return ts != typeutil.ZeroTime
 	})
+	// Put a participant priority key under the election path, which would remain after the primary resigns.
+	electionPath := endpoint.KeyspaceGroupsElectionPath(mgr.tsoSvcRootPath, 1) + "/"
+	_, err = suite.etcdClient.Put(suite.ctx, electionPath+"participant/1/leader_priority", "1")
+	re.NoError(err)
 	// Delete keyspace group 1.
 	suite.applyEtcdEvents(re, rootPath, []*etcdEvent{generateKeyspaceGroupDeleteEvent(1)})
 	// Check if the TSO key is deleted.
@@ -124,6 +128,12 @@ func (suite *keyspaceGroupManagerTestSuite) TestDeletedGroupCleanup() {
 		re.NoError(err)
 		return ts == typeutil.ZeroTime
 	})
+	// Check if the election keys are deleted.
+	testutil.Eventually(re, func() bool {
+		resp, err := suite.etcdClient.Get(suite.ctx, electionPath, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		re.NoError(err)
+		return resp.Count == 0
+	})
 	// Check if the keyspace group is deleted completely.
 	mgr.RLock()
 	re.Nil(mgr.ams[1])
@@ -180,8 +190,46 @@ func (suite *keyspaceGroupManagerTestSuite) TestNewKeyspaceGroupManager() {
 	re.Equal(mcsutils.DefaultLeaderLease, am.leaderLease)
 	re.Equal(time.Hour*24, am.maxResetTSGap())
 	re.Equal(legacySvcRootPath, am.rootPath)
-	re.Equal(time.Duration(mcsutils.DefaultLeaderLease)*time.Second, am.saveInterval)
-	re.Equal(time.Duration(50)*time.Millisecond, am.updatePhysicalInterval)
+	re.Equal(time.Duration(mcsutils.DefaultLeaderLease)*time.Second, am.getSaveInterval())
+	re.Equal(time.Duration(50)*time.Millisecond, am.getUpdatePhysicalInterval())
+}
+
+// TestKeyspaceGroupTSOIntervals tests the TSO intervals overridden by the keyspace group.
+func (suite *keyspaceGroupManagerTestSuite) TestKeyspaceGroupTSOIntervals() {
+	re := suite.Require()
+
+	mgr := suite.newUniqueKeyspaceGroupManager(0)
+	re.NotNil(mgr)
+	defer mgr.Close()
+	re.NoError(mgr.Initialize())
+
+	rootPath := mgr.legacySvcRootPath
+	svcAddr := mgr.tsoServiceID.ServiceAddr
+	checkIntervals := func(updatePhysicalInterval, saveInterval time.Duration) {
+		testutil.Eventually(re, func() bool {
+			am, err := mgr.GetAllocatorManager(1)
+			return err == nil && am != nil &&
+				am.getUpdatePhysicalInterval() == updatePhysicalInterval &&
+				am.getSaveInterval() == saveInterval
+		})
+	}
+
+	event := generateKeyspaceGroupPutEvent(1, []uint32{1}, []string{svcAddr})
+	event.ksg.TSOConfig = map[string]string{endpoint.TSOUpdatePhysicalIntervalKey: "10ms"}
+	suite.applyEtcdEvents(re, rootPath, []*etcdEvent{event})
+	checkIntervals(10*time.Millisecond, suite.cfg.TSOSaveInterval)
+
+	// The changes of the keyspace group are applied to the running allocator manager.
+	event = generateKeyspaceGroupPutEvent(1, []uint32{1}, []string{svcAddr})
+	event.ksg.TSOConfig = map[string]string{endpoint.TSOSaveIntervalKey: "5s"}
+	suite.applyEtcdEvents(re, rootPath, []*etcdEvent{event})
+	checkIntervals(suite.cfg.TSOUpdatePhysicalInterval, 5*time.Second)
+
+	// The invalid settings fall back to the server config.
+	event = generateKeyspaceGroupPutEvent(1, []uint32{1}, []string{svcAddr})
+	event.ksg.TSOConfig = map[string]string{endpoint.TSOSaveIntervalKey: "abc"}
+	suite.applyEtcdEvents(re, rootPath, []*etcdEvent{event})
+	checkIntervals(suite.cfg.TSOUpdatePhysicalInterval, suite.cfg.TSOSaveInterval)
 }
 
 // TestLoadKeyspaceGroupsAssignment tests the loading of the keyspace group assignment.
@@ -477,6 +525,13 @@ func (suite *keyspaceGroupManagerTestSuite) TestGetKeyspaceGroupMetaWithCheck()
 	re.Equal(uint32(100), kgid)
 	re.Nil(am)
 	re.Nil(kg)
+
+	// Only the loaded and valid keyspace groups can be got by the ID.
+	kg = mgr.GetKeyspaceGroup(mcsutils.DefaultKeyspaceGroupID)
+	re.NotNil(kg)
+	re.Equal(mcsutils.DefaultKeyspaceGroupID, kg.ID)
+	re.Nil(mgr.GetKeyspaceGroup(100))
+	re.Nil(mgr.GetKeyspaceGroup(mcsutils.MaxKeyspaceGroupCountInUse))
 }
 
 // TestDefaultMembershipRestriction tests the restriction of default keyspace always
@@ -1146,6 +1201,111 @@ func (suite *keyspaceGroupManagerTestSuite) TestPrimaryPriorityChange() {
 	wg.Wait()
 }
 
+// TestPriorityCampaignChecker tests that the campaign is deferred to the alive member
+// with higher priority for a bounded time.
+func (suite *keyspaceGroupManagerTestSuite) TestPriorityCampaignChecker() {
+	re := suite.Require()
+
+	mgr := suite.newUniqueKeyspaceGroupManager(0)
+	re.NotNil(mgr)
+	defer mgr.Close()
+	mgr.maxPriorityCampaignDeferral = 200 * time.Millisecond
+
+	localAddr := mgr.tsoServiceID.ServiceAddr
+	otherAddr := tempurl.Alloc()
+	mgr.groupMembers.Store(uint32(1), []endpoint.KeyspaceGroupMember{
+		{Address: localAddr, Priority: 0},
+		{Address: otherAddr, Priority: 1},
+	})
+	checker := mgr.newPriorityCampaignChecker(1)
+	// The member with higher priority is not alive.
+	re.True(checker(nil))
+	// Defer the campaign since the member with higher priority is alive.
+	mgr.tsoNodes.Store(otherAddr, struct{}{})
+	re.False(checker(nil))
+	// The deferral is bounded.
+	testutil.Eventually(re, func() bool {
+		return checker(nil)
+	})
+	// The member with the highest priority campaigns without deferral.
+	mgr.groupMembers.Store(uint32(1), []endpoint.KeyspaceGroupMember{
+		{Address: localAddr, Priority: 2},
+		{Address: otherAddr, Priority: 1},
+	})
+	re.True(mgr.newPriorityCampaignChecker(1)(nil))
+}
+
+func (suite *keyspaceGroupManagerTestSuite) TestDrain() {
+	re := suite.Require()
+
+	mgr := suite.newUniqueKeyspaceGroupManager(0)
+	re.NotNil(mgr)
+	defer mgr.Close()
+	re.NoError(mgr.Initialize())
+	localAddr := mgr.tsoServiceID.ServiceAddr
+	otherAddr := tempurl.Alloc()
+	event := generateKeyspaceGroupPutEvent(0, []uint32{0}, []string{localAddr, otherAddr})
+	re.NoError(putKeyspaceGroupToEtcd(suite.ctx, suite.etcdClient, mgr.legacySvcRootPath, event.ksg))
+	testutil.Eventually(re, func() bool {
+		_, kg := mgr.getKeyspaceGroupMeta(0)
+		return kg != nil && len(kg.Members) == 2
+	})
+	waitForPrimariesServing(re, []*KeyspaceGroupManager{mgr}, []uint32{0})
+
+	// The other member is alive but never takes over the primary, the server is
+	// undrained after the timeout and keeps serving.
+	mgr.tsoNodes.Store(otherAddr, struct{}{})
+	ctx, cancel := context.WithTimeout(suite.ctx, time.Second)
+	err := mgr.Drain(ctx)
+	cancel()
+	re.ErrorContains(err, "failed to hand off")
+	re.False(mgr.IsDraining())
+	waitForPrimariesServing(re, []*KeyspaceGroupManager{mgr}, []uint32{0})
+
+	// There is no other member to hand off to, the server stops accepting the
+	// requests at once.
+	mgr.tsoNodes.Delete(otherAddr)
+	re.NoError(mgr.Drain(suite.ctx))
+	re.True(mgr.IsDraining())
+	member, err := mgr.GetElectionMember(0, 0)
+	re.NoError(err)
+	re.False(member.IsLeader())
+	mgr.Undrain()
+	re.False(mgr.IsDraining())
+	waitForPrimariesServing(re, []*KeyspaceGroupManager{mgr}, []uint32{0})
+}
+
+func (suite *keyspaceGroupManagerTestSuite) TestClockDriftResignsPrimary() {
+	re := suite.Require()
+
+	cfg := suite.createConfig()
+	cfg.MaxClockDrift = 5 * time.Second
+	mgr := suite.newKeyspaceGroupManager(0, rand.Uint64(), cfg)
+	re.NotNil(mgr)
+	defer mgr.Close()
+	re.NoError(mgr.Initialize())
+	waitForPrimariesServing(re, []*KeyspaceGroupManager{mgr}, []uint32{0})
+
+	am, err := mgr.GetAllocatorManager(0)
+	re.NoError(err)
+	detector := NewClockDriftDetector(nil, nil, nil)
+	detector.drift.Store(int64(10 * time.Second))
+	detector.checkedAt.Store(time.Now().UnixNano())
+	am.SetClockDriftDetector(detector)
+	// The primary resigns and the member can't be the primary again.
+	testutil.Eventually(re, func() bool {
+		_, _, err := mgr.HandleTSORequest(mgr.ctx, 0, 0, GlobalDCLocation, 1)
+		return err != nil
+	})
+	time.Sleep(time.Second)
+	_, _, err = mgr.HandleTSORequest(mgr.ctx, 0, 0, GlobalDCLocation, 1)
+	re.Error(err)
+
+	// The member becomes the primary again once the drift recovers.
+	detector.drift.Store(0)
+	waitForPrimariesServing(re, []*KeyspaceGroupManager{mgr}, []uint32{0})
+}
+
 // Register TSO server.
 func (suite *keyspaceGroupManagerTestSuite) registerTSOServer(
 	re *require.Assertions, clusterID, svcAddr string, cfg *TestServiceConfig,
@@ -74,9 +74,12 @@ func newLocalTimestampOracle(am *AllocatorManager, leadership *election.Leadersh
 		keyspaceGroupID:        am.kgID,
 		tsPath:                 endpoint.KeyspaceGroupLocalTSPath(localTSOAllocatorEtcdPrefix, am.kgID, dcLocation),
 		storage:                am.storage,
-		saveInterval:           am.saveInterval,
-		updatePhysicalInterval: am.updatePhysicalInterval,
+		saveInterval:           am.getSaveInterval,
+		updatePhysicalInterval: am.getUpdatePhysicalInterval,
 		maxResetTSGap:          am.maxResetTSGap,
+		clockOffset:            am.getClockOffset,
+		clockDrift:             am.getClockDrift,
+		maxClockDrift:          am.maxClockDrift,
 		dcLocation:             dcLocation,
 		tsoMux:                 &tsoObject{},
 		metrics:                newTSOMetrics(am.getGroupIDStr(), dcLocation),
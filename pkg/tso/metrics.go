@@ -17,11 +17,12 @@ package tso
 import "github.com/prometheus/client_golang/prometheus"
 
 const (
-	pdNamespace  = "pd"
-	tsoNamespace = "tso"
-	dcLabel      = "dc"
-	typeLabel    = "type"
-	groupLabel   = "group"
+	pdNamespace    = "pd"
+	tsoNamespace   = "tso"
+	dcLabel        = "dc"
+	typeLabel      = "type"
+	groupLabel     = "group"
+	componentLabel = "component"
 )
 
 var (
@@ -67,6 +68,22 @@ var (
 			Help:      "Indicate the PD server role info, whether it's a TSO allocator.",
 		}, []string{groupLabel, dcLabel})
 
+	tsoClockDriftGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: pdNamespace,
+			Subsystem: "tso",
+			Name:      "clock_drift_seconds",
+			Help:      "The estimated drift of the local clock against the etcd peers, positive if the local clock is ahead.",
+		})
+
+	tsoComponentAllocationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: pdNamespace,
+			Subsystem: "tso",
+			Name:      "component_allocations",
+			Help:      "Counter of the TSO requests and the allocated timestamps by the calling components.",
+		}, []string{componentLabel, typeLabel})
+
 	// Keyspace Group metrics
 	keyspaceGroupStateGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -92,6 +109,8 @@ func init() {
 	prometheus.MustRegister(tsoGap)
 	prometheus.MustRegister(tsoOpDuration)
 	prometheus.MustRegister(tsoAllocatorRole)
+	prometheus.MustRegister(tsoClockDriftGauge)
+	prometheus.MustRegister(tsoComponentAllocationCounter)
 	prometheus.MustRegister(keyspaceGroupStateGauge)
 	prometheus.MustRegister(keyspaceGroupOpDuration)
 }
@@ -116,6 +135,8 @@ type tsoMetrics struct {
 	notLeaderAnymoreEvent        prometheus.Counter
 	logicalOverflowEvent         prometheus.Counter
 	exceededMaxRetryEvent        prometheus.Counter
+	clockDriftEvent              prometheus.Counter
+	logicalExhaustionEvent       prometheus.Counter
 	// timestampOracle operation duration
 	syncSaveDuration   prometheus.Observer
 	resetSaveDuration  prometheus.Observer
@@ -153,6 +174,8 @@ func newTSOMetrics(groupID, dcLocation string) *tsoMetrics {
 		notLeaderAnymoreEvent:        tsoCounter.WithLabelValues("not_leader_anymore", groupID, dcLocation),
 		logicalOverflowEvent:         tsoCounter.WithLabelValues("logical_overflow", groupID, dcLocation),
 		exceededMaxRetryEvent:        tsoCounter.WithLabelValues("exceeded_max_retry", groupID, dcLocation),
+		clockDriftEvent:              tsoCounter.WithLabelValues("clock_drift", groupID, dcLocation),
+		logicalExhaustionEvent:       tsoCounter.WithLabelValues("logical_exhaustion", groupID, dcLocation),
 		syncSaveDuration:             tsoOpDuration.WithLabelValues("sync_save", groupID, dcLocation),
 		resetSaveDuration:            tsoOpDuration.WithLabelValues("reset_save", groupID, dcLocation),
 		updateSaveDuration:           tsoOpDuration.WithLabelValues("update_save", groupID, dcLocation),
@@ -33,6 +33,7 @@ type TestServiceConfig struct {
 	TSOUpdatePhysicalInterval time.Duration       // Interval to update TSO in physical storage.
 	TSOSaveInterval           time.Duration       // Interval to save TSO to physical storage.
 	MaxResetTSGap             time.Duration       // Maximum gap to reset TSO.
+	MaxClockDrift             time.Duration       // Maximum drift of the local clock.
 	TLSConfig                 *grpcutil.TLSConfig // TLS configuration.
 }
 
@@ -81,6 +82,11 @@ func (c *TestServiceConfig) GetMaxResetTSGap() time.Duration {
 	return c.MaxResetTSGap
 }
 
+// GetMaxClockDrift returns the MaxClockDrift field of TestServiceConfig.
+func (c *TestServiceConfig) GetMaxClockDrift() time.Duration {
+	return c.MaxClockDrift
+}
+
 // GetTLSConfig returns the TLSConfig field of TestServiceConfig.
 func (c *TestServiceConfig) GetTLSConfig() *grpcutil.TLSConfig {
 	return c.TLSConfig
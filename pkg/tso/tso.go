@@ -49,6 +49,9 @@ const (
 	// and trigger unnecessary warnings about clock offset.
 	// It's an empirical value.
 	jetLagWarningThreshold = 150 * time.Millisecond
+	// logicalExhaustionAlarmRatio is the ratio of maxLogical, the logical time
+	// consumed within a physical tick at the current rate above it raises the alarm.
+	logicalExhaustionAlarmRatio = 0.8
 )
 
 // tsoObject is used to store the current TSO in memory with a RWMutex lock.
@@ -67,9 +70,15 @@ type timestampOracle struct {
 	tsPath  string
 	storage endpoint.TSOStorage
 	// TODO: remove saveInterval
-	saveInterval           time.Duration
-	updatePhysicalInterval time.Duration
+	saveInterval           func() time.Duration
+	updatePhysicalInterval func() time.Duration
 	maxResetTSGap          func() time.Duration
+	// clockOffset is added to the system time, it's used to simulate the clock skew.
+	clockOffset func() time.Duration
+	// clockDrift returns the drift of the local clock against the peers, the
+	// primary/leader resigns once it exceeds maxClockDrift.
+	clockDrift    func() time.Duration
+	maxClockDrift func() time.Duration
 	// tso info stored in the memory
 	tsoMux *tsoObject
 	// last timestamp window stored in etcd
@@ -96,6 +105,30 @@ func (t *timestampOracle) setTSOPhysical(next time.Time, force bool) {
 	}
 }
 
+// now returns the system time with the clock offset.
+func (t *timestampOracle) now() time.Time {
+	if t.clockOffset == nil {
+		return time.Now()
+	}
+	return time.Now().Add(t.clockOffset())
+}
+
+// checkClockDrift returns an error if the drift of the local clock exceeds the
+// max clock drift, 0 max clock drift means no limit.
+func (t *timestampOracle) checkClockDrift() error {
+	if t.clockDrift == nil || t.maxClockDrift == nil {
+		return nil
+	}
+	maxDrift := t.maxClockDrift()
+	if maxDrift <= 0 {
+		return nil
+	}
+	if drift := t.clockDrift(); drift > maxDrift || drift < -maxDrift {
+		return errs.ErrClockDrift.FastGenByArgs(drift, maxDrift)
+	}
+	return nil
+}
+
 func (t *timestampOracle) getTSO() (time.Time, int64) {
 	t.tsoMux.RLock()
 	defer t.tsoMux.RUnlock()
@@ -164,6 +197,16 @@ func (t *timestampOracle) SyncTimestamp() error {
 		time.Sleep(time.Second)
 	})
 
+	// Refuse to be the primary/leader if the local clock is drifting, otherwise the
+	// TSO may jump to the future and fall back once the clock is corrected.
+	if err := t.checkClockDrift(); err != nil {
+		log.Error("refuse to sync timestamp",
+			logutil.CondUint32("keyspace-group-id", t.keyspaceGroupID, t.keyspaceGroupID > 0),
+			errs.ZapError(err))
+		t.metrics.clockDriftEvent.Inc()
+		return err
+	}
+
 	last, err := t.storage.LoadTimestamp(t.tsPath)
 	if err != nil {
 		return err
@@ -187,7 +230,7 @@ func (t *timestampOracle) SyncTimestamp() error {
 		return nil
 	}
 
-	next := time.Now()
+	next := t.now()
 	failpoint.Inject("fallBackSync", func() {
 		next = next.Add(time.Hour)
 	})
@@ -207,7 +250,7 @@ func (t *timestampOracle) SyncTimestamp() error {
 	failpoint.Inject("failedToSaveTimestamp", func() {
 		failpoint.Return(errs.ErrEtcdTxnInternal)
 	})
-	save := next.Add(t.saveInterval)
+	save := next.Add(t.saveInterval())
 	start := time.Now()
 	if err = t.storage.SaveTimestamp(t.GetTimestampPath(), save); err != nil {
 		t.metrics.errSaveSyncTSEvent.Inc()
@@ -280,7 +323,7 @@ func (t *timestampOracle) resetUserTimestampInner(leadership *election.Leadershi
 	}
 	// save into etcd only if nextPhysical is close to lastSavedTime
 	if typeutil.SubRealTimeByWallClock(t.getLastSavedTime(), nextPhysical) <= UpdateTimestampGuard {
-		save := nextPhysical.Add(t.saveInterval)
+		save := nextPhysical.Add(t.saveInterval())
 		start := time.Now()
 		if err := t.storage.SaveTimestamp(t.GetTimestampPath(), save); err != nil {
 			t.metrics.errSaveResetTSEvent.Inc()
@@ -319,7 +362,7 @@ func (t *timestampOracle) UpdateTimestamp() error {
 	t.metrics.tsoPhysicalGauge.Set(float64(prevPhysical.UnixNano() / int64(time.Millisecond)))
 	t.metrics.tsoPhysicalGapGauge.Set(float64(time.Since(prevPhysical).Milliseconds()))
 
-	now := time.Now()
+	now := t.now()
 	failpoint.Inject("fallBackUpdate", func() {
 		now = now.Add(time.Hour)
 	})
@@ -330,13 +373,13 @@ func (t *timestampOracle) UpdateTimestamp() error {
 	t.metrics.saveEvent.Inc()
 
 	jetLag := typeutil.SubRealTimeByWallClock(now, prevPhysical)
-	if jetLag > 3*t.updatePhysicalInterval && jetLag > jetLagWarningThreshold {
+	if jetLag > 3*t.updatePhysicalInterval() && jetLag > jetLagWarningThreshold {
 		log.Warn("clock offset",
 			logutil.CondUint32("keyspace-group-id", t.keyspaceGroupID, t.keyspaceGroupID > 0),
 			zap.Duration("jet-lag", jetLag),
 			zap.Time("prev-physical", prevPhysical),
 			zap.Time("now", now),
-			zap.Duration("update-physical-interval", t.updatePhysicalInterval))
+			zap.Duration("update-physical-interval", t.updatePhysicalInterval()))
 		t.metrics.slowSaveEvent.Inc()
 	}
 
@@ -344,6 +387,20 @@ func (t *timestampOracle) UpdateTimestamp() error {
 		t.metrics.systemTimeSlowEvent.Inc()
 	}
 
+	// Don't advance the physical time with the drifting local clock. The error makes
+	// the allocator reset and the primary/leader resign, and the member with the
+	// drifting clock refuses to sync the timestamp to be the primary/leader again.
+	if err := t.checkClockDrift(); err != nil {
+		log.Error("the local clock is drifting, resign the primary/leader",
+			logutil.CondUint32("keyspace-group-id", t.keyspaceGroupID, t.keyspaceGroupID > 0),
+			zap.String("dc-location", t.dcLocation),
+			errs.ZapError(err))
+		t.metrics.clockDriftEvent.Inc()
+		return err
+	}
+
+	t.checkLogicalExhaustion(prevLogical, jetLag)
+
 	var next time.Time
 	// If the system time is greater, it will be synchronized with the system time.
 	if jetLag > UpdateTimestampGuard {
@@ -364,7 +421,7 @@ func (t *timestampOracle) UpdateTimestamp() error {
 	// It is not safe to increase the physical time to `next`.
 	// The time window needs to be updated and saved to etcd.
 	if typeutil.SubRealTimeByWallClock(t.getLastSavedTime(), next) <= UpdateTimestampGuard {
-		save := next.Add(t.saveInterval)
+		save := next.Add(t.saveInterval())
 		start := time.Now()
 		if err := t.storage.SaveTimestamp(t.GetTimestampPath(), save); err != nil {
 			log.Warn("save timestamp failed",
@@ -384,6 +441,29 @@ func (t *timestampOracle) UpdateTimestamp() error {
 	return nil
 }
 
+// checkLogicalExhaustion raises the alarm if the logical time consumed within a
+// physical tick at the current rate is close to maxLogical, the TSO requests have
+// to wait for the next physical time once it's exhausted. The logical time is
+// consumed since the physical time is updated, which is about `elapsed` ago.
+func (t *timestampOracle) checkLogicalExhaustion(logical int64, elapsed time.Duration) bool {
+	if logical <= 0 {
+		return false
+	}
+	elapsed = max(elapsed, UpdateTimestampGuard)
+	perTick := float64(logical) * float64(t.updatePhysicalInterval()) / float64(elapsed)
+	if perTick < float64(maxLogical)*logicalExhaustionAlarmRatio {
+		return false
+	}
+	log.Warn("the logical time is about to be exhausted within a physical tick, please adjust config item `tso-update-physical-interval`",
+		logutil.CondUint32("keyspace-group-id", t.keyspaceGroupID, t.keyspaceGroupID > 0),
+		zap.Int64("logical", logical),
+		zap.Duration("elapsed", elapsed),
+		zap.Float64("logical-per-tick", perTick),
+		zap.Duration("update-physical-interval", t.updatePhysicalInterval()))
+	t.metrics.logicalExhaustionEvent.Inc()
+	return true
+}
+
 var maxRetryCount = 10
 
 // getTS is used to get a timestamp.
@@ -415,7 +495,7 @@ func (t *timestampOracle) getTS(ctx context.Context, leadership *election.Leader
 				zap.Reflect("response", resp),
 				zap.Int("retry-count", i), errs.ZapError(errs.ErrLogicOverflow))
 			t.metrics.logicalOverflowEvent.Inc()
-			time.Sleep(t.updatePhysicalInterval)
+			time.Sleep(t.updatePhysicalInterval())
 			continue
 		}
 		// In case lease expired after the first check.
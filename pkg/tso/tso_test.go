@@ -0,0 +1,59 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tso
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/storage/kv"
+)
+
+func TestLogicalExhaustionAlarm(t *testing.T) {
+	re := require.New(t)
+	oracle := &timestampOracle{
+		storage:                endpoint.NewStorageEndpoint(kv.NewMemoryKV(), nil),
+		saveInterval:           func() time.Duration { return 3 * time.Second },
+		updatePhysicalInterval: func() time.Duration { return 50 * time.Millisecond },
+		maxResetTSGap:          func() time.Duration { return time.Hour },
+		tsoMux:                 &tsoObject{},
+		metrics:                newTSOMetrics("0", GlobalDCLocation),
+	}
+	re.NoError(oracle.SyncTimestamp())
+
+	// a few logical time within a tick is far from exhausted.
+	re.False(oracle.checkLogicalExhaustion(0, 50*time.Millisecond))
+	re.False(oracle.checkLogicalExhaustion(1000, 50*time.Millisecond))
+	// a quarter of maxLogical in 10ms exhausts it within the 50ms tick.
+	re.True(oracle.checkLogicalExhaustion(maxLogical/4, 10*time.Millisecond))
+	re.False(oracle.checkLogicalExhaustion(maxLogical/4, 50*time.Millisecond))
+	// the elapsed time is no less than the guard.
+	re.False(oracle.checkLogicalExhaustion(maxLogical/100, 0))
+
+	// the alarm is raised by the logical time allocated since the physical time is updated.
+	physical, _, _ := oracle.generateTSO(context.Background(), maxLogical*3/4, 0)
+	re.NotZero(physical)
+	prevPhysical, logical := oracle.getTSO()
+	re.Equal(maxLogical*3/4, logical)
+	re.True(oracle.checkLogicalExhaustion(logical, time.Since(prevPhysical)))
+	re.NoError(oracle.UpdateTimestamp())
+	// the physical time is increased and the logical time is reset.
+	next, logical := oracle.getTSO()
+	re.True(next.After(prevPhysical))
+	re.Zero(logical)
+}
@@ -54,6 +54,8 @@ const (
 	PDRedirectorHeader = "PD-Redirector"
 	// PDAllowFollowerHandleHeader is used to mark whether this request is allowed to be handled by the follower PD.
 	PDAllowFollowerHandleHeader = "PD-Allow-follower-handle" // #nosec G101
+	// PDClusterStateEpochHeader is used to carry the cluster state epoch in the response.
+	PDClusterStateEpochHeader = "PD-Cluster-State-Epoch"
 	// XForwardedForHeader is used to mark the client IP.
 	XForwardedForHeader = "X-Forwarded-For"
 	// XForwardedPortHeader is used to mark the client port.
@@ -378,6 +380,27 @@ func ReadJSON(r io.ReadCloser, data any) error {
 	return err
 }
 
+// WriteJSONStream writes the items as a JSON array one by one, so that the
+// whole response doesn't need to be built in memory.
+func WriteJSONStream[T any](w io.Writer, items []T) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	for i, item := range items {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
 // ReadJSONRespondError writes json into data.
 // On error respond with a 400 Bad Request
 func ReadJSONRespondError(rd *render.Render, w http.ResponseWriter, body io.ReadCloser, data any) error {
@@ -489,10 +512,23 @@ func (p *customReverseProxies) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		copyHeader(w.Header(), resp.Header)
 		w.WriteHeader(resp.StatusCode)
 
+		// Each chunk is written and flushed once it's read, so the streaming
+		// responses are not held by the proxy.
+		flusher, _ := w.(http.Flusher)
+		buf := make([]byte, chunkSize)
 		for {
-			if _, err = io.CopyN(w, reader, chunkSize); err != nil {
-				if err == io.EOF {
-					err = nil
+			n, readErr := reader.Read(buf)
+			if n > 0 {
+				if _, err = w.Write(buf[:n]); err != nil {
+					break
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+			if readErr != nil {
+				if readErr != io.EOF {
+					err = readErr
 				}
 				break
 			}
@@ -15,10 +15,12 @@
 package apiutil
 
 import (
+	"bufio"
 	"bytes"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -240,3 +242,32 @@ func TestParseHexKeys(t *testing.T) {
 	re.NotEqual([]byte("\x00\x01\x02\x03\x04\x05\x06\x07"), parseKeys[0])
 	re.Equal([]byte("world"), parseKeys[1])
 }
+
+func TestCustomReverseProxiesStreaming(t *testing.T) {
+	re := require.New(t)
+	release := make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("first\n"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte("second\n"))
+	}))
+	defer backend.Close()
+	backendURL, err := url.Parse(backend.URL)
+	re.NoError(err)
+	proxy := httptest.NewServer(NewCustomReverseProxies(&http.Client{}, []url.URL{*backendURL}))
+	defer proxy.Close()
+
+	resp, err := http.Get(proxy.URL)
+	re.NoError(err)
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	// the first line arrives before the backend finishes the response.
+	line, err := reader.ReadString('\n')
+	re.NoError(err)
+	re.Equal("first\n", line)
+	close(release)
+	line, err = reader.ReadString('\n')
+	re.NoError(err)
+	re.Equal("second\n", line)
+}
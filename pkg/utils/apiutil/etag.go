@@ -0,0 +1,104 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+const (
+	// ETagHeader is the header of the entity tag of the response.
+	ETagHeader = "ETag"
+	// IfNoneMatchHeader is the header used by the GET requests to avoid transferring the unchanged resource.
+	IfNoneMatchHeader = "If-None-Match"
+	// IfMatchHeader is the header used by the write requests to do the optimistic concurrency control.
+	IfMatchHeader = "If-Match"
+
+	// PreconditionFailedMsg is the message responded when the If-Match header doesn't match.
+	PreconditionFailedMsg = "The resource has been modified, please get it again."
+)
+
+// ComputeETag returns the strong entity tag of the JSON representation of the value.
+func ComputeETag(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// matchETag returns whether the ETag is listed in the header of the conditional request.
+func matchETag(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteETag sets the ETag header of the value. It returns true and responds
+// 304 Not Modified if the ETag matches the If-None-Match header of the request,
+// in which case the caller should not write the body anymore.
+func WriteETag(w http.ResponseWriter, r *http.Request, v any) bool {
+	etag, err := ComputeETag(v)
+	if err != nil {
+		return false
+	}
+	w.Header().Set(ETagHeader, etag)
+	if header := r.Header.Get(IfNoneMatchHeader); header != "" && matchETag(header, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// IfMatch returns whether the If-Match header of the request matches the ETag of
+// the current value. It always returns true if the request has no such header.
+func IfMatch(r *http.Request, current any) bool {
+	header := r.Header.Get(IfMatchHeader)
+	if header == "" {
+		return true
+	}
+	etag, err := ComputeETag(current)
+	if err != nil {
+		return false
+	}
+	return matchETag(header, etag)
+}
+
+// IfMatchCheck returns a check of the current value, which fails with
+// errs.ErrPreconditionFailed if the If-Match header of the request doesn't match
+// the ETag of the value. It's used to compare the ETag under the same lock as the
+// write, and returns nil if the request has no such header.
+func IfMatchCheck[T any](r *http.Request) func(current T) error {
+	if r.Header.Get(IfMatchHeader) == "" {
+		return nil
+	}
+	return func(current T) error {
+		if !IfMatch(r, current) {
+			return errs.ErrPreconditionFailed.FastGenByArgs()
+		}
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/errs"
+)
+
+func TestETag(t *testing.T) {
+	re := require.New(t)
+	value := map[string]int{"a": 1}
+	etag, err := ComputeETag(value)
+	re.NoError(err)
+
+	// the first request gets the ETag.
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	re.False(WriteETag(w, r, value))
+	re.Equal(etag, w.Header().Get(ETagHeader))
+
+	// the unchanged value is not transferred again.
+	w = httptest.NewRecorder()
+	r.Header.Set(IfNoneMatchHeader, `"other", W/`+etag)
+	re.True(WriteETag(w, r, value))
+	re.Equal(http.StatusNotModified, w.Code)
+
+	// the changed value has a different ETag.
+	w = httptest.NewRecorder()
+	re.False(WriteETag(w, r, map[string]int{"a": 2}))
+	re.NotEqual(etag, w.Header().Get(ETagHeader))
+
+	r = httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+	re.True(IfMatch(r, value))
+	r.Header.Set(IfMatchHeader, etag)
+	re.True(IfMatch(r, value))
+	re.False(IfMatch(r, map[string]int{"a": 2}))
+	r.Header.Set(IfMatchHeader, "*")
+	re.True(IfMatch(r, map[string]int{"a": 2}))
+
+	// the check is used to compare the ETag under the lock of the write.
+	r = httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+	re.Nil(IfMatchCheck[map[string]int](r))
+	r.Header.Set(IfMatchHeader, etag)
+	check := IfMatchCheck[map[string]int](r)
+	re.NoError(check(value))
+	re.True(errs.ErrPreconditionFailed.Equal(check(map[string]int{"a": 2})))
+}
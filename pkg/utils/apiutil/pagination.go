@@ -0,0 +1,131 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiutil
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/pingcap/errors"
+)
+
+const (
+	// PageTokenParam is the query parameter of the cursor of the page.
+	PageTokenParam = "page_token"
+	// PageLimitParam is the query parameter of the max number of the items of the page.
+	PageLimitParam = "limit"
+	// XNextPageTokenHeader is the header of the cursor of the next page, it's
+	// absent if the page is the last one.
+	XNextPageTokenHeader = "X-Next-Page-Token"
+)
+
+// PageQuery is the cursor-based pagination query of the listing APIs.
+// The items are always sorted by their key, e.g. the ID or the start key, and
+// the token is the key of the first item of the page, so the pages are stable
+// even if the items are added or removed between the requests.
+type PageQuery struct {
+	// Token is the next_page_token of the previous page, an empty token means the first page.
+	Token string
+	// Limit is the max number of the items of the page, 0 means no limit.
+	Limit int
+}
+
+// ParsePageQuery parses the pagination query from the URL query.
+func ParsePageQuery(query url.Values) (*PageQuery, error) {
+	q := &PageQuery{Token: query.Get(PageTokenParam)}
+	if limit := query.Get(PageLimitParam); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, errors.Errorf("invalid limit %q", limit)
+		}
+		if l < 0 {
+			return nil, errors.Errorf("limit %d should not be negative", l)
+		}
+		q.Limit = l
+	}
+	return q, nil
+}
+
+// IsPaged returns whether the query asks for a page rather than all the items.
+func (q *PageQuery) IsPaged() bool {
+	return q.Token != "" || q.Limit > 0
+}
+
+// ScanLimit returns the number of the items to scan for the page, an extra item
+// is scanned to know the token of the next page.
+func (q *PageQuery) ScanLimit() int {
+	if q.Limit <= 0 {
+		return 0
+	}
+	return q.Limit + 1
+}
+
+// IDToken returns the ID of the first item of the page.
+func (q *PageQuery) IDToken() (uint64, error) {
+	if q.Token == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(q.Token, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid page token %q", q.Token)
+	}
+	return id, nil
+}
+
+// KeyToken returns the key of the first item of the page.
+func (q *PageQuery) KeyToken() ([]byte, error) {
+	key, err := hex.DecodeString(q.Token)
+	if err != nil {
+		return nil, errors.Errorf("invalid page token %q", q.Token)
+	}
+	return key, nil
+}
+
+// KeyPageToken returns the page token of the item with the key.
+func KeyPageToken(key []byte) string {
+	return hex.EncodeToString(key)
+}
+
+// TrimPage trims the scanned items to the page, it returns the page and the
+// token of the next page which is empty if there are no more items.
+func TrimPage[T any](items []T, q *PageQuery, token func(T) string) ([]T, string) {
+	if q.Limit <= 0 || len(items) <= q.Limit {
+		return items, ""
+	}
+	return items[:q.Limit], token(items[q.Limit])
+}
+
+// PaginateByID sorts the items by ID and returns the page of the query along
+// with the token of the next page.
+func PaginateByID[T any](items []T, q *PageQuery, id func(T) uint64) ([]T, string, error) {
+	start, err := q.IDToken()
+	if err != nil {
+		return nil, "", err
+	}
+	sort.SliceStable(items, func(i, j int) bool { return id(items[i]) < id(items[j]) })
+	items = items[sort.Search(len(items), func(i int) bool { return id(items[i]) >= start }):]
+	page, next := TrimPage(items, q, func(item T) string { return strconv.FormatUint(id(item), 10) })
+	return page, next, nil
+}
+
+// SetNextPageToken sets the token of the next page into the response header.
+func SetNextPageToken(w http.ResponseWriter, token string) {
+	if token != "" {
+		w.Header().Set(XNextPageTokenHeader, token)
+	}
+}
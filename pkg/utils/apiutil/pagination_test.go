@@ -0,0 +1,68 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiutil
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginateByID(t *testing.T) {
+	re := require.New(t)
+	_, err := ParsePageQuery(url.Values{PageLimitParam: {"-1"}})
+	re.Error(err)
+	_, err = ParsePageQuery(url.Values{PageLimitParam: {"a"}})
+	re.Error(err)
+
+	identity := func(id uint64) uint64 { return id }
+	items := []uint64{5, 1, 4, 2, 3}
+	q, err := ParsePageQuery(url.Values{PageLimitParam: {"2"}})
+	re.NoError(err)
+	re.True(q.IsPaged())
+	re.Equal(3, q.ScanLimit())
+
+	// the items are sorted by ID, and the token is the ID of the first item of the next page.
+	var pages [][]uint64
+	for {
+		page, next, err := PaginateByID(items, q, identity)
+		re.NoError(err)
+		pages = append(pages, page)
+		if next == "" {
+			break
+		}
+		q.Token = next
+	}
+	re.Equal([][]uint64{{1, 2}, {3, 4}, {5}}, pages)
+
+	// the page is stable even if the items before it are removed.
+	q.Token = "3"
+	page, next, err := PaginateByID([]uint64{5, 4, 3}, q, identity)
+	re.NoError(err)
+	re.Equal([]uint64{3, 4}, page)
+	re.Equal("5", next)
+
+	// no limit means all the items from the token.
+	q.Limit = 0
+	page, next, err = PaginateByID(items, q, identity)
+	re.NoError(err)
+	re.Equal([]uint64{3, 4, 5}, page)
+	re.Empty(next)
+
+	q.Token = "x"
+	_, _, err = PaginateByID(items, q, identity)
+	re.Error(err)
+}
@@ -148,8 +148,8 @@ func EtcdKVGet(c *clientv3.Client, key string, opts ...clientv3.OpOption) (*clie
 		d := val.(int)
 		time.Sleep(time.Duration(d) * time.Second)
 	})
-	resp, err := clientv3.NewKV(c).Get(ctx, key, opts...)
-	if cost := time.Since(start); cost > DefaultSlowRequestTime {
+	resp, err := c.Get(ctx, key, opts...)
+	if cost := time.Since(start); recordSlowOp(SlowOpGet, key, getResponseSize(resp), cost, err) {
 		log.Warn("kv gets too slow", zap.String("request-key", key), zap.Duration("cost", cost), errs.ZapError(err))
 	}
 
@@ -161,6 +161,17 @@ func EtcdKVGet(c *clientv3.Client, key string, opts ...clientv3.OpOption) (*clie
 	return resp, nil
 }
 
+func getResponseSize(resp *clientv3.GetResponse) int {
+	if resp == nil {
+		return 0
+	}
+	size := 0
+	for _, kv := range resp.Kvs {
+		size += len(kv.Key) + len(kv.Value)
+	}
+	return size
+}
+
 // IsHealthy checks if the etcd is healthy.
 func IsHealthy(ctx context.Context, client *clientv3.Client) bool {
 	timeout := DefaultRequestTimeout
@@ -219,12 +230,11 @@ func GetProtoMsgWithModRev(c *clientv3.Client, key string, msg proto.Message, op
 
 // EtcdKVPutWithTTL put (key, value) into etcd with a ttl of ttlSeconds
 func EtcdKVPutWithTTL(ctx context.Context, c *clientv3.Client, key string, value string, ttlSeconds int64) (clientv3.LeaseID, error) {
-	kv := clientv3.NewKV(c)
 	grantResp, err := c.Grant(ctx, ttlSeconds)
 	if err != nil {
 		return 0, err
 	}
-	_, err = kv.Put(ctx, key, value, clientv3.WithLease(grantResp.ID))
+	_, err = c.Put(ctx, key, value, clientv3.WithLease(grantResp.ID))
 	return grantResp.ID, err
 }
 
@@ -403,6 +413,11 @@ type LoopWatcher struct {
 	postEventsFn func([]*clientv3.Event) error
 	// preEventsFn is used to call before handling all events.
 	preEventsFn func([]*clientv3.Event) error
+	// loadStartFn and loadFinishedFn are used to call before and after loading all
+	// the keys from etcd. They are only used by the Informer to find out the keys
+	// deleted between two loads.
+	loadStartFn    func()
+	loadFinishedFn func()
 
 	// forceLoadMu is used to ensure two force loads have minimal interval.
 	forceLoadMu syncutil.RWMutex
@@ -548,7 +563,7 @@ func (lw *LoopWatcher) watch(ctx context.Context, revision int64) (nextRevision
 		if watcher != nil {
 			watcher.Close()
 		}
-		watcher = clientv3.NewWatcher(lw.client)
+		watcher = NewWatcher(lw.client)
 		// In order to prevent a watch stream being stuck in a partitioned node,
 		// make sure to wrap context with "WithRequireLeader".
 		watcherCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
@@ -680,6 +695,9 @@ func (lw *LoopWatcher) load(ctx context.Context) (nextRevision int64, err error)
 				zap.String("key", lw.key), zap.Error(err))
 		}
 	}()
+	if lw.loadStartFn != nil {
+		lw.loadStartFn()
+	}
 
 	for {
 		select {
@@ -730,6 +748,9 @@ func (lw *LoopWatcher) load(ctx context.Context) (nextRevision int64, err error)
 		}
 		// Note: if there are no keys in etcd, the resp.More is false. It also means the load is finished.
 		if !resp.More {
+			if lw.loadFinishedFn != nil {
+				lw.loadFinishedFn()
+			}
 			return resp.Header.Revision + 1, err
 		}
 	}
@@ -17,11 +17,13 @@ package etcdutil
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
 	"net"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -552,6 +554,107 @@ func (suite *loopWatcherTestSuite) TestCallBack() {
 	cache.RUnlock()
 }
 
+func (suite *loopWatcherTestSuite) TestInformer() {
+	re := suite.Require()
+	var (
+		mu        syncutil.Mutex
+		events    []string
+		failTimes int
+	)
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+	checkEvents := func(expected ...string) {
+		testutil.Eventually(re, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return fmt.Sprint(events) == fmt.Sprint(expected)
+		})
+		mu.Lock()
+		events = events[:0]
+		mu.Unlock()
+	}
+	suite.put(re, "TestInformer/a", "1")
+	inf := NewInformer(
+		suite.ctx,
+		&suite.wg,
+		suite.client,
+		"test",
+		"TestInformer/",
+		func(kv *mvccpb.KeyValue) (int, error) {
+			return strconv.Atoi(string(kv.Value))
+		},
+		InformerHandler[int]{
+			OnAdd: func(key string, obj int) error {
+				mu.Lock()
+				if failTimes > 0 {
+					failTimes--
+					mu.Unlock()
+					return errors.New("injected failure")
+				}
+				mu.Unlock()
+				record(fmt.Sprintf("add %s %d", key, obj))
+				return nil
+			},
+			OnUpdate: func(key string, oldObj, newObj int) error {
+				record(fmt.Sprintf("update %s %d->%d", key, oldObj, newObj))
+				return nil
+			},
+			OnDelete: func(key string, obj int) error {
+				record(fmt.Sprintf("delete %s %d", key, obj))
+				return nil
+			},
+		},
+		0, /* no periodic resync */
+	)
+	inf.StartWatchLoop()
+	re.NoError(inf.WaitLoad())
+	checkEvents("add TestInformer/a 1")
+
+	suite.put(re, "TestInformer/b", "2")
+	suite.put(re, "TestInformer/a", "3")
+	// The key which fails to be decoded is skipped.
+	suite.put(re, "TestInformer/c", "x")
+	checkEvents("add TestInformer/b 2", "update TestInformer/a 1->3")
+	obj, ok := inf.Get("TestInformer/a")
+	re.True(ok)
+	re.Equal(3, obj)
+	re.Equal([]int{3, 2}, inf.List())
+
+	_, err := suite.client.Delete(suite.ctx, "TestInformer/b")
+	re.NoError(err)
+	checkEvents("delete TestInformer/b 2")
+	re.Equal(1, inf.Len())
+
+	// Simulate a missed deletion, the key should be deleted after relisting.
+	inf.mu.Lock()
+	inf.cache["TestInformer/d"] = 4
+	inf.mu.Unlock()
+	testutil.Eventually(re, func() bool {
+		inf.ForceLoad()
+		_, ok := inf.Get("TestInformer/d")
+		return !ok
+	})
+	checkEvents("update TestInformer/a 3->3", "delete TestInformer/d 4")
+	re.Equal([]int{3}, inf.List())
+
+	// The failed handler is retried by relisting.
+	mu.Lock()
+	failTimes = 2
+	mu.Unlock()
+	suite.put(re, "TestInformer/e", "5")
+	testutil.Eventually(re, func() bool {
+		_, ok := inf.Get("TestInformer/e")
+		return ok
+	})
+	mu.Lock()
+	re.Zero(failTimes)
+	re.Contains(events, "add TestInformer/e 5")
+	mu.Unlock()
+}
+
 func (suite *loopWatcherTestSuite) TestWatcherLoadLimit() {
 	re := suite.Require()
 	for count := 1; count < 10; count++ {
@@ -0,0 +1,338 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutil
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/utils/logutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.etcd.io/etcd/clientv3"
+	"go.etcd.io/etcd/mvcc/mvccpb"
+	"go.uber.org/zap"
+)
+
+const (
+	informerEventAdd          = "add"
+	informerEventUpdate       = "update"
+	informerEventDelete       = "delete"
+	informerEventDecodeFailed = "decode-failed"
+	informerEventHandleFailed = "handle-failed"
+	informerEventRetryGiveUp  = "retry-give-up"
+
+	// maxInformerRetryTimes is the max times to relist the keys in a row for
+	// the failed handlers, the next periodic resync retries them again.
+	maxInformerRetryTimes = 3
+)
+
+// InformerHandler handles the changes of the objects cached by an Informer.
+// All the handlers are called sequentially in the watch loop, and any of them could be nil.
+// If a handler returns an error, the cache is kept as before the change, and the keys are
+// relisted with backoff to deliver the change again, see maxInformerRetryTimes.
+type InformerHandler[T any] struct {
+	OnAdd    func(key string, obj T) error
+	OnUpdate func(key string, oldObj, newObj T) error
+	// OnDelete is called with the last cached object of the deleted key.
+	OnDelete func(key string, obj T) error
+}
+
+// Informer keeps a local cache of the objects decoded from the keys under an etcd prefix
+// with the list+watch semantics: the keys are listed from etcd first and then kept updated
+// by the watch events. To recover from the missed events, e.g. the deletions compacted
+// before being watched, the keys are relisted periodically, and the cached keys missing
+// from the relisting are treated as deleted.
+//
+// It fits the watchers handling each key on its own. The watchers which apply a batch of
+// events as a whole, e.g. the rule watchers locking the rule manager across the events,
+// or which watch a single key, e.g. the config watchers, still use the LoopWatcher.
+type Informer[T any] struct {
+	ctx     context.Context
+	wg      *sync.WaitGroup
+	name    string
+	watcher *LoopWatcher
+	decode  func(*mvccpb.KeyValue) (T, error)
+	handler InformerHandler[T]
+	// resyncInterval is the interval to relist the keys from etcd, 0 means no periodic resync.
+	resyncInterval time.Duration
+
+	mu    syncutil.RWMutex
+	cache map[string]T
+	// listed records the keys put during the ongoing listing.
+	// It is only accessed in the watch loop so it needs no lock.
+	listed map[string]struct{}
+	// failed and retryTimes record the failed handlers since the last retry, and
+	// the retries in a row. They are only accessed in the watch loop.
+	failed     bool
+	retryTimes int
+	// retryCh passes the backoff of the next retry to the resync loop.
+	retryCh chan time.Duration
+}
+
+// NewInformer creates a new Informer for the keys under the prefix.
+func NewInformer[T any](
+	ctx context.Context, wg *sync.WaitGroup,
+	client *clientv3.Client,
+	name, prefix string,
+	decode func(*mvccpb.KeyValue) (T, error),
+	handler InformerHandler[T],
+	resyncInterval time.Duration,
+) *Informer[T] {
+	inf := &Informer[T]{
+		ctx:            ctx,
+		wg:             wg,
+		name:           name,
+		decode:         decode,
+		handler:        handler,
+		resyncInterval: resyncInterval,
+		cache:          make(map[string]T),
+		retryCh:        make(chan time.Duration, 1),
+	}
+	inf.watcher = NewLoopWatcher(
+		ctx, wg,
+		client,
+		name, prefix,
+		func([]*clientv3.Event) error { return nil },
+		inf.put, inf.delete,
+		inf.postEvents,
+		true, /* withPrefix */
+	)
+	inf.watcher.loadStartFn = func() {
+		inf.listed = make(map[string]struct{})
+	}
+	inf.watcher.loadFinishedFn = inf.finishListing
+	return inf
+}
+
+// StartWatchLoop starts the watch loop and the resync loop of the informer.
+func (inf *Informer[T]) StartWatchLoop() {
+	inf.watcher.StartWatchLoop()
+	inf.wg.Add(1)
+	go inf.resyncLoop()
+}
+
+// WaitLoad waits for the result to obtain whether the keys are listed for the first time.
+func (inf *Informer[T]) WaitLoad() error {
+	return inf.watcher.WaitLoad()
+}
+
+// ForceLoad forces to relist the keys from etcd.
+func (inf *Informer[T]) ForceLoad() {
+	inf.watcher.ForceLoad()
+}
+
+// SetLoadRetryTimes sets the retry times when listing the keys for the first time.
+func (inf *Informer[T]) SetLoadRetryTimes(times int) {
+	inf.watcher.SetLoadRetryTimes(times)
+}
+
+// Get returns the cached object of the key.
+func (inf *Informer[T]) Get(key string) (T, bool) {
+	inf.mu.RLock()
+	defer inf.mu.RUnlock()
+	obj, ok := inf.cache[key]
+	return obj, ok
+}
+
+// List returns all the cached objects in the order of their keys.
+func (inf *Informer[T]) List() []T {
+	inf.mu.RLock()
+	defer inf.mu.RUnlock()
+	keys := make([]string, 0, len(inf.cache))
+	for key := range inf.cache {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	objs := make([]T, 0, len(keys))
+	for _, key := range keys {
+		objs = append(objs, inf.cache[key])
+	}
+	return objs
+}
+
+// Len returns the number of the cached objects.
+func (inf *Informer[T]) Len() int {
+	inf.mu.RLock()
+	defer inf.mu.RUnlock()
+	return len(inf.cache)
+}
+
+func (inf *Informer[T]) resyncLoop() {
+	defer logutil.LogPanic()
+	defer inf.wg.Done()
+
+	var resyncC <-chan time.Time
+	if inf.resyncInterval > 0 {
+		ticker := time.NewTicker(inf.resyncInterval)
+		defer ticker.Stop()
+		resyncC = ticker.C
+	}
+	var retryTimer *time.Timer
+	var retryC <-chan time.Time
+	defer func() {
+		if retryTimer != nil {
+			retryTimer.Stop()
+		}
+	}()
+	for {
+		select {
+		case <-inf.ctx.Done():
+			log.Info("exit the resync loop of informer", zap.String("name", inf.name))
+			return
+		case <-resyncC:
+			inf.watcher.ForceLoad()
+		case backoff := <-inf.retryCh:
+			if retryTimer != nil {
+				retryTimer.Stop()
+			}
+			retryTimer = time.NewTimer(backoff)
+			retryC = retryTimer.C
+		case <-retryC:
+			retryC = nil
+			inf.watcher.ForceLoad()
+		}
+	}
+}
+
+func (inf *Informer[T]) put(kv *mvccpb.KeyValue) error {
+	key := string(kv.Key)
+	obj, err := inf.decode(kv)
+	if err != nil {
+		// The key is not recorded as listed, so the stale object will be removed after the listing.
+		informerEventCounter.WithLabelValues(inf.name, informerEventDecodeFailed).Inc()
+		log.Warn("failed to decode the object in informer",
+			zap.String("name", inf.name), zap.String("key", key), zap.Error(err))
+		return nil
+	}
+	if inf.listed != nil {
+		inf.listed[key] = struct{}{}
+	}
+	inf.mu.Lock()
+	oldObj, ok := inf.cache[key]
+	inf.cache[key] = obj
+	inf.mu.Unlock()
+
+	event := informerEventAdd
+	if ok {
+		event = informerEventUpdate
+		if inf.handler.OnUpdate != nil {
+			err = inf.handler.OnUpdate(key, oldObj, obj)
+		}
+	} else if inf.handler.OnAdd != nil {
+		err = inf.handler.OnAdd(key, obj)
+	}
+	if err != nil {
+		// Restore the cache, so the same change is delivered by the retry.
+		inf.mu.Lock()
+		if ok {
+			inf.cache[key] = oldObj
+		} else {
+			delete(inf.cache, key)
+		}
+		inf.mu.Unlock()
+		inf.handleFailed(key, event, err)
+		return nil
+	}
+	inf.mu.RLock()
+	informerCacheSizeGauge.WithLabelValues(inf.name).Set(float64(len(inf.cache)))
+	inf.mu.RUnlock()
+	informerEventCounter.WithLabelValues(inf.name, event).Inc()
+	return nil
+}
+
+func (inf *Informer[T]) delete(kv *mvccpb.KeyValue) error {
+	inf.deleteKey(string(kv.Key))
+	return nil
+}
+
+func (inf *Informer[T]) deleteKey(key string) {
+	inf.mu.RLock()
+	obj, ok := inf.cache[key]
+	inf.mu.RUnlock()
+	if !ok {
+		return
+	}
+	if inf.handler.OnDelete != nil {
+		if err := inf.handler.OnDelete(key, obj); err != nil {
+			// Keep the object, so the relisting finds it missing and deletes it again.
+			inf.handleFailed(key, informerEventDelete, err)
+			return
+		}
+	}
+	inf.mu.Lock()
+	delete(inf.cache, key)
+	informerCacheSizeGauge.WithLabelValues(inf.name).Set(float64(len(inf.cache)))
+	inf.mu.Unlock()
+	informerEventCounter.WithLabelValues(inf.name, informerEventDelete).Inc()
+}
+
+func (inf *Informer[T]) handleFailed(key, event string, err error) {
+	inf.failed = true
+	informerEventCounter.WithLabelValues(inf.name, informerEventHandleFailed).Inc()
+	log.Warn("failed to handle the event in informer",
+		zap.String("name", inf.name), zap.String("key", key), zap.String("event", event), zap.Error(err))
+}
+
+// postEvents schedules a retry if any handler fails in the handled events.
+func (inf *Informer[T]) postEvents([]*clientv3.Event) error {
+	if !inf.failed {
+		return nil
+	}
+	inf.failed = false
+	if inf.retryTimes >= maxInformerRetryTimes {
+		informerEventCounter.WithLabelValues(inf.name, informerEventRetryGiveUp).Inc()
+		log.Warn("stop retrying the failed events in informer until the next resync",
+			zap.String("name", inf.name), zap.Int("retry-times", inf.retryTimes))
+		return nil
+	}
+	inf.retryTimes++
+	backoff := defaultEtcdRetryInterval << (inf.retryTimes - 1)
+	select {
+	case inf.retryCh <- backoff:
+	default:
+	}
+	return nil
+}
+
+// finishListing deletes the cached keys which are missing from the finished listing.
+func (inf *Informer[T]) finishListing() {
+	listed := inf.listed
+	inf.listed = nil
+	if listed == nil {
+		return
+	}
+	inf.mu.RLock()
+	deleted := make([]string, 0)
+	for key := range inf.cache {
+		if _, ok := listed[key]; !ok {
+			deleted = append(deleted, key)
+		}
+	}
+	inf.mu.RUnlock()
+
+	for _, key := range deleted {
+		log.Info("delete the key missing from the listing in informer",
+			zap.String("name", inf.name), zap.String("key", key))
+		inf.deleteKey(key)
+	}
+	// All the keys are delivered successfully, so the retries are finished.
+	if !inf.failed {
+		inf.retryTimes = 0
+	}
+	informerResyncCounter.WithLabelValues(inf.name).Inc()
+}
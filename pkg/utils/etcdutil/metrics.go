@@ -20,6 +20,7 @@ var (
 	sourceLabel   = "source"
 	typeLabel     = "type"
 	endpointLabel = "endpoint"
+	nameLabel     = "name"
 )
 
 var (
@@ -39,9 +40,45 @@ var (
 			Help:      "Bucketed histogram of latency of health check.",
 			Buckets:   []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 		}, []string{sourceLabel, endpointLabel})
+
+	etcdSlowOpCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "etcd_slow_operations_total",
+			Help:      "Counter of the etcd operations whose latency exceeds the threshold.",
+		}, []string{typeLabel})
+
+	informerEventCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "etcd_informer_events_total",
+			Help:      "Counter of the cache events handled by the etcd informers.",
+		}, []string{nameLabel, typeLabel})
+
+	informerCacheSizeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "etcd_informer_cache_size",
+			Help:      "The number of the objects cached by the etcd informers.",
+		}, []string{nameLabel})
+
+	informerResyncCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "etcd_informer_resyncs_total",
+			Help:      "Counter of the full listings finished by the etcd informers.",
+		}, []string{nameLabel})
 )
 
 func init() {
 	prometheus.MustRegister(etcdStateGauge)
 	prometheus.MustRegister(etcdEndpointLatency)
+	prometheus.MustRegister(etcdSlowOpCounter)
+	prometheus.MustRegister(informerEventCounter)
+	prometheus.MustRegister(informerCacheSizeGauge)
+	prometheus.MustRegister(informerResyncCounter)
 }
@@ -0,0 +1,50 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutil
+
+import (
+	"go.etcd.io/etcd/clientv3"
+	"go.etcd.io/etcd/clientv3/namespace"
+)
+
+// namespacedWatcher is the watcher of a namespaced client, it keeps the prefix
+// so the new watchers created by NewWatcher are namespaced too.
+type namespacedWatcher struct {
+	clientv3.Watcher
+	prefix string
+}
+
+// WithNamespace makes all the keys accessed through the client prefixed with
+// the given prefix, e.g. "/pd/cluster_id" is stored as "<prefix>/pd/cluster_id",
+// so the clients with different prefixes can share one etcd without affecting
+// each other. It should be called before the client is used.
+func WithNamespace(client *clientv3.Client, prefix string) {
+	client.KV = namespace.NewKV(client.KV, prefix)
+	client.Lease = namespace.NewLease(client.Lease, prefix)
+	client.Watcher = &namespacedWatcher{
+		Watcher: namespace.NewWatcher(client.Watcher, prefix),
+		prefix:  prefix,
+	}
+}
+
+// NewWatcher creates a new watcher of the client, which can be closed without
+// affecting the other watchers. It's namespaced if the client is namespaced.
+func NewWatcher(client *clientv3.Client) clientv3.Watcher {
+	watcher := clientv3.NewWatcher(client)
+	if w, ok := client.Watcher.(*namespacedWatcher); ok {
+		return namespace.NewWatcher(watcher, w.prefix)
+	}
+	return watcher
+}
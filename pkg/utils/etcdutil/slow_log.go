@@ -0,0 +1,224 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutil
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+const (
+	// maxSlowOps is the max number of the slow operations kept in the slow log.
+	maxSlowOps = 1024
+	// slowOpPrefixDepth is the number of the leading key segments used to attribute the operation.
+	slowOpPrefixDepth = 4
+	// slowOpIDSegment replaces the numeric key segments, e.g. the cluster ID and the region ID.
+	slowOpIDSegment = "{id}"
+)
+
+// The types of the slow etcd operations.
+const (
+	SlowOpGet   = "get"
+	SlowOpTxn   = "txn"
+	SlowOpLease = "lease"
+)
+
+// SlowOp is an etcd operation whose latency exceeds the threshold.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type SlowOp struct {
+	Type   string        `json:"type"`
+	Key    string        `json:"key"`
+	Prefix string        `json:"prefix"`
+	Size   int           `json:"size"`
+	Cost   time.Duration `json:"cost"`
+	Caller string        `json:"caller"`
+	Error  string        `json:"error,omitempty"`
+	Time   time.Time     `json:"time"`
+}
+
+// SlowOpPrefixStats is the statistics of the slow etcd operations of a key prefix.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type SlowOpPrefixStats struct {
+	Prefix    string        `json:"prefix"`
+	Count     int           `json:"count"`
+	TotalCost time.Duration `json:"total-cost"`
+	MaxCost   time.Duration `json:"max-cost"`
+	TotalSize int           `json:"total-size"`
+}
+
+// slowOpLog keeps the recent slow etcd operations in a ring buffer.
+type slowOpLog struct {
+	syncutil.RWMutex
+	threshold time.Duration
+	ops       []SlowOp
+	next      int
+}
+
+var globalSlowOpLog = &slowOpLog{threshold: DefaultSlowRequestTime}
+
+// SetSlowOpThreshold sets the latency threshold of the slow etcd operations.
+func SetSlowOpThreshold(threshold time.Duration) {
+	globalSlowOpLog.Lock()
+	defer globalSlowOpLog.Unlock()
+	globalSlowOpLog.threshold = threshold
+}
+
+// GetSlowOpThreshold returns the latency threshold of the slow etcd operations.
+func GetSlowOpThreshold() time.Duration {
+	globalSlowOpLog.RLock()
+	defer globalSlowOpLog.RUnlock()
+	return globalSlowOpLog.threshold
+}
+
+// recordSlowOp records the operation into the slow log if its cost exceeds the
+// threshold, it returns whether the operation is slow.
+func recordSlowOp(opType, key string, size int, cost time.Duration, err error) bool {
+	if cost <= GetSlowOpThreshold() {
+		return false
+	}
+	op := SlowOp{
+		Type:   opType,
+		Key:    key,
+		Prefix: keyPrefix(key),
+		Size:   size,
+		Cost:   cost,
+		Caller: slowOpCaller(),
+		Time:   time.Now(),
+	}
+	if err != nil {
+		op.Error = err.Error()
+	}
+	etcdSlowOpCounter.WithLabelValues(opType).Inc()
+	globalSlowOpLog.Lock()
+	defer globalSlowOpLog.Unlock()
+	if len(globalSlowOpLog.ops) < maxSlowOps {
+		globalSlowOpLog.ops = append(globalSlowOpLog.ops, op)
+	} else {
+		globalSlowOpLog.ops[globalSlowOpLog.next] = op
+	}
+	globalSlowOpLog.next = (globalSlowOpLog.next + 1) % maxSlowOps
+	return true
+}
+
+// RecordSlowTxn records the etcd transaction into the slow log if it's slow.
+func RecordSlowTxn(key string, size int, cost time.Duration, err error) bool {
+	return recordSlowOp(SlowOpTxn, key, size, cost, err)
+}
+
+// RecordSlowLease records the etcd lease operation into the slow log if it's slow.
+func RecordSlowLease(purpose string, cost time.Duration, err error) bool {
+	return recordSlowOp(SlowOpLease, purpose, 0, cost, err)
+}
+
+// GetSlowOps returns the recent slow etcd operations, the newest first.
+// Only the operations whose key has the given prefix are returned if it's not empty.
+func GetSlowOps(prefix string) []SlowOp {
+	globalSlowOpLog.RLock()
+	defer globalSlowOpLog.RUnlock()
+	n := len(globalSlowOpLog.ops)
+	ops := make([]SlowOp, 0, n)
+	for i := 1; i <= n; i++ {
+		op := globalSlowOpLog.ops[(globalSlowOpLog.next-i+n)%n]
+		if strings.HasPrefix(op.Key, prefix) {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// GetSlowOpPrefixStats aggregates the recent slow etcd operations by the key
+// prefix, the prefix with the most total cost first.
+func GetSlowOpPrefixStats() []SlowOpPrefixStats {
+	statsMap := make(map[string]*SlowOpPrefixStats)
+	for _, op := range GetSlowOps("") {
+		s, ok := statsMap[op.Prefix]
+		if !ok {
+			s = &SlowOpPrefixStats{Prefix: op.Prefix}
+			statsMap[op.Prefix] = s
+		}
+		s.Count++
+		s.TotalCost += op.Cost
+		s.TotalSize += op.Size
+		if op.Cost > s.MaxCost {
+			s.MaxCost = op.Cost
+		}
+	}
+	stats := make([]SlowOpPrefixStats, 0, len(statsMap))
+	for _, s := range statsMap {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalCost != stats[j].TotalCost {
+			return stats[i].TotalCost > stats[j].TotalCost
+		}
+		return stats[i].Prefix < stats[j].Prefix
+	})
+	return stats
+}
+
+// keyPrefix returns the leading segments of the key with the numeric segments
+// replaced, so the operations on the same kind of the keys share the prefix.
+// e.g. "/pd/7187976276065784319/raft/r/00000000000000000002" -> "/pd/{id}/raft/r".
+func keyPrefix(key string) string {
+	segments := strings.Split(key, "/")
+	depth := slowOpPrefixDepth
+	if strings.HasPrefix(key, "/") {
+		depth++
+	}
+	if len(segments) > depth {
+		segments = segments[:depth]
+	}
+	for i, s := range segments {
+		if isNumeric(s) {
+			segments[i] = slowOpIDSegment
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isNumeric(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// slowOpCaller returns the first caller outside the etcd wrappers.
+func slowOpCaller() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.Contains(frame.Function, "pkg/utils/etcdutil.") &&
+			!strings.Contains(frame.Function, "pkg/storage/kv.") &&
+			!strings.Contains(frame.Function, "pkg/election.") {
+			return fmt.Sprintf("%s:%d", frame.Function, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func resetSlowOpLog() {
+	globalSlowOpLog.Lock()
+	defer globalSlowOpLog.Unlock()
+	globalSlowOpLog.threshold = DefaultSlowRequestTime
+	globalSlowOpLog.ops = nil
+	globalSlowOpLog.next = 0
+}
+
+func TestSlowOpLog(t *testing.T) {
+	re := require.New(t)
+	// The log is shared by the whole process, so start from an empty one.
+	resetSlowOpLog()
+	defer resetSlowOpLog()
+	re.Equal("/pd/{id}/raft/r", keyPrefix("/pd/7187976276065784319/raft/r/00000000000000000002"))
+	re.Equal("/pd/{id}/gc", keyPrefix("/pd/7187976276065784319/gc"))
+	re.Equal("resource_group/settings", keyPrefix("resource_group/settings"))
+
+	re.False(recordSlowOp(SlowOpGet, "/pd/1/raft/s/1", 10, time.Millisecond, nil))
+	re.True(recordSlowOp(SlowOpGet, "/pd/1/raft/r/1", 10, 2*time.Second, nil))
+	re.True(RecordSlowTxn("/pd/1/raft/r/2", 20, 3*time.Second, nil))
+	re.True(RecordSlowTxn("/pd/1/gc/safe_point", 5, 4*time.Second, nil))
+
+	ops := GetSlowOps("/pd/1/raft")
+	re.Len(ops, 2)
+	// The newest first.
+	re.Equal(SlowOpTxn, ops[0].Type)
+	re.Equal(SlowOpGet, ops[1].Type)
+
+	stats := GetSlowOpPrefixStats()
+	re.Len(stats, 2)
+	re.Equal("/pd/{id}/raft/r", stats[0].Prefix)
+	re.Equal(2, stats[0].Count)
+	re.Equal(5*time.Second, stats[0].TotalCost)
+	re.Equal(3*time.Second, stats[0].MaxCost)
+	re.Equal(30, stats[0].TotalSize)
+	re.Equal("/pd/{id}/gc/safe_point", stats[1].Prefix)
+
+	// The oldest operations are overwritten once the log is full.
+	for i := 0; i < maxSlowOps; i++ {
+		recordSlowOp(SlowOpGet, "/pd/1/config", 1, 2*time.Second, nil)
+	}
+	re.Len(GetSlowOps(""), maxSlowOps)
+	re.Empty(GetSlowOps("/pd/1/raft"))
+}
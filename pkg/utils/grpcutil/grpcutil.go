@@ -20,6 +20,7 @@ import (
 	"crypto/x509"
 	"io"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -42,6 +43,17 @@ const (
 	ForwardMetadataKey = "pd-forwarded-host"
 	// FollowerHandleMetadataKey is used to mark the permit of follower handle.
 	FollowerHandleMetadataKey = "pd-allow-follower-handle"
+	// ClusterStateEpochMetadataKey is used to carry the cluster state epoch in the response header.
+	ClusterStateEpochMetadataKey = "pd-cluster-state-epoch"
+	// ComponentMetadataKey is used to record the component of the caller, e.g. tidb, cdc or br.
+	ComponentMetadataKey = "pd-component"
+	// ClientVersionMetadataKey is used to record the version of the caller component.
+	ClientVersionMetadataKey = "pd-client-version"
+	// KeyspaceMetadataKey is used to record the keyspace of the caller.
+	KeyspaceMetadataKey = "pd-keyspace"
+	// MaxStalenessMetadataKey is used to bound the staleness of the region
+	// metadata served by the followers, in milliseconds.
+	MaxStalenessMetadataKey = "pd-max-staleness"
 )
 
 // TLSConfig is the configuration for supporting tls.
@@ -193,6 +205,56 @@ func GetForwardedHost(ctx context.Context) string {
 	return ""
 }
 
+// GetComponent returns the component of the caller in metadata.
+func GetComponent(ctx context.Context) string {
+	s := metadata.ValueFromIncomingContext(ctx, ComponentMetadataKey)
+	if len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
+
+// GetClientVersion returns the version of the caller component in metadata.
+func GetClientVersion(ctx context.Context) string {
+	s := metadata.ValueFromIncomingContext(ctx, ClientVersionMetadataKey)
+	if len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
+
+// GetKeyspace returns the keyspace of the caller in metadata.
+func GetKeyspace(ctx context.Context) string {
+	s := metadata.ValueFromIncomingContext(ctx, KeyspaceMetadataKey)
+	if len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
+
+// BuildComponentContext creates a context with the component of the caller in
+// the outgoing metadata, the context is not changed if the component is empty.
+func BuildComponentContext(ctx context.Context, component string) context.Context {
+	if component == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, ComponentMetadataKey, component)
+}
+
+// GetMaxStaleness returns the max staleness of the region metadata which the
+// caller accepts from the followers, it returns false if it's not given.
+func GetMaxStaleness(ctx context.Context) (time.Duration, bool) {
+	s := metadata.ValueFromIncomingContext(ctx, MaxStalenessMetadataKey)
+	if len(s) == 0 {
+		return 0, false
+	}
+	ms, err := strconv.ParseInt(s[0], 10, 64)
+	if err != nil || ms <= 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
 // IsFollowerHandleEnabled returns the follower host in metadata.
 func IsFollowerHandleEnabled(ctx context.Context) bool {
 	md, ok := metadata.FromIncomingContext(ctx)
@@ -0,0 +1,179 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tempurl
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+// PortSeedEnv is the environment variable to specify the seed of the port range
+// reserved for the test binary, the process ID is used if it's not set.
+const PortSeedEnv = "PD_TEST_PORT_SEED"
+
+const (
+	// The ports are allocated from [portRangeBegin, portRangeEnd), which stays
+	// below the ephemeral port range of Linux to avoid colliding with the ports
+	// randomly picked by the kernel.
+	portRangeBegin = 10000
+	portRangeEnd   = 30000
+	portBlockSize  = 200
+	portBlocks     = (portRangeEnd - portRangeBegin) / portBlockSize
+)
+
+var (
+	defaultPortAllocator     *PortAllocator
+	defaultPortAllocatorErr  error
+	defaultPortAllocatorOnce sync.Once
+)
+
+// PortAllocator allocates local URLs from a block of ports reserved for the test
+// binary. The block is claimed by listening on its first port until the process
+// exits, so the other test binaries skip it and claim the next free block. The
+// seed chooses the first block to try, so the allocation is reproducible with
+// the same seed if the block is free.
+//
+// The allocated ports are kept listened on until they are released right before
+// the servers listen on them, see Release.
+type PortAllocator struct {
+	mu         syncutil.Mutex
+	firstBlock int
+	begin      int
+	end        int
+	next       int
+	guard      net.Listener
+	held       map[string]net.Listener
+}
+
+// NewPortAllocator creates a PortAllocator which tries the port block chosen by
+// the seed first.
+func NewPortAllocator(seed int64) *PortAllocator {
+	return &PortAllocator{
+		firstBlock: int(uint64(seed) % portBlocks),
+		held:       make(map[string]net.Listener),
+	}
+}
+
+// Alloc allocates a local URL whose port is in the claimed block. The ports are
+// allocated in order and the ones in use are skipped. The port is kept listened
+// on until it's released.
+func (a *PortAllocator) Alloc() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.guard == nil {
+		if err := a.claimBlock(); err != nil {
+			return "", err
+		}
+	}
+	for ; a.next < a.end; a.next++ {
+		addr := fmt.Sprintf("http://127.0.0.1:%d", a.next)
+		if a.reserve(addr) {
+			a.next++
+			return addr, nil
+		}
+	}
+	return "", errors.Errorf("the reserved ports [%d, %d) are exhausted", a.begin, a.end)
+}
+
+// Release stops listening on the port of the allocated URL, so that the server
+// can listen on it. It's a no-op if the URL is not held by the allocator.
+func (a *PortAllocator) Release(addr string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if l, ok := a.held[addr]; ok {
+		l.Close()
+		delete(a.held, addr)
+	}
+}
+
+// claimBlock claims the first free block from the one chosen by the seed, the
+// first port of the block is used as the guard and never allocated.
+func (a *PortAllocator) claimBlock() error {
+	for i := 0; i < portBlocks; i++ {
+		begin := portRangeBegin + (a.firstBlock+i)%portBlocks*portBlockSize
+		guard, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", begin))
+		if err != nil {
+			continue
+		}
+		a.guard = guard
+		a.begin, a.end, a.next = begin, begin+portBlockSize, begin+1
+		return nil
+	}
+	return errors.Errorf("no free port block in [%d, %d)", portRangeBegin, portRangeEnd)
+}
+
+func (a *PortAllocator) reserve(addr string) bool {
+	testAddrMutex.Lock()
+	defer testAddrMutex.Unlock()
+	if _, ok := testAddrMap[addr]; ok {
+		return false
+	}
+	// check before listening, otherwise the listener itself is found.
+	if !environmentCheck(addr) {
+		return false
+	}
+	l, err := net.Listen("tcp", addr[len("http://"):])
+	if err != nil {
+		return false
+	}
+	a.held[addr] = l
+	testAddrMap[addr] = struct{}{}
+	return true
+}
+
+// AllocReserved allocates a local URL for testing from the port block reserved
+// for the test binary, see PortSeedEnv for how the block is chosen. The URL from
+// the UT allocator is preferred if it's available. The URL should be released by
+// ReleaseReserved right before the server listens on it.
+func AllocReserved() (string, error) {
+	if url := getFromUT(); url != "" {
+		return url, nil
+	}
+	a, err := getDefaultPortAllocator()
+	if err != nil {
+		return "", err
+	}
+	return a.Alloc()
+}
+
+// ReleaseReserved releases the URL allocated by AllocReserved, it's a no-op for
+// the other URLs.
+func ReleaseReserved(addr string) {
+	if a, err := getDefaultPortAllocator(); err == nil {
+		a.Release(addr)
+	}
+}
+
+func getDefaultPortAllocator() (*PortAllocator, error) {
+	defaultPortAllocatorOnce.Do(func() {
+		seed := int64(os.Getpid())
+		if s := os.Getenv(PortSeedEnv); s != "" {
+			v, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				defaultPortAllocatorErr = errors.Annotatef(err, "invalid %s %q", PortSeedEnv, s)
+				return
+			}
+			seed = v
+		}
+		defaultPortAllocator = NewPortAllocator(seed)
+	})
+	return defaultPortAllocator, defaultPortAllocatorErr
+}
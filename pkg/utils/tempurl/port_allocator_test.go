@@ -0,0 +1,74 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tempurl
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortAllocator(t *testing.T) {
+	re := require.New(t)
+	// the same seed tries the same block first.
+	re.Equal(NewPortAllocator(1).firstBlock, NewPortAllocator(1).firstBlock)
+	re.NotEqual(NewPortAllocator(1).firstBlock, NewPortAllocator(2).firstBlock)
+	re.Equal(NewPortAllocator(1).firstBlock, NewPortAllocator(1+portBlocks).firstBlock)
+
+	a := NewPortAllocator(7)
+	port := func(addr string) int {
+		u, err := url.Parse(addr)
+		re.NoError(err)
+		p, err := strconv.Atoi(u.Port())
+		re.NoError(err)
+		return p
+	}
+	addr1, err := a.Alloc()
+	re.NoError(err)
+	defer a.guard.Close()
+	re.Greater(port(addr1), a.begin)
+	re.Less(port(addr1), a.end)
+
+	// the allocated port is held until it's released.
+	_, err = net.Listen("tcp", addr1[len("http://"):])
+	re.Error(err)
+	a.Release(addr1)
+	l, err := net.Listen("tcp", addr1[len("http://"):])
+	re.NoError(err)
+	re.NoError(l.Close())
+	a.Release(addr1)
+
+	// the port in use is skipped.
+	l, err = net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(a.next))
+	re.NoError(err)
+	defer l.Close()
+	addr2, err := a.Alloc()
+	re.NoError(err)
+	defer a.Release(addr2)
+	re.Greater(port(addr2), port(addr1)+1)
+	re.Less(port(addr2), a.end)
+
+	// the block claimed by another allocator is skipped even with the same seed.
+	b := NewPortAllocator(7)
+	addr3, err := b.Alloc()
+	re.NoError(err)
+	defer b.guard.Close()
+	defer b.Release(addr3)
+	re.NotEqual(a.begin, b.begin)
+	re.True(port(addr3) < a.begin || port(addr3) >= a.end)
+}
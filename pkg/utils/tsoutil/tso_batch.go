@@ -0,0 +1,155 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsoutil
+
+import (
+	"context"
+	"time"
+
+	"github.com/tikv/pd/pkg/timerpool"
+)
+
+const (
+	// minBatchWait is the wait window the adaptive batching starts with, the window
+	// is closed once it shrinks below it.
+	minBatchWait = 50 * time.Microsecond
+	// minBatchSize is the logical batch size the adaptive batching starts with.
+	minBatchSize = 64
+	// maxBatchSize is the max logical count of a merged request if the policy doesn't
+	// limit it, at most 1<<18 timestamps can be generated in one physical time.
+	maxBatchSize = 1 << 18
+	// batchPolicyRefreshInterval is the interval to refresh the batch policy of a dispatch loop.
+	batchPolicyRefreshInterval = 10 * time.Second
+)
+
+// BatchPolicy is the policy to batch the forwarded TSO requests adaptively. Without it,
+// the dispatcher merges the pending requests and forwards them at once. With it, the
+// dispatcher waits for more requests under a high request rate, the wait window and the
+// logical batch size grow with the rate until they reach the caps of the policy.
+type BatchPolicy struct {
+	// MaxBatchWait is the max time to wait for more requests before forwarding them,
+	// 0 disables the adaptive batching.
+	MaxBatchWait time.Duration
+	// MaxBatchSize is the max logical count of a merged request, 0 means no limit.
+	MaxBatchSize uint32
+}
+
+// Cap returns the policy capped by the given limits, the zero limits are ignored.
+func (p BatchPolicy) Cap(maxBatchWait time.Duration, maxBatchSize uint32) BatchPolicy {
+	if maxBatchWait > 0 && maxBatchWait < p.MaxBatchWait {
+		p.MaxBatchWait = maxBatchWait
+	}
+	if maxBatchSize > 0 && (p.MaxBatchSize == 0 || maxBatchSize < p.MaxBatchSize) {
+		p.MaxBatchSize = maxBatchSize
+	}
+	return p
+}
+
+// BatchPolicyGetter returns the batch policy of the requests forwarded to the keyspace group.
+type BatchPolicyGetter func(keyspaceGroupID uint32) BatchPolicy
+
+// adaptiveBatch is the batch window of a dispatch loop, it's adjusted after each batch.
+type adaptiveBatch struct {
+	policy BatchPolicy
+	// wait is the time to wait for more requests after taking the pending ones.
+	wait time.Duration
+	// size is the logical count to forward the batch at without waiting any longer,
+	// 0 means no limit.
+	size uint32
+}
+
+func (b *adaptiveBatch) setPolicy(policy BatchPolicy) {
+	enabled := b.policy.MaxBatchWait > 0
+	b.policy = policy
+	if policy.MaxBatchWait <= 0 {
+		b.wait, b.size = 0, policy.MaxBatchSize
+		return
+	}
+	// Start from the smallest window once the adaptive batching is enabled.
+	if !enabled {
+		b.wait, b.size = 0, minBatchSize
+	}
+	b.wait = min(b.wait, policy.MaxBatchWait)
+	b.size = min(max(b.size, minBatchSize), b.maxSize())
+}
+
+func (b *adaptiveBatch) maxSize() uint32 {
+	if b.policy.MaxBatchSize > 0 {
+		return b.policy.MaxBatchSize
+	}
+	return maxBatchSize
+}
+
+func (b *adaptiveBatch) full(count uint32) bool {
+	return b.size > 0 && count >= b.size
+}
+
+// adjust adjusts the batch window by the last batch. The window grows if the requests
+// come faster than they're forwarded, i.e. they're queued up or fill the batch, so that
+// more of them are merged. It shrinks if no more request comes in the window, so that
+// the sparse requests aren't delayed for nothing.
+func (b *adaptiveBatch) adjust(busy, idle bool) {
+	if b.policy.MaxBatchWait <= 0 {
+		return
+	}
+	switch {
+	case busy:
+		b.wait = min(max(2*b.wait, minBatchWait), b.policy.MaxBatchWait)
+		b.size = min(2*b.size, b.maxSize())
+	case idle:
+		if b.wait /= 2; b.wait < minBatchWait {
+			b.wait = 0
+		}
+		b.size = max(b.size/2, min(minBatchSize, b.maxSize()))
+	}
+}
+
+// collect collects the first request and the pending ones into the requests, and then
+// waits for more requests in the batch window. It returns the number of the requests.
+func (b *adaptiveBatch) collect(
+	ctx context.Context,
+	first Request,
+	tsoRequestCh <-chan Request,
+	requests []Request,
+) int {
+	requests[0] = first
+	n, count := 1, first.getCount()
+	queued := len(tsoRequestCh) + 1
+	for ; n < queued && n < len(requests) && !b.full(count); n++ {
+		requests[n] = <-tsoRequestCh
+		count += requests[n].getCount()
+	}
+	timedOut := false
+	if b.wait > 0 && n < len(requests) && !b.full(count) {
+		timer := timerpool.GlobalTimerPool.Get(b.wait)
+	waitLoop:
+		for n < len(requests) && !b.full(count) {
+			select {
+			case req := <-tsoRequestCh:
+				requests[n] = req
+				count += req.getCount()
+				n++
+			case <-timer.C:
+				timedOut = true
+				break waitLoop
+			case <-ctx.Done():
+				break waitLoop
+			}
+		}
+		timerpool.GlobalTimerPool.Put(timer)
+	}
+	b.adjust(queued > 1 || b.full(count), timedOut && n == 1)
+	return n
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tsoutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRequest(count uint32) Request {
+	return NewPDProtoRequest("", nil, &pdpb.TsoRequest{Count: count}, nil)
+}
+
+func TestBatchPolicyCap(t *testing.T) {
+	re := require.New(t)
+	policy := BatchPolicy{MaxBatchWait: time.Millisecond}
+	re.Equal(policy, policy.Cap(0, 0))
+	re.Equal(BatchPolicy{MaxBatchWait: 100 * time.Microsecond, MaxBatchSize: 100},
+		policy.Cap(100*time.Microsecond, 100))
+	// The cap can't enable the disabled adaptive batching.
+	re.Equal(BatchPolicy{MaxBatchSize: 100}, BatchPolicy{MaxBatchSize: 200}.Cap(time.Millisecond, 100))
+}
+
+func TestAdaptiveBatch(t *testing.T) {
+	re := require.New(t)
+	ctx := context.Background()
+	requests := make([]Request, 100)
+	ch := make(chan Request, 100)
+
+	// Without the policy, all the pending requests are merged.
+	batch := &adaptiveBatch{}
+	for i := 0; i < 10; i++ {
+		ch <- newTestRequest(100)
+	}
+	re.Equal(10, batch.collect(ctx, <-ch, ch, requests))
+	re.Zero(batch.wait)
+
+	// The logical batch size is limited.
+	batch.setPolicy(BatchPolicy{MaxBatchSize: 250})
+	for i := 0; i < 10; i++ {
+		ch <- newTestRequest(100)
+	}
+	re.Equal(3, batch.collect(ctx, <-ch, ch, requests))
+	re.Zero(batch.wait)
+	for len(ch) > 0 {
+		<-ch
+	}
+
+	// The window grows when the requests are queued up.
+	batch.setPolicy(BatchPolicy{MaxBatchWait: 200 * time.Microsecond, MaxBatchSize: 1000})
+	re.Zero(batch.wait)
+	re.Equal(uint32(minBatchSize), batch.size)
+	ch <- newTestRequest(1)
+	re.Equal(2, batch.collect(ctx, newTestRequest(1), ch, requests))
+	re.Equal(minBatchWait, batch.wait)
+	re.Equal(uint32(2*minBatchSize), batch.size)
+	for i := 0; i < 3; i++ {
+		ch <- newTestRequest(1)
+		batch.collect(ctx, newTestRequest(1), ch, requests)
+	}
+	// The window is capped by the policy.
+	re.Equal(200*time.Microsecond, batch.wait)
+	re.Equal(uint32(1000), batch.size)
+
+	// The requests coming in the window are merged.
+	go func() {
+		time.Sleep(50 * time.Microsecond)
+		ch <- newTestRequest(1)
+	}()
+	batch.setPolicy(BatchPolicy{MaxBatchWait: 100 * time.Millisecond, MaxBatchSize: 1000})
+	batch.wait = 100 * time.Millisecond
+	re.Equal(2, batch.collect(ctx, newTestRequest(1), ch, requests))
+
+	// The window shrinks when no more request comes in it.
+	batch.setPolicy(BatchPolicy{MaxBatchWait: 200 * time.Microsecond, MaxBatchSize: 1000})
+	for _, wait := range []time.Duration{100 * time.Microsecond, minBatchWait, 0} {
+		re.Equal(1, batch.collect(ctx, newTestRequest(1), ch, requests))
+		re.Equal(wait, batch.wait)
+	}
+	re.Equal(uint32(125), batch.size)
+
+	// The adaptive batching is disabled by the policy.
+	batch.wait = 200 * time.Microsecond
+	batch.setPolicy(BatchPolicy{})
+	re.Zero(batch.wait)
+	re.Zero(batch.size)
+}
@@ -45,21 +45,30 @@ type tsoResp interface {
 type TSODispatcher struct {
 	tsoProxyHandleDuration prometheus.Histogram
 	tsoProxyBatchSize      prometheus.Histogram
+	// getBatchPolicy returns the adaptive batch policy of the keyspace group, the
+	// adaptive batching is disabled if it's nil.
+	getBatchPolicy BatchPolicyGetter
 
 	// dispatchChs is used to dispatch different TSO requests to the corresponding forwarding TSO channels.
-	dispatchChs sync.Map // Store as map[string]chan Request
+	dispatchChs sync.Map // Store as map[dispatchKey]chan Request
 }
 
 // NewTSODispatcher creates and returns a TSODispatcher
-func NewTSODispatcher(tsoProxyHandleDuration, tsoProxyBatchSize prometheus.Histogram) *TSODispatcher {
+func NewTSODispatcher(
+	tsoProxyHandleDuration, tsoProxyBatchSize prometheus.Histogram,
+	getBatchPolicy BatchPolicyGetter,
+) *TSODispatcher {
 	tsoDispatcher := &TSODispatcher{
 		tsoProxyHandleDuration: tsoProxyHandleDuration,
 		tsoProxyBatchSize:      tsoProxyBatchSize,
+		getBatchPolicy:         getBatchPolicy,
 	}
 	return tsoDispatcher
 }
 
-// DispatchRequest is the entry point for dispatching/forwarding a tso request to the destination host
+// DispatchRequest is the entry point for dispatching/forwarding a tso request to the destination host.
+// The first error is sent to errCh of the request creating the forwarding channel, it could be nil if
+// the requests report their errors by themselves.
 func (s *TSODispatcher) DispatchRequest(
 	ctx context.Context,
 	req Request,
@@ -67,11 +76,11 @@ func (s *TSODispatcher) DispatchRequest(
 	doneCh <-chan struct{},
 	errCh chan<- error,
 	tsoPrimaryWatchers ...*etcdutil.LoopWatcher) {
-	val, loaded := s.dispatchChs.LoadOrStore(req.getForwardedHost(), make(chan Request, maxMergeRequests))
+	val, loaded := s.dispatchChs.LoadOrStore(req.getDispatchKey(), make(chan Request, maxMergeRequests))
 	reqCh := val.(chan Request)
 	if !loaded {
 		tsDeadlineCh := make(chan *TSDeadline, 1)
-		go s.dispatch(ctx, tsoProtoFactory, req.getForwardedHost(), req.getClientConn(), reqCh, tsDeadlineCh, doneCh, errCh, tsoPrimaryWatchers...)
+		go s.dispatch(ctx, tsoProtoFactory, req.getDispatchKey(), req.getForwardedHost(), req.getKeyspaceGroupID(), req.getClientConn(), reqCh, tsDeadlineCh, doneCh, errCh, tsoPrimaryWatchers...)
 		go WatchTSDeadline(ctx, tsDeadlineCh)
 	}
 	reqCh <- req
@@ -80,7 +89,9 @@ func (s *TSODispatcher) DispatchRequest(
 func (s *TSODispatcher) dispatch(
 	ctx context.Context,
 	tsoProtoFactory ProtoFactory,
+	dispatchKey string,
 	forwardedHost string,
+	keyspaceGroupID uint32,
 	clientConn *grpc.ClientConn,
 	tsoRequestCh <-chan Request,
 	tsDeadlineCh chan<- *TSDeadline,
@@ -90,13 +101,25 @@ func (s *TSODispatcher) dispatch(
 	defer logutil.LogPanic()
 	dispatcherCtx, ctxCancel := context.WithCancel(ctx)
 	defer ctxCancel()
-	defer s.dispatchChs.Delete(forwardedHost)
+	var err error
+	// Fail the requests left in the channel after it's deleted, so that their
+	// senders won't wait for the responses forever.
+	defer func() {
+		if err == nil {
+			err = errs.ErrGRPCSend.Wrap(dispatcherCtx.Err()).GenWithStackByCause()
+		}
+		failPendingRequests(tsoRequestCh, err)
+	}()
+	defer s.dispatchChs.Delete(dispatchKey)
 
 	forwardStream, cancel, err := tsoProtoFactory.createForwardStream(ctx, clientConn)
 	if err != nil || forwardStream == nil {
 		log.Error("create tso forwarding stream error",
 			zap.String("forwarded-host", forwardedHost),
 			errs.ZapError(errs.ErrGRPCCreateStream, err))
+		if errCh == nil {
+			return
+		}
 		select {
 		case <-dispatcherCtx.Done():
 			return
@@ -113,14 +136,16 @@ func (s *TSODispatcher) dispatch(
 
 	requests := make([]Request, maxMergeRequests+1)
 	needUpdateServicePrimaryAddr := len(tsoPrimaryWatchers) > 0 && tsoPrimaryWatchers[0] != nil
+	batch := &adaptiveBatch{}
+	var policyRefreshTime time.Time
 	for {
 		select {
 		case first := <-tsoRequestCh:
-			pendingTSOReqCount := len(tsoRequestCh) + 1
-			requests[0] = first
-			for i := 1; i < pendingTSOReqCount; i++ {
-				requests[i] = <-tsoRequestCh
+			if s.getBatchPolicy != nil && time.Since(policyRefreshTime) >= batchPolicyRefreshInterval {
+				batch.setPolicy(s.getBatchPolicy(keyspaceGroupID))
+				policyRefreshTime = time.Now()
 			}
+			pendingTSOReqCount := batch.collect(dispatcherCtx, first, tsoRequestCh, requests)
 			done := make(chan struct{})
 			dl := NewTSDeadline(DefaultTSOProxyTimeout, done, cancel)
 			select {
@@ -137,6 +162,12 @@ func (s *TSODispatcher) dispatch(
 				if needUpdateServicePrimaryAddr && strings.Contains(err.Error(), errs.NotLeaderErr) {
 					tsoPrimaryWatchers[0].ForceLoad()
 				}
+				for _, req := range requests[:pendingTSOReqCount] {
+					req.notifyError(err)
+				}
+				if errCh == nil {
+					return
+				}
 				select {
 				case <-dispatcherCtx.Done():
 					return
@@ -179,6 +210,18 @@ func (s *TSODispatcher) processRequests(forwardStream stream, requests []Request
 	return s.finishRequest(requests, physical, firstLogical, suffixBits)
 }
 
+// failPendingRequests fails the requests left in the channel without blocking.
+func failPendingRequests(tsoRequestCh <-chan Request, err error) {
+	for {
+		select {
+		case req := <-tsoRequestCh:
+			req.notifyError(err)
+		default:
+			return
+		}
+	}
+}
+
 // Because of the suffix, we need to shift the count before we add it to the logical part.
 func addLogical(logical, count int64, suffixBits uint32) int64 {
 	return logical + count<<suffixBits
@@ -15,6 +15,8 @@
 package tsoutil
 
 import (
+	"fmt"
+
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/pingcap/kvproto/pkg/tsopb"
 	"github.com/tikv/pd/pkg/mcs/utils"
@@ -26,6 +28,11 @@ import (
 type Request interface {
 	// getForwardedHost returns the forwarded host
 	getForwardedHost() string
+	// getDispatchKey returns the key of the forwarding channel, only the requests
+	// with the same key can be merged into one forwarded request
+	getDispatchKey() string
+	// getKeyspaceGroupID returns the ID of the keyspace group the request is forwarded to
+	getKeyspaceGroupID() uint32
 	// getClientConn returns the grpc client connection
 	getClientConn() *grpc.ClientConn
 	// getCount returns the count of timestamps to retrieve
@@ -35,6 +42,8 @@ type Request interface {
 	process(forwardStream stream, count uint32) (tsoResp, error)
 	// postProcess sends the response back to the sender of the request
 	postProcess(countSum, physical, firstLogical int64, suffixBits uint32) (int64, error)
+	// notifyError notifies the sender of the request that it failed to be forwarded
+	notifyError(err error)
 }
 
 // response is an interface wrapping tsopb.TsoResponse and pdpb.TsoResponse
@@ -42,21 +51,30 @@ type response interface {
 	GetTimestamp() *pdpb.Timestamp
 }
 
-// TSOProtoRequest wraps the request and stream channel in the TSO grpc service
+// TSOProtoResult is the result of a TSO request forwarded by the TSO grpc service.
+type TSOProtoResult struct {
+	Response *tsopb.TsoResponse
+	Err      error
+}
+
+// TSOProtoRequest wraps the request and result channel in the TSO grpc service
 type TSOProtoRequest struct {
 	forwardedHost string
 	clientConn    *grpc.ClientConn
 	request       *tsopb.TsoRequest
-	stream        tsopb.TSO_TsoServer
+	// resultCh receives exactly one result of the request, the stream receiving
+	// the request sends the responses back in order by itself.
+	resultCh chan<- *TSOProtoResult
 }
 
-// NewTSOProtoRequest creates a TSOProtoRequest and returns as a Request
-func NewTSOProtoRequest(forwardedHost string, clientConn *grpc.ClientConn, request *tsopb.TsoRequest, stream tsopb.TSO_TsoServer) Request {
+// NewTSOProtoRequest creates a TSOProtoRequest and returns as a Request, the
+// result channel should be buffered so that the dispatcher is never blocked.
+func NewTSOProtoRequest(forwardedHost string, clientConn *grpc.ClientConn, request *tsopb.TsoRequest, resultCh chan<- *TSOProtoResult) Request {
 	tsoRequest := &TSOProtoRequest{
 		forwardedHost: forwardedHost,
 		clientConn:    clientConn,
 		request:       request,
-		stream:        stream,
+		resultCh:      resultCh,
 	}
 	return tsoRequest
 }
@@ -66,6 +84,17 @@ func (r *TSOProtoRequest) getForwardedHost() string {
 	return r.forwardedHost
 }
 
+// getDispatchKey returns the key of the forwarding channel, the requests of the
+// different keyspace groups can't be merged even if they have the same primary
+func (r *TSOProtoRequest) getDispatchKey() string {
+	return fmt.Sprintf("%s/%d", r.forwardedHost, r.request.GetHeader().GetKeyspaceGroupId())
+}
+
+// getKeyspaceGroupID returns the ID of the keyspace group the request is forwarded to
+func (r *TSOProtoRequest) getKeyspaceGroupID() uint32 {
+	return r.request.GetHeader().GetKeyspaceGroupId()
+}
+
 // getClientConn returns the grpc client connection
 func (r *TSOProtoRequest) getClientConn() *grpc.ClientConn {
 	return r.clientConn
@@ -88,21 +117,33 @@ func (r *TSOProtoRequest) postProcess(countSum, physical, firstLogical int64, su
 	count := r.request.GetCount()
 	countSum += int64(count)
 	response := &tsopb.TsoResponse{
-		Header: &tsopb.ResponseHeader{ClusterId: r.request.GetHeader().GetClusterId()},
-		Count:  count,
+		Header: &tsopb.ResponseHeader{
+			ClusterId:       r.request.GetHeader().GetClusterId(),
+			KeyspaceGroupId: r.request.GetHeader().GetKeyspaceGroupId(),
+		},
+		Count: count,
 		Timestamp: &pdpb.Timestamp{
 			Physical:   physical,
 			Logical:    addLogical(firstLogical, countSum, suffixBits),
 			SuffixBits: suffixBits,
 		},
 	}
-	// Send back to the client.
-	if err := r.stream.Send(response); err != nil {
-		return countSum, err
-	}
+	r.sendResult(&TSOProtoResult{Response: response})
 	return countSum, nil
 }
 
+// notifyError notifies the sender of the request that it failed to be forwarded
+func (r *TSOProtoRequest) notifyError(err error) {
+	r.sendResult(&TSOProtoResult{Err: err})
+}
+
+func (r *TSOProtoRequest) sendResult(result *TSOProtoResult) {
+	select {
+	case r.resultCh <- result:
+	default:
+	}
+}
+
 // PDProtoRequest wraps the request and stream channel in the PD grpc service
 type PDProtoRequest struct {
 	forwardedHost string
@@ -127,6 +168,17 @@ func (r *PDProtoRequest) getForwardedHost() string {
 	return r.forwardedHost
 }
 
+// getDispatchKey returns the key of the forwarding channel
+func (r *PDProtoRequest) getDispatchKey() string {
+	return r.forwardedHost
+}
+
+// getKeyspaceGroupID returns the ID of the keyspace group the request is forwarded to,
+// it's always the default keyspace group for the PD requests
+func (*PDProtoRequest) getKeyspaceGroupID() uint32 {
+	return utils.DefaultKeyspaceGroupID
+}
+
 // getClientConn returns the grpc client connection
 func (r *PDProtoRequest) getClientConn() *grpc.ClientConn {
 	return r.clientConn
@@ -163,3 +215,7 @@ func (r *PDProtoRequest) postProcess(countSum, physical, firstLogical int64, suf
 	}
 	return countSum, nil
 }
+
+// notifyError is a no-op, the errors of the PD requests are reported through
+// the error channel of the dispatcher.
+func (*PDProtoRequest) notifyError(error) {}
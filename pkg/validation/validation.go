@@ -0,0 +1,128 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+// The kinds of the changes to be validated.
+const (
+	KindScheduleConfig        = "schedule-config"
+	KindReplicationConfig     = "replication-config"
+	KindReplicationModeConfig = "replication-mode-config"
+	KindPDServerConfig        = "pd-server-config"
+	KindKeyspaceConfig        = "keyspace-config"
+	KindMicroServiceConfig    = "micro-service-config"
+	KindPlacementRules        = "placement-rules"
+)
+
+// defaultValidateTimeout is the timeout of validating a change by all the validators.
+const defaultValidateTimeout = 10 * time.Second
+
+// Change is a config or rule change to be validated before it's applied.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type Change struct {
+	// Kind is the kind of the changed object, e.g. schedule-config.
+	Kind string `json:"kind"`
+	// Old is the object before the change, it may be nil for the rules.
+	Old any `json:"old,omitempty"`
+	// New is the object after the change, or the changed part of the rules.
+	New any `json:"new"`
+}
+
+// Validator validates the changes, e.g. to enforce the org-specific guardrails.
+type Validator interface {
+	// Name returns the unique name of the validator.
+	Name() string
+	// Validate returns an error to reject the change, the error message is
+	// returned to the user who makes the change.
+	Validate(ctx context.Context, change *Change) error
+}
+
+// Manager manages the validators, which are invoked on the config and rule changes.
+type Manager struct {
+	mu         syncutil.RWMutex
+	validators map[string]Validator
+}
+
+// NewManager creates a new Manager.
+func NewManager() *Manager {
+	return &Manager{validators: make(map[string]Validator)}
+}
+
+// Register registers a validator, the one with the same name is replaced.
+func (m *Manager) Register(v Validator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.validators[v.Name()] = v
+	log.Info("change validator is registered", zap.String("name", v.Name()))
+}
+
+// Unregister unregisters the validator with the given name.
+func (m *Manager) Unregister(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.validators, name)
+}
+
+// GetValidatorNames returns the sorted names of the registered validators.
+func (m *Manager) GetValidatorNames() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.validators))
+	for name := range m.validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate invokes the validators in the order of the names, the change is
+// rejected by the first validator which returns an error.
+func (m *Manager) Validate(kind string, oldValue, newValue any) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	validators := make([]Validator, 0, len(m.validators))
+	for _, v := range m.validators {
+		validators = append(validators, v)
+	}
+	m.mu.RUnlock()
+	if len(validators) == 0 {
+		return nil
+	}
+	sort.Slice(validators, func(i, j int) bool { return validators[i].Name() < validators[j].Name() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultValidateTimeout)
+	defer cancel()
+	change := &Change{Kind: kind, Old: oldValue, New: newValue}
+	for _, v := range validators {
+		if err := v.Validate(ctx, change); err != nil {
+			log.Warn("change is rejected by the validator",
+				zap.String("kind", kind), zap.String("validator", v.Name()), errs.ZapError(err))
+			return errs.ErrChangeRejected.FastGenByArgs(kind, v.Name(), err.Error())
+		}
+	}
+	return nil
+}
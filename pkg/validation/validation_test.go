@@ -0,0 +1,108 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/errs"
+)
+
+type funcValidator struct {
+	name     string
+	validate func(*Change) error
+}
+
+func (v *funcValidator) Name() string { return v.name }
+
+func (v *funcValidator) Validate(_ context.Context, change *Change) error {
+	return v.validate(change)
+}
+
+func TestManager(t *testing.T) {
+	re := require.New(t)
+	var m *Manager
+	re.NoError(m.Validate(KindScheduleConfig, nil, 1))
+
+	m = NewManager()
+	var called []string
+	m.Register(&funcValidator{name: "b", validate: func(*Change) error {
+		called = append(called, "b")
+		return errors.New("too large")
+	}})
+	m.Register(&funcValidator{name: "a", validate: func(c *Change) error {
+		called = append(called, "a")
+		re.Equal(KindScheduleConfig, c.Kind)
+		return nil
+	}})
+	re.Equal([]string{"a", "b"}, m.GetValidatorNames())
+	err := m.Validate(KindScheduleConfig, 1, 2)
+	re.True(errs.ErrChangeRejected.Equal(err))
+	re.Contains(err.Error(), "too large")
+	re.Equal([]string{"a", "b"}, called)
+
+	m.Unregister("b")
+	re.NoError(m.Validate(KindScheduleConfig, 1, 2))
+}
+
+func TestWebhook(t *testing.T) {
+	re := require.New(t)
+	// The handler sends the kind before responding, so it's already buffered
+	// once the validation returns.
+	kinds := make(chan string, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		change := &Change{}
+		if err := json.NewDecoder(r.Body).Decode(change); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		kinds <- change.Kind
+		resp := &WebhookResponse{Allowed: change.Kind != KindPlacementRules, Message: "rules are frozen"}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	cfg := WebhookConfig{Name: "guard", URL: server.URL}
+	re.NoError(cfg.Adjust())
+	re.Equal(defaultWebhookTimeout, cfg.Timeout.Duration)
+	webhook := NewWebhook(cfg, server.Client())
+	re.NoError(webhook.Validate(context.Background(), &Change{Kind: KindScheduleConfig}))
+	err := webhook.Validate(context.Background(), &Change{Kind: KindPlacementRules})
+	re.EqualError(err, "rules are frozen")
+
+	// Only the configured kinds are sent.
+	cfg.Kinds = []string{KindReplicationConfig}
+	webhook = NewWebhook(cfg, server.Client())
+	re.NoError(webhook.Validate(context.Background(), &Change{Kind: KindPlacementRules}))
+	re.Len(kinds, 2)
+	re.Equal(KindScheduleConfig, <-kinds)
+	re.Equal(KindPlacementRules, <-kinds)
+
+	// The changes are rejected if the webhook is unavailable unless it fails open.
+	cfg = WebhookConfig{Name: "down", URL: server.URL}
+	re.NoError(cfg.Adjust())
+	server.Close()
+	re.Error(NewWebhook(cfg, &http.Client{}).Validate(context.Background(), &Change{Kind: KindScheduleConfig}))
+	cfg.FailOpen = true
+	re.NoError(NewWebhook(cfg, &http.Client{}).Validate(context.Background(), &Change{Kind: KindScheduleConfig}))
+
+	re.Error((&WebhookConfig{Name: "bad", URL: "localhost:8080"}).Adjust())
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/utils/typeutil"
+	"go.uber.org/zap"
+)
+
+const defaultWebhookTimeout = 3 * time.Second
+
+// WebhookConfig is the config of an external validation endpoint.
+type WebhookConfig struct {
+	Name string `toml:"name" json:"name"`
+	URL  string `toml:"url" json:"url"`
+	// Kinds are the kinds of the changes sent to the webhook, all by default.
+	Kinds   []string          `toml:"kinds" json:"kinds"`
+	Timeout typeutil.Duration `toml:"timeout" json:"timeout"`
+	// FailOpen allows the changes if the webhook is unavailable, the changes
+	// are rejected by default.
+	FailOpen bool `toml:"fail-open" json:"fail-open"`
+}
+
+// Adjust adjusts the config and checks if it's valid.
+func (c *WebhookConfig) Adjust() error {
+	if c.Name == "" {
+		return errors.New("the name of the validation webhook is empty")
+	}
+	if !strings.HasPrefix(c.URL, "http://") && !strings.HasPrefix(c.URL, "https://") {
+		return errors.Errorf("the url of the validation webhook %s should start with http:// or https://", c.Name)
+	}
+	if c.Timeout.Duration <= 0 {
+		c.Timeout = typeutil.NewDuration(defaultWebhookTimeout)
+	}
+	return nil
+}
+
+// WebhookResponse is the response of the validation webhook.
+type WebhookResponse struct {
+	Allowed bool   `json:"allowed"`
+	Message string `json:"message"`
+}
+
+// Webhook is a validator which posts the change in JSON to an external
+// endpoint, the endpoint responds a WebhookResponse to allow or reject it.
+type Webhook struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+// NewWebhook creates a validator of the webhook.
+func NewWebhook(cfg WebhookConfig, client *http.Client) *Webhook {
+	return &Webhook{cfg: cfg, client: client}
+}
+
+// Name implements Validator.
+func (w *Webhook) Name() string {
+	return w.cfg.Name
+}
+
+// Validate implements Validator.
+func (w *Webhook) Validate(ctx context.Context, change *Change) error {
+	if len(w.cfg.Kinds) > 0 && !containsKind(w.cfg.Kinds, change.Kind) {
+		return nil
+	}
+	resp, err := w.post(ctx, change)
+	if err != nil {
+		if w.cfg.FailOpen {
+			log.Warn("validation webhook is unavailable, the change is allowed",
+				zap.String("name", w.cfg.Name), zap.String("kind", change.Kind), errs.ZapError(err))
+			return nil
+		}
+		return err
+	}
+	if !resp.Allowed {
+		if resp.Message == "" {
+			return errors.New("rejected without message")
+		}
+		return errors.New(resp.Message)
+	}
+	return nil
+}
+
+func (w *Webhook) post(ctx context.Context, change *Change) (*WebhookResponse, error) {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return nil, errs.ErrJSONMarshal.Wrap(err).GenWithStackByCause()
+	}
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.Timeout.Duration)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	httpResp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call the validation webhook: %v", err)
+	}
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the validation webhook responds %d: %s", httpResp.StatusCode, string(body))
+	}
+	resp := &WebhookResponse{}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return nil, errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByCause()
+	}
+	return resp, nil
+}
+
+func containsKind(kinds []string, kind string) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -176,6 +176,15 @@ func (h *adminHandler) UnmarkSnapshotRecovering(w http.ResponseWriter, r *http.R
 	h.rd.Text(w, http.StatusOK, "")
 }
 
+// @Tags     admin
+// @Summary  Get the consumption rate and the projected exhaustion horizon of the ID allocator.
+// @Produce  json
+// @Success  200  {object}  id.Stats
+// @Router   /admin/alloc-id/stats [get]
+func (h *adminHandler) GetAllocIDStats(w http.ResponseWriter, _ *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.svr.GetAllocator().GetStats())
+}
+
 // RecoverAllocID recover base alloc id
 // body should be in {"id": "123"} format
 func (h *adminHandler) RecoverAllocID(w http.ResponseWriter, r *http.Request) {
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/stretchr/testify/suite"
 	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/id"
 	"github.com/tikv/pd/pkg/replication"
 	"github.com/tikv/pd/pkg/utils/apiutil"
 	tu "github.com/tikv/pd/pkg/utils/testutil"
@@ -296,6 +297,21 @@ func (suite *adminTestSuite) TestMarkSnapshotRecovering() {
 		tu.StatusOK(re), tu.StringContain(re, "false")))
 }
 
+func (suite *adminTestSuite) TestAllocIDStats() {
+	re := suite.Require()
+	for i := 0; i < 10; i++ {
+		_, err := suite.svr.GetAllocator().Alloc()
+		re.NoError(err)
+	}
+	url := fmt.Sprintf("%s/admin/alloc-id/stats", suite.urlPrefix)
+	stats := &id.Stats{}
+	re.NoError(tu.ReadGetJSON(re, testDialClient, url, stats))
+	re.Equal("idalloc", stats.Label)
+	re.Less(stats.Base, stats.End)
+	re.LessOrEqual(stats.Step, stats.MaxStep)
+	re.False(stats.Alarm)
+}
+
 func (suite *adminTestSuite) TestRecoverAllocID() {
 	re := suite.Require()
 	url := fmt.Sprintf("%s/admin/base-alloc-id", suite.urlPrefix)
@@ -16,8 +16,10 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/server"
 	"github.com/unrolled/render"
@@ -90,3 +92,45 @@ func (c *checkerHandler) GetCheckerStatus(w http.ResponseWriter, r *http.Request
 	}
 	c.r.JSON(w, http.StatusOK, output)
 }
+
+// @Tags     checker
+// @Summary  Get the progress of the current patrol round of the checkers.
+// @Produce  json
+// @Success  200  {object}  schedule.PatrolRegionsProgress
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /checker/patrol-progress [get]
+func (c *checkerHandler) GetPatrolRegionsProgress(w http.ResponseWriter, _ *http.Request) {
+	progress, err := c.Handler.GetPatrolRegionsProgress()
+	if err != nil {
+		c.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.r.JSON(w, http.StatusOK, progress)
+}
+
+// @Tags     checker
+// @Summary  Diagnose the adjacent regions of a region as the merge target.
+// @Param    region_id  path  integer  true  "Region Id"
+// @Produce  json
+// @Success  200  {array}   checker.MergeTargetDiagnosis
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The region does not exist."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /checker/merge-checker/diagnostic/{region_id} [get]
+func (c *checkerHandler) DiagnoseMergeTargets(w http.ResponseWriter, r *http.Request) {
+	regionID, err := strconv.ParseUint(mux.Vars(r)["region_id"], 10, 64)
+	if err != nil {
+		c.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	diagnoses, err := c.Handler.DiagnoseMergeTargets(regionID)
+	if err != nil {
+		if errs.ErrRegionNotFound.Equal(err) {
+			c.r.JSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		c.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.r.JSON(w, http.StatusOK, diagnoses)
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/tikv/pd/server"
+	"github.com/unrolled/render"
+)
+
+type clientHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newClientHandler(svr *server.Server, rd *render.Render) *clientHandler {
+	return &clientHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+// @Tags     client
+// @Summary  List the gRPC clients which have sent requests to the PD leader recently, with their component, version, keyspace, start time and RPC mix.
+// @Produce  json
+// @Success  200  {array}  server.ClientInfo
+// @Router   /clients [get]
+func (h *clientHandler) GetClients(w http.ResponseWriter, _ *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.svr.GetClients())
+}
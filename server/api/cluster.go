@@ -16,11 +16,16 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/tikv/pd/server"
 	"github.com/unrolled/render"
 )
 
+// defaultStaleSafePoint is the default lag after which the GC safe point is
+// reported as stale by the cluster check.
+const defaultStaleSafePoint = 24 * time.Hour
+
 type clusterHandler struct {
 	svr *server.Server
 	rd  *render.Render
@@ -56,3 +61,50 @@ func (h *clusterHandler) GetClusterStatus(w http.ResponseWriter, _ *http.Request
 	}
 	h.rd.JSON(w, http.StatusOK, status)
 }
+
+// @Tags     cluster
+// @Summary  Get the latency accounting of the registered heartbeat interceptors.
+// @Produce  json
+// @Success  200  {array}  cluster.InterceptorStats
+// @Router   /cluster/heartbeat-interceptors [get]
+func (h *clusterHandler) GetHeartbeatInterceptors(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	h.rd.JSON(w, http.StatusOK, rc.GetHeartbeatInterceptors().GetStats())
+}
+
+// @Tags     cluster
+// @Summary  Get the status of the degraded placement and the audit records of its transitions.
+// @Produce  json
+// @Success  200  {object}  cluster.DegradedPlacementStatus
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /cluster/degraded-placement [get]
+func (h *clusterHandler) GetDegradedPlacementStatus(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	status, err := rc.GetDegradedPlacementStatus()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, status)
+}
+
+// @Tags     cluster
+// @Summary  Check the cluster health, including the members, the etcd alarms, the stores, the operators, the rule violations and the GC safe point.
+// @Param    stale_safepoint  query  string  false  "The lag after which the GC safe point is stale, 24h by default"
+// @Produce  json
+// @Success  200  {object}  cluster.CheckReport
+// @Failure  400  {string}  string  "The input is invalid."
+// @Router   /cluster/check [get]
+func (h *clusterHandler) CheckCluster(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	staleSafePoint := defaultStaleSafePoint
+	if value := r.URL.Query().Get("stale_safepoint"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "invalid stale_safepoint")
+			return
+		}
+		staleSafePoint = d
+	}
+	h.rd.JSON(w, http.StatusOK, rc.Check(staleSafePoint))
+}
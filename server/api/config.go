@@ -34,6 +34,7 @@ import (
 	"github.com/tikv/pd/pkg/utils/jsonutil"
 	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/tikv/pd/pkg/utils/reflectutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/config"
 	"github.com/unrolled/render"
@@ -46,6 +47,9 @@ var _ *sc.ScheduleConfig = nil
 type confHandler struct {
 	svr *server.Server
 	rd  *render.Render
+	// writeMu serializes the config writes, so the If-Match check of a write
+	// and the write itself aren't interleaved with the other writes.
+	writeMu syncutil.Mutex
 }
 
 func newConfHandler(svr *server.Server, rd *render.Render) *confHandler {
@@ -61,20 +65,31 @@ func newConfHandler(svr *server.Server, rd *render.Render) *confHandler {
 // @Success  200  {object}  config.Config
 // @Router   /config [get]
 func (h *confHandler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.getConfig(r)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if apiutil.WriteETag(w, r, cfg) {
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cfg)
+}
+
+func (h *confHandler) getConfig(r *http.Request) (*config.Config, error) {
 	cfg := h.svr.GetConfig()
 	if h.svr.IsServiceIndependent(utils.SchedulingServiceName) &&
 		r.Header.Get(apiutil.XForbiddenForwardToMicroServiceHeader) != "true" {
 		schedulingServerConfig, err := h.GetSchedulingServerConfig()
 		if err != nil {
-			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
-			return
+			return nil, err
 		}
 		cfg.Schedule = schedulingServerConfig.Schedule
 		cfg.Replication = schedulingServerConfig.Replication
 	} else {
 		cfg.Schedule.MaxMergeRegionKeys = cfg.Schedule.GetMaxMergeRegionKeys()
 	}
-	h.rd.JSON(w, http.StatusOK, cfg)
+	return cfg, nil
 }
 
 // @Tags     config
@@ -99,12 +114,19 @@ func (h *confHandler) GetDefaultConfig(w http.ResponseWriter, _ *http.Request) {
 // @Accept   json
 // @Param    ttlSecond  query  integer  false  "ttl param is only for BR and lightning now. Don't use it."
 // @Param    body       body   object   false  "json params"
+// @Param    impact     query  bool     false  "Whether to return the impact summary of the change"
 // @Produce  json
 // @Success  200  {string}  string  "The config is updated."
 // @Failure  400  {string}  string  "The input is invalid."
+// @Failure  412  {string}  string  "The config has been modified."
 // @Failure  500  {string}  string  "PD server failed to proceed the request."
 // @Router   /config [post]
 func (h *confHandler) SetConfig(w http.ResponseWriter, r *http.Request) {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	if !checkConfigIfMatch(h, w, r, h.getConfig) {
+		return
+	}
 	cfg := h.svr.GetConfig()
 	data, err := io.ReadAll(r.Body)
 	r.Body.Close()
@@ -139,6 +161,11 @@ func (h *confHandler) SetConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var oldSchedule *sc.ScheduleConfig
+	var oldReplication *sc.ReplicationConfig
+	if wantConfigImpact(r) {
+		oldSchedule, oldReplication = h.svr.GetScheduleConfig(), h.svr.GetReplicationConfig()
+	}
 	for k, v := range conf {
 		if s := strings.Split(k, "."); len(s) > 1 {
 			if err := h.updateConfig(cfg, k, v); err != nil {
@@ -158,7 +185,12 @@ func (h *confHandler) SetConfig(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	h.rd.JSON(w, http.StatusOK, "The config is updated.")
+	var impact *configImpact
+	if wantConfigImpact(r) {
+		impact = computeScheduleConfigImpact(oldSchedule, h.svr.GetScheduleConfig())
+		impact.merge(computeReplicationConfigImpact(h.svr, oldReplication, h.svr.GetReplicationConfig()))
+	}
+	h.respondConfigUpdated(w, r, impact)
 }
 
 func (h *confHandler) updateConfig(cfg *config.Config, key string, value any) error {
@@ -336,33 +368,50 @@ func getConfigMap(cfg map[string]any, key []string, value any) map[string]any {
 // @Success  200  {object}  sc.ScheduleConfig
 // @Router   /config/schedule [get]
 func (h *confHandler) GetScheduleConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.getScheduleConfig(r)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if apiutil.WriteETag(w, r, cfg) {
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cfg)
+}
+
+func (h *confHandler) getScheduleConfig(r *http.Request) (*sc.ScheduleConfig, error) {
 	if h.svr.IsServiceIndependent(utils.SchedulingServiceName) &&
 		r.Header.Get(apiutil.XForbiddenForwardToMicroServiceHeader) != "true" {
 		cfg, err := h.GetSchedulingServerConfig()
 		if err != nil {
-			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
-			return
+			return nil, err
 		}
 		cfg.Schedule.SchedulersPayload = nil
-		h.rd.JSON(w, http.StatusOK, cfg.Schedule)
-		return
+		return &cfg.Schedule, nil
 	}
 	cfg := h.svr.GetScheduleConfig()
 	cfg.MaxMergeRegionKeys = cfg.GetMaxMergeRegionKeys()
-	h.rd.JSON(w, http.StatusOK, cfg)
+	return cfg, nil
 }
 
 // @Tags     config
 // @Summary  Update a schedule config item.
 // @Accept   json
-// @Param    body  body  object  string  "json params"
+// @Param    body    body   object  string  "json params"
+// @Param    impact  query  bool    false   "Whether to return the impact summary of the change"
 // @Produce  json
 // @Success  200  {string}  string  "The config is updated."
 // @Failure  400  {string}  string  "The input is invalid."
 // @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Failure  412  {string}  string  "The config has been modified."
 // @Failure  503  {string}  string  "PD server has no leader."
 // @Router   /config/schedule [post]
 func (h *confHandler) SetScheduleConfig(w http.ResponseWriter, r *http.Request) {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	if !checkConfigIfMatch(h, w, r, h.getScheduleConfig) {
+		return
+	}
 	data, err := io.ReadAll(r.Body)
 	r.Body.Close()
 	if err != nil {
@@ -384,6 +433,7 @@ func (h *confHandler) SetScheduleConfig(w http.ResponseWriter, r *http.Request)
 	}
 
 	config := h.svr.GetScheduleConfig()
+	oldConfig := config.Clone()
 	err = json.Unmarshal(data, &config)
 	if err != nil {
 		var errCode errcode.ErrorCode
@@ -398,10 +448,18 @@ func (h *confHandler) SetScheduleConfig(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := h.svr.SetScheduleConfig(*config); err != nil {
+		if errs.ErrChangeRejected.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	h.rd.JSON(w, http.StatusOK, "The config is updated.")
+	var impact *configImpact
+	if wantConfigImpact(r) {
+		impact = computeScheduleConfigImpact(oldConfig, h.svr.GetScheduleConfig())
+	}
+	h.respondConfigUpdated(w, r, impact)
 }
 
 // @Tags     config
@@ -410,40 +468,66 @@ func (h *confHandler) SetScheduleConfig(w http.ResponseWriter, r *http.Request)
 // @Success  200  {object}  sc.ReplicationConfig
 // @Router   /config/replicate [get]
 func (h *confHandler) GetReplicationConfig(w http.ResponseWriter, r *http.Request) {
+	cfg, err := h.getReplicationConfig(r)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if apiutil.WriteETag(w, r, cfg) {
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cfg)
+}
+
+func (h *confHandler) getReplicationConfig(r *http.Request) (*sc.ReplicationConfig, error) {
 	if h.svr.IsServiceIndependent(utils.SchedulingServiceName) &&
 		r.Header.Get(apiutil.XForbiddenForwardToMicroServiceHeader) != "true" {
 		cfg, err := h.GetSchedulingServerConfig()
 		if err != nil {
-			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
-			return
+			return nil, err
 		}
-		h.rd.JSON(w, http.StatusOK, cfg.Replication)
-		return
+		return &cfg.Replication, nil
 	}
-	h.rd.JSON(w, http.StatusOK, h.svr.GetReplicationConfig())
+	return h.svr.GetReplicationConfig(), nil
 }
 
 // @Tags     config
 // @Summary  Update a replication config item.
 // @Accept   json
-// @Param    body  body  object  string  "json params"
+// @Param    body    body   object  string  "json params"
+// @Param    impact  query  bool    false   "Whether to return the impact summary of the change"
 // @Produce  json
 // @Success  200  {string}  string  "The config is updated."
 // @Failure  400  {string}  string  "The input is invalid."
 // @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Failure  412  {string}  string  "The config has been modified."
 // @Failure  503  {string}  string  "PD server has no leader."
 // @Router   /config/replicate [post]
 func (h *confHandler) SetReplicationConfig(w http.ResponseWriter, r *http.Request) {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+	if !checkConfigIfMatch(h, w, r, h.getReplicationConfig) {
+		return
+	}
 	config := h.svr.GetReplicationConfig()
+	oldConfig := config.Clone()
 	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &config); err != nil {
 		return
 	}
 
 	if err := h.svr.SetReplicationConfig(*config); err != nil {
+		if errs.ErrChangeRejected.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	h.rd.JSON(w, http.StatusOK, "The config is updated.")
+	var impact *configImpact
+	if wantConfigImpact(r) {
+		impact = computeReplicationConfigImpact(h.svr, oldConfig, h.svr.GetReplicationConfig())
+	}
+	h.respondConfigUpdated(w, r, impact)
 }
 
 // @Tags     config
@@ -562,6 +646,25 @@ func (h *confHandler) GetPDServerConfig(w http.ResponseWriter, _ *http.Request)
 	h.rd.JSON(w, http.StatusOK, h.svr.GetPDServerConfig())
 }
 
+// checkConfigIfMatch responds 412 Precondition Failed if the If-Match header of the
+// request doesn't match the ETag of the current config. It returns whether the
+// request can be proceeded.
+func checkConfigIfMatch[T any](h *confHandler, w http.ResponseWriter, r *http.Request, get func(*http.Request) (T, error)) bool {
+	if r.Header.Get(apiutil.IfMatchHeader) == "" {
+		return true
+	}
+	current, err := get(r)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	if !apiutil.IfMatch(r, current) {
+		h.rd.JSON(w, http.StatusPreconditionFailed, apiutil.PreconditionFailedMsg)
+		return false
+	}
+	return true
+}
+
 func (h *confHandler) GetSchedulingServerConfig() (*config.Config, error) {
 	addr, ok := h.svr.GetServicePrimaryAddr(h.svr.Context(), utils.SchedulingServiceName)
 	if !ok {
@@ -0,0 +1,195 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	sc "github.com/tikv/pd/pkg/schedule/config"
+	"github.com/tikv/pd/server"
+)
+
+// configImpactQuery is the query parameter used to ask for the impact summary.
+const configImpactQuery = "impact"
+
+// configUpdated is the response of a config update with the impact summary.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type configUpdated struct {
+	Message string        `json:"message"`
+	Impact  *configImpact `json:"impact"`
+}
+
+// configImpact summarizes which subsystems are affected by a config change and
+// whether the change requires rescheduling activity.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type configImpact struct {
+	ChangedItems       []string `json:"changed-items"`
+	AffectedSubsystems []string `json:"affected-subsystems"`
+	NeedReschedule     bool     `json:"need-reschedule"`
+	// AffectedRegionCount is the estimated number of the regions which need
+	// to be rescheduled because of the change, -1 means it's unknown.
+	AffectedRegionCount int      `json:"affected-region-count"`
+	Details             []string `json:"details,omitempty"`
+}
+
+func wantConfigImpact(r *http.Request) bool {
+	return r.URL.Query().Get(configImpactQuery) == "true"
+}
+
+// respondConfigUpdated responds the config update with the impact summary if it's asked.
+func (h *confHandler) respondConfigUpdated(w http.ResponseWriter, r *http.Request, impact *configImpact) {
+	if !wantConfigImpact(r) {
+		h.rd.JSON(w, http.StatusOK, "The config is updated.")
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, &configUpdated{Message: "The config is updated.", Impact: impact})
+}
+
+// diffConfigItems returns the json keys of the items which are different in the two configs.
+func diffConfigItems(oldCfg, newCfg any) []string {
+	oldMap, newMap := make(map[string]any), make(map[string]any)
+	if data, err := json.Marshal(oldCfg); err == nil {
+		_ = json.Unmarshal(data, &oldMap)
+	}
+	if data, err := json.Marshal(newCfg); err == nil {
+		_ = json.Unmarshal(data, &newMap)
+	}
+	var changed []string
+	for k, v := range newMap {
+		if fmt.Sprint(oldMap[k]) != fmt.Sprint(v) {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func newConfigImpact() *configImpact {
+	return &configImpact{ChangedItems: []string{}, AffectedSubsystems: []string{}}
+}
+
+func (c *configImpact) addSubsystem(subsystem string) {
+	for _, s := range c.AffectedSubsystems {
+		if s == subsystem {
+			return
+		}
+	}
+	c.AffectedSubsystems = append(c.AffectedSubsystems, subsystem)
+}
+
+func (c *configImpact) merge(other *configImpact) {
+	c.ChangedItems = append(c.ChangedItems, other.ChangedItems...)
+	for _, s := range other.AffectedSubsystems {
+		c.addSubsystem(s)
+	}
+	c.NeedReschedule = c.NeedReschedule || other.NeedReschedule
+	if other.AffectedRegionCount != 0 {
+		if c.AffectedRegionCount < 0 || other.AffectedRegionCount < 0 {
+			c.AffectedRegionCount = -1
+		} else {
+			c.AffectedRegionCount += other.AffectedRegionCount
+		}
+	}
+	c.Details = append(c.Details, other.Details...)
+}
+
+// computeScheduleConfigImpact computes the impact of the schedule config change.
+func computeScheduleConfigImpact(oldCfg, newCfg *sc.ScheduleConfig) *configImpact {
+	impact := newConfigImpact()
+	for _, item := range diffConfigItems(oldCfg, newCfg) {
+		impact.ChangedItems = append(impact.ChangedItems, "schedule."+item)
+		if itemImpact, ok := sc.GetScheduleItemImpact(item); ok {
+			impact.addSubsystem(itemImpact.Subsystem)
+			impact.NeedReschedule = impact.NeedReschedule || itemImpact.NeedReschedule
+		}
+	}
+	return impact
+}
+
+// computeReplicationConfigImpact computes the impact of the replication config change.
+func computeReplicationConfigImpact(svr *server.Server, oldCfg, newCfg *sc.ReplicationConfig) *configImpact {
+	impact := newConfigImpact()
+	for _, item := range diffConfigItems(oldCfg, newCfg) {
+		impact.ChangedItems = append(impact.ChangedItems, "replication."+item)
+	}
+	if len(impact.ChangedItems) == 0 {
+		return impact
+	}
+	if oldCfg.EnablePlacementRules != newCfg.EnablePlacementRules {
+		impact.addSubsystem(sc.SubsystemRuleChecker)
+		impact.addSubsystem(sc.SubsystemReplicaChecker)
+		impact.Details = append(impact.Details, "the checker of the replicas is switched")
+	}
+	if oldCfg.MaxReplicas != newCfg.MaxReplicas {
+		impact.NeedReschedule = true
+		impact.addSubsystem(replicaSubsystem(newCfg))
+		impact.AffectedRegionCount = countRegionsWithoutReplicas(svr, newCfg)
+		if impact.AffectedRegionCount > 0 {
+			action := "new peers"
+			if newCfg.MaxReplicas < oldCfg.MaxReplicas {
+				action = "removing peers"
+			}
+			impact.Details = append(impact.Details,
+				fmt.Sprintf("%d regions need %s to reach %d replicas", impact.AffectedRegionCount, action, newCfg.MaxReplicas))
+		}
+	}
+	if strings.Join(oldCfg.LocationLabels, ",") != strings.Join(newCfg.LocationLabels, ",") || oldCfg.IsolationLevel != newCfg.IsolationLevel {
+		impact.NeedReschedule = true
+		impact.addSubsystem(replicaSubsystem(newCfg))
+		impact.Details = append(impact.Details, "replicas may be moved to satisfy the new isolation requirement")
+		if impact.AffectedRegionCount == 0 {
+			impact.AffectedRegionCount = -1
+		}
+	}
+	return impact
+}
+
+func replicaSubsystem(cfg *sc.ReplicationConfig) string {
+	if cfg.EnablePlacementRules {
+		return sc.SubsystemRuleChecker
+	}
+	return sc.SubsystemReplicaChecker
+}
+
+// countRegionsWithoutReplicas returns the number of the regions whose replicas
+// don't match the config, -1 means it's unknown. If the placement rules are
+// enabled, the regions which don't fit the rules are counted, otherwise the
+// regions whose voter count is not equal to the max replicas are counted.
+func countRegionsWithoutReplicas(svr *server.Server, cfg *sc.ReplicationConfig) int {
+	rc := svr.GetRaftCluster()
+	if rc == nil {
+		return -1
+	}
+	count := 0
+	if cfg.EnablePlacementRules {
+		ruleManager := rc.GetRuleManager()
+		for _, region := range rc.GetRegions() {
+			if !ruleManager.FitRegion(rc, region).IsSatisfied() {
+				count++
+			}
+		}
+		return count
+	}
+	for _, region := range rc.GetRegions() {
+		if len(region.GetVoters()) != int(cfg.MaxReplicas) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+	sc "github.com/tikv/pd/pkg/schedule/config"
+	"github.com/tikv/pd/pkg/schedule/placement"
+	"github.com/tikv/pd/server"
+)
+
+func TestScheduleConfigImpact(t *testing.T) {
+	re := require.New(t)
+	oldCfg := &sc.ScheduleConfig{MaxMergeRegionSize: 20, LeaderScheduleLimit: 4}
+	newCfg := oldCfg.Clone()
+	impact := computeScheduleConfigImpact(oldCfg, newCfg)
+	re.Empty(impact.ChangedItems)
+	re.False(impact.NeedReschedule)
+
+	newCfg.LeaderScheduleLimit = 8
+	impact = computeScheduleConfigImpact(oldCfg, newCfg)
+	re.Equal([]string{"schedule.leader-schedule-limit"}, impact.ChangedItems)
+	re.Equal([]string{sc.SubsystemOperatorLimit}, impact.AffectedSubsystems)
+	re.False(impact.NeedReschedule)
+
+	newCfg.MaxMergeRegionSize = 0
+	impact = computeScheduleConfigImpact(oldCfg, newCfg)
+	re.Len(impact.ChangedItems, 2)
+	re.Contains(impact.AffectedSubsystems, sc.SubsystemMergeChecker)
+	re.True(impact.NeedReschedule)
+
+	other := newConfigImpact()
+	other.AffectedSubsystems = []string{sc.SubsystemMergeChecker, sc.SubsystemRuleChecker}
+	other.AffectedRegionCount = 3
+	impact.merge(other)
+	re.Len(impact.AffectedSubsystems, 3)
+	re.Equal(3, impact.AffectedRegionCount)
+	other.AffectedRegionCount = -1
+	impact.merge(other)
+	re.Equal(-1, impact.AffectedRegionCount)
+}
+
+func TestCountRegionsWithoutReplicas(t *testing.T) {
+	re := require.New(t)
+	svr, cleanup := mustNewServer(re)
+	defer cleanup()
+	server.MustWaitLeader(re, []*server.Server{svr})
+	mustBootstrapCluster(re, svr)
+	for id := uint64(1); id <= 3; id++ {
+		mustPutStore(re, svr, id, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+	}
+	// 2 voters and 1 learner are required by the rules.
+	ruleManager := svr.GetRaftCluster().GetRuleManager()
+	re.NoError(ruleManager.SetRule(&placement.Rule{GroupID: placement.DefaultGroupID, ID: placement.DefaultRuleID, Role: placement.Voter, Count: 2}))
+	re.NoError(ruleManager.SetRule(&placement.Rule{GroupID: placement.DefaultGroupID, ID: "learner", Role: placement.Learner, Count: 1}))
+
+	newRegion := func(id uint64, start, end string, learner bool, storeIDs ...uint64) *core.RegionInfo {
+		peers := make([]*metapb.Peer, 0, len(storeIDs))
+		for _, storeID := range storeIDs {
+			peers = append(peers, &metapb.Peer{Id: id*10 + storeID, StoreId: storeID})
+		}
+		if learner {
+			peers[len(peers)-1].Role = metapb.PeerRole_Learner
+		}
+		return core.NewRegionInfo(&metapb.Region{
+			Id:          id,
+			StartKey:    []byte(start),
+			EndKey:      []byte(end),
+			Peers:       peers,
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 2},
+		}, peers[0])
+	}
+	// 3 voters: too many voters for the rules.
+	mustRegionHeartbeat(re, svr, newRegion(10, "", "a", false, 1, 2, 3))
+	// 2 voters and 1 learner: fit the rules.
+	mustRegionHeartbeat(re, svr, newRegion(11, "a", "b", true, 1, 2, 3))
+	// 2 voters: the learner is missing.
+	mustRegionHeartbeat(re, svr, newRegion(12, "b", "", false, 1, 2))
+
+	cfg := &sc.ReplicationConfig{MaxReplicas: 2, EnablePlacementRules: true}
+	re.Equal(2, countRegionsWithoutReplicas(svr, cfg))
+	// Only the voters are counted without the placement rules.
+	cfg.EnablePlacementRules = false
+	re.Equal(1, countRegionsWithoutReplicas(svr, cfg))
+}
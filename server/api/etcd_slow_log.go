@@ -0,0 +1,53 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/tikv/pd/pkg/utils/etcdutil"
+	"github.com/tikv/pd/pkg/utils/typeutil"
+	"github.com/unrolled/render"
+)
+
+// etcdSlowLog is the recent slow etcd operations and their statistics by the key prefix.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type etcdSlowLog struct {
+	Threshold  typeutil.Duration            `json:"threshold"`
+	Prefixes   []etcdutil.SlowOpPrefixStats `json:"prefixes"`
+	Operations []etcdutil.SlowOp            `json:"operations"`
+}
+
+type etcdSlowLogHandler struct {
+	rd *render.Render
+}
+
+func newEtcdSlowLogHandler(rd *render.Render) *etcdSlowLogHandler {
+	return &etcdSlowLogHandler{rd: rd}
+}
+
+// @Tags     debug
+// @Summary  Get the recent slow etcd operations of this PD server.
+// @Param    prefix  query  string  false  "Only return the operations whose key has the prefix"
+// @Produce  json
+// @Success  200  {object}  etcdSlowLog
+// @Router   /debug/etcd/slow-ops [get]
+func (h *etcdSlowLogHandler) GetSlowOps(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, &etcdSlowLog{
+		Threshold:  typeutil.NewDuration(etcdutil.GetSlowOpThreshold()),
+		Prefixes:   etcdutil.GetSlowOpPrefixStats(),
+		Operations: etcdutil.GetSlowOps(r.URL.Query().Get("prefix")),
+	})
+}
@@ -132,6 +132,32 @@ func (h *hotStatusHandler) GetHotBuckets(w http.ResponseWriter, r *http.Request)
 	h.rd.JSON(w, http.StatusOK, ret)
 }
 
+// @Tags     hotspot
+// @Summary  Analyze the hot regions and recommend the actions to mitigate the hotspot.
+// @Param    type  query  string  false  "The type of the hotspot, write or read"  default(write)
+// @Produce  json
+// @Success  200  {object}  handler.HotAnalysis
+// @Failure  400  {string}  string  "The request is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /hotspot/analysis [get]
+func (h *hotStatusHandler) AnalyzeHotRegions(w http.ResponseWriter, r *http.Request) {
+	typ := utils.Write
+	switch t := r.URL.Query().Get("type"); t {
+	case "", utils.Write.String():
+	case utils.Read.String():
+		typ = utils.Read
+	default:
+		h.rd.JSON(w, http.StatusBadRequest, fmt.Sprintf("invalid hotspot type: %s", t))
+		return
+	}
+	analysis, err := h.Handler.AnalyzeHotRegions(typ)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, analysis)
+}
+
 // @Tags     hotspot
 // @Summary  List the history hot regions.
 // @Accept   json
@@ -15,13 +15,17 @@
 package api
 
 import (
+	"bufio"
 	"context"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/pingcap/failpoint"
 	"github.com/tikv/pd/pkg/audit"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/pkg/utils/requestutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/cluster"
@@ -96,10 +100,57 @@ func (m clusterMiddleware) Middleware(h http.Handler) http.Handler {
 			return
 		}
 		ctx := context.WithValue(r.Context(), clusterCtxKey{}, rc)
-		h.ServeHTTP(w, r.WithContext(ctx))
+		h.ServeHTTP(&stateEpochResponseWriter{ResponseWriter: w, rc: rc}, r.WithContext(ctx))
 	})
 }
 
+// stateEpochResponseWriter attaches the cluster state epoch to the response
+// header. The epoch is read right before the header is written, so that the
+// changes made by the request itself are reflected.
+type stateEpochResponseWriter struct {
+	http.ResponseWriter
+	rc          *cluster.RaftCluster
+	wroteHeader bool
+}
+
+func (w *stateEpochResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Header().Set(apiutil.PDClusterStateEpochHeader, strconv.FormatUint(w.rc.GetStateEpoch(), 10))
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *stateEpochResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher.
+func (w *stateEpochResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker.
+func (w *stateEpochResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap returns the wrapped writer, it's used by http.ResponseController.
+func (w *stateEpochResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 type clusterCtxKey struct{}
 
 func getCluster(r *http.Request) *cluster.RaftCluster {
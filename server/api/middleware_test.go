@@ -0,0 +1,51 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	return nil, nil, nil
+}
+
+func TestStateEpochResponseWriterPassThrough(t *testing.T) {
+	re := require.New(t)
+	recorder := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+	var w http.ResponseWriter = &stateEpochResponseWriter{ResponseWriter: recorder}
+	hijacker, ok := w.(http.Hijacker)
+	re.True(ok)
+	_, _, err := hijacker.Hijack()
+	re.NoError(err)
+	re.True(recorder.hijacked)
+
+	// the writer doesn't pretend to support hijacking.
+	w = &stateEpochResponseWriter{ResponseWriter: httptest.NewRecorder()}
+	_, _, err = w.(http.Hijacker).Hijack()
+	re.ErrorIs(err, http.ErrNotSupported)
+}
@@ -15,11 +15,14 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/schedule/handler"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/server"
@@ -66,10 +69,13 @@ func (h *operatorHandler) GetOperatorsByRegion(w http.ResponseWriter, r *http.Re
 
 // @Tags     operator
 // @Summary  List pending operators.
-// @Param    kind   query  string  false  "Specify the operator kind."  Enums(admin, leader, region)
-// @Param    object query  bool    false  "Whether to return as JSON object."
+// @Param    kind        query  string   false  "Specify the operator kind."  Enums(admin, leader, region)
+// @Param    object      query  bool     false  "Whether to return as JSON object."
+// @Param    page_token  query  string   false  "The X-Next-Page-Token header of the previous page"
+// @Param    limit       query  integer  false  "Limit count of the page"
 // @Produce  json
 // @Success  200  {array}   operator.Operator
+// @Failure  400  {string}  string  "The input is invalid."
 // @Failure  500  {string}  string  "PD server failed to proceed the request."
 // @Router   /operators [get]
 func (h *operatorHandler) GetOperators(w http.ResponseWriter, r *http.Request) {
@@ -78,6 +84,11 @@ func (h *operatorHandler) GetOperators(w http.ResponseWriter, r *http.Request) {
 		err     error
 	)
 
+	page, err := apiutil.ParsePageQuery(r.URL.Query())
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	kinds, ok := r.URL.Query()["kind"]
 	_, objectFlag := r.URL.Query()["object"]
 	if !ok {
@@ -90,6 +101,13 @@ func (h *operatorHandler) GetOperators(w http.ResponseWriter, r *http.Request) {
 		h.r.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	// The operators are sorted by the region ID, there is at most one operator of a region.
+	results, nextPageToken, err := apiutil.PaginateByID(results, page, (*operator.Operator).RegionID)
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	apiutil.SetNextPageToken(w, nextPageToken)
 	if objectFlag {
 		objResults := make([]*operator.OpObject, len(results))
 		for i, op := range results {
@@ -169,6 +187,43 @@ func (h *operatorHandler) DeleteOperatorByRegion(w http.ResponseWriter, r *http.
 	h.r.JSON(w, http.StatusOK, "The pending operator is canceled.")
 }
 
+// @Tags     operator
+// @Summary  Move a Region to the desired placement with the minimal steps planned by PD.
+// @Param    region_id  path  int                      true  "A Region's Id"
+// @Param    body       body  handler.RegionPlacement  true  "The desired peers and their roles"
+// @Accept   json
+// @Produce  json
+// @Success  200  {object}  handler.PlacementPlan
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The region does not exist."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /operators/{region_id}/placement [post]
+func (h *operatorHandler) SetRegionPlacement(w http.ResponseWriter, r *http.Request) {
+	regionID, err := strconv.ParseUint(mux.Vars(r)["region_id"], 10, 64)
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var input handler.RegionPlacement
+	if err := apiutil.ReadJSONRespondError(h.r, w, r.Body, &input); err != nil {
+		return
+	}
+	if err := input.Validate(); err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	plan, err := h.AddRegionPlacementOperator(regionID, &input)
+	if err != nil {
+		if errs.ErrRegionNotFound.Equal(err) {
+			h.r.JSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, plan)
+}
+
 // @Tags     operator
 // @Summary  lists the finished operators since the given timestamp in second.
 // @Param    from  query  integer  false  "From Unix timestamp"
@@ -196,3 +251,62 @@ func (h *operatorHandler) GetOperatorRecords(w http.ResponseWriter, r *http.Requ
 	}
 	h.r.JSON(w, http.StatusOK, records)
 }
+
+// @Tags     operator
+// @Summary  Watch the step progress of the operators on the given regions, the events are streamed as the JSON lines until all the operators end or the request is canceled.
+// @Param    region_id  query  integer  true  "The region ID, it can be given multiple times to watch a batch of operators"
+// @Produce  json
+// @Success  200  {array}   operator.ProgressEvent
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /operators/progress [get]
+func (h *operatorHandler) WatchOperatorProgress(w http.ResponseWriter, r *http.Request) {
+	ids := r.URL.Query()["region_id"]
+	if len(ids) == 0 {
+		h.r.JSON(w, http.StatusBadRequest, "region_id is required")
+		return
+	}
+	regionIDs := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		regionID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			h.r.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		regionIDs = append(regionIDs, regionID)
+	}
+	if _, err := h.GetOperatorController(); err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	_ = h.Handler.WatchOperatorProgress(r.Context(), regionIDs, func(events []*operator.ProgressEvent) error {
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+}
+
+// @Tags     operator
+// @Summary  lists the stores which are temporarily excluded as the leader target after failed leader transfers.
+// @Produce  json
+// @Success  200  {object}  []operator.LeaderTransferExclusion
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /operators/leader-transfer-blacklist [get]
+func (h *operatorHandler) GetLeaderTransferBlacklist(w http.ResponseWriter, _ *http.Request) {
+	exclusions, err := h.Handler.GetLeaderTransferBlacklist()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, exclusions)
+}
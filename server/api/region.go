@@ -76,11 +76,38 @@ func (h *regionHandler) GetRegionByID(w http.ResponseWriter, r *http.Request) {
 	h.rd.Data(w, http.StatusOK, b)
 }
 
+// @Tags     region
+// @Summary  Get the metadata of a removed region by its original region ID.
+// @Param    id  path  integer  true  "Region Id"
+// @Produce  json
+// @Success  200  {object}  core.RegionTombstone
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The region is not found in the tombstone history."
+// @Router   /region/id/{id}/tombstone [get]
+func (h *regionHandler) GetRegionTombstone(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+
+	regionID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tombstone := rc.GetRegionTombstone(regionID)
+	if tombstone == nil {
+		h.rd.JSON(w, http.StatusNotFound, fmt.Sprintf("region %d is not found in the tombstone history", regionID))
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, tombstone)
+}
+
 // @Tags     region
 // @Summary  Search for a region by a key. GetRegion is named to be consistent with gRPC
 // @Param    key  path  string  true  "Region key"
 // @Produce  json
 // @Success  200  {object}  response.RegionInfo
+// @Param    keyspace       query  string  false  "Keyspace name, the key is translated into the region key of the keyspace"
+// @Param    keyspace_id    query  string  false  "Keyspace ID, it overrides the keyspace name"
+// @Param    keyspace_mode  query  string  false  "The mode of the keyspace key, txn or raw"  default(txn)
 // @Router   /region/key/{key} [get]
 func (h *regionHandler) GetRegion(w http.ResponseWriter, r *http.Request) {
 	rc := getCluster(r)
@@ -97,6 +124,11 @@ func (h *regionHandler) GetRegion(w http.ResponseWriter, r *http.Request) {
 		h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	paramsByte, err = translateKeyspaceKeys(h.svr, r.URL.Query(), paramsByte)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	regionInfo := rc.GetRegionByKey(paramsByte[0])
 	b, err := response.MarshalRegionInfoJSON(r.Context(), regionInfo)
@@ -144,13 +176,34 @@ func newRegionsHandler(svr *server.Server, rd *render.Render) *regionsHandler {
 
 // @Tags     region
 // @Summary  List all regions in the cluster.
+// @Param    page_token  query  string   false  "The next_page_token of the previous page"
+// @Param    limit       query  integer  false  "Limit count of the page"
 // @Produce  json
 // @Success  200  {object}  response.RegionsInfo
+// @Failure  400  {string}  string  "The input is invalid."
 // @Router   /regions [get]
 func (h *regionsHandler) GetRegions(w http.ResponseWriter, r *http.Request) {
 	rc := getCluster(r)
-	regions := rc.GetRegions()
-	b, err := response.MarshalRegionsInfoJSON(r.Context(), regions)
+	page, err := apiutil.ParsePageQuery(r.URL.Query())
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var (
+		regions       []*core.RegionInfo
+		nextPageToken string
+	)
+	if page.IsPaged() {
+		regions, nextPageToken, err = response.ScanRegionsPage(rc.ScanRegions, nil, nil, page)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	} else {
+		regions = rc.GetRegions()
+	}
+	apiutil.SetNextPageToken(w, nextPageToken)
+	b, err := response.MarshalRegionsPageJSON(r.Context(), regions, nextPageToken)
 	if err != nil {
 		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
 		return
@@ -160,9 +213,13 @@ func (h *regionsHandler) GetRegions(w http.ResponseWriter, r *http.Request) {
 
 // @Tags     region
 // @Summary  List regions in a given range [startKey, endKey).
-// @Param    key     query  string   true   "Region range start key"
-// @Param    endkey  query  string   true   "Region range end key"
-// @Param    limit   query  integer  false  "Limit count"  default(16)
+// @Param    key         query  string   true   "Region range start key"
+// @Param    endkey      query  string   true   "Region range end key"
+// @Param    limit       query  integer  false  "Limit count"  default(16)
+// @Param    page_token  query  string   false  "The next_page_token of the previous page, it overrides the start key"
+// @Param    keyspace       query  string  false  "Keyspace name, the keys are translated into the region keys of the keyspace, the empty end key means the end of the keyspace"
+// @Param    keyspace_id    query  string  false  "Keyspace ID, it overrides the keyspace name"
+// @Param    keyspace_mode  query  string  false  "The mode of the keyspace keys, txn or raw"  default(txn)
 // @Produce  json
 // @Success  200  {object}  response.RegionsInfo
 // @Failure  400  {string}  string  "The input is invalid."
@@ -176,6 +233,11 @@ func (h *regionsHandler) ScanRegions(w http.ResponseWriter, r *http.Request) {
 		h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	paramsByte, err = translateKeyspaceKeys(h.svr, query, paramsByte)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	limit, err := h.AdjustLimit(query.Get("limit"))
 	if err != nil {
@@ -183,8 +245,14 @@ func (h *regionsHandler) ScanRegions(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	regions := rc.ScanRegions(paramsByte[0], paramsByte[1], limit)
-	b, err := response.MarshalRegionsInfoJSON(r.Context(), regions)
+	page := &apiutil.PageQuery{Token: query.Get(apiutil.PageTokenParam), Limit: limit}
+	regions, nextPageToken, err := response.ScanRegionsPage(rc.ScanRegions, paramsByte[0], paramsByte[1], page)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	apiutil.SetNextPageToken(w, nextPageToken)
+	b, err := response.MarshalRegionsPageJSON(r.Context(), regions, nextPageToken)
 	if err != nil {
 		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
 		return
@@ -192,6 +260,33 @@ func (h *regionsHandler) ScanRegions(w http.ResponseWriter, r *http.Request) {
 	h.rd.Data(w, http.StatusOK, b)
 }
 
+// @Tags     region
+// @Summary  List the recent split and merge events overlapping with a given range [startKey, endKey), from the newest to the oldest.
+// @Param    key      query  string   false  "Range start key"
+// @Param    end_key  query  string   false  "Range end key, the empty end key means the end of the key space"
+// @Param    format   query  string   false  "The format of the keys, hex or raw"
+// @Param    limit    query  integer  false  "Limit count"  default(16)
+// @Produce  json
+// @Success  200  {array}   cluster.RegionEvent
+// @Failure  400  {string}  string  "The input is invalid."
+// @Router   /regions/events [get]
+func (h *regionsHandler) GetRegionEvents(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	query := r.URL.Query()
+	paramsByte := [][]byte{[]byte(query.Get("key")), []byte(query.Get("end_key"))}
+	paramsByte, err := apiutil.ParseHexKeys(query.Get("format"), paramsByte)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := h.AdjustLimit(query.Get("limit"))
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, rc.GetRegionEvents(paramsByte[0], paramsByte[1], limit))
+}
+
 // @Tags     region
 // @Summary  Get count of regions.
 // @Produce  json
@@ -286,6 +381,48 @@ func (h *regionsHandler) GetKeyspaceRegions(w http.ResponseWriter, r *http.Reque
 	h.rd.Data(w, http.StatusOK, b)
 }
 
+// translateKeyspaceKeys translates the user keys into the region keys of the
+// keyspace if it's specified by the query, so that the tenants can query their
+// regions without knowing the internal encoding. The keys are either a single
+// key or a pair of the start key and the end key.
+func translateKeyspaceKeys(svr *server.Server, query url.Values, keys [][]byte) ([][]byte, error) {
+	name, idStr := query.Get("keyspace"), query.Get("keyspace_id")
+	if name == "" && idStr == "" {
+		return keys, nil
+	}
+	var id uint32
+	manager := svr.GetKeyspaceManager()
+	if idStr != "" {
+		id64, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		id = uint32(id64)
+		if _, err := manager.LoadKeyspaceByID(id); err != nil {
+			return nil, err
+		}
+	} else {
+		meta, err := manager.LoadKeyspace(name)
+		if err != nil {
+			return nil, err
+		}
+		id = meta.GetId()
+	}
+	mode := query.Get("keyspace_mode")
+	if len(keys) == 1 {
+		key, err := keyspace.MakeRegionKey(id, mode, keys[0])
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{key}, nil
+	}
+	start, end, err := keyspace.MakeRegionKeyRange(id, mode, keys[0], keys[1])
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{start, end}, nil
+}
+
 // @Tags     region
 // @Summary  List all regions that miss peer.
 // @Produce  json
@@ -740,6 +877,83 @@ func (h *regionsHandler) AccelerateRegionsScheduleInRanges(w http.ResponseWriter
 	h.rd.Text(w, http.StatusOK, msgBuilder.String())
 }
 
+// @Tags     region
+// @Summary  Merge the undersized regions in a given range aggressively, only receive hex format for keys
+// @Accept   json
+// @Param    body  body  object  true  "json params"
+// @Produce  json
+// @Success  200  {object}  checker.RangeMergeProgress
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /regions/merge [post]
+func (h *regionsHandler) MergeRegionsInRange(w http.ResponseWriter, r *http.Request) {
+	var input map[string]any
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	rawStartKey, ok1 := input["start_key"].(string)
+	rawEndKey, ok2 := input["end_key"].(string)
+	if !ok1 || !ok2 {
+		h.rd.JSON(w, http.StatusBadRequest, "start_key or end_key is not string")
+		return
+	}
+	targetCount := 1
+	if tc, ok := input["target_count"].(float64); ok {
+		targetCount = int(tc)
+	}
+	progress, err := h.Handler.MergeRegionsInRange(rawStartKey, rawEndKey, targetCount)
+	if err != nil {
+		if errs.ErrRangeMergeInvalidRange.Equal(err) || errs.ErrRangeMergeTooManyJobs.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, progress)
+}
+
+// @Tags     region
+// @Summary  List the progress of the recent range merge jobs.
+// @Produce  json
+// @Success  200  {array}   checker.RangeMergeProgress
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /regions/merge [get]
+func (h *regionsHandler) GetRangeMergeJobs(w http.ResponseWriter, _ *http.Request) {
+	jobs, err := h.Handler.GetRangeMergeJobs()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, jobs)
+}
+
+// @Tags     region
+// @Summary  Cancel the running range merge job.
+// @Param    id  path  integer  true  "Job Id"
+// @Produce  json
+// @Success  200  {string}  string  "The job is canceled."
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The job does not exist."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /regions/merge/{id} [delete]
+func (h *regionsHandler) CancelRangeMergeJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.Handler.CancelRangeMergeJob(id); err != nil {
+		if errs.ErrRangeMergeJobNotFound.Equal(err) {
+			h.rd.JSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The job is canceled.")
+}
+
 func (h *regionsHandler) GetTopNRegions(w http.ResponseWriter, r *http.Request, less func(a, b *core.RegionInfo) bool) {
 	rc := getCluster(r)
 	limit, err := h.AdjustLimit(r.URL.Query().Get("limit"))
@@ -757,7 +971,7 @@ func (h *regionsHandler) GetTopNRegions(w http.ResponseWriter, r *http.Request,
 }
 
 // @Tags     region
-// @Summary  Scatter regions by given key ranges or regions id distributed by given group with given retry limit
+// @Summary  Scatter regions by given key ranges or regions id distributed by given group with given retry limit, the target stores can be limited by the store group
 // @Accept   json
 // @Param    body  body  object  true  "json params"
 // @Produce  json
@@ -772,6 +986,7 @@ func (h *regionsHandler) ScatterRegions(w http.ResponseWriter, r *http.Request)
 	rawStartKey, ok1 := input["start_key"].(string)
 	rawEndKey, ok2 := input["end_key"].(string)
 	group, _ := input["group"].(string)
+	storeGroup, _ := input["store_group"].(string)
 	retryLimit := 5
 	if rl, ok := input["retry_limit"].(float64); ok {
 		retryLimit = int(rl)
@@ -779,13 +994,13 @@ func (h *regionsHandler) ScatterRegions(w http.ResponseWriter, r *http.Request)
 
 	opsCount, failures, err := func() (int, map[uint64]error, error) {
 		if ok1 && ok2 {
-			return h.ScatterRegionsByRange(rawStartKey, rawEndKey, group, retryLimit)
+			return h.ScatterRegionsByRange(rawStartKey, rawEndKey, group, retryLimit, storeGroup)
 		}
 		ids, ok := typeutil.JSONToUint64Slice(input["regions_id"])
 		if !ok {
 			return 0, nil, errors.New("regions_id is invalid")
 		}
-		return h.ScatterRegionsByID(ids, group, retryLimit)
+		return h.ScatterRegionsByID(ids, group, retryLimit, storeGroup)
 	}()
 	if err != nil {
 		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
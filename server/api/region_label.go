@@ -15,11 +15,17 @@
 package api
 
 import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/schedule/labeler"
 	"github.com/tikv/pd/pkg/utils/apiutil"
@@ -27,6 +33,9 @@ import (
 	"github.com/unrolled/render"
 )
 
+// maxLabelRulesImportSize is the max size of the imported label rules, before and after decompression.
+const maxLabelRulesImportSize = 32 << 20
+
 type regionLabelHandler struct {
 	svr *server.Server
 	rd  *render.Render
@@ -47,6 +56,9 @@ func newRegionLabelHandler(s *server.Server, rd *render.Render) *regionLabelHand
 func (h *regionLabelHandler) GetAllRegionLabelRules(w http.ResponseWriter, r *http.Request) {
 	cluster := getCluster(r)
 	rules := cluster.GetRegionLabeler().GetAllLabelRules()
+	if apiutil.WriteETag(w, r, rules) {
+		return
+	}
 	h.rd.JSON(w, http.StatusOK, rules)
 }
 
@@ -57,6 +69,7 @@ func (h *regionLabelHandler) GetAllRegionLabelRules(w http.ResponseWriter, r *ht
 // @Produce  json
 // @Success  200  {string}  string  "Update region label rules successfully."
 // @Failure  400  {string}  string  "The input is invalid."
+// @Failure  412  {string}  string  "The rules have been modified."
 // @Failure  500  {string}  string  "PD server failed to proceed the request."
 // @Router   /config/region-label/rules [patch]
 func (h *regionLabelHandler) PatchRegionLabelRules(w http.ResponseWriter, r *http.Request) {
@@ -65,8 +78,10 @@ func (h *regionLabelHandler) PatchRegionLabelRules(w http.ResponseWriter, r *htt
 	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &patch); err != nil {
 		return
 	}
-	if err := cluster.GetRegionLabeler().Patch(patch); err != nil {
-		if errs.ErrRegionRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+	if err := cluster.GetRegionLabeler().PatchIfMatch(patch, apiutil.IfMatchCheck[[]*labeler.LabelRule](r)); err != nil {
+		if errs.ErrPreconditionFailed.Equal(err) {
+			h.rd.JSON(w, http.StatusPreconditionFailed, apiutil.PreconditionFailedMsg)
+		} else if errs.ErrRegionRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
 			h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		} else {
 			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
@@ -76,6 +91,82 @@ func (h *regionLabelHandler) PatchRegionLabelRules(w http.ResponseWriter, r *htt
 	h.rd.JSON(w, http.StatusOK, "Update region label rules successfully.")
 }
 
+// @Tags     region_label
+// @Summary  Export all label rules of cluster, the response is compressed by gzip if the client accepts it.
+// @Produce  json
+// @Success  200  {array}  labeler.LabelRule
+// @Router   /config/region-label/rules/export [get]
+func (h *regionLabelHandler) ExportRegionLabelRules(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	rules := cluster.GetRegionLabeler().GetAllLabelRules()
+	w.Header().Set("Content-Type", "application/json")
+	var out io.Writer = w
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		defer gw.Close()
+		out = gw
+	}
+	w.WriteHeader(http.StatusOK)
+	if err := apiutil.WriteJSONStream(out, rules); err != nil {
+		log.Warn("failed to export label rules", errs.ZapError(err))
+	}
+}
+
+// @Tags     region_label
+// @Summary  Replace all label rules of cluster with the imported ones, the request body can be compressed by gzip.
+// @Accept   json
+// @Param    rules    body   []labeler.LabelRule  true   "All the label rules"
+// @Param    dry_run  query  bool                 false  "Only return the difference without applying it"
+// @Produce  json
+// @Success  200  {object}  labeler.LabelRuleDiff
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  413  {string}  string  "The request body is too large."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /config/region-label/rules/import [post]
+func (h *regionLabelHandler) ImportRegionLabelRules(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r)
+	dryRun := false
+	if dryRunStr := r.URL.Query().Get("dry_run"); dryRunStr != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(dryRunStr); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	var body io.ReadCloser = http.MaxBytesReader(w, r.Body, maxLabelRulesImportSize)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gr, err := gzip.NewReader(body)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		defer gr.Close()
+		// Limit the decompressed size as well.
+		body = http.MaxBytesReader(w, gr, maxLabelRulesImportSize)
+	}
+	var rules []*labeler.LabelRule
+	if err := json.NewDecoder(body).Decode(&rules); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.rd.JSON(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	diff, err := cluster.GetRegionLabeler().ImportLabelRules(rules, dryRun)
+	if err != nil {
+		if errs.ErrRegionRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, diff)
+}
+
 // @Tags     region_label
 // @Summary  Get label rules of cluster by ids.
 // @Param    body  body  []string  true  "IDs of query rules"
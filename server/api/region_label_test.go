@@ -15,8 +15,11 @@
 package api
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"sort"
 	"testing"
@@ -108,6 +111,62 @@ func (suite *regionLabelTestSuite) TestGetSet() {
 	re.Equal([]*labeler.LabelRule{rules[1], rules[2]}, resp)
 }
 
+func (suite *regionLabelTestSuite) TestImportExport() {
+	re := suite.Require()
+	rules := make([]*labeler.LabelRule, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		rules = append(rules, &labeler.LabelRule{
+			ID:       fmt.Sprintf("bulk-%04d", i),
+			Labels:   []labeler.RegionLabel{{Key: "k", Value: fmt.Sprintf("v%d", i)}},
+			RuleType: "key-range",
+			Data:     makeKeyRanges(fmt.Sprintf("%08x", 2*i), fmt.Sprintf("%08x", 2*i+1)),
+		})
+	}
+	data, err := json.Marshal(rules)
+	re.NoError(err)
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err = gw.Write(data)
+	re.NoError(err)
+	re.NoError(gw.Close())
+	importRules := func(url string) *labeler.LabelRuleDiff {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(buf.Bytes()))
+		re.NoError(err)
+		req.Header.Set("Content-Encoding", "gzip")
+		resp, err := testDialClient.Do(req)
+		re.NoError(err)
+		defer resp.Body.Close()
+		re.Equal(http.StatusOK, resp.StatusCode)
+		var diff labeler.LabelRuleDiff
+		re.NoError(json.NewDecoder(resp.Body).Decode(&diff))
+		return &diff
+	}
+
+	diff := importRules(suite.urlPrefix + "rules/import?dry_run=true")
+	re.False(diff.Applied)
+	re.Len(diff.Added, 1000)
+	diff = importRules(suite.urlPrefix + "rules/import")
+	re.True(diff.Applied)
+	re.Len(diff.Added, 1000)
+
+	var exported []*labeler.LabelRule
+	err = tu.ReadGetJSON(re, testDialClient, suite.urlPrefix+"rules/export", &exported)
+	re.NoError(err)
+	re.Equal(rules, exported)
+	diff = importRules(suite.urlPrefix + "rules/import")
+	re.Equal(1000, diff.Unchanged)
+
+	// the invalid rules are rejected.
+	err = tu.CheckPostJSON(testDialClient, suite.urlPrefix+"rules/import", []byte(`[{"id":"bad","rule_type":"key-range"}]`),
+		tu.Status(re, http.StatusBadRequest))
+	re.NoError(err)
+	err = tu.CheckPostJSON(testDialClient, suite.urlPrefix+"rules/import", []byte(`[]`), tu.StatusOK(re))
+	re.NoError(err)
+	err = tu.ReadGetJSON(re, testDialClient, suite.urlPrefix+"rules/export", &exported)
+	re.NoError(err)
+	re.Empty(exported)
+}
+
 func makeKeyRanges(keys ...string) []any {
 	var res []any
 	for i := 0; i < len(keys); i += 2 {
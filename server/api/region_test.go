@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/tikv/pd/pkg/codec"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/response"
 	"github.com/tikv/pd/pkg/utils/apiutil"
@@ -207,6 +208,25 @@ func (suite *regionTestSuite) TestRegions() {
 		re.Equal(regions[i].ApproximateSize, r.ApproximateSize)
 		re.Equal(regions[i].ApproximateKeys, r.ApproximateKeys)
 	}
+
+	// list the regions page by page in the order of the start key.
+	var pagedIDs []uint64
+	pageToken := ""
+	for {
+		regionsInfo = &response.RegionsInfo{}
+		pageURL := fmt.Sprintf("%s?limit=2&page_token=%s", url, pageToken)
+		re.NoError(tu.ReadGetJSON(re, testDialClient, pageURL, regionsInfo))
+		re.LessOrEqual(regionsInfo.Count, 2)
+		for _, r := range regionsInfo.Regions {
+			pagedIDs = append(pagedIDs, r.ID)
+		}
+		if regionsInfo.NextPageToken == "" {
+			break
+		}
+		pageToken = regionsInfo.NextPageToken
+	}
+	re.Equal([]uint64{2, 3, 4}, pagedIDs)
+	re.NoError(tu.CheckGetJSON(testDialClient, url+"?page_token=xyz", nil, tu.Status(re, http.StatusBadRequest)))
 }
 
 func (suite *regionTestSuite) TestStoreRegions() {
@@ -364,6 +384,47 @@ func TestRegionsWithKillRequest(t *testing.T) {
 	close(doneCh)
 }
 
+func TestKeyspaceRegions(t *testing.T) {
+	re := require.New(t)
+	svr, cleanup := mustNewServer(re)
+	defer cleanup()
+	server.MustWaitLeader(re, []*server.Server{svr})
+
+	urlPrefix := fmt.Sprintf("%s%s/api/v1", svr.GetAddr(), apiPrefix)
+	mustBootstrapCluster(re, svr)
+
+	// the regions of the txn keys in the default keyspace and the next keyspace.
+	rs := []*core.RegionInfo{
+		core.NewTestRegionInfo(2, 1, codec.EncodeBytes([]byte{'x', 0, 0, 0}), codec.EncodeBytes([]byte{'x', 0, 0, 0, 'm'})),
+		core.NewTestRegionInfo(3, 1, codec.EncodeBytes([]byte{'x', 0, 0, 0, 'm'}), codec.EncodeBytes([]byte{'x', 0, 0, 1})),
+		core.NewTestRegionInfo(4, 1, codec.EncodeBytes([]byte{'x', 0, 0, 1}), nil),
+	}
+	for _, r := range rs {
+		mustRegionHeartbeat(re, svr, r)
+	}
+
+	region := &response.RegionInfo{}
+	re.NoError(tu.ReadGetJSON(re, testDialClient, urlPrefix+"/region/key/c?keyspace=DEFAULT", region))
+	re.Equal(uint64(2), region.ID)
+	region = &response.RegionInfo{}
+	re.NoError(tu.ReadGetJSON(re, testDialClient, urlPrefix+"/region/key/n?keyspace_id=0", region))
+	re.Equal(uint64(3), region.ID)
+
+	// the empty end key means the end of the keyspace.
+	regions := &response.RegionsInfo{}
+	re.NoError(tu.ReadGetJSON(re, testDialClient, urlPrefix+"/regions/key?key=c&keyspace_id=0", regions))
+	re.Equal(2, regions.Count)
+	re.Equal(uint64(2), regions.Regions[0].ID)
+	re.Equal(uint64(3), regions.Regions[1].ID)
+	regions = &response.RegionsInfo{}
+	re.NoError(tu.ReadGetJSON(re, testDialClient, urlPrefix+"/regions/key?key=c&end_key=d&keyspace=DEFAULT", regions))
+	re.Equal(1, regions.Count)
+	re.Equal(uint64(2), regions.Regions[0].ID)
+
+	re.NoError(tu.CheckGetJSON(testDialClient, urlPrefix+"/region/key/c?keyspace=unknown", nil, tu.Status(re, http.StatusBadRequest)))
+	re.NoError(tu.CheckGetJSON(testDialClient, urlPrefix+"/region/key/c?keyspace_id=0&keyspace_mode=unknown", nil, tu.Status(re, http.StatusBadRequest)))
+}
+
 type getRegionTestSuite struct {
 	suite.Suite
 	svr       *server.Server
@@ -89,6 +89,9 @@ func getRuleManager(r *http.Request) *placement.RuleManager {
 func (h *ruleHandler) GetAllRules(w http.ResponseWriter, r *http.Request) {
 	manager := getRuleManager(r)
 	rules := manager.GetAllRules()
+	if apiutil.WriteETag(w, r, rules) {
+		return
+	}
 	h.rd.JSON(w, http.StatusOK, rules)
 }
 
@@ -98,11 +101,17 @@ func (h *ruleHandler) GetAllRules(w http.ResponseWriter, r *http.Request) {
 // @Param    rules  body      []placement.Rule  true  "Parameters of rules"
 // @Success  200    {string}  string            "Update rules successfully."
 // @Failure  400    {string}  string            "The input is invalid."
-// @Failure  412    {string}  string            "Placement rules feature is disabled."
+// @Failure  412    {string}  string            "Placement rules feature is disabled or the rules have been modified."
 // @Failure  500    {string}  string            "PD server failed to proceed the request."
 // @Router   /config/rules [post]
 func (h *ruleHandler) SetAllRules(w http.ResponseWriter, r *http.Request) {
 	manager := getRuleManager(r)
+	// Fail fast before syncing the replication config, the ETag is compared
+	// again under the same lock as the write.
+	if !apiutil.IfMatch(r, manager.GetAllRules()) {
+		h.rd.JSON(w, http.StatusPreconditionFailed, apiutil.PreconditionFailedMsg)
+		return
+	}
 	var rules []*placement.Rule
 	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &rules); err != nil {
 		return
@@ -114,8 +123,11 @@ func (h *ruleHandler) SetAllRules(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	if err := manager.SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
-		SetRules(rules); err != nil {
-		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+		SetRulesIfMatch(rules, apiutil.IfMatchCheck[[]*placement.Rule](r)); err != nil {
+		if errs.ErrPreconditionFailed.Equal(err) {
+			h.rd.JSON(w, http.StatusPreconditionFailed, apiutil.PreconditionFailedMsg)
+		} else if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) || errs.ErrStoreGroupNotFound.Equal(err) ||
+			errs.ErrChangeRejected.Equal(err) {
 			h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		} else {
 			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
@@ -212,6 +224,40 @@ func (h *ruleHandler) GetRulesByKey(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, rules)
 }
 
+// @Tags     rule
+// @Summary  Show how the replicas are distributed across the zones versus what the rules prescribe.
+// @Param    start_key   query  string  false  "The start key of the range in hex"
+// @Param    end_key     query  string  false  "The end key of the range in hex"
+// @Param    group       query  string  false  "Only take the rules of the group into account"
+// @Param    zone_label  query  string  false  "The store label key of the zones, default to zone"
+// @Param    page_token  query  string  false  "The next_page_token of the previous page"
+// @Param    limit       query  integer  false  "Limit count of the regions of the page, at most 10240"
+// @Produce  json
+// @Success  200  {object}  placement.ZoneDistribution
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  412  {string}  string  "Placement rules feature is disabled."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /config/rules/zone-distribution [get]
+func (h *ruleHandler) GetZoneDistribution(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, err := apiutil.ParsePageQuery(query)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	distribution, err := h.Handler.GetZoneDistribution(query.Get("start_key"), query.Get("end_key"), query.Get("group"), query.Get("zone_label"), page)
+	if err != nil {
+		if errs.ErrKeyFormat.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	apiutil.SetNextPageToken(w, distribution.NextPageToken)
+	h.rd.JSON(w, http.StatusOK, distribution)
+}
+
 // @Tags     rule
 // @Summary  Get rule of cluster by group and id.
 // @Param    group  path  string  true  "The name of group"
@@ -255,7 +301,8 @@ func (h *ruleHandler) SetRule(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := manager.SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
 		SetRule(&rule); err != nil {
-		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) || errs.ErrStoreGroupNotFound.Equal(err) ||
+			errs.ErrChangeRejected.Equal(err) {
 			h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		} else {
 			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
@@ -325,7 +372,8 @@ func (h *ruleHandler) BatchRules(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := manager.SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
 		Batch(opts); err != nil {
-		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) || errs.ErrStoreGroupNotFound.Equal(err) ||
+			errs.ErrChangeRejected.Equal(err) {
 			h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		} else {
 			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
@@ -418,6 +466,97 @@ func (h *ruleHandler) GetAllGroupConfigs(w http.ResponseWriter, r *http.Request)
 	h.rd.JSON(w, http.StatusOK, ruleGroups)
 }
 
+// @Tags     rule
+// @Summary  Get store group by id.
+// @Param    id  path  string  true  "Store Group Id"
+// @Produce  json
+// @Success  200  {object}  placement.StoreGroup
+// @Failure  404  {string}  string  "The StoreGroup does not exist."
+// @Failure  412  {string}  string  "Placement rules feature is disabled."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /config/store_group/{id} [get]
+func (h *ruleHandler) GetStoreGroup(w http.ResponseWriter, r *http.Request) {
+	manager := getRuleManager(r)
+	id := mux.Vars(r)["id"]
+	group := manager.GetStoreGroup(id)
+	if group == nil {
+		h.rd.JSON(w, http.StatusNotFound, nil)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, group)
+}
+
+// @Tags     rule
+// @Summary  Update store group, the rules targeting it will select the stores by the new selectors.
+// @Accept   json
+// @Param    group  body  placement.StoreGroup  true  "Parameters of store group"
+// @Produce  json
+// @Success  200  {string}  string  "Update store group successfully."
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  412  {string}  string  "Placement rules feature is disabled."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /config/store_group [post]
+func (h *ruleHandler) SetStoreGroup(w http.ResponseWriter, r *http.Request) {
+	manager := getRuleManager(r)
+	var storeGroup placement.StoreGroup
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &storeGroup); err != nil {
+		return
+	}
+	if err := manager.SetStoreGroup(&storeGroup); err != nil {
+		if errs.ErrStoreGroupContent.Equal(err) || errs.ErrChangeRejected.Equal(err) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	cluster := getCluster(r)
+	for _, rule := range manager.GetRulesByStoreGroup(storeGroup.ID) {
+		cluster.AddSuspectKeyRange(rule.StartKey, rule.EndKey)
+	}
+	h.rd.JSON(w, http.StatusOK, "Update store group successfully.")
+}
+
+// @Tags     rule
+// @Summary  Delete store group, it fails if any rule still targets the store group.
+// @Param    id  path  string  true  "Store Group Id"
+// @Produce  json
+// @Success  200  {string}  string  "Delete store group successfully."
+// @Failure  400  {string}  string  "The StoreGroup is used by the rules."
+// @Failure  404  {string}  string  "The StoreGroup does not exist."
+// @Failure  412  {string}  string  "Placement rules feature is disabled."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /config/store_group/{id} [delete]
+func (h *ruleHandler) DeleteStoreGroup(w http.ResponseWriter, r *http.Request) {
+	manager := getRuleManager(r)
+	id := mux.Vars(r)["id"]
+	if err := manager.DeleteStoreGroup(id); err != nil {
+		switch {
+		case errs.ErrStoreGroupNotFound.Equal(err):
+			h.rd.JSON(w, http.StatusNotFound, err.Error())
+		case errs.ErrStoreGroupInUse.Equal(err):
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		default:
+			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "Delete store group successfully.")
+}
+
+// @Tags     rule
+// @Summary  List all store groups.
+// @Produce  json
+// @Success  200  {array}   placement.StoreGroup
+// @Failure  412  {string}  string  "Placement rules feature is disabled."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /config/store_groups [get]
+func (h *ruleHandler) GetAllStoreGroups(w http.ResponseWriter, r *http.Request) {
+	manager := getRuleManager(r)
+	storeGroups := manager.GetStoreGroups()
+	h.rd.JSON(w, http.StatusOK, storeGroups)
+}
+
 // @Tags     rule
 // @Summary  List all rules and groups configuration.
 // @Produce  json
@@ -428,6 +567,9 @@ func (h *ruleHandler) GetAllGroupConfigs(w http.ResponseWriter, r *http.Request)
 func (h *ruleHandler) GetPlacementRules(w http.ResponseWriter, r *http.Request) {
 	manager := getRuleManager(r)
 	bundles := manager.GetAllGroupBundles()
+	if apiutil.WriteETag(w, r, bundles) {
+		return
+	}
 	h.rd.JSON(w, http.StatusOK, bundles)
 }
 
@@ -437,7 +579,7 @@ func (h *ruleHandler) GetPlacementRules(w http.ResponseWriter, r *http.Request)
 // @Produce  json
 // @Success  200  {string}  string  "Update rules and groups successfully."
 // @Failure  400  {string}  string  "The input is invalid."
-// @Failure  412  {string}  string  "Placement rules feature is disabled."
+// @Failure  412  {string}  string  "Placement rules feature is disabled or the rules have been modified."
 // @Failure  500  {string}  string  "PD server failed to proceed the request."
 // @Router   /config/placement-rule [post]
 func (h *ruleHandler) SetPlacementRules(w http.ResponseWriter, r *http.Request) {
@@ -448,8 +590,11 @@ func (h *ruleHandler) SetPlacementRules(w http.ResponseWriter, r *http.Request)
 	}
 	_, partial := r.URL.Query()["partial"]
 	if err := manager.SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
-		SetAllGroupBundles(groups, !partial); err != nil {
-		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+		SetAllGroupBundlesIfMatch(groups, !partial, apiutil.IfMatchCheck[[]placement.GroupBundle](r)); err != nil {
+		if errs.ErrPreconditionFailed.Equal(err) {
+			h.rd.JSON(w, http.StatusPreconditionFailed, apiutil.PreconditionFailedMsg)
+		} else if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) || errs.ErrStoreGroupNotFound.Equal(err) ||
+			errs.ErrChangeRejected.Equal(err) {
 			h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		} else {
 			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
@@ -525,7 +670,8 @@ func (h *ruleHandler) SetPlacementRuleByGroup(w http.ResponseWriter, r *http.Req
 	}
 	if err := manager.SetKeyType(h.svr.GetConfig().PDServerCfg.KeyType).
 		SetGroupBundle(group); err != nil {
-		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) {
+		if errs.ErrRuleContent.Equal(err) || errs.ErrHexDecodingString.Equal(err) || errs.ErrStoreGroupNotFound.Equal(err) ||
+			errs.ErrChangeRejected.Equal(err) {
 			h.rd.JSON(w, http.StatusBadRequest, err.Error())
 		} else {
 			h.rd.JSON(w, http.StatusInternalServerError, err.Error())
@@ -134,6 +134,17 @@ func (h *schedulerHandler) CreateScheduler(w http.ResponseWriter, r *http.Reques
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+		if tp == types.EvictLeaderScheduler {
+			rc := h.svr.GetRaftCluster()
+			if rc == nil {
+				h.r.JSON(w, http.StatusInternalServerError, errs.ErrNotBootstrapped.FastGenByArgs().Error())
+				return
+			}
+			if err := schedulers.CheckEvictingStoreRatio(rc.GetStores(), rc.GetOpts().GetMaxEvictingStoreRatio(), uint64(storeID)); err != nil {
+				h.r.JSON(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
 
 		collector(strconv.FormatUint(uint64(storeID), 10))
 	case types.ShuffleHotRegionScheduler:
@@ -247,6 +258,29 @@ func (h *schedulerHandler) PauseOrResumeScheduler(w http.ResponseWriter, r *http
 	h.r.JSON(w, http.StatusOK, "Pause or resume the scheduler successfully.")
 }
 
+// @Tags     scheduler
+// @Summary  Run one scheduling round of a scheduler and return the operators without adding them.
+// @Param    name  path  string  true  "The name of the scheduler."
+// @Produce  json
+// @Success  200  {object}  handler.SchedulerDryRunResult
+// @Failure  400  {string}  string  "The scheduler doesn't support dry run."
+// @Failure  404  {string}  string  "The scheduler is not found."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /schedulers/{name}/dry-run [get]
+func (h *schedulerHandler) DryRunScheduler(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	result, err := h.Handler.DryRunScheduler(name)
+	if err != nil {
+		if errs.ErrSchedulerNotDryRunnable.Equal(err) {
+			h.r.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.handleErr(w, err)
+		return
+	}
+	h.r.JSON(w, http.StatusOK, result)
+}
+
 type schedulerConfigHandler struct {
 	svr *server.Server
 	rd  *render.Render
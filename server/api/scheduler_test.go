@@ -0,0 +1,92 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/schedule/handler"
+	"github.com/tikv/pd/pkg/schedule/schedulers"
+	tu "github.com/tikv/pd/pkg/utils/testutil"
+	"github.com/tikv/pd/server"
+)
+
+func TestSchedulerDryRun(t *testing.T) {
+	re := require.New(t)
+	svr, cleanup := mustNewServer(re)
+	defer cleanup()
+	server.MustWaitLeader(re, []*server.Server{svr})
+	mustBootstrapCluster(re, svr)
+	for id := uint64(1); id <= 3; id++ {
+		mustPutStore(re, svr, id, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+	}
+	urlPrefix := fmt.Sprintf("%s%s/api/v1/schedulers", svr.GetAddr(), apiPrefix)
+
+	body, err := json.Marshal(map[string]any{"name": schedulers.BalanceLeaderName})
+	re.NoError(err)
+	re.NoError(tu.CheckPostJSON(testDialClient, urlPrefix, body, tu.StatusOK(re)))
+	// Pause the scheduler to make sure the operators are only created by the dry run.
+	pauseArgs, err := json.Marshal(map[string]any{"delay": 100})
+	re.NoError(err)
+	re.NoError(tu.CheckPostJSON(testDialClient, urlPrefix+"/"+schedulers.BalanceLeaderName, pauseArgs, tu.StatusOK(re)))
+	for i := uint64(0); i < 20; i++ {
+		regionID := 100 + i
+		peers := []*metapb.Peer{
+			{Id: regionID*10 + 1, StoreId: 1},
+			{Id: regionID*10 + 2, StoreId: 2},
+			{Id: regionID*10 + 3, StoreId: 3},
+		}
+		region := &metapb.Region{
+			Id:          regionID,
+			StartKey:    []byte(fmt.Sprintf("a%02d", i)),
+			EndKey:      []byte(fmt.Sprintf("a%02d", i+1)),
+			Peers:       peers,
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+		}
+		mustRegionHeartbeat(re, svr, core.NewRegionInfo(region, peers[0], core.SetApproximateSize(10)))
+	}
+	// Wait for the leader count of the stores to be updated.
+	tu.Eventually(re, func() bool {
+		for id := uint64(1); id <= 3; id++ {
+			mustPutStore(re, svr, id, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+		}
+		return svr.GetRaftCluster().GetStore(1).GetLeaderCount() == 20
+	})
+
+	result := &handler.SchedulerDryRunResult{}
+	re.NoError(tu.ReadGetJSON(re, testDialClient, urlPrefix+"/"+schedulers.BalanceLeaderName+"/dry-run", result))
+	re.Equal(schedulers.BalanceLeaderName, result.Name)
+	re.False(result.Allowed)
+	re.NotEmpty(result.Operators)
+	for _, op := range result.Operators {
+		re.Contains(op.Kind, "leader")
+		re.NotEmpty(op.Steps)
+	}
+	// The operators are not added.
+	re.Empty(svr.GetRaftCluster().GetOperatorController().GetOperators())
+
+	re.NoError(tu.CheckGetJSON(testDialClient, urlPrefix+"/not-exist/dry-run", nil, tu.Status(re, http.StatusNotFound)))
+	// The schedulers with side effects can't be dry run.
+	body, err = json.Marshal(map[string]any{"name": schedulers.EvictSlowStoreName})
+	re.NoError(err)
+	re.NoError(tu.CheckPostJSON(testDialClient, urlPrefix, body, tu.StatusOK(re)))
+	re.NoError(tu.CheckGetJSON(testDialClient, urlPrefix+"/"+schedulers.EvictSlowStoreName+"/dry-run", nil, tu.Status(re, http.StatusBadRequest)))
+}
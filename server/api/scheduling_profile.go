@@ -0,0 +1,85 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	sc "github.com/tikv/pd/pkg/schedule/config"
+	"github.com/tikv/pd/server"
+	"github.com/unrolled/render"
+)
+
+type schedulingProfileHandler struct {
+	*server.Handler
+	r *render.Render
+}
+
+func newSchedulingProfileHandler(svr *server.Server, r *render.Render) *schedulingProfileHandler {
+	return &schedulingProfileHandler{
+		Handler: svr.GetHandler(),
+		r:       r,
+	}
+}
+
+// SchedulingProfiles is the built-in scheduling profiles and the active one.
+type SchedulingProfiles struct {
+	// Active is empty if no profile has been applied.
+	Active string `json:"active"`
+	// Modified is true if the config has been changed after the active profile was applied.
+	Modified bool                    `json:"modified"`
+	Profiles []*sc.SchedulingProfile `json:"profiles"`
+}
+
+// @Tags     scheduling_profile
+// @Summary  List the built-in scheduling profiles and the active one.
+// @Produce  json
+// @Success  200  {object}  SchedulingProfiles
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /scheduling-profiles [get]
+func (h *schedulingProfileHandler) GetSchedulingProfiles(w http.ResponseWriter, _ *http.Request) {
+	active, modified, err := h.GetSchedulingProfile()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, &SchedulingProfiles{
+		Active:   active,
+		Modified: modified,
+		Profiles: sc.GetSchedulingProfiles(),
+	})
+}
+
+// @Tags     scheduling_profile
+// @Summary  Switch to the scheduling profile.
+// @Param    name  path  string  true  "The name of the scheduling profile"
+// @Produce  json
+// @Success  200  {string}  string  "The scheduling profile is switched."
+// @Failure  404  {string}  string  "The scheduling profile is not found."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /scheduling-profiles/{name} [post]
+func (h *schedulingProfileHandler) SwitchSchedulingProfile(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if _, ok := sc.GetSchedulingProfile(name); !ok {
+		h.r.JSON(w, http.StatusNotFound, "The scheduling profile is not found.")
+		return
+	}
+	if err := h.Handler.SwitchSchedulingProfile(name); err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, "The scheduling profile is switched.")
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/pingcap/failpoint"
+	"github.com/stretchr/testify/suite"
+	sc "github.com/tikv/pd/pkg/schedule/config"
+	types "github.com/tikv/pd/pkg/schedule/type"
+	tu "github.com/tikv/pd/pkg/utils/testutil"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/cluster"
+)
+
+type schedulingProfileTestSuite struct {
+	suite.Suite
+	svr       *server.Server
+	cleanup   tu.CleanupFunc
+	urlPrefix string
+}
+
+func TestSchedulingProfileTestSuite(t *testing.T) {
+	suite.Run(t, new(schedulingProfileTestSuite))
+}
+
+func (suite *schedulingProfileTestSuite) SetupSuite() {
+	re := suite.Require()
+	suite.svr, suite.cleanup = mustNewServer(re)
+	server.MustWaitLeader(re, []*server.Server{suite.svr})
+
+	addr := suite.svr.GetAddr()
+	suite.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(re, suite.svr)
+}
+
+func (suite *schedulingProfileTestSuite) TearDownSuite() {
+	suite.cleanup()
+}
+
+func (suite *schedulingProfileTestSuite) TestSwitchSchedulingProfile() {
+	re := suite.Require()
+	url := fmt.Sprintf("%s/scheduling-profiles", suite.urlPrefix)
+	profiles := &SchedulingProfiles{}
+	re.NoError(tu.ReadGetJSON(re, testDialClient, url, profiles))
+	re.Empty(profiles.Active)
+	re.Len(profiles.Profiles, 4)
+
+	err := tu.CheckPostJSON(testDialClient, url+"/unknown", nil, tu.Status(re, 404))
+	re.NoError(err)
+
+	checkSchedulers := func(expected ...types.CheckerSchedulerType) {
+		for _, tp := range sc.GetProfileSchedulers() {
+			existed, _ := suite.svr.GetHandler().IsSchedulerExisted(tp.String())
+			re.Equal(existed, slices.Contains(expected, tp), tp)
+		}
+	}
+	// Wait for the coordinator to add the default schedulers.
+	rc := suite.svr.GetRaftCluster()
+	rc.SetPrepared()
+	tu.Eventually(re, rc.GetCoordinator().AreSchedulersInitialized)
+	checkSchedulers(sc.GetProfileSchedulers()...)
+
+	err = tu.CheckPostJSON(testDialClient, url+"/"+sc.SchedulingProfileMaintenance, nil, tu.StatusOK(re))
+	re.NoError(err)
+	cfg := suite.svr.GetScheduleConfig()
+	re.Zero(cfg.MergeScheduleLimit)
+	re.Zero(cfg.HotRegionScheduleLimit)
+	checkSchedulers()
+
+	err = tu.CheckPostJSON(testDialClient, url+"/"+sc.SchedulingProfileImportFriendly, nil, tu.StatusOK(re))
+	re.NoError(err)
+	checkSchedulers(types.BalanceLeaderScheduler)
+
+	err = tu.CheckPostJSON(testDialClient, url+"/"+sc.SchedulingProfileAggressiveRebalance, nil, tu.StatusOK(re))
+	re.NoError(err)
+	cfg = suite.svr.GetScheduleConfig()
+	re.Equal(uint64(16), cfg.LeaderScheduleLimit)
+	re.Equal(uint64(16), cfg.MergeScheduleLimit)
+	checkSchedulers(sc.GetProfileSchedulers()...)
+
+	re.NoError(tu.ReadGetJSON(re, testDialClient, url, profiles))
+	re.Equal(sc.SchedulingProfileAggressiveRebalance, profiles.Active)
+	re.False(profiles.Modified)
+	status := &cluster.Status{}
+	re.NoError(tu.ReadGetJSON(re, testDialClient, fmt.Sprintf("%s/cluster/status", suite.urlPrefix), status))
+	re.Equal(sc.SchedulingProfileAggressiveRebalance, status.SchedulingProfile)
+	re.False(status.SchedulingProfileModified)
+
+	// Changing a limit makes the config diverge from the active profile.
+	cfg = suite.svr.GetScheduleConfig()
+	cfg.MergeScheduleLimit = 1
+	re.NoError(suite.svr.SetScheduleConfig(*cfg))
+	re.NoError(tu.ReadGetJSON(re, testDialClient, url, profiles))
+	re.Equal(sc.SchedulingProfileAggressiveRebalance, profiles.Active)
+	re.True(profiles.Modified)
+	re.NoError(tu.ReadGetJSON(re, testDialClient, fmt.Sprintf("%s/cluster/status", suite.urlPrefix), status))
+	re.True(status.SchedulingProfileModified)
+
+	// So does removing a managed scheduler.
+	err = tu.CheckPostJSON(testDialClient, url+"/"+sc.SchedulingProfileAggressiveRebalance, nil, tu.StatusOK(re))
+	re.NoError(err)
+	re.NoError(tu.ReadGetJSON(re, testDialClient, url, profiles))
+	re.False(profiles.Modified)
+	re.NoError(suite.svr.GetHandler().RemoveScheduler(types.HotRegionScheduler.String()))
+	re.NoError(tu.ReadGetJSON(re, testDialClient, url, profiles))
+	re.True(profiles.Modified)
+	re.NoError(suite.svr.GetHandler().AddScheduler(types.HotRegionScheduler))
+
+	// A failed switch restores the config and the schedulers.
+	re.NoError(failpoint.Enable("github.com/tikv/pd/server/saveSchedulingProfileFail", `return(true)`))
+	err = tu.CheckPostJSON(testDialClient, url+"/"+sc.SchedulingProfileMaintenance, nil, tu.Status(re, 500))
+	re.NoError(err)
+	re.NoError(failpoint.Disable("github.com/tikv/pd/server/saveSchedulingProfileFail"))
+	cfg = suite.svr.GetScheduleConfig()
+	re.Equal(uint64(16), cfg.LeaderScheduleLimit)
+	re.Equal(uint64(16), cfg.MergeScheduleLimit)
+	checkSchedulers(sc.GetProfileSchedulers()...)
+	re.NoError(tu.ReadGetJSON(re, testDialClient, url, profiles))
+	re.Equal(sc.SchedulingProfileAggressiveRebalance, profiles.Active)
+	re.False(profiles.Modified)
+}
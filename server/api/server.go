@@ -46,10 +46,14 @@ func NewHandler(_ context.Context, svr *server.Server) (http.Handler, apiutil.AP
 	//	"/operators", http.MethodGet
 	//	"/operators", http.MethodPost
 	//	"/operators/records",http.MethodGet
+	//	"/operators/progress",http.MethodGet
+	//	"/operators/leader-transfer-blacklist",http.MethodGet
 	//	"/operators/{region_id}", http.MethodGet
 	//	"/operators/{region_id}", http.MethodDelete
+	//	"/operators/{region_id}/placement", http.MethodPost
 	//	"/checker/{name}", http.MethodPost
 	//	"/checker/{name}", http.MethodGet
+	//	"/checker/merge-checker/diagnostic/{region_id}", http.MethodGet
 	//	"/schedulers", http.MethodGet
 	//	"/schedulers/{name}", http.MethodPost, which is to be used to pause or resume the scheduler rather than create a new scheduler
 	//	"/schedulers/diagnostic/{name}", http.MethodGet
@@ -59,6 +63,7 @@ func NewHandler(_ context.Context, svr *server.Server) (http.Handler, apiutil.AP
 	//	"/hotspot/regions/history", http.MethodGet
 	//	"/hotspot/stores", http.MethodGet
 	//	"/hotspot/buckets", http.MethodGet
+	//	"/hotspot/analysis", http.MethodGet
 	// Following requests are **not** redirected:
 	//	"/schedulers", http.MethodPost
 	//	"/schedulers/{name}", http.MethodDelete
@@ -97,6 +102,11 @@ func NewHandler(_ context.Context, svr *server.Server) (http.Handler, apiutil.AP
 				scheapi.APIPathPrefix+"/regions/accelerate-schedule",
 				mcs.SchedulingServiceName,
 				[]string{http.MethodPost}),
+			serverapi.MicroserviceRedirectRule(
+				prefix+"/regions/merge",
+				scheapi.APIPathPrefix+"/regions/merge",
+				mcs.SchedulingServiceName,
+				[]string{http.MethodPost, http.MethodGet, http.MethodDelete}),
 			serverapi.MicroserviceRedirectRule(
 				prefix+"/regions/scatter",
 				scheapi.APIPathPrefix+"/regions/scatter",
@@ -122,6 +132,11 @@ func NewHandler(_ context.Context, svr *server.Server) (http.Handler, apiutil.AP
 				scheapi.APIPathPrefix+"/config/region-label/rules",
 				mcs.SchedulingServiceName,
 				[]string{http.MethodGet}),
+			serverapi.MicroserviceRedirectRule(
+				prefix+"/stats/zone-quorum",
+				scheapi.APIPathPrefix+"/stats/zone-quorum",
+				mcs.SchedulingServiceName,
+				[]string{http.MethodGet}),
 			serverapi.MicroserviceRedirectRule(
 				prefix+"/hotspot",
 				scheapi.APIPathPrefix+"/hotspot",
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/tikv/pd/pkg/gc"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/server"
 	"github.com/unrolled/render"
@@ -81,6 +82,21 @@ func (h *serviceGCSafepointHandler) GetGCSafePoint(w http.ResponseWriter, _ *htt
 	h.rd.JSON(w, http.StatusOK, list)
 }
 
+// @Tags     service_gc_safepoint
+// @Summary  Simulate the GC safepoint advance with all the services blocking the GC worker ignored.
+// @Produce  json
+// @Success  200  {object}  gc.GCSafePointSimulation
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /gc/safepoint/simulate [get]
+func (h *serviceGCSafepointHandler) SimulateGCSafePointAdvance(w http.ResponseWriter, _ *http.Request) {
+	ssps, err := h.svr.GetStorage().LoadAllServiceGCSafePoints()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, gc.SimulateGCSafePointAdvance(ssps, time.Now()))
+}
+
 // @Tags     service_gc_safepoint
 // @Summary  Delete a service GC safepoint.
 // @Param    service_id  path  string  true  "Service ID"
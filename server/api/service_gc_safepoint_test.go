@@ -16,11 +16,13 @@ package api
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/stretchr/testify/suite"
+	"github.com/tikv/pd/pkg/gc"
 	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/pkg/utils/testutil"
@@ -101,3 +103,32 @@ func (suite *serviceGCSafepointTestSuite) TestServiceGCSafepoint() {
 	re.NoError(err)
 	re.Equal(list.ServiceGCSafepoints[1:], left)
 }
+
+func (suite *serviceGCSafepointTestSuite) TestSimulateGCSafePointAdvance() {
+	re := suite.Require()
+	storage := suite.svr.GetStorage()
+	now := time.Now()
+	ssps := []*endpoint.ServiceSafePoint{
+		{ServiceID: endpoint.GCWorkerServiceSafePointID, ExpiredAt: math.MaxInt64, SafePoint: 300},
+		{ServiceID: "simulate-a", ExpiredAt: now.Unix() + 10, SafePoint: 100},
+	}
+	for _, ssp := range ssps {
+		re.NoError(storage.SaveServiceGCSafePoint(ssp))
+	}
+	defer func() {
+		re.NoError(storage.RemoveServiceGCSafePoint("simulate-a"))
+	}()
+
+	sim := &gc.GCSafePointSimulation{}
+	err := testutil.ReadGetJSON(re, testDialClient, suite.urlPrefix+"/gc/safepoint/simulate", sim)
+	re.NoError(err)
+	re.Equal(uint64(300), sim.SimulatedGCSafePoint)
+	var blocker *gc.GCSafePointBlocker
+	for _, b := range sim.Blockers {
+		if b.ServiceID == "simulate-a" {
+			blocker = b
+		}
+	}
+	re.NotNil(blocker)
+	re.Equal(uint64(100), blocker.SafePoint)
+}
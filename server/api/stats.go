@@ -53,3 +53,23 @@ func (h *statsHandler) GetRegionStatus(w http.ResponseWriter, r *http.Request) {
 	}
 	h.rd.JSON(w, http.StatusOK, stats)
 }
+
+// @Tags     stats
+// @Summary  Get the number of regions which would lose quorum if the zone failed.
+// @Produce  json
+// @Success  200  {object}  map[string]int
+// @Router   /stats/zone-quorum [get]
+func (h *statsHandler) GetZoneQuorumStatus(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	h.rd.JSON(w, http.StatusOK, rc.GetZoneQuorumStats().GetZoneQuorumLossCount())
+}
+
+// @Tags     stats
+// @Summary  Get the estimated memory usage of the cluster metadata per component.
+// @Produce  json
+// @Success  200  {array}  core.MemoryUsage
+// @Router   /stats/memory [get]
+func (h *statsHandler) GetMemoryUsage(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	h.rd.JSON(w, http.StatusOK, rc.GetBasicCluster().GetMemoryUsage())
+}
@@ -15,6 +15,7 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/core/storelimit"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/response"
@@ -77,6 +79,28 @@ func (h *storeHandler) GetStore(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, storeInfo)
 }
 
+// @Tags     store
+// @Summary  Get the address change history of a store, which records the re-registrations with changed addresses.
+// @Param    id  path  integer  true  "Store Id"
+// @Produce  json
+// @Success  200  {array}   endpoint.StoreAddressChange
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /store/{id}/address-changes [get]
+func (h *storeHandler) GetStoreAddressChanges(w http.ResponseWriter, r *http.Request) {
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	changes, err := getCluster(r).GetStorage().LoadStoreAddressChanges(storeID)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, changes)
+}
+
 // @Tags     store
 // @Summary  Take down a store from the cluster.
 // @Param    id     path   integer  true  "Store Id"
@@ -293,6 +317,79 @@ func (h *storeHandler) SetStoreWeight(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, "The store's weight is updated.")
 }
 
+// @Tags     store
+// @Summary  Replace the store with a new one, the store is set as Offline and its peers are moved to the new store one by one.
+// @Param    id    path  integer  true  "Store Id"
+// @Param    body  body  object   true  "The new store ID, e.g. {\"new_store_id\": 2}"
+// @Produce  json
+// @Success  200  {string}  string  "The store is being replaced."
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The store does not exist."
+// @Router   /store/{id}/replace [post]
+func (h *storeHandler) ReplaceStore(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	var input struct {
+		NewStoreID uint64 `json:"new_store_id"`
+	}
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if input.NewStoreID == 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "new store id unset")
+		return
+	}
+
+	if err := rc.ReplaceStore(storeID, input.NewStoreID); err != nil {
+		if errors.ErrorEqual(err, errs.ErrStoreNotFound.FastGenByArgs(input.NewStoreID)) {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.responseStoreErr(w, err, storeID)
+		return
+	}
+
+	h.rd.JSON(w, http.StatusOK, "The store is being replaced.")
+}
+
+// @Tags     store
+// @Summary  Report the compaction pressure of the store's storage engine, the stats expire if they are not reported again in 5 minutes.
+// @Param    id    path  integer               true  "Store Id"
+// @Param    body  body  core.CompactionStats  true  "The pending compaction bytes and the level0 file count"
+// @Produce  json
+// @Success  200  {string}  string  "The store's compaction stats are updated."
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The store is not found."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /store/{id}/compaction-stats [post]
+func (h *storeHandler) SetStoreCompactionStats(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	stats := &core.CompactionStats{}
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, stats); err != nil {
+		return
+	}
+	stats.ReportTime = time.Now()
+	if err := rc.UpdateCompactionStats(storeID, stats); err != nil {
+		if errors.ErrorEqual(err, errs.ErrStoreNotFound.FastGenByArgs(storeID)) {
+			h.rd.JSON(w, http.StatusNotFound, err.Error())
+			return
+		}
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The store's compaction stats are updated.")
+}
+
 // FIXME: details of input json body params
 // @Tags     store
 // @Summary  Set the store's limit.
@@ -577,6 +674,128 @@ type Progress struct {
 	LeftSeconds  float64 `json:"left_seconds"`
 }
 
+// @Tags     stores
+// @Summary  Get the ongoing store replacements and the number of the regions left on the old stores.
+// @Produce  json
+// @Success  200  {array}   cluster.StoreReplacementStatus
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /stores/replacements [get]
+func (h *storesHandler) GetStoreReplacements(w http.ResponseWriter, r *http.Request) {
+	replacements, err := getCluster(r).GetStoreReplacements()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, replacements)
+}
+
+// @Tags     stores
+// @Summary  Get the estimated clock skews of the stores against PD, which are sourced from the store heartbeats.
+// @Produce  json
+// @Success  200  {array}  cluster.StoreClockSkew
+// @Router   /stores/clock-skew [get]
+func (h *storesHandler) GetStoreClockSkews(w http.ResponseWriter, r *http.Request) {
+	h.rd.JSON(w, http.StatusOK, getCluster(r).GetStoreClockSkews())
+}
+
+// @Tags     stores
+// @Summary  Get the slow store event timeline, which records the slow score transitions, the evictions and the recoveries of the stores.
+// @Param    store_id    query  integer  false  "The store ID, all stores by default"
+// @Param    start_time  query  integer  false  "The start unix timestamp in seconds"
+// @Param    end_time    query  integer  false  "The end unix timestamp in seconds, not limited by default"
+// @Produce  json
+// @Success  200  {array}   endpoint.SlowStoreEvent
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /stores/slow-events [get]
+func (h *storesHandler) GetSlowStoreEvents(w http.ResponseWriter, r *http.Request) {
+	var (
+		storeID            uint64
+		startTime, endTime time.Time
+		err                error
+	)
+	query := r.URL.Query()
+	if v := query.Get("store_id"); v != "" {
+		if storeID, err = strconv.ParseUint(v, 10, 64); err != nil {
+			apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(err))
+			return
+		}
+	}
+	for _, t := range []struct {
+		name string
+		time *time.Time
+	}{{"start_time", &startTime}, {"end_time", &endTime}} {
+		v := query.Get(t.name)
+		if v == "" {
+			continue
+		}
+		ts, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(err))
+			return
+		}
+		*t.time = time.Unix(ts, 0)
+	}
+	events, err := getCluster(r).GetStorage().LoadSlowStoreEvents(storeID, startTime, endTime)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, events)
+}
+
+// @Tags     stores
+// @Summary  Watch the lifecycle state changes of the stores, the events are streamed as the JSON lines until the request is canceled.
+// @Param    revision  query  integer  false  "Only the events after the revision are returned, the latest revision by default"
+// @Produce  json
+// @Success  200  {array}   cluster.StoreEvent
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  410  {string}  string  "The revision is unavailable, the stores should be listed again."
+// @Router   /stores/watch [get]
+func (h *storesHandler) WatchStoreEvents(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	revision := rc.GetStoreEventRevision()
+	if v := r.URL.Query().Get("revision"); v != "" {
+		var err error
+		if revision, err = strconv.ParseUint(v, 10, 64); err != nil {
+			apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(err))
+			return
+		}
+	}
+	events, notify, err := rc.WatchStoreEvents(revision)
+	if err != nil {
+		h.rd.JSON(w, http.StatusGone, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for {
+		for _, event := range events {
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+			revision = event.Revision
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-rc.Context().Done():
+			return
+		case <-notify:
+		}
+		// the watcher falls too far behind if the revision is unavailable,
+		// close the stream to let it list the stores again.
+		if events, notify, err = rc.WatchStoreEvents(revision); err != nil {
+			return
+		}
+	}
+}
+
 // @Tags     stores
 // @Summary  Get store progress in the cluster.
 // @Produce  json
@@ -629,14 +848,22 @@ func (h *storesHandler) GetStoresProgress(w http.ResponseWriter, r *http.Request
 
 // @Tags     store
 // @Summary     Get all stores in the cluster.
-// @Param       state  query  array  true  "Specify accepted store states."
+// @Param       state       query  array    true   "Specify accepted store states."
+// @Param       page_token  query  string   false  "The next_page_token of the previous page"
+// @Param       limit       query  integer  false  "Limit count of the page"
 // @Produce  json
 // @Success     200  {object}  response.StoresInfo
+// @Failure     400  {string}  string  "The input is invalid."
 // @Failure  500  {string}  string  "PD server failed to proceed the request."
 // @Router      /stores [get]
 // @Deprecated  Better to use /stores/check instead.
 func (h *storesHandler) GetAllStores(w http.ResponseWriter, r *http.Request) {
 	rc := getCluster(r)
+	page, err := apiutil.ParsePageQuery(r.URL.Query())
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	stores := rc.GetMetaStores()
 	StoresInfo := &response.StoresInfo{
 		Stores: make([]*response.StoreInfo, 0, len(stores)),
@@ -660,20 +887,31 @@ func (h *storesHandler) GetAllStores(w http.ResponseWriter, r *http.Request) {
 		storeInfo := response.BuildStoreInfo(h.GetScheduleConfig(), store)
 		StoresInfo.Stores = append(StoresInfo.Stores, storeInfo)
 	}
-	StoresInfo.Count = len(StoresInfo.Stores)
-
+	if err := StoresInfo.Paginate(page); err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	apiutil.SetNextPageToken(w, StoresInfo.NextPageToken)
 	h.rd.JSON(w, http.StatusOK, StoresInfo)
 }
 
 // @Tags     store
 // @Summary  Get all stores by states in the cluster.
-// @Param    state  query  array  true  "Specify accepted store states."
+// @Param    state       query  array    true   "Specify accepted store states."
+// @Param    page_token  query  string   false  "The next_page_token of the previous page"
+// @Param    limit       query  integer  false  "Limit count of the page"
 // @Produce  json
 // @Success  200  {object}  response.StoresInfo
+// @Failure  400  {string}  string  "The input is invalid."
 // @Failure  500  {string}  string  "PD server failed to proceed the request."
 // @Router   /stores/check [get]
 func (h *storesHandler) GetStoresByState(w http.ResponseWriter, r *http.Request) {
 	rc := getCluster(r)
+	page, err := apiutil.ParsePageQuery(r.URL.Query())
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	stores := rc.GetMetaStores()
 	StoresInfo := &response.StoresInfo{
 		Stores: make([]*response.StoreInfo, 0, len(stores)),
@@ -711,8 +949,11 @@ func (h *storesHandler) GetStoresByState(w http.ResponseWriter, r *http.Request)
 		}
 		StoresInfo.Stores = append(StoresInfo.Stores, storeInfo)
 	}
-	StoresInfo.Count = len(StoresInfo.Stores)
-
+	if err := StoresInfo.Paginate(page); err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	apiutil.SetNextPageToken(w, StoresInfo.NextPageToken)
 	h.rd.JSON(w, http.StatusOK, StoresInfo)
 }
 
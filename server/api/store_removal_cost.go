@@ -0,0 +1,188 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errcode"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/core/storelimit"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/schedule/filter"
+	"github.com/tikv/pd/pkg/slice"
+	"github.com/tikv/pd/pkg/utils/apiutil"
+	"github.com/tikv/pd/pkg/utils/typeutil"
+	"github.com/tikv/pd/server/cluster"
+)
+
+// maxRemovalCostSimulatedRegions bounds the regions whose replicas are placed by
+// the estimation, each of them stands for the regions following it up to the next
+// one, so the estimation of a store with a lot of regions stays cheap.
+const maxRemovalCostSimulatedRegions = 4096
+
+// storeRemovalCost is the estimated cost of removing a store.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type storeRemovalCost struct {
+	StoreID     uint64 `json:"store_id"`
+	RegionCount int    `json:"region_count"`
+	// MoveBytes is the approximate bytes of the replicas to be moved.
+	MoveBytes int64 `json:"move_bytes"`
+	// EstimatedDuration is the projected time to move all the replicas under
+	// the current store limits, it's empty if the replicas can't be moved.
+	EstimatedDuration typeutil.Duration `json:"estimated_duration"`
+	// UnplacedRegionCount is the number of the regions which have no candidate target store.
+	UnplacedRegionCount int `json:"unplaced_region_count"`
+	// SimulatedRegionCount is the number of the regions whose replicas are placed by the
+	// estimation, the placement of the others is extrapolated from them.
+	SimulatedRegionCount int                  `json:"simulated_region_count"`
+	Targets              []*removalCostTarget `json:"targets"`
+}
+
+// removalCostTarget is a store which would receive the replicas of the removed store.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type removalCostTarget struct {
+	StoreID     uint64 `json:"store_id"`
+	Address     string `json:"address"`
+	RegionCount int    `json:"region_count"`
+	MoveBytes   int64  `json:"move_bytes"`
+}
+
+// @Tags     store
+// @Summary  Estimate the cost of removing a store before taking it down.
+// @Param    id  path  integer  true  "Store Id"
+// @Produce  json
+// @Success  200  {object}  storeRemovalCost
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  404  {string}  string  "The store does not exist."
+// @Failure  410  {string}  string  "The store has already been removed."
+// @Router   /stores/{id}/removal-cost [get]
+func (h *storeHandler) GetStoreRemovalCost(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r)
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	store := rc.GetStore(storeID)
+	if store == nil {
+		h.rd.JSON(w, http.StatusNotFound, errs.ErrStoreNotFound.FastGenByArgs(storeID).Error())
+		return
+	}
+	if store.IsRemoved() {
+		h.rd.JSON(w, http.StatusGone, errs.ErrStoreRemoved.FastGenByArgs(storeID).Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, estimateStoreRemovalCost(rc, store))
+}
+
+// estimateStoreRemovalCost simulates moving the replicas of the store one by one
+// to the candidate store holding the least bytes, which is close to what the
+// replica checker and the balance schedulers will eventually achieve. The
+// candidates are filtered like the checkers do, so the placement rules and the
+// location labels are respected.
+func estimateStoreRemovalCost(rc *cluster.RaftCluster, store *core.StoreInfo) *storeRemovalCost {
+	const scope = "store-removal-cost"
+	storeID := store.GetID()
+	conf := rc.GetSharedConfig()
+	stateFilter := &filter.StoreStateFilter{ActionScope: scope, MoveRegion: true, AllowTemporaryStates: true}
+	specialUseFilter := filter.NewSpecialUseFilter(scope)
+	var candidates []*core.StoreInfo
+	targets := make(map[uint64]*removalCostTarget)
+	projected := make(map[uint64]int64)
+	for _, s := range rc.GetStores() {
+		if s.GetID() == storeID || s.IsDisconnected() || s.IsTiFlash() != store.IsTiFlash() ||
+			!stateFilter.Target(conf, s).IsOK() || !specialUseFilter.Target(conf, s).IsOK() {
+			continue
+		}
+		candidates = append(candidates, s)
+		targets[s.GetID()] = &removalCostTarget{StoreID: s.GetID(), Address: s.GetAddress()}
+		projected[s.GetID()] = s.GetRegionSize()
+	}
+
+	cost := &storeRemovalCost{StoreID: storeID, Targets: []*removalCostTarget{}}
+	regions := rc.GetStoreRegions(storeID)
+	step := (len(regions) + maxRemovalCostSimulatedRegions - 1) / maxRemovalCostSimulatedRegions
+	for i := 0; i < len(regions); i += step {
+		var count int
+		var size int64
+		for _, region := range regions[i:min(i+step, len(regions))] {
+			count++
+			size += region.GetApproximateSize()
+		}
+		cost.RegionCount += count
+		cost.MoveBytes += size * units.MiB
+		cost.SimulatedRegionCount++
+
+		region := regions[i]
+		filters := []filter.Filter{
+			filter.NewExcludedFilter(scope, nil, region.GetStoreIDs()),
+			filter.NewPlacementSafeguard(scope, conf, rc.GetBasicCluster(), rc.GetRuleManager(), region, store, nil),
+		}
+		var target *removalCostTarget
+		for _, candidate := range candidates {
+			id := candidate.GetID()
+			if target != nil && (projected[id] > projected[target.StoreID] ||
+				(projected[id] == projected[target.StoreID] && id > target.StoreID)) {
+				continue
+			}
+			if slice.AllOf(filters, func(i int) bool { return filters[i].Target(conf, candidate).IsOK() }) {
+				target = targets[id]
+			}
+		}
+		if target == nil {
+			cost.UnplacedRegionCount += count
+			continue
+		}
+		target.RegionCount += count
+		target.MoveBytes += size * units.MiB
+		projected[target.StoreID] += size
+	}
+
+	// Each replica needs a remove-peer on the store and an add-peer on the target,
+	// the slowest side determines the time.
+	minutes := rateLimitedMinutes(cost.RegionCount, rc.GetStoreLimitByType(storeID, storelimit.RemovePeer))
+	for _, t := range targets {
+		if t.RegionCount == 0 {
+			continue
+		}
+		cost.Targets = append(cost.Targets, t)
+		minutes = math.Max(minutes, rateLimitedMinutes(t.RegionCount, rc.GetStoreLimitByType(t.StoreID, storelimit.AddPeer)))
+	}
+	sort.Slice(cost.Targets, func(i, j int) bool {
+		return cost.Targets[i].StoreID < cost.Targets[j].StoreID
+	})
+	if cost.UnplacedRegionCount == 0 && !math.IsInf(minutes, 1) {
+		cost.EstimatedDuration = typeutil.NewDuration(time.Duration(minutes * float64(time.Minute)))
+	}
+	return cost
+}
+
+// rateLimitedMinutes returns the minutes to finish the operators under the
+// store limit, which is the number of the operators per minute.
+func rateLimitedMinutes(count int, ratePerMin float64) float64 {
+	if count == 0 {
+		return 0
+	}
+	if ratePerMin <= 0 {
+		return math.Inf(1)
+	}
+	return float64(count) / ratePerMin
+}
@@ -31,10 +31,12 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/response"
+	"github.com/tikv/pd/pkg/schedule/placement"
 	tu "github.com/tikv/pd/pkg/utils/testutil"
 	"github.com/tikv/pd/pkg/utils/typeutil"
 	"github.com/tikv/pd/pkg/versioninfo"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/cluster"
 	"github.com/tikv/pd/server/config"
 )
 
@@ -360,6 +362,94 @@ func (suite *storeTestSuite) TestStoreDelete() {
 	suite.SetupSuite()
 }
 
+func (suite *storeTestSuite) TestStoreWatch() {
+	re := suite.Require()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, suite.urlPrefix+"/stores/watch?revision=0", http.NoBody)
+	re.NoError(err)
+	resp, err := testDialClient.Do(req)
+	re.NoError(err)
+	defer resp.Body.Close()
+	re.Equal(http.StatusOK, resp.StatusCode)
+
+	// the new store is streamed after the existing ones.
+	mustPutStore(re, suite.svr, 100, metapb.StoreState_Up, metapb.NodeState_Serving, nil)
+	decoder := json.NewDecoder(resp.Body)
+	var revision uint64
+	for {
+		event := &cluster.StoreEvent{}
+		re.NoError(decoder.Decode(event))
+		re.Equal(revision+1, event.Revision)
+		revision = event.Revision
+		if event.StoreID == 100 {
+			re.Empty(event.PrevState)
+			break
+		}
+	}
+
+	status := requestStatusBody(re, testDialClient, http.MethodGet, suite.urlPrefix+"/stores/watch?revision=10086")
+	re.Equal(http.StatusGone, status)
+	// close the stream and remove the new store.
+	cancel()
+	suite.cleanup()
+	suite.SetupSuite()
+}
+
+func (suite *storeTestSuite) TestStoreRemovalCost() {
+	re := suite.Require()
+	// only the connected stores can receive the replicas.
+	_, err := suite.grpcSvr.StoreHeartbeat(context.Background(), &pdpb.StoreHeartbeatRequest{
+		Header: &pdpb.RequestHeader{ClusterId: suite.svr.ClusterID()},
+		Stats:  &pdpb.StoreStats{StoreId: 4, Capacity: 100 * units.GiB, Available: 100 * units.GiB},
+	})
+	re.NoError(err)
+	for id := uint64(100); id < 103; id++ {
+		region := newRegionInfo(id, fmt.Sprintf("a%d", id), fmt.Sprintf("a%d", id+1), 1, 1, []uint64{1}, nil, nil, 1)
+		mustRegionHeartbeat(re, suite.svr, region.Clone(core.SetApproximateSize(10)))
+	}
+
+	cost := new(storeRemovalCost)
+	re.NoError(tu.ReadGetJSON(re, testDialClient, fmt.Sprintf("%s/stores/1/removal-cost", suite.urlPrefix), cost))
+	re.Equal(3, cost.RegionCount)
+	re.Equal(int64(30*units.MiB), cost.MoveBytes)
+	re.Zero(cost.UnplacedRegionCount)
+	re.Len(cost.Targets, 1)
+	re.Equal(uint64(4), cost.Targets[0].StoreID)
+	re.Equal(3, cost.Targets[0].RegionCount)
+	// the default store limit is 15 operators per minute.
+	re.Equal(12*time.Second, cost.EstimatedDuration.Duration)
+	re.Equal(3, cost.SimulatedRegionCount)
+
+	// the target stores must match the placement rules.
+	mustPutStore(re, suite.svr, 1, metapb.StoreState_Up, metapb.NodeState_Serving, []*metapb.StoreLabel{{Key: "zone", Value: "z1"}})
+	mustPutStore(re, suite.svr, 4, metapb.StoreState_Up, metapb.NodeState_Serving, []*metapb.StoreLabel{{Key: "zone", Value: "z2"}})
+	replication := suite.svr.GetReplicationConfig().Clone()
+	replication.EnablePlacementRules = true
+	re.NoError(suite.svr.SetReplicationConfig(*replication))
+	re.NoError(suite.svr.GetRaftCluster().GetRuleManager().SetRule(&placement.Rule{
+		GroupID:          placement.DefaultGroupID,
+		ID:               placement.DefaultRuleID,
+		Role:             placement.Voter,
+		Count:            3,
+		LabelConstraints: []placement.LabelConstraint{{Key: "zone", Op: placement.In, Values: []string{"z1"}}},
+	}))
+	cost = new(storeRemovalCost)
+	re.NoError(tu.ReadGetJSON(re, testDialClient, fmt.Sprintf("%s/stores/1/removal-cost", suite.urlPrefix), cost))
+	re.Equal(3, cost.RegionCount)
+	re.Equal(3, cost.UnplacedRegionCount)
+	re.Empty(cost.Targets)
+	re.Zero(cost.EstimatedDuration.Duration)
+
+	status := requestStatusBody(re, testDialClient, http.MethodGet, fmt.Sprintf("%s/stores/7/removal-cost", suite.urlPrefix))
+	re.Equal(http.StatusGone, status)
+	status = requestStatusBody(re, testDialClient, http.MethodGet, fmt.Sprintf("%s/stores/10086/removal-cost", suite.urlPrefix))
+	re.Equal(http.StatusNotFound, status)
+	// reset the regions and the store heartbeat.
+	suite.cleanup()
+	suite.SetupSuite()
+}
+
 func (suite *storeTestSuite) TestStoreSetState() {
 	re := suite.Require()
 	// prepare enough online stores to store replica.
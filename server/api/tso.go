@@ -77,3 +77,12 @@ func (h *tsoHandler) TransferLocalTSOAllocator(w http.ResponseWriter, r *http.Re
 	}
 	h.rd.JSON(w, http.StatusOK, "The transfer command is submitted.")
 }
+
+// @Tags     tso
+// @Summary  Get the TSO allocation counts of the calling components since the server started, which tell whether TiDB, CDC or BR drives the TSO QPS.
+// @Produce  json
+// @Success  200  {array}  tso.ComponentAllocation
+// @Router   /tso/components [get]
+func (h *tsoHandler) GetComponentAllocations(w http.ResponseWriter, _ *http.Request) {
+	h.rd.JSON(w, http.StatusOK, h.svr.GetTSOComponentAllocations())
+}
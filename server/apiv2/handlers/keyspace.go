@@ -16,6 +16,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/keyspacepb"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/keyspace"
+	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/apiv2/middlewares"
 )
@@ -41,7 +43,13 @@ func RegisterKeyspace(r *gin.RouterGroup) {
 	router.GET("/:name", LoadKeyspace)
 	router.PATCH("/:name/config", UpdateKeyspaceConfig)
 	router.PUT("/:name/state", UpdateKeyspaceState)
+	router.PUT("/:name/metadata", UpdateKeyspaceMetadata)
 	router.GET("/id/:id", LoadKeyspaceByID)
+
+	schemaRouter := r.Group("keyspace-metadata-schema")
+	schemaRouter.Use(middlewares.BootstrapChecker())
+	schemaRouter.GET("", GetKeyspaceMetadataSchema)
+	schemaRouter.PUT("", SetKeyspaceMetadataSchema)
 }
 
 // CreateKeyspaceParams represents parameters needed when creating a new keyspace.
@@ -49,6 +57,8 @@ func RegisterKeyspace(r *gin.RouterGroup) {
 type CreateKeyspaceParams struct {
 	Name   string            `json:"name"`
 	Config map[string]string `json:"config"`
+	// Metadata is the custom metadata validated by the metadata schema.
+	Metadata json.RawMessage `json:"metadata,omitempty"`
 }
 
 // CreateKeyspace creates keyspace according to given input.
@@ -74,6 +84,12 @@ func CreateKeyspace(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, errs.ErrBindJSON.Wrap(err).GenWithStackByCause())
 		return
 	}
+	if len(createParams.Metadata) > 0 {
+		if createParams.Config == nil {
+			createParams.Config = make(map[string]string)
+		}
+		createParams.Config[keyspace.MetadataKey] = string(createParams.Metadata)
+	}
 	req := &keyspace.CreateKeyspaceRequest{
 		Name:       createParams.Name,
 		Config:     createParams.Config,
@@ -82,7 +98,7 @@ func CreateKeyspace(c *gin.Context) {
 	}
 	meta, err := manager.CreateKeyspace(req)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		c.AbortWithStatusJSON(metadataErrorStatus(err), err.Error())
 		return
 	}
 	c.IndentedJSON(http.StatusOK, &KeyspaceMeta{meta})
@@ -156,7 +172,7 @@ func LoadKeyspaceByID(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, &KeyspaceMeta{meta})
 }
 
-// parseLoadAllQuery parses LoadAllKeyspaces'/GetKeyspaceGroups' query parameters.
+// parseLoadAllQuery parses LoadAllKeyspaces'/GetKeyspaceGroups' pagination query.
 // page_token:
 // The keyspace/keyspace group id of the scan start. If not set, scan from keyspace/keyspace group with id 0.
 // It's string of ID of the previous scan result's last element (next_page_token).
@@ -164,33 +180,24 @@ func LoadKeyspaceByID(c *gin.Context) {
 // The maximum number of keyspace metas/keyspace groups to return. If not set, no limit is posed.
 // Every scan scans limit + 1 keyspaces/keyspace groups (if limit != 0), the extra scanned keyspace/keyspace group
 // is to check if there's more, and used to set next_page_token in response.
-func parseLoadAllQuery(c *gin.Context) (scanStart uint32, scanLimit int, err error) {
-	pageToken, set := c.GetQuery("page_token")
-	if !set || pageToken == "" {
-		// If pageToken is empty or unset, then scan from ID of 0.
-		scanStart = 0
-	} else {
-		scanStart64, err := strconv.ParseUint(pageToken, 10, 32)
-		if err != nil {
-			return 0, 0, err
-		}
-		scanStart = uint32(scanStart64)
+func parseLoadAllQuery(c *gin.Context) (page *apiutil.PageQuery, scanStart uint32, err error) {
+	page, err = apiutil.ParsePageQuery(c.Request.URL.Query())
+	if err != nil {
+		return nil, 0, err
 	}
-
-	limitStr, set := c.GetQuery("limit")
-	if !set || limitStr == "" || limitStr == "0" {
-		// If limit is unset or empty or 0, then no limit is posed for scan.
-		scanLimit = 0
-	} else {
-		scanLimit64, err := strconv.ParseInt(limitStr, 10, 64)
-		if err != nil {
-			return 0, 0, err
-		}
-		// Scan an extra element for next_page_token.
-		scanLimit = int(scanLimit64) + 1
+	start, err := page.IDToken()
+	if err != nil {
+		return nil, 0, err
 	}
+	if start > math.MaxUint32 {
+		return nil, 0, errors.Errorf("invalid page token %q", page.Token)
+	}
+	return page, uint32(start), nil
+}
 
-	return scanStart, scanLimit, nil
+// idPageToken returns the page token of the keyspace/keyspace group with the ID.
+func idPageToken(id uint32) string {
+	return strconv.FormatUint(uint64(id), 10)
 }
 
 // LoadAllKeyspacesResponse represents response given when loading all keyspaces.
@@ -208,6 +215,7 @@ type LoadAllKeyspacesResponse struct {
 // @Summary  list keyspaces.
 // @Param    page_token  query  string  false  "page token"
 // @Param    limit       query  string  false  "maximum number of results to return"
+// @Param    metadata.*  query  string  false  "only return the keyspaces whose metadata field equals to the value"
 // @Produce  json
 // @Success  200  {object}  LoadAllKeyspacesResponse
 // @Failure  400  {string}  string  "The input is invalid."
@@ -220,12 +228,12 @@ func LoadAllKeyspaces(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, managerUninitializedErr)
 		return
 	}
-	scanStart, scanLimit, err := parseLoadAllQuery(c)
+	page, scanStart, err := parseLoadAllQuery(c)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
 		return
 	}
-	scanned, err := manager.LoadRangeKeyspace(scanStart, scanLimit)
+	scanned, err := manager.LoadRangeKeyspaceByMetadata(scanStart, page.ScanLimit(), parseMetadataFilters(c))
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
 		return
@@ -236,26 +244,31 @@ func LoadAllKeyspaces(c *gin.Context) {
 		c.IndentedJSON(http.StatusOK, resp)
 		return
 	}
-	var resultKeyspaces []*KeyspaceMeta
-	if scanLimit == 0 || len(scanned) < scanLimit {
-		// No next page, all scanned are results.
-		resultKeyspaces = make([]*KeyspaceMeta, len(scanned))
-		for i, meta := range scanned {
-			resultKeyspaces[i] = &KeyspaceMeta{meta}
-		}
-	} else {
-		// Scanned limit + 1 keyspaces, there is next page, all but last are results.
-		resultKeyspaces = make([]*KeyspaceMeta, len(scanned)-1)
-		for i := range resultKeyspaces {
-			resultKeyspaces[i] = &KeyspaceMeta{scanned[i]}
-		}
-		// Also set next_page_token here.
-		resp.NextPageToken = strconv.Itoa(int(scanned[len(scanned)-1].Id))
+	// Scanned limit + 1 keyspaces if there is next page, all but last are results.
+	scanned, resp.NextPageToken = apiutil.TrimPage(scanned, page, func(meta *keyspacepb.KeyspaceMeta) string {
+		return idPageToken(meta.GetId())
+	})
+	resultKeyspaces := make([]*KeyspaceMeta, 0, len(scanned))
+	for _, meta := range scanned {
+		resultKeyspaces = append(resultKeyspaces, &KeyspaceMeta{meta})
 	}
+	apiutil.SetNextPageToken(c.Writer, resp.NextPageToken)
 	resp.Keyspaces = resultKeyspaces
 	c.IndentedJSON(http.StatusOK, resp)
 }
 
+// parseMetadataFilters returns the filters of the metadata fields from the
+// query parameters like `metadata.tier=gold`.
+func parseMetadataFilters(c *gin.Context) map[string]string {
+	filters := make(map[string]string)
+	for key, values := range c.Request.URL.Query() {
+		if name, ok := strings.CutPrefix(key, keyspace.MetadataKey+"."); ok && name != "" && len(values) > 0 {
+			filters[name] = values[0]
+		}
+	}
+	return filters
+}
+
 // UpdateConfigParams represents parameters needed to modify target keyspace's configs.
 // NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
 // A Map of string to string pointer is used to differentiate between json null and "",
@@ -295,7 +308,7 @@ func UpdateKeyspaceConfig(c *gin.Context) {
 	mutations := getMutations(configParams.Config)
 	meta, err := manager.UpdateKeyspaceConfig(name, mutations)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		c.AbortWithStatusJSON(metadataErrorStatus(err), err.Error())
 		return
 	}
 	c.IndentedJSON(http.StatusOK, &KeyspaceMeta{meta})
@@ -366,6 +379,103 @@ func UpdateKeyspaceState(c *gin.Context) {
 	c.IndentedJSON(http.StatusOK, &KeyspaceMeta{meta})
 }
 
+// UpdateKeyspaceMetadata replaces target keyspace's metadata.
+//
+// @Tags     keyspaces
+// @Summary  Update keyspace metadata.
+// @Param    name  path  string  true  "Keyspace Name"
+// @Param    body  body  object  true  "The metadata object, null removes the metadata"
+// @Produce  json
+// @Success  200  {object}  KeyspaceMeta
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /keyspaces/{name}/metadata [put]
+func UpdateKeyspaceMetadata(c *gin.Context) {
+	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
+	manager := svr.GetKeyspaceManager()
+	if manager == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, managerUninitializedErr)
+		return
+	}
+	var metadata json.RawMessage
+	if err := c.BindJSON(&metadata); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errs.ErrBindJSON.Wrap(err).GenWithStackByCause())
+		return
+	}
+	meta, err := manager.UpdateKeyspaceMetadata(c.Param("name"), metadata)
+	if err != nil {
+		c.AbortWithStatusJSON(metadataErrorStatus(err), err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, &KeyspaceMeta{meta})
+}
+
+// GetKeyspaceMetadataSchema returns the schema of the keyspace metadata.
+//
+// @Tags     keyspaces
+// @Summary  Get the schema of keyspace metadata.
+// @Produce  json
+// @Success  200  {object}  keyspace.MetadataSchema
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /keyspace-metadata-schema [get]
+func GetKeyspaceMetadataSchema(c *gin.Context) {
+	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
+	manager := svr.GetKeyspaceManager()
+	if manager == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, managerUninitializedErr)
+		return
+	}
+	schema, err := manager.GetMetadataSchema()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, schema)
+}
+
+// SetKeyspaceMetadataSchema sets the schema of the keyspace metadata.
+//
+// @Tags     keyspaces
+// @Summary  Set the schema of keyspace metadata, null removes the schema.
+// @Param    body  body  keyspace.MetadataSchema  true  "The schema of keyspace metadata"
+// @Produce  json
+// @Success  200  {string}  string  "The schema is updated."
+// @Failure  400  {string}  string  "The input is invalid."
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /keyspace-metadata-schema [put]
+func SetKeyspaceMetadataSchema(c *gin.Context) {
+	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
+	manager := svr.GetKeyspaceManager()
+	if manager == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, managerUninitializedErr)
+		return
+	}
+	var schema *keyspace.MetadataSchema
+	if err := c.BindJSON(&schema); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errs.ErrBindJSON.Wrap(err).GenWithStackByCause())
+		return
+	}
+	if schema != nil {
+		if err := schema.Check(); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if err := manager.SetMetadataSchema(schema); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, "The schema is updated.")
+}
+
+// metadataErrorStatus returns 400 for the invalid metadata, 500 otherwise.
+func metadataErrorStatus(err error) int {
+	if errors.Cause(err) == keyspace.ErrInvalidKeyspaceMetadata {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 // KeyspaceMeta wraps keyspacepb.KeyspaceMeta to provide custom JSON marshal.
 type KeyspaceMeta struct {
 	*keyspacepb.KeyspaceMeta
@@ -373,7 +483,19 @@ type KeyspaceMeta struct {
 
 // MarshalJSON creates custom marshal of KeyspaceMeta with the following:
 // 1. Keyspace State are marshaled to their corresponding name for better readability.
+// 2. Keyspace metadata is marshaled as a JSON object instead of a config item.
 func (meta *KeyspaceMeta) MarshalJSON() ([]byte, error) {
+	config := meta.Config
+	var metadata json.RawMessage
+	if value, ok := meta.Config[keyspace.MetadataKey]; ok && json.Valid([]byte(value)) {
+		metadata = json.RawMessage(value)
+		config = make(map[string]string, len(meta.Config))
+		for k, v := range meta.Config {
+			if k != keyspace.MetadataKey {
+				config[k] = v
+			}
+		}
+	}
 	return json.Marshal(&struct {
 		ID             uint32            `json:"id"`
 		Name           string            `json:"name,omitempty"`
@@ -381,13 +503,15 @@ func (meta *KeyspaceMeta) MarshalJSON() ([]byte, error) {
 		CreatedAt      int64             `json:"created_at,omitempty"`
 		StateChangedAt int64             `json:"state_changed_at,omitempty"`
 		Config         map[string]string `json:"config,omitempty"`
+		Metadata       json.RawMessage   `json:"metadata,omitempty"`
 	}{
 		meta.Id,
 		meta.Name,
 		meta.State.String(),
 		meta.CreatedAt,
 		meta.StateChangedAt,
-		meta.Config,
+		config,
+		metadata,
 	})
 }
 
@@ -400,11 +524,18 @@ func (meta *KeyspaceMeta) UnmarshalJSON(data []byte) error {
 		CreatedAt      int64             `json:"created_at,omitempty"`
 		StateChangedAt int64             `json:"state_changed_at,omitempty"`
 		Config         map[string]string `json:"config,omitempty"`
+		Metadata       json.RawMessage   `json:"metadata,omitempty"`
 	}{}
 
 	if err := json.Unmarshal(data, aux); err != nil {
 		return err
 	}
+	if len(aux.Metadata) > 0 {
+		if aux.Config == nil {
+			aux.Config = make(map[string]string)
+		}
+		aux.Config[keyspace.MetadataKey] = string(aux.Metadata)
+	}
 	pbMeta := &keyspacepb.KeyspaceMeta{
 		Id:             aux.ID,
 		Name:           aux.Name,
@@ -19,6 +19,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/tikv/pd/pkg/mcs/discovery"
+	"github.com/tikv/pd/pkg/mcs/utils"
+	"github.com/tikv/pd/pkg/versioninfo"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/apiv2/middlewares"
 )
@@ -28,6 +30,101 @@ func RegisterMicroService(r *gin.RouterGroup) {
 	router := r.Group("ms")
 	router.GET("members/:service", GetMembers)
 	router.GET("primary/:service", GetPrimary)
+	router.GET("handshake", Handshake)
+}
+
+// HandshakeProtocolVersion is the version of the handshake protocol. It should be
+// increased once the response of the handshake is changed incompatibly.
+const HandshakeProtocolVersion = 1
+
+const (
+	// pdServiceMode means all the services are provided by PD itself.
+	pdServiceMode = "pd"
+	// apiServiceMode means some services are split out into microservices.
+	apiServiceMode = "api"
+)
+
+// handshakeServices is the services which can be split out into microservices.
+var handshakeServices = []string{
+	utils.TSOServiceName,
+	utils.SchedulingServiceName,
+	utils.ResourceManagerServiceName,
+}
+
+// ServiceEndpoint is the endpoint of a service.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ServiceEndpoint struct {
+	Addr    string `json:"addr"`
+	Version string `json:"version"`
+}
+
+// ServiceDeployment describes how a service is deployed.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ServiceDeployment struct {
+	Name string `json:"name"`
+	// Independent means the service is provided by the microservice rather than PD.
+	Independent bool              `json:"independent"`
+	Endpoints   []ServiceEndpoint `json:"endpoints"`
+}
+
+// HandshakeResponse is the response of the handshake.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type HandshakeResponse struct {
+	Mode            string              `json:"mode"`
+	ProtocolVersion int                 `json:"protocol-version"`
+	Services        []ServiceDeployment `json:"services"`
+}
+
+// Handshake returns which services are split out into microservices and their endpoints,
+// so that the clients can detect the deployment mode without the static configuration.
+// @Tags     handshake
+// @Summary  Get the deployment mode and the endpoints of the services.
+// @Produce  json
+// @Success  200  {object}  HandshakeResponse
+// @Failure  500  {string}  string  "PD server failed to proceed the request."
+// @Router   /ms/handshake [get]
+func Handshake(c *gin.Context) {
+	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
+	resp := &HandshakeResponse{
+		Mode:            pdServiceMode,
+		ProtocolVersion: HandshakeProtocolVersion,
+		Services:        make([]ServiceDeployment, 0, len(handshakeServices)),
+	}
+	pdEndpoints := []ServiceEndpoint{{Addr: svr.GetAddr(), Version: versioninfo.PDReleaseVersion}}
+	if !svr.IsAPIServiceMode() {
+		for _, name := range handshakeServices {
+			resp.Services = append(resp.Services, ServiceDeployment{Name: name, Endpoints: pdEndpoints})
+		}
+		c.IndentedJSON(http.StatusOK, resp)
+		return
+	}
+
+	resp.Mode = apiServiceMode
+	for _, name := range handshakeServices {
+		entries, err := discovery.GetMSMembers(name, svr.GetClient())
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+			return
+		}
+		deployment := ServiceDeployment{Name: name, Endpoints: make([]ServiceEndpoint, 0, len(entries))}
+		for _, entry := range entries {
+			deployment.Endpoints = append(deployment.Endpoints, ServiceEndpoint{Addr: entry.ServiceAddr, Version: entry.Version})
+		}
+		switch name {
+		case utils.TSOServiceName:
+			// The TSO service is always provided by the microservice in API service mode.
+			deployment.Independent = true
+		case utils.SchedulingServiceName:
+			deployment.Independent = svr.IsServiceIndependent(name)
+		default:
+			deployment.Independent = len(entries) > 0
+		}
+		if !deployment.Independent {
+			deployment.Endpoints = pdEndpoints
+		}
+		resp.Services = append(resp.Services, deployment)
+	}
+	c.IndentedJSON(http.StatusOK, resp)
 }
 
 // GetMembers gets all members of the cluster for the specified service.
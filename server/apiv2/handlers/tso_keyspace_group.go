@@ -18,13 +18,16 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/pingcap/errors"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/apiv2/middlewares"
@@ -44,10 +47,21 @@ func RegisterTSOKeyspaceGroup(r *gin.RouterGroup) {
 	router.PATCH("/:id", SetNodesForKeyspaceGroup)          // only to support set nodes
 	router.PATCH("/:id/*node", SetPriorityForKeyspaceGroup) // only to support set priority
 	router.POST("/:id/alloc", AllocNodesForKeyspaceGroup)
+	router.POST("/:id/tso-config", UpdateTSOConfigForKeyspaceGroup)
 	router.POST("/:id/split", SplitKeyspaceGroupByID)
 	router.DELETE("/:id/split", FinishSplitKeyspaceByID)
 	router.POST("/:id/merge", MergeKeyspaceGroups)
 	router.DELETE("/:id/merge", FinishMergeKeyspaceByID)
+
+	profileRouter := r.Group("tso/keyspace-group-profiles")
+	profileRouter.Use(middlewares.BootstrapChecker())
+	profileRouter.GET("", GetKeyspaceGroupProfiles)
+	profileRouter.GET("/:kind", GetKeyspaceGroupProfile)
+	profileRouter.PATCH("/:kind", UpdateKeyspaceGroupProfile)
+
+	autoSplitRouter := r.Group("tso/keyspace-group-auto-split-events")
+	autoSplitRouter.Use(middlewares.BootstrapChecker())
+	autoSplitRouter.GET("", GetKeyspaceGroupAutoSplitEvents)
 }
 
 // CreateKeyspaceGroupParams defines the params for creating keyspace groups.
@@ -55,15 +69,35 @@ type CreateKeyspaceGroupParams struct {
 	KeyspaceGroups []*endpoint.KeyspaceGroup `json:"keyspace-groups"`
 }
 
+// keyspaceGroupMemberPriorities is used to find out the members whose priority is not specified.
+type keyspaceGroupMemberPriorities struct {
+	KeyspaceGroups []struct {
+		Members []struct {
+			Priority *int `json:"priority"`
+		} `json:"members"`
+	} `json:"keyspace-groups"`
+}
+
 // CreateKeyspaceGroups creates keyspace groups.
+// The members without a priority take the priority of the profile of the user kind.
 func CreateKeyspaceGroups(c *gin.Context) {
 	createParams := &CreateKeyspaceGroupParams{}
-	err := c.BindJSON(createParams)
+	err := c.ShouldBindBodyWith(createParams, binding.JSON)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, errs.ErrBindJSON.Wrap(err).GenWithStackByCause())
 		return
 	}
-	for _, keyspaceGroup := range createParams.KeyspaceGroups {
+	priorities := &keyspaceGroupMemberPriorities{}
+	if err := c.ShouldBindBodyWith(priorities, binding.JSON); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errs.ErrBindJSON.Wrap(err).GenWithStackByCause())
+		return
+	}
+	for i, keyspaceGroup := range createParams.KeyspaceGroups {
+		for j, member := range priorities.KeyspaceGroups[i].Members {
+			if member.Priority == nil {
+				keyspaceGroup.Members[j].Priority = utils.UnsetKeyspaceGroupReplicaPriority
+			}
+		}
 		if !isValid(keyspaceGroup.ID) {
 			c.AbortWithStatusJSON(http.StatusBadRequest, "invalid keyspace group id")
 			return
@@ -74,6 +108,10 @@ func CreateKeyspaceGroups(c *gin.Context) {
 			c.AbortWithStatusJSON(http.StatusBadRequest, "invalid user kind")
 			return
 		}
+		if err := endpoint.ValidateTSOConfig(keyspaceGroup.TSOConfig); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
+			return
+		}
 	}
 
 	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
@@ -93,7 +131,7 @@ func CreateKeyspaceGroups(c *gin.Context) {
 // GetKeyspaceGroups gets keyspace groups from the start ID with limit.
 // If limit is 0, it will load all keyspace groups from the start ID.
 func GetKeyspaceGroups(c *gin.Context) {
-	scanStart, scanLimit, err := parseLoadAllQuery(c)
+	page, scanStart, err := parseLoadAllQuery(c)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
 		return
@@ -105,11 +143,15 @@ func GetKeyspaceGroups(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, GroupManagerUninitializedErr)
 		return
 	}
-	keyspaceGroups, err := manager.GetKeyspaceGroups(scanStart, scanLimit)
+	keyspaceGroups, err := manager.GetKeyspaceGroups(scanStart, page.ScanLimit())
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
 		return
 	}
+	keyspaceGroups, nextPageToken := apiutil.TrimPage(keyspaceGroups, page, func(kg *endpoint.KeyspaceGroup) string {
+		return idPageToken(kg.ID)
+	})
+	apiutil.SetNextPageToken(c.Writer, nextPageToken)
 	var kgs []*endpoint.KeyspaceGroup
 	state, set := c.GetQuery("state")
 	if set {
@@ -208,6 +250,9 @@ type SplitKeyspaceGroupByIDParams struct {
 	// StartKeyspaceID and EndKeyspaceID are used to indicate the range of keyspaces to be split.
 	StartKeyspaceID uint32 `json:"start-keyspace-id"`
 	EndKeyspaceID   uint32 `json:"end-keyspace-id"`
+	// Primary is the optional tso node which the primary of the new keyspace group
+	// will be handed off to after the split is finished.
+	Primary string `json:"primary,omitempty"`
 }
 
 var patrolKeyspaceAssignmentState struct {
@@ -269,9 +314,15 @@ func SplitKeyspaceGroupByID(c *gin.Context) {
 	patrolKeyspaceAssignmentState.Unlock()
 
 	// Split keyspace group.
-	err = groupManager.SplitKeyspaceGroupByID(
-		id, splitParams.NewID,
-		splitParams.Keyspaces, splitParams.StartKeyspaceID, splitParams.EndKeyspaceID)
+	if len(splitParams.Primary) > 0 {
+		err = groupManager.SplitKeyspaceGroupWithPrimary(
+			id, splitParams.NewID, splitParams.Primary,
+			splitParams.Keyspaces, splitParams.StartKeyspaceID, splitParams.EndKeyspaceID)
+	} else {
+		err = groupManager.SplitKeyspaceGroupByID(
+			id, splitParams.NewID,
+			splitParams.Keyspaces, splitParams.StartKeyspaceID, splitParams.EndKeyspaceID)
+	}
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
 		return
@@ -528,6 +579,185 @@ func SetPriorityForKeyspaceGroup(c *gin.Context) {
 	c.JSON(http.StatusOK, nil)
 }
 
+// UpdateTSOConfigForKeyspaceGroupParams defines the params for updating the TSO settings of the keyspace group.
+type UpdateTSOConfigForKeyspaceGroupParams struct {
+	TSOConfig map[string]*string `json:"tso-config"`
+}
+
+// UpdateTSOConfigForKeyspaceGroup updates the TSO settings of the keyspace group, which
+// override the ones of the TSO servers. A TSO setting is removed if its value is null.
+func UpdateTSOConfigForKeyspaceGroup(c *gin.Context) {
+	id, err := validateKeyspaceGroupID(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, "invalid keyspace group id")
+		return
+	}
+	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
+	manager := svr.GetKeyspaceGroupManager()
+	if manager == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, GroupManagerUninitializedErr)
+		return
+	}
+	updateParams := &UpdateTSOConfigForKeyspaceGroupParams{}
+	err = c.BindJSON(updateParams)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errs.ErrBindJSON.Wrap(err).GenWithStackByCause())
+		return
+	}
+	kg, err := manager.UpdateTSOConfigForKeyspaceGroup(id, updateParams.TSOConfig)
+	if err != nil {
+		if errs.ErrInvalidTSOConfig.Equal(err) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, kg)
+}
+
+// GetKeyspaceGroupProfiles gets the keyspace group profiles of all user kinds.
+func GetKeyspaceGroupProfiles(c *gin.Context) {
+	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
+	manager := svr.GetKeyspaceGroupManager()
+	if manager == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, GroupManagerUninitializedErr)
+		return
+	}
+	profiles, err := manager.GetKeyspaceGroupProfiles()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, profiles)
+}
+
+// GetKeyspaceGroupProfile gets the keyspace group profile of the user kind.
+func GetKeyspaceGroupProfile(c *gin.Context) {
+	kind := c.Param("kind")
+	if !endpoint.IsUserKindValid(kind) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, "invalid user kind")
+		return
+	}
+	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
+	manager := svr.GetKeyspaceGroupManager()
+	if manager == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, GroupManagerUninitializedErr)
+		return
+	}
+	profile, err := manager.GetKeyspaceGroupProfile(endpoint.StringUserKind(kind))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, profile)
+}
+
+// UpdateKeyspaceGroupProfileParams defines the params for updating the keyspace group profile.
+// The fields which are not specified are left unchanged.
+type UpdateKeyspaceGroupProfileParams struct {
+	ReplicaCount *int               `json:"replica-count"`
+	Priority     *int               `json:"priority"`
+	TSOConfig    map[string]*string `json:"tso-config"`
+}
+
+// UpdateKeyspaceGroupProfile updates the keyspace group profile of the user kind.
+// A TSO setting is removed from the profile if its value is null.
+func UpdateKeyspaceGroupProfile(c *gin.Context) {
+	kind := c.Param("kind")
+	if !endpoint.IsUserKindValid(kind) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, "invalid user kind")
+		return
+	}
+	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
+	manager := svr.GetKeyspaceGroupManager()
+	if manager == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, GroupManagerUninitializedErr)
+		return
+	}
+	updateParams := &UpdateKeyspaceGroupProfileParams{}
+	err := c.BindJSON(updateParams)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, errs.ErrBindJSON.Wrap(err).GenWithStackByCause())
+		return
+	}
+	if updateParams.ReplicaCount != nil && *updateParams.ReplicaCount < utils.DefaultKeyspaceGroupReplicaCount {
+		c.AbortWithStatusJSON(http.StatusBadRequest, "invalid replica count, should be at least 2")
+		return
+	}
+	if updateParams.Priority != nil && *updateParams.Priority == utils.UnsetKeyspaceGroupReplicaPriority {
+		c.AbortWithStatusJSON(http.StatusBadRequest, "invalid priority")
+		return
+	}
+	tsoConfig := make(map[string]string, len(updateParams.TSOConfig))
+	for k, v := range updateParams.TSOConfig {
+		if v != nil {
+			tsoConfig[k] = *v
+		}
+	}
+	if err := endpoint.ValidateTSOConfig(tsoConfig); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
+		return
+	}
+	profile, err := manager.UpdateKeyspaceGroupProfile(endpoint.StringUserKind(kind), func(profile *endpoint.KeyspaceGroupProfile) {
+		if updateParams.ReplicaCount != nil {
+			profile.ReplicaCount = *updateParams.ReplicaCount
+		}
+		if updateParams.Priority != nil {
+			profile.Priority = *updateParams.Priority
+		}
+		for k, v := range updateParams.TSOConfig {
+			if v == nil {
+				delete(profile.TSOConfig, k)
+				continue
+			}
+			if profile.TSOConfig == nil {
+				profile.TSOConfig = make(map[string]string)
+			}
+			profile.TSOConfig[k] = *v
+		}
+	})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, profile)
+}
+
+// GetKeyspaceGroupAutoSplitEvents gets the events of splitting the keyspace groups automatically.
+// The events can be filtered by the unix timestamps in seconds with the query parameters
+// `start_time` and `end_time`, the range is [start_time, end_time).
+func GetKeyspaceGroupAutoSplitEvents(c *gin.Context) {
+	var startTime, endTime time.Time
+	for _, t := range []struct {
+		name string
+		time *time.Time
+	}{{"start_time", &startTime}, {"end_time", &endTime}} {
+		v := c.Query(t.name)
+		if v == "" {
+			continue
+		}
+		ts, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, "invalid "+t.name)
+			return
+		}
+		*t.time = time.Unix(ts, 0)
+	}
+	svr := c.MustGet(middlewares.ServerContextKey).(*server.Server)
+	manager := svr.GetKeyspaceGroupManager()
+	if manager == nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, GroupManagerUninitializedErr)
+		return
+	}
+	events, err := manager.GetAutoSplitEvents(startTime, endTime)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.IndentedJSON(http.StatusOK, events)
+}
+
 func validateKeyspaceGroupID(c *gin.Context) (uint32, error) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
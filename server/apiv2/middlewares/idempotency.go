@@ -0,0 +1,196 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tikv/pd/pkg/cache"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+const (
+	// IdempotencyKeyHeader is the header carrying the key chosen by the client
+	// for a mutating request. The retries with the same key get the result of
+	// the first execution instead of executing the request again.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks the response replayed from the result of
+	// the first execution.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultIdempotencyKeyTTL is how long the result of the first execution is kept.
+	DefaultIdempotencyKeyTTL = 10 * time.Minute
+	maxIdempotencyKeyLength  = 255
+	// maxIdempotencyKeys is the max number of the kept results, the requests
+	// with the new keys are refused once it's reached.
+	maxIdempotencyKeys = 10000
+	// maxIdempotentBodySize is the max size of the buffered request body and
+	// of the kept response body of a request with an idempotency key.
+	maxIdempotentBodySize = 1 << 20
+)
+
+// idempotentResult is the result of the first execution of the request with an
+// idempotency key. The fields are set before done is closed.
+type idempotentResult struct {
+	fingerprint string
+	done        chan struct{}
+	status      int
+	contentType string
+	body        []byte
+}
+
+// responseRecorder records the response while writing it to the client, it
+// stops recording once the body exceeds maxIdempotentBodySize.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *responseRecorder) record(data []byte) {
+	if w.truncated || w.body.Len()+len(data) > maxIdempotentBodySize {
+		w.truncated = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.record(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency is a middleware to support the Idempotency-Key header on the
+// mutating requests. The result of the first execution is kept for the TTL and
+// replayed to the retries with the same key, so the retrying clients don't
+// create the resources twice. Reusing the key for a different request is
+// refused, and so is a retry while the first execution is in progress. The
+// results of the server errors, the panics and the responses too large to be
+// kept are dropped, so they can be retried.
+//
+// NOTE: the results are only kept in the memory of the PD leader, so a retry
+// reaching the new leader after the leader changes executes the request again.
+func Idempotency(ctx context.Context, ttl time.Duration) gin.HandlerFunc {
+	var mu syncutil.Mutex
+	results := cache.NewStringTTL(ctx, ttl, ttl)
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if len(key) == 0 || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, "the idempotency key is too long")
+			return
+		}
+		// The body is buffered to fingerprint the request, so limit its size.
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, err.Error())
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(c.Request, body)
+
+		mu.Lock()
+		if v, ok := results.Get(key); ok {
+			mu.Unlock()
+			replayResult(c, v.(*idempotentResult), fingerprint)
+			return
+		}
+		if results.Len() >= maxIdempotencyKeys {
+			mu.Unlock()
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, "too many idempotency keys are kept, please retry later")
+			return
+		}
+		result := &idempotentResult{
+			fingerprint: fingerprint,
+			done:        make(chan struct{}),
+		}
+		results.Put(key, result)
+		mu.Unlock()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		finished := false
+		// Wake up the retries even if the handler panics.
+		defer func() {
+			result.status = recorder.Status()
+			result.contentType = recorder.Header().Get("Content-Type")
+			result.body = recorder.body.Bytes()
+			// Remove the result before waking up the retries, otherwise they
+			// may replay it.
+			if !finished || recorder.truncated || result.status >= http.StatusInternalServerError {
+				mu.Lock()
+				results.Remove(key)
+				mu.Unlock()
+			}
+			close(result.done)
+		}()
+		c.Next()
+		finished = true
+	}
+}
+
+func replayResult(c *gin.Context, result *idempotentResult, fingerprint string) {
+	if result.fingerprint != fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, "the idempotency key is used by a different request")
+		return
+	}
+	select {
+	case <-result.done:
+	default:
+		c.AbortWithStatusJSON(http.StatusConflict, "the request with the same idempotency key is in progress")
+		return
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(result.status, result.contentType, result.body)
+	c.Abort()
+}
+
+// requestFingerprint identifies the request by the method, the URL and the body.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
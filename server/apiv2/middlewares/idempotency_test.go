@@ -0,0 +1,182 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotencyRouter(ctx context.Context, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(Idempotency(ctx, time.Minute))
+	router.POST("/resources", handler)
+	return router
+}
+
+func serveIdempotent(router http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/resources", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var executed atomic.Int32
+	router := newIdempotencyRouter(ctx, func(c *gin.Context) {
+		c.String(http.StatusCreated, "created-%d", executed.Add(1))
+	})
+
+	first := serveIdempotent(router, "key", `{"name":"a"}`)
+	re.Equal(http.StatusCreated, first.Code)
+	re.Empty(first.Header().Get(IdempotentReplayedHeader))
+	retry := serveIdempotent(router, "key", `{"name":"a"}`)
+	re.Equal(http.StatusCreated, retry.Code)
+	re.Equal("true", retry.Header().Get(IdempotentReplayedHeader))
+	re.Equal(first.Body.String(), retry.Body.String())
+	re.Equal(int32(1), executed.Load())
+
+	// reusing the key for a different request is refused.
+	w := serveIdempotent(router, "key", `{"name":"b"}`)
+	re.Equal(http.StatusUnprocessableEntity, w.Code)
+	re.Equal(int32(1), executed.Load())
+
+	// a different key executes the request again.
+	w = serveIdempotent(router, "another-key", `{"name":"a"}`)
+	re.Equal(http.StatusCreated, w.Code)
+	re.Equal(int32(2), executed.Load())
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started, release := make(chan struct{}), make(chan struct{})
+	router := newIdempotencyRouter(ctx, func(c *gin.Context) {
+		close(started)
+		<-release
+		c.String(http.StatusCreated, "created")
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- serveIdempotent(router, "key", "body")
+	}()
+	<-started
+	w := serveIdempotent(router, "key", "body")
+	re.Equal(http.StatusConflict, w.Code)
+	close(release)
+	re.Equal(http.StatusCreated, (<-done).Code)
+
+	w = serveIdempotent(router, "key", "body")
+	re.Equal(http.StatusCreated, w.Code)
+	re.Equal("true", w.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotencyServerError(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var executed atomic.Int32
+	router := newIdempotencyRouter(ctx, func(c *gin.Context) {
+		if executed.Add(1) == 1 {
+			c.String(http.StatusInternalServerError, "failed")
+			return
+		}
+		c.String(http.StatusCreated, "created")
+	})
+
+	w := serveIdempotent(router, "key", "body")
+	re.Equal(http.StatusInternalServerError, w.Code)
+	// the server error is not kept, so the retry is executed again.
+	w = serveIdempotent(router, "key", "body")
+	re.Equal(http.StatusCreated, w.Code)
+	re.Empty(w.Header().Get(IdempotentReplayedHeader))
+	re.Equal(int32(2), executed.Load())
+}
+
+func TestIdempotencyPanic(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var executed atomic.Int32
+	router := newIdempotencyRouter(ctx, func(c *gin.Context) {
+		if executed.Add(1) == 1 {
+			panic("handler panics")
+		}
+		c.String(http.StatusCreated, "created")
+	})
+
+	w := serveIdempotent(router, "key", "body")
+	re.Equal(http.StatusInternalServerError, w.Code)
+	// the key is released after the panic, so the retry is executed again.
+	w = serveIdempotent(router, "key", "body")
+	re.Equal(http.StatusCreated, w.Code)
+	re.Empty(w.Header().Get(IdempotentReplayedHeader))
+	re.Equal(int32(2), executed.Load())
+}
+
+func TestIdempotencyLimits(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var executed atomic.Int32
+	router := newIdempotencyRouter(ctx, func(c *gin.Context) {
+		if executed.Add(1) == 1 {
+			c.String(http.StatusOK, strings.Repeat("a", maxIdempotentBodySize+1))
+			return
+		}
+		c.String(http.StatusCreated, "created")
+	})
+
+	// the request body is too large to be buffered.
+	w := serveIdempotent(router, "key", strings.Repeat("a", maxIdempotentBodySize+1))
+	re.Equal(http.StatusRequestEntityTooLarge, w.Code)
+	re.Equal(int32(0), executed.Load())
+
+	// the response is too large to be kept, so the retry is executed again.
+	w = serveIdempotent(router, "key", "body")
+	re.Equal(http.StatusOK, w.Code)
+	re.Equal(maxIdempotentBodySize+1, w.Body.Len())
+	w = serveIdempotent(router, "key", "body")
+	re.Equal(http.StatusCreated, w.Code)
+	re.Empty(w.Header().Get(IdempotentReplayedHeader))
+	re.Equal(int32(2), executed.Load())
+
+	// the requests with the new keys are refused once too many keys are kept.
+	for i := 1; i < maxIdempotencyKeys; i++ {
+		serveIdempotent(router, fmt.Sprintf("key-%d", i), "body")
+	}
+	w = serveIdempotent(router, "new-key", "body")
+	re.Equal(http.StatusTooManyRequests, w.Code)
+	// the kept results are still replayed.
+	w = serveIdempotent(router, "key", "body")
+	re.Equal("true", w.Header().Get(IdempotentReplayedHeader))
+}
@@ -44,13 +44,14 @@ const apiV2Prefix = "/pd/api/v2/"
 // @license.name   Apache 2.0
 // @license.url    http://www.apache.org/licenses/LICENSE-2.0.html
 // @BasePath       /pd/api/v2
-func NewV2Handler(_ context.Context, svr *server.Server) (http.Handler, apiutil.APIServiceGroup, error) {
+func NewV2Handler(ctx context.Context, svr *server.Server) (http.Handler, apiutil.APIServiceGroup, error) {
 	router := gin.New()
 	router.Use(func(c *gin.Context) {
 		c.Set(middlewares.ServerContextKey, svr)
 		c.Next()
 	})
 	router.Use(middlewares.Redirector())
+	router.Use(middlewares.Idempotency(ctx, middlewares.DefaultIdempotencyKeyTTL))
 	root := router.Group(apiV2Prefix)
 	handlers.RegisterKeyspace(root)
 	handlers.RegisterTSOKeyspaceGroup(root)
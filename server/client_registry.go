@@ -0,0 +1,209 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/tikv/pd/pkg/utils/grpcutil"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	// clientExpireTime is the time after which a client without any RPC is forgotten.
+	clientExpireTime = 10 * time.Minute
+	// maxClientCount is the max number of the clients tracked, it prevents the
+	// short-lived connections from bloating the registry.
+	maxClientCount = 8192
+)
+
+// ClientInfo is the metadata of a gRPC client connected to PD.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type ClientInfo struct {
+	// Address is the remote address of the connection.
+	Address   string `json:"address"`
+	Component string `json:"component,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Keyspace  string `json:"keyspace,omitempty"`
+	// StartTime is the time when the first RPC of the client is received.
+	StartTime    time.Time `json:"start_time"`
+	LastSeenTime time.Time `json:"last_seen_time"`
+	// RPCs is the number of the requests of each RPC method.
+	RPCs map[string]uint64 `json:"rpcs"`
+}
+
+// clientRegistry tracks the gRPC clients by the metadata of their requests.
+type clientRegistry struct {
+	syncutil.RWMutex
+	clients map[string]*trackedClient
+}
+
+// trackedClient is a client in the registry. The RPCs are counted with the atomics,
+// so a stream can keep counting its requests after registering once.
+type trackedClient struct {
+	address   string
+	startTime time.Time
+	lastSeen  atomic.Int64
+	// removed is set once the client is forgotten, the holders should register it again.
+	removed atomic.Bool
+
+	mu        syncutil.RWMutex
+	component string
+	version   string
+	keyspace  string
+	rpcs      map[string]*atomic.Uint64
+}
+
+func newClientRegistry() *clientRegistry {
+	return &clientRegistry{clients: make(map[string]*trackedClient)}
+}
+
+// observe records the RPC of the client in the context at now.
+func (r *clientRegistry) observe(ctx context.Context, now time.Time) {
+	if client := r.register(ctx, now); client != nil {
+		client.observe(rpcMethod(ctx), now)
+	}
+}
+
+// register returns the client of the context, it adds the client if it's not tracked
+// yet and updates the metadata carried by the context. It returns nil if the client
+// can't be tracked.
+func (r *clientRegistry) register(ctx context.Context, now time.Time) *trackedClient {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return nil
+	}
+	addr := p.Addr.String()
+	r.RLock()
+	client, ok := r.clients[addr]
+	r.RUnlock()
+	if !ok {
+		r.Lock()
+		client, ok = r.clients[addr]
+		if !ok {
+			if len(r.clients) >= maxClientCount {
+				r.gcLocked(now)
+			}
+			if len(r.clients) >= maxClientCount {
+				r.Unlock()
+				return nil
+			}
+			client = &trackedClient{
+				address:   addr,
+				startTime: now,
+				rpcs:      make(map[string]*atomic.Uint64),
+			}
+			client.lastSeen.Store(now.UnixNano())
+			r.clients[addr] = client
+		}
+		r.Unlock()
+	}
+	client.updateMetadata(ctx)
+	return client
+}
+
+// updateMetadata updates the metadata of the client with the one in the context.
+// The metadata may be absent in some RPCs, e.g. the ones sent by the old clients.
+func (c *trackedClient) updateMetadata(ctx context.Context) {
+	component, version, keyspace := grpcutil.GetComponent(ctx), grpcutil.GetClientVersion(ctx), grpcutil.GetKeyspace(ctx)
+	c.mu.RLock()
+	changed := (component != "" && component != c.component) ||
+		(version != "" && version != c.version) ||
+		(keyspace != "" && keyspace != c.keyspace)
+	c.mu.RUnlock()
+	if !changed {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if component != "" {
+		c.component = component
+	}
+	if version != "" {
+		c.version = version
+	}
+	if keyspace != "" {
+		c.keyspace = keyspace
+	}
+}
+
+// observe records an RPC of the method at now.
+func (c *trackedClient) observe(method string, now time.Time) {
+	c.lastSeen.Store(now.UnixNano())
+	c.mu.RLock()
+	counter, ok := c.rpcs[method]
+	c.mu.RUnlock()
+	if !ok {
+		c.mu.Lock()
+		if counter, ok = c.rpcs[method]; !ok {
+			counter = &atomic.Uint64{}
+			c.rpcs[method] = counter
+		}
+		c.mu.Unlock()
+	}
+	counter.Add(1)
+}
+
+func (c *trackedClient) info() *ClientInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	info := &ClientInfo{
+		Address:      c.address,
+		Component:    c.component,
+		Version:      c.version,
+		Keyspace:     c.keyspace,
+		StartTime:    c.startTime,
+		LastSeenTime: time.Unix(0, c.lastSeen.Load()),
+		RPCs:         make(map[string]uint64, len(c.rpcs)),
+	}
+	for method, counter := range c.rpcs {
+		info.RPCs[method] = counter.Load()
+	}
+	return info
+}
+
+func (r *clientRegistry) gcLocked(now time.Time) {
+	for addr, client := range r.clients {
+		if now.Sub(time.Unix(0, client.lastSeen.Load())) > clientExpireTime {
+			client.removed.Store(true)
+			delete(r.clients, addr)
+		}
+	}
+}
+
+// getAll returns the clients which are active recently, sorted by the address.
+func (r *clientRegistry) getAll(now time.Time) []*ClientInfo {
+	r.Lock()
+	r.gcLocked(now)
+	clients := make([]*ClientInfo, 0, len(r.clients))
+	for _, client := range r.clients {
+		clients = append(clients, client.info())
+	}
+	r.Unlock()
+	sort.Slice(clients, func(i, j int) bool { return clients[i].Address < clients[j].Address })
+	return clients
+}
+
+// rpcMethod returns the name of the RPC method of the context without the service.
+func rpcMethod(ctx context.Context) string {
+	method, _ := grpc.Method(ctx)
+	return method[strings.LastIndex(method, "/")+1:]
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/utils/grpcutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+type mockServerTransportStream struct {
+	grpc.ServerTransportStream
+	method string
+}
+
+func (s *mockServerTransportStream) Method() string {
+	return s.method
+}
+
+func newClientContext(addr, method string, kv ...string) context.Context {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 1234}})
+	ctx = grpc.NewContextWithServerTransportStream(ctx, &mockServerTransportStream{method: method})
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(kv...))
+}
+
+func TestClientRegistry(t *testing.T) {
+	re := require.New(t)
+	registry := newClientRegistry()
+	now := time.Now()
+
+	registry.observe(newClientContext("127.0.0.2", "/pdpb.PD/GetRegion",
+		grpcutil.ComponentMetadataKey, "tidb",
+		grpcutil.ClientVersionMetadataKey, "v8.1.0",
+		grpcutil.KeyspaceMetadataKey, "1"), now)
+	registry.observe(newClientContext("127.0.0.2", "/pdpb.PD/GetRegion"), now.Add(time.Second))
+	registry.observe(newClientContext("127.0.0.2", "/pdpb.PD/Tso"), now.Add(2*time.Second))
+	registry.observe(newClientContext("127.0.0.1", "/pdpb.PD/StoreHeartbeat"), now.Add(3*time.Second))
+	// The context without the peer is ignored.
+	registry.observe(context.Background(), now)
+
+	clients := registry.getAll(now.Add(3 * time.Second))
+	re.Len(clients, 2)
+	re.Equal("127.0.0.1:1234", clients[0].Address)
+	re.Empty(clients[0].Component)
+	re.Equal(map[string]uint64{"StoreHeartbeat": 1}, clients[0].RPCs)
+	re.Equal("127.0.0.2:1234", clients[1].Address)
+	// The metadata is kept even if the later requests don't carry it.
+	re.Equal("tidb", clients[1].Component)
+	re.Equal("v8.1.0", clients[1].Version)
+	re.Equal("1", clients[1].Keyspace)
+	re.Equal(now, clients[1].StartTime)
+	re.True(now.Add(2 * time.Second).Equal(clients[1].LastSeenTime))
+	re.Equal(map[string]uint64{"GetRegion": 2, "Tso": 1}, clients[1].RPCs)
+
+	// The returned clients are copies.
+	clients[1].RPCs["Tso"] = 100
+	re.Equal(uint64(1), registry.getAll(now.Add(3 * time.Second))[1].RPCs["Tso"])
+
+	// The idle clients are forgotten.
+	clients = registry.getAll(now.Add(2*time.Second + clientExpireTime + time.Second))
+	re.Len(clients, 1)
+	re.Equal("127.0.0.1:1234", clients[0].Address)
+}
+
+func TestClientRegistryStream(t *testing.T) {
+	re := require.New(t)
+	registry := newClientRegistry()
+	now := time.Now()
+
+	// A stream registers the client once and keeps counting its requests.
+	ctx := newClientContext("127.0.0.1", "/pdpb.PD/Tso", grpcutil.ComponentMetadataKey, "tidb")
+	client := registry.register(ctx, now)
+	re.NotNil(client)
+	for i := 0; i < 3; i++ {
+		client.observe(rpcMethod(ctx), now.Add(time.Second))
+	}
+	registry.observe(newClientContext("127.0.0.1", "/pdpb.PD/GetRegion"), now)
+	clients := registry.getAll(now.Add(time.Second))
+	re.Len(clients, 1)
+	re.Equal("tidb", clients[0].Component)
+	re.Equal(map[string]uint64{"Tso": 3, "GetRegion": 1}, clients[0].RPCs)
+
+	// The forgotten client is marked, so the stream registers it again.
+	re.Empty(registry.getAll(now.Add(time.Second + clientExpireTime + time.Second)))
+	re.True(client.removed.Load())
+	re.NotSame(client, registry.register(ctx, now.Add(clientExpireTime)))
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/movingaverage"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"github.com/tikv/pd/pkg/utils/typeutil"
+	"go.uber.org/zap"
+)
+
+// The levels of the store clock skew.
+const (
+	ClockSkewLevelNormal  = "normal"
+	ClockSkewLevelWarning = "warning"
+	ClockSkewLevelDanger  = "danger"
+)
+
+const (
+	// clockSkewWarningThreshold is the skew to warn. The timestamps in the
+	// heartbeats are in seconds, so the skew within one second is the noise.
+	clockSkewWarningThreshold = time.Second
+	// clockSkewDangerThreshold is the skew which endangers the lease-based reads.
+	// The leader lease of TiKV is at most 9s by default, and it's considered
+	// unsafe once the skew eats up about half of it.
+	clockSkewDangerThreshold = 4 * time.Second
+	// clockSkewFilterSize is the number of the heartbeats used to estimate the
+	// skew, which filters out the jitters of the heartbeat latency.
+	clockSkewFilterSize = 5
+)
+
+// StoreClockSkew is the estimated clock skew of a store against PD.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreClockSkew struct {
+	StoreID uint64 `json:"store_id"`
+	Address string `json:"address"`
+	// Skew is positive if the store clock is ahead of PD.
+	Skew       typeutil.Duration `json:"skew"`
+	Level      string            `json:"level"`
+	UpdateTime time.Time         `json:"update_time"`
+}
+
+type storeClockSkew struct {
+	address    string
+	filter     *movingaverage.MedianFilter
+	level      string
+	updateTime time.Time
+}
+
+// clockSkewDetector estimates the clock skews of the stores by comparing the
+// end timestamps of the store heartbeats with the PD time.
+type clockSkewDetector struct {
+	syncutil.RWMutex
+	stores map[uint64]*storeClockSkew
+}
+
+func newClockSkewDetector() *clockSkewDetector {
+	return &clockSkewDetector{
+		stores: make(map[uint64]*storeClockSkew),
+	}
+}
+
+func clockSkewLevel(skew time.Duration) string {
+	if skew < 0 {
+		skew = -skew
+	}
+	switch {
+	case skew > clockSkewDangerThreshold:
+		return ClockSkewLevelDanger
+	case skew > clockSkewWarningThreshold:
+		return ClockSkewLevelWarning
+	default:
+		return ClockSkewLevelNormal
+	}
+}
+
+// observe updates the skew estimate of the store with the heartbeat received at now.
+func (d *clockSkewDetector) observe(store *core.StoreInfo, now time.Time) {
+	endTimestamp := store.GetStoreStats().GetInterval().GetEndTimestamp()
+	if endTimestamp == 0 {
+		return
+	}
+	d.Lock()
+	defer d.Unlock()
+	s, ok := d.stores[store.GetID()]
+	if !ok {
+		s = &storeClockSkew{
+			filter: movingaverage.NewMedianFilter(clockSkewFilterSize),
+			level:  ClockSkewLevelNormal,
+		}
+		d.stores[store.GetID()] = s
+	}
+	storeLabel := strconv.FormatUint(store.GetID(), 10)
+	if s.address != store.GetAddress() {
+		storeClockSkewGauge.DeleteLabelValues(s.address, storeLabel)
+		s.address = store.GetAddress()
+	}
+	s.updateTime = now
+	s.filter.Add(float64(int64(endTimestamp) - now.Unix()))
+	skew := time.Duration(s.filter.Get() * float64(time.Second))
+	storeClockSkewGauge.WithLabelValues(s.address, storeLabel).Set(skew.Seconds())
+	level := clockSkewLevel(skew)
+	if level == s.level {
+		return
+	}
+	if level == ClockSkewLevelNormal {
+		log.Info("store clock skew recovers",
+			zap.Uint64("store-id", store.GetID()), zap.String("address", s.address), zap.Duration("skew", skew))
+	} else {
+		log.Warn("store clock skew exceeds the threshold, the lease-based reads may be unsafe",
+			zap.Uint64("store-id", store.GetID()), zap.String("address", s.address),
+			zap.Duration("skew", skew), zap.String("level", level))
+	}
+	s.level = level
+}
+
+// forget stops tracking the physically deleted store.
+func (d *clockSkewDetector) forget(storeID uint64) {
+	d.Lock()
+	defer d.Unlock()
+	if s, ok := d.stores[storeID]; ok {
+		storeClockSkewGauge.DeleteLabelValues(s.address, strconv.FormatUint(storeID, 10))
+		delete(d.stores, storeID)
+	}
+}
+
+// getSkews returns the skew estimates of the stores ordered by the store ID.
+func (d *clockSkewDetector) getSkews() []*StoreClockSkew {
+	d.RLock()
+	defer d.RUnlock()
+	skews := make([]*StoreClockSkew, 0, len(d.stores))
+	for id, s := range d.stores {
+		skews = append(skews, &StoreClockSkew{
+			StoreID:    id,
+			Address:    s.address,
+			Skew:       typeutil.NewDuration(time.Duration(s.filter.Get() * float64(time.Second))),
+			Level:      s.level,
+			UpdateTime: s.updateTime,
+		})
+	}
+	sort.Slice(skews, func(i, j int) bool { return skews[i].StoreID < skews[j].StoreID })
+	return skews
+}
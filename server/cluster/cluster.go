@@ -51,6 +51,7 @@ import (
 	"github.com/tikv/pd/pkg/schedule/hbstream"
 	"github.com/tikv/pd/pkg/schedule/labeler"
 	"github.com/tikv/pd/pkg/schedule/placement"
+	"github.com/tikv/pd/pkg/schedule/schedulers"
 	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/pkg/statistics"
 	"github.com/tikv/pd/pkg/statistics/utils"
@@ -63,6 +64,7 @@ import (
 	"github.com/tikv/pd/pkg/utils/netutil"
 	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/tikv/pd/pkg/utils/typeutil"
+	"github.com/tikv/pd/pkg/validation"
 	"github.com/tikv/pd/pkg/versioninfo"
 	"github.com/tikv/pd/server/config"
 	"go.etcd.io/etcd/clientv3"
@@ -76,6 +78,7 @@ var (
 	// WithLabelValues is a heavy operation, define variable to avoid call it every time.
 	regionUpdateCacheEventCounter = regionEventCounter.WithLabelValues("update_cache")
 	regionUpdateKVEventCounter    = regionEventCounter.WithLabelValues("update_kv")
+	regionStatsUnsampledCounter   = regionEventCounter.WithLabelValues("stats_unsampled")
 	regionCacheMissCounter        = bucketEventCounter.WithLabelValues("region_cache_miss")
 	versionNotMatchCounter        = bucketEventCounter.WithLabelValues("version_not_match")
 	updateFailedCounter           = bucketEventCounter.WithLabelValues("update_failed")
@@ -154,6 +157,12 @@ type RaftCluster struct {
 	storage          storage.Storage
 	minResolvedTS    uint64
 	externalTS       uint64
+	stateEpoch       *stateEpoch
+	storeWatcher     *storeWatcher
+	clockSkew        *clockSkewDetector
+	regionJournal    *regionJournal
+	// degradedPlacement relaxes the placement rules when a zone is down.
+	degradedPlacement *degradedPlacement
 
 	// Keep the previous store limit settings when removing a store.
 	prevStoreLimit map[uint64]map[storelimit.Type]float64
@@ -174,6 +183,9 @@ type RaftCluster struct {
 	keyspaceGroupManager     *keyspace.GroupManager
 	independentServices      sync.Map
 	hbstreams                *hbstream.HeartbeatStreams
+	interceptors             *cluster.HeartbeatInterceptors
+	// validation validates the placement rule changes before they're applied.
+	validation *validation.Manager
 
 	// heartbeatRunner is used to process the subtree update task asynchronously.
 	heartbeatRunner ratelimit.Runner
@@ -191,6 +203,13 @@ type Status struct {
 	RaftBootstrapTime time.Time `json:"raft_bootstrap_time,omitempty"`
 	IsInitialized     bool      `json:"is_initialized"`
 	ReplicationStatus string    `json:"replication_status"`
+	// StateEpoch is bumped whenever the topology of the cluster changes.
+	StateEpoch uint64 `json:"state_epoch"`
+	// SchedulingProfile is the name of the active scheduling profile.
+	SchedulingProfile string `json:"scheduling_profile,omitempty"`
+	// SchedulingProfileModified is true if the schedule limits or the managed
+	// schedulers have been changed after the profile was applied.
+	SchedulingProfileModified bool `json:"scheduling_profile_modified,omitempty"`
 }
 
 // NewRaftCluster create a new cluster.
@@ -204,12 +223,18 @@ func NewRaftCluster(ctx context.Context, clusterID uint64, basicCluster *core.Ba
 		etcdClient:      etcdClient,
 		BasicCluster:    basicCluster,
 		storage:         storage,
+		interceptors:    cluster.NewHeartbeatInterceptors(),
 		heartbeatRunner: ratelimit.NewConcurrentRunner(heartbeatTaskRunner, ratelimit.NewConcurrencyLimiter(uint64(runtime.NumCPU()*2)), time.Minute),
 		miscRunner:      ratelimit.NewConcurrentRunner(miscTaskRunner, ratelimit.NewConcurrencyLimiter(uint64(runtime.NumCPU()*2)), time.Minute),
 		logRunner:       ratelimit.NewConcurrentRunner(logTaskRunner, ratelimit.NewConcurrencyLimiter(uint64(runtime.NumCPU()*2)), time.Minute),
 	}
 }
 
+// SetValidationManager sets the manager of the validators for the placement rule changes.
+func (c *RaftCluster) SetValidationManager(m *validation.Manager) {
+	c.validation = m
+}
+
 // GetStoreConfig returns the store config.
 func (c *RaftCluster) GetStoreConfig() sc.StoreConfigProvider {
 	return c.GetOpts()
@@ -244,13 +269,51 @@ func (c *RaftCluster) LoadClusterStatus() (*Status, error) {
 	if c.replicationMode != nil {
 		replicationStatus = c.replicationMode.GetReplicationStatus().String()
 	}
+	var stateEpoch uint64
+	if c.stateEpoch != nil {
+		stateEpoch = c.stateEpoch.get()
+	}
+	schedulingProfile, modified, err := c.GetSchedulingProfile()
+	if err != nil {
+		return nil, err
+	}
 	return &Status{
-		RaftBootstrapTime: bootstrapTime,
-		IsInitialized:     isInitialized,
-		ReplicationStatus: replicationStatus,
+		RaftBootstrapTime:         bootstrapTime,
+		IsInitialized:             isInitialized,
+		ReplicationStatus:         replicationStatus,
+		StateEpoch:                stateEpoch,
+		SchedulingProfile:         schedulingProfile,
+		SchedulingProfileModified: modified,
 	}, nil
 }
 
+// GetSchedulingProfile returns the name of the active scheduling profile, and
+// whether the schedule limits or the managed schedulers have diverged from it.
+func (c *RaftCluster) GetSchedulingProfile() (name string, modified bool, err error) {
+	name, err = c.storage.LoadSchedulingProfile()
+	if err != nil || len(name) == 0 {
+		return name, false, err
+	}
+	profile, ok := sc.GetSchedulingProfile(name)
+	if !ok || !profile.Matches(c.opt.GetScheduleConfig()) {
+		return name, true, nil
+	}
+	if c.schedulingController == nil {
+		return name, false, nil
+	}
+	co := c.GetCoordinator()
+	if co == nil || !co.AreSchedulersInitialized() {
+		return name, false, nil
+	}
+	for _, tp := range sc.GetProfileSchedulers() {
+		existed, _ := co.GetSchedulersController().IsSchedulerExisted(tp.String())
+		if existed != profile.IsSchedulerEnabled(tp) {
+			return name, true, nil
+		}
+	}
+	return name, false, nil
+}
+
 func (c *RaftCluster) isInitialized() bool {
 	if c.GetTotalRegionCount() > 1 {
 		return true
@@ -289,7 +352,16 @@ func (c *RaftCluster) InitCluster(
 	c.unsafeRecoveryController = unsaferecovery.NewController(c)
 	c.keyspaceGroupManager = keyspaceGroupManager
 	c.hbstreams = hbstreams
+	c.stateEpoch = newStateEpoch(c.storage)
+	c.storeWatcher = newStoreWatcher(c.storage)
+	c.clockSkew = newClockSkewDetector()
+	c.regionJournal = newRegionJournal()
 	c.ruleManager = placement.NewRuleManager(c.ctx, c.storage, c, c.GetOpts())
+	c.ruleManager.SetChangeCallback(func() { c.stateEpoch.bump(stateEpochRuleChange) })
+	c.ruleManager.SetValidateCallback(func(oldChange, newChange *placement.RulesChange) error {
+		return c.validation.Validate(validation.KindPlacementRules, oldChange, newChange)
+	})
+	c.degradedPlacement = newDegradedPlacement(c.storage, c.ruleManager)
 	if c.opt.IsPlacementRulesEnabled() {
 		err := c.ruleManager.Initialize(c.opt.GetMaxReplicas(), c.opt.GetLocationLabels(), c.opt.GetIsolationLevel())
 		if err != nil {
@@ -321,6 +393,18 @@ func (c *RaftCluster) Start(s Server) error {
 	if cluster == nil {
 		return nil
 	}
+	if err := c.stateEpoch.load(); err != nil {
+		return err
+	}
+	if err := c.storeWatcher.load(); err != nil {
+		return err
+	}
+	// The events may be written by the other leaders or the scheduling service
+	// since this server was the leader last time.
+	c.storage.ResetBoundedEventCounts()
+	if err := c.degradedPlacement.load(); err != nil {
+		return err
+	}
 
 	c.regionLabeler, err = labeler.NewRegionLabeler(c.ctx, c.storage, regionLabelGCInterval)
 	if err != nil {
@@ -352,7 +436,7 @@ func (c *RaftCluster) Start(s Server) error {
 		}
 	}
 	c.checkServices()
-	c.wg.Add(9)
+	c.wg.Add(12)
 	go c.runServiceCheckJob()
 	go c.runMetricsCollectionJob()
 	go c.runNodeStateCheckJob()
@@ -362,6 +446,9 @@ func (c *RaftCluster) Start(s Server) error {
 	go c.runStoreConfigSync()
 	go c.runUpdateStoreStats()
 	go c.startGCTuner()
+	go c.runTSOPrimaryColocationJob()
+	go c.runKeyspaceGroupAutoSplitJob()
+	go c.runTSOPrimaryBalanceJob()
 
 	c.running = true
 	c.heartbeatRunner.Start(c.ctx)
@@ -654,6 +741,9 @@ func (c *RaftCluster) LoadClusterInfo() (*RaftCluster, error) {
 		zap.Int("count", c.GetStoreCount()),
 		zap.Duration("cost", time.Since(start)),
 	)
+	if err := c.loadStoreReplacements(); err != nil {
+		return nil, err
+	}
 
 	start = time.Now()
 
@@ -711,6 +801,8 @@ func (c *RaftCluster) runNodeStateCheckJob() {
 			return
 		case <-ticker.C:
 			c.checkStores()
+			c.storeWatcher.observe(c.opt.GetMaxStoreDownTime(), c.GetStores()...)
+			c.degradedPlacement.check(c.opt.GetReplicationConfig(), c.GetStores())
 		}
 	}
 }
@@ -860,6 +952,11 @@ func (c *RaftCluster) IsSchedulingHalted() bool {
 	return c.opt.IsSchedulingHalted() || c.unsafeRecoveryController.IsRunning()
 }
 
+// GetHeartbeatInterceptors returns the heartbeat interceptors.
+func (c *RaftCluster) GetHeartbeatInterceptors() *cluster.HeartbeatInterceptors {
+	return c.interceptors
+}
+
 // GetUnsafeRecoveryController returns the unsafe recovery controller.
 func (c *RaftCluster) GetUnsafeRecoveryController() *unsaferecovery.Controller {
 	return c.unsafeRecoveryController
@@ -902,7 +999,6 @@ func (c *RaftCluster) HandleStoreHeartbeat(heartbeat *pdpb.StoreHeartbeatRequest
 	} else {
 		newStore = store.Clone(core.SetStoreStats(stats), core.SetLastHeartbeatTS(nowTime), opt)
 	}
-
 	if newStore.IsLowSpace(c.opt.GetLowSpaceRatio()) {
 		log.Warn("store does not have enough disk space",
 			zap.Uint64("store-id", storeID),
@@ -920,6 +1016,9 @@ func (c *RaftCluster) HandleStoreHeartbeat(heartbeat *pdpb.StoreHeartbeatRequest
 		statistics.UpdateStoreHeartbeatMetrics(store)
 	}
 	c.PutStore(newStore)
+	c.storeWatcher.observe(c.opt.GetMaxStoreDownTime(), newStore)
+	c.clockSkew.observe(newStore, nowTime)
+	c.recordSlowScoreTransition(store, newStore)
 	var (
 		regions  map[uint64]*core.RegionInfo
 		interval uint64
@@ -995,6 +1094,7 @@ func (c *RaftCluster) HandleStoreHeartbeat(heartbeat *pdpb.StoreHeartbeatRequest
 		}
 		c.hotStat.CheckReadAsync(collectUnReportedPeerTask)
 	}
+	c.interceptors.OnStoreHeartbeat(newStore, stats, resp)
 	return nil
 }
 
@@ -1041,8 +1141,13 @@ func (c *RaftCluster) processRegionHeartbeat(ctx *core.MetaProcessContext, regio
 
 	region.Inherit(origin, c.GetStoreConfig().IsEnableRegionBucket())
 
+	// Only the sampled heartbeats update the flow statistics.
+	sampled := cluster.SampleRegionStats(c.opt.GetRegionStatsSampleRatio())
+	if !sampled {
+		regionStatsUnsampledCounter.Inc()
+	}
 	if !c.IsServiceIndependent(mcsutils.SchedulingServiceName) {
-		cluster.HandleStatsAsync(c, region)
+		cluster.HandleStatsAsync(c, region, sampled)
 	}
 	tracer.OnAsyncHotStatsFinished()
 	hasRegionStats := c.regionStats != nil
@@ -1096,6 +1201,7 @@ func (c *RaftCluster) processRegionHeartbeat(ctx *core.MetaProcessContext, regio
 			tracer.OnSaveCacheFinished()
 			return err
 		}
+		c.regionJournal.recordMerge(region, origin, overlaps)
 		ctx.TaskRunner.RunTask(
 			regionID,
 			ratelimit.UpdateSubTree,
@@ -1255,11 +1361,20 @@ func (c *RaftCluster) putStoreImpl(store *metapb.Store, force bool) error {
 		}
 	}
 
-	s := c.GetStore(store.GetId())
+	old := c.GetStore(store.GetId())
+	s := old
 	if s == nil {
-		// Add a new store.
-		s = core.NewStoreInfo(store)
+		// Add a new store, the stores loaded from the storage are not new.
+		var opts []core.StoreCreateOption
+		if window := c.opt.GetStoreSlowStartWindow(); window > 0 {
+			log.Info("new store starts to ramp its weights", zap.Uint64("store-id", store.GetId()), zap.Duration("window", window))
+			opts = append(opts, core.SetSlowStart(time.Now(), window))
+		}
+		s = core.NewStoreInfo(store, opts...)
 	} else {
+		if err := checkStoreAddressChange(s, store); err != nil {
+			return err
+		}
 		// Use the given labels to update the store.
 		labels := store.GetLabels()
 		if !force {
@@ -1277,7 +1392,79 @@ func (c *RaftCluster) putStoreImpl(store *metapb.Store, force bool) error {
 	if err := c.checkStoreLabels(s); err != nil {
 		return err
 	}
-	return c.setStore(s)
+	if err := c.setStore(s); err != nil {
+		return err
+	}
+	if old != nil && old.GetAddress() != s.GetAddress() {
+		c.recordStoreAddressChange(old, s)
+	}
+	return nil
+}
+
+// checkStoreAddressChange checks whether the store can re-register with a changed
+// address. The address can only be changed by a restarted store process, otherwise
+// the process on the old address may still be serving with the same store ID.
+func checkStoreAddressChange(old *core.StoreInfo, store *metapb.Store) error {
+	if old.GetAddress() == store.GetAddress() {
+		return nil
+	}
+	if store.GetAddress() == "" {
+		return errs.ErrInvalidStoreAddressChange.FastGenByArgs(store.GetId(), "the new address is empty")
+	}
+	if old.IsRemoved() || old.IsPhysicallyDestroyed() {
+		return errs.ErrInvalidStoreAddressChange.FastGenByArgs(store.GetId(), "the store has been removed")
+	}
+	// The store which has never been started, such as the one put by the bootstrap,
+	// can't be checked by the start timestamp.
+	if startTS := old.GetMeta().GetStartTimestamp(); startTS > 0 && store.GetStartTimestamp() <= startTS {
+		return errs.ErrInvalidStoreAddressChange.FastGenByArgs(store.GetId(),
+			fmt.Sprintf("the store is not restarted, the old address %s may still be in use", old.GetAddress()))
+	}
+	return nil
+}
+
+// recordStoreAddressChange records the address change to the history of the store.
+func (c *RaftCluster) recordStoreAddressChange(old, store *core.StoreInfo) {
+	log.Warn("store address changed",
+		zap.Uint64("store-id", store.GetID()),
+		zap.String("old-address", old.GetAddress()),
+		zap.String("new-address", store.GetAddress()))
+	if c.storage == nil {
+		return
+	}
+	change := &endpoint.StoreAddressChange{
+		StoreID:        store.GetID(),
+		Time:           time.Now(),
+		OldAddress:     old.GetAddress(),
+		NewAddress:     store.GetAddress(),
+		StartTimestamp: store.GetMeta().GetStartTimestamp(),
+	}
+	if err := c.storage.SaveStoreAddressChange(change); err != nil {
+		log.Warn("failed to record the store address change",
+			zap.Uint64("store-id", change.StoreID), errs.ZapError(err))
+	}
+}
+
+// recordSlowScoreTransition records the event to the slow store timeline if the
+// store becomes slow or normal according to the slow score.
+func (c *RaftCluster) recordSlowScoreTransition(oldStore, newStore *core.StoreInfo) {
+	if c.storage == nil || oldStore.IsSlow() == newStore.IsSlow() {
+		return
+	}
+	event := &endpoint.SlowStoreEvent{
+		StoreID:   newStore.GetID(),
+		Time:      time.Now(),
+		Type:      endpoint.SlowStoreEventBecomeNormal,
+		SlowScore: newStore.GetSlowScore(),
+		Reason:    fmt.Sprintf("the slow score changes from %d to %d", oldStore.GetSlowScore(), newStore.GetSlowScore()),
+	}
+	if newStore.IsSlow() {
+		event.Type = endpoint.SlowStoreEventBecomeSlow
+	}
+	if err := c.storage.SaveSlowStoreEvent(event); err != nil {
+		log.Warn("failed to record the slow store event",
+			zap.Uint64("store-id", event.StoreID), zap.String("type", event.Type), errs.ZapError(err))
+	}
 }
 
 func (c *RaftCluster) checkStoreVersion(store *metapb.Store) error {
@@ -1316,6 +1503,14 @@ func (c *RaftCluster) checkStoreLabels(s *core.StoreInfo) error {
 			}
 		}
 	}
+	if err := sc.CheckLabelTaxonomy(c.opt.GetLabelTaxonomy(), s.GetLabels()); err != nil {
+		if c.opt.GetLabelTaxonomyAction() != sc.LabelTaxonomyQuarantine {
+			return errs.ErrInvalidStoreLabels.FastGenByArgs(s.GetID(), err.Error())
+		}
+		// The quarantined store is excluded from the schedule targets by the filters.
+		log.Warn("store is quarantined for violating the label taxonomy",
+			zap.Stringer("store", s.GetMeta()), errs.ZapError(err))
+	}
 	return nil
 }
 
@@ -1340,6 +1535,11 @@ func (c *RaftCluster) RemoveStore(storeID uint64, physicallyDestroyed bool) erro
 		if err := c.checkReplicaBeforeOfflineStore(storeID); err != nil {
 			return err
 		}
+		// Taking the store offline moves all its peers out, so it's refused like
+		// the other evictions when too many stores are evicting.
+		if err := schedulers.CheckEvictingStoreRatio(c.GetStores(), c.opt.GetMaxEvictingStoreRatio(), storeID); err != nil {
+			return err
+		}
 	}
 	newStore := store.Clone(core.SetStoreState(metapb.StoreState_Offline, physicallyDestroyed))
 	log.Warn("store has been offline",
@@ -1449,6 +1649,10 @@ func (c *RaftCluster) BuryStore(storeID uint64, forceBury bool) error {
 		if !c.IsServiceIndependent(mcsutils.SchedulingServiceName) {
 			c.removeStoreStatistics(storeID)
 		}
+		// the replacement is finished.
+		if store.GetReplacementTarget() != 0 {
+			c.removeStoreReplacement(storeID)
+		}
 	}
 	return err
 }
@@ -1522,6 +1726,10 @@ func (c *RaftCluster) UpStore(storeID uint64) error {
 			_ = c.SetStoreLimit(storeID, storelimit.RemovePeer, limiter[storelimit.RemovePeer])
 		}
 		c.resetProgress(storeID, store.GetAddress())
+		// the replacement is canceled.
+		if store.GetReplacementTarget() != 0 {
+			c.removeStoreReplacement(storeID)
+		}
 	}
 	return err
 }
@@ -1575,6 +1783,18 @@ func (c *RaftCluster) SetStoreWeight(storeID uint64, leaderWeight, regionWeight
 	return c.setStore(newStore)
 }
 
+// UpdateCompactionStats updates the compaction stats reported by the store. The
+// stats are also saved for the scheduling service to watch in API service mode.
+func (c *RaftCluster) UpdateCompactionStats(storeID uint64, stats *core.CompactionStats) error {
+	if err := c.BasicCluster.UpdateCompactionStats(storeID, stats); err != nil {
+		return err
+	}
+	if c.storage == nil || !c.isAPIServiceMode {
+		return nil
+	}
+	return c.storage.SaveStoreCompactionStats(storeID, stats)
+}
+
 func (c *RaftCluster) setStore(store *core.StoreInfo) error {
 	if c.storage != nil {
 		if err := c.storage.SaveStoreMeta(store.GetMeta()); err != nil {
@@ -1582,6 +1802,8 @@ func (c *RaftCluster) setStore(store *core.StoreInfo) error {
 		}
 	}
 	c.PutStore(store)
+	c.stateEpoch.bump(stateEpochStoreChange)
+	c.storeWatcher.observe(c.opt.GetMaxStoreDownTime(), store)
 	if !c.IsServiceIndependent(mcsutils.SchedulingServiceName) {
 		c.updateStoreStatistics(store.GetID(), store.IsSlow())
 	}
@@ -1719,7 +1941,7 @@ func (c *RaftCluster) calculateRange(stores []*core.StoreInfo, store *core.Store
 	var storeSize float64
 	rules := c.ruleManager.GetRulesForApplyRange(startKey, endKey)
 	for _, rule := range rules {
-		if !placement.MatchLabelConstraints(store, rule.LabelConstraints) {
+		if !rule.MatchStore(store) {
 			continue
 		}
 
@@ -1728,7 +1950,7 @@ func (c *RaftCluster) calculateRange(stores []*core.StoreInfo, store *core.Store
 			if s.IsRemoving() || s.IsRemoved() {
 				continue
 			}
-			if placement.MatchLabelConstraints(s, rule.LabelConstraints) {
+			if rule.MatchStore(s) {
 				matchStores = append(matchStores, s)
 			}
 		}
@@ -1938,8 +2160,14 @@ func (c *RaftCluster) deleteStore(store *core.StoreInfo) error {
 		if err := c.storage.DeleteStoreMeta(store.GetMeta()); err != nil {
 			return err
 		}
+		if err := c.storage.DeleteStoreCompactionStats(store.GetID()); err != nil {
+			return err
+		}
 	}
 	c.DeleteStore(store)
+	c.stateEpoch.bump(stateEpochStoreChange)
+	c.storeWatcher.forget(store.GetID())
+	c.clockSkew.forget(store.GetID())
 	return nil
 }
 
@@ -1956,6 +2184,8 @@ func (c *RaftCluster) collectHealthStatus() {
 	members, err := GetMembers(c.etcdClient)
 	if err != nil {
 		log.Error("get members error", errs.ZapError(err))
+	} else {
+		c.stateEpoch.observeMembers(members)
 	}
 	healthy := CheckHealth(c.httpClient, members)
 	for _, member := range members {
@@ -2260,6 +2490,39 @@ func (c *RaftCluster) GetExternalTS() uint64 {
 	return c.externalTS
 }
 
+// GetStateEpoch returns the cluster state epoch, which is bumped whenever the
+// members, the stores or the placement rules are changed.
+func (c *RaftCluster) GetStateEpoch() uint64 {
+	return c.stateEpoch.get()
+}
+
+// GetDegradedPlacementStatus returns the status of the degraded placement.
+func (c *RaftCluster) GetDegradedPlacementStatus() (*DegradedPlacementStatus, error) {
+	return c.degradedPlacement.getStatus()
+}
+
+// WatchStoreEvents returns the store lifecycle events after the given revision,
+// and a channel which is closed once there are newer events.
+func (c *RaftCluster) WatchStoreEvents(revision uint64) ([]*StoreEvent, <-chan struct{}, error) {
+	return c.storeWatcher.getEvents(revision)
+}
+
+// GetRegionEvents returns at most limit split and merge events overlapping with
+// the key range, from the newest to the oldest.
+func (c *RaftCluster) GetRegionEvents(startKey, endKey []byte, limit int) []*RegionEvent {
+	return c.regionJournal.query(startKey, endKey, limit)
+}
+
+// GetStoreClockSkews returns the estimated clock skews of the stores against PD.
+func (c *RaftCluster) GetStoreClockSkews() []*StoreClockSkew {
+	return c.clockSkew.getSkews()
+}
+
+// GetStoreEventRevision returns the revision of the latest store lifecycle event.
+func (c *RaftCluster) GetStoreEventRevision() uint64 {
+	return c.storeWatcher.getRevision()
+}
+
 // SetExternalTS sets the external timestamp.
 func (c *RaftCluster) SetExternalTS(timestamp uint64) error {
 	c.Lock()
@@ -0,0 +1,232 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	mcsutils "github.com/tikv/pd/pkg/mcs/utils"
+	"github.com/tikv/pd/pkg/statistics"
+	"github.com/tikv/pd/pkg/utils/tsoutil"
+)
+
+// The results of the cluster checks, from the best to the worst.
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// longRunningOperatorThreshold is the elapsed time after which a running
+// operator is reported by the cluster check.
+const longRunningOperatorThreshold = 10 * time.Minute
+
+// CheckItem is the result of one aspect of the cluster check.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type CheckItem struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// CheckReport is the summary of the cluster health, its status is the worst
+// status of the items.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type CheckReport struct {
+	Status string       `json:"status"`
+	Items  []*CheckItem `json:"items"`
+}
+
+func worseCheckStatus(a, b string) string {
+	rank := map[string]int{CheckPass: 0, CheckWarn: 1, CheckFail: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// Check aggregates the member health, the etcd alarms, the store states, the
+// running operators, the rule violations and the GC safe point staleness into
+// a single report. The GC safe point is stale if it's older than staleSafePoint.
+func (c *RaftCluster) Check(staleSafePoint time.Duration) *CheckReport {
+	items := []*CheckItem{
+		c.checkMemberHealth(),
+		c.checkEtcdAlarms(),
+		c.checkStoreStates(),
+		c.checkOperators(),
+		c.checkRuleViolations(),
+		c.checkGCSafePoint(staleSafePoint),
+	}
+	report := &CheckReport{Status: CheckPass}
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		report.Items = append(report.Items, item)
+		report.Status = worseCheckStatus(report.Status, item.Status)
+	}
+	return report
+}
+
+func (c *RaftCluster) checkMemberHealth() *CheckItem {
+	if c.etcdClient == nil {
+		return nil
+	}
+	item := &CheckItem{Name: "members", Status: CheckPass}
+	members, err := GetMembers(c.etcdClient)
+	if err != nil {
+		item.Status, item.Message = CheckFail, fmt.Sprintf("failed to get the members: %v", err)
+		return item
+	}
+	healthy := CheckHealth(c.httpClient, members)
+	var unhealthy []string
+	for _, member := range members {
+		if _, ok := healthy[member.GetMemberId()]; !ok {
+			unhealthy = append(unhealthy, member.GetName())
+		}
+	}
+	item.Message = fmt.Sprintf("%d/%d members are healthy", len(healthy), len(members))
+	if len(unhealthy) > 0 {
+		item.Status = CheckWarn
+		if len(healthy)*2 <= len(members) {
+			item.Status = CheckFail
+		}
+		item.Message += fmt.Sprintf(", unhealthy: %s", strings.Join(unhealthy, ", "))
+	}
+	return item
+}
+
+func (c *RaftCluster) checkEtcdAlarms() *CheckItem {
+	if c.etcdClient == nil {
+		return nil
+	}
+	item := &CheckItem{Name: "etcd-alarms", Status: CheckPass, Message: "no alarm"}
+	ctx, cancel := context.WithTimeout(c.ctx, clientTimeout)
+	defer cancel()
+	resp, err := c.etcdClient.AlarmList(ctx)
+	if err != nil {
+		item.Status, item.Message = CheckFail, fmt.Sprintf("failed to list the alarms: %v", err)
+		return item
+	}
+	if len(resp.Alarms) > 0 {
+		alarms := make([]string, 0, len(resp.Alarms))
+		for _, alarm := range resp.Alarms {
+			alarms = append(alarms, fmt.Sprintf("%s on member %x", alarm.GetAlarm(), alarm.GetMemberID()))
+		}
+		item.Status, item.Message = CheckFail, strings.Join(alarms, ", ")
+	}
+	return item
+}
+
+func (c *RaftCluster) checkStoreStates() *CheckItem {
+	item := &CheckItem{Name: "stores", Status: CheckPass}
+	counts := make(map[string]int)
+	for _, store := range c.GetStores() {
+		counts[storeLifecycleState(store, c.opt.GetMaxStoreDownTime())]++
+	}
+	item.Message = fmt.Sprintf("%d up, %d disconnected, %d down, %d offline",
+		counts[StoreStateUp], counts[StoreStateDisconnected], counts[StoreStateDown], counts[StoreStateOffline])
+	switch {
+	case counts[StoreStateUp] == 0:
+		item.Status = CheckFail
+	case counts[StoreStateDisconnected] > 0 || counts[StoreStateDown] > 0:
+		item.Status = CheckWarn
+	}
+	return item
+}
+
+func (c *RaftCluster) checkOperators() *CheckItem {
+	item := &CheckItem{Name: "operators", Status: CheckPass}
+	if c.IsServiceIndependent(mcsutils.SchedulingServiceName) {
+		item.Message = "checked by the scheduling service"
+		return item
+	}
+	oc := c.GetOperatorController()
+	running := oc.GetOperators()
+	var longRunning int
+	for _, op := range running {
+		if op.ElapsedTime() > longRunningOperatorThreshold {
+			longRunning++
+		}
+	}
+	item.Message = fmt.Sprintf("%d running, %d waiting", len(running), len(oc.GetWaitingOperators()))
+	if longRunning > 0 {
+		item.Status = CheckWarn
+		item.Message += fmt.Sprintf(", %d running longer than %s", longRunning, longRunningOperatorThreshold)
+	}
+	return item
+}
+
+func (c *RaftCluster) checkRuleViolations() *CheckItem {
+	item := &CheckItem{Name: "rule-violations", Status: CheckPass}
+	if c.IsServiceIndependent(mcsutils.SchedulingServiceName) {
+		item.Message = "checked by the scheduling service"
+		return item
+	}
+	var violations []string
+	for _, stat := range []struct {
+		typ  statistics.RegionStatisticType
+		name string
+	}{
+		{statistics.MissPeer, "miss-peer"},
+		{statistics.ExtraPeer, "extra-peer"},
+		{statistics.DownPeer, "down-peer"},
+		{statistics.OfflinePeer, "offline-peer"},
+	} {
+		if count := len(c.GetRegionStatsByType(stat.typ)); count > 0 {
+			violations = append(violations, fmt.Sprintf("%d %s regions", count, stat.name))
+		}
+	}
+	if len(violations) == 0 {
+		item.Message = "all regions satisfy the placement"
+		return item
+	}
+	item.Status, item.Message = CheckWarn, strings.Join(violations, ", ")
+	return item
+}
+
+func (c *RaftCluster) checkGCSafePoint(staleSafePoint time.Duration) *CheckItem {
+	item := &CheckItem{Name: "gc-safepoint", Status: CheckPass}
+	safePoint, err := c.storage.LoadGCSafePoint()
+	if err != nil {
+		item.Status, item.Message = CheckFail, fmt.Sprintf("failed to load the GC safe point: %v", err)
+		return item
+	}
+	if safePoint == 0 {
+		item.Message = "GC has not run yet"
+		return item
+	}
+	safePointTime, _ := tsoutil.ParseTS(safePoint)
+	lag := time.Since(safePointTime).Round(time.Second)
+	item.Message = fmt.Sprintf("the GC safe point is %s behind", lag)
+	if lag > staleSafePoint {
+		item.Status = CheckWarn
+		// the stale safe point is usually blocked by a service safe point.
+		ssps, err := c.storage.LoadAllServiceGCSafePoints()
+		if err == nil {
+			now := time.Now().Unix()
+			for _, ssp := range ssps {
+				if ssp.ExpiredAt > now && ssp.SafePoint <= safePoint {
+					item.Message += fmt.Sprintf(", blocked by the service %s", ssp.ServiceID)
+					break
+				}
+			}
+		}
+	}
+	return item
+}
@@ -40,6 +40,8 @@ import (
 	"github.com/tikv/pd/pkg/core/storelimit"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/id"
+	"github.com/tikv/pd/pkg/keyspace"
+	mcsutils "github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/mock/mockhbstream"
 	"github.com/tikv/pd/pkg/mock/mockid"
 	"github.com/tikv/pd/pkg/progress"
@@ -56,6 +58,7 @@ import (
 	"github.com/tikv/pd/pkg/statistics"
 	"github.com/tikv/pd/pkg/statistics/utils"
 	"github.com/tikv/pd/pkg/storage"
+	"github.com/tikv/pd/pkg/storage/endpoint"
 	"github.com/tikv/pd/pkg/utils/logutil"
 	"github.com/tikv/pd/pkg/utils/operatorutil"
 	"github.com/tikv/pd/pkg/utils/testutil"
@@ -198,6 +201,49 @@ func TestStoreHeartbeat(t *testing.T) {
 	re.Equal(uint64(1), storeStats[1][0].RegionID)
 }
 
+func TestStoreSlowStart(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	opt.GetScheduleConfig().StoreSlowStartWindow = typeutil.NewDuration(time.Hour)
+	s := storage.NewStorageWithMemoryBackend()
+	re.NoError(s.SaveMeta(&metapb.Cluster{Id: 1, MaxPeerCount: 3}))
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, s)
+
+	stores := newTestStores(2, "2.0.0")
+	re.NoError(cluster.PutMetaStore(stores[0].GetMeta()))
+	// The new registered store starts to ramp its weights.
+	re.True(cluster.GetStore(1).IsSlowStarting())
+	req := &pdpb.StoreHeartbeatRequest{Stats: &pdpb.StoreStats{StoreId: 1, Capacity: 100, Available: 50}}
+	re.NoError(cluster.HandleStoreHeartbeat(req, &pdpb.StoreHeartbeatResponse{}))
+	re.True(cluster.GetStore(1).IsSlowStarting())
+	// The store registers again after it restarts.
+	re.NoError(cluster.PutMetaStore(stores[0].GetMeta()))
+	re.True(cluster.GetStore(1).IsSlowStarting())
+
+	// Reload the cluster from the storage as the new leader does, the store 2
+	// has never sent a heartbeat.
+	re.NoError(cluster.PutMetaStore(stores[1].GetMeta()))
+	cluster = newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, s)
+	rc, err := cluster.LoadClusterInfo()
+	re.NoError(err)
+	re.NotNil(rc)
+	for _, store := range stores {
+		storeID := store.GetID()
+		re.False(cluster.GetStore(storeID).IsSlowStarting())
+		req := &pdpb.StoreHeartbeatRequest{Stats: &pdpb.StoreStats{StoreId: storeID, Capacity: 100, Available: 50}}
+		re.NoError(cluster.HandleStoreHeartbeat(req, &pdpb.StoreHeartbeatResponse{}))
+		re.False(cluster.GetStore(storeID).IsSlowStarting())
+	}
+	// Only the store registered after the reload is new.
+	newStore := newTestStores(3, "2.0.0")[2]
+	re.NoError(cluster.PutMetaStore(newStore.GetMeta()))
+	re.True(cluster.GetStore(newStore.GetID()).IsSlowStarting())
+}
+
 func TestFilterUnhealthyStore(t *testing.T) {
 	re := require.New(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -236,6 +282,174 @@ func TestFilterUnhealthyStore(t *testing.T) {
 	}
 }
 
+func TestStoreAddressChange(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend())
+	cluster.coordinator = schedule.NewCoordinator(ctx, cluster, nil)
+	stores := newTestStores(2, "2.0.0")
+	for _, store := range stores {
+		meta := typeutil.DeepClone(store.GetMeta(), core.StoreFactory)
+		meta.StartTimestamp = time.Now().Unix()
+		re.NoError(cluster.PutMetaStore(meta))
+	}
+
+	// the address can't be changed by the same store process.
+	meta := typeutil.DeepClone(cluster.GetStore(1).GetMeta(), core.StoreFactory)
+	meta.Address = "127.0.0.1:30001"
+	re.ErrorContains(cluster.PutMetaStore(meta), "not restarted")
+	// the address can be changed by a restarted store process.
+	meta.StartTimestamp++
+	re.NoError(cluster.PutMetaStore(meta))
+	re.Equal(meta.Address, cluster.GetStore(1).GetAddress())
+	// the address of the other store can't be used.
+	meta.Address = stores[1].GetAddress()
+	meta.StartTimestamp++
+	re.ErrorContains(cluster.PutMetaStore(meta), "duplicated store address")
+
+	changes, err := cluster.GetStorage().LoadStoreAddressChanges(1)
+	re.NoError(err)
+	re.Len(changes, 1)
+	re.Equal(stores[0].GetAddress(), changes[0].OldAddress)
+	re.Equal("127.0.0.1:30001", changes[0].NewAddress)
+	changes, err = cluster.GetStorage().LoadStoreAddressChanges(2)
+	re.NoError(err)
+	re.Empty(changes)
+}
+
+func TestStoreReplacement(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend())
+	cluster.coordinator = schedule.NewCoordinator(ctx, cluster, nil)
+	for _, store := range newTestStores(4, "2.0.0") {
+		re.NoError(cluster.PutMetaStore(store.GetMeta()))
+	}
+
+	re.ErrorContains(cluster.ReplaceStore(1, 1), "the same one")
+	re.ErrorContains(cluster.ReplaceStore(1, 5), "not found")
+	// the independent scheduling service doesn't support the replacement.
+	cluster.independentServices.Store(mcsutils.SchedulingServiceName, true)
+	re.ErrorContains(cluster.ReplaceStore(1, 4), "not supported")
+	re.False(cluster.GetStore(1).IsRemoving())
+	cluster.independentServices.Delete(mcsutils.SchedulingServiceName)
+	re.NoError(cluster.ReplaceStore(1, 4))
+	re.True(cluster.GetStore(1).IsRemoving())
+	re.Equal(uint64(4), cluster.GetStore(1).GetReplacementTarget())
+	// the stores in a replacement can't be replaced again.
+	re.ErrorContains(cluster.ReplaceStore(1, 3), "removed")
+	re.ErrorContains(cluster.ReplaceStore(4, 3), "is replacing")
+	re.ErrorContains(cluster.ReplaceStore(2, 4), "is replacing")
+	replacements, err := cluster.GetStoreReplacements()
+	re.NoError(err)
+	re.Len(replacements, 1)
+	re.Equal(uint64(1), replacements[0].OldStoreID)
+	re.Equal(uint64(4), replacements[0].NewStoreID)
+
+	// the replacement is restored after reloading.
+	cluster.PutStore(cluster.GetStore(1).Clone(core.SetReplacementTarget(0)))
+	re.NoError(cluster.loadStoreReplacements())
+	re.Equal(uint64(4), cluster.GetStore(1).GetReplacementTarget())
+
+	// the replacement is canceled by setting the store up.
+	re.NoError(cluster.UpStore(1))
+	re.Zero(cluster.GetStore(1).GetReplacementTarget())
+	replacements, err = cluster.GetStoreReplacements()
+	re.NoError(err)
+	re.Empty(replacements)
+}
+
+func TestStoreLabelTaxonomy(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	cfg := opt.GetReplicationConfig().Clone()
+	cfg.LabelTaxonomy = []sc.StoreLabelConstraint{{Key: "zone", Required: true, Values: []string{"z1", "z2"}}}
+	opt.SetReplicationConfig(cfg)
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend())
+	stores := newTestStores(2, "2.0.0")
+	withZone := func(store *core.StoreInfo, zone string) *metapb.Store {
+		meta := typeutil.DeepClone(store.GetMeta(), core.StoreFactory)
+		if zone != "" {
+			meta.Labels = []*metapb.StoreLabel{{Key: "zone", Value: zone}, {Key: "host", Value: "h1"}}
+		}
+		return meta
+	}
+
+	re.NoError(cluster.PutMetaStore(withZone(stores[0], "z1")))
+	re.ErrorContains(cluster.PutMetaStore(withZone(stores[1], "")), "is required")
+	re.ErrorContains(cluster.PutMetaStore(withZone(stores[1], "z3")), "not allowed")
+	re.Nil(cluster.GetStore(2))
+	// the label updates are checked too.
+	re.ErrorContains(cluster.UpdateStoreLabels(1, []*metapb.StoreLabel{{Key: "zone", Value: "z9"}}, false), "not allowed")
+	re.ErrorContains(cluster.DeleteStoreLabel(1, "zone"), "is required")
+	re.NoError(cluster.DeleteStoreLabel(1, "host"))
+	re.Equal("z1", cluster.GetStore(1).GetLabelValue("zone"))
+
+	// the stores violating the taxonomy are accepted when they are quarantined.
+	cfg.LabelTaxonomyAction = sc.LabelTaxonomyQuarantine
+	opt.SetReplicationConfig(cfg)
+	re.NoError(cluster.PutMetaStore(withZone(stores[1], "z3")))
+	re.Equal("z3", cluster.GetStore(2).GetLabelValue("zone"))
+}
+
+func TestLeaderMajorityLabel(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend())
+	zones := []string{"z1", "z2", "z2", ""}
+	for i, store := range newTestStores(4, "2.0.0") {
+		meta := store.GetMeta()
+		if zones[i] != "" {
+			meta.Labels = []*metapb.StoreLabel{{Key: "zone", Value: zones[i]}}
+		}
+		re.NoError(cluster.PutMetaStore(meta))
+	}
+	putRegion := func(id uint64, startKey, endKey []byte, leaderStoreID uint64) {
+		leader := &metapb.Peer{Id: id + 100, StoreId: leaderStoreID}
+		region := core.NewRegionInfo(&metapb.Region{
+			Id:          id,
+			StartKey:    startKey,
+			EndKey:      endKey,
+			Peers:       []*metapb.Peer{leader},
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+		}, leader)
+		re.NoError(cluster.putRegion(region))
+	}
+	// keyspace 1 has two leaders in z2 and one in z1.
+	bound := keyspace.MakeRegionBound(1)
+	putRegion(1, bound.RawLeftBound, bound.RawRightBound, 1)
+	putRegion(2, bound.TxnLeftBound, append(bound.TxnLeftBound, 'a'), 2)
+	putRegion(3, append(bound.TxnLeftBound, 'a'), bound.TxnRightBound, 3)
+	// keyspace 2 has two leaders in z1 and one on the store without the label.
+	bound = keyspace.MakeRegionBound(2)
+	putRegion(4, bound.RawLeftBound, bound.RawRightBound, 1)
+	putRegion(5, bound.TxnLeftBound, append(bound.TxnLeftBound, 'a'), 1)
+	putRegion(6, append(bound.TxnLeftBound, 'a'), bound.TxnRightBound, 4)
+
+	re.Equal("z2", cluster.getLeaderMajorityLabel([]uint32{1}, "zone"))
+	re.Equal("z1", cluster.getLeaderMajorityLabel([]uint32{2}, "zone"))
+	// the leaders of all the keyspaces are counted together.
+	re.Equal("z1", cluster.getLeaderMajorityLabel([]uint32{1, 2}, "zone"))
+	re.Empty(cluster.getLeaderMajorityLabel([]uint32{3}, "zone"))
+	re.Empty(cluster.getLeaderMajorityLabel([]uint32{1}, "host"))
+}
+
 func TestSetOfflineStore(t *testing.T) {
 	re := require.New(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -326,6 +540,39 @@ func TestSetOfflineWithReplica(t *testing.T) {
 	re.NoError(cluster.RemoveStore(3, true))
 }
 
+func TestSetOfflineWithEvictingStores(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	cfg := opt.GetScheduleConfig().Clone()
+	cfg.MaxEvictingStoreRatio = 0.2
+	opt.SetScheduleConfig(cfg)
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend())
+	cluster.coordinator = schedule.NewCoordinator(ctx, cluster, nil)
+
+	// Put 6 stores.
+	for _, store := range newTestStores(6, "2.0.0") {
+		re.NoError(cluster.PutMetaStore(store.GetMeta()))
+	}
+
+	re.NoError(cluster.RemoveStore(2, false))
+	re.NoError(cluster.PauseLeaderTransfer(3))
+	// should be refused since 2 of 6 stores are evicting or offline.
+	err = cluster.RemoveStore(4, false)
+	re.Contains(err.Error(), string(errs.ErrSchedulerEvictionRefused.RFCCode()))
+	re.True(cluster.GetStore(4).IsServing())
+	// should be success since physically-destroyed is true.
+	re.NoError(cluster.RemoveStore(4, true))
+	cluster.ResumeLeaderTransfer(3)
+	re.Error(cluster.RemoveStore(5, false))
+	cfg.MaxEvictingStoreRatio = 0.5
+	opt.SetScheduleConfig(cfg)
+	re.NoError(cluster.RemoveStore(5, false))
+}
+
 func addEvictLeaderScheduler(cluster *RaftCluster, storeID uint64) (evictScheduler schedulers.Scheduler, err error) {
 	args := []string{fmt.Sprintf("%d", storeID)}
 	evictScheduler, err = schedulers.CreateScheduler(schedulers.EvictLeaderType, cluster.GetOperatorController(), cluster.storage, schedulers.ConfigSliceDecoder(schedulers.EvictLeaderType, args), cluster.GetCoordinator().GetSchedulersController().RemoveScheduler)
@@ -365,6 +612,11 @@ func TestSetOfflineStoreWithEvictLeader(t *testing.T) {
 	re.Error(err)
 	re.Contains(err.Error(), string(errs.ErrNoStoreForRegionLeader.RFCCode()))
 	re.NoError(cluster.RemoveScheduler(schedulers.EvictLeaderName))
+	// the stopped scheduler resumes the leader transfer asynchronously, and
+	// store 1 is counted as evicting until then.
+	testutil.Eventually(re, func() bool {
+		return cluster.GetStore(1).AllowLeaderTransfer()
+	})
 	re.NoError(cluster.RemoveStore(3, false))
 }
 
@@ -658,6 +910,57 @@ func TestRegionHeartbeatHotStat(t *testing.T) {
 	re.Len(stats[4], 1)
 }
 
+func TestRegionStatsSampling(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend())
+	cluster.coordinator = schedule.NewCoordinator(ctx, cluster, nil)
+	for _, store := range newTestStores(3, "2.0.0") {
+		re.NoError(cluster.setStore(store))
+	}
+	setSampleRatio := func(ratio float64) {
+		cfg := opt.GetScheduleConfig().Clone()
+		cfg.RegionStatsSampleRatio = ratio
+		opt.SetScheduleConfig(cfg)
+	}
+	setSampleRatio(1e-9)
+
+	peers := []*metapb.Peer{{Id: 1, StoreId: 1}, {Id: 2, StoreId: 2}, {Id: 3, StoreId: 3}}
+	region := core.NewRegionInfo(&metapb.Region{
+		Id:          1,
+		Peers:       peers,
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 2, Version: 2},
+	}, peers[0], core.WithInterval(&pdpb.TimeInterval{StartTimestamp: 0, EndTimestamp: utils.RegionHeartBeatReportInterval}),
+		core.SetWrittenBytes(30000*10),
+		core.SetWrittenKeys(300000*10))
+	// the meta is always updated, but the flow statistics are not.
+	re.NoError(cluster.processRegionHeartbeat(core.ContextTODO(), region))
+	re.NotNil(cluster.GetRegion(1))
+	// the pending and down peers are always collected.
+	region = region.Clone(core.WithPendingPeers(peers[2:]),
+		core.WithDownPeers([]*pdpb.PeerStats{{Peer: peers[1], DownSeconds: 100}}))
+	re.NoError(cluster.processRegionHeartbeat(core.ContextTODO(), region))
+	re.Len(cluster.GetRegion(1).GetPendingPeers(), 1)
+	time.Sleep(time.Second)
+	re.Empty(cluster.hotStat.RegionStats(utils.Write, 0)[1])
+	re.Len(cluster.GetRegionStatsByType(statistics.PendingPeer), 1)
+	re.Len(cluster.GetRegionStatsByType(statistics.DownPeer), 1)
+	// the label statistics are not updated by the unsampled regions.
+	cluster.UpdateRegionsLabelLevelStats([]*core.RegionInfo{region})
+	re.Empty(cluster.labelStats.GetLabelCounter())
+
+	setSampleRatio(1)
+	re.NoError(cluster.processRegionHeartbeat(core.ContextTODO(), region))
+	time.Sleep(time.Second)
+	re.Len(cluster.hotStat.RegionStats(utils.Write, 0)[1], 1)
+	cluster.UpdateRegionsLabelLevelStats([]*core.RegionInfo{region})
+	re.NotEmpty(cluster.labelStats.GetLabelCounter())
+}
+
 func TestBucketHeartbeat(t *testing.T) {
 	re := require.New(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1804,6 +2107,379 @@ func TestCalculateStoreSize2(t *testing.T) {
 	re.Equal(3000.0, cluster.getThreshold(stores, store))
 }
 
+func TestStateEpoch(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	s := storage.NewStorageWithMemoryBackend()
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, s)
+	cluster.ruleManager.SetChangeCallback(func() { cluster.stateEpoch.bump(stateEpochRuleChange) })
+	// the epoch is bumped once the cluster is started.
+	re.NoError(cluster.stateEpoch.load())
+	re.Equal(uint64(1), cluster.GetStateEpoch())
+
+	// store changes
+	store := newTestStores(1, "2.0.0")[0]
+	re.NoError(cluster.PutMetaStore(store.GetMeta()))
+	re.Equal(uint64(2), cluster.GetStateEpoch())
+	re.NoError(cluster.RemoveStore(store.GetID(), true))
+	re.Equal(uint64(3), cluster.GetStateEpoch())
+
+	// rule changes
+	rule := &placement.Rule{GroupID: placement.DefaultGroupID, ID: "test", Role: placement.Voter, Count: 1}
+	re.NoError(cluster.ruleManager.SetRule(rule))
+	re.Equal(uint64(4), cluster.GetStateEpoch())
+	re.NoError(cluster.ruleManager.SetRule(rule.Clone()))
+	re.Equal(uint64(4), cluster.GetStateEpoch())
+
+	// member changes
+	members := []*pdpb.Member{{MemberId: 1}, {MemberId: 2}}
+	cluster.stateEpoch.observeMembers(members)
+	cluster.stateEpoch.observeMembers(members)
+	re.Equal(uint64(4), cluster.GetStateEpoch())
+	cluster.stateEpoch.observeMembers(append(members, &pdpb.Member{MemberId: 3}))
+	re.Equal(uint64(5), cluster.GetStateEpoch())
+	cluster.stateEpoch.observeMembers(members[1:])
+	re.Equal(uint64(6), cluster.GetStateEpoch())
+
+	// the epochs are reserved by windows, and the epoch never goes backwards after the leader changes.
+	limit, err := s.LoadClusterStateEpoch()
+	re.NoError(err)
+	re.Equal(stateEpochStep, limit)
+	epoch := newStateEpoch(s)
+	re.NoError(epoch.load())
+	re.Equal(stateEpochStep+1, epoch.get())
+
+	// the epoch isn't advanced if the window can't be reserved.
+	failStorage := &mockStateEpochStorage{}
+	epoch = newStateEpoch(failStorage)
+	re.NoError(epoch.load())
+	for i := uint64(1); i < stateEpochStep; i++ {
+		epoch.bump(stateEpochStoreChange)
+	}
+	re.Equal(stateEpochStep, epoch.get())
+	re.Equal(1, failStorage.saveCount)
+	failStorage.err = errors.New("injected")
+	epoch.bump(stateEpochStoreChange)
+	re.Equal(stateEpochStep, epoch.get())
+	failStorage.err = nil
+	epoch.bump(stateEpochStoreChange)
+	re.Equal(stateEpochStep+1, epoch.get())
+	re.Equal(2, failStorage.saveCount)
+	newEpoch := newStateEpoch(failStorage)
+	failStorage.err = errors.New("injected")
+	re.Error(newEpoch.load())
+	re.Equal(failStorage.limit, newEpoch.get())
+}
+
+type mockStateEpochStorage struct {
+	limit     uint64
+	saveCount int
+	err       error
+}
+
+func (s *mockStateEpochStorage) LoadClusterStateEpoch() (uint64, error) {
+	return s.limit, nil
+}
+
+func (s *mockStateEpochStorage) SaveClusterStateEpoch(limit uint64) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.limit = limit
+	s.saveCount++
+	return nil
+}
+
+func TestStoreWatcher(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend())
+	states := func(events []*StoreEvent) []string {
+		var states []string
+		for _, event := range events {
+			states = append(states, event.PrevState+"->"+event.State)
+		}
+		return states
+	}
+
+	store := newTestStores(1, "2.0.0")[0].Clone(core.SetLastHeartbeatTS(time.Now()))
+	re.NoError(cluster.setStore(store))
+	events, notify, err := cluster.WatchStoreEvents(0)
+	re.NoError(err)
+	re.Equal([]string{"->up"}, states(events))
+	re.Equal(uint64(1), cluster.GetStoreEventRevision())
+
+	// the disconnected and down stores are observed by the check job.
+	cluster.PutStore(store.Clone(core.SetLastHeartbeatTS(time.Now().Add(-time.Minute))))
+	cluster.storeWatcher.observe(opt.GetMaxStoreDownTime(), cluster.GetStores()...)
+	cluster.storeWatcher.observe(opt.GetMaxStoreDownTime(), cluster.GetStores()...)
+	cluster.PutStore(store.Clone(core.SetLastHeartbeatTS(time.Now().Add(-opt.GetMaxStoreDownTime() - time.Minute))))
+	cluster.storeWatcher.observe(opt.GetMaxStoreDownTime(), cluster.GetStores()...)
+	re.NoError(cluster.RemoveStore(store.GetID(), true))
+	re.NoError(cluster.BuryStore(store.GetID(), true))
+	select {
+	case <-notify:
+	default:
+		re.FailNow("the watcher is not notified")
+	}
+	events, _, err = cluster.WatchStoreEvents(1)
+	re.NoError(err)
+	re.Equal([]string{"up->disconnected", "disconnected->down", "down->offline", "offline->tombstone"}, states(events))
+	re.Equal(uint64(5), events[3].Revision)
+
+	// the revisions out of the range are unavailable.
+	_, _, err = cluster.WatchStoreEvents(6)
+	re.ErrorIs(err, errs.ErrStoreEventRevisionUnavailable)
+	for i := 0; i < maxStoreEvents; i++ {
+		lastHeartbeat := time.Now()
+		if i%2 == 0 {
+			lastHeartbeat = lastHeartbeat.Add(-time.Minute)
+		}
+		cluster.storeWatcher.observe(opt.GetMaxStoreDownTime(), store.Clone(core.SetLastHeartbeatTS(lastHeartbeat)))
+	}
+	_, _, err = cluster.WatchStoreEvents(1)
+	re.ErrorIs(err, errs.ErrStoreEventRevisionUnavailable)
+	events, _, err = cluster.WatchStoreEvents(cluster.GetStoreEventRevision() - 1)
+	re.NoError(err)
+	re.Len(events, 1)
+
+	// the revision is continued after the leader changes.
+	revision := cluster.GetStoreEventRevision()
+	watcher := newStoreWatcher(cluster.storage)
+	re.NoError(watcher.load())
+	re.Equal(revision, watcher.getRevision())
+	_, _, err = watcher.getEvents(revision - 1)
+	re.ErrorIs(err, errs.ErrStoreEventRevisionUnavailable)
+	watcher.observe(opt.GetMaxStoreDownTime(), store)
+	events, _, err = watcher.getEvents(revision)
+	re.NoError(err)
+	re.Len(events, 1)
+	re.Equal(revision+1, events[0].Revision)
+}
+
+func TestStoreClockSkew(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend())
+	stores := newTestStores(2, "2.0.0")
+	for _, store := range stores {
+		re.NoError(cluster.setStore(store))
+	}
+	heartbeat := func(storeID uint64, offset time.Duration) {
+		end := uint64(time.Now().Add(offset).Unix())
+		req := &pdpb.StoreHeartbeatRequest{
+			Stats: &pdpb.StoreStats{
+				StoreId:  storeID,
+				Interval: &pdpb.TimeInterval{StartTimestamp: end - 10, EndTimestamp: end},
+			},
+		}
+		re.NoError(cluster.HandleStoreHeartbeat(req, &pdpb.StoreHeartbeatResponse{}))
+	}
+	levels := func() []string {
+		var levels []string
+		for _, skew := range cluster.GetStoreClockSkews() {
+			levels = append(levels, skew.Level)
+		}
+		return levels
+	}
+
+	for i := 0; i < clockSkewFilterSize; i++ {
+		heartbeat(1, 0)
+		heartbeat(2, -10*time.Second)
+	}
+	re.Equal([]string{ClockSkewLevelNormal, ClockSkewLevelDanger}, levels())
+	skews := cluster.GetStoreClockSkews()
+	re.LessOrEqual(skews[1].Skew.Duration, -9*time.Second)
+
+	// the skew is estimated by the median of the recent heartbeats.
+	for i := 0; i < clockSkewFilterSize/2; i++ {
+		heartbeat(1, 3*time.Second)
+		heartbeat(2, 0)
+	}
+	re.Equal([]string{ClockSkewLevelNormal, ClockSkewLevelDanger}, levels())
+	heartbeat(1, 3*time.Second)
+	heartbeat(2, 0)
+	re.Equal([]string{ClockSkewLevelWarning, ClockSkewLevelNormal}, levels())
+
+	// the deleted stores are not tracked any more.
+	re.NoError(cluster.RemoveStore(2, true))
+	re.NoError(cluster.BuryStore(2, true))
+	re.NoError(cluster.deleteStore(cluster.GetStore(2)))
+	re.Len(cluster.GetStoreClockSkews(), 1)
+}
+
+func TestRegionJournal(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, storage.NewStorageWithMemoryBackend())
+	cluster.coordinator = schedule.NewCoordinator(ctx, cluster, nil)
+	newRegion := func(id uint64, startKey, endKey string, version uint64) *metapb.Region {
+		return &metapb.Region{
+			Id:          id,
+			StartKey:    []byte(startKey),
+			EndKey:      []byte(endKey),
+			RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: version},
+			Peers:       []*metapb.Peer{{Id: id + 100, StoreId: 1}},
+		}
+	}
+	heartbeat := func(region *metapb.Region) {
+		re.NoError(cluster.processRegionHeartbeat(core.ContextTODO(), core.NewRegionInfo(region, region.Peers[0])))
+	}
+	types := func(events []*RegionEvent) []string {
+		var types []string
+		for _, event := range events {
+			types = append(types, event.Type)
+		}
+		return types
+	}
+
+	// region 1 [a, c) splits into region 2 [a, b) and region 1 [b, c).
+	heartbeat(newRegion(1, "a", "c", 1))
+	_, err = cluster.HandleBatchReportSplit(&pdpb.ReportBatchSplitRequest{
+		Regions: []*metapb.Region{newRegion(2, "a", "b", 2), newRegion(1, "b", "c", 2)},
+	})
+	re.NoError(err)
+	heartbeat(newRegion(2, "a", "b", 2))
+	heartbeat(newRegion(1, "b", "c", 2))
+	// region 3 [c, d) is merged into region 1.
+	heartbeat(newRegion(3, "c", "d", 1))
+	heartbeat(newRegion(1, "b", "d", 3))
+
+	events := cluster.GetRegionEvents(nil, nil, 10)
+	re.Equal([]string{RegionEventMerge, RegionEventSplit}, types(events))
+	re.Equal([]uint64{1, 3}, events[0].SourceRegionIDs)
+	re.Equal([]uint64{1}, events[0].RegionIDs)
+	re.Equal(core.HexRegionKeyStr([]byte("b")), events[0].StartKey)
+	re.Equal([]uint64{1}, events[1].SourceRegionIDs)
+	re.Equal([]uint64{2, 1}, events[1].RegionIDs)
+
+	// the events are filtered by the key range.
+	re.Equal([]string{RegionEventSplit}, types(cluster.GetRegionEvents([]byte("a"), []byte("b"), 10)))
+	re.Equal([]string{RegionEventMerge, RegionEventSplit}, types(cluster.GetRegionEvents([]byte("b"), []byte("c"), 10)))
+	re.Equal([]string{RegionEventMerge}, types(cluster.GetRegionEvents([]byte("c"), nil, 10)))
+	re.Empty(cluster.GetRegionEvents([]byte("d"), nil, 10))
+	re.Len(cluster.GetRegionEvents(nil, nil, 1), 1)
+
+	// the journal is bounded.
+	for i := 0; i < maxRegionEvents; i++ {
+		cluster.regionJournal.recordSplit([]*metapb.Region{newRegion(4, "x", "y", 1), newRegion(5, "y", "z", 1)})
+	}
+	re.Empty(cluster.GetRegionEvents([]byte("a"), []byte("d"), 10))
+}
+
+func TestDegradedPlacement(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, opt, err := newTestScheduleConfig()
+	re.NoError(err)
+	s := storage.NewStorageWithMemoryBackend()
+	cluster := newTestRaftCluster(ctx, mockid.NewIDAllocator(), opt, s)
+	rm := cluster.ruleManager
+	re.NoError(rm.SetRule(&placement.Rule{
+		GroupID: placement.DefaultGroupID, ID: placement.DefaultRuleID, Role: placement.Voter, Count: 3,
+		LocationLabels: []string{"zone", "host"}, IsolationLevel: "zone",
+	}))
+	d := newDegradedPlacement(s, rm)
+	re.NoError(d.load())
+	d.startTime = time.Now().Add(-time.Hour)
+
+	cfg := opt.GetReplicationConfig().Clone()
+	cfg.EnableDegradedPlacement = true
+	cfg.DegradedZoneDownTime = typeutil.NewDuration(time.Minute)
+	newStores := func(downZones ...string) []*core.StoreInfo {
+		var stores []*core.StoreInfo
+		for i, zone := range []string{"z1", "z1", "z2", "z3"} {
+			heartbeat := time.Now()
+			if slice.Contains(downZones, zone) {
+				heartbeat = heartbeat.Add(-time.Hour)
+			}
+			stores = append(stores, core.NewStoreInfo(&metapb.Store{
+				Id:     uint64(i + 1),
+				Labels: []*metapb.StoreLabel{{Key: "zone", Value: zone}},
+			}, core.SetLastHeartbeatTS(heartbeat)))
+		}
+		return stores
+	}
+
+	// nothing changes if all zones are up or down.
+	d.check(cfg, newStores())
+	d.check(cfg, newStores("z1", "z2", "z3"))
+	status, err := d.getStatus()
+	re.NoError(err)
+	re.False(status.Degraded)
+	re.Empty(status.Events)
+
+	// the isolation level is relaxed when a zone is down.
+	d.check(cfg, newStores("z1"))
+	re.Empty(rm.GetRule(placement.DefaultGroupID, placement.DefaultRuleID).IsolationLevel)
+	d.check(cfg, newStores("z1"))
+	status, err = d.getStatus()
+	re.NoError(err)
+	re.True(status.Degraded)
+	re.Equal([]string{"z1"}, status.DownZones)
+	re.Len(status.OriginalRules, 1)
+	re.Equal("zone", status.OriginalRules[0].IsolationLevel)
+	re.Len(status.Events, 1)
+	re.Equal(endpoint.DegradedPlacementEnter, status.Events[0].Action)
+	re.Equal([]string{"pd/default"}, status.Events[0].Rules)
+
+	// the status is kept after the leader changes.
+	d = newDegradedPlacement(s, rm)
+	re.NoError(d.load())
+	d.startTime = time.Now().Add(-time.Hour)
+	status, err = d.getStatus()
+	re.NoError(err)
+	re.Equal([]string{"z1"}, status.DownZones)
+
+	// the original rules are restored once the zone recovers.
+	d.check(cfg, newStores())
+	re.Equal("zone", rm.GetRule(placement.DefaultGroupID, placement.DefaultRuleID).IsolationLevel)
+	status, err = d.getStatus()
+	re.NoError(err)
+	re.False(status.Degraded)
+	re.Empty(status.OriginalRules)
+	re.Len(status.Events, 2)
+	re.Equal(endpoint.DegradedPlacementExit, status.Events[1].Action)
+
+	// the rules edited while being degraded are not restored.
+	d.check(cfg, newStores("z1"))
+	rule := rm.GetRule(placement.DefaultGroupID, placement.DefaultRuleID).Clone()
+	re.Empty(rule.IsolationLevel)
+	rule.Count = 5
+	re.NoError(rm.SetRule(rule))
+	d.check(cfg, newStores())
+	rule = rm.GetRule(placement.DefaultGroupID, placement.DefaultRuleID)
+	re.Empty(rule.IsolationLevel)
+	re.Equal(5, rule.Count)
+	re.NoError(rm.SetRule(&placement.Rule{
+		GroupID: placement.DefaultGroupID, ID: placement.DefaultRuleID, Role: placement.Voter, Count: 3,
+		LocationLabels: []string{"zone", "host"}, IsolationLevel: "zone",
+	}))
+
+	// the degraded placement is disabled.
+	cfg.EnableDegradedPlacement = false
+	d.check(cfg, newStores("z1"))
+	re.Equal("zone", rm.GetRule(placement.DefaultGroupID, placement.DefaultRuleID).IsolationLevel)
+}
+
 func TestStores(t *testing.T) {
 	re := require.New(t)
 	n := uint64(10)
@@ -2880,6 +3556,67 @@ func TestCheckCache(t *testing.T) {
 	re.NoError(failpoint.Disable("github.com/tikv/pd/pkg/schedule/break-patrol"))
 }
 
+func TestPatrolRegionsConcurrently(t *testing.T) {
+	re := require.New(t)
+
+	tc, co, cleanup := prepare(func(cfg *sc.ScheduleConfig) {
+		cfg.PatrolRegionConcurrency = 4
+	}, nil, nil, re)
+	defer cleanup()
+
+	re.NoError(tc.addRegionStore(1, 0))
+	re.NoError(tc.addRegionStore(2, 0))
+	re.NoError(tc.addRegionStore(3, 0))
+	regionCount := 100
+	for i := 1; i <= regionCount; i++ {
+		re.NoError(tc.addLeaderRegion(uint64(i), 1, 2, 3))
+	}
+	re.NoError(failpoint.Enable("github.com/tikv/pd/pkg/schedule/break-patrol", `return`))
+	defer func() {
+		re.NoError(failpoint.Disable("github.com/tikv/pd/pkg/schedule/break-patrol"))
+	}()
+
+	co.GetWaitGroup().Add(1)
+	co.PatrolRegions()
+	progress := co.GetPatrolRegionsProgress()
+	re.Len(progress.Partitions, 4)
+	checked := 0
+	for _, partition := range progress.Partitions {
+		re.True(partition.Done)
+		checked += partition.CheckedRegions
+	}
+	re.Equal(regionCount, checked)
+	re.Positive(co.GetPatrolRegionsDuration())
+	re.Positive(progress.Lag.Duration)
+}
+
+func TestPatrolRegionsConcurrentlyWithinLimit(t *testing.T) {
+	re := require.New(t)
+
+	tc, co, cleanup := prepare(func(cfg *sc.ScheduleConfig) {
+		cfg.PatrolRegionConcurrency = 4
+		cfg.ReplicaScheduleLimit = 3
+	}, nil, nil, re)
+	defer cleanup()
+
+	re.NoError(tc.addRegionStore(1, 0))
+	re.NoError(tc.addRegionStore(2, 0))
+	re.NoError(tc.addRegionStore(3, 0))
+	tc.SetStoreLimit(3, storelimit.AddPeer, 600)
+	// all the regions lack a replica.
+	for i := 1; i <= 100; i++ {
+		re.NoError(tc.addLeaderRegion(uint64(i), 1, 2))
+	}
+	re.NoError(failpoint.Enable("github.com/tikv/pd/pkg/schedule/break-patrol", `return`))
+	defer func() {
+		re.NoError(failpoint.Disable("github.com/tikv/pd/pkg/schedule/break-patrol"))
+	}()
+
+	co.GetWaitGroup().Add(1)
+	co.PatrolRegions()
+	re.Equal(uint64(3), co.GetOperatorController().OperatorCount(operator.OpReplica))
+}
+
 func TestPeerState(t *testing.T) {
 	re := require.New(t)
 
@@ -3759,7 +4496,7 @@ func BenchmarkHandleStatsAsync(b *testing.B) {
 	b.ResetTimer()
 	// Run HandleStatsAsync b.N times
 	for i := 0; i < b.N; i++ {
-		cluster.HandleStatsAsync(c, region)
+		cluster.HandleStatsAsync(c, region, true)
 	}
 }
 
@@ -61,6 +61,7 @@ func (c *RaftCluster) HandleRegionHeartbeat(region *core.RegionInfo) error {
 		return err
 	}
 	tracer.OnAllStageFinished()
+	c.interceptors.OnRegionHeartbeat(region)
 
 	if c.IsServiceIndependent(mcsutils.SchedulingServiceName) {
 		return nil
@@ -207,7 +208,7 @@ func checkSplitRegions(regions []*metapb.Region) error {
 }
 
 // HandleReportSplit handles the report split request.
-func (*RaftCluster) HandleReportSplit(request *pdpb.ReportSplitRequest) (*pdpb.ReportSplitResponse, error) {
+func (c *RaftCluster) HandleReportSplit(request *pdpb.ReportSplitRequest) (*pdpb.ReportSplitResponse, error) {
 	left := request.GetLeft()
 	right := request.GetRight()
 
@@ -227,11 +228,12 @@ func (*RaftCluster) HandleReportSplit(request *pdpb.ReportSplitRequest) (*pdpb.R
 	log.Info("region split, generate new region",
 		zap.Uint64("region-id", originRegion.GetId()),
 		logutil.ZapRedactStringer("region-meta", core.RegionToHexMeta(left)))
+	c.regionJournal.recordSplit([]*metapb.Region{left, right})
 	return &pdpb.ReportSplitResponse{}, nil
 }
 
 // HandleBatchReportSplit handles the batch report split request.
-func (*RaftCluster) HandleBatchReportSplit(request *pdpb.ReportBatchSplitRequest) (*pdpb.ReportBatchSplitResponse, error) {
+func (c *RaftCluster) HandleBatchReportSplit(request *pdpb.ReportBatchSplitRequest) (*pdpb.ReportBatchSplitResponse, error) {
 	regions := request.GetRegions()
 
 	hrm := core.RegionsToHexMeta(regions)
@@ -249,6 +251,7 @@ func (*RaftCluster) HandleBatchReportSplit(request *pdpb.ReportBatchSplitRequest
 		zap.Uint64("region-id", originRegion.GetId()),
 		logutil.ZapRedactStringer("origin", hrm),
 		zap.Int("total", last))
+	c.regionJournal.recordSplit(regions)
 	return &pdpb.ReportBatchSplitResponse{}, nil
 }
 
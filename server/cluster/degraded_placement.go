@@ -0,0 +1,269 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
+	sc "github.com/tikv/pd/pkg/schedule/config"
+	"github.com/tikv/pd/pkg/schedule/placement"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+// DegradedPlacementStatus is the status of the degraded placement.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type DegradedPlacementStatus struct {
+	Degraded  bool      `json:"degraded"`
+	DownZones []string  `json:"down_zones,omitempty"`
+	Since     time.Time `json:"since"`
+	// OriginalRules are the rules before being relaxed, they are restored once
+	// the down zones recover.
+	OriginalRules []*placement.Rule `json:"original_rules,omitempty"`
+	// RelaxedRules are the rules set by the degraded placement, the rules which
+	// differ from them are edited in the meantime and aren't restored.
+	RelaxedRules []*placement.Rule `json:"relaxed_rules,omitempty"`
+	// Events are the audit records of the transitions, they are not persisted
+	// along with the status.
+	Events []*endpoint.DegradedPlacementEvent `json:"events,omitempty"`
+}
+
+// degradedPlacement relaxes the placement rules temporarily when a zone is
+// entirely down, so that the replicas can be placed in the remaining zones,
+// and restores the original rules once the zone recovers. The status is
+// persisted so that the original rules can be restored by the new leader, and
+// every transition is recorded for auditing.
+type degradedPlacement struct {
+	storage     endpoint.DegradedPlacementStorage
+	ruleManager *placement.RuleManager
+	// startTime is used to avoid treating the stores as down before they
+	// send the heartbeats to the new leader.
+	startTime time.Time
+
+	mu struct {
+		syncutil.Mutex
+		status DegradedPlacementStatus
+	}
+}
+
+func newDegradedPlacement(storage endpoint.DegradedPlacementStorage, ruleManager *placement.RuleManager) *degradedPlacement {
+	return &degradedPlacement{
+		storage:     storage,
+		ruleManager: ruleManager,
+	}
+}
+
+// load loads the persisted status.
+func (d *degradedPlacement) load() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.startTime = time.Now()
+	if _, err := d.storage.LoadDegradedPlacementState(&d.mu.status); err != nil {
+		return err
+	}
+	if d.mu.status.Degraded {
+		degradedPlacementGauge.Set(1)
+	} else {
+		degradedPlacementGauge.Set(0)
+	}
+	return nil
+}
+
+// check checks the zones of the stores, and relaxes or restores the placement
+// rules if the down zones are changed.
+func (d *degradedPlacement) check(cfg *sc.ReplicationConfig, stores []*core.StoreInfo) {
+	if !d.ruleManager.IsInitialized() {
+		return
+	}
+	var (
+		downZones []string
+		reason    string
+	)
+	if cfg.EnablePlacementRules && cfg.EnableDegradedPlacement {
+		downTime := cfg.DegradedZoneDownTime.Duration
+		if time.Since(d.startTime) < downTime {
+			return
+		}
+		var ok bool
+		if downZones, ok = findDownZones(stores, cfg.DegradedZoneLabel, downTime); !ok {
+			return
+		}
+		if len(downZones) > 0 {
+			reason = fmt.Sprintf("zones %v are down for more than %s", downZones, downTime)
+		} else {
+			reason = "the down zones are recovered"
+		}
+	} else {
+		reason = "the degraded placement is disabled"
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := d.mu.status
+	if slices.Equal(downZones, status.DownZones) {
+		return
+	}
+
+	// relax the rules from the original ones, and restore the rules which are
+	// not relaxed any more. The rules deleted or edited in the meantime are not
+	// restored, the edits are kept.
+	current := make(map[[2]string]*placement.Rule)
+	for _, rule := range d.ruleManager.GetAllRules() {
+		current[rule.Key()] = rule
+	}
+	relaxed := make(map[[2]string]*placement.Rule, len(status.RelaxedRules))
+	for _, rule := range status.RelaxedRules {
+		relaxed[rule.Key()] = rule
+	}
+	changes := make(map[[2]string]*placement.Rule)
+	for _, rule := range status.OriginalRules {
+		cur, ok := current[rule.Key()]
+		if !ok {
+			continue
+		}
+		if r, ok := relaxed[rule.Key()]; ok && !sameRuleContent(cur, r) {
+			log.Warn("the relaxed rule is edited, keep the edit instead of restoring it",
+				zap.String("rule", rule.GroupID+"/"+rule.ID))
+			continue
+		}
+		current[rule.Key()] = rule
+		changes[rule.Key()] = rule
+	}
+	rules := make([]*placement.Rule, 0, len(current))
+	for _, rule := range current {
+		rules = append(rules, rule)
+	}
+	var originalRules, relaxedRules []*placement.Rule
+	for _, rule := range placement.RelaxRulesForDownZones(rules, cfg.DegradedZoneLabel, downZones) {
+		originalRules = append(originalRules, current[rule.Key()].Clone())
+		relaxedRules = append(relaxedRules, rule.Clone())
+		changes[rule.Key()] = rule
+	}
+	toSet := make([]*placement.Rule, 0, len(changes))
+	ruleKeys := make([]string, 0, len(changes))
+	for _, rule := range changes {
+		toSet = append(toSet, rule)
+		ruleKeys = append(ruleKeys, rule.GroupID+"/"+rule.ID)
+	}
+	sort.Strings(ruleKeys)
+	if len(toSet) > 0 {
+		if err := d.ruleManager.SetRulesWithoutValidation(toSet); err != nil {
+			log.Error("failed to update the rules for the degraded placement",
+				zap.Strings("down-zones", downZones), errs.ZapError(err))
+			return
+		}
+	}
+
+	action := endpoint.DegradedPlacementEnter
+	switch {
+	case len(downZones) == 0:
+		action = endpoint.DegradedPlacementExit
+		status = DegradedPlacementStatus{}
+	case status.Degraded:
+		action = endpoint.DegradedPlacementUpdate
+	default:
+		status.Since = time.Now()
+	}
+	if len(downZones) > 0 {
+		status.Degraded = true
+		status.DownZones = downZones
+		status.OriginalRules = originalRules
+		status.RelaxedRules = relaxedRules
+	}
+	d.mu.status = status
+	if err := d.storage.SaveDegradedPlacementState(&status); err != nil {
+		log.Error("failed to save the degraded placement status", errs.ZapError(err))
+	}
+	event := &endpoint.DegradedPlacementEvent{
+		Time:      time.Now(),
+		Action:    action,
+		DownZones: downZones,
+		Rules:     ruleKeys,
+		Reason:    reason,
+	}
+	if err := d.storage.SaveDegradedPlacementEvent(event); err != nil {
+		log.Error("failed to save the degraded placement event", errs.ZapError(err))
+	}
+	degradedPlacementEventCounter.WithLabelValues(action).Inc()
+	if status.Degraded {
+		degradedPlacementGauge.Set(1)
+		log.Warn("placement rules are relaxed for the down zones",
+			zap.String("action", action), zap.Strings("down-zones", downZones), zap.Strings("rules", ruleKeys))
+	} else {
+		degradedPlacementGauge.Set(0)
+		log.Info("placement rules are restored", zap.String("reason", reason), zap.Strings("rules", ruleKeys))
+	}
+}
+
+// getStatus returns the status along with the transition events.
+func (d *degradedPlacement) getStatus() (*DegradedPlacementStatus, error) {
+	d.mu.Lock()
+	status := d.mu.status
+	d.mu.Unlock()
+	events, err := d.storage.LoadDegradedPlacementEvents()
+	if err != nil {
+		return nil, err
+	}
+	status.Events = events
+	return &status, nil
+}
+
+// sameRuleContent returns true if the rules are the same except the runtime fields.
+func sameRuleContent(a, b *placement.Rule) bool {
+	a, b = a.Clone(), b.Clone()
+	a.Version, a.CreateTimestamp = 0, 0
+	b.Version, b.CreateTimestamp = 0, 0
+	return a.String() == b.String()
+}
+
+// findDownZones returns the sorted zones whose stores are all down for more
+// than downTime. It returns false if no zone is up, in which case relaxing the
+// rules doesn't help.
+func findDownZones(stores []*core.StoreInfo, zoneLabel string, downTime time.Duration) ([]string, bool) {
+	zones := make(map[string]bool) // zone -> up
+	for _, store := range stores {
+		if store.IsRemoved() {
+			continue
+		}
+		zone := store.GetLabelValue(zoneLabel)
+		if zone == "" {
+			continue
+		}
+		zones[zone] = zones[zone] || store.DownTime() < downTime
+	}
+	var (
+		downZones []string
+		hasUp     bool
+	)
+	for zone, up := range zones {
+		if up {
+			hasUp = true
+		} else {
+			downZones = append(downZones, zone)
+		}
+	}
+	if !hasUp {
+		return nil, false
+	}
+	sort.Strings(downZones)
+	return downZones, true
+}
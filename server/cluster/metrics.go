@@ -88,6 +88,30 @@ var (
 			Help:      "The ETA of corresponding action",
 		}, []string{"address", "store", "action"})
 
+	stateEpochGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "state_epoch",
+			Help:      "The epoch of the cluster state.",
+		})
+
+	degradedPlacementGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "degraded_placement",
+			Help:      "Whether the placement rules are relaxed for the down zones.",
+		})
+
+	degradedPlacementEventCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "degraded_placement_event",
+			Help:      "Counter of the degraded placement transitions.",
+		}, []string{"action"})
+
 	storeSyncConfigEvent = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "pd",
@@ -95,6 +119,14 @@ var (
 			Name:      "store_sync",
 			Help:      "The state of store sync config",
 		}, []string{"address", "state"})
+
+	storeClockSkewGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "store_clock_skew_seconds",
+			Help:      "The estimated clock skew of the store against PD, positive if the store clock is ahead.",
+		}, []string{"address", "store"})
 )
 
 func init() {
@@ -108,4 +140,8 @@ func init() {
 	prometheus.MustRegister(storesETAGauge)
 	prometheus.MustRegister(storeSyncConfigEvent)
 	prometheus.MustRegister(updateStoreStatsGauge)
+	prometheus.MustRegister(stateEpochGauge)
+	prometheus.MustRegister(degradedPlacementGauge)
+	prometheus.MustRegister(degradedPlacementEventCounter)
+	prometheus.MustRegister(storeClockSkewGauge)
 }
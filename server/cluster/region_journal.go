@@ -0,0 +1,139 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+)
+
+// The types of the region events.
+const (
+	RegionEventSplit = "split"
+	RegionEventMerge = "merge"
+)
+
+// maxRegionEvents is the max number of the region events kept in the journal.
+const maxRegionEvents = 4096
+
+// RegionEvent is a split or a merge of the regions.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type RegionEvent struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	// StartKey and EndKey are the hex encoded key range affected by the event.
+	StartKey string `json:"start_key"`
+	EndKey   string `json:"end_key"`
+	// SourceRegionIDs are the regions before the event, and RegionIDs are
+	// the resulting regions.
+	SourceRegionIDs []uint64 `json:"source_region_ids"`
+	RegionIDs       []uint64 `json:"region_ids"`
+
+	startKey, endKey []byte
+}
+
+// regionJournal keeps the latest split and merge events in memory, so that
+// the changes of the regions in a key range can be traced. The journal starts
+// over once the leader changes.
+type regionJournal struct {
+	syncutil.RWMutex
+	events []*RegionEvent
+}
+
+func newRegionJournal() *regionJournal {
+	return &regionJournal{}
+}
+
+func (j *regionJournal) record(event *RegionEvent) {
+	event.Time = time.Now()
+	event.StartKey = core.HexRegionKeyStr(event.startKey)
+	event.EndKey = core.HexRegionKeyStr(event.endKey)
+	j.Lock()
+	defer j.Unlock()
+	j.events = append(j.events, event)
+	if len(j.events) > maxRegionEvents {
+		j.events = append(j.events[:0:0], j.events[len(j.events)-maxRegionEvents:]...)
+	}
+}
+
+// recordSplit records the split which results in the given regions, the
+// original region is the last one.
+func (j *regionJournal) recordSplit(regions []*metapb.Region) {
+	if len(regions) < 2 {
+		return
+	}
+	ids := make([]uint64, 0, len(regions))
+	for _, region := range regions {
+		ids = append(ids, region.GetId())
+	}
+	j.record(&RegionEvent{
+		Type:            RegionEventSplit,
+		SourceRegionIDs: []uint64{regions[len(regions)-1].GetId()},
+		RegionIDs:       ids,
+		startKey:        regions[0].GetStartKey(),
+		endKey:          regions[len(regions)-1].GetEndKey(),
+	})
+}
+
+// recordMerge records the merge if the region covers a wider key range than
+// its origin and the overlapped regions are removed.
+func (j *regionJournal) recordMerge(region, origin *core.RegionInfo, overlaps []*core.RegionInfo) {
+	if origin == nil || len(overlaps) == 0 {
+		return
+	}
+	endKey, originEndKey := region.GetEndKey(), origin.GetEndKey()
+	expanded := bytes.Compare(region.GetStartKey(), origin.GetStartKey()) < 0 ||
+		(len(originEndKey) > 0 && (len(endKey) == 0 || bytes.Compare(endKey, originEndKey) > 0))
+	if !expanded {
+		return
+	}
+	// the overlaps may contain the origin itself.
+	sources := []uint64{origin.GetID()}
+	for _, overlap := range overlaps {
+		if overlap.GetID() != region.GetID() {
+			sources = append(sources, overlap.GetID())
+		}
+	}
+	if len(sources) == 1 {
+		return
+	}
+	j.record(&RegionEvent{
+		Type:            RegionEventMerge,
+		SourceRegionIDs: sources,
+		RegionIDs:       []uint64{region.GetID()},
+		startKey:        region.GetStartKey(),
+		endKey:          region.GetEndKey(),
+	})
+}
+
+// query returns at most limit events overlapping with [startKey, endKey) from
+// the newest to the oldest, the empty endKey means the end of the key space.
+func (j *regionJournal) query(startKey, endKey []byte, limit int) []*RegionEvent {
+	j.RLock()
+	defer j.RUnlock()
+	events := make([]*RegionEvent, 0)
+	for i := len(j.events) - 1; i >= 0 && len(events) < limit; i-- {
+		event := j.events[i]
+		if (len(endKey) == 0 || bytes.Compare(event.startKey, endKey) < 0) &&
+			(len(event.endKey) == 0 || bytes.Compare(startKey, event.endKey) < 0) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/cluster"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/schedule"
 	"github.com/tikv/pd/pkg/schedule/checker"
@@ -51,6 +52,7 @@ type schedulingController struct {
 	opt         sc.ConfProvider
 	coordinator *schedule.Coordinator
 	labelStats  *statistics.LabelStatistics
+	zoneStats   *statistics.ZoneQuorumStatistics
 	regionStats *statistics.RegionStatistics
 	hotStat     *statistics.HotStat
 	slowStat    *statistics.SlowStat
@@ -67,6 +69,7 @@ func newSchedulingController(parentCtx context.Context, basicCluster *core.Basic
 		BasicCluster: basicCluster,
 		opt:          opt,
 		labelStats:   statistics.NewLabelStatistics(),
+		zoneStats:    statistics.NewZoneQuorumStatistics(),
 		hotStat:      statistics.NewHotStat(parentCtx),
 		slowStat:     statistics.NewSlowStat(),
 		regionStats:  statistics.NewRegionStatistics(basicCluster, opt, ruleManager),
@@ -173,6 +176,7 @@ func resetSchedulingMetrics() {
 	schedule.ResetHotSpotMetrics()
 	statistics.ResetRegionStatsMetrics()
 	statistics.ResetLabelStatsMetrics()
+	statistics.ResetZoneQuorumStatsMetrics()
 	// reset hot cache metrics
 	statistics.ResetHotCacheStatusMetrics()
 }
@@ -192,6 +196,7 @@ func (sc *schedulingController) collectSchedulingMetrics() {
 	}
 	sc.regionStats.Collect()
 	sc.labelStats.Collect()
+	sc.zoneStats.Collect()
 	// collect hot cache metrics
 	sc.hotStat.CollectMetrics()
 	// collect the lock metrics
@@ -223,6 +228,11 @@ func (sc *schedulingController) GetLabelStats() *statistics.LabelStatistics {
 	return sc.labelStats
 }
 
+// GetZoneQuorumStats gets zone quorum statistics.
+func (sc *schedulingController) GetZoneQuorumStats() *statistics.ZoneQuorumStatistics {
+	return sc.zoneStats
+}
+
 // GetRegionStatsByType gets the status of the region by types.
 func (sc *schedulingController) GetRegionStatsByType(typ statistics.RegionStatisticType) []*core.RegionInfo {
 	if sc.regionStats == nil {
@@ -233,8 +243,14 @@ func (sc *schedulingController) GetRegionStatsByType(typ statistics.RegionStatis
 
 // UpdateRegionsLabelLevelStats updates the status of the region label level by types.
 func (sc *schedulingController) UpdateRegionsLabelLevelStats(regions []*core.RegionInfo) {
+	ratio := sc.opt.GetRegionStatsSampleRatio()
 	for _, region := range regions {
-		sc.labelStats.Observe(region, sc.getStoresWithoutLabelLocked(region, core.EngineKey, core.EngineTiFlash), sc.opt.GetLocationLabels())
+		stores := sc.getStoresWithoutLabelLocked(region, core.EngineKey, core.EngineTiFlash)
+		// Only the sampled regions update the label statistics.
+		if cluster.SampleRegionStats(ratio) {
+			sc.labelStats.Observe(region, stores, sc.opt.GetLocationLabels())
+		}
+		sc.zoneStats.Observe(region, stores, sc.opt.GetLocationLabels())
 	}
 }
 
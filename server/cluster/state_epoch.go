@@ -0,0 +1,124 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"sync/atomic"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+// The reasons to bump the cluster state epoch.
+const (
+	stateEpochLeaderChange = "leader-change"
+	stateEpochMemberChange = "member-change"
+	stateEpochStoreChange  = "store-change"
+	stateEpochRuleChange   = "rule-change"
+)
+
+// stateEpochStep is the number of the epochs reserved by one save, so that the
+// storage is written once per window instead of on every bump.
+const stateEpochStep uint64 = 1000
+
+// stateEpoch is a monotonically increasing number which is bumped whenever the
+// topology of the cluster changes, e.g. the members, the stores or the
+// placement rules are changed. The clients and caches can compare it with the
+// previous one to detect the staleness cheaply. The upper bound of the epochs
+// handed out is reserved in storage before they are used, so it never goes
+// backwards even if the leader changes.
+type stateEpoch struct {
+	storage endpoint.ClusterStateEpochStorage
+	epoch   atomic.Uint64
+
+	mu struct {
+		syncutil.Mutex
+		// limit is the upper bound of the epochs reserved in storage.
+		limit uint64
+		// memberIDs is used to detect the member changes.
+		memberIDs map[uint64]struct{}
+	}
+}
+
+func newStateEpoch(storage endpoint.ClusterStateEpochStorage) *stateEpoch {
+	return &stateEpoch{storage: storage}
+}
+
+// load starts from the reserved upper bound, because the previous leader might
+// have handed out any epoch up to it, and bumps it since the new leader cannot
+// tell what has been changed during the leader change.
+func (e *stateEpoch) load() error {
+	limit, err := e.storage.LoadClusterStateEpoch()
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.epoch.Store(limit)
+	e.mu.limit = limit
+	return e.bumpLocked(stateEpochLeaderChange)
+}
+
+func (e *stateEpoch) get() uint64 {
+	return e.epoch.Load()
+}
+
+// bump increases the epoch, it's kept unchanged if a new window can't be reserved.
+func (e *stateEpoch) bump(reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_ = e.bumpLocked(reason)
+}
+
+func (e *stateEpoch) bumpLocked(reason string) error {
+	epoch := e.epoch.Load() + 1
+	if epoch > e.mu.limit {
+		limit := epoch + stateEpochStep - 1
+		if err := e.storage.SaveClusterStateEpoch(limit); err != nil {
+			log.Error("failed to reserve the cluster state epochs",
+				zap.Uint64("epoch", epoch), zap.Uint64("limit", limit), zap.String("reason", reason), errs.ZapError(err))
+			return err
+		}
+		e.mu.limit = limit
+	}
+	e.epoch.Store(epoch)
+	stateEpochGauge.Set(float64(epoch))
+	log.Debug("cluster state epoch is bumped", zap.Uint64("epoch", epoch), zap.String("reason", reason))
+	return nil
+}
+
+// observeMembers bumps the epoch if the members are changed since the last observation.
+func (e *stateEpoch) observeMembers(members []*pdpb.Member) {
+	memberIDs := make(map[uint64]struct{}, len(members))
+	for _, m := range members {
+		memberIDs[m.GetMemberId()] = struct{}{}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	changed := e.mu.memberIDs != nil && len(e.mu.memberIDs) != len(memberIDs)
+	for id := range memberIDs {
+		if _, ok := e.mu.memberIDs[id]; !ok && e.mu.memberIDs != nil {
+			changed = true
+		}
+	}
+	e.mu.memberIDs = memberIDs
+	if changed {
+		_ = e.bumpLocked(stateEpochMemberChange)
+	}
+}
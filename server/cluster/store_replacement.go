@@ -0,0 +1,146 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
+	mcsutils "github.com/tikv/pd/pkg/mcs/utils"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"go.uber.org/zap"
+)
+
+// StoreReplacementStatus is the status of a store replacement.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreReplacementStatus struct {
+	*endpoint.StoreReplacement
+	// RemainingRegionCount is the number of the regions left on the old store.
+	RemainingRegionCount int `json:"remaining_region_count"`
+}
+
+// ReplaceStore replaces the old store with the new one, e.g. for a hardware
+// replacement. The old store is set offline, and the checkers move each of its
+// peers to the new store instead of spreading them among the other stores, as
+// long as the placement doesn't get worse. The replacement finishes when the
+// old store becomes tombstone, and is canceled if the old store is set up again.
+// It's refused when the scheduling service is independent, whose checkers don't
+// know the replacement target.
+func (c *RaftCluster) ReplaceStore(oldStoreID, newStoreID uint64) error {
+	if c.IsServiceIndependent(mcsutils.SchedulingServiceName) {
+		return errs.ErrInvalidStoreReplacement.FastGenByArgs(oldStoreID, "it's not supported when the scheduling service is independent")
+	}
+	oldStore := c.GetStore(oldStoreID)
+	if oldStore == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(oldStoreID)
+	}
+	newStore := c.GetStore(newStoreID)
+	if newStore == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(newStoreID)
+	}
+	if err := c.checkStoreReplacement(oldStore, newStore); err != nil {
+		return err
+	}
+	replacement := &endpoint.StoreReplacement{
+		OldStoreID: oldStoreID,
+		NewStoreID: newStoreID,
+		StartTime:  time.Now(),
+	}
+	if err := c.storage.SaveStoreReplacement(replacement); err != nil {
+		return err
+	}
+	c.PutStore(oldStore.Clone(core.SetReplacementTarget(newStoreID)))
+	if err := c.RemoveStore(oldStoreID, false); err != nil {
+		c.removeStoreReplacement(oldStoreID)
+		return err
+	}
+	log.Warn("store is being replaced",
+		zap.Uint64("old-store-id", oldStoreID),
+		zap.Uint64("new-store-id", newStoreID))
+	return nil
+}
+
+func (c *RaftCluster) checkStoreReplacement(oldStore, newStore *core.StoreInfo) error {
+	oldStoreID, newStoreID := oldStore.GetID(), newStore.GetID()
+	if oldStoreID == newStoreID {
+		return errs.ErrInvalidStoreReplacement.FastGenByArgs(oldStoreID, "the new store is the same one")
+	}
+	if oldStore.IsRemoved() || oldStore.IsRemoving() || oldStore.IsPhysicallyDestroyed() {
+		return errs.ErrInvalidStoreReplacement.FastGenByArgs(oldStoreID, "the store has been removed or is being removed")
+	}
+	if newStore.IsRemoved() || newStore.IsRemoving() || newStore.IsPhysicallyDestroyed() {
+		return errs.ErrInvalidStoreReplacement.FastGenByArgs(oldStoreID,
+			fmt.Sprintf("the new store %d has been removed or is being removed", newStoreID))
+	}
+	if oldStore.IsTiFlash() != newStore.IsTiFlash() {
+		return errs.ErrInvalidStoreReplacement.FastGenByArgs(oldStoreID,
+			fmt.Sprintf("the engine of the new store %d is different", newStoreID))
+	}
+	for _, store := range c.GetStores() {
+		if target := store.GetReplacementTarget(); target == oldStoreID || target == newStoreID {
+			return errs.ErrInvalidStoreReplacement.FastGenByArgs(oldStoreID,
+				fmt.Sprintf("store %d is replacing store %d", target, store.GetID()))
+		}
+	}
+	return nil
+}
+
+// removeStoreReplacement removes the replacement of the old store if any.
+func (c *RaftCluster) removeStoreReplacement(oldStoreID uint64) {
+	if store := c.GetStore(oldStoreID); store != nil && store.GetReplacementTarget() != 0 {
+		c.PutStore(store.Clone(core.SetReplacementTarget(0)))
+	}
+	if err := c.storage.DeleteStoreReplacement(oldStoreID); err != nil {
+		log.Warn("failed to delete the store replacement",
+			zap.Uint64("old-store-id", oldStoreID), errs.ZapError(err))
+	}
+}
+
+// loadStoreReplacements restores the replacement targets of the stores, the
+// replacements of the stores which have been removed are cleaned up.
+func (c *RaftCluster) loadStoreReplacements() error {
+	replacements, err := c.storage.LoadStoreReplacements()
+	if err != nil {
+		return err
+	}
+	for _, replacement := range replacements {
+		store := c.GetStore(replacement.OldStoreID)
+		if store == nil || store.IsRemoved() {
+			c.removeStoreReplacement(replacement.OldStoreID)
+			continue
+		}
+		c.PutStore(store.Clone(core.SetReplacementTarget(replacement.NewStoreID)))
+	}
+	return nil
+}
+
+// GetStoreReplacements returns the status of the ongoing store replacements.
+func (c *RaftCluster) GetStoreReplacements() ([]*StoreReplacementStatus, error) {
+	replacements, err := c.storage.LoadStoreReplacements()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]*StoreReplacementStatus, 0, len(replacements))
+	for _, replacement := range replacements {
+		statuses = append(statuses, &StoreReplacementStatus{
+			StoreReplacement:     replacement,
+			RemainingRegionCount: c.GetStoreRegionCount(replacement.OldStoreID),
+		})
+	}
+	return statuses, nil
+}
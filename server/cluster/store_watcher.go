@@ -0,0 +1,169 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/core"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/syncutil"
+	"go.uber.org/zap"
+)
+
+// The lifecycle states of the stores in the store events.
+const (
+	StoreStateUp           = "up"
+	StoreStateDisconnected = "disconnected"
+	StoreStateDown         = "down"
+	StoreStateOffline      = "offline"
+	StoreStateTombstone    = "tombstone"
+)
+
+// maxStoreEvents is the max number of the store events kept for watching.
+const maxStoreEvents = 1024
+
+// StoreEvent is a lifecycle state change of a store.
+// NOTE: This type is exported by HTTP API. Please pay more attention when modifying it.
+type StoreEvent struct {
+	Revision uint64 `json:"revision"`
+	StoreID  uint64 `json:"store_id"`
+	Address  string `json:"address"`
+	// PrevState is empty if the store is observed for the first time.
+	PrevState string    `json:"prev_state,omitempty"`
+	State     string    `json:"state"`
+	Time      time.Time `json:"time"`
+}
+
+// storeWatcher records the lifecycle state changes of the stores with the
+// increasing revisions, so that the watchers can follow the changes from the
+// revision they have seen. The events are kept in memory, but the revision is
+// persisted, so it never goes backwards even if the leader changes, and the
+// watchers following the old leader have to start over from the current state.
+type storeWatcher struct {
+	syncutil.RWMutex
+	storage  endpoint.StoreEventRevisionStorage
+	states   map[uint64]string
+	revision uint64
+	events   []*StoreEvent
+	// notify is closed and replaced once there are new events.
+	notify chan struct{}
+}
+
+func newStoreWatcher(storage endpoint.StoreEventRevisionStorage) *storeWatcher {
+	return &storeWatcher{
+		storage: storage,
+		states:  make(map[uint64]string),
+		notify:  make(chan struct{}),
+	}
+}
+
+// load loads the persisted revision, the new events follow it.
+func (w *storeWatcher) load() error {
+	revision, err := w.storage.LoadStoreEventRevision()
+	if err != nil {
+		return err
+	}
+	w.Lock()
+	defer w.Unlock()
+	if revision > w.revision {
+		w.revision = revision
+	}
+	return nil
+}
+
+func storeLifecycleState(store *core.StoreInfo, maxStoreDownTime time.Duration) string {
+	switch {
+	case store.IsRemoved():
+		return StoreStateTombstone
+	case store.IsRemoving():
+		return StoreStateOffline
+	case store.DownTime() > maxStoreDownTime:
+		return StoreStateDown
+	case store.IsDisconnected():
+		return StoreStateDisconnected
+	default:
+		return StoreStateUp
+	}
+}
+
+// observe records the events of the stores whose states are changed.
+func (w *storeWatcher) observe(maxStoreDownTime time.Duration, stores ...*core.StoreInfo) {
+	w.Lock()
+	defer w.Unlock()
+	var changed bool
+	for _, store := range stores {
+		state := storeLifecycleState(store, maxStoreDownTime)
+		prev, ok := w.states[store.GetID()]
+		if ok && prev == state {
+			continue
+		}
+		w.states[store.GetID()] = state
+		w.revision++
+		w.events = append(w.events, &StoreEvent{
+			Revision:  w.revision,
+			StoreID:   store.GetID(),
+			Address:   store.GetAddress(),
+			PrevState: prev,
+			State:     state,
+			Time:      time.Now(),
+		})
+		changed = true
+	}
+	if !changed {
+		return
+	}
+	if err := w.storage.SaveStoreEventRevision(w.revision); err != nil {
+		log.Error("failed to save the revision of the store events",
+			zap.Uint64("revision", w.revision), errs.ZapError(err))
+	}
+	if len(w.events) > maxStoreEvents {
+		w.events = append(w.events[:0:0], w.events[len(w.events)-maxStoreEvents:]...)
+	}
+	close(w.notify)
+	w.notify = make(chan struct{})
+}
+
+// forget stops tracking the physically deleted store.
+func (w *storeWatcher) forget(storeID uint64) {
+	w.Lock()
+	defer w.Unlock()
+	delete(w.states, storeID)
+}
+
+// getRevision returns the revision of the latest event.
+func (w *storeWatcher) getRevision() uint64 {
+	w.RLock()
+	defer w.RUnlock()
+	return w.revision
+}
+
+// getEvents returns the events after the given revision, and a channel which
+// is closed once there are newer events.
+func (w *storeWatcher) getEvents(revision uint64) ([]*StoreEvent, <-chan struct{}, error) {
+	w.RLock()
+	defer w.RUnlock()
+	oldest := w.revision + 1
+	if len(w.events) > 0 {
+		oldest = w.events[0].Revision
+	}
+	if revision+1 < oldest || revision > w.revision {
+		return nil, nil, errs.ErrStoreEventRevisionUnavailable.FastGenByArgs(revision, oldest-1, w.revision)
+	}
+	events := append([]*StoreEvent(nil), w.events[len(w.events)-int(w.revision-revision):]...)
+	return events, w.notify, nil
+}
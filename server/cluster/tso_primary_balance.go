@@ -0,0 +1,53 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/utils/logutil"
+)
+
+const tsoPrimaryBalanceInterval = time.Minute
+
+// runTSOPrimaryBalanceJob balances the TSO primaries of the keyspace groups across
+// the tso nodes periodically if it's enabled.
+func (c *RaftCluster) runTSOPrimaryBalanceJob() {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	interval := tsoPrimaryBalanceInterval
+	failpoint.Inject("fastTSOPrimaryBalance", func() {
+		interval = 100 * time.Millisecond
+	})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			log.Info("tso primary balance job has been stopped")
+			return
+		case <-ticker.C:
+		}
+		cfg := c.opt.GetKeyspaceConfig()
+		// The co-location takes precedence as both of them decide the primaries by the priorities.
+		if !cfg.EnableTSOPrimaryBalance || cfg.TSOPrimaryColocationLabel != "" || c.keyspaceGroupManager == nil {
+			continue
+		}
+		c.keyspaceGroupManager.BalancePrimaries(c.httpClient, cfg.TSOPrimaryBalanceCoolDown.Duration)
+	}
+}
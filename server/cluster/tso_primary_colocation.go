@@ -0,0 +1,92 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/keyspace"
+	"github.com/tikv/pd/pkg/utils/logutil"
+)
+
+const (
+	tsoPrimaryColocationInterval = time.Minute
+	// maxColocationScanRegions is the max number of the regions scanned in each
+	// key range of a keyspace, which is enough to find where most leaders live.
+	maxColocationScanRegions = 4096
+)
+
+// runTSOPrimaryColocationJob co-locates the TSO primary of each keyspace group
+// with the region leaders of its keyspaces periodically if it's enabled.
+func (c *RaftCluster) runTSOPrimaryColocationJob() {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	interval := tsoPrimaryColocationInterval
+	failpoint.Inject("fastTSOPrimaryColocation", func() {
+		interval = 100 * time.Millisecond
+	})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			log.Info("tso primary colocation job has been stopped")
+			return
+		case <-ticker.C:
+		}
+		labelKey := c.opt.GetKeyspaceConfig().TSOPrimaryColocationLabel
+		if labelKey == "" || c.keyspaceGroupManager == nil {
+			continue
+		}
+		c.keyspaceGroupManager.ColocatePrimaries(labelKey, func(keyspaces []uint32) string {
+			return c.getLeaderMajorityLabel(keyspaces, labelKey)
+		})
+	}
+}
+
+// getLeaderMajorityLabel returns the label value of the stores where most region
+// leaders of the keyspaces live, it returns an empty string if there is no leader
+// on the stores with the label.
+func (c *RaftCluster) getLeaderMajorityLabel(keyspaces []uint32, labelKey string) string {
+	counts := make(map[string]int)
+	for _, id := range keyspaces {
+		bound := keyspace.MakeRegionBound(id)
+		for _, keyRange := range [][2][]byte{
+			{bound.RawLeftBound, bound.RawRightBound},
+			{bound.TxnLeftBound, bound.TxnRightBound},
+		} {
+			for _, region := range c.ScanRegions(keyRange[0], keyRange[1], maxColocationScanRegions) {
+				store := c.GetStore(region.GetLeader().GetStoreId())
+				if store == nil {
+					continue
+				}
+				if value := store.GetLabelValue(labelKey); value != "" {
+					counts[value]++
+				}
+			}
+		}
+	}
+	var majority string
+	for value, count := range counts {
+		// Break the tie by the value to make it stable.
+		if count > counts[majority] || (count == counts[majority] && value < majority) {
+			majority = value
+		}
+	}
+	return majority
+}
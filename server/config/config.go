@@ -33,11 +33,13 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/tikv/pd/pkg/errs"
 	rm "github.com/tikv/pd/pkg/mcs/resourcemanager/server"
+	"github.com/tikv/pd/pkg/mcs/utils"
 	sc "github.com/tikv/pd/pkg/schedule/config"
 	"github.com/tikv/pd/pkg/utils/configutil"
 	"github.com/tikv/pd/pkg/utils/grpcutil"
 	"github.com/tikv/pd/pkg/utils/metricutil"
 	"github.com/tikv/pd/pkg/utils/typeutil"
+	"github.com/tikv/pd/pkg/validation"
 	"github.com/tikv/pd/pkg/versioninfo"
 	"go.etcd.io/etcd/embed"
 	"go.etcd.io/etcd/pkg/transport"
@@ -85,6 +87,13 @@ type Config struct {
 	// TSOProxyRecvFromClientTimeout is the timeout for the TSO proxy to receive a tso request from a client via grpc TSO stream.
 	// After the timeout, the TSO proxy will close the grpc TSO stream.
 	TSOProxyRecvFromClientTimeout typeutil.Duration `toml:"tso-proxy-recv-from-client-timeout" json:"tso-proxy-recv-from-client-timeout"`
+	// TSOProxyMaxBatchWait is the max time for the TSO proxy to wait for more requests to merge
+	// under a high request rate. The wait window grows with the rate up to it, and 0 disables
+	// the adaptive batching. It can be capped by the keyspace group.
+	TSOProxyMaxBatchWait typeutil.Duration `toml:"tso-proxy-max-batch-wait" json:"tso-proxy-max-batch-wait"`
+	// TSOProxyMaxBatchSize is the max logical count of a request merged by the TSO proxy.
+	// 0 means no limit. It can be capped by the keyspace group.
+	TSOProxyMaxBatchSize uint32 `toml:"tso-proxy-max-batch-size" json:"tso-proxy-max-batch-size"`
 
 	// TSOSaveInterval is the interval to save timestamp.
 	TSOSaveInterval typeutil.Duration `toml:"tso-save-interval" json:"tso-save-interval"`
@@ -96,6 +105,11 @@ type Config struct {
 	// be automatically clamped to the range.
 	TSOUpdatePhysicalInterval typeutil.Duration `toml:"tso-update-physical-interval" json:"tso-update-physical-interval"`
 
+	// MaxClockDrift is the max drift of the local clock against the peers. Once
+	// it's exceeded, the TSO primary/leader resigns and the member can't become
+	// the TSO primary/leader again until the drift recovers. 0 means no limit.
+	MaxClockDrift typeutil.Duration `toml:"max-clock-drift" json:"max-clock-drift"`
+
 	// EnableLocalTSO is used to enable the Local TSO Allocator feature,
 	// which allows the PD server to generate Local TSO for certain DC-level transactions.
 	// To make this feature meaningful, user has to set the "zone" label for the PD server
@@ -168,6 +182,12 @@ type Config struct {
 	MicroService MicroServiceConfig `toml:"micro-service" json:"micro-service"`
 
 	Controller rm.ControllerConfig `toml:"controller" json:"controller"`
+
+	Metering rm.MeteringConfig `toml:"metering" json:"metering"`
+
+	// ValidationWebhooks are the external endpoints which validate the config
+	// and rule changes, a rejected change is not applied.
+	ValidationWebhooks []validation.WebhookConfig `toml:"validation-webhook" json:"validation-webhook"`
 }
 
 // NewConfig creates a new config.
@@ -230,8 +250,6 @@ const (
 	defaultTSOSaveInterval = time.Duration(defaultLeaderLease) * time.Second
 	// defaultTSOUpdatePhysicalInterval is the default value of the config `TSOUpdatePhysicalInterval`.
 	defaultTSOUpdatePhysicalInterval = 50 * time.Millisecond
-	maxTSOUpdatePhysicalInterval     = 10 * time.Second
-	minTSOUpdatePhysicalInterval     = 1 * time.Millisecond
 
 	defaultLogFormat = "text"
 	defaultLogLevel  = "info"
@@ -246,11 +264,15 @@ const (
 	defaultGCTunerThreshold           = 0.6
 	minGCTunerThreshold               = 0
 	maxGCTunerThreshold               = 0.9
+	defaultSlowEtcdOpThreshold        = time.Second
 
 	defaultWaitRegionSplitTimeout   = 30 * time.Second
 	defaultCheckRegionSplitInterval = 50 * time.Millisecond
 	minCheckRegionSplitInterval     = 1 * time.Millisecond
 	maxCheckRegionSplitInterval     = 100 * time.Millisecond
+	// defaultTSOPrimaryBalanceCoolDown is long enough for a primary to be transferred
+	// and its TSO QPS to be observed on the new node.
+	defaultTSOPrimaryBalanceCoolDown = 5 * time.Minute
 
 	defaultEnableSchedulingFallback = true
 )
@@ -409,10 +431,10 @@ func (c *Config) Adjust(meta *toml.MetaData, reloading bool) error {
 	configutil.AdjustDuration(&c.TSOSaveInterval, defaultTSOSaveInterval)
 	configutil.AdjustDuration(&c.TSOUpdatePhysicalInterval, defaultTSOUpdatePhysicalInterval)
 
-	if c.TSOUpdatePhysicalInterval.Duration > maxTSOUpdatePhysicalInterval {
-		c.TSOUpdatePhysicalInterval.Duration = maxTSOUpdatePhysicalInterval
-	} else if c.TSOUpdatePhysicalInterval.Duration < minTSOUpdatePhysicalInterval {
-		c.TSOUpdatePhysicalInterval.Duration = minTSOUpdatePhysicalInterval
+	if c.TSOUpdatePhysicalInterval.Duration > utils.MaxTSOUpdatePhysicalInterval {
+		c.TSOUpdatePhysicalInterval.Duration = utils.MaxTSOUpdatePhysicalInterval
+	} else if c.TSOUpdatePhysicalInterval.Duration < utils.MinTSOUpdatePhysicalInterval {
+		c.TSOUpdatePhysicalInterval.Duration = utils.MinTSOUpdatePhysicalInterval
 	}
 	if c.TSOUpdatePhysicalInterval.Duration != defaultTSOUpdatePhysicalInterval {
 		log.Warn("tso update physical interval is non-default",
@@ -472,6 +494,13 @@ func (c *Config) Adjust(meta *toml.MetaData, reloading bool) error {
 	}
 
 	c.Controller.Adjust(configMetaData.Child("controller"))
+	c.Metering.Adjust(configMetaData.Child("metering"))
+
+	for i := range c.ValidationWebhooks {
+		if err := c.ValidationWebhooks[i].Adjust(); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -532,6 +561,8 @@ type PDServerConfig struct {
 	GCTunerThreshold float64 `toml:"gc-tuner-threshold" json:"gc-tuner-threshold"`
 	// BlockSafePointV1 is used to control gc safe point v1 and service safe point v1 can not be updated.
 	BlockSafePointV1 bool `toml:"block-safe-point-v1" json:"block-safe-point-v1,string"`
+	// SlowEtcdOpThreshold is the latency threshold to record an etcd operation into the slow log.
+	SlowEtcdOpThreshold typeutil.Duration `toml:"slow-etcd-op-threshold" json:"slow-etcd-op-threshold"`
 }
 
 func (c *PDServerConfig) adjust(meta *configutil.ConfigMetaData) error {
@@ -581,6 +612,9 @@ func (c *PDServerConfig) adjust(meta *configutil.ConfigMetaData) error {
 	} else if c.GCTunerThreshold > maxGCTunerThreshold {
 		c.GCTunerThreshold = maxGCTunerThreshold
 	}
+	if !meta.IsDefined("slow-etcd-op-threshold") {
+		configutil.AdjustDuration(&c.SlowEtcdOpThreshold, defaultSlowEtcdOpThreshold)
+	}
 	if err := c.migrateConfigurationFromFile(meta); err != nil {
 		return err
 	}
@@ -692,6 +726,16 @@ func (c *Config) GetTSOProxyRecvFromClientTimeout() time.Duration {
 	return c.TSOProxyRecvFromClientTimeout.Duration
 }
 
+// GetTSOProxyMaxBatchWait returns the max time for the TSO proxy to wait for more requests to merge.
+func (c *Config) GetTSOProxyMaxBatchWait() time.Duration {
+	return c.TSOProxyMaxBatchWait.Duration
+}
+
+// GetTSOProxyMaxBatchSize returns the max logical count of a request merged by the TSO proxy.
+func (c *Config) GetTSOProxyMaxBatchSize() uint32 {
+	return c.TSOProxyMaxBatchSize
+}
+
 // GetTSOUpdatePhysicalInterval returns TSO update physical interval.
 func (c *Config) GetTSOUpdatePhysicalInterval() time.Duration {
 	return c.TSOUpdatePhysicalInterval.Duration
@@ -702,6 +746,11 @@ func (c *Config) GetTSOSaveInterval() time.Duration {
 	return c.TSOSaveInterval.Duration
 }
 
+// GetMaxClockDrift returns the max drift of the local clock.
+func (c *Config) GetMaxClockDrift() time.Duration {
+	return c.MaxClockDrift.Duration
+}
+
 // GetTLSConfig returns the TLS config.
 func (c *Config) GetTLSConfig() *grpcutil.TLSConfig {
 	return &c.Security.TLSConfig
@@ -884,6 +933,21 @@ type KeyspaceConfig struct {
 	WaitRegionSplitTimeout typeutil.Duration `toml:"wait-region-split-timeout" json:"wait-region-split-timeout"`
 	// CheckRegionSplitInterval indicates the interval to check whether the region split is complete
 	CheckRegionSplitInterval typeutil.Duration `toml:"check-region-split-interval" json:"check-region-split-interval"`
+	// TSOPrimaryColocationLabel is the label key, e.g. zone, to co-locate the TSO primary of
+	// each keyspace group with the region leaders of its keyspaces. It's disabled if empty.
+	TSOPrimaryColocationLabel string `toml:"tso-primary-colocation-label" json:"tso-primary-colocation-label"`
+	// AutoSplitTSOQPSThreshold is the TSO QPS of a keyspace group to split it automatically.
+	// It's disabled if zero.
+	AutoSplitTSOQPSThreshold float64 `toml:"auto-split-tso-qps-threshold" json:"auto-split-tso-qps-threshold"`
+	// AutoSplitKeyspaceCountThreshold is the keyspace count of a keyspace group to split it
+	// automatically. It's disabled if zero.
+	AutoSplitKeyspaceCountThreshold int `toml:"auto-split-keyspace-count-threshold" json:"auto-split-keyspace-count-threshold"`
+	// EnableTSOPrimaryBalance is used to balance the TSO primaries of the keyspace groups across
+	// the tso nodes by the keyspace group count and the TSO QPS. It doesn't work if the primaries
+	// are co-located with the data by tso-primary-colocation-label.
+	EnableTSOPrimaryBalance bool `toml:"enable-tso-primary-balance" json:"enable-tso-primary-balance"`
+	// TSOPrimaryBalanceCoolDown is the min interval between two primary moves of the balance.
+	TSOPrimaryBalanceCoolDown typeutil.Duration `toml:"tso-primary-balance-cool-down" json:"tso-primary-balance-cool-down"`
 }
 
 // Validate checks if keyspace config falls within acceptable range.
@@ -895,6 +959,12 @@ func (c *KeyspaceConfig) Validate() error {
 	if c.CheckRegionSplitInterval.Duration >= c.WaitRegionSplitTimeout.Duration {
 		return errors.New("[keyspace] check-region-split-interval should be less than wait-region-split-timeout")
 	}
+	if c.AutoSplitTSOQPSThreshold < 0 || c.AutoSplitKeyspaceCountThreshold < 0 {
+		return errors.New("[keyspace] auto-split-tso-qps-threshold and auto-split-keyspace-count-threshold should be non-negative")
+	}
+	if c.TSOPrimaryBalanceCoolDown.Duration < 0 {
+		return errors.New("[keyspace] tso-primary-balance-cool-down should be non-negative")
+	}
 	return nil
 }
 
@@ -908,6 +978,9 @@ func (c *KeyspaceConfig) adjust(meta *configutil.ConfigMetaData) {
 	if !meta.IsDefined("check-region-split-interval") {
 		c.CheckRegionSplitInterval = typeutil.NewDuration(defaultCheckRegionSplitInterval)
 	}
+	if !meta.IsDefined("tso-primary-balance-cool-down") {
+		c.TSOPrimaryBalanceCoolDown = typeutil.NewDuration(defaultTSOPrimaryBalanceCoolDown)
+	}
 }
 
 // Clone makes a deep copy of the keyspace config.
@@ -26,6 +26,7 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/ratelimit"
 	sc "github.com/tikv/pd/pkg/schedule/config"
 	"github.com/tikv/pd/pkg/storage"
@@ -139,6 +140,14 @@ func TestValidation(t *testing.T) {
 	re.NoError(cfg.Schedule.Validate())
 	cfg.Schedule.TolerantSizeRatio = -0.6
 	re.Error(cfg.Schedule.Validate())
+	// check replication config
+	cfg.Replication.LocationLabels = []string{"zone", "rack"}
+	cfg.Replication.LocationLabelWeights = []float64{100}
+	re.Error(cfg.Replication.Validate())
+	cfg.Replication.LocationLabelWeights = []float64{100, 0}
+	re.Error(cfg.Replication.Validate())
+	cfg.Replication.LocationLabelWeights = []float64{100, 1}
+	re.NoError(cfg.Replication.Validate())
 	// check quota
 	re.Equal(defaultQuotaBackendBytes, cfg.QuotaBackendBytes)
 	// check request bytes
@@ -190,6 +199,7 @@ leader-schedule-limit = 0
 	re.Equal("info", cfg.Log.Level)
 	re.Equal(uint64(0), cfg.Schedule.MaxMergeRegionKeys)
 	re.Equal("http://127.0.0.1:9090", cfg.PDServerCfg.MetricStorage)
+	re.Equal(defaultSlowEtcdOpThreshold, cfg.PDServerCfg.SlowEtcdOpThreshold.Duration)
 
 	re.Equal(defaultTSOUpdatePhysicalInterval, cfg.TSOUpdatePhysicalInterval.Duration)
 
@@ -235,7 +245,7 @@ tso-update-physical-interval = "500ns"
 	err = cfg.Adjust(&meta, false)
 	re.NoError(err)
 
-	re.Equal(minTSOUpdatePhysicalInterval, cfg.TSOUpdatePhysicalInterval.Duration)
+	re.Equal(utils.MinTSOUpdatePhysicalInterval, cfg.TSOUpdatePhysicalInterval.Duration)
 
 	cfgData = `
 tso-update-physical-interval = "15s"
@@ -246,7 +256,7 @@ tso-update-physical-interval = "15s"
 	err = cfg.Adjust(&meta, false)
 	re.NoError(err)
 
-	re.Equal(maxTSOUpdatePhysicalInterval, cfg.TSOUpdatePhysicalInterval.Duration)
+	re.Equal(utils.MaxTSOUpdatePhysicalInterval, cfg.TSOUpdatePhysicalInterval.Duration)
 
 	cfgData = `
 [log]
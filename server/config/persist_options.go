@@ -175,6 +175,21 @@ func (o *PersistOptions) GetLocationLabels() []string {
 	return o.GetReplicationConfig().LocationLabels
 }
 
+// GetLocationLabelWeights returns the weights of the location labels.
+func (o *PersistOptions) GetLocationLabelWeights() []float64 {
+	return o.GetReplicationConfig().LocationLabelWeights
+}
+
+// GetLabelTaxonomy returns the constraints of the store labels.
+func (o *PersistOptions) GetLabelTaxonomy() []sc.StoreLabelConstraint {
+	return o.GetReplicationConfig().LabelTaxonomy
+}
+
+// GetLabelTaxonomyAction returns the action on the stores violating the label taxonomy.
+func (o *PersistOptions) GetLabelTaxonomyAction() string {
+	return o.GetReplicationConfig().LabelTaxonomyAction
+}
+
 // SetLocationLabels sets the location labels.
 func (o *PersistOptions) SetLocationLabels(labels []string) {
 	v := o.GetReplicationConfig().Clone()
@@ -306,6 +321,21 @@ func (o *PersistOptions) IsDiagnosticAllowed() bool {
 	return o.GetScheduleConfig().EnableDiagnostic
 }
 
+// GetDecisionLogFile returns the file of the scheduling decision log.
+func (o *PersistOptions) GetDecisionLogFile() string {
+	return o.GetScheduleConfig().DecisionLogFile
+}
+
+// GetDecisionLogMaxSize returns the max size in MB of the decision log file.
+func (o *PersistOptions) GetDecisionLogMaxSize() int {
+	return o.GetScheduleConfig().DecisionLogMaxSize
+}
+
+// GetDecisionLogMaxBackups returns the max number of the rotated decision log files.
+func (o *PersistOptions) GetDecisionLogMaxBackups() int {
+	return o.GetScheduleConfig().DecisionLogMaxBackups
+}
+
 // SetEnableDiagnostic to set the option for diagnose. It's only used to test.
 func (o *PersistOptions) SetEnableDiagnostic(enable bool) {
 	v := o.GetScheduleConfig().Clone()
@@ -407,6 +437,11 @@ func (o *PersistOptions) GetPatrolRegionInterval() time.Duration {
 	return o.GetScheduleConfig().PatrolRegionInterval.Duration
 }
 
+// GetPatrolRegionConcurrency returns the number of the key space partitions patrolled concurrently.
+func (o *PersistOptions) GetPatrolRegionConcurrency() int {
+	return o.GetScheduleConfig().PatrolRegionConcurrency
+}
+
 // GetMaxStoreDownTime returns the max down time of a store.
 func (o *PersistOptions) GetMaxStoreDownTime() time.Duration {
 	return o.GetScheduleConfig().MaxStoreDownTime.Duration
@@ -535,6 +570,51 @@ func (o *PersistOptions) GetSlowStoreEvictingAffectedStoreRatioThreshold() float
 	return o.GetScheduleConfig().SlowStoreEvictingAffectedStoreRatioThreshold
 }
 
+// GetLabelDomainOperatorLimits returns the limits of the coexist region movements in each label domain.
+func (o *PersistOptions) GetLabelDomainOperatorLimits() []sc.LabelDomainOperatorLimit {
+	return o.GetScheduleConfig().LabelDomainOperatorLimits
+}
+
+// GetClusterSnapshotBandwidth returns the aggregate bandwidth of the snapshots per second in the cluster.
+func (o *PersistOptions) GetClusterSnapshotBandwidth() uint64 {
+	return uint64(o.GetScheduleConfig().ClusterSnapshotBandwidth)
+}
+
+// GetRegionStatsSampleRatio returns the fraction of the region heartbeats which update the expensive statistics.
+func (o *PersistOptions) GetRegionStatsSampleRatio() float64 {
+	return o.GetScheduleConfig().RegionStatsSampleRatio
+}
+
+// GetMaxStorePendingCompactionBytes returns the limit of the pending compaction bytes of a store.
+func (o *PersistOptions) GetMaxStorePendingCompactionBytes() uint64 {
+	return uint64(o.GetScheduleConfig().MaxStorePendingCompactionBytes)
+}
+
+// GetMaxStoreLevel0FileCount returns the limit of the level0 file count of a store.
+func (o *PersistOptions) GetMaxStoreLevel0FileCount() uint64 {
+	return o.GetScheduleConfig().MaxStoreLevel0FileCount
+}
+
+// GetMaxEvictingStoreRatio returns the max fraction of the stores which are evicting or offline.
+func (o *PersistOptions) GetMaxEvictingStoreRatio() float64 {
+	return o.GetScheduleConfig().MaxEvictingStoreRatio
+}
+
+// GetLeaderTransferBlacklistWindow returns the base window to exclude a store as the leader target.
+func (o *PersistOptions) GetLeaderTransferBlacklistWindow() time.Duration {
+	return o.GetScheduleConfig().LeaderTransferBlacklistWindow.Duration
+}
+
+// GetMaxLearnerCatchUpTime returns the max duration for a new learner to catch up.
+func (o *PersistOptions) GetMaxLearnerCatchUpTime() time.Duration {
+	return o.GetScheduleConfig().MaxLearnerCatchUpTime.Duration
+}
+
+// GetStoreSlowStartWindow returns the window to ramp the weights of a new store.
+func (o *PersistOptions) GetStoreSlowStartWindow() time.Duration {
+	return o.GetScheduleConfig().StoreSlowStartWindow.Duration
+}
+
 // GetHighSpaceRatio returns the high space ratio.
 func (o *PersistOptions) GetHighSpaceRatio() float64 {
 	return o.GetScheduleConfig().HighSpaceRatio
@@ -595,6 +675,11 @@ func (o *PersistOptions) GetGCTunerThreshold() float64 {
 	return o.GetPDServerConfig().GCTunerThreshold
 }
 
+// GetSlowEtcdOpThreshold gets the latency threshold of the slow etcd operations.
+func (o *PersistOptions) GetSlowEtcdOpThreshold() time.Duration {
+	return o.GetPDServerConfig().SlowEtcdOpThreshold.Duration
+}
+
 // IsRemoveDownReplicaEnabled returns if remove down replica is enabled.
 func (o *PersistOptions) IsRemoveDownReplicaEnabled() bool {
 	return o.GetScheduleConfig().EnableRemoveDownReplica
@@ -804,6 +889,8 @@ func (o *PersistOptions) Reload(storage endpoint.ConfigStorage) error {
 		return err
 	}
 	adjustScheduleCfg(&cfg.Schedule)
+	// The decision log file is only set by the config file at startup.
+	cfg.Schedule.DecisionLogFile = o.GetScheduleConfig().DecisionLogFile
 	// Some fields may not be stored in the storage, we need to calculate them manually.
 	cfg.StoreConfig.Adjust()
 	cfg.PDServerCfg.MigrateDeprecatedFlags()
@@ -225,7 +225,8 @@ func (s *GrpcServer) forwardTSORequestWithDeadLine(
 
 func createTSOForwardStream(ctx context.Context, client *grpc.ClientConn) (tsopb.TSO_TsoClient, context.Context, context.CancelFunc, error) {
 	done := make(chan struct{})
-	forwardCtx, cancelForward := context.WithCancel(ctx)
+	// Pass the component of the caller to the TSO service, which counts the allocations.
+	forwardCtx, cancelForward := context.WithCancel(grpcutil.BuildComponentContext(ctx, grpcutil.GetComponent(ctx)))
 	go grpcutil.CheckStream(forwardCtx, cancelForward, done)
 	forwardStream, err := tsopb.NewTSOClient(client).Tso(forwardCtx)
 	done <- struct{}{}
@@ -52,6 +52,7 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -257,6 +258,7 @@ func (s *GrpcServer) unaryFollowerMiddleware(ctx context.Context, req request, f
 	if err := s.validateRoleInRequest(ctx, req.GetHeader(), allowFollower); err != nil {
 		return nil, err
 	}
+	s.clientRegistry.observe(ctx, time.Now())
 	return nil, nil
 }
 
@@ -447,8 +449,20 @@ func (s *GrpcServer) getMinTSFromSingleServer(
 	return resp, nil
 }
 
+// setStateEpochHeader attaches the cluster state epoch to the response header,
+// so that the clients can detect whether their cached topology is stale.
+func setStateEpochHeader(ctx context.Context, rc *cluster.RaftCluster) {
+	if rc == nil {
+		return
+	}
+	md := metadata.Pairs(grpcutil.ClusterStateEpochMetadataKey, strconv.FormatUint(rc.GetStateEpoch(), 10))
+	if err := grpc.SetHeader(ctx, md); err != nil {
+		log.Debug("failed to set the cluster state epoch header", errs.ZapError(err))
+	}
+}
+
 // GetMembers implements gRPC PDServer.
-func (s *GrpcServer) GetMembers(context.Context, *pdpb.GetMembersRequest) (*pdpb.GetMembersResponse, error) {
+func (s *GrpcServer) GetMembers(ctx context.Context, _ *pdpb.GetMembersRequest) (*pdpb.GetMembersResponse, error) {
 	if s.GetServiceMiddlewarePersistOptions().IsGRPCRateLimitEnabled() {
 		fName := currentFunction()
 		limiter := s.GetGRPCRateLimiter()
@@ -502,6 +516,7 @@ func (s *GrpcServer) GetMembers(context.Context, *pdpb.GetMembersRequest) (*pdpb
 		}
 	}
 
+	setStateEpochHeader(ctx, s.GetRaftCluster())
 	return &pdpb.GetMembersResponse{
 		Header:              s.header(),
 		Members:             members,
@@ -532,6 +547,9 @@ func (s *GrpcServer) Tso(stream pdpb.PD_TsoServer) error {
 	)
 	ctx, cancel := context.WithCancel(stream.Context())
 	defer cancel()
+	component := grpcutil.GetComponent(stream.Context())
+	// Register the client once for the stream, and count its requests with the atomics.
+	client, method := s.clientRegistry.register(stream.Context(), time.Now()), rpcMethod(stream.Context())
 	for {
 		// Prevent unnecessary performance overhead of the channel.
 		if errCh != nil {
@@ -584,6 +602,13 @@ func (s *GrpcServer) Tso(stream pdpb.PD_TsoServer) error {
 		if err != nil {
 			return status.Errorf(codes.Unknown, err.Error())
 		}
+		s.tsoComponentAllocations.Observe(component, count)
+		if client != nil && client.removed.Load() {
+			client = s.clientRegistry.register(stream.Context(), time.Now())
+		}
+		if client != nil {
+			client.observe(method, time.Now())
+		}
 		response := &pdpb.TsoResponse{
 			Header:    s.header(),
 			Timestamp: &ts,
@@ -756,6 +781,7 @@ func (s *GrpcServer) GetStore(ctx context.Context, request *pdpb.GetStoreRequest
 		return &pdpb.GetStoreResponse{Header: s.notBootstrappedHeader()}, nil
 	}
 
+	setStateEpochHeader(ctx, rc)
 	storeID := request.GetStoreId()
 	store := rc.GetStore(storeID)
 	if store == nil {
@@ -870,6 +896,7 @@ func (s *GrpcServer) GetAllStores(ctx context.Context, request *pdpb.GetAllStore
 		return &pdpb.GetAllStoresResponse{Header: s.notBootstrappedHeader()}, nil
 	}
 
+	setStateEpochHeader(ctx, rc)
 	// Don't return tombstone stores.
 	var stores []*metapb.Store
 	if request.GetExcludeTombstoneStores() {
@@ -1419,6 +1446,9 @@ func (s *GrpcServer) GetRegion(ctx context.Context, request *pdpb.GetRegionReque
 		if !rc.GetRegionSyncer().IsRunning() {
 			return &pdpb.GetRegionResponse{Header: s.regionNotFound()}, nil
 		}
+		if header := s.checkFollowerStaleness(ctx, rc); header != nil {
+			return &pdpb.GetRegionResponse{Header: header}, nil
+		}
 		region = rc.GetRegionByKey(request.GetRegionKey())
 		if region == nil {
 			log.Warn("follower get region nil", zap.String("key", string(request.GetRegionKey())))
@@ -1481,6 +1511,9 @@ func (s *GrpcServer) GetPrevRegion(ctx context.Context, request *pdpb.GetRegionR
 		if !rc.GetRegionSyncer().IsRunning() {
 			return &pdpb.GetRegionResponse{Header: s.regionNotFound()}, nil
 		}
+		if header := s.checkFollowerStaleness(ctx, rc); header != nil {
+			return &pdpb.GetRegionResponse{Header: header}, nil
+		}
 	} else {
 		rc = s.GetRaftCluster()
 		if rc == nil {
@@ -1539,6 +1572,9 @@ func (s *GrpcServer) GetRegionByID(ctx context.Context, request *pdpb.GetRegionB
 		if !rc.GetRegionSyncer().IsRunning() {
 			return &pdpb.GetRegionResponse{Header: s.regionNotFound()}, nil
 		}
+		if header := s.checkFollowerStaleness(ctx, rc); header != nil {
+			return &pdpb.GetRegionResponse{Header: header}, nil
+		}
 	} else {
 		rc = s.GetRaftCluster()
 		if rc == nil {
@@ -1601,6 +1637,9 @@ func (s *GrpcServer) ScanRegions(ctx context.Context, request *pdpb.ScanRegionsR
 		if !rc.GetRegionSyncer().IsRunning() {
 			return &pdpb.ScanRegionsResponse{Header: s.regionNotFound()}, nil
 		}
+		if header := s.checkFollowerStaleness(ctx, rc); header != nil {
+			return &pdpb.ScanRegionsResponse{Header: header}, nil
+		}
 	} else {
 		rc = s.GetRaftCluster()
 		if rc == nil {
@@ -1659,6 +1698,9 @@ func (s *GrpcServer) BatchScanRegions(ctx context.Context, request *pdpb.BatchSc
 		if !rc.GetRegionSyncer().IsRunning() {
 			return &pdpb.BatchScanRegionsResponse{Header: s.regionNotFound()}, nil
 		}
+		if header := s.checkFollowerStaleness(ctx, rc); header != nil {
+			return &pdpb.BatchScanRegionsResponse{Header: header}, nil
+		}
 	} else {
 		rc = s.GetRaftCluster()
 		if rc == nil {
@@ -2421,6 +2463,25 @@ func (s *GrpcServer) invalidValue(msg string) *pdpb.ResponseHeader {
 	})
 }
 
+// checkFollowerStaleness returns an error header if the region cache of the
+// follower is staler than the bound given by the caller, so that the caller
+// can retry on the leader.
+func (s *GrpcServer) checkFollowerStaleness(ctx context.Context, rc *cluster.RaftCluster) *pdpb.ResponseHeader {
+	maxStaleness, ok := grpcutil.GetMaxStaleness(ctx)
+	if !ok {
+		return nil
+	}
+	staleness := rc.GetRegionSyncer().GetStaleness()
+	if staleness <= maxStaleness {
+		return nil
+	}
+	followerStaleRegionRequestCounter.Inc()
+	return s.errorHeader(&pdpb.Error{
+		Type:    pdpb.ErrorType_UNKNOWN,
+		Message: fmt.Sprintf("the region cache of the follower is stale, staleness %v exceeds the bound %v", staleness, maxStaleness),
+	})
+}
+
 func (s *GrpcServer) regionNotFound() *pdpb.ResponseHeader {
 	return s.errorHeader(&pdpb.Error{
 		Type:    pdpb.ErrorType_REGION_NOT_FOUND,
@@ -22,6 +22,8 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/core"
@@ -246,6 +248,96 @@ func (h *Handler) RemoveScheduler(name string) error {
 	return err
 }
 
+// GetSchedulingProfile returns the name of the active scheduling profile, and
+// whether the config has been modified after the profile was applied.
+// It returns an empty string if no profile has been applied.
+func (h *Handler) GetSchedulingProfile() (name string, modified bool, err error) {
+	rc, err := h.GetRaftCluster()
+	if err != nil {
+		name, err = h.s.storage.LoadSchedulingProfile()
+		return name, false, err
+	}
+	return rc.GetSchedulingProfile()
+}
+
+// SwitchSchedulingProfile applies the schedule limits of the scheduling profile,
+// and adds or removes the managed schedulers according to the profile.
+// If any step fails, the config and the schedulers changed so far are restored.
+func (h *Handler) SwitchSchedulingProfile(name string) (err error) {
+	profile, ok := sc.GetSchedulingProfile(name)
+	if !ok {
+		return errs.ErrSchedulingProfileNotFound.FastGenByArgs(name)
+	}
+	rc, err := h.GetRaftCluster()
+	if err != nil {
+		return err
+	}
+	// The schedulers added before the initialization may be overwritten by
+	// the persisted ones, so the profile is only applied after it.
+	if !rc.GetCoordinator().AreSchedulersInitialized() {
+		return errs.ErrSchedulersNotInitialized.FastGenByArgs()
+	}
+	oldCfg := h.s.GetScheduleConfig()
+	cfg := oldCfg.Clone()
+	profile.Apply(cfg)
+	if err := h.s.SetScheduleConfig(*cfg); err != nil {
+		return err
+	}
+	var added, removed []types.CheckerSchedulerType
+	defer func() {
+		if err == nil {
+			return
+		}
+		h.rollbackSchedulingProfile(name, oldCfg, added, removed)
+	}()
+	for _, tp := range sc.GetProfileSchedulers() {
+		existed, err := h.IsSchedulerExisted(tp.String())
+		if err != nil && !errors.ErrorEqual(err, errs.ErrSchedulerNotFound.FastGenByArgs()) {
+			return err
+		}
+		enabled := profile.IsSchedulerEnabled(tp)
+		switch {
+		case enabled && !existed:
+			if err := h.AddScheduler(tp); err != nil {
+				return err
+			}
+			added = append(added, tp)
+		case !enabled && existed:
+			if err := h.RemoveScheduler(tp.String()); err != nil {
+				return err
+			}
+			removed = append(removed, tp)
+		}
+	}
+	failpoint.Inject("saveSchedulingProfileFail", func() {
+		failpoint.Return(errors.New("fail to save the scheduling profile"))
+	})
+	if err := h.s.storage.SaveSchedulingProfile(name); err != nil {
+		return err
+	}
+	log.Info("scheduling profile is switched", zap.String("profile", name))
+	return nil
+}
+
+// rollbackSchedulingProfile restores the config and the schedulers changed by
+// a failed switch, the failures are only logged since nothing more can be done.
+func (h *Handler) rollbackSchedulingProfile(name string, oldCfg *sc.ScheduleConfig, added, removed []types.CheckerSchedulerType) {
+	log.Warn("failed to switch the scheduling profile, rolling back", zap.String("profile", name))
+	for _, tp := range added {
+		if err := h.RemoveScheduler(tp.String()); err != nil {
+			log.Error("failed to remove the scheduler added by the profile", zap.String("scheduler-name", tp.String()), errs.ZapError(err))
+		}
+	}
+	for _, tp := range removed {
+		if err := h.AddScheduler(tp); err != nil {
+			log.Error("failed to add back the scheduler removed by the profile", zap.String("scheduler-name", tp.String()), errs.ZapError(err))
+		}
+	}
+	if err := h.s.SetScheduleConfig(*oldCfg); err != nil {
+		log.Error("failed to restore the schedule config", errs.ZapError(err))
+	}
+}
+
 // SetAllStoresLimit is used to set limit of all stores.
 func (h *Handler) SetAllStoresLimit(ratePerMin float64, limitType storelimit.Type) error {
 	c, err := h.GetRaftCluster()
@@ -160,6 +160,14 @@ var (
 			Name:      "forward_fail_total",
 			Help:      "Counter of forward fail.",
 		}, []string{"request", "type"})
+
+	followerStaleRegionRequestCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "follower_stale_region_request_total",
+			Help:      "Counter of the region requests rejected by the follower because its region cache exceeds the staleness bound.",
+		})
 )
 
 func init() {
@@ -180,4 +188,5 @@ func init() {
 	prometheus.MustRegister(bucketReportInterval)
 	prometheus.MustRegister(apiConcurrencyGauge)
 	prometheus.MustRegister(forwardFailCounter)
+	prometheus.MustRegister(followerStaleRegionRequestCounter)
 }
@@ -78,6 +78,7 @@ import (
 	"github.com/tikv/pd/pkg/utils/syncutil"
 	"github.com/tikv/pd/pkg/utils/tsoutil"
 	"github.com/tikv/pd/pkg/utils/typeutil"
+	"github.com/tikv/pd/pkg/validation"
 	"github.com/tikv/pd/pkg/versioninfo"
 	"github.com/tikv/pd/server/cluster"
 	"github.com/tikv/pd/server/config"
@@ -98,6 +99,9 @@ const (
 	// idAllocPath for idAllocator to save persistent window's end.
 	idAllocPath  = "alloc_id"
 	idAllocLabel = "idalloc"
+	// idAllocMaxStep is the max step the idAllocator can enlarge to when the
+	// regions are split at a high rate.
+	idAllocMaxStep = 100000
 
 	recoveringMarkPath = "cluster/markers/snapshot-recovering"
 
@@ -182,6 +186,14 @@ type Server struct {
 	basicCluster *core.BasicCluster
 	// for tso.
 	tsoAllocatorManager *tso.AllocatorManager
+	// clockDriftDetector estimates the drift of the local clock for the TSO allocators.
+	clockDriftDetector *tso.ClockDriftDetector
+	// tsoComponentAllocations counts the TSO allocations by the calling components.
+	tsoComponentAllocations *tso.ComponentAllocations
+	// clientRegistry tracks the gRPC clients connected to the server.
+	clientRegistry *clientRegistry
+	// validation validates the config and rule changes before they're applied.
+	validation *validation.Manager
 	// for raft cluster
 	cluster *cluster.RaftCluster
 	// For async region heartbeat.
@@ -264,6 +276,9 @@ func CreateServer(ctx context.Context, cfg *config.Config, services []string, le
 		startTimestamp:                  time.Now().Unix(),
 		DiagnosticsServer:               sysutil.NewDiagnosticsServer(cfg.Log.File.Filename),
 		mode:                            mode,
+		tsoComponentAllocations:         tso.NewComponentAllocations(),
+		clientRegistry:                  newClientRegistry(),
+		validation:                      validation.NewManager(),
 		tsoClientPool: struct {
 			syncutil.RWMutex
 			clients map[string]tsopb.TSO_TsoClient
@@ -272,6 +287,9 @@ func CreateServer(ctx context.Context, cfg *config.Config, services []string, le
 		},
 	}
 	s.handler = newHandler(s)
+	for _, webhookCfg := range cfg.ValidationWebhooks {
+		s.validation.Register(validation.NewWebhook(webhookCfg, &http.Client{}))
+	}
 
 	// create audit backend
 	s.auditBackends = []audit.Backend{
@@ -457,6 +475,7 @@ func (s *Server) startServer(ctx context.Context) error {
 		AllocPath: idAllocPath,
 		Label:     idAllocLabel,
 		Member:    s.member.MemberValue(),
+		MaxStep:   idAllocMaxStep,
 	})
 	s.encryptionKeyManager, err = encryption.NewManager(s.client, &s.cfg.Security.Encryption)
 	if err != nil {
@@ -473,11 +492,13 @@ func (s *Server) startServer(ctx context.Context) error {
 		return err
 	}
 	s.storage = storage.NewCoreStorage(defaultStorage, regionStorage)
-	s.tsoDispatcher = tsoutil.NewTSODispatcher(tsoProxyHandleDuration, tsoProxyBatchSize)
+	s.tsoDispatcher = tsoutil.NewTSODispatcher(tsoProxyHandleDuration, tsoProxyBatchSize, s.getTSOProxyBatchPolicy)
 	s.tsoProtoFactory = &tsoutil.TSOProtoFactory{}
 	s.pdProtoFactory = &tsoutil.PDProtoFactory{}
 	if !s.IsAPIServiceMode() {
 		s.tsoAllocatorManager = tso.NewAllocatorManager(s.ctx, mcs.DefaultKeyspaceGroupID, s.member, s.rootPath, s.storage, s, false)
+		s.clockDriftDetector = tso.NewClockDriftDetector(s.client, s.httpClient, s.member.Member().GetClientUrls())
+		s.tsoAllocatorManager.SetClockDriftDetector(s.clockDriftDetector)
 		// When disabled the Local TSO, we should clean up the Local TSO Allocator's meta info written in etcd if it exists.
 		if !s.cfg.EnableLocalTSO {
 			if err = s.tsoAllocatorManager.CleanUpDCLocation(); err != nil {
@@ -494,6 +515,7 @@ func (s *Server) startServer(ctx context.Context) error {
 	s.gcSafePointManager = gc.NewSafePointManager(s.storage, s.cfg.PDServerCfg)
 	s.basicCluster = core.NewBasicCluster()
 	s.cluster = cluster.NewRaftCluster(ctx, clusterID, s.GetBasicCluster(), s.GetStorage(), syncer.NewRegionSyncer(s), s.client, s.httpClient)
+	s.cluster.SetValidationManager(s.validation)
 	keyspaceIDAllocator := id.NewAllocator(&id.AllocatorParams{
 		Client:    s.client,
 		RootPath:  s.rootPath,
@@ -664,6 +686,9 @@ func (s *Server) startServerLoop(ctx context.Context) {
 	go s.etcdLeaderLoop()
 	go s.serverMetricsLoop()
 	go s.encryptionKeyManagerLoop()
+	if s.clockDriftDetector != nil {
+		s.clockDriftDetector.StartCheckLoop(s.serverLoopCtx, &s.serverLoopWg)
+	}
 	if s.IsAPIServiceMode() {
 		s.initTSOPrimaryWatcher()
 		s.initSchedulingPrimaryWatcher()
@@ -915,6 +940,22 @@ func (s *Server) GetTSOAllocatorManager() *tso.AllocatorManager {
 	return s.tsoAllocatorManager
 }
 
+// GetTSOComponentAllocations returns the TSO allocation counts of the calling components.
+func (s *Server) GetTSOComponentAllocations() []*tso.ComponentAllocation {
+	return s.tsoComponentAllocations.GetAll()
+}
+
+// GetClients returns the gRPC clients which have sent requests to the server recently.
+func (s *Server) GetClients() []*ClientInfo {
+	return s.clientRegistry.getAll(time.Now())
+}
+
+// GetValidationManager returns the manager of the validators, which can be
+// used to register the in-process validators for the config and rule changes.
+func (s *Server) GetValidationManager() *validation.Manager {
+	return s.validation
+}
+
 // GetKeyspaceManager returns the keyspace manager of server.
 func (s *Server) GetKeyspaceManager() *keyspace.Manager {
 	return s.keyspaceManager
@@ -1006,6 +1047,9 @@ func (s *Server) SetKeyspaceConfig(cfg config.KeyspaceConfig) error {
 		return err
 	}
 	old := s.persistOptions.GetKeyspaceConfig()
+	if err := s.validation.Validate(validation.KindKeyspaceConfig, old, &cfg); err != nil {
+		return err
+	}
 	s.persistOptions.SetKeyspaceConfig(&cfg)
 	if err := s.persistOptions.Persist(s.storage); err != nil {
 		s.persistOptions.SetKeyspaceConfig(old)
@@ -1028,6 +1072,9 @@ func (s *Server) GetMicroServiceConfig() *config.MicroServiceConfig {
 // SetMicroServiceConfig sets the micro service config information.
 func (s *Server) SetMicroServiceConfig(cfg config.MicroServiceConfig) error {
 	old := s.persistOptions.GetMicroServiceConfig()
+	if err := s.validation.Validate(validation.KindMicroServiceConfig, old, &cfg); err != nil {
+		return err
+	}
 	s.persistOptions.SetMicroServiceConfig(&cfg)
 	if err := s.persistOptions.Persist(s.storage); err != nil {
 		s.persistOptions.SetMicroServiceConfig(old)
@@ -1056,7 +1103,13 @@ func (s *Server) SetScheduleConfig(cfg sc.ScheduleConfig) error {
 		return err
 	}
 	old := s.persistOptions.GetScheduleConfig()
+	if cfg.DecisionLogFile != old.DecisionLogFile {
+		return errors.New("decision-log-file can only be set in the config file")
+	}
 	cfg.SchedulersPayload = nil
+	if err := s.validation.Validate(validation.KindScheduleConfig, old, &cfg); err != nil {
+		return err
+	}
 	s.persistOptions.SetScheduleConfig(&cfg)
 	if err := s.persistOptions.Persist(s.storage); err != nil {
 		s.persistOptions.SetScheduleConfig(old)
@@ -1083,6 +1136,9 @@ func (s *Server) SetReplicationConfig(cfg sc.ReplicationConfig) error {
 		return err
 	}
 	old := s.persistOptions.GetReplicationConfig()
+	if err := s.validation.Validate(validation.KindReplicationConfig, old, &cfg); err != nil {
+		return err
+	}
 	if cfg.EnablePlacementRules != old.EnablePlacementRules {
 		rc := s.GetRaftCluster()
 		if rc == nil {
@@ -1141,7 +1197,7 @@ func (s *Server) SetReplicationConfig(cfg sc.ReplicationConfig) error {
 		if rc == nil {
 			return errs.ErrNotBootstrapped.GenWithStackByArgs()
 		}
-		if err := rc.GetRuleManager().SetRule(rule); err != nil {
+		if err := rc.GetRuleManager().SetRulesWithoutValidation([]*placement.Rule{rule}); err != nil {
 			log.Error("failed to update rule count",
 				errs.ZapError(err))
 			return err
@@ -1157,7 +1213,7 @@ func (s *Server) SetReplicationConfig(cfg sc.ReplicationConfig) error {
 			if rc == nil {
 				return errs.ErrNotBootstrapped.GenWithStackByArgs()
 			}
-			if e := rc.GetRuleManager().SetRule(rule); e != nil {
+			if e := rc.GetRuleManager().SetRulesWithoutValidation([]*placement.Rule{rule}); e != nil {
 				log.Error("failed to roll back count of rule when update replication config", errs.ZapError(e))
 			}
 		}
@@ -1313,6 +1369,9 @@ func (s *Server) SetPDServerConfig(cfg config.PDServerConfig) error {
 	}
 
 	old := s.persistOptions.GetPDServerConfig()
+	if err := s.validation.Validate(validation.KindPDServerConfig, old, &cfg); err != nil {
+		return err
+	}
 	s.persistOptions.SetPDServerConfig(&cfg)
 	if err := s.persistOptions.Persist(s.storage); err != nil {
 		s.persistOptions.SetPDServerConfig(old)
@@ -1322,6 +1381,7 @@ func (s *Server) SetPDServerConfig(cfg config.PDServerConfig) error {
 			errs.ZapError(err))
 		return err
 	}
+	etcdutil.SetSlowOpThreshold(cfg.SlowEtcdOpThreshold.Duration)
 	log.Info("PD server config is updated", zap.Reflect("new", cfg), zap.Reflect("old", old))
 	return nil
 }
@@ -1421,6 +1481,11 @@ func (s *Server) GetControllerConfig() *rm_server.ControllerConfig {
 	return &s.cfg.Controller
 }
 
+// GetMeteringConfig gets the resource manager metering config.
+func (s *Server) GetMeteringConfig() *rm_server.MeteringConfig {
+	return &s.cfg.Metering
+}
+
 // GetRaftCluster gets Raft cluster.
 // If cluster has not been bootstrapped, return nil.
 func (s *Server) GetRaftCluster() *cluster.RaftCluster {
@@ -1586,6 +1651,9 @@ func (s *Server) SetReplicationModeConfig(cfg config.ReplicationModeConfig) erro
 	}
 
 	old := s.persistOptions.GetReplicationModeConfig()
+	if err := s.validation.Validate(validation.KindReplicationModeConfig, old, &cfg); err != nil {
+		return err
+	}
 	s.persistOptions.SetReplicationModeConfig(&cfg)
 	if err := s.persistOptions.Persist(s.storage); err != nil {
 		s.persistOptions.SetReplicationModeConfig(old)
@@ -1876,6 +1944,7 @@ func (s *Server) reloadConfigFromKV() error {
 	s.loadRateLimitConfig()
 	s.loadGRPCRateLimitConfig()
 	s.loadKeyspaceConfig()
+	etcdutil.SetSlowOpThreshold(s.persistOptions.GetSlowEtcdOpThreshold())
 	useRegionStorage := s.persistOptions.IsUseRegionStorage()
 	regionStorage := storage.TrySwitchRegionStorage(s.storage, useRegionStorage)
 	if regionStorage != nil {
@@ -2143,6 +2212,25 @@ func (s *Server) GetTSOProxyRecvFromClientTimeout() time.Duration {
 	return s.cfg.GetTSOProxyRecvFromClientTimeout()
 }
 
+// getTSOProxyBatchPolicy returns the policy to batch the TSO requests forwarded to the
+// keyspace group, the config of the server is capped by the one of the keyspace group.
+func (s *Server) getTSOProxyBatchPolicy(keyspaceGroupID uint32) tsoutil.BatchPolicy {
+	policy := tsoutil.BatchPolicy{
+		MaxBatchWait: s.cfg.GetTSOProxyMaxBatchWait(),
+		MaxBatchSize: s.cfg.GetTSOProxyMaxBatchSize(),
+	}
+	if s.keyspaceGroupManager == nil {
+		return policy
+	}
+	group, err := s.keyspaceGroupManager.GetKeyspaceGroupByID(keyspaceGroupID)
+	if err != nil {
+		log.Warn("failed to get the keyspace group to batch the tso requests",
+			zap.Uint32("keyspace-group-id", keyspaceGroupID), errs.ZapError(err))
+		return policy
+	}
+	return policy.Cap(group.GetTSOBatchCaps())
+}
+
 // GetLeaderLease returns the leader lease.
 func (s *Server) GetLeaderLease() int64 {
 	return s.cfg.GetLeaderLease()
@@ -2158,6 +2246,11 @@ func (s *Server) GetTSOUpdatePhysicalInterval() time.Duration {
 	return s.cfg.GetTSOUpdatePhysicalInterval()
 }
 
+// GetMaxClockDrift returns the max drift of the local clock.
+func (s *Server) GetMaxClockDrift() time.Duration {
+	return s.cfg.GetMaxClockDrift()
+}
+
 // GetMaxResetTSGap gets the max gap to reset the tso.
 func (s *Server) GetMaxResetTSGap() time.Duration {
 	return s.persistOptions.GetMaxResetTSGap()
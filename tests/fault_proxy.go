@@ -0,0 +1,227 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const faultProxyBufferSize = 32 * 1024
+
+// faultProxy is a TCP proxy between a server and its backend, which can drop,
+// freeze or delay the traffic to simulate the network faults.
+type faultProxy struct {
+	listener net.Listener
+	target   string
+
+	mu          sync.Mutex
+	conns       map[net.Conn]struct{}
+	partitioned bool
+	pausedUntil time.Time
+	latency     time.Duration
+	closed      bool
+}
+
+func newFaultProxy(target string) (*faultProxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &faultProxy{
+		listener: listener,
+		target:   target,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	go p.serve()
+	return p, nil
+}
+
+// newFaultProxies starts a proxy for each of the comma-separated endpoints,
+// and returns the proxies with the endpoints pointing to them.
+func newFaultProxies(endpoints string) ([]*faultProxy, string, error) {
+	var (
+		proxies   []*faultProxy
+		proxyURLs []string
+	)
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		u, err := url.Parse(strings.TrimSpace(endpoint))
+		if err != nil {
+			closeFaultProxies(proxies)
+			return nil, "", err
+		}
+		p, err := newFaultProxy(u.Host)
+		if err != nil {
+			closeFaultProxies(proxies)
+			return nil, "", err
+		}
+		proxies = append(proxies, p)
+		u.Host = p.listener.Addr().String()
+		proxyURLs = append(proxyURLs, u.String())
+	}
+	return proxies, strings.Join(proxyURLs, ","), nil
+}
+
+func closeFaultProxies(proxies []*faultProxy) {
+	for _, p := range proxies {
+		p.close()
+	}
+}
+
+func (p *faultProxy) serve() {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+		go p.handle(conn)
+	}
+}
+
+func (p *faultProxy) handle(conn net.Conn) {
+	if !p.track(conn) {
+		conn.Close()
+		return
+	}
+	backend, err := net.Dial("tcp", p.target)
+	if err != nil {
+		p.untrack(conn)
+		return
+	}
+	if !p.track(backend) {
+		p.untrack(conn)
+		backend.Close()
+		return
+	}
+	go p.pipe(backend, conn)
+	go p.pipe(conn, backend)
+}
+
+// pipe copies the data from src to dst until any of them is closed.
+func (p *faultProxy) pipe(dst, src net.Conn) {
+	defer func() {
+		p.untrack(src)
+		p.untrack(dst)
+	}()
+	buf := make([]byte, faultProxyBufferSize)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if !p.wait() {
+				return
+			}
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// wait blocks the traffic while the proxy is paused, and delays it by the
+// latency. It returns false if the traffic should be dropped.
+func (p *faultProxy) wait() bool {
+	for {
+		p.mu.Lock()
+		partitioned, pausedUntil, latency := p.partitioned || p.closed, p.pausedUntil, p.latency
+		p.mu.Unlock()
+		if partitioned {
+			return false
+		}
+		if pause := time.Until(pausedUntil); pause > 0 {
+			time.Sleep(pause)
+			continue
+		}
+		if latency > 0 {
+			time.Sleep(latency)
+		}
+		return true
+	}
+}
+
+// track records the connection, it returns false if the proxy doesn't accept
+// the new connections.
+func (p *faultProxy) track(conn net.Conn) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.partitioned || p.closed {
+		return false
+	}
+	p.conns[conn] = struct{}{}
+	return true
+}
+
+func (p *faultProxy) untrack(conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.conns, conn)
+	conn.Close()
+}
+
+// activeConns returns the number of the connections being proxied.
+func (p *faultProxy) activeConns() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// each connection is tracked on both sides of the proxy.
+	return len(p.conns) / 2
+}
+
+// closeConnsLocked closes all the connections, it should be called with the lock held.
+func (p *faultProxy) closeConnsLocked() {
+	for conn := range p.conns {
+		conn.Close()
+		delete(p.conns, conn)
+	}
+}
+
+// partition drops all the traffic and refuses the new connections until it's healed.
+func (p *faultProxy) partition(partitioned bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.partitioned = partitioned
+	if partitioned {
+		p.closeConnsLocked()
+	}
+}
+
+// pause freezes the traffic for the given duration without closing the connections.
+func (p *faultProxy) pause(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pausedUntil = time.Now().Add(d)
+}
+
+// setLatency delays each piece of the traffic by the given duration, 0 means no delay.
+func (p *faultProxy) setLatency(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.latency = d
+}
+
+func (p *faultProxy) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	p.listener.Close()
+	p.closeConnsLocked()
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/stretchr/testify/suite"
 	pd "github.com/tikv/pd/client"
 	clierrs "github.com/tikv/pd/client/errs"
+	"github.com/tikv/pd/client/grpcutil"
 	"github.com/tikv/pd/client/retry"
 	"github.com/tikv/pd/pkg/core"
 	"github.com/tikv/pd/pkg/errs"
@@ -981,6 +982,62 @@ func (suite *followerForwardAndHandleTestSuite) TestGetRegionFromFollower() {
 	re.NoError(failpoint.Disable("github.com/tikv/pd/client/fastCheckAvailable"))
 }
 
+func (suite *followerForwardAndHandleTestSuite) TestGetRegionFromFollowerWithMaxStaleness() {
+	re := suite.Require()
+	ctx, cancel := context.WithCancel(suite.ctx)
+	defer cancel()
+
+	cluster := suite.cluster
+	cli := setupCli(ctx, re, suite.endpoints)
+	defer cli.Close()
+	cli.UpdateOption(pd.EnableFollowerHandle, true)
+	re.NotEmpty(cluster.WaitLeader())
+	leader := cluster.GetLeaderServer()
+	testutil.Eventually(re, func() bool {
+		for _, s := range cluster.GetServers() {
+			if !s.IsLeader() && !s.GetServer().DirectlyGetRaftCluster().GetRegionSyncer().IsRunning() {
+				return false
+			}
+		}
+		return true
+	})
+
+	// make network problem for leader, so the requests can only be served by the followers.
+	re.NoError(failpoint.Enable("github.com/tikv/pd/client/unreachableNetwork1", fmt.Sprintf("return(\"%s\")", leader.GetAddr())))
+	time.Sleep(150 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		resp, err := cli.GetRegion(ctx, []byte("a"), pd.WithMaxStaleness(time.Hour))
+		re.NoError(err)
+		re.Equal(suite.regionID, resp.Meta.Id)
+	}
+	re.NoError(failpoint.Disable("github.com/tikv/pd/client/unreachableNetwork1"))
+
+	// the follower rejects the requests with the bound it can't meet.
+	follower := cluster.GetServer(cluster.GetFollower())
+	grpcPDClient := testutil.MustNewGrpcClient(re, follower.GetAddr())
+	getRegion := func(maxStaleness time.Duration) *pdpb.GetRegionResponse {
+		cctx := grpcutil.BuildFollowerHandleContext(ctx)
+		cctx = grpcutil.BuildMaxStalenessContext(cctx, maxStaleness)
+		resp, err := grpcPDClient.GetRegion(cctx, &pdpb.GetRegionRequest{
+			Header:    testutil.NewRequestHeader(leader.GetClusterID()),
+			RegionKey: []byte("a"),
+		})
+		re.NoError(err)
+		return resp
+	}
+	resp := getRegion(time.Hour)
+	re.Nil(resp.GetHeader().GetError())
+	re.Equal(suite.regionID, resp.GetRegion().GetId())
+	resp = getRegion(time.Millisecond)
+	re.Contains(resp.GetHeader().GetError().GetMessage(), "stale")
+	re.Nil(resp.GetRegion())
+
+	// the client retries the rejected requests on the leader.
+	resp2, err := cli.GetRegion(ctx, []byte("a"), pd.WithMaxStaleness(time.Millisecond))
+	re.NoError(err)
+	re.Equal(suite.regionID, resp2.Meta.Id)
+}
+
 func (suite *followerForwardAndHandleTestSuite) TestGetTSFuture() {
 	re := suite.Require()
 	ctx, cancel := context.WithCancel(suite.ctx)
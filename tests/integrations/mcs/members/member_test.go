@@ -18,12 +18,15 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	pdClient "github.com/tikv/pd/client/http"
 	bs "github.com/tikv/pd/pkg/basicserver"
 	"github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/utils/tempurl"
 	"github.com/tikv/pd/pkg/utils/testutil"
+	"github.com/tikv/pd/pkg/versioninfo"
+	"github.com/tikv/pd/server/apiv2/handlers"
 	"github.com/tikv/pd/tests"
 )
 
@@ -113,3 +116,66 @@ func (suite *memberTestSuite) TestPrimary() {
 	re.NoError(err)
 	re.NotEmpty(primary)
 }
+
+func (suite *memberTestSuite) TestHandshake() {
+	re := suite.Require()
+	tsoMembers, err := suite.pdClient.GetMicroServiceMembers(suite.ctx, utils.TSOServiceName)
+	re.NoError(err)
+	schedulingMembers, err := suite.pdClient.GetMicroServiceMembers(suite.ctx, utils.SchedulingServiceName)
+	re.NoError(err)
+	testutil.Eventually(re, func() bool {
+		return suite.server.GetServer().IsServiceIndependent(utils.SchedulingServiceName)
+	})
+
+	resp := readHandshake(re, suite.backendEndpoints)
+	re.Equal("api", resp.Mode)
+	re.Equal(handlers.HandshakeProtocolVersion, resp.ProtocolVersion)
+	re.Len(resp.Services, 3)
+	services := make(map[string]handlers.ServiceDeployment)
+	for _, service := range resp.Services {
+		services[service.Name] = service
+	}
+
+	checkEndpoints := func(service handlers.ServiceDeployment, members []pdClient.MicroServiceMember) {
+		re.True(service.Independent)
+		expected := make([]handlers.ServiceEndpoint, 0, len(members))
+		for _, member := range members {
+			expected = append(expected, handlers.ServiceEndpoint{Addr: member.ServiceAddr, Version: member.Version})
+		}
+		re.ElementsMatch(expected, service.Endpoints)
+	}
+	checkEndpoints(services[utils.TSOServiceName], tsoMembers)
+	checkEndpoints(services[utils.SchedulingServiceName], schedulingMembers)
+
+	// the resource manager is not split out, so it is still provided by PD.
+	rm := services[utils.ResourceManagerServiceName]
+	re.False(rm.Independent)
+	re.Equal([]handlers.ServiceEndpoint{{Addr: suite.backendEndpoints, Version: versioninfo.PDReleaseVersion}}, rm.Endpoints)
+}
+
+func TestHandshakeInPDMode(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := tests.NewTestCluster(ctx, 1)
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.RunInitialServers())
+	re.NotEmpty(cluster.WaitLeader())
+	addr := cluster.GetLeaderServer().GetAddr()
+
+	resp := readHandshake(re, addr)
+	re.Equal("pd", resp.Mode)
+	re.Equal(handlers.HandshakeProtocolVersion, resp.ProtocolVersion)
+	re.Len(resp.Services, 3)
+	for _, service := range resp.Services {
+		re.False(service.Independent)
+		re.Equal([]handlers.ServiceEndpoint{{Addr: addr, Version: versioninfo.PDReleaseVersion}}, service.Endpoints)
+	}
+}
+
+func readHandshake(re *require.Assertions, addr string) *handlers.HandshakeResponse {
+	resp := &handlers.HandshakeResponse{}
+	re.NoError(testutil.ReadGetJSON(re, tests.TestDialClient, addr+"/pd/api/v2/ms/handshake", resp))
+	return resp
+}
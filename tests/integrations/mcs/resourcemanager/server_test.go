@@ -27,6 +27,7 @@ import (
 	"github.com/tikv/pd/client/grpcutil"
 	bs "github.com/tikv/pd/pkg/basicserver"
 	"github.com/tikv/pd/pkg/utils/tempurl"
+	"github.com/tikv/pd/pkg/utils/testutil"
 	"github.com/tikv/pd/pkg/versioninfo"
 	"github.com/tikv/pd/tests"
 )
@@ -122,3 +123,41 @@ func TestResourceManagerServer(t *testing.T) {
 		re.Equal(versioninfo.PDReleaseVersion, s.Version)
 	}
 }
+
+func TestResourceManagerPrimaryChange(t *testing.T) {
+	re := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := tests.NewTestAPICluster(ctx, 1)
+	defer cluster.Destroy()
+	re.NoError(err)
+	re.NoError(cluster.RunInitialServers())
+	leader := cluster.GetServer(cluster.WaitLeader())
+
+	tc, err := tests.NewTestResourceManagerCluster(ctx, re, 2, leader.GetAddr())
+	re.NoError(err)
+	defer tc.Destroy()
+	tc.WaitForPrimaryServing(re)
+	re.NoError(tc.ResignPrimary())
+	// one of the servers is elected as the primary again.
+	primary := tc.WaitForPrimaryServing(re)
+
+	group, err := json.Marshal(&rmpb.ResourceGroup{Name: "pingcap", Mode: 1})
+	re.NoError(err)
+	err = testutil.CheckPostJSON(tests.TestDialClient, primary.GetAddr()+"/resource-manager/api/v1/config/group",
+		group, testutil.StatusOK(re))
+	re.NoError(err)
+	// the other server takes over after the primary is destroyed, and the group is kept.
+	tc.DestroyServer(primary.GetAddr())
+	newPrimary := tc.WaitForPrimaryServing(re)
+	re.NotEqual(primary.GetAddr(), newPrimary.GetAddr())
+	testutil.Eventually(re, func() bool {
+		resp, err := tests.TestDialClient.Get(newPrimary.GetAddr() + "/resource-manager/api/v1/config/group/pingcap")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"testing"
 	"time"
 
@@ -681,6 +682,15 @@ func (suite *apiTestSuite) checkStores(cluster *tests.TestCluster) {
 	urlPrefix = fmt.Sprintf("%s/scheduling/api/v1/stores/233", scheServerAddr)
 	testutil.CheckGetJSON(tests.TestDialClient, urlPrefix, nil,
 		testutil.Status(re, http.StatusNotFound), testutil.StringContain(re, "not found"))
+	// Test the compatible apiv1 paths of the scheduling server.
+	urlPrefix = fmt.Sprintf("%s/pd/api/v1/stores", scheServerAddr)
+	err = testutil.ReadGetJSON(re, tests.TestDialClient, urlPrefix, &resp)
+	re.NoError(err)
+	re.Equal(3, int(resp["count"].(float64)))
+	urlPrefix = fmt.Sprintf("%s/pd/api/v1/store/1", scheServerAddr)
+	err = testutil.ReadGetJSON(re, tests.TestDialClient, urlPrefix, &resp)
+	re.NoError(err)
+	re.Equal("tikv1", resp["store"].(map[string]any)["address"])
 }
 
 func (suite *apiTestSuite) TestRegions() {
@@ -719,4 +729,80 @@ func (suite *apiTestSuite) checkRegions(cluster *tests.TestCluster) {
 	urlPrefix = fmt.Sprintf("%s/scheduling/api/v1/regions/233", scheServerAddr)
 	testutil.CheckGetJSON(tests.TestDialClient, urlPrefix, nil,
 		testutil.Status(re, http.StatusNotFound), testutil.StringContain(re, "not found"))
+	// Test the compatible apiv1 paths of the scheduling server.
+	urlPrefix = fmt.Sprintf("%s/pd/api/v1/regions", scheServerAddr)
+	err = testutil.ReadGetJSON(re, tests.TestDialClient, urlPrefix, &resp)
+	re.NoError(err)
+	re.Equal(3, int(resp["count"].(float64)))
+	urlPrefix = fmt.Sprintf("%s/pd/api/v1/region/id/1", scheServerAddr)
+	err = testutil.ReadGetJSON(re, tests.TestDialClient, urlPrefix, &resp)
+	re.NoError(err)
+	re.Equal(key, resp["start_key"])
+	urlPrefix = fmt.Sprintf("%s/pd/api/v1/region/key/%s?format=hex", scheServerAddr, fmt.Sprintf("%x", "c"))
+	err = testutil.ReadGetJSON(re, tests.TestDialClient, urlPrefix, &resp)
+	re.NoError(err)
+	re.Equal(2., resp["id"])
+	var ops []any
+	urlPrefix = fmt.Sprintf("%s/pd/api/v1/operators", scheServerAddr)
+	err = testutil.ReadGetJSON(re, tests.TestDialClient, urlPrefix, &ops)
+	re.NoError(err)
+}
+
+func (suite *apiTestSuite) TestZoneQuorum() {
+	suite.env.RunTestInAPIMode(suite.checkZoneQuorum)
+}
+
+func (suite *apiTestSuite) checkZoneQuorum(cluster *tests.TestCluster) {
+	re := suite.Require()
+	leaderAddr := cluster.GetLeaderServer().GetAddr()
+	reqData, err := json.Marshal(map[string]any{
+		"location-labels": "zone",
+	})
+	re.NoError(err)
+	err = testutil.CheckPostJSON(tests.TestDialClient, fmt.Sprintf("%s/pd/api/v1/config", leaderAddr), reqData, testutil.StatusOK(re))
+	re.NoError(err)
+	sche := cluster.GetSchedulingPrimaryServer()
+	testutil.Eventually(re, func() bool {
+		return slice.Contains(sche.GetPersistConfig().GetLocationLabels(), "zone")
+	})
+
+	zones := []string{"z1", "z1", "z2"}
+	peers := make([]*metapb.Peer, 0, len(zones))
+	for i, zone := range zones {
+		storeID := uint64(101 + i)
+		tests.MustPutStore(re, cluster, &metapb.Store{
+			Id:        storeID,
+			Address:   fmt.Sprintf("tikv%d", storeID),
+			State:     metapb.StoreState_Up,
+			NodeState: metapb.NodeState_Serving,
+			Version:   "2.0.0",
+			Labels:    []*metapb.StoreLabel{{Key: "zone", Value: zone}},
+		})
+		peers = append(peers, &metapb.Peer{Id: 1000 + storeID, StoreId: storeID})
+	}
+	region := core.NewRegionInfo(&metapb.Region{
+		Id:          1000,
+		StartKey:    []byte("x"),
+		EndKey:      []byte("y"),
+		Peers:       peers,
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+	}, peers[0], core.SetSource(core.Heartbeat))
+	tests.MustPutRegionInfo(re, cluster, region)
+
+	// The zone accounting is done by the scheduling server, the request to PD
+	// should be forwarded to it.
+	expected := map[string]int{"z1": 1}
+	var resp map[string]int
+	testutil.Eventually(re, func() bool {
+		err := testutil.ReadGetJSON(re, tests.TestDialClient,
+			fmt.Sprintf("%s/scheduling/api/v1/stats/zone-quorum", sche.GetAddr()), &resp)
+		re.NoError(err)
+		return reflect.DeepEqual(expected, resp)
+	})
+	re.Empty(cluster.GetLeaderServer().GetRaftCluster().GetZoneQuorumStats().GetZoneQuorumLossCount())
+	resp = nil
+	err = testutil.ReadGetJSON(re, tests.TestDialClient, fmt.Sprintf("%s/pd/api/v1/stats/zone-quorum", leaderAddr), &resp,
+		testutil.WithHeader(re, apiutil.XForwardedToMicroServiceHeader, "true"))
+	re.NoError(err)
+	re.Equal(expected, resp)
 }
@@ -67,6 +67,46 @@ func (suite *metaTestSuite) TearDownSuite() {
 	suite.cluster.Destroy()
 }
 
+type slowStartConfig struct {
+	window time.Duration
+}
+
+func (c slowStartConfig) GetStoreSlowStartWindow() time.Duration {
+	return c.window
+}
+
+func (suite *metaTestSuite) TestStoreSlowStart() {
+	re := suite.Require()
+	rc := suite.pdLeaderServer.GetServer().GetRaftCluster()
+	newStore := func(id uint64) *metapb.Store {
+		return &metapb.Store{Id: id, Address: fmt.Sprintf("mock-%d", id), State: metapb.StoreState_Up, NodeState: metapb.NodeState_Serving}
+	}
+	re.NoError(rc.PutMetaStore(newStore(3)))
+
+	cfg := slowStartConfig{window: time.Hour}
+	cluster := core.NewBasicCluster()
+	_, err := meta.NewWatcher(suite.ctx, suite.pdLeaderServer.GetEtcdClient(), suite.cluster.GetCluster().GetId(), cluster, cfg)
+	re.NoError(err)
+	// The existing store is not a new one.
+	re.NotNil(cluster.GetStore(3))
+	re.False(cluster.GetStore(3).IsSlowStarting())
+	// The store registered after the watcher starts is a new one.
+	re.NoError(rc.PutMetaStore(newStore(4)))
+	testutil.Eventually(re, func() bool {
+		store := cluster.GetStore(4)
+		return store != nil && store.IsSlowStarting()
+	})
+
+	// Restart the watcher, none of the stores is new.
+	cluster = core.NewBasicCluster()
+	_, err = meta.NewWatcher(suite.ctx, suite.pdLeaderServer.GetEtcdClient(), suite.cluster.GetCluster().GetId(), cluster, cfg)
+	re.NoError(err)
+	for _, id := range []uint64{3, 4} {
+		re.NotNil(cluster.GetStore(id))
+		re.False(cluster.GetStore(id).IsSlowStarting())
+	}
+}
+
 func (suite *metaTestSuite) TestStoreWatch() {
 	re := suite.Require()
 
@@ -77,6 +117,7 @@ func (suite *metaTestSuite) TestStoreWatch() {
 		suite.pdLeaderServer.GetEtcdClient(),
 		suite.cluster.GetCluster().GetId(),
 		cluster,
+		slowStartConfig{},
 	)
 	re.NoError(err)
 	for i := uint64(1); i <= 4; i++ {
@@ -112,4 +153,19 @@ func (suite *metaTestSuite) TestStoreWatch() {
 		}
 		return cluster.GetStore(5).GetLabels()[0].GetValue() == "z1"
 	})
+
+	// test synchronized compaction stats
+	rc := suite.pdLeaderServer.GetRaftCluster()
+	re.NoError(rc.UpdateCompactionStats(5, &core.CompactionStats{Level0FileCount: 100, ReportTime: time.Now()}))
+	testutil.Eventually(re, func() bool {
+		stats := cluster.GetStore(5).GetCompactionStats()
+		return stats != nil && stats.Level0FileCount == 100
+	})
+	rc.PutMetaStore(
+		&metapb.Store{Id: 5, Address: "mock-5", State: metapb.StoreState_Up, NodeState: metapb.NodeState_Serving, LastHeartbeat: time.Now().UnixNano(), Labels: []*metapb.StoreLabel{{Key: "zone", Value: "z2"}}},
+	)
+	testutil.Eventually(re, func() bool {
+		return cluster.GetStore(5).GetLabels()[0].GetValue() == "z2"
+	})
+	re.NotNil(cluster.GetStore(5).GetCompactionStats())
 }
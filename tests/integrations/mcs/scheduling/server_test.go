@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/tikv/pd/pkg/core/storelimit"
+	"github.com/tikv/pd/pkg/mcs/discovery"
 	mcs "github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/schedule/operator"
 	"github.com/tikv/pd/pkg/schedule/schedulers"
@@ -158,6 +160,52 @@ func (suite *serverTestSuite) TestPrimaryChange() {
 	})
 }
 
+func (suite *serverTestSuite) TestResignPrimary() {
+	re := suite.Require()
+	tc, err := tests.NewTestSchedulingCluster(suite.ctx, 2, suite.backendEndpoints)
+	re.NoError(err)
+	defer tc.Destroy()
+	tc.WaitForPrimaryServing(re)
+	re.NoError(tc.ResignPrimary())
+	// one of the servers is elected as the primary again.
+	primary := tc.WaitForPrimaryServing(re)
+	testutil.Eventually(re, func() bool {
+		watchedAddr, ok := suite.pdLeader.GetServicePrimaryAddr(suite.ctx, mcs.SchedulingServiceName)
+		return ok && primary.GetAddr() == watchedAddr
+	})
+	// the other server takes over after the primary is destroyed.
+	tc.DestroyServer(primary.GetAddr())
+	re.NotEqual(primary.GetAddr(), tc.WaitForPrimaryServing(re).GetAddr())
+}
+
+func (suite *serverTestSuite) TestGracefulShutdown() {
+	re := suite.Require()
+	tc, err := tests.NewTestSchedulingCluster(suite.ctx, 2, suite.backendEndpoints)
+	re.NoError(err)
+	defer tc.Destroy()
+	tc.WaitForPrimaryServing(re)
+	primary := tc.GetPrimaryServer()
+	oldPrimaryAddr := primary.GetAddr()
+
+	err = testutil.CheckPostJSON(tests.TestDialClient, oldPrimaryAddr+"/scheduling/api/v1/admin/shutdown", nil,
+		testutil.StatusOK(re), testutil.StringContain(re, "shutting down"))
+	re.NoError(err)
+	select {
+	case <-primary.ShutdownRequested():
+	default:
+		re.FailNow("the shutdown is not requested")
+	}
+
+	// the server is deregistered and the primary is taken over by the other one.
+	primary.Close()
+	clusterID := strconv.FormatUint(suite.pdLeader.GetClusterID(), 10)
+	addrs, err := discovery.Discover(suite.pdLeader.GetEtcdClient(), clusterID, mcs.SchedulingServiceName)
+	re.NoError(err)
+	re.NotContains(addrs, oldPrimaryAddr)
+	tc.WaitForPrimaryServing(re)
+	re.NotEqual(oldPrimaryAddr, tc.GetPrimaryServer().GetAddr())
+}
+
 func (suite *serverTestSuite) TestForwardStoreHeartbeat() {
 	re := suite.Require()
 	tc, err := tests.NewTestSchedulingCluster(suite.ctx, 1, suite.backendEndpoints)
@@ -751,3 +799,35 @@ func (suite *serverTestSuite) TestOnlineProgress() {
 	suite.TearDownSuite()
 	suite.SetupSuite()
 }
+
+func TestSchedulingServerWithTLS(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tlsCfg, err := tests.GenerateTestTLSConfig(t.TempDir())
+	re.NoError(err)
+
+	cluster, err := tests.NewTestAPICluster(ctx, 1, tests.WithTLS(tlsCfg))
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.RunInitialServers())
+	leaderName := cluster.WaitLeader()
+	re.NotEmpty(leaderName)
+	pdLeader := cluster.GetServer(leaderName)
+	re.NoError(pdLeader.BootstrapCluster())
+
+	tc, err := tests.NewTestSchedulingClusterWithTLS(ctx, 1, pdLeader.GetAddr(), tlsCfg)
+	re.NoError(err)
+	defer tc.Destroy()
+	primary := tc.WaitForPrimaryServing(re)
+	re.True(primary.IsSecure())
+	testutil.Eventually(re, func() bool {
+		watchedAddr, ok := pdLeader.GetServicePrimaryAddr(ctx, mcs.SchedulingServiceName)
+		return ok && watchedAddr == primary.GetAddr()
+	})
+	// the primary can talk to the API server leader with TLS.
+	testutil.Eventually(re, func() bool {
+		id, err := primary.GetCluster().AllocID()
+		return err == nil && id > 0
+	})
+}
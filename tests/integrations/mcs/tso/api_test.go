@@ -27,10 +27,12 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	pd "github.com/tikv/pd/client"
 	tso "github.com/tikv/pd/pkg/mcs/tso/server"
 	apis "github.com/tikv/pd/pkg/mcs/tso/server/apis/v1"
 	mcsutils "github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/storage/endpoint"
+	pdtso "github.com/tikv/pd/pkg/tso"
 	"github.com/tikv/pd/pkg/utils/apiutil"
 	"github.com/tikv/pd/pkg/utils/testutil"
 	"github.com/tikv/pd/pkg/versioninfo"
@@ -262,6 +264,86 @@ func (suite *tsoAPITestSuite) TestStatus() {
 	re.Equal(versioninfo.PDReleaseVersion, s.Version)
 }
 
+func (suite *tsoAPITestSuite) TestHealth() {
+	re := suite.Require()
+
+	primary := suite.tsoCluster.WaitForDefaultPrimaryServing(re)
+	resp, err := tests.TestDialClient.Get(primary.GetConfig().GetAdvertiseListenAddr() + "/tso/api/v1/health")
+	re.NoError(err)
+	defer resp.Body.Close()
+	re.Equal(http.StatusOK, resp.StatusCode)
+	respBytes, err := io.ReadAll(resp.Body)
+	re.NoError(err)
+	var status string
+	re.NoError(json.Unmarshal(respBytes, &status))
+	re.Equal("ok", status)
+
+	resp, err = tests.TestDialClient.Get(primary.GetConfig().GetAdvertiseListenAddr() + "/tso/api/v1/health/detail")
+	re.NoError(err)
+	defer resp.Body.Close()
+	re.Equal(http.StatusOK, resp.StatusCode)
+	respBytes, err = io.ReadAll(resp.Body)
+	re.NoError(err)
+	var health apis.Health
+	re.NoError(json.Unmarshal(respBytes, &health))
+	re.True(health.BackendConnected)
+	defaultGroup := health.KeyspaceGroups[mcsutils.DefaultKeyspaceGroupID]
+	re.NotNil(defaultGroup)
+	re.True(defaultGroup.IsPrimary)
+	re.True(defaultGroup.PrimaryElected)
+	re.True(defaultGroup.Serving)
+	re.False(defaultGroup.LastSavedTime.IsZero())
+}
+
+func (suite *tsoAPITestSuite) TestResignPrimaries() {
+	re := suite.Require()
+
+	primary := suite.tsoCluster.WaitForDefaultPrimaryServing(re)
+	resp, err := tests.TestDialClient.Post(primary.GetConfig().GetAdvertiseListenAddr()+"/tso/api/v1/admin/resign-primaries", "application/json", nil)
+	re.NoError(err)
+	defer resp.Body.Close()
+	re.Equal(http.StatusOK, resp.StatusCode)
+	respBytes, err := io.ReadAll(resp.Body)
+	re.NoError(err)
+	var groupIDs []uint32
+	re.NoError(json.Unmarshal(respBytes, &groupIDs))
+	re.Equal([]uint32{mcsutils.DefaultKeyspaceGroupID}, groupIDs)
+	// The keyspace group elects the primary again.
+	re.NotNil(suite.tsoCluster.WaitForDefaultPrimaryServing(re))
+}
+
+func (suite *tsoAPITestSuite) TestComponentAllocations() {
+	re := suite.Require()
+
+	primary := suite.tsoCluster.WaitForDefaultPrimaryServing(re)
+	// the component is passed through the API server which forwards the TSO requests.
+	client, err := pd.NewClientWithContext(suite.ctx, []string{suite.backendEndpoints}, pd.SecurityOption{}, pd.WithComponent("cdc"))
+	re.NoError(err)
+	defer client.Close()
+	for i := 0; i < 3; i++ {
+		_, _, err = client.GetTS(suite.ctx)
+		re.NoError(err)
+	}
+
+	resp, err := tests.TestDialClient.Get(primary.GetConfig().GetAdvertiseListenAddr() + "/tso/api/v1/components")
+	re.NoError(err)
+	defer resp.Body.Close()
+	re.Equal(http.StatusOK, resp.StatusCode)
+	respBytes, err := io.ReadAll(resp.Body)
+	re.NoError(err)
+	var allocations []*pdtso.ComponentAllocation
+	re.NoError(json.Unmarshal(respBytes, &allocations))
+	var cdc *pdtso.ComponentAllocation
+	for _, allocation := range allocations {
+		if allocation.Component == "cdc" {
+			cdc = allocation
+		}
+	}
+	re.NotNil(cdc, string(respBytes))
+	re.GreaterOrEqual(cdc.Requests, uint64(1))
+	re.GreaterOrEqual(cdc.Timestamps, uint64(3))
+}
+
 func (suite *tsoAPITestSuite) TestConfig() {
 	re := suite.Require()
 
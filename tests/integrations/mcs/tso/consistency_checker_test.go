@@ -0,0 +1,142 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tso
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/stretchr/testify/require"
+	pd "github.com/tikv/pd/client"
+	clierrs "github.com/tikv/pd/client/errs"
+	"github.com/tikv/pd/pkg/utils/tsoutil"
+)
+
+// tsoConsistencyChecker drives several clients to request the TSO of the same
+// keyspaces concurrently, and checks the external consistency of the TSO: a
+// request issued after another one is finished must get a greater timestamp,
+// no matter which client or keyspace group primary serves it. The requests
+// which overlap with each other are not ordered.
+type tsoConsistencyChecker struct {
+	re     *require.Assertions
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	clients []pd.Client
+
+	mu sync.Mutex
+	// lastTS is the greatest timestamp finished of each keyspace.
+	lastTS map[uint32]*pdpb.Timestamp
+	// counts is the number of the timestamps checked of each keyspace.
+	counts     map[uint32]int
+	violations []string
+}
+
+func newTSOConsistencyChecker(ctx context.Context, re *require.Assertions) *tsoConsistencyChecker {
+	ctx, cancel := context.WithCancel(ctx)
+	return &tsoConsistencyChecker{
+		re:     re,
+		ctx:    ctx,
+		cancel: cancel,
+		lastTS: make(map[uint32]*pdpb.Timestamp),
+		counts: make(map[uint32]int),
+	}
+}
+
+// addClients starts `count` clients to request the TSO of the keyspace.
+func (c *tsoConsistencyChecker) addClients(keyspaceID uint32, count int, backendEndpoints []string) {
+	c.mu.Lock()
+	if _, ok := c.counts[keyspaceID]; !ok {
+		c.counts[keyspaceID] = 0
+	}
+	c.mu.Unlock()
+	for i := 0; i < count; i++ {
+		client, err := pd.NewClientWithKeyspace(c.ctx, keyspaceID, backendEndpoints, pd.SecurityOption{})
+		c.re.NoError(err)
+		c.re.NotNil(client)
+		c.clients = append(c.clients, client)
+		c.wg.Add(1)
+		go c.run(client, keyspaceID, i)
+	}
+}
+
+func (c *tsoConsistencyChecker) run(client pd.Client, keyspaceID uint32, clientIdx int) {
+	defer c.wg.Done()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		default:
+		}
+		// Any request finished before this one is issued happens before it.
+		c.mu.Lock()
+		before := c.lastTS[keyspaceID]
+		c.mu.Unlock()
+		physical, logical, err := client.GetTS(c.ctx)
+		if err != nil {
+			errMsg := err.Error()
+			// Ignore the errors caused by the split, merge and context cancellation.
+			if strings.Contains(errMsg, "context canceled") ||
+				strings.Contains(errMsg, clierrs.NotLeaderErr) ||
+				strings.Contains(errMsg, clierrs.NotServedErr) ||
+				strings.Contains(errMsg, "ErrKeyspaceNotAssigned") ||
+				strings.Contains(errMsg, "ErrKeyspaceGroupIsMerging") {
+				continue
+			}
+			c.addViolation(fmt.Sprintf("keyspace %d client %d: unexpected error: %s", keyspaceID, clientIdx, errMsg))
+			return
+		}
+		ts := &pdpb.Timestamp{Physical: physical, Logical: logical}
+		c.mu.Lock()
+		if before != nil && tsoutil.CompareTimestamp(ts, before) <= 0 {
+			c.violations = append(c.violations, fmt.Sprintf("keyspace %d client %d: got %v after %v",
+				keyspaceID, clientIdx, ts, before))
+		}
+		if last := c.lastTS[keyspaceID]; last == nil || tsoutil.CompareTimestamp(ts, last) > 0 {
+			c.lastTS[keyspaceID] = ts
+		}
+		c.counts[keyspaceID]++
+		c.mu.Unlock()
+	}
+}
+
+func (c *tsoConsistencyChecker) addViolation(violation string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.violations = append(c.violations, violation)
+}
+
+// stop stops the clients and checks that there is no causality violation.
+func (c *tsoConsistencyChecker) stop() {
+	// Wait for a while to make sure the clients have sent more TSO requests.
+	time.Sleep(time.Second)
+	c.cancel()
+	c.wg.Wait()
+	for _, client := range c.clients {
+		client.Close()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.re.Empty(c.violations)
+	// Every keyspace should get some timestamps checked.
+	for keyspaceID, count := range c.counts {
+		c.re.Positive(count, "keyspace %d", keyspaceID)
+	}
+}
@@ -19,18 +19,18 @@ import (
 	"math/rand"
 	"net/http"
 	"strings"
-	"sync"
 	"testing"
 	"time"
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/kvproto/pkg/tsopb"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	pd "github.com/tikv/pd/client"
-	clierrs "github.com/tikv/pd/client/errs"
 	"github.com/tikv/pd/pkg/election"
 	"github.com/tikv/pd/pkg/errs"
+	tso "github.com/tikv/pd/pkg/mcs/tso/server"
 	mcsutils "github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/member"
 	"github.com/tikv/pd/pkg/mock/mockid"
@@ -44,6 +44,8 @@ import (
 	"github.com/tikv/pd/tests"
 	"github.com/tikv/pd/tests/integrations/mcs"
 	handlersutil "github.com/tikv/pd/tests/server/apiv2/handlers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 type tsoKeyspaceGroupManagerTestSuite struct {
@@ -183,6 +185,7 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) TestKeyspacesServedByNonDefaultKe
 		{suite.allocID(), []uint32{2, 12}},
 	}
 
+	groups := make([]endpoint.KeyspaceGroup, 0, len(params))
 	for _, param := range params {
 		if param.keyspaceGroupID == 0 {
 			// we have already created default keyspace group, so we can skip it.
@@ -190,19 +193,11 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) TestKeyspacesServedByNonDefaultKe
 			// served by default keyspace group.
 			continue
 		}
-		handlersutil.MustCreateKeyspaceGroup(re, suite.pdLeaderServer, &handlers.CreateKeyspaceGroupParams{
-			KeyspaceGroups: []*endpoint.KeyspaceGroup{
-				{
-					ID:        param.keyspaceGroupID,
-					UserKind:  endpoint.Standard.String(),
-					Members:   suite.tsoCluster.GetKeyspaceGroupMember(),
-					Keyspaces: param.keyspaceIDs,
-				},
-			},
-		})
+		groups = append(groups, endpoint.KeyspaceGroup{ID: param.keyspaceGroupID, Keyspaces: param.keyspaceIDs})
 	}
+	suite.tsoCluster.BootstrapKeyspaceGroups(re, groups)
 
-	// Wait until all keyspace groups are ready.
+	// Check the keyspace groups are served with the right paths.
 	testutil.Eventually(re, func() bool {
 		for _, param := range params {
 			for _, keyspaceID := range param.keyspaceIDs {
@@ -316,6 +311,73 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) requestTSO(
 	return ts, err
 }
 
+func (suite *tsoKeyspaceGroupManagerTestSuite) TestForwardTSOMixedWithLocal() {
+	re := suite.Require()
+	id := suite.allocID()
+	handlersutil.MustCreateKeyspaceGroup(re, suite.pdLeaderServer, &handlers.CreateKeyspaceGroupParams{
+		KeyspaceGroups: []*endpoint.KeyspaceGroup{
+			{
+				ID:        id,
+				UserKind:  endpoint.Standard.String(),
+				Members:   suite.tsoCluster.GetKeyspaceGroupMember(),
+				Keyspaces: []uint32{111},
+			},
+		},
+	})
+	// Let the default keyspace group and the keyspace group `id` have different primaries.
+	var server *tso.Server
+	testutil.Eventually(re, func() bool {
+		defaultPrimary := suite.tsoCluster.GetPrimaryServer(mcsutils.DefaultKeyspaceID, mcsutils.DefaultKeyspaceGroupID)
+		primary := suite.tsoCluster.GetPrimaryServer(111, id)
+		if defaultPrimary == nil || primary == nil {
+			return false
+		}
+		if defaultPrimary == primary {
+			re.NoError(suite.tsoCluster.ResignPrimary(111, id))
+			return false
+		}
+		server = primary
+		return true
+	})
+
+	cc, err := grpc.DialContext(suite.ctx, strings.TrimPrefix(server.GetAddr(), "http://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	re.NoError(err)
+	defer cc.Close()
+	stream, err := tsopb.NewTSOClient(cc).Tso(suite.ctx)
+	re.NoError(err)
+	defer stream.CloseSend()
+	// The requests of the default keyspace group are forwarded to its primary while the ones
+	// of the keyspace group `id` are handled locally, they share one stream and the responses
+	// should come back in the order of the requests.
+	lastTS := make(map[uint32]uint64)
+	for i := 0; i < 100; i++ {
+		for _, keyspaceID := range []uint32{mcsutils.DefaultKeyspaceID, 111} {
+			keyspaceGroupID := mcsutils.DefaultKeyspaceGroupID
+			if keyspaceID == 111 {
+				keyspaceGroupID = id
+			}
+			re.NoError(stream.Send(&tsopb.TsoRequest{
+				Header: &tsopb.RequestHeader{
+					ClusterId:       server.ClusterID(),
+					KeyspaceId:      keyspaceID,
+					KeyspaceGroupId: keyspaceGroupID,
+				},
+				Count: 1,
+			}))
+		}
+		for _, keyspaceGroupID := range []uint32{mcsutils.DefaultKeyspaceGroupID, id} {
+			resp, err := stream.Recv()
+			re.NoError(err)
+			re.Nil(resp.GetHeader().GetError())
+			re.Equal(keyspaceGroupID, resp.GetHeader().GetKeyspaceGroupId())
+			ts := tsoutil.ComposeTS(resp.GetTimestamp().GetPhysical(), resp.GetTimestamp().GetLogical())
+			re.Greater(ts, lastTS[keyspaceGroupID])
+			lastTS[keyspaceGroupID] = ts
+		}
+	}
+}
+
 func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupSplitElection() {
 	re := suite.Require()
 	// Create the keyspace group `oldID` with keyspaces [111, 222, 333].
@@ -406,6 +468,8 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupSplitClient()
 	re := suite.Require()
 	// Enable the failpoint to slow down the system time to test whether the TSO is monotonic.
 	re.NoError(failpoint.Enable("github.com/tikv/pd/pkg/tso/systemTimeSlow", `return(true)`))
+	// Delay the timestamp sync of the new primary to widen the race window of the split.
+	re.NoError(failpoint.Enable("github.com/tikv/pd/pkg/tso/delaySyncTimestamp", `return(true)`))
 	// Create the keyspace group `oldID` with keyspaces [444, 555, 666].
 	oldID := suite.allocID()
 	handlersutil.MustCreateKeyspaceGroup(re, suite.pdLeaderServer, &handlers.CreateKeyspaceGroupParams{
@@ -422,8 +486,8 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupSplitClient()
 	re.Equal(oldID, kg1.ID)
 	re.Equal([]uint32{444, 555, 666}, kg1.Keyspaces)
 	re.False(kg1.IsSplitting())
-	// Request the TSO for keyspace 555 concurrently via client.
-	cancel := suite.dispatchClient(re, 555, oldID)
+	// Request the TSO for keyspaces 444 and 555 concurrently via clients.
+	cancel := suite.dispatchClient(re, oldID, 444, 555)
 	// Split the keyspace group `oldID` to `newID`.
 	newID := suite.allocID()
 	handlersutil.MustSplitKeyspaceGroup(re, suite.pdLeaderServer, oldID, &handlers.SplitKeyspaceGroupByIDParams{
@@ -434,65 +498,28 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupSplitClient()
 	waitFinishSplit(re, suite.pdLeaderServer, oldID, newID, []uint32{444}, []uint32{555, 666})
 	// Stop the client.
 	cancel()
+	re.NoError(failpoint.Disable("github.com/tikv/pd/pkg/tso/delaySyncTimestamp"))
 	re.NoError(failpoint.Disable("github.com/tikv/pd/pkg/tso/systemTimeSlow"))
 }
 
+// dispatchClient starts the clients to request the TSO of the keyspaces in
+// the keyspace group concurrently, and returns the function to stop them and
+// check the external consistency of the TSO.
 func (suite *tsoKeyspaceGroupManagerTestSuite) dispatchClient(
-	re *require.Assertions, keyspaceID, keyspaceGroupID uint32,
+	re *require.Assertions, keyspaceGroupID uint32, keyspaceIDs ...uint32,
 ) context.CancelFunc {
-	// Make sure the leader of the keyspace group is elected.
-	member, err := suite.tsoCluster.
-		WaitForPrimaryServing(re, keyspaceID, keyspaceGroupID).
-		GetMember(keyspaceID, keyspaceGroupID)
-	re.NoError(err)
-	re.NotNil(member)
-	// Prepare the client for keyspace.
-	tsoClient, err := pd.NewClientWithKeyspace(suite.ctx, keyspaceID, []string{suite.pdLeaderServer.GetAddr()}, pd.SecurityOption{})
-	re.NoError(err)
-	re.NotNil(tsoClient)
-	var (
-		wg                        sync.WaitGroup
-		ctx, cancel               = context.WithCancel(suite.ctx)
-		lastPhysical, lastLogical int64
-	)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
-			physical, logical, err := tsoClient.GetTS(ctx)
-			if err != nil {
-				errMsg := err.Error()
-				// Ignore the errors caused by the split and context cancellation.
-				if strings.Contains(errMsg, "context canceled") ||
-					strings.Contains(errMsg, clierrs.NotLeaderErr) ||
-					strings.Contains(errMsg, clierrs.NotServedErr) ||
-					strings.Contains(errMsg, "ErrKeyspaceNotAssigned") ||
-					strings.Contains(errMsg, "ErrKeyspaceGroupIsMerging") {
-					continue
-				}
-				re.FailNow(errMsg)
-			}
-			if physical == lastPhysical {
-				re.Greater(logical, lastLogical)
-			} else {
-				re.Greater(physical, lastPhysical)
-			}
-			lastPhysical, lastLogical = physical, logical
-		}
-	}()
-	return func() {
-		// Wait for a while to make sure the client has sent more TSO requests.
-		time.Sleep(time.Second)
-		// Cancel the context to stop the client.
-		cancel()
-		// Wait for the client to stop.
-		wg.Wait()
+	checker := newTSOConsistencyChecker(suite.ctx, re)
+	for _, keyspaceID := range keyspaceIDs {
+		// Make sure the leader of the keyspace group is elected.
+		member, err := suite.tsoCluster.
+			WaitForPrimaryServing(re, keyspaceID, keyspaceGroupID).
+			GetMember(keyspaceID, keyspaceGroupID)
+		re.NoError(err)
+		re.NotNil(member)
+		// Use multiple clients to make the requests overlap with each other.
+		checker.addClients(keyspaceID, 2, []string{suite.pdLeaderServer.GetAddr()})
 	}
+	return checker.stop
 }
 
 func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupMembers() {
@@ -643,6 +670,10 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupMerge() {
 
 func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupMergeClient() {
 	re := suite.Require()
+	// Slow down the system time and delay the timestamp sync of the merge target
+	// to widen the race window of the merge.
+	re.NoError(failpoint.Enable("github.com/tikv/pd/pkg/tso/systemTimeSlow", `return(true)`))
+	re.NoError(failpoint.Enable("github.com/tikv/pd/pkg/tso/delaySyncTimestamp", `return(true)`))
 	// Create the keyspace group `id` with keyspaces [111, 222, 333].
 	id := suite.allocID()
 	handlersutil.MustCreateKeyspaceGroup(re, suite.pdLeaderServer, &handlers.CreateKeyspaceGroupParams{
@@ -659,8 +690,8 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupMergeClient()
 	re.Equal(id, kg1.ID)
 	re.Equal([]uint32{111, 222, 333}, kg1.Keyspaces)
 	re.False(kg1.IsMerging())
-	// Request the TSO for keyspace 222 concurrently via client.
-	cancel := suite.dispatchClient(re, 222, id)
+	// Request the TSO for keyspaces 222 and 333 concurrently via clients.
+	cancel := suite.dispatchClient(re, id, 222, 333)
 	// Merge the keyspace group 1 to the default keyspace group.
 	handlersutil.MustMergeKeyspaceGroup(re, suite.pdLeaderServer, mcsutils.DefaultKeyspaceGroupID, &handlers.MergeKeyspaceGroupsParams{
 		MergeList: []uint32{id},
@@ -669,6 +700,8 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupMergeClient()
 	waitFinishMerge(re, suite.pdLeaderServer, mcsutils.DefaultKeyspaceGroupID, []uint32{111, 222, 333})
 	// Stop the client.
 	cancel()
+	re.NoError(failpoint.Disable("github.com/tikv/pd/pkg/tso/delaySyncTimestamp"))
+	re.NoError(failpoint.Disable("github.com/tikv/pd/pkg/tso/systemTimeSlow"))
 }
 
 func waitFinishMerge(
@@ -695,7 +728,7 @@ func (suite *tsoKeyspaceGroupManagerTestSuite) TestTSOKeyspaceGroupMergeBeforeIn
 	re.NoError(failpoint.Enable("github.com/tikv/pd/pkg/tso/failedToSaveTimestamp", `return(true)`))
 	// Request the TSO for the default keyspace concurrently via client.
 	id := suite.allocID()
-	cancel := suite.dispatchClient(re, mcsutils.DefaultKeyspaceID, mcsutils.DefaultKeyspaceGroupID)
+	cancel := suite.dispatchClient(re, mcsutils.DefaultKeyspaceGroupID, mcsutils.DefaultKeyspaceID)
 	// Create the keyspace group 1 with keyspaces [111, 222, 333].
 	handlersutil.MustCreateKeyspaceGroup(re, suite.pdLeaderServer, &handlers.CreateKeyspaceGroupParams{
 		KeyspaceGroups: []*endpoint.KeyspaceGroup{
@@ -22,11 +22,13 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/tsopb"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	pd "github.com/tikv/pd/client"
@@ -40,6 +42,7 @@ import (
 	"github.com/tikv/pd/pkg/utils/tempurl"
 	"github.com/tikv/pd/pkg/utils/testutil"
 	"github.com/tikv/pd/pkg/utils/tsoutil"
+	"github.com/tikv/pd/pkg/utils/typeutil"
 	"github.com/tikv/pd/tests"
 	"github.com/tikv/pd/tests/integrations/mcs"
 	"go.etcd.io/etcd/clientv3"
@@ -370,6 +373,82 @@ func TestForwardTSOWhenPrimaryChanged(t *testing.T) {
 	suite.checkAvailableTSO(re)
 }
 
+func TestDrainTSOPrimary(t *testing.T) {
+	re := require.New(t)
+	suite := NewAPIServerForward(re)
+	defer suite.ShutDown()
+
+	tc, err := tests.NewTestTSOCluster(suite.ctx, 2, suite.backendEndpoints)
+	re.NoError(err)
+	defer tc.Destroy()
+	oldPrimary := tc.WaitForDefaultPrimaryServing(re).GetAddr()
+	suite.checkAvailableTSO(re)
+
+	// The primary is handed off before the server is destroyed, so there is no need
+	// to wait for the leader lease timeout.
+	ctx, cancel := context.WithTimeout(suite.ctx, 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	re.NoError(tc.DrainAndDestroyServer(ctx, oldPrimary))
+	primary := tc.WaitForDefaultPrimaryServing(re)
+	re.Less(time.Since(start), time.Duration(utils.DefaultLeaderLease)*time.Second)
+	re.NotEqual(oldPrimary, primary.GetAddr())
+	suite.checkAvailableTSO(re)
+}
+
+func TestForwardTSOFromNonPrimary(t *testing.T) {
+	re := require.New(t)
+	suite := NewAPIServerForward(re)
+	defer suite.ShutDown()
+
+	tc, err := tests.NewTestTSOCluster(suite.ctx, 2, suite.backendEndpoints)
+	re.NoError(err)
+	defer tc.Destroy()
+	primary := tc.WaitForDefaultPrimaryServing(re)
+	var follower *tso.Server
+	for _, server := range tc.GetServers() {
+		if server != primary {
+			follower = server
+		}
+	}
+	re.NotNil(follower)
+
+	// the requests received by the non-primary member are forwarded to the primary.
+	cc, err := grpc.DialContext(suite.ctx, strings.TrimPrefix(follower.GetAddr(), "http://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	re.NoError(err)
+	defer cc.Close()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stream, err := tsopb.NewTSOClient(cc).Tso(suite.ctx)
+			re.NoError(err)
+			defer stream.CloseSend()
+			var lastTS uint64
+			for j := 0; j < 100; j++ {
+				re.NoError(stream.Send(&tsopb.TsoRequest{
+					Header: &tsopb.RequestHeader{
+						ClusterId:       follower.ClusterID(),
+						KeyspaceId:      utils.DefaultKeyspaceID,
+						KeyspaceGroupId: utils.DefaultKeyspaceGroupID,
+					},
+					Count: 2,
+				}))
+				resp, err := stream.Recv()
+				re.NoError(err)
+				re.Equal(uint32(2), resp.GetCount())
+				re.Equal(utils.DefaultKeyspaceGroupID, resp.GetHeader().GetKeyspaceGroupId())
+				ts := tsoutil.ComposeTS(resp.GetTimestamp().GetPhysical(), resp.GetTimestamp().GetLogical())
+				re.Greater(ts, lastTS)
+				lastTS = ts
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func TestResignTSOPrimaryForward(t *testing.T) {
 	re := require.New(t)
 	suite := NewAPIServerForward(re)
@@ -396,6 +475,397 @@ func TestResignTSOPrimaryForward(t *testing.T) {
 	}
 }
 
+func TestTSOPrimaryWithFaults(t *testing.T) {
+	re := require.New(t)
+	suite := NewAPIServerForward(re)
+	defer suite.ShutDown()
+	tc, err := tests.NewTestTSOCluster(suite.ctx, 2, suite.backendEndpoints)
+	re.NoError(err)
+	defer tc.Destroy()
+	primary := tc.WaitForDefaultPrimaryServing(re).GetAddr()
+
+	// the tso service is still available with the slow backend.
+	tc.InjectBackendLatency(50 * time.Millisecond)
+	suite.checkAvailableTSO(re)
+	tc.InjectBackendLatency(0)
+
+	// the other server takes over the primary after the partitioned one loses its lease.
+	waitNewPrimary := func(oldPrimary string) string {
+		var newPrimary string
+		testutil.Eventually(re, func() bool {
+			server := tc.GetPrimaryServer(utils.DefaultKeyspaceID, utils.DefaultKeyspaceGroupID)
+			if server == nil || server.GetAddr() == oldPrimary {
+				return false
+			}
+			newPrimary = server.GetAddr()
+			return true
+		}, testutil.WithWaitFor(30*time.Second))
+		return newPrimary
+	}
+	re.NoError(tc.PartitionServer(primary))
+	newPrimary := waitNewPrimary(primary)
+	re.NoError(tc.HealServer(primary))
+	suite.checkAvailableTSO(re)
+
+	// the primary also changes if the backend traffic of the server freezes longer than the lease.
+	re.NoError(tc.PauseServerBackend(newPrimary, 2*time.Duration(utils.DefaultLeaderLease)*time.Second))
+	waitNewPrimary(newPrimary)
+	suite.checkAvailableTSO(re)
+	re.Error(tc.PartitionServer("unknown"))
+}
+
+func TestTSORollingRestart(t *testing.T) {
+	re := require.New(t)
+	suite := NewAPIServerForward(re)
+	defer suite.ShutDown()
+	tc, err := tests.NewTestTSOCluster(suite.ctx, 3, suite.backendEndpoints)
+	re.NoError(err)
+	defer tc.Destroy()
+	tc.WaitForDefaultPrimaryServing(re)
+	addrs := tc.GetAddrs()
+	suite.checkAvailableTSO(re)
+	_, _, err = suite.pdClient.GetTS(suite.ctx)
+	re.NoError(err)
+
+	// the servers keep their addresses and get the new config after the rolling upgrade.
+	tc.RollingRestart(re, func(cfg *tso.Config) {
+		cfg.TSOUpdatePhysicalInterval = typeutil.NewDuration(10 * time.Millisecond)
+	})
+	re.ElementsMatch(addrs, tc.GetAddrs())
+	for _, server := range tc.GetServers() {
+		re.Equal(10*time.Millisecond, server.GetConfig().TSOUpdatePhysicalInterval.Duration)
+	}
+	suite.checkAvailableTSO(re)
+
+	re.Error(tc.RestartServer("unknown", nil))
+	re.NoError(tc.RestartServer(addrs[0], nil))
+	tc.WaitForPrimariesSettled(re)
+	re.ElementsMatch(addrs, tc.GetAddrs())
+	suite.checkAvailableTSO(re)
+}
+
+func TestTSOClockSkew(t *testing.T) {
+	re := require.New(t)
+	suite := NewAPIServerForward(re)
+	defer suite.ShutDown()
+	tc, err := tests.NewTestTSOCluster(suite.ctx, 2, suite.backendEndpoints)
+	re.NoError(err)
+	defer tc.Destroy()
+	primary := tc.WaitForDefaultPrimaryServing(re)
+	suite.checkAvailableTSO(re)
+	re.Error(tc.SetClockOffset("unknown", time.Hour))
+
+	var lastPhysical, lastLogical int64
+	checkMonotonic := func() int64 {
+		physical, logical, err := suite.pdClient.GetTS(suite.ctx)
+		re.NoError(err)
+		re.True(physical > lastPhysical || (physical == lastPhysical && logical > lastLogical))
+		lastPhysical, lastLogical = physical, logical
+		return physical
+	}
+	start := checkMonotonic()
+
+	// the TSO follows the clock which jumps forward.
+	re.NoError(tc.SetClockOffset(primary.GetAddr(), time.Hour))
+	testutil.Eventually(re, func() bool {
+		return checkMonotonic()-start > (50 * time.Minute).Milliseconds()
+	})
+
+	// the TSO doesn't fall back when the clock jumps back.
+	re.NoError(tc.SetClockOffset(primary.GetAddr(), 0))
+	for i := 0; i < 10; i++ {
+		checkMonotonic()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the TSO doesn't fall back after the primary changes to the server whose clock is behind.
+	re.NoError(tc.ResignPrimary(utils.DefaultKeyspaceID, utils.DefaultKeyspaceGroupID))
+	tc.WaitForDefaultPrimaryServing(re)
+	testutil.Eventually(re, func() bool {
+		_, _, err := suite.pdClient.GetTS(suite.ctx)
+		return err == nil
+	})
+	checkMonotonic()
+}
+
+func TestWaitForPrimaryServingCtx(t *testing.T) {
+	re := require.New(t)
+	suite := NewAPIServerForward(re)
+	defer suite.ShutDown()
+	tc, err := tests.NewTestTSOCluster(suite.ctx, 1, suite.backendEndpoints)
+	re.NoError(err)
+	defer tc.Destroy()
+
+	// The waiters can be used in the goroutines without the assertions.
+	errCh := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(suite.ctx, 30*time.Second)
+		defer cancel()
+		if _, err := tc.WaitForDefaultPrimaryServingCtx(ctx); err != nil {
+			errCh <- err
+			return
+		}
+		errCh <- tc.WaitForPrimariesSettledCtx(ctx)
+	}()
+	re.NoError(<-errCh)
+
+	// The keyspace group which doesn't exist is never served.
+	ctx, cancel := context.WithTimeout(suite.ctx, 500*time.Millisecond)
+	defer cancel()
+	primary, err := tc.WaitForPrimaryServingCtx(ctx, 100, 100)
+	re.ErrorIs(err, context.DeadlineExceeded)
+	re.Nil(primary)
+}
+
+func TestTSOClusterWithServerConfig(t *testing.T) {
+	re := require.New(t)
+	suite := NewAPIServerForward(re)
+	defer suite.ShutDown()
+	tc, err := tests.NewTestTSOCluster(suite.ctx, 1, suite.backendEndpoints)
+	re.NoError(err)
+	defer tc.Destroy()
+
+	// Add a server with a longer lease and a slower physical clock update.
+	addr := tempurl.Alloc()
+	re.NoError(tc.AddServerWithConfig(addr, func(cfg *tso.Config) {
+		cfg.LeaderLease = 10
+		cfg.TSOUpdatePhysicalInterval = typeutil.NewDuration(200 * time.Millisecond)
+	}))
+	for serverAddr, server := range tc.GetServers() {
+		if serverAddr == addr {
+			re.Equal(int64(10), server.GetConfig().GetLeaderLease())
+			re.Equal(200*time.Millisecond, server.GetConfig().GetTSOUpdatePhysicalInterval())
+		} else {
+			re.Equal(utils.DefaultLeaderLease, server.GetConfig().GetLeaderLease())
+		}
+	}
+	tc.WaitForDefaultPrimaryServing(re)
+	tc.WaitForPrimariesSettled(re)
+}
+
+func TestTSOClusterLeakCheck(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Don't use the PD client, whose goroutines are started after the TSO servers.
+	cluster, err := tests.NewTestAPICluster(ctx, 1)
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.RunInitialServers())
+	leaderName := cluster.WaitLeader()
+	re.NotEmpty(leaderName)
+	pdLeader := cluster.GetServer(leaderName)
+	re.NoError(pdLeader.BootstrapCluster())
+	backendEndpoints := pdLeader.GetAddr()
+
+	tc, err := tests.NewTestTSOCluster(ctx, 2, backendEndpoints)
+	re.NoError(err)
+	tc.WaitForDefaultPrimaryServing(re)
+	re.NoError(tc.DestroyWithLeakCheck())
+
+	// The goroutines started after the cluster are reported unless they are allowed.
+	tc, err = tests.NewTestTSOCluster(ctx, 1, backendEndpoints)
+	re.NoError(err)
+	tc.WaitForDefaultPrimaryServing(re)
+	leakCh := make(chan struct{})
+	defer close(leakCh)
+	go leakUntilClosed(leakCh)
+	err = tc.DestroyWithLeakCheck()
+	re.ErrorContains(err, "leakUntilClosed")
+	tc, err = tests.NewTestTSOCluster(ctx, 1, backendEndpoints)
+	re.NoError(err)
+	tc.WaitForDefaultPrimaryServing(re)
+	re.NoError(tc.DestroyWithLeakCheck(goleak.IgnoreTopFunction("github.com/tikv/pd/tests/integrations/mcs/tso.leakUntilClosed")))
+}
+
+func leakUntilClosed(ch <-chan struct{}) {
+	<-ch
+}
+
+func TestMCSCluster(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := tests.NewTestMCSCluster(ctx, re, tests.MCSClusterConfig{
+		APIServerCount:             1,
+		TSOServerCount:             2,
+		SchedulingServerCount:      1,
+		ResourceManagerServerCount: 1,
+	})
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.Start())
+
+	re.NotNil(cluster.GetAPILeaderServer())
+	re.NotNil(cluster.GetTSOCluster().WaitForDefaultPrimaryServing(re))
+	re.NotNil(cluster.GetSchedulingCluster().GetPrimaryServer())
+	re.NotNil(cluster.GetResourceManagerCluster().GetPrimaryServer())
+	re.Equal(cluster.GetSchedulingCluster().GetPrimaryServer(), cluster.GetAPICluster().GetSchedulingPrimaryServer())
+
+	// The keyspace group changes made through the API server are served by the TSO servers.
+	cluster.GetTSOCluster().BootstrapKeyspaceGroups(re, []endpoint.KeyspaceGroup{{ID: 1, Keyspaces: []uint32{1}}})
+	re.NotNil(cluster.GetTSOCluster().GetPrimaryServer(1, 1))
+}
+
+func TestMCSClusterSuspendBackend(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := tests.NewTestMCSCluster(ctx, re, tests.MCSClusterConfig{
+		APIServerCount:        1,
+		TSOServerCount:        1,
+		SchedulingServerCount: 1,
+		SuspendableBackend:    true,
+	})
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.Start())
+	tsoCluster, schedulingCluster := cluster.GetTSOCluster(), cluster.GetSchedulingCluster()
+
+	// the primaries step down after losing the backend, so they can't serve the stale data.
+	cluster.SuspendBackend()
+	testutil.Eventually(re, func() bool {
+		return tsoCluster.GetPrimaryServer(utils.DefaultKeyspaceID, utils.DefaultKeyspaceGroupID) == nil &&
+			schedulingCluster.GetPrimaryServer() == nil
+	}, testutil.WithWaitFor(30*time.Second))
+	// the API servers are not affected.
+	re.NotNil(cluster.GetAPILeaderServer())
+
+	// the primaries are elected again after the backend is resumed.
+	cluster.ResumeBackend()
+	re.NotNil(tsoCluster.WaitForDefaultPrimaryServing(re))
+	re.NotNil(schedulingCluster.WaitForPrimaryServing(re))
+}
+
+func TestTSOClustersShareBackend(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := tests.NewTestAPICluster(ctx, 1)
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.RunInitialServers())
+	leaderName := cluster.WaitLeader()
+	re.NotEmpty(leaderName)
+	pdLeader := cluster.GetServer(leaderName)
+	re.NoError(pdLeader.BootstrapCluster())
+	backendEndpoints := pdLeader.GetAddr()
+
+	// the clusters elect their own primaries, as their keys don't overlap.
+	prefixes := []string{"/cluster-1", "/cluster-2"}
+	for _, prefix := range prefixes {
+		tc, err := tests.NewTestTSOClusterWithRootPathPrefix(ctx, 1, backendEndpoints, prefix)
+		re.NoError(err)
+		defer tc.Destroy()
+		primary := tc.WaitForDefaultPrimaryServing(re)
+		re.Equal(pdLeader.GetClusterID(), primary.ClusterID())
+	}
+	client := pdLeader.GetEtcdClient()
+	for _, prefix := range prefixes {
+		resp, err := client.Get(ctx, prefix+"/ms/", clientv3.WithPrefix(), clientv3.WithCountOnly())
+		re.NoError(err)
+		re.Positive(resp.Count)
+	}
+	// the servers are invisible to the backend.
+	addrs, err := discovery.Discover(client, strconv.FormatUint(pdLeader.GetClusterID(), 10), utils.TSOServiceName)
+	re.NoError(err)
+	re.Empty(addrs)
+}
+
+// stateLogRecorder records the logs written by DumpState.
+type stateLogRecorder struct {
+	testing.TB
+	logs []string
+}
+
+func (r *stateLogRecorder) Log(args ...any) {
+	r.logs = append(r.logs, fmt.Sprint(args...))
+}
+
+func TestMCSClusterDumpState(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := tests.NewTestMCSCluster(ctx, re, tests.MCSClusterConfig{
+		APIServerCount:        1,
+		TSOServerCount:        1,
+		SchedulingServerCount: 1,
+	})
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.Start())
+
+	recorder := &stateLogRecorder{TB: t}
+	cluster.DumpState(recorder)
+	re.Len(recorder.logs, 1)
+	state := recorder.logs[0]
+	tsoAddr := cluster.GetTSOCluster().GetAddrs()[0]
+	re.Contains(state, cluster.GetAPICluster().GetLeader())
+	re.Contains(state, fmt.Sprintf("server %s: serving=true", tsoAddr))
+	re.Contains(state, fmt.Sprintf("keyspace group %d:", utils.DefaultKeyspaceGroupID))
+	re.Contains(state, "primary=true, election={path: ")
+	re.Contains(state, fmt.Sprintf("leader-urls: [%s]", tsoAddr))
+	re.Contains(state, fmt.Sprintf("server %s: serving=true", cluster.GetSchedulingCluster().GetAddrs()[0]))
+}
+
+func TestTSOServerWithTLS(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tlsCfg, err := tests.GenerateTestTLSConfig(t.TempDir())
+	re.NoError(err)
+
+	cluster, err := tests.NewTestAPICluster(ctx, 1, tests.WithTLS(tlsCfg))
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.RunInitialServers())
+	leaderName := cluster.WaitLeader()
+	re.NotEmpty(leaderName)
+	pdLeader := cluster.GetServer(leaderName)
+	re.NoError(pdLeader.BootstrapCluster())
+	backendEndpoints := pdLeader.GetAddr()
+	re.True(strings.HasPrefix(backendEndpoints, "https://"))
+
+	tc, err := tests.NewTestTSOClusterWithTLS(ctx, 2, backendEndpoints, tlsCfg)
+	re.NoError(err)
+	defer tc.Destroy()
+	primary := tc.WaitForDefaultPrimaryServing(re)
+	re.True(strings.HasPrefix(primary.GetAddr(), "https://"))
+
+	// the client without the certificates can't get the TSO.
+	cctx, ccancel := context.WithTimeout(ctx, 3*time.Second)
+	defer ccancel()
+	_, err = pd.NewClientWithContext(cctx, []string{backendEndpoints}, pd.SecurityOption{}, pd.WithMaxErrorRetry(1))
+	re.Error(err)
+
+	client, err := pd.NewClientWithContext(ctx, []string{backendEndpoints}, pd.SecurityOption{
+		CAPath:   tlsCfg.CAPath,
+		CertPath: tlsCfg.CertPath,
+		KeyPath:  tlsCfg.KeyPath,
+	})
+	re.NoError(err)
+	defer client.Close()
+	var lastTS uint64
+	for i := 0; i < 10; i++ {
+		var physical, logical int64
+		testutil.Eventually(re, func() bool {
+			physical, logical, err = client.GetTS(ctx)
+			return err == nil
+		})
+		ts := tsoutil.ComposeTS(physical, logical)
+		re.Greater(ts, lastTS)
+		lastTS = ts
+	}
+
+	// the servers still work with TLS after the primary changes.
+	re.NoError(tc.ResignPrimary(utils.DefaultKeyspaceID, utils.DefaultKeyspaceGroupID))
+	tc.WaitForDefaultPrimaryServing(re)
+	testutil.Eventually(re, func() bool {
+		physical, logical, err := client.GetTS(ctx)
+		return err == nil && tsoutil.ComposeTS(physical, logical) > lastTS
+	})
+}
+
 func TestResignAPIPrimaryForward(t *testing.T) {
 	re := require.New(t)
 	suite := NewAPIServerForward(re)
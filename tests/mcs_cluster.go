@@ -0,0 +1,247 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/pd/pkg/mcs/utils"
+)
+
+const closeEtcdTickFailpoint = "github.com/tikv/pd/pkg/utils/etcdutil/closeTick"
+
+// MCSClusterConfig is the config of a TestMCSCluster, the service whose server
+// count is zero is not started.
+type MCSClusterConfig struct {
+	APIServerCount             int
+	TSOServerCount             int
+	SchedulingServerCount      int
+	ResourceManagerServerCount int
+	// APIServerOptions customize the configs of the API servers.
+	APIServerOptions []ConfigOption
+	// SuspendableBackend connects the other services to the API servers through
+	// the proxies, which is required by SuspendBackend. It disables the endpoint
+	// syncing of their etcd clients, so only the tests that need it should set it.
+	SuspendableBackend bool
+}
+
+// TestMCSCluster is a test cluster in the microservice mode, it composes the API
+// server cluster with the TSO, scheduling and resource manager clusters, which
+// are started and destroyed together.
+type TestMCSCluster struct {
+	ctx context.Context
+	re  *require.Assertions
+	cfg MCSClusterConfig
+
+	apiCluster *TestCluster
+	// backendProxies are between the other services and the API servers, which
+	// are used to cut the services off from the backend.
+	backendProxies         []*faultProxy
+	tsoCluster             *TestTSOCluster
+	schedulingCluster      *TestSchedulingCluster
+	resourceManagerCluster *TestResourceManagerCluster
+}
+
+// NewTestMCSCluster creates a new microservice test cluster, the servers are not
+// started until Start is called.
+func NewTestMCSCluster(ctx context.Context, re *require.Assertions, cfg MCSClusterConfig) (*TestMCSCluster, error) {
+	if cfg.APIServerCount <= 0 {
+		return nil, errors.New("at least one API server is required")
+	}
+	apiCluster, err := NewTestAPICluster(ctx, cfg.APIServerCount, cfg.APIServerOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return &TestMCSCluster{
+		ctx:        ctx,
+		re:         re,
+		cfg:        cfg,
+		apiCluster: apiCluster,
+	}, nil
+}
+
+// Start starts the API servers and bootstraps the cluster, then starts the other
+// services against the API leader. It blocks until every started service has a
+// serving primary. The state of the cluster is attached to the error if it fails.
+func (c *TestMCSCluster) Start() error {
+	if err := c.start(); err != nil {
+		return fmt.Errorf("%w\n%s", err, c.stateDump())
+	}
+	return nil
+}
+
+func (c *TestMCSCluster) start() error {
+	if err := c.apiCluster.RunInitialServers(); err != nil {
+		return err
+	}
+	leaderName := c.apiCluster.WaitLeader()
+	if leaderName == "" {
+		return errors.New("no API leader is elected")
+	}
+	leaderServer := c.apiCluster.GetServer(leaderName)
+	if err := leaderServer.BootstrapCluster(); err != nil {
+		return err
+	}
+	leaderServer.GetRaftCluster().SetPrepared()
+	var err error
+	backendEndpoints := leaderServer.GetAddr()
+	if c.cfg.SuspendableBackend {
+		// The other services connect to the backend via the proxies, so they can be
+		// cut off from the backend by SuspendBackend.
+		proxies, proxyEndpoints, err := newFaultProxies(backendEndpoints)
+		if err != nil {
+			return err
+		}
+		c.backendProxies, backendEndpoints = proxies, proxyEndpoints
+		// The etcd clients sync the endpoints from the member list, which bypasses
+		// the proxies, so the syncing is disabled for the clients of the services.
+		if err := failpoint.Enable(closeEtcdTickFailpoint, `return(true)`); err != nil {
+			return err
+		}
+		defer func() {
+			_ = failpoint.Disable(closeEtcdTickFailpoint)
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(c.ctx, 30*time.Second)
+	defer cancel()
+	if c.cfg.TSOServerCount > 0 {
+		c.tsoCluster, err = NewTestTSOCluster(c.ctx, c.cfg.TSOServerCount, backendEndpoints)
+		if err != nil {
+			return err
+		}
+		if _, err = c.tsoCluster.WaitForDefaultPrimaryServingCtx(ctx); err != nil {
+			return err
+		}
+	}
+	if c.cfg.SchedulingServerCount > 0 {
+		c.schedulingCluster, err = NewTestSchedulingCluster(c.ctx, c.cfg.SchedulingServerCount, backendEndpoints)
+		if err != nil {
+			return err
+		}
+		// The API cluster takes over the lifecycle of the scheduling cluster.
+		c.apiCluster.SetSchedulingCluster(c.schedulingCluster)
+		primary, err := c.schedulingCluster.WaitForPrimaryServingCtx(ctx)
+		if err != nil {
+			return err
+		}
+		primary.GetCluster().SetPrepared()
+		err = waitUntil(ctx, 100*time.Millisecond, func() bool {
+			return c.apiCluster.GetLeaderServer().GetRaftCluster().IsServiceIndependent(utils.SchedulingServiceName)
+		})
+		if err != nil {
+			return errors.Annotate(err, "the scheduling service is not independent")
+		}
+	}
+	if c.cfg.ResourceManagerServerCount > 0 {
+		c.resourceManagerCluster, err = NewTestResourceManagerCluster(c.ctx, c.re, c.cfg.ResourceManagerServerCount, backendEndpoints)
+		if err != nil {
+			return err
+		}
+		if _, err = c.resourceManagerCluster.WaitForPrimaryServingCtx(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Destroy stops and destroys all the services of the test cluster.
+func (c *TestMCSCluster) Destroy() {
+	if c.resourceManagerCluster != nil {
+		c.resourceManagerCluster.Destroy()
+	}
+	if c.tsoCluster != nil {
+		c.tsoCluster.Destroy()
+	}
+	// It destroys the scheduling cluster too.
+	c.apiCluster.Destroy()
+	closeFaultProxies(c.backendProxies)
+}
+
+// SuspendBackend cuts the TSO, scheduling and resource manager servers off from
+// the backend, the existing connections are closed and the new ones are refused
+// until ResumeBackend is called. The primaries of the services will step down
+// once their leases expire, while the API servers are not affected. It requires
+// SuspendableBackend and the failpoints to be enabled, and doesn't apply to the
+// servers added after Start.
+func (c *TestMCSCluster) SuspendBackend() {
+	c.re.True(c.cfg.SuspendableBackend, "the backend of the cluster is not suspendable")
+	for _, p := range c.backendProxies {
+		p.partition(true)
+	}
+}
+
+// ResumeBackend reconnects the services to the backend after SuspendBackend.
+func (c *TestMCSCluster) ResumeBackend() {
+	for _, p := range c.backendProxies {
+		p.partition(false)
+	}
+}
+
+// GetAPICluster returns the API server cluster.
+func (c *TestMCSCluster) GetAPICluster() *TestCluster {
+	return c.apiCluster
+}
+
+// GetAPILeaderServer returns the API leader server.
+func (c *TestMCSCluster) GetAPILeaderServer() *TestServer {
+	return c.apiCluster.GetLeaderServer()
+}
+
+// GetTSOCluster returns the TSO cluster, it's nil if no TSO server is configured.
+func (c *TestMCSCluster) GetTSOCluster() *TestTSOCluster {
+	return c.tsoCluster
+}
+
+// GetSchedulingCluster returns the scheduling cluster, it's nil if no scheduling
+// server is configured.
+func (c *TestMCSCluster) GetSchedulingCluster() *TestSchedulingCluster {
+	return c.schedulingCluster
+}
+
+// GetResourceManagerCluster returns the resource manager cluster, it's nil if no
+// resource manager server is configured.
+func (c *TestMCSCluster) GetResourceManagerCluster() *TestResourceManagerCluster {
+	return c.resourceManagerCluster
+}
+
+// DumpState writes the state of the cluster to the test log, including the API
+// leader and the serving status, the keyspace groups and the election records
+// of the servers of each service.
+func (c *TestMCSCluster) DumpState(t testing.TB) {
+	t.Log(c.stateDump())
+}
+
+func (c *TestMCSCluster) stateDump() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "the API leader: %q\n", c.apiCluster.GetLeader())
+	if c.tsoCluster != nil {
+		b.WriteString(c.tsoCluster.stateDump())
+	}
+	if c.schedulingCluster != nil {
+		b.WriteString(c.schedulingCluster.stateDump())
+	}
+	if c.resourceManagerCluster != nil {
+		b.WriteString(c.resourceManagerCluster.stateDump())
+	}
+	return b.String()
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/stretchr/testify/require"
+	rm "github.com/tikv/pd/pkg/mcs/resourcemanager/server"
+	"github.com/tikv/pd/pkg/utils/tempurl"
+	"github.com/tikv/pd/pkg/utils/testutil"
+)
+
+// TestResourceManagerCluster is a test cluster for resource manager.
+type TestResourceManagerCluster struct {
+	ctx context.Context
+	re  *require.Assertions
+
+	backendEndpoints string
+	servers          map[string]*rm.Server
+	cleanupFuncs     map[string]testutil.CleanupFunc
+}
+
+// NewTestResourceManagerCluster creates a new resource manager test cluster.
+func NewTestResourceManagerCluster(ctx context.Context, re *require.Assertions, initialServerCount int, backendEndpoints string) (tc *TestResourceManagerCluster, err error) {
+	tc = &TestResourceManagerCluster{
+		ctx:              ctx,
+		re:               re,
+		backendEndpoints: backendEndpoints,
+		servers:          make(map[string]*rm.Server, initialServerCount),
+		cleanupFuncs:     make(map[string]testutil.CleanupFunc, initialServerCount),
+	}
+	for i := 0; i < initialServerCount; i++ {
+		err = tc.AddServer(tempurl.Alloc())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tc, nil
+}
+
+// AddServer adds a new resource manager server to the test cluster.
+func (tc *TestResourceManagerCluster) AddServer(addr string) error {
+	cfg := rm.NewConfig()
+	cfg.BackendEndpoints = tc.backendEndpoints
+	cfg.ListenAddr = addr
+	generatedCfg, err := rm.GenerateConfig(cfg)
+	if err != nil {
+		return err
+	}
+	// Use the address as the name to distinguish the servers on the same host.
+	generatedCfg.Name = generatedCfg.ListenAddr
+	server, cleanup, err := rm.NewTestServer(tc.ctx, tc.re, generatedCfg)
+	if err != nil {
+		return err
+	}
+	tc.servers[generatedCfg.GetListenAddr()] = server
+	tc.cleanupFuncs[generatedCfg.GetListenAddr()] = cleanup
+	return nil
+}
+
+// Destroy stops and destroy the test cluster.
+func (tc *TestResourceManagerCluster) Destroy() {
+	for _, cleanup := range tc.cleanupFuncs {
+		cleanup()
+	}
+	tc.cleanupFuncs = nil
+	tc.servers = nil
+}
+
+// DestroyServer stops and destroy the test server by the given address.
+func (tc *TestResourceManagerCluster) DestroyServer(addr string) {
+	tc.cleanupFuncs[addr]()
+	delete(tc.cleanupFuncs, addr)
+	delete(tc.servers, addr)
+}
+
+// ResignPrimary resigns the primary resource manager server.
+func (tc *TestResourceManagerCluster) ResignPrimary() error {
+	primaryServer := tc.GetPrimaryServer()
+	if primaryServer == nil {
+		return errors.New("no primary resource manager server")
+	}
+	primaryServer.ResignPrimary()
+	return nil
+}
+
+// GetPrimaryServer returns the primary resource manager server.
+func (tc *TestResourceManagerCluster) GetPrimaryServer() *rm.Server {
+	for _, server := range tc.servers {
+		if server.IsServing() {
+			return server
+		}
+	}
+	return nil
+}
+
+// WaitForPrimaryServing waits for one of servers being elected to be the primary.
+func (tc *TestResourceManagerCluster) WaitForPrimaryServing(re *require.Assertions) *rm.Server {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	primary, err := tc.WaitForPrimaryServingCtx(ctx)
+	if err != nil {
+		re.NoError(err, tc.stateDump())
+	}
+	return primary
+}
+
+// WaitForPrimaryServingCtx is like WaitForPrimaryServing, but it returns an error
+// instead of failing the test if no primary is serving before the context is done.
+func (tc *TestResourceManagerCluster) WaitForPrimaryServingCtx(ctx context.Context) (*rm.Server, error) {
+	var primary *rm.Server
+	err := waitUntil(ctx, 50*time.Millisecond, func() bool {
+		primary = tc.GetPrimaryServer()
+		return primary != nil
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "no resource manager primary is serving")
+	}
+	return primary, nil
+}
+
+// GetServer returns the resource manager server by the given address.
+func (tc *TestResourceManagerCluster) GetServer(addr string) *rm.Server {
+	return tc.servers[addr]
+}
+
+// GetServers returns all resource manager servers.
+func (tc *TestResourceManagerCluster) GetServers() map[string]*rm.Server {
+	return tc.servers
+}
+
+// GetAddrs returns all resource manager server addresses.
+func (tc *TestResourceManagerCluster) GetAddrs() []string {
+	addrs := make([]string, 0, len(tc.servers))
+	for _, server := range tc.servers {
+		addrs = append(addrs, server.GetAddr())
+	}
+	return addrs
+}
+
+// DumpState writes whether each server is serving and the primary seen by it to
+// the test log. The waiters dump the state automatically when they time out.
+func (tc *TestResourceManagerCluster) DumpState(t testing.TB) {
+	t.Log(tc.stateDump())
+}
+
+func (tc *TestResourceManagerCluster) stateDump() string {
+	var b strings.Builder
+	dumpPrimaryState(&b, "resource manager", tc.servers)
+	return b.String()
+}
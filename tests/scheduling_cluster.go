@@ -16,12 +16,16 @@ package tests
 
 import (
 	"context"
+	"strings"
+	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/stretchr/testify/require"
 	scheduling "github.com/tikv/pd/pkg/mcs/scheduling/server"
 	sc "github.com/tikv/pd/pkg/mcs/scheduling/server/config"
 	"github.com/tikv/pd/pkg/schedule/schedulers"
+	"github.com/tikv/pd/pkg/utils/grpcutil"
 	"github.com/tikv/pd/pkg/utils/tempurl"
 	"github.com/tikv/pd/pkg/utils/testutil"
 )
@@ -33,16 +37,29 @@ type TestSchedulingCluster struct {
 	backendEndpoints string
 	servers          map[string]*scheduling.Server
 	cleanupFuncs     map[string]testutil.CleanupFunc
+	// tlsConfig is used by all the servers if it's not nil.
+	tlsConfig *grpcutil.TLSConfig
 }
 
 // NewTestSchedulingCluster creates a new scheduling test cluster.
 func NewTestSchedulingCluster(ctx context.Context, initialServerCount int, backendEndpoints string) (tc *TestSchedulingCluster, err error) {
+	return newTestSchedulingCluster(ctx, initialServerCount, backendEndpoints, nil)
+}
+
+// NewTestSchedulingClusterWithTLS creates a new scheduling test cluster whose
+// servers enable TLS with the given certificates, the backend should enable TLS too.
+func NewTestSchedulingClusterWithTLS(ctx context.Context, initialServerCount int, backendEndpoints string, tlsCfg *grpcutil.TLSConfig) (tc *TestSchedulingCluster, err error) {
+	return newTestSchedulingCluster(ctx, initialServerCount, backendEndpoints, tlsCfg)
+}
+
+func newTestSchedulingCluster(ctx context.Context, initialServerCount int, backendEndpoints string, tlsCfg *grpcutil.TLSConfig) (tc *TestSchedulingCluster, err error) {
 	schedulers.Register()
 	tc = &TestSchedulingCluster{
 		ctx:              ctx,
 		backendEndpoints: backendEndpoints,
 		servers:          make(map[string]*scheduling.Server, initialServerCount),
 		cleanupFuncs:     make(map[string]testutil.CleanupFunc, initialServerCount),
+		tlsConfig:        tlsCfg,
 	}
 	for i := 0; i < initialServerCount; i++ {
 		err = tc.AddServer(tempurl.Alloc())
@@ -58,6 +75,10 @@ func (tc *TestSchedulingCluster) AddServer(addr string) error {
 	cfg := sc.NewConfig()
 	cfg.BackendEndpoints = tc.backendEndpoints
 	cfg.ListenAddr = addr
+	if tc.tlsConfig != nil {
+		cfg.Security.TLSConfig = *tc.tlsConfig
+		cfg.ListenAddr = toHTTPS(addr)
+	}
 	cfg.Name = cfg.ListenAddr
 	generatedCfg, err := scheduling.GenerateConfig(cfg)
 	if err != nil {
@@ -92,6 +113,16 @@ func (tc *TestSchedulingCluster) DestroyServer(addr string) {
 	delete(tc.servers, addr)
 }
 
+// ResignPrimary resigns the primary scheduling server.
+func (tc *TestSchedulingCluster) ResignPrimary() error {
+	primaryServer := tc.GetPrimaryServer()
+	if primaryServer == nil {
+		return errors.New("no primary scheduling server")
+	}
+	primaryServer.ResignPrimary()
+	return nil
+}
+
 // GetPrimaryServer returns the primary scheduling server.
 func (tc *TestSchedulingCluster) GetPrimaryServer() *scheduling.Server {
 	for _, server := range tc.servers {
@@ -104,20 +135,29 @@ func (tc *TestSchedulingCluster) GetPrimaryServer() *scheduling.Server {
 
 // WaitForPrimaryServing waits for one of servers being elected to be the primary/leader of the given keyspace.
 func (tc *TestSchedulingCluster) WaitForPrimaryServing(re *require.Assertions) *scheduling.Server {
-	var primary *scheduling.Server
-	testutil.Eventually(re, func() bool {
-		for _, server := range tc.servers {
-			if server.IsServing() {
-				primary = server
-				return true
-			}
-		}
-		return false
-	}, testutil.WithWaitFor(10*time.Second), testutil.WithTickInterval(50*time.Millisecond))
-
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	primary, err := tc.WaitForPrimaryServingCtx(ctx)
+	if err != nil {
+		re.NoError(err, tc.stateDump())
+	}
 	return primary
 }
 
+// WaitForPrimaryServingCtx is like WaitForPrimaryServing, but it returns an error
+// instead of failing the test if no primary is serving before the context is done.
+func (tc *TestSchedulingCluster) WaitForPrimaryServingCtx(ctx context.Context) (*scheduling.Server, error) {
+	var primary *scheduling.Server
+	err := waitUntil(ctx, 50*time.Millisecond, func() bool {
+		primary = tc.GetPrimaryServer()
+		return primary != nil
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "no scheduling primary is serving")
+	}
+	return primary, nil
+}
+
 // GetServer returns the scheduling server by the given address.
 func (tc *TestSchedulingCluster) GetServer(addr string) *scheduling.Server {
 	for srvAddr, server := range tc.servers {
@@ -141,3 +181,15 @@ func (tc *TestSchedulingCluster) GetAddrs() []string {
 	}
 	return addrs
 }
+
+// DumpState writes whether each server is serving and the primary seen by it to
+// the test log. The waiters dump the state automatically when they time out.
+func (tc *TestSchedulingCluster) DumpState(t testing.TB) {
+	t.Log(tc.stateDump())
+}
+
+func (tc *TestSchedulingCluster) stateDump() string {
+	var b strings.Builder
+	dumpPrimaryState(&b, "scheduling", tc.servers)
+	return b.String()
+}
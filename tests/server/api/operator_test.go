@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
@@ -156,6 +157,11 @@ func (suite *operatorTestSuite) checkAddRemovePeer(cluster *tests.TestCluster) {
 	err = tu.CheckPostJSON(tests.TestDialClient, fmt.Sprintf("%s/operators", urlPrefix), []byte(`{"name":"transfer-region", "region_id": 1, "to_store_ids": [1, 2, 3]}`), tu.StatusNotOK(re))
 	re.NoError(err)
 
+	// Fail to place the region which does not exist.
+	err = tu.CheckPostJSON(tests.TestDialClient, fmt.Sprintf("%s/operators/%d/placement", urlPrefix, 100), []byte(`{"peers": [{"store_id": 1, "role": "leader"}]}`),
+		tu.Status(re, http.StatusNotFound), tu.StringContain(re, "region 100 not found"))
+	re.NoError(err)
+
 	// Fail to get operator if from is latest.
 	time.Sleep(time.Second)
 	url := fmt.Sprintf("%s/operators/records?from=%s", urlPrefix, strconv.FormatInt(time.Now().Unix(), 10))
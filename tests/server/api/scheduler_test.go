@@ -306,7 +306,7 @@ func (suite *scheduleTestSuite) checkAPI(cluster *tests.TestCluster) {
 				resp := make(map[string]any)
 				tu.Eventually(re, func() bool {
 					re.NoError(tu.ReadGetJSON(re, tests.TestDialClient, listURL, &resp))
-					return resp["degree"] == 3.0 && resp["split-limit"] == 0.0
+					return resp["degree"] == 3.0 && resp["split-limit"] == 10.0 && resp["batch-size"] == 4.0
 				})
 				dataMap := make(map[string]any)
 				dataMap["degree"] = 4
@@ -17,6 +17,7 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -113,6 +114,28 @@ func (suite *keyspaceGroupTestSuite) TestCreateKeyspaceGroups() {
 	FailCreateKeyspaceGroupWithCode(re, suite.server, kgs, http.StatusInternalServerError)
 }
 
+func (suite *keyspaceGroupTestSuite) TestKeyspaceGroupProfile() {
+	re := suite.Require()
+	httpReq, err := http.NewRequest(http.MethodPatch, suite.server.GetAddr()+"/pd/api/v2/tso/keyspace-group-profiles/standard",
+		strings.NewReader(`{"priority": 10}`))
+	re.NoError(err)
+	resp, err := tests.TestDialClient.Do(httpReq)
+	re.NoError(err)
+	resp.Body.Close()
+	re.Equal(http.StatusOK, resp.StatusCode)
+
+	// only the member without a priority inherits the priority of the profile.
+	httpReq, err = http.NewRequest(http.MethodPost, suite.server.GetAddr()+keyspaceGroupsPrefix,
+		strings.NewReader(`{"keyspace-groups": [{"id": 1, "user-kind": "standard", "members": [{"address": "a"}, {"address": "b", "priority": 0}]}]}`))
+	re.NoError(err)
+	resp, err = tests.TestDialClient.Do(httpReq)
+	re.NoError(err)
+	resp.Body.Close()
+	re.Equal(http.StatusOK, resp.StatusCode)
+	kg := MustLoadKeyspaceGroupByID(re, suite.server, 1)
+	re.Equal([]endpoint.KeyspaceGroupMember{{Address: "a", Priority: 10}, {Address: "b", Priority: 0}}, kg.Members)
+}
+
 func (suite *keyspaceGroupTestSuite) TestLoadKeyspaceGroup() {
 	re := suite.Require()
 	kgs := &handlers.CreateKeyspaceGroupParams{KeyspaceGroups: []*endpoint.KeyspaceGroup{
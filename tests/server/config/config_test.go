@@ -24,13 +24,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	cfg "github.com/tikv/pd/pkg/mcs/scheduling/server/config"
 	"github.com/tikv/pd/pkg/ratelimit"
 	sc "github.com/tikv/pd/pkg/schedule/config"
+	"github.com/tikv/pd/pkg/schedule/placement"
+	"github.com/tikv/pd/pkg/utils/etcdutil"
 	tu "github.com/tikv/pd/pkg/utils/testutil"
 	"github.com/tikv/pd/pkg/utils/typeutil"
+	"github.com/tikv/pd/pkg/validation"
 	"github.com/tikv/pd/pkg/versioninfo"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/tests"
@@ -235,6 +239,71 @@ func (suite *configTestSuite) checkConfigSchedule(cluster *tests.TestCluster) {
 		re.NoError(tu.ReadGetJSON(re, tests.TestDialClient, addr, scheduleConfig1))
 		return reflect.DeepEqual(*scheduleConfig1, *scheduleConfig)
 	})
+
+	// the decision log file can only be set in the config file.
+	postData, err = json.Marshal(map[string]any{"schedule.decision-log-file": "decision.log"})
+	re.NoError(err)
+	err = tu.CheckPostJSON(tests.TestDialClient, fmt.Sprintf("%s/pd/api/v1/config", urlPrefix), postData, tu.Status(re, http.StatusBadRequest))
+	re.NoError(err)
+	re.Empty(leaderServer.GetServer().GetScheduleConfig().DecisionLogFile)
+}
+
+type limitValidator struct{}
+
+func (limitValidator) Name() string { return "limit" }
+
+func (limitValidator) Validate(_ context.Context, change *validation.Change) error {
+	switch change.Kind {
+	case validation.KindScheduleConfig:
+		if change.New.(*sc.ScheduleConfig).LeaderScheduleLimit > 64 {
+			return errors.New("leader-schedule-limit should not exceed 64")
+		}
+	case validation.KindPlacementRules:
+		for _, rule := range change.New.(*placement.RulesChange).Rules {
+			if rule.Count > 5 {
+				return errors.New("too many replicas")
+			}
+		}
+	}
+	return nil
+}
+
+func (suite *configTestSuite) TestConfigValidation() {
+	suite.env.RunTestInPDMode(suite.checkConfigValidation)
+}
+
+func (suite *configTestSuite) checkConfigValidation(cluster *tests.TestCluster) {
+	re := suite.Require()
+	leaderServer := cluster.GetLeaderServer()
+	urlPrefix := leaderServer.GetAddr()
+	manager := leaderServer.GetServer().GetValidationManager()
+	manager.Register(limitValidator{})
+	defer manager.Unregister("limit")
+
+	addr := fmt.Sprintf("%s/pd/api/v1/config/schedule", urlPrefix)
+	scheduleConfig := &sc.ScheduleConfig{}
+	re.NoError(tu.ReadGetJSON(re, tests.TestDialClient, addr, scheduleConfig))
+	oldLimit := scheduleConfig.LeaderScheduleLimit
+	scheduleConfig.LeaderScheduleLimit = 100
+	postData, err := json.Marshal(scheduleConfig)
+	re.NoError(err)
+	err = tu.CheckPostJSON(tests.TestDialClient, addr, postData,
+		tu.Status(re, http.StatusBadRequest), tu.StringContain(re, "leader-schedule-limit should not exceed 64"))
+	re.NoError(err)
+	re.Equal(oldLimit, leaderServer.GetServer().GetScheduleConfig().LeaderScheduleLimit)
+
+	rule := &placement.Rule{GroupID: "pd", ID: "validation", Role: placement.Voter, Count: 7}
+	postData, err = json.Marshal(rule)
+	re.NoError(err)
+	addr = fmt.Sprintf("%s/pd/api/v1/config/rule", urlPrefix)
+	err = tu.CheckPostJSON(tests.TestDialClient, addr, postData,
+		tu.Status(re, http.StatusBadRequest), tu.StringContain(re, "too many replicas"))
+	re.NoError(err)
+	rule.Count = 1
+	postData, err = json.Marshal(rule)
+	re.NoError(err)
+	re.NoError(tu.CheckPostJSON(tests.TestDialClient, addr, postData, tu.StatusOK(re)))
+	re.NoError(tu.CheckDelete(tests.TestDialClient, fmt.Sprintf("%s/pd/%s", addr, rule.ID), tu.StatusOK(re)))
 }
 
 func (suite *configTestSuite) TestConfigReplication() {
@@ -405,6 +474,15 @@ func (suite *configTestSuite) checkConfigPDServer(cluster *tests.TestCluster) {
 	re.Equal(int(3), sc.FlowRoundByDigit)
 	re.Equal(typeutil.NewDuration(time.Second), sc.MinResolvedTSPersistenceInterval)
 	re.Equal(24*time.Hour, sc.MaxResetTSGap.Duration)
+	re.Equal(typeutil.NewDuration(time.Second), sc.SlowEtcdOpThreshold)
+
+	// the slow etcd op threshold takes effect once it's updated.
+	for _, threshold := range []time.Duration{2 * time.Second, time.Second} {
+		postData, err = json.Marshal(map[string]any{"pd-server.slow-etcd-op-threshold": threshold.String()})
+		re.NoError(err)
+		re.NoError(tu.CheckPostJSON(tests.TestDialClient, addrPost, postData, tu.StatusOK(re)))
+		re.Equal(threshold, etcdutil.GetSlowOpThreshold())
+	}
 }
 
 var ttlConfig = map[string]any{
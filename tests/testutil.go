@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"os"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -29,6 +30,7 @@ import (
 	"time"
 
 	"github.com/docker/go-units"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
@@ -191,8 +193,18 @@ func NewSchedulingTestServer(ctx context.Context, cfg *sc.Config) (*scheduling.S
 
 // WaitForPrimaryServing waits for one of servers being elected to be the primary/leader
 func WaitForPrimaryServing(re *require.Assertions, serverMap map[string]bs.Server) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	primary, err := WaitForPrimaryServingCtx(ctx, serverMap)
+	re.NoError(err)
+	return primary
+}
+
+// WaitForPrimaryServingCtx is like WaitForPrimaryServing, but it returns an error
+// instead of failing the test if no primary is serving before the context is done.
+func WaitForPrimaryServingCtx(ctx context.Context, serverMap map[string]bs.Server) (string, error) {
 	var primary string
-	testutil.Eventually(re, func() bool {
+	err := waitUntil(ctx, 50*time.Millisecond, func() bool {
 		for name, s := range serverMap {
 			if s.IsServing() {
 				primary = name
@@ -200,9 +212,48 @@ func WaitForPrimaryServing(re *require.Assertions, serverMap map[string]bs.Serve
 			}
 		}
 		return false
-	}, testutil.WithWaitFor(10*time.Second), testutil.WithTickInterval(50*time.Millisecond))
+	})
+	if err != nil {
+		return "", errors.Annotate(err, "no primary is serving")
+	}
+	return primary, nil
+}
 
-	return primary
+// waitUntil checks the condition every tick until it's satisfied or the context is done.
+func waitUntil(ctx context.Context, tick time.Duration, condition func() bool) error {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		if condition() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+type primaryServer interface {
+	bs.Server
+	IsClosed() bool
+}
+
+// dumpPrimaryState writes whether each server is serving and the primary seen
+// by it, which is the election record of the service.
+func dumpPrimaryState[T primaryServer](b *strings.Builder, service string, servers map[string]T) {
+	fmt.Fprintf(b, "the state of the %s cluster:\n", service)
+	addrs := make([]string, 0, len(servers))
+	for addr := range servers {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		server := servers[addr]
+		fmt.Fprintf(b, "  server %s: serving=%t, closed=%t, primary-urls=%v\n",
+			addr, server.IsServing(), server.IsClosed(), server.GetLeaderListenUrls())
+	}
 }
 
 // MustPutStore is used for test purpose.
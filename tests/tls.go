@@ -0,0 +1,120 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tikv/pd/pkg/utils/grpcutil"
+	"github.com/tikv/pd/server/config"
+)
+
+const testCertValidity = 24 * time.Hour
+
+// GenerateTestTLSConfig generates an ephemeral CA and a certificate signed by
+// it into the given directory. The certificate is valid for 127.0.0.1 and
+// localhost and can be used by both the servers and the clients, so all the
+// connections in the test cluster are mutually authenticated.
+func GenerateTestTLSConfig(dir string) (*grpcutil.TLSConfig, error) {
+	now := time.Now()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pd-test-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(testCertValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "pd-test"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(testCertValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg := &grpcutil.TLSConfig{
+		CAPath:   filepath.Join(dir, "ca.pem"),
+		CertPath: filepath.Join(dir, "pd.pem"),
+		KeyPath:  filepath.Join(dir, "pd-key.pem"),
+	}
+	for path, block := range map[string]*pem.Block{
+		tlsCfg.CAPath:   {Type: "CERTIFICATE", Bytes: caDER},
+		tlsCfg.CertPath: {Type: "CERTIFICATE", Bytes: certDER},
+		tlsCfg.KeyPath:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+			return nil, err
+		}
+	}
+	return tlsCfg, nil
+}
+
+// WithTLS enables TLS for the PD servers of the test cluster with the given
+// certificates, all the URLs are switched to HTTPS.
+func WithTLS(tlsCfg *grpcutil.TLSConfig) ConfigOption {
+	return func(conf *config.Config, _ string) {
+		conf.Security.TLSConfig = *tlsCfg
+		conf.AdvertiseClientUrls = toHTTPS(conf.AdvertiseClientUrls)
+		conf.ClientUrls = toHTTPS(conf.ClientUrls)
+		conf.AdvertisePeerUrls = toHTTPS(conf.AdvertisePeerUrls)
+		conf.PeerUrls = toHTTPS(conf.PeerUrls)
+		conf.InitialCluster = toHTTPS(conf.InitialCluster)
+	}
+}
+
+func toHTTPS(urls string) string {
+	return strings.ReplaceAll(urls, "http://", "https://")
+}
@@ -15,17 +15,31 @@
 package tests
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
 	"sync"
+	"testing"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/stretchr/testify/require"
 	tso "github.com/tikv/pd/pkg/mcs/tso/server"
 	mcsutils "github.com/tikv/pd/pkg/mcs/utils"
 	"github.com/tikv/pd/pkg/storage/endpoint"
+	"github.com/tikv/pd/pkg/utils/grpcutil"
 	"github.com/tikv/pd/pkg/utils/tempurl"
 	"github.com/tikv/pd/pkg/utils/testutil"
+	"github.com/tikv/pd/server/apiv2/handlers"
+	"go.etcd.io/etcd/clientv3"
+	"go.uber.org/goleak"
+	"go.uber.org/multierr"
 )
 
 // TestTSOCluster is a test cluster for TSO.
@@ -35,21 +49,64 @@ type TestTSOCluster struct {
 	backendEndpoints string
 	servers          map[string]*tso.Server
 	cleanupFuncs     map[string]testutil.CleanupFunc
+	// proxies are the fault-injecting proxies between each server and the backend.
+	proxies        map[string][]*faultProxy
+	backendLatency time.Duration
+	// clockOffsets are the offsets of the physical clocks of the servers.
+	clockOffsets map[string]time.Duration
+	// tlsConfig is used by all the servers if it's not nil.
+	tlsConfig *grpcutil.TLSConfig
+	// rootPathPrefix namespaces the keys of all the servers in the backend.
+	rootPathPrefix string
+	// leakBaseline ignores the goroutines which exist before the cluster starts.
+	leakBaseline goleak.Option
 }
 
 // NewTestTSOCluster creates a new TSO test cluster.
 func NewTestTSOCluster(ctx context.Context, initialServerCount int, backendEndpoints string) (tc *TestTSOCluster, err error) {
+	return newTestTSOCluster(ctx, initialServerCount, backendEndpoints, nil, "")
+}
+
+// NewTestTSOClusterWithTLS creates a new TSO test cluster whose servers enable
+// TLS with the given certificates, the backend should enable TLS too.
+func NewTestTSOClusterWithTLS(ctx context.Context, initialServerCount int, backendEndpoints string, tlsCfg *grpcutil.TLSConfig) (tc *TestTSOCluster, err error) {
+	return newTestTSOCluster(ctx, initialServerCount, backendEndpoints, tlsCfg, "")
+}
+
+// NewTestTSOClusterWithRootPathPrefix creates a new TSO test cluster whose keys
+// in the backend are prefixed with the given root path prefix, so the clusters
+// with different prefixes can share one backend without stepping on each other.
+// The servers share the cluster ID with the backend, but they are invisible to
+// the API servers, e.g. BootstrapKeyspaceGroups doesn't work for the cluster.
+func NewTestTSOClusterWithRootPathPrefix(ctx context.Context, initialServerCount int, backendEndpoints, rootPathPrefix string) (tc *TestTSOCluster, err error) {
+	return newTestTSOCluster(ctx, initialServerCount, backendEndpoints, nil, rootPathPrefix)
+}
+
+func newTestTSOCluster(ctx context.Context, initialServerCount int, backendEndpoints string, tlsCfg *grpcutil.TLSConfig, rootPathPrefix string) (tc *TestTSOCluster, err error) {
 	tc = &TestTSOCluster{
 		ctx:              ctx,
 		backendEndpoints: backendEndpoints,
 		servers:          make(map[string]*tso.Server, initialServerCount),
 		cleanupFuncs:     make(map[string]testutil.CleanupFunc, initialServerCount),
+		proxies:          make(map[string][]*faultProxy, initialServerCount),
+		clockOffsets:     make(map[string]time.Duration),
+		tlsConfig:        tlsCfg,
+		rootPathPrefix:   rootPathPrefix,
+		leakBaseline:     goleak.IgnoreCurrent(),
+	}
+	if rootPathPrefix != "" {
+		if err := tc.copyClusterID(); err != nil {
+			return nil, err
+		}
 	}
 	for i := 0; i < initialServerCount; i++ {
-		err = tc.AddServer(tempurl.Alloc())
+		addr, err := tempurl.AllocReserved()
 		if err != nil {
 			return nil, err
 		}
+		if err = tc.AddServer(addr); err != nil {
+			return nil, err
+		}
 	}
 	return tc, nil
 }
@@ -63,6 +120,16 @@ func RestartTestTSOCluster(
 		backendEndpoints: cluster.backendEndpoints,
 		servers:          make(map[string]*tso.Server, len(cluster.servers)),
 		cleanupFuncs:     make(map[string]testutil.CleanupFunc, len(cluster.servers)),
+		// The restarted servers still connect to the backend via the same proxies.
+		proxies:        cluster.proxies,
+		backendLatency: cluster.backendLatency,
+		tlsConfig:      cluster.tlsConfig,
+		rootPathPrefix: cluster.rootPathPrefix,
+		leakBaseline:   cluster.leakBaseline,
+		clockOffsets:   make(map[string]time.Duration, len(cluster.clockOffsets)),
+	}
+	for addr, offset := range cluster.clockOffsets {
+		newCluster.clockOffsets[addr] = offset
 	}
 	var (
 		serverMap  sync.Map
@@ -94,6 +161,10 @@ func RestartTestTSOCluster(
 		newCleanup, _ := cleanupMap.Load(addr)
 		newCluster.servers[addr] = newServer.(*tso.Server)
 		newCluster.cleanupFuncs[addr] = newCleanup.(testutil.CleanupFunc)
+		// the clock of the machine is still skewed after the restart.
+		if offset, ok := newCluster.clockOffsets[addr]; ok {
+			newCluster.servers[addr].GetKeyspaceGroupManager().SetClockOffset(offset)
+		}
 		return true
 	})
 
@@ -106,24 +177,53 @@ func RestartTestTSOCluster(
 
 // AddServer adds a new TSO server to the test cluster.
 func (tc *TestTSOCluster) AddServer(addr string) error {
+	return tc.AddServerWithConfig(addr, nil)
+}
+
+// AddServerWithConfig adds a new TSO server to the test cluster, its config can
+// be changed by the cfgMutator before it starts, e.g. the lease or the log level,
+// but the listen address should not be changed.
+func (tc *TestTSOCluster) AddServerWithConfig(addr string, cfgMutator func(*tso.Config)) error {
+	// Connect to the backend via the proxies, so the faults can be injected.
+	proxies, proxyEndpoints, err := newFaultProxies(tc.backendEndpoints)
+	if err != nil {
+		return err
+	}
+	for _, p := range proxies {
+		p.setLatency(tc.backendLatency)
+	}
 	cfg := tso.NewConfig()
-	cfg.BackendEndpoints = tc.backendEndpoints
+	cfg.BackendEndpoints = proxyEndpoints
 	cfg.ListenAddr = addr
+	if tc.tlsConfig != nil {
+		cfg.Security.TLSConfig = *tc.tlsConfig
+		cfg.ListenAddr = toHTTPS(addr)
+	}
 	cfg.Name = cfg.ListenAddr
 	generatedCfg, err := tso.GenerateConfig(cfg)
 	if err != nil {
+		closeFaultProxies(proxies)
 		return err
 	}
+	generatedCfg.RootPathPrefix = tc.rootPathPrefix
+	if cfgMutator != nil {
+		cfgMutator(generatedCfg)
+	}
 	err = InitLogger(generatedCfg.Log, generatedCfg.Logger, generatedCfg.LogProps, generatedCfg.Security.RedactInfoLog)
 	if err != nil {
+		closeFaultProxies(proxies)
 		return err
 	}
+	// Hand the reserved port over to the server.
+	tempurl.ReleaseReserved(addr)
 	server, cleanup, err := NewTSOTestServer(tc.ctx, generatedCfg)
 	if err != nil {
+		closeFaultProxies(proxies)
 		return err
 	}
 	tc.servers[generatedCfg.GetListenAddr()] = server
 	tc.cleanupFuncs[generatedCfg.GetListenAddr()] = cleanup
+	tc.proxies[generatedCfg.GetListenAddr()] = proxies
 	return nil
 }
 
@@ -132,15 +232,241 @@ func (tc *TestTSOCluster) Destroy() {
 	for _, cleanup := range tc.cleanupFuncs {
 		cleanup()
 	}
+	for _, proxies := range tc.proxies {
+		closeFaultProxies(proxies)
+	}
+	tc.cleanupFuncs = nil
+	tc.servers = nil
+	tc.proxies = nil
+}
+
+// DestroyWithLeakCheck is like Destroy, but it also checks whether the servers
+// leak any backend connection or goroutine after they are stopped. The goroutines
+// which exist before the cluster starts are ignored, and so are the ones matching
+// testutil.LeakOptions or the given allowlist. All the leaks are returned together.
+func (tc *TestTSOCluster) DestroyWithLeakCheck(allowlist ...goleak.Option) error {
+	var errs []error
+	for addr, cleanup := range tc.cleanupFuncs {
+		cleanup()
+		// the proxies need some time to observe the closed connections.
+		var leaked int
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = waitUntil(ctx, 50*time.Millisecond, func() bool {
+			leaked = 0
+			for _, p := range tc.proxies[addr] {
+				leaked += p.activeConns()
+			}
+			return leaked == 0
+		})
+		cancel()
+		if leaked > 0 {
+			errs = append(errs, errors.Errorf("tso server %s leaks %d backend connections", addr, leaked))
+		}
+	}
+	for _, proxies := range tc.proxies {
+		closeFaultProxies(proxies)
+	}
 	tc.cleanupFuncs = nil
 	tc.servers = nil
+	tc.proxies = nil
+
+	opts := append([]goleak.Option{tc.leakBaseline}, testutil.LeakOptions...)
+	if err := goleak.Find(append(opts, allowlist...)...); err != nil {
+		errs = append(errs, err)
+	}
+	return multierr.Combine(errs...)
 }
 
 // DestroyServer stops and destroy the test server by the given address.
 func (tc *TestTSOCluster) DestroyServer(addr string) {
 	tc.cleanupFuncs[addr]()
+	closeFaultProxies(tc.proxies[addr])
 	delete(tc.cleanupFuncs, addr)
 	delete(tc.servers, addr)
+	delete(tc.proxies, addr)
+	delete(tc.clockOffsets, addr)
+}
+
+// DrainAndDestroyServer drains the test server by the given address before destroying it,
+// so that the primaries held by it are handed off to the other servers in advance.
+func (tc *TestTSOCluster) DrainAndDestroyServer(ctx context.Context, addr string) error {
+	server, ok := tc.servers[addr]
+	if !ok {
+		return fmt.Errorf("tso server %s not found", addr)
+	}
+	err := server.Drain(ctx)
+	tc.DestroyServer(addr)
+	return err
+}
+
+// RestartServer stops the TSO server and starts it again with the same address
+// and config, the config can be changed by the cfgMutator before the restart to
+// simulate an upgrade, but the listen address should not be changed. The server
+// still connects to the backend via the same proxies.
+func (tc *TestTSOCluster) RestartServer(addr string, cfgMutator func(*tso.Config)) error {
+	server, ok := tc.servers[addr]
+	if !ok {
+		return fmt.Errorf("tso server %s not found", addr)
+	}
+	cfg := server.GetConfig()
+	tc.cleanupFuncs[addr]()
+	if cfgMutator != nil {
+		cfgMutator(cfg)
+	}
+	newServer, cleanup, err := NewTSOTestServer(tc.ctx, cfg)
+	if err != nil {
+		closeFaultProxies(tc.proxies[addr])
+		delete(tc.cleanupFuncs, addr)
+		delete(tc.servers, addr)
+		delete(tc.proxies, addr)
+		return err
+	}
+	tc.servers[addr] = newServer
+	tc.cleanupFuncs[addr] = cleanup
+	// the clock of the machine is still skewed after the restart.
+	if offset, ok := tc.clockOffsets[addr]; ok {
+		newServer.GetKeyspaceGroupManager().SetClockOffset(offset)
+	}
+	return nil
+}
+
+// RollingRestart restarts the TSO servers one by one like a rolling upgrade,
+// the primary of the default keyspace group is restarted last. It waits for all
+// the keyspace groups to have the primaries before restarting the next server.
+// The cfgMutators are applied to each server before its restart.
+func (tc *TestTSOCluster) RollingRestart(re *require.Assertions, cfgMutators ...func(*tso.Config)) {
+	var primaryAddr string
+	if primary := tc.GetPrimaryServer(mcsutils.DefaultKeyspaceID, mcsutils.DefaultKeyspaceGroupID); primary != nil {
+		primaryAddr = primary.GetAddr()
+	}
+	addrs := make([]string, 0, len(tc.servers))
+	for addr := range tc.servers {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		if (addrs[i] == primaryAddr) != (addrs[j] == primaryAddr) {
+			return addrs[j] == primaryAddr
+		}
+		return addrs[i] < addrs[j]
+	})
+	cfgMutator := func(cfg *tso.Config) {
+		for _, mutate := range cfgMutators {
+			mutate(cfg)
+		}
+	}
+	for _, addr := range addrs {
+		re.NoError(tc.RestartServer(addr, cfgMutator))
+		tc.WaitForPrimariesSettled(re)
+	}
+}
+
+// WaitForPrimariesSettled waits for all the keyspace groups known by the servers
+// to have the primaries.
+func (tc *TestTSOCluster) WaitForPrimariesSettled(re *require.Assertions) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := tc.WaitForPrimariesSettledCtx(ctx); err != nil {
+		re.NoError(err, tc.stateDump())
+	}
+}
+
+// WaitForPrimariesSettledCtx is like WaitForPrimariesSettled, but it returns an
+// error instead of failing the test if the primaries are not settled before the
+// context is done.
+func (tc *TestTSOCluster) WaitForPrimariesSettledCtx(ctx context.Context) error {
+	err := waitUntil(ctx, 100*time.Millisecond, func() bool {
+		groups := make(map[uint32]*endpoint.KeyspaceGroup)
+		for _, server := range tc.servers {
+			if server.IsClosed() || server.GetKeyspaceGroupManager() == nil {
+				return false
+			}
+			for id, group := range server.GetKeyspaceGroupManager().GetKeyspaceGroups() {
+				groups[id] = group
+			}
+		}
+		if len(groups) == 0 {
+			return false
+		}
+		for id, group := range groups {
+			keyspaceID := mcsutils.DefaultKeyspaceID
+			if group != nil && len(group.Keyspaces) > 0 {
+				keyspaceID = group.Keyspaces[0]
+			}
+			if tc.GetPrimaryServer(keyspaceID, id) == nil {
+				return false
+			}
+		}
+		return true
+	})
+	return errors.Annotate(err, "the primaries of the keyspace groups are not settled")
+}
+
+// PartitionServer cuts the network between the server and the backend, the
+// existing connections are closed and the new ones are refused until the
+// partition is healed by HealServer. The server will lose its primaries once
+// the leases expire.
+func (tc *TestTSOCluster) PartitionServer(addr string) error {
+	proxies, ok := tc.proxies[addr]
+	if !ok {
+		return fmt.Errorf("tso server %s not found", addr)
+	}
+	for _, p := range proxies {
+		p.partition(true)
+	}
+	return nil
+}
+
+// HealServer heals the network partition of the server.
+func (tc *TestTSOCluster) HealServer(addr string) error {
+	proxies, ok := tc.proxies[addr]
+	if !ok {
+		return fmt.Errorf("tso server %s not found", addr)
+	}
+	for _, p := range proxies {
+		p.partition(false)
+	}
+	return nil
+}
+
+// PauseServerBackend freezes the traffic between the server and the backend for
+// the given duration without closing the connections, which looks like a process
+// freeze, e.g. a long GC pause, to the backend. The clients still reach the
+// server directly, so it keeps serving the requests that don't need the backend.
+func (tc *TestTSOCluster) PauseServerBackend(addr string, d time.Duration) error {
+	proxies, ok := tc.proxies[addr]
+	if !ok {
+		return fmt.Errorf("tso server %s not found", addr)
+	}
+	for _, p := range proxies {
+		p.pause(d)
+	}
+	return nil
+}
+
+// InjectBackendLatency delays the traffic between all the servers and the
+// backend by the given duration to simulate the slow etcd, 0 means no delay.
+// It also applies to the servers added later.
+func (tc *TestTSOCluster) InjectBackendLatency(d time.Duration) {
+	tc.backendLatency = d
+	for _, proxies := range tc.proxies {
+		for _, p := range proxies {
+			p.setLatency(d)
+		}
+	}
+}
+
+// SetClockOffset offsets the physical clock of the server by the given duration
+// to simulate the clock skew, a negative offset makes the clock jump back. It
+// applies to all the keyspace groups served by the server and survives the
+// restart of the server, 0 means no offset.
+func (tc *TestTSOCluster) SetClockOffset(addr string, offset time.Duration) error {
+	server, ok := tc.servers[addr]
+	if !ok {
+		return fmt.Errorf("tso server %s not found", addr)
+	}
+	server.GetKeyspaceGroupManager().SetClockOffset(offset)
+	tc.clockOffsets[addr] = offset
+	return nil
 }
 
 // ResignPrimary resigns the primary TSO server.
@@ -164,25 +490,82 @@ func (tc *TestTSOCluster) GetPrimaryServer(keyspaceID, keyspaceGroupID uint32) *
 
 // WaitForPrimaryServing waits for one of servers being elected to be the primary/leader of the given keyspace.
 func (tc *TestTSOCluster) WaitForPrimaryServing(re *require.Assertions, keyspaceID, keyspaceGroupID uint32) *tso.Server {
-	var primary *tso.Server
-	testutil.Eventually(re, func() bool {
-		for _, server := range tc.servers {
-			if server.IsKeyspaceServing(keyspaceID, keyspaceGroupID) {
-				primary = server
-				return true
-			}
-		}
-		return false
-	}, testutil.WithWaitFor(30*time.Second), testutil.WithTickInterval(100*time.Millisecond))
-
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	primary, err := tc.WaitForPrimaryServingCtx(ctx, keyspaceID, keyspaceGroupID)
+	if err != nil {
+		re.NoError(err, tc.stateDump())
+	}
 	return primary
 }
 
+// WaitForPrimaryServingCtx is like WaitForPrimaryServing, but it returns an error
+// instead of failing the test if no primary is serving before the context is done.
+func (tc *TestTSOCluster) WaitForPrimaryServingCtx(ctx context.Context, keyspaceID, keyspaceGroupID uint32) (*tso.Server, error) {
+	var primary *tso.Server
+	err := waitUntil(ctx, 100*time.Millisecond, func() bool {
+		primary = tc.GetPrimaryServer(keyspaceID, keyspaceGroupID)
+		return primary != nil
+	})
+	if err != nil {
+		return nil, errors.Annotatef(err, "no primary is serving keyspace %d in keyspace group %d", keyspaceID, keyspaceGroupID)
+	}
+	return primary, nil
+}
+
 // WaitForDefaultPrimaryServing waits for one of servers being elected to be the primary/leader of the default keyspace.
 func (tc *TestTSOCluster) WaitForDefaultPrimaryServing(re *require.Assertions) *tso.Server {
 	return tc.WaitForPrimaryServing(re, mcsutils.DefaultKeyspaceID, mcsutils.DefaultKeyspaceGroupID)
 }
 
+// WaitForDefaultPrimaryServingCtx is like WaitForDefaultPrimaryServing, but it returns
+// an error instead of failing the test if no primary is serving before the context is done.
+func (tc *TestTSOCluster) WaitForDefaultPrimaryServingCtx(ctx context.Context) (*tso.Server, error) {
+	return tc.WaitForPrimaryServingCtx(ctx, mcsutils.DefaultKeyspaceID, mcsutils.DefaultKeyspaceGroupID)
+}
+
+// BootstrapKeyspaceGroups creates the keyspace groups through the backend, the
+// groups without members are served by all the servers. It blocks until every
+// keyspace of the groups is served by a primary.
+func (tc *TestTSOCluster) BootstrapKeyspaceGroups(re *require.Assertions, groups []endpoint.KeyspaceGroup) {
+	params := &handlers.CreateKeyspaceGroupParams{KeyspaceGroups: make([]*endpoint.KeyspaceGroup, 0, len(groups))}
+	for i := range groups {
+		group := groups[i]
+		if len(group.Members) == 0 {
+			group.Members = tc.GetKeyspaceGroupMember()
+		}
+		if group.UserKind == "" {
+			group.UserKind = endpoint.Standard.String()
+		}
+		params.KeyspaceGroups = append(params.KeyspaceGroups, &group)
+	}
+	data, err := json.Marshal(params)
+	re.NoError(err)
+	client := TestDialClient
+	if tc.tlsConfig != nil {
+		tlsCfg, err := tc.tlsConfig.ToTLSConfig()
+		re.NoError(err)
+		client = &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+	}
+	backend := strings.Split(tc.backendEndpoints, ",")[0]
+	resp, err := client.Post(backend+"/pd/api/v2/tso/keyspace-groups", "application/json", bytes.NewBuffer(data))
+	re.NoError(err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	re.NoError(err)
+	re.Equal(http.StatusOK, resp.StatusCode, string(body))
+
+	for _, group := range params.KeyspaceGroups {
+		keyspaceIDs := group.Keyspaces
+		if len(keyspaceIDs) == 0 {
+			keyspaceIDs = []uint32{mcsutils.DefaultKeyspaceID}
+		}
+		for _, keyspaceID := range keyspaceIDs {
+			tc.WaitForPrimaryServing(re, keyspaceID, group.ID)
+		}
+	}
+}
+
 // GetServer returns the TSO server by the given address.
 func (tc *TestTSOCluster) GetServer(addr string) *tso.Server {
 	for srvAddr, server := range tc.servers {
@@ -217,3 +600,86 @@ func (tc *TestTSOCluster) GetAddrs() []string {
 	}
 	return addrs
 }
+
+// copyClusterID copies the cluster ID of the backend under the root path prefix,
+// which is read by the servers when they start.
+func (tc *TestTSOCluster) copyClusterID() error {
+	var tlsCfg *tls.Config
+	if tc.tlsConfig != nil {
+		var err error
+		if tlsCfg, err = tc.tlsConfig.ToTLSConfig(); err != nil {
+			return err
+		}
+	}
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(tc.backendEndpoints, ","),
+		DialTimeout: 3 * time.Second,
+		TLS:         tlsCfg,
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(tc.ctx, 10*time.Second)
+	defer cancel()
+	resp, err := client.Get(ctx, mcsutils.ClusterIDPath)
+	if err != nil {
+		return err
+	}
+	if len(resp.Kvs) == 0 {
+		return errors.New("the cluster ID of the backend is not initialized")
+	}
+	_, err = client.Put(ctx, tc.rootPathPrefix+mcsutils.ClusterIDPath, string(resp.Kvs[0].Value))
+	return err
+}
+
+// DumpState writes the state of the servers to the test log, including whether
+// each server is serving, the keyspace groups assigned to it and the election
+// records of the groups seen by it. The waiters dump the state automatically
+// when they time out.
+func (tc *TestTSOCluster) DumpState(t testing.TB) {
+	t.Log(tc.stateDump())
+}
+
+func (tc *TestTSOCluster) stateDump() string {
+	var b strings.Builder
+	b.WriteString("the state of the TSO cluster:\n")
+	addrs := make([]string, 0, len(tc.servers))
+	for addr := range tc.servers {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		server := tc.servers[addr]
+		fmt.Fprintf(&b, "  server %s: serving=%t, closed=%t\n", addr, server.IsServing(), server.IsClosed())
+		kgm := server.GetKeyspaceGroupManager()
+		if kgm == nil {
+			continue
+		}
+		groups := kgm.GetKeyspaceGroups()
+		ids := make([]uint32, 0, len(groups))
+		for id := range groups {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			fmt.Fprintf(&b, "    keyspace group %d:", id)
+			if group := groups[id]; group != nil {
+				members := make([]string, 0, len(group.Members))
+				for _, member := range group.Members {
+					members = append(members, member.Address)
+				}
+				fmt.Fprintf(&b, " keyspaces=%v, members=%v,", group.Keyspaces, members)
+			}
+			am, err := kgm.GetAllocatorManager(id)
+			if err != nil {
+				fmt.Fprintf(&b, " %v\n", err)
+				continue
+			}
+			member := am.GetMember()
+			fmt.Fprintf(&b, " primary=%t, election={path: %s, leader-id: %d, leader-urls: %v}\n",
+				member.IsLeader(), member.GetLeaderPath(), member.GetLeaderID(), member.GetLeaderListenUrls())
+		}
+	}
+	return b.String()
+}
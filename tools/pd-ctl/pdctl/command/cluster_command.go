@@ -14,7 +14,17 @@
 
 package command
 
-import "github.com/spf13/cobra"
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tikv/pd/server/cluster"
+)
+
+const clusterCheckPrefix = "pd/api/v1/cluster/check"
 
 // NewClusterCommand return a cluster subcommand of rootCmd
 func NewClusterCommand() *cobra.Command {
@@ -25,9 +35,22 @@ func NewClusterCommand() *cobra.Command {
 		Run:               showClusterCommandFunc,
 	}
 	cmd.AddCommand(NewClusterStatusCommand())
+	cmd.AddCommand(NewClusterCheckCommand())
 	return cmd
 }
 
+// NewClusterCheckCommand return a cluster check subcommand of clusterCmd
+func NewClusterCheckCommand() *cobra.Command {
+	r := &cobra.Command{
+		Use:   "check [--stale-safepoint <duration>] [--json]",
+		Short: "check the cluster health and show a pass/warn/fail report",
+		Run:   checkClusterCommandFunc,
+	}
+	r.Flags().String("stale-safepoint", "", "the lag after which the GC safe point is stale, 24h by default")
+	r.Flags().Bool("json", false, "show the report in JSON format")
+	return r
+}
+
 // NewClusterStatusCommand return a cluster status subcommand of clusterCmd
 func NewClusterStatusCommand() *cobra.Command {
 	r := &cobra.Command{
@@ -55,3 +78,32 @@ func showClusterStatusCommandFunc(cmd *cobra.Command, _ []string) {
 	}
 	jsonPrint(cmd, status)
 }
+
+func checkClusterCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	prefix := clusterCheckPrefix
+	if stale, _ := cmd.Flags().GetString("stale-safepoint"); stale != "" {
+		prefix += "?stale_safepoint=" + url.QueryEscape(stale)
+	}
+	r, err := doRequest(cmd, prefix, http.MethodGet, http.Header{})
+	if err != nil {
+		cmd.Printf("Failed to check the cluster: %s\n", err)
+		return
+	}
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		cmd.Println(r)
+		return
+	}
+	report := &cluster.CheckReport{}
+	if err := json.Unmarshal([]byte(r), report); err != nil {
+		cmd.Printf("Failed to check the cluster: %s\n", err)
+		return
+	}
+	for _, item := range report.Items {
+		cmd.Printf("[%s] %s: %s\n", strings.ToUpper(item.Status), item.Name, item.Message)
+	}
+	cmd.Printf("\nOverall: %s\n", strings.ToUpper(report.Status))
+}
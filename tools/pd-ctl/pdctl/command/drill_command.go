@@ -0,0 +1,402 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/spf13/cobra"
+	"github.com/tikv/pd/pkg/response"
+)
+
+const (
+	drillStepResignPDLeader     = "resign-pd-leader"
+	drillStepResignTSOPrimaries = "resign-tso-primaries"
+	drillStepEvictLeaders       = "evict-leaders"
+
+	drillConfirmation = "drill"
+
+	healthPrefix             = "pd/api/v1/health"
+	tsoMembersPrefix         = "pd/api/v2/ms/members/tso"
+	tsoHealthDetailPrefix    = "tso/api/v1/health/detail"
+	tsoResignPrimariesPrefix = "tso/api/v1/admin/resign-primaries"
+)
+
+// drillSteps are the supported steps of the failover drill in the default order.
+var drillSteps = []string{drillStepResignPDLeader, drillStepResignTSOPrimaries, drillStepEvictLeaders}
+
+// drillReport is the report of the failover drill.
+type drillReport struct {
+	Passed bool               `json:"passed"`
+	Steps  []*drillStepReport `json:"steps"`
+}
+
+// drillStepReport is the result of a step of the failover drill.
+type drillStepReport struct {
+	Step      string    `json:"step"`
+	StartTime time.Time `json:"start-time"`
+	// RecoveryTime is the time from the fault is injected to the cluster recovers.
+	RecoveryTime string `json:"recovery-time,omitempty"`
+	Skipped      bool   `json:"skipped,omitempty"`
+	Detail       string `json:"detail,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+type drillOptions struct {
+	store    uint64
+	timeout  time.Duration
+	interval time.Duration
+}
+
+type drillStepFunc func(cmd *cobra.Command, step *drillStepReport, opts *drillOptions) error
+
+// NewDrillCommand returns the failover drill subcommand of rootCmd.
+func NewDrillCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drill [--steps=<step>,...] [--store=<store_id>] [--timeout=<duration>] [--yes]",
+		Short: "run a failover drill, which injects the faults step by step and reports the recovery time of each step",
+		Long: fmt.Sprintf(`run a failover drill, which injects the faults step by step and reports the recovery time of each step.
+The supported steps are:
+  %s: resign the PD leader, it recovers once another member becomes the leader.
+  %s: resign the primaries of all the keyspace groups on the TSO servers, it recovers once every keyspace group is served again.
+  %s: evict the leaders from the store, it recovers once the store has no leader. The eviction is removed after the step, and the step is skipped if the store is already being evicted.
+The drill stops at the first failed step, and it doesn't start a step unless all the PD members are healthy.`,
+			drillStepResignPDLeader, drillStepResignTSOPrimaries, drillStepEvictLeaders),
+		Run: drillCommandFunc,
+	}
+	cmd.Flags().String("steps", strings.Join(drillSteps, ","), "the steps to execute in order")
+	cmd.Flags().Uint64("store", 0, "the store to evict the leaders from, it's required by the evict-leaders step")
+	cmd.Flags().Duration("timeout", time.Minute, "the max time to wait for the recovery of each step")
+	cmd.Flags().Duration("interval", 100*time.Millisecond, "the interval of checking the recovery")
+	cmd.Flags().Bool("yes", false, "run the drill without the confirmation")
+	return cmd
+}
+
+func drillCommandFunc(cmd *cobra.Command, _ []string) {
+	stepsFlag, err := cmd.Flags().GetString("steps")
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	opts := &drillOptions{}
+	if opts.store, err = cmd.Flags().GetUint64("store"); err != nil {
+		cmd.Println(err)
+		return
+	}
+	if opts.timeout, err = cmd.Flags().GetDuration("timeout"); err != nil {
+		cmd.Println(err)
+		return
+	}
+	if opts.interval, err = cmd.Flags().GetDuration("interval"); err != nil {
+		cmd.Println(err)
+		return
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	stepFuncs := map[string]drillStepFunc{
+		drillStepResignPDLeader:     drillResignPDLeader,
+		drillStepResignTSOPrimaries: drillResignTSOPrimaries,
+		drillStepEvictLeaders:       drillEvictLeaders,
+	}
+	steps := strings.Split(stepsFlag, ",")
+	for i, step := range steps {
+		steps[i] = strings.TrimSpace(step)
+		if _, ok := stepFuncs[steps[i]]; !ok {
+			cmd.Printf("Unknown step %q, the supported steps are %s\n", steps[i], strings.Join(drillSteps, ","))
+			return
+		}
+		if steps[i] == drillStepEvictLeaders && opts.store == 0 {
+			cmd.Printf("The store is required by the %s step, please specify it by --store\n", drillStepEvictLeaders)
+			return
+		}
+	}
+
+	if !yes {
+		cmd.Printf("The drill injects the faults into the cluster in order: %s\n", strings.Join(steps, ", "))
+		cmd.Printf("Type %q to continue: ", drillConfirmation)
+		line, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if strings.TrimSpace(line) != drillConfirmation {
+			cmd.Println("Aborted, the confirmation doesn't match")
+			return
+		}
+	}
+
+	report := &drillReport{Passed: true}
+	for _, name := range steps {
+		step := &drillStepReport{Step: name}
+		report.Steps = append(report.Steps, step)
+		if err = checkDrillHealth(cmd); err == nil {
+			err = stepFuncs[name](cmd, step, opts)
+		}
+		if err != nil {
+			step.Error = err.Error()
+			report.Passed = false
+			break
+		}
+	}
+	jsonPrint(cmd, report)
+}
+
+// checkDrillHealth checks all the PD members are healthy before injecting a fault.
+func checkDrillHealth(cmd *cobra.Command) error {
+	resp, err := doRequest(cmd, healthPrefix, http.MethodGet, http.Header{})
+	if err != nil {
+		return err
+	}
+	var healths []struct {
+		Name   string `json:"name"`
+		Health bool   `json:"health"`
+	}
+	if err = json.Unmarshal([]byte(resp), &healths); err != nil {
+		return err
+	}
+	for _, health := range healths {
+		if !health.Health {
+			return errors.Errorf("PD member %s is unhealthy", health.Name)
+		}
+	}
+	return nil
+}
+
+func drillResignPDLeader(cmd *cobra.Command, step *drillStepReport, opts *drillOptions) error {
+	resp, err := doRequest(cmd, membersPrefix, http.MethodGet, http.Header{})
+	if err != nil {
+		return err
+	}
+	var members struct {
+		Members []json.RawMessage `json:"members"`
+	}
+	if err = json.Unmarshal([]byte(resp), &members); err != nil {
+		return err
+	}
+	if len(members.Members) < 2 {
+		return errors.New("no other PD member can take over the leadership")
+	}
+	oldLeader, err := getDrillPDLeader(cmd)
+	if err != nil {
+		return err
+	}
+	step.StartTime = time.Now()
+	if _, err = doRequest(cmd, leaderResignPrefix, http.MethodPost, http.Header{}); err != nil {
+		return err
+	}
+	var newLeader string
+	if err = waitDrillRecovery(step, opts, func() bool {
+		leader, err := getDrillPDLeader(cmd)
+		newLeader = leader
+		return err == nil && leader != "" && leader != oldLeader
+	}); err != nil {
+		return err
+	}
+	step.Detail = fmt.Sprintf("the leader is transferred from %s to %s", oldLeader, newLeader)
+	return nil
+}
+
+func getDrillPDLeader(cmd *cobra.Command) (string, error) {
+	resp, err := doRequest(cmd, leaderMemberPrefix, http.MethodGet, http.Header{})
+	if err != nil {
+		return "", err
+	}
+	var leader struct {
+		Name string `json:"name"`
+	}
+	if err = json.Unmarshal([]byte(resp), &leader); err != nil {
+		return "", err
+	}
+	return leader.Name, nil
+}
+
+func drillResignTSOPrimaries(cmd *cobra.Command, step *drillStepReport, opts *drillOptions) error {
+	apiServiceMode, err := isAPIServiceMode(cmd)
+	if err != nil {
+		return err
+	}
+	if !apiServiceMode {
+		// The TSO is served by the PD leader if there is no TSO server.
+		step.Skipped = true
+		step.Detail = fmt.Sprintf("no TSO server is found, the TSO is served by the PD leader, see %s", drillStepResignPDLeader)
+		return nil
+	}
+	resp, err := doRequest(cmd, tsoMembersPrefix, http.MethodGet, http.Header{})
+	if err != nil {
+		return err
+	}
+	var nodes []struct {
+		ServiceAddr string `json:"service-addr"`
+	}
+	if err = json.Unmarshal([]byte(resp), &nodes); err != nil {
+		return err
+	}
+	step.StartTime = time.Now()
+	resigned := make(map[uint32]struct{})
+	for _, node := range nodes {
+		resp, err := doRequestSingleEndpoint(cmd, node.ServiceAddr, tsoResignPrimariesPrefix, http.MethodPost, http.Header{})
+		if err != nil {
+			return errors.Errorf("failed to resign the primaries on %s: %s", node.ServiceAddr, err)
+		}
+		var groupIDs []uint32
+		if err = json.Unmarshal([]byte(resp), &groupIDs); err != nil {
+			return err
+		}
+		for _, id := range groupIDs {
+			resigned[id] = struct{}{}
+		}
+	}
+	if err = waitDrillRecovery(step, opts, func() bool {
+		serving := make(map[uint32]struct{}, len(resigned))
+		for _, node := range nodes {
+			resp, err := doRequestSingleEndpoint(cmd, node.ServiceAddr, tsoHealthDetailPrefix, http.MethodGet, http.Header{})
+			if err != nil {
+				continue
+			}
+			var health struct {
+				KeyspaceGroups map[uint32]struct {
+					Serving bool `json:"serving"`
+				} `json:"keyspace_groups"`
+			}
+			if json.Unmarshal([]byte(resp), &health) != nil {
+				continue
+			}
+			for id, group := range health.KeyspaceGroups {
+				if group.Serving {
+					serving[id] = struct{}{}
+				}
+			}
+		}
+		for id := range resigned {
+			if _, ok := serving[id]; !ok {
+				return false
+			}
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+	step.Detail = fmt.Sprintf("the primaries of %d keyspace groups on %d TSO servers are resigned", len(resigned), len(nodes))
+	return nil
+}
+
+func drillEvictLeaders(cmd *cobra.Command, step *drillStepReport, opts *drillOptions) error {
+	// The existing eviction is left in place, since removing it after the step
+	// would bring the leaders back against the will of the operator.
+	evicting, err := isDrillStoreEvicting(cmd, opts.store)
+	if err != nil {
+		return err
+	}
+	if evicting {
+		step.Skipped = true
+		step.Detail = fmt.Sprintf("the leaders of store %d are already being evicted", opts.store)
+		return nil
+	}
+	leaderCount, err := getDrillStoreLeaderCount(cmd, opts.store)
+	if err != nil {
+		return err
+	}
+	if leaderCount == 0 {
+		return errors.Errorf("store %d has no leader to evict", opts.store)
+	}
+	input, err := json.Marshal(map[string]any{
+		"name":     evictLeaderSchedulerName,
+		"store_id": opts.store,
+	})
+	if err != nil {
+		return err
+	}
+	step.StartTime = time.Now()
+	if _, err = doRequest(cmd, schedulersPrefix, http.MethodPost, http.Header{"Content-Type": {"application/json"}},
+		WithBody(strings.NewReader(string(input)))); err != nil {
+		return err
+	}
+	defer func() {
+		prefix := fmt.Sprintf("%s/%s-%d", schedulersPrefix, evictLeaderSchedulerName, opts.store)
+		if _, err := doRequest(cmd, prefix, http.MethodDelete, http.Header{}); err != nil {
+			cmd.Printf("Failed to remove the leader eviction of store %d, please remove it manually: %s\n", opts.store, err)
+		}
+	}()
+	if err = waitDrillRecovery(step, opts, func() bool {
+		count, err := getDrillStoreLeaderCount(cmd, opts.store)
+		return err == nil && count == 0
+	}); err != nil {
+		return err
+	}
+	step.Detail = fmt.Sprintf("%d leaders are evicted from store %d", leaderCount, opts.store)
+	return nil
+}
+
+// isDrillStoreEvicting returns whether the store is in the config of the evict-leader-scheduler.
+func isDrillStoreEvicting(cmd *cobra.Command, storeID uint64) (bool, error) {
+	resp, err := doRequest(cmd, schedulersPrefix, http.MethodGet, http.Header{})
+	if err != nil {
+		return false, err
+	}
+	var schedulers []string
+	if err = json.Unmarshal([]byte(resp), &schedulers); err != nil {
+		return false, err
+	}
+	if !slices.Contains(schedulers, evictLeaderSchedulerName) {
+		return false, nil
+	}
+	resp, err = doRequest(cmd, path.Join(schedulerConfigPrefix, evictLeaderSchedulerName, "list"), http.MethodGet, http.Header{})
+	if err != nil {
+		return false, err
+	}
+	var conf struct {
+		StoreIDWithRanges map[uint64]json.RawMessage `json:"store-id-ranges"`
+	}
+	if err = json.Unmarshal([]byte(resp), &conf); err != nil {
+		return false, err
+	}
+	_, ok := conf.StoreIDWithRanges[storeID]
+	return ok, nil
+}
+
+func getDrillStoreLeaderCount(cmd *cobra.Command, storeID uint64) (int, error) {
+	resp, err := doRequest(cmd, fmt.Sprintf(storePrefix, storeID), http.MethodGet, http.Header{})
+	if err != nil {
+		return 0, err
+	}
+	store := &response.StoreInfo{}
+	if err = json.Unmarshal([]byte(resp), store); err != nil {
+		return 0, err
+	}
+	if store.Status == nil {
+		return 0, nil
+	}
+	return store.Status.LeaderCount, nil
+}
+
+// waitDrillRecovery waits until the cluster is recovered from the fault injected at the
+// start time of the step, and records the recovery time.
+func waitDrillRecovery(step *drillStepReport, opts *drillOptions, recovered func() bool) error {
+	for !recovered() {
+		if time.Since(step.StartTime) > opts.timeout {
+			return errors.Errorf("not recovered in %s", opts.timeout)
+		}
+		time.Sleep(opts.interval)
+	}
+	step.RecoveryTime = time.Since(step.StartTime).String()
+	return nil
+}
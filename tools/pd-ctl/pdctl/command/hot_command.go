@@ -24,6 +24,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
+	"github.com/tikv/pd/pkg/schedule/handler"
 	"github.com/tikv/pd/pkg/storage"
 )
 
@@ -33,6 +34,7 @@ const (
 	hotStoresPrefix         = "pd/api/v1/hotspot/stores"
 	hotRegionsHistoryPrefix = "pd/api/v1/hotspot/regions/history"
 	hotBucketsPrefix        = "pd/api/v1/hotspot/buckets"
+	hotAnalysisPrefix       = "pd/api/v1/hotspot/analysis"
 )
 
 // NewHotSpotCommand return a hot subcommand of rootCmd
@@ -46,6 +48,7 @@ func NewHotSpotCommand() *cobra.Command {
 	cmd.AddCommand(NewHotStoreCommand())
 	cmd.AddCommand(NewHotRegionsHistoryCommand())
 	cmd.AddCommand(NewHotBucketsCommand())
+	cmd.AddCommand(NewHotAnalyzeCommand())
 	return cmd
 }
 
@@ -153,6 +156,77 @@ func showHotBucketsCommandFunc(cmd *cobra.Command, args []string) {
 	cmd.Println(r)
 }
 
+// NewHotAnalyzeCommand return a hot analyze subcommand of hotSpotCmd
+func NewHotAnalyzeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze [write|read] [--json]",
+		Short: "analyze the hotspot and show the recommended actions",
+		Run:   analyzeHotCommandFunc,
+	}
+	cmd.Flags().Bool("json", false, "show the analysis in JSON format")
+	return cmd
+}
+
+func analyzeHotCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		cmd.Println(cmd.UsageString())
+		return
+	}
+	prefix := hotAnalysisPrefix
+	if len(args) == 1 {
+		if args[0] != "write" && args[0] != "read" {
+			cmd.Println(cmd.UsageString())
+			return
+		}
+		prefix += "?type=" + args[0]
+	}
+	r, err := doRequest(cmd, prefix, http.MethodGet, http.Header{})
+	if err != nil {
+		cmd.Printf("Failed to analyze hotspot: %s\n", err)
+		return
+	}
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		cmd.Println(r)
+		return
+	}
+	analysis := &handler.HotAnalysis{}
+	if err := json.Unmarshal([]byte(r), analysis); err != nil {
+		cmd.Printf("Failed to analyze hotspot: %s\n", err)
+		return
+	}
+	printHotAnalysis(cmd, analysis)
+}
+
+func printHotAnalysis(cmd *cobra.Command, analysis *handler.HotAnalysis) {
+	cmd.Printf("Average %s byte rate of stores: %.2f\n", analysis.Type, analysis.AverageByteRate)
+	if len(analysis.SkewedStores) == 0 {
+		cmd.Println("\nNo skewed stores.")
+	} else {
+		cmd.Println("\nSkewed stores:")
+		for _, store := range analysis.SkewedStores {
+			cmd.Printf("  store %d: byte rate %.2f (%.1fx of average), %d hot regions\n",
+				store.StoreID, store.ByteRate, store.Ratio, store.HotRegionCount)
+		}
+	}
+	if len(analysis.HotRegions) == 0 {
+		cmd.Println("\nNo hot regions.")
+	} else {
+		cmd.Println("\nTop hot regions:")
+		for _, region := range analysis.HotRegions {
+			cmd.Printf("  region %d on store %d: byte rate %.2f, key rate %.2f, query rate %.2f, %.0f%% of the store\n",
+				region.RegionID, region.StoreID, region.ByteRate, region.KeyRate, region.QueryRate, region.Share*100)
+		}
+	}
+	if len(analysis.Recommendations) == 0 {
+		cmd.Println("\nNo actions are recommended.")
+		return
+	}
+	cmd.Println("\nRecommended actions:")
+	for _, r := range analysis.Recommendations {
+		cmd.Printf("  [%s] %s\n    pd-ctl %s\n", r.Action, r.Reason, r.Command)
+	}
+}
+
 func showHotRegionsHistoryCommandFunc(cmd *cobra.Command, args []string) {
 	if len(args) < 2 || len(args)%2 != 0 {
 		cmd.Println(cmd.UsageString())
@@ -160,6 +160,7 @@ func NewAddSchedulerCommand() *cobra.Command {
 	c.AddCommand(NewSlowTrendEvictLeaderSchedulerCommand())
 	c.AddCommand(NewBalanceWitnessSchedulerCommand())
 	c.AddCommand(NewTransferWitnessLeaderSchedulerCommand())
+	c.AddCommand(NewCompactionPressureSchedulerCommand())
 	return c
 }
 
@@ -381,6 +382,16 @@ func NewTransferWitnessLeaderSchedulerCommand() *cobra.Command {
 	return c
 }
 
+// NewCompactionPressureSchedulerCommand returns a command to add a compaction-pressure-scheduler.
+func NewCompactionPressureSchedulerCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "compaction-pressure-scheduler",
+		Short: "add a scheduler to move leaders off the stores under compaction pressure",
+		Run:   addSchedulerCommandFunc,
+	}
+	return c
+}
+
 // NewSlowTrendEvictLeaderSchedulerCommand returns a command to add a evict-slow-trend-scheduler.
 func NewSlowTrendEvictLeaderSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
@@ -15,12 +15,29 @@
 package command
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
+	pd "github.com/tikv/pd/client"
 	"github.com/tikv/pd/pkg/utils/tsoutil"
 )
 
+const (
+	leaderResignPrefix = "pd/api/v1/leader/resign"
+	msHandshakePrefix  = "pd/api/v2/ms/handshake"
+	tsoPrimaryPrefix   = "pd/api/v2/ms/primary/tso"
+
+	// tsoSelfTestMinBackoff and tsoSelfTestMaxBackoff bound the backoff of
+	// acquiring the timestamps after a failure.
+	tsoSelfTestMinBackoff = 10 * time.Millisecond
+	tsoSelfTestMaxBackoff = time.Second
+)
+
 // NewTSOCommand return a TSO subcommand of rootCmd
 func NewTSOCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -28,6 +45,19 @@ func NewTSOCommand() *cobra.Command {
 		Short: "parse TSO to the system and logic time",
 		Run:   showTSOCommandFunc,
 	}
+	cmd.AddCommand(NewTSOSelfTestCommand())
+	return cmd
+}
+
+// NewTSOSelfTestCommand return a TSO self test subcommand of tsoCmd
+func NewTSOSelfTestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "selftest [--duration=<duration>] [--failover-interval=<duration>]",
+		Short: "acquire timestamps while failing over the TSO service, and verify the timestamps are monotonic",
+		Run:   tsoSelfTestCommandFunc,
+	}
+	cmd.Flags().Duration("duration", 30*time.Second, "the duration of the self test")
+	cmd.Flags().Duration("failover-interval", 10*time.Second, "the interval to resign the TSO primary, or the PD leader if the TSO is served by PD, 0 means no failover")
 	return cmd
 }
 
@@ -46,3 +76,150 @@ func showTSOCommandFunc(cmd *cobra.Command, args []string) {
 	cmd.Println("system: ", physicalTime)
 	cmd.Println("logic:  ", logical)
 }
+
+// tsoSelfTestReport is the result of the TSO self test.
+type tsoSelfTestReport struct {
+	Requests       int    `json:"requests"`
+	Errors         int    `json:"errors"`
+	FailoverTarget string `json:"failover-target"`
+	Failovers      int    `json:"failovers"`
+	FailoverErrors int    `json:"failover-errors"`
+	Monotonic      bool   `json:"monotonic"`
+	Violations     int    `json:"violations"`
+	MaxStall       string `json:"max-stall"`
+}
+
+// tsoSelfTestCommandFunc acquires the timestamps one by one and resigns the
+// TSO primary periodically, which is the primary of the keyspace group in API
+// service mode and the PD leader otherwise. It verifies the timestamps are
+// strictly increasing and reports the max stall, which is the max interval
+// between two successfully acquired timestamps.
+func tsoSelfTestCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cmd.Usage()
+		return
+	}
+	duration, err := cmd.Flags().GetDuration("duration")
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	failoverInterval, err := cmd.Flags().GetDuration("failover-interval")
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	security := pd.SecurityOption{}
+	security.CAPath, _ = cmd.Flags().GetString("cacert")
+	security.CertPath, _ = cmd.Flags().GetString("cert")
+	security.KeyPath, _ = cmd.Flags().GetString("key")
+
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+	cli, err := pd.NewClientWithContext(ctx, getEndpoints(cmd), security)
+	if err != nil {
+		cmd.Printf("Failed to create the PD client: %s\n", err)
+		return
+	}
+	defer cli.Close()
+
+	report := &tsoSelfTestReport{Monotonic: true}
+	var failoverC <-chan time.Time
+	if failoverInterval > 0 {
+		apiServiceMode, err := isAPIServiceMode(cmd)
+		if err != nil {
+			cmd.Printf("Failed to get the deployment mode: %s\n", err)
+			return
+		}
+		report.FailoverTarget = "pd-leader"
+		if apiServiceMode {
+			report.FailoverTarget = "tso-primary"
+		}
+		ticker := time.NewTicker(failoverInterval)
+		defer ticker.Stop()
+		failoverC = ticker.C
+	}
+	var (
+		last     uint64
+		maxStall time.Duration
+		lastTime = time.Now()
+		backoff  = tsoSelfTestMinBackoff
+	)
+	for ctx.Err() == nil {
+		select {
+		case <-failoverC:
+			report.Failovers++
+			if err := resignTSOPrimary(cmd, report.FailoverTarget == "tso-primary"); err != nil {
+				report.FailoverErrors++
+			}
+		default:
+		}
+		physical, logical, err := cli.GetTS(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		report.Requests++
+		if err != nil {
+			report.Errors++
+			// back off to avoid flooding the service during the failover.
+			select {
+			case <-ctx.Done():
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, tsoSelfTestMaxBackoff)
+			continue
+		}
+		backoff = tsoSelfTestMinBackoff
+		now := time.Now()
+		if stall := now.Sub(lastTime); stall > maxStall {
+			maxStall = stall
+		}
+		lastTime = now
+		ts := tsoutil.ComposeTS(physical, logical)
+		if ts <= last {
+			report.Monotonic = false
+			report.Violations++
+		}
+		last = ts
+	}
+	report.MaxStall = maxStall.String()
+	jsonPrint(cmd, report)
+}
+
+// isAPIServiceMode returns whether the cluster runs in API service mode, in which
+// the TSO is served by the primaries of the keyspace groups rather than PD.
+func isAPIServiceMode(cmd *cobra.Command) (bool, error) {
+	resp, err := doRequest(cmd, msHandshakePrefix, http.MethodGet, http.Header{})
+	if err != nil {
+		return false, err
+	}
+	var handshake struct {
+		Mode string `json:"mode"`
+	}
+	if err = json.Unmarshal([]byte(resp), &handshake); err != nil {
+		return false, err
+	}
+	return handshake.Mode == "api", nil
+}
+
+// resignTSOPrimary resigns the primary of the default keyspace group in API
+// service mode, and the PD leader otherwise.
+func resignTSOPrimary(cmd *cobra.Command, apiServiceMode bool) error {
+	if !apiServiceMode {
+		_, err := doRequest(cmd, leaderResignPrefix, http.MethodPost, http.Header{})
+		return err
+	}
+	resp, err := doRequest(cmd, tsoPrimaryPrefix, http.MethodGet, http.Header{})
+	if err != nil {
+		return err
+	}
+	var primary string
+	if err = json.Unmarshal([]byte(resp), &primary); err != nil {
+		return err
+	}
+	if primary == "" {
+		return errors.New("no TSO primary is found")
+	}
+	_, err = doRequestSingleEndpoint(cmd, primary, tsoResignPrimariesPrefix, http.MethodPost, http.Header{})
+	return err
+}
@@ -15,26 +15,53 @@
 package command
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/tikv/pd/pkg/response"
+	"github.com/tikv/pd/pkg/unsaferecovery"
 )
 
-const unsafePrefix = "pd/api/v1/admin/unsafe"
+const (
+	unsafePrefix = "pd/api/v1/admin/unsafe"
+	// wizardRegionPageLimit is the page size of scanning the regions to preview the impact.
+	wizardRegionPageLimit = 1024
+	// wizardMaxSampleRegions is the max number of the affected regions printed in the preview.
+	wizardMaxSampleRegions = 10
+)
 
 // NewUnsafeCommand returns the unsafe subcommand of rootCmd.
 func NewUnsafeCommand() *cobra.Command {
 	unsafeCmd := &cobra.Command{
-		Use:   `unsafe [command]`,
-		Short: "Unsafe operations",
+		Use:     `unsafe [command]`,
+		Aliases: []string{"unsafe-recover"},
+		Short:   "Unsafe operations",
 	}
 	unsafeCmd.AddCommand(NewRemoveFailedStoresCommand())
+	unsafeCmd.AddCommand(NewUnsafeRecoverWizardCommand())
 	return unsafeCmd
 }
 
+// NewUnsafeRecoverWizardCommand returns the unsafe recover wizard command.
+func NewUnsafeRecoverWizardCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wizard",
+		Short: "Walk through identifying the failed stores, previewing the impact and removing them unsafely",
+		Run:   unsafeRecoverWizardCommandFunc,
+	}
+	cmd.Flags().Float64("timeout", 300, "timeout in seconds")
+	cmd.Flags().Duration("interval", 3*time.Second, "interval of polling the recovery status")
+	return cmd
+}
+
 // NewRemoveFailedStoresCommand returns the unsafe remove failed stores command.
 func NewRemoveFailedStoresCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -117,3 +144,262 @@ func removeFailedStoresShowCommandFunc(cmd *cobra.Command, _ []string) {
 	}
 	cmd.Println(resp)
 }
+
+func unsafeRecoverWizardCommandFunc(cmd *cobra.Command, _ []string) {
+	timeout, err := cmd.Flags().GetFloat64("timeout")
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	interval, err := cmd.Flags().GetDuration("interval")
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	reader := bufio.NewReader(cmd.InOrStdin())
+
+	cmd.Println("Step 1/4: identify the failed stores")
+	stores, err := getWizardStores(cmd)
+	if err != nil {
+		cmd.Printf("Failed to get the stores: %s\n", err)
+		return
+	}
+	var suggested []uint64
+	for _, store := range stores {
+		lastHeartbeat := "never"
+		if store.Status.LastHeartbeatTS != nil {
+			lastHeartbeat = store.Status.LastHeartbeatTS.Format(time.RFC3339)
+		}
+		cmd.Printf("  store %d\t%s\t%s\tlast heartbeat: %s\n",
+			store.Store.GetId(), store.Store.GetAddress(), store.Store.StateName, lastHeartbeat)
+		if store.Store.StateName == response.DownStateName {
+			suggested = append(suggested, store.Store.GetId())
+		}
+	}
+	cmd.Printf("Enter the IDs of the failed stores separated by commas [%s]: ", joinStoreIDs(suggested))
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		cmd.Println()
+		cmd.Println("Aborted, no input")
+		return
+	}
+	failedStores := suggested
+	if line = strings.TrimSpace(line); line != "" {
+		if failedStores, err = parseStoreIDs(line); err != nil {
+			cmd.Println(err)
+			return
+		}
+	}
+	if len(failedStores) == 0 {
+		cmd.Println("Aborted, no failed store is specified")
+		return
+	}
+	states := make(map[uint64]string, len(stores))
+	for _, store := range stores {
+		states[store.Store.GetId()] = store.Store.StateName
+	}
+	for _, id := range failedStores {
+		state, ok := states[id]
+		switch {
+		case !ok:
+			cmd.Printf("Note: store %d is unknown to PD, it's regarded as failed\n", id)
+		case state == "Up":
+			cmd.Printf("Aborted, store %d is %s, only the failed stores can be removed\n", id, state)
+			return
+		}
+	}
+
+	cmd.Println("Step 2/4: preview the impact")
+	lost, total, err := getRegionsLosingMajority(cmd, failedStores)
+	if err != nil {
+		cmd.Printf("Failed to scan the regions: %s\n", err)
+		return
+	}
+	var allLost int
+	for _, region := range lost {
+		if region.allLost {
+			allLost++
+		}
+	}
+	cmd.Printf("  %d of %d regions lose the majority, %d of them lose all replicas and will be recreated as empty regions\n",
+		len(lost), total, allLost)
+	for i, region := range lost {
+		if i == wizardMaxSampleRegions {
+			cmd.Printf("  ... and %d more\n", len(lost)-i)
+			break
+		}
+		cmd.Printf("  region %d: %d of %d voters are on the failed stores\n", region.id, region.failed, region.voters)
+	}
+	if len(lost) == 0 {
+		cmd.Println("No region loses the majority, unsafe recovery is unnecessary, please delete the stores instead")
+		return
+	}
+
+	cmd.Println("Step 3/4: confirm")
+	confirmation := joinStoreIDs(failedStores)
+	cmd.Printf("Unsafe recovery may lose the data which is irrecoverable. Type %q to continue: ", confirmation)
+	line, _ = reader.ReadString('\n')
+	if strings.TrimSpace(line) != confirmation {
+		cmd.Println("Aborted, the confirmation doesn't match")
+		return
+	}
+
+	cmd.Println("Step 4/4: recover")
+	postInput := map[string]any{
+		"stores":  failedStores,
+		"timeout": timeout,
+	}
+	reqData, err := json.Marshal(postInput)
+	if err != nil {
+		cmd.Println(err)
+		return
+	}
+	prefix := fmt.Sprintf("%s/remove-failed-stores", unsafePrefix)
+	if _, err = doRequest(cmd, prefix, http.MethodPost, http.Header{"Content-Type": {"application/json"}},
+		WithBody(strings.NewReader(string(reqData)))); err != nil {
+		cmd.Printf("Failed to remove the failed stores: %s\n", err)
+		return
+	}
+	waitUnsafeRecovery(cmd, interval)
+}
+
+// waitUnsafeRecovery prints the stages of the ongoing unsafe recovery until it's finished or failed.
+func waitUnsafeRecovery(cmd *cobra.Command, interval time.Duration) {
+	prefix := fmt.Sprintf("%s/remove-failed-stores/show", unsafePrefix)
+	var printed int
+	var lastStatus string
+	for {
+		resp, err := doRequest(cmd, prefix, http.MethodGet, http.Header{})
+		if err != nil {
+			cmd.Printf("Failed to get the recovery status: %s\n", err)
+			return
+		}
+		var outputs []unsaferecovery.StageOutput
+		if err = json.Unmarshal([]byte(resp), &outputs); err != nil {
+			cmd.Printf("Failed to parse the recovery status: %s\n", err)
+			return
+		}
+		for ; printed < len(outputs); printed++ {
+			output := outputs[printed]
+			// the last output is the report status of the ongoing stage, which is not
+			// recorded and printed only when it changes.
+			if !strings.HasPrefix(output.Info, "Unsafe recovery") {
+				if printed == len(outputs)-1 && output.Info != lastStatus {
+					cmd.Printf("  [%s] %s\n", output.Time, output.Info)
+					lastStatus = output.Info
+				}
+				break
+			}
+			cmd.Printf("  [%s] %s\n", output.Time, output.Info)
+			for _, detail := range output.Details {
+				cmd.Printf("    %s\n", detail)
+			}
+			if strings.HasPrefix(output.Info, "Unsafe recovery Finished") ||
+				strings.HasPrefix(output.Info, "Unsafe recovery Failed") {
+				return
+			}
+		}
+		time.Sleep(interval)
+	}
+}
+
+func getWizardStores(cmd *cobra.Command) ([]*response.StoreInfo, error) {
+	resp, err := doRequest(cmd, storesPrefix, http.MethodGet, http.Header{})
+	if err != nil {
+		return nil, err
+	}
+	stores := &response.StoresInfo{}
+	if err = json.Unmarshal([]byte(resp), stores); err != nil {
+		return nil, err
+	}
+	sort.Slice(stores.Stores, func(i, j int) bool {
+		return stores.Stores[i].Store.GetId() < stores.Stores[j].Store.GetId()
+	})
+	return stores.Stores, nil
+}
+
+type regionLosingMajority struct {
+	id      uint64
+	voters  int
+	failed  int
+	allLost bool
+}
+
+// getRegionsLosingMajority scans all regions page by page and returns the regions
+// losing the majority of the voters on the failed stores, along with the number of
+// the scanned regions.
+func getRegionsLosingMajority(cmd *cobra.Command, failedStores []uint64) ([]regionLosingMajority, int, error) {
+	failed := make(map[uint64]struct{}, len(failedStores))
+	for _, id := range failedStores {
+		failed[id] = struct{}{}
+	}
+	var (
+		lost      []regionLosingMajority
+		total     int
+		pageToken string
+	)
+	for {
+		prefix := fmt.Sprintf("%s?limit=%d", regionsPrefix, wizardRegionPageLimit)
+		if pageToken != "" {
+			prefix += "&page_token=" + url.QueryEscape(pageToken)
+		}
+		resp, err := doRequest(cmd, prefix, http.MethodGet, http.Header{})
+		if err != nil {
+			return nil, 0, err
+		}
+		regions := &response.RegionsInfo{}
+		if err = json.Unmarshal([]byte(resp), regions); err != nil {
+			return nil, 0, err
+		}
+		for _, region := range regions.Regions {
+			total++
+			var voters, failedVoters, failedPeers int
+			for _, peer := range region.Peers {
+				_, onFailed := failed[peer.GetStoreId()]
+				if onFailed {
+					failedPeers++
+				}
+				if peer.IsLearner {
+					continue
+				}
+				voters++
+				if onFailed {
+					failedVoters++
+				}
+			}
+			if voters > 0 && failedVoters*2 >= voters {
+				lost = append(lost, regionLosingMajority{
+					id:      region.ID,
+					voters:  voters,
+					failed:  failedVoters,
+					allLost: failedPeers == len(region.Peers),
+				})
+			}
+		}
+		if regions.NextPageToken == "" {
+			return lost, total, nil
+		}
+		pageToken = regions.NextPageToken
+	}
+}
+
+func parseStoreIDs(input string) ([]uint64, error) {
+	var stores []uint64
+	for _, str := range strings.Split(input, ",") {
+		store, err := strconv.ParseUint(strings.TrimSpace(str), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+	sort.Slice(stores, func(i, j int) bool { return stores[i] < stores[j] })
+	return stores, nil
+}
+
+func joinStoreIDs(stores []uint64) string {
+	strs := make([]string, 0, len(stores))
+	for _, store := range stores {
+		strs = append(strs, strconv.FormatUint(store, 10))
+	}
+	return strings.Join(strs, ",")
+}
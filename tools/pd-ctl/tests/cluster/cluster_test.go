@@ -88,3 +88,57 @@ func TestClusterAndPing(t *testing.T) {
 	_, err = tests.ExecuteCommand(cmd, args...)
 	re.Contains(err.Error(), "no such file or directory")
 }
+
+func TestClusterCheck(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := pdTests.NewTestCluster(ctx, 1)
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.RunInitialServers())
+	re.NotEmpty(cluster.WaitLeader())
+	re.NoError(cluster.GetLeaderServer().BootstrapCluster())
+	pdAddr := cluster.GetConfig().GetClientURL()
+	cmd := ctl.GetRootCmd()
+
+	args := []string{"-u", pdAddr, "cluster", "check"}
+	output, err := tests.ExecuteCommand(cmd, args...)
+	re.NoError(err)
+	re.Contains(string(output), "[PASS] members: 1/1 members are healthy")
+	re.Contains(string(output), "[PASS] etcd-alarms: no alarm")
+	re.Contains(string(output), "[PASS] gc-safepoint: GC has not run yet")
+	re.Contains(string(output), "Overall: PASS")
+
+	// the store without the heartbeat for a while is disconnected.
+	pdTests.MustPutStore(re, cluster, &metapb.Store{
+		Id:            2,
+		Address:       "mock-2",
+		State:         metapb.StoreState_Up,
+		NodeState:     metapb.NodeState_Serving,
+		LastHeartbeat: time.Now().Add(-time.Minute).UnixNano(),
+	})
+	args = []string{"-u", pdAddr, "cluster", "check", "--json"}
+	output, err = tests.ExecuteCommand(cmd, args...)
+	re.NoError(err)
+	report := &clusterpkg.CheckReport{}
+	re.NoError(json.Unmarshal(output, report))
+	statuses := make(map[string]string)
+	for _, item := range report.Items {
+		statuses[item.Name] = item.Status
+	}
+	re.Equal(map[string]string{
+		"members":         clusterpkg.CheckPass,
+		"etcd-alarms":     clusterpkg.CheckPass,
+		"stores":          clusterpkg.CheckWarn,
+		"operators":       clusterpkg.CheckPass,
+		"rule-violations": clusterpkg.CheckPass,
+		"gc-safepoint":    clusterpkg.CheckPass,
+	}, statuses)
+	re.Equal(clusterpkg.CheckWarn, report.Status)
+
+	args = []string{"-u", pdAddr, "cluster", "check", "--stale-safepoint", "abc"}
+	output, err = tests.ExecuteCommand(cmd, args...)
+	re.NoError(err)
+	re.Contains(string(output), "Failed to check the cluster")
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drill_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/stretchr/testify/require"
+	pdTests "github.com/tikv/pd/tests"
+	ctl "github.com/tikv/pd/tools/pd-ctl/pdctl"
+	"github.com/tikv/pd/tools/pd-ctl/tests"
+)
+
+func TestDrill(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := pdTests.NewTestCluster(ctx, 3)
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.RunInitialServers())
+	leader := cluster.WaitLeader()
+	re.NotEmpty(leader)
+	re.NoError(cluster.GetLeaderServer().BootstrapCluster())
+	pdAddr := cluster.GetConfig().GetClientURL()
+	cmd := ctl.GetRootCmd()
+
+	// The drill isn't started without the confirmation.
+	cmd.SetIn(strings.NewReader("yes\n"))
+	output, err := tests.ExecuteCommand(cmd, "-u", pdAddr, "drill", "--steps=resign-pd-leader")
+	re.NoError(err)
+	re.Contains(string(output), "Aborted, the confirmation doesn't match")
+	re.Equal(leader, cluster.GetLeader())
+
+	// The store is required to evict the leaders.
+	output, err = tests.ExecuteCommand(cmd, "-u", pdAddr, "drill", "--steps=resign-pd-leader,evict-leaders")
+	re.NoError(err)
+	re.Contains(string(output), "The store is required by the evict-leaders step")
+
+	output, err = tests.ExecuteCommand(cmd, "-u", pdAddr, "drill", "--steps=resign-pd-leader,resign-tso-primaries", "--yes")
+	re.NoError(err)
+	report := struct {
+		Passed bool `json:"passed"`
+		Steps  []struct {
+			Step         string `json:"step"`
+			RecoveryTime string `json:"recovery-time"`
+			Skipped      bool   `json:"skipped"`
+			Error        string `json:"error"`
+		} `json:"steps"`
+	}{}
+	re.NoError(json.Unmarshal(output, &report))
+	re.True(report.Passed, string(output))
+	re.Len(report.Steps, 2)
+	re.Equal("resign-pd-leader", report.Steps[0].Step)
+	re.NotEmpty(report.Steps[0].RecoveryTime)
+	re.Empty(report.Steps[0].Error)
+	// The TSO is served by the PD leader without the TSO servers.
+	re.Equal("resign-tso-primaries", report.Steps[1].Step)
+	re.True(report.Steps[1].Skipped)
+	re.NotEqual(leader, cluster.WaitLeader())
+
+	// The existing leader eviction of the store is left in place.
+	pdTests.MustPutStore(re, cluster, &metapb.Store{
+		Id:            1,
+		State:         metapb.StoreState_Up,
+		NodeState:     metapb.NodeState_Serving,
+		LastHeartbeat: time.Now().UnixNano(),
+	})
+	output, err = tests.ExecuteCommand(cmd, "-u", pdAddr, "scheduler", "add", "evict-leader-scheduler", "1")
+	re.NoError(err)
+	re.Contains(string(output), "Success!")
+	output, err = tests.ExecuteCommand(cmd, "-u", pdAddr, "drill", "--steps=evict-leaders", "--store=1", "--yes")
+	re.NoError(err)
+	re.NoError(json.Unmarshal(output, &report))
+	re.True(report.Passed, string(output))
+	re.Len(report.Steps, 1)
+	re.True(report.Steps[0].Skipped)
+	output, err = tests.ExecuteCommand(cmd, "-u", pdAddr, "scheduler", "config", "evict-leader-scheduler")
+	re.NoError(err)
+	re.Contains(string(output), `"1"`)
+}
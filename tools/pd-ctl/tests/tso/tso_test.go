@@ -15,12 +15,15 @@
 package tso_test
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	pdTests "github.com/tikv/pd/tests"
 	ctl "github.com/tikv/pd/tools/pd-ctl/pdctl"
 	"github.com/tikv/pd/tools/pd-ctl/tests"
 )
@@ -59,3 +62,64 @@ func TestTSO(t *testing.T) {
 	str = fmt.Sprintln("system: ", physicalTime) + fmt.Sprintln("logic:  ", logicalTime)
 	re.Equal(string(output), str)
 }
+
+type tsoSelfTestReport struct {
+	Requests       int    `json:"requests"`
+	FailoverTarget string `json:"failover-target"`
+	Failovers      int    `json:"failovers"`
+	FailoverErrors int    `json:"failover-errors"`
+	Monotonic      bool   `json:"monotonic"`
+}
+
+func TestTSOSelfTest(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := pdTests.NewTestCluster(ctx, 3)
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.RunInitialServers())
+	re.NotEmpty(cluster.WaitLeader())
+	re.NoError(cluster.GetLeaderServer().BootstrapCluster())
+	pdAddr := cluster.GetConfig().GetClientURL()
+	cmd := ctl.GetRootCmd()
+
+	args := []string{"-u", pdAddr, "tso", "selftest", "--duration=5s", "--failover-interval=2s"}
+	output, err := tests.ExecuteCommand(cmd, args...)
+	re.NoError(err)
+	report := tsoSelfTestReport{}
+	re.NoError(json.Unmarshal(output, &report))
+	re.True(report.Monotonic)
+	re.Positive(report.Requests)
+	re.Positive(report.Failovers)
+	re.Equal("pd-leader", report.FailoverTarget)
+}
+
+func TestTSOSelfTestInAPIServiceMode(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := pdTests.NewTestAPICluster(ctx, 1)
+	re.NoError(err)
+	defer cluster.Destroy()
+	re.NoError(cluster.RunInitialServers())
+	re.NotEmpty(cluster.WaitLeader())
+	re.NoError(cluster.GetLeaderServer().BootstrapCluster())
+	pdAddr := cluster.GetConfig().GetClientURL()
+	tsoCluster, err := pdTests.NewTestTSOCluster(ctx, 2, pdAddr)
+	re.NoError(err)
+	defer tsoCluster.Destroy()
+	tsoCluster.WaitForDefaultPrimaryServing(re)
+	cmd := ctl.GetRootCmd()
+
+	args := []string{"-u", pdAddr, "tso", "selftest", "--duration=5s", "--failover-interval=2s"}
+	output, err := tests.ExecuteCommand(cmd, args...)
+	re.NoError(err)
+	report := tsoSelfTestReport{}
+	re.NoError(json.Unmarshal(output, &report))
+	re.True(report.Monotonic)
+	re.Positive(report.Requests)
+	re.Positive(report.Failovers)
+	re.Equal("tso-primary", report.FailoverTarget)
+	re.Less(report.FailoverErrors, report.Failovers)
+}
@@ -16,8 +16,11 @@ package unsafe_operation_test
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/stretchr/testify/require"
 	pdTests "github.com/tikv/pd/tests"
 	ctl "github.com/tikv/pd/tools/pd-ctl/pdctl"
@@ -56,3 +59,49 @@ func TestRemoveFailedStores(t *testing.T) {
 	_, err = tests.ExecuteCommand(cmd, args...)
 	re.NoError(err)
 }
+
+func TestUnsafeRecoverWizard(t *testing.T) {
+	re := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cluster, err := pdTests.NewTestCluster(ctx, 1)
+	re.NoError(err)
+	defer cluster.Destroy()
+	err = cluster.RunInitialServers()
+	re.NoError(err)
+	re.NotEmpty(cluster.WaitLeader())
+	err = cluster.GetLeaderServer().BootstrapCluster()
+	re.NoError(err)
+	pdAddr := cluster.GetConfig().GetClientURL()
+	cmd := ctl.GetRootCmd()
+
+	// store 2 is down, and region 3 loses all its replicas on it.
+	pdTests.MustPutStore(re, cluster, &metapb.Store{Id: 1, State: metapb.StoreState_Up, LastHeartbeat: time.Now().UnixNano()})
+	pdTests.MustPutStore(re, cluster, &metapb.Store{Id: 2, State: metapb.StoreState_Up, LastHeartbeat: time.Now().Add(-time.Hour).UnixNano()})
+	pdTests.MustPutRegion(re, cluster, 2, 1, []byte("a"), []byte("b"))
+	pdTests.MustPutRegion(re, cluster, 3, 2, []byte("b"), []byte("c"))
+
+	// the down stores are suggested, and the recovery is aborted without the confirmation.
+	cmd.SetIn(strings.NewReader("\nyes\n"))
+	output, err := tests.ExecuteCommand(cmd, "-u", pdAddr, "unsafe-recover", "wizard")
+	re.NoError(err)
+	re.Contains(string(output), "Enter the IDs of the failed stores separated by commas [2]")
+	re.Contains(string(output), "1 of 2 regions lose the majority, 1 of them lose all replicas")
+	re.Contains(string(output), "region 3: 1 of 1 voters are on the failed stores")
+	re.Contains(string(output), "Aborted, the confirmation doesn't match")
+
+	// the alive stores can't be removed.
+	cmd.SetIn(strings.NewReader("1,2\n"))
+	output, err = tests.ExecuteCommand(cmd, "-u", pdAddr, "unsafe", "wizard")
+	re.NoError(err)
+	re.Contains(string(output), "Aborted, store 1 is Up")
+	re.NotContains(string(output), "Step 2/4")
+
+	// no region loses the majority without the failed stores.
+	cmd.SetIn(strings.NewReader("4\n"))
+	output, err = tests.ExecuteCommand(cmd, "-u", pdAddr, "unsafe", "wizard")
+	re.NoError(err)
+	re.Contains(string(output), "store 4 is unknown to PD")
+	re.Contains(string(output), "No region loses the majority")
+	re.NotContains(string(output), "Step 3/4")
+}